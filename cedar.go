@@ -40,6 +40,7 @@ const (
 	StatsCacheBuildlogger = "buildlogger"
 	StatsCacheTestResults = "test_results"
 	StatsCachePerf        = "perf"
+	StatsCacheRetention   = "retention"
 )
 
 var (
@@ -55,6 +56,7 @@ var (
 		StatsCacheBuildlogger,
 		StatsCacheTestResults,
 		StatsCachePerf,
+		StatsCacheRetention,
 	}
 )
 
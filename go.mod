@@ -14,6 +14,7 @@ require (
 	github.com/evergreen-ci/utility v0.0.0-20220404192535-d16eb64796e6
 	github.com/fraugster/parquet-go v0.11.0
 	github.com/jpillora/backoff v1.0.0
+	github.com/klauspost/compress v1.13.6
 	github.com/mongodb/amboy v0.0.0-20220209145213-c1c572da4472
 	github.com/mongodb/anser v0.0.0-20211116195831-fdc43007b59f
 	github.com/mongodb/ftdc v0.0.0-20211028165431-67f017692185
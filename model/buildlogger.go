@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"io/ioutil"
+	"path"
 	"sort"
 	"time"
 
@@ -47,17 +49,26 @@ type Log struct {
 	CreatedAt   time.Time       `bson:"created_at"`
 	CompletedAt time.Time       `bson:"completed_at"`
 	Artifact    LogArtifactInfo `bson:"artifact"`
+	// FailureSignatures are the likely failure lines found in the tail of
+	// a log closed with a non-zero exit code.
+	FailureSignatures []LogFailureSignature `bson:"failure_signatures,omitempty"`
+	// FailedRecompressionAttempts is the number of times recompressing the
+	// log's chunks has failed.
+	FailedRecompressionAttempts int `bson:"failed_recompression_attempts,omitempty"`
 
 	env       cedar.Environment
 	populated bool
 }
 
 var (
-	logIDKey          = bsonutil.MustHaveTag(Log{}, "ID")
-	logInfoKey        = bsonutil.MustHaveTag(Log{}, "Info")
-	logCreatedAtKey   = bsonutil.MustHaveTag(Log{}, "CreatedAt")
-	logCompletedAtKey = bsonutil.MustHaveTag(Log{}, "CompletedAt")
-	logArtifactKey    = bsonutil.MustHaveTag(Log{}, "Artifact")
+	logIDKey                = bsonutil.MustHaveTag(Log{}, "ID")
+	logInfoKey              = bsonutil.MustHaveTag(Log{}, "Info")
+	logCreatedAtKey         = bsonutil.MustHaveTag(Log{}, "CreatedAt")
+	logCompletedAtKey       = bsonutil.MustHaveTag(Log{}, "CompletedAt")
+	logFailureSignaturesKey = bsonutil.MustHaveTag(Log{}, "FailureSignatures")
+	logArtifactKey          = bsonutil.MustHaveTag(Log{}, "Artifact")
+
+	logFailedRecompressionAttemptsKey = bsonutil.MustHaveTag(Log{}, "FailedRecompressionAttempts")
 )
 
 // Setup sets the environment for the log. The environment is required for
@@ -133,6 +144,42 @@ func (l *Log) Remove(ctx context.Context) error {
 	return errors.Wrapf(err, "removing log record '%s'", l.ID)
 }
 
+// RemoveArtifacts removes the log's chunks from the offline blob storage
+// bucket configured for the log. The environment should not be nil.
+func (l *Log) RemoveArtifacts(ctx context.Context) error {
+	if l.env == nil {
+		return errors.New("cannot remove log artifacts with a nil environment")
+	}
+
+	if l.ID == "" {
+		l.ID = l.Info.ID()
+	}
+	// Removing an empty prefix would remove every log in the bucket.
+	if l.Artifact.Prefix == "" {
+		return errors.Errorf("cannot remove artifacts for log '%s' without an artifact prefix", l.ID)
+	}
+
+	conf := &CedarConfig{}
+	conf.Setup(l.env)
+	if err := conf.Find(); err != nil {
+		return errors.Wrap(err, "getting application configuration")
+	}
+
+	bucket, err := l.Artifact.Type.Create(
+		ctx,
+		l.env,
+		conf.Bucket.BuildLogsBucket,
+		l.Artifact.Prefix,
+		string(pail.S3PermissionsPrivate),
+		false,
+	)
+	if err != nil {
+		return errors.Wrap(err, "creating bucket")
+	}
+
+	return errors.Wrapf(bucket.RemovePrefix(ctx, ""), "removing artifacts for log '%s'", l.ID)
+}
+
 // Append uploads a chunk of log lines to the offline blob storage bucket
 // configured for the log. The environment should not be nil.
 func (l *Log) Append(ctx context.Context, lines []LogLine) error {
@@ -157,7 +204,11 @@ func (l *Log) Append(ctx context.Context, lines []LogLine) error {
 			line.Priority = level.Trace
 		}
 
-		_, err := lineBuffer.WriteString(prependPriorityAndTimestamp(line.Priority, line.Timestamp, line.Data))
+		data, err := l.Info.Format.encodeLineData(line.Data)
+		if err != nil {
+			return errors.Wrap(err, "encoding log line")
+		}
+		_, err = lineBuffer.WriteString(prependPriorityAndTimestamp(line.Priority, line.Timestamp, data))
 		if err != nil {
 			return errors.Wrap(err, "buffering lines")
 		}
@@ -174,14 +225,18 @@ func (l *Log) Append(ctx context.Context, lines []LogLine) error {
 		conf.Bucket.BuildLogsBucket,
 		l.Artifact.Prefix,
 		string(pail.S3PermissionsPrivate),
-		true,
+		l.Artifact.getCompression() == LogCompressionNone,
 	)
 	if err != nil {
 		return errors.Wrap(err, "creating bucket")
 	}
 
-	key := createBuildloggerChunkKey(lines[0].Timestamp, lines[len(lines)-1].Timestamp, len(lines))
-	if err := bucket.Put(ctx, key, lineBuffer); err != nil {
+	data, err := l.Artifact.getCompression().compress(lineBuffer.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "compressing log lines")
+	}
+	key := createBuildloggerChunkKey(lines[0].Timestamp, lines[len(lines)-1].Timestamp, len(lines)) + l.Artifact.getCompression().extension()
+	if err := bucket.Put(ctx, key, bytes.NewReader(data)); err != nil {
 		return errors.Wrap(err, "uploading log lines to bucket")
 	}
 
@@ -251,13 +306,211 @@ func (l *Log) Close(ctx context.Context, exitCode int) error {
 
 }
 
-// Download returns a LogIterator which iterates lines of the given log. The
+// Recompress compresses the chunks of a completed, uncompressed (version 1)
+// log with the given codec and upgrades its artifact to version 2. The
+// uncompressed chunks are only removed once the log record is updated, so the
+// log remains readable throughout. The environment should not be nil.
+func (l *Log) Recompress(ctx context.Context, compression LogCompression) error {
+	if l.env == nil {
+		return errors.New("cannot recompress log with a nil environment")
+	}
+	if err := compression.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+	if compression == LogCompressionNone {
+		return errors.New("must specify a compression codec")
+	}
+	if l.Artifact.Version != 1 {
+		return errors.Errorf("cannot recompress log with artifact version %d", l.Artifact.Version)
+	}
+	if l.CompletedAt.IsZero() {
+		return errors.New("cannot recompress an incomplete log")
+	}
+
+	if l.ID == "" {
+		l.ID = l.Info.ID()
+	}
+
+	conf := &CedarConfig{}
+	conf.Setup(l.env)
+	if err := conf.Find(); err != nil {
+		return errors.Wrap(err, "getting application configuration")
+	}
+	bucket, err := l.Artifact.Type.Create(
+		ctx,
+		l.env,
+		conf.Bucket.BuildLogsBucket,
+		l.Artifact.Prefix,
+		string(pail.S3PermissionsPrivate),
+		false,
+	)
+	if err != nil {
+		return errors.Wrap(err, "creating bucket")
+	}
+
+	chunks, err := l.getChunks(ctx, bucket)
+	if err != nil {
+		return errors.Wrap(err, "getting chunks")
+	}
+	keys := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		if err = recompressLogChunk(ctx, bucket, chunk, compression); err != nil {
+			return errors.Wrapf(err, "recompressing chunk '%s'", chunk.Key)
+		}
+		keys[i] = chunk.Key
+	}
+
+	updateResult, err := l.env.GetDB().Collection(buildloggerCollection).UpdateOne(
+		ctx,
+		bson.M{
+			logIDKey: l.ID,
+			bsonutil.GetDottedKeyName(logArtifactKey, logArtifactInfoVersionKey): 1,
+		},
+		bson.M{
+			"$set": bson.M{
+				bsonutil.GetDottedKeyName(logArtifactKey, logArtifactInfoVersionKey):     2,
+				bsonutil.GetDottedKeyName(logArtifactKey, logArtifactInfoCompressionKey): compression,
+			},
+		},
+	)
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection":   buildloggerCollection,
+		"id":           l.ID,
+		"compression":  compression,
+		"num_chunks":   len(chunks),
+		"updateResult": updateResult,
+		"op":           "recompress buildlogger log",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "updating log '%s'", l.ID)
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.Errorf("could not find uncompressed log record '%s'", l.ID)
+	}
+	l.Artifact.Version = 2
+	l.Artifact.Compression = compression
+
+	return errors.Wrap(bucket.RemoveMany(ctx, keys...), "removing uncompressed chunks")
+}
+
+// IncFailedRecompressionAttempts increments the failed_recompression_attempts
+// field by 1. The environment should not be nil.
+func (l *Log) IncFailedRecompressionAttempts(ctx context.Context) error {
+	if l.env == nil {
+		return errors.New("cannot increment failed recompression attempts with a nil environment")
+	}
+
+	if l.ID == "" {
+		l.ID = l.Info.ID()
+	}
+
+	updateResult, err := l.env.GetDB().Collection(buildloggerCollection).UpdateOne(
+		ctx,
+		bson.M{logIDKey: l.ID},
+		bson.M{"$inc": bson.M{logFailedRecompressionAttemptsKey: 1}},
+	)
+	if err == nil && updateResult.MatchedCount == 0 {
+		err = errors.Errorf("could not find log record '%s'", l.ID)
+	}
+	if err == nil {
+		l.FailedRecompressionAttempts++
+	}
+
+	return errors.Wrapf(err, "incrementing failed recompression attempts for log '%s'", l.ID)
+}
+
+func recompressLogChunk(ctx context.Context, bucket pail.Bucket, chunk LogChunkInfo, compression LogCompression) error {
+	r, err := getLogChunk(ctx, bucket, chunk)
+	if err != nil {
+		return errors.Wrap(err, "downloading chunk")
+	}
+	data, err := ioutil.ReadAll(r)
+	catcher := grip.NewBasicCatcher()
+	catcher.Wrap(err, "reading chunk")
+	catcher.Wrap(r.Close(), "closing chunk")
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	compressed, err := compression.compress(data)
+	if err != nil {
+		return errors.Wrap(err, "compressing chunk")
+	}
+
+	return errors.Wrap(bucket.Put(ctx, chunk.Key+compression.extension(), bytes.NewReader(compressed)), "uploading compressed chunk")
+}
+
+// FindLogsToRecompressOptions describes the search criteria for finding logs
+// to recompress.
+type FindLogsToRecompressOptions struct {
+	// FailureLimit, when greater than 0, excludes the logs whose
+	// recompression has failed at least this many times.
+	FailureLimit int
+	// ExcludeIDs are the IDs of logs that should not match, such as those
+	// that previously failed to be recompressed.
+	ExcludeIDs []string
+	// Limit, when greater than 0, limits the number of logs returned.
+	Limit int
+}
+
+// FindLogsToRecompress returns the completed, uncompressed (version 1) logs
+// matching the given options, oldest first. The environment should not be
+// nil.
+func FindLogsToRecompress(ctx context.Context, env cedar.Environment, opts FindLogsToRecompressOptions) ([]Log, error) {
+	if env == nil {
+		return nil, errors.New("cannot find logs with a nil environment")
+	}
+
+	query := bson.M{
+		bsonutil.GetDottedKeyName(logArtifactKey, logArtifactInfoVersionKey): 1,
+		logCompletedAtKey: bson.M{"$gt": time.Time{}},
+	}
+	if opts.FailureLimit > 0 {
+		// Logs that never failed do not have the field, so $lt would
+		// not match them.
+		query[logFailedRecompressionAttemptsKey] = bson.M{"$not": bson.M{"$gte": opts.FailureLimit}}
+	}
+	if len(opts.ExcludeIDs) > 0 {
+		query[logIDKey] = bson.M{"$nin": opts.ExcludeIDs}
+	}
+	findOpts := options.Find().SetSort(bson.M{logCompletedAtKey: 1})
+	if opts.Limit > 0 {
+		findOpts.SetLimit(int64(opts.Limit))
+	}
+	cur, err := env.GetDB().Collection(buildloggerCollection).Find(ctx, query, findOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding logs to recompress")
+	}
+
+	var logs []Log
+	if err = cur.All(ctx, &logs); err != nil {
+		return nil, errors.Wrap(err, "decoding logs to recompress")
+	}
+	for i := range logs {
+		logs[i].Setup(env)
+		logs[i].populated = true
+	}
+
+	return logs, nil
+}
+
+// Download returns a LogIterator which iterates lines of the given log. Lines
+// of logs with a structured format are returned as structured records. The
 // environment should not be nil.
 func (l *Log) Download(ctx context.Context, timeRange TimeRange) (LogIterator, error) {
 	if l.env == nil {
 		return nil, errors.New("cannot download log with a nil environment")
 	}
 
+	bucket, chunks, err := l.getBucketAndChunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStructuredLogIterator(NewBatchedLogIterator(bucket, chunks, 2, timeRange), l.Info.Format), nil
+}
+
+func (l *Log) getBucketAndChunks(ctx context.Context) (pail.Bucket, []LogChunkInfo, error) {
 	if l.ID == "" {
 		l.ID = l.Info.ID()
 	}
@@ -265,7 +518,7 @@ func (l *Log) Download(ctx context.Context, timeRange TimeRange) (LogIterator, e
 	conf := &CedarConfig{}
 	conf.Setup(l.env)
 	if err := conf.Find(); err != nil {
-		return nil, errors.Wrap(err, "getting application configuration")
+		return nil, nil, errors.Wrap(err, "getting application configuration")
 	}
 
 	bucket, err := l.Artifact.Type.Create(
@@ -277,15 +530,64 @@ func (l *Log) Download(ctx context.Context, timeRange TimeRange) (LogIterator, e
 		false,
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "creating bucket")
+		return nil, nil, errors.Wrap(err, "creating bucket")
 	}
 
 	chunks, err := l.getChunks(ctx, bucket)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting chunks")
+		return nil, nil, errors.Wrap(err, "getting chunks")
+	}
+
+	return bucket, chunks, nil
+}
+
+// Follow returns a FollowingLogIterator which iterates lines of the given log
+// as they are appended, polling the log's bucket for new chunks until the log
+// is completed. The format of the lines is the log's format. The environment
+// should not be nil.
+func (l *Log) Follow(ctx context.Context, opts LogFollowOptions) (FollowingLogIterator, error) {
+	if l.env == nil {
+		return nil, errors.New("cannot follow log with a nil environment")
+	}
+
+	if l.ID == "" {
+		l.ID = l.Info.ID()
+	}
+
+	conf := &CedarConfig{}
+	conf.Setup(l.env)
+	if err := conf.Find(); err != nil {
+		return nil, errors.Wrap(err, "getting application configuration")
+	}
+
+	bucket, err := l.Artifact.Type.Create(
+		ctx,
+		l.env,
+		conf.Bucket.BuildLogsBucket,
+		l.Artifact.Prefix,
+		string(pail.S3PermissionsPrivate),
+		false,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating bucket")
 	}
 
-	return NewBatchedLogIterator(bucket, chunks, 2, timeRange), nil
+	poll := func(ctx context.Context) ([]LogChunkInfo, bool, error) {
+		if err := l.Find(ctx); err != nil {
+			return nil, false, errors.Wrap(err, "refreshing log")
+		}
+		completed := !l.CompletedAt.IsZero()
+
+		chunks, err := l.getChunks(ctx, bucket)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "getting chunks")
+		}
+
+		return chunks, completed, nil
+	}
+
+	opts.Format = l.Info.Format
+	return NewFollowingLogIterator(bucket, poll, opts), nil
 }
 
 func (l *Log) getChunks(ctx context.Context, bucket pail.Bucket) ([]LogChunkInfo, error) {
@@ -295,19 +597,33 @@ func (l *Log) getChunks(ctx context.Context, bucket pail.Bucket) ([]LogChunkInfo
 		// Version 0 stores log chunk information directly in the
 		// DB.
 		chunks = l.Artifact.Chunks
-	case 1:
+	case 1, 2:
 		// Version 1 uses the key of the chunk in the pail-backed
-		// offline storage to encode the chunk information.
+		// offline storage to encode the chunk information. Version 2
+		// additionally compresses each chunk with the log's codec and
+		// suffixes the key with the codec's extension. Keys without
+		// the expected extension are skipped since they belong to a
+		// log that is in the process of being recompressed.
+		compression := l.Artifact.getCompression()
+		if err := compression.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid artifact compression")
+		}
+
 		it, err := bucket.List(ctx, "")
 		if err != nil {
 			return nil, errors.Wrap(err, "listing chunks")
 		}
 
 		for it.Next(ctx) {
+			if path.Ext(it.Item().Name()) != compression.extension() {
+				continue
+			}
+
 			chunk, err := parseBuildloggerChunkKey(it.Item().Name())
 			if err != nil {
 				return nil, errors.Wrapf(err, "parsing chunk key '%s'", it.Item().Name())
 			}
+			chunk.Compression = compression
 			chunks = append(chunks, chunk)
 		}
 		if err = it.Err(); err != nil {
@@ -408,6 +724,9 @@ type LogLine struct {
 	Priority  level.Priority
 	Timestamp time.Time
 	Data      string
+	// Fields are the fields of the line's record when the log has a
+	// structured format, such as JSON or BSON, and are otherwise nil.
+	Fields map[string]interface{}
 }
 
 // Logs describes a set of buildlogger logs, typically related by some
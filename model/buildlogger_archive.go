@@ -0,0 +1,174 @@
+package model
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/recovery"
+	"github.com/pkg/errors"
+)
+
+// LogArchiveManifestName is the name of the JSON manifest file written at
+// the beginning of every log archive.
+const LogArchiveManifestName = "manifest.json"
+
+// LogArchiveOptions describes the options for creating a log archive.
+type LogArchiveOptions struct {
+	// Manifest is the JSON encoded metadata of the archived logs, written
+	// to the archive as LogArchiveManifestName.
+	Manifest []byte
+	// TimeRange is the time range of the lines archived from each log. If
+	// zero, all lines up to the creation of the archive are archived.
+	TimeRange TimeRange
+	// PrintTime and PrintPriority are passed through to the
+	// LogIteratorReader of each log.
+	PrintTime     bool
+	PrintPriority bool
+	// Download, when set, returns a new iterator for the lines of the
+	// given log in the given time range. Defaults to Log.Download.
+	Download func(context.Context, *Log, TimeRange) (LogIterator, error)
+}
+
+var logArchiveFileNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// LogArchiveFileNames returns the name of the archive file of each of the
+// given logs, in order. File names are built from the test name, process name
+// and tags of each log and made unique within the archive.
+func LogArchiveFileNames(logs []Log) []string {
+	names := make([]string, len(logs))
+	seen := map[string]int{}
+	for i, log := range logs {
+		parts := []string{}
+		if log.Info.TestName != "" {
+			parts = append(parts, log.Info.TestName)
+		}
+		if log.Info.ProcessName != "" {
+			parts = append(parts, log.Info.ProcessName)
+		}
+		tags := append([]string{}, log.Info.Tags...)
+		sort.Strings(tags)
+		parts = append(parts, tags...)
+		if log.Info.Trial > 0 {
+			parts = append(parts, fmt.Sprintf("trial-%d", log.Info.Trial))
+		}
+
+		name := logArchiveFileNameRegexp.ReplaceAllString(strings.Join(parts, "_"), "_")
+		if strings.Trim(name, "._") == "" {
+			name = "log"
+		}
+		if count := seen[name]; count > 0 {
+			seen[name]++
+			name = fmt.Sprintf("%s_%d", name, count)
+		} else {
+			seen[name] = 1
+		}
+		names[i] = name + ".log"
+	}
+
+	return names
+}
+
+// NewLogArchiveReader returns an io.ReadCloser that streams a zip archive of
+// the given logs, with one compressed file per log named by
+// LogArchiveFileNames, preceded by the manifest. Each log is downloaded once
+// and its lines are written directly to the archive, which is never held in
+// memory or on disk. Reading stops with an error when the context is
+// canceled; callers should close the reader when done.
+func NewLogArchiveReader(ctx context.Context, logs []Log, opts LogArchiveOptions) io.ReadCloser {
+	if opts.TimeRange.IsZero() {
+		opts.TimeRange = TimeRange{EndAt: time.Now()}
+	}
+	if opts.Download == nil {
+		opts.Download = func(ctx context.Context, log *Log, timeRange TimeRange) (LogIterator, error) {
+			return log.Download(ctx, timeRange)
+		}
+	}
+
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if err := recovery.HandlePanicWithError(recover(), nil, "log archive writer"); err != nil {
+				_ = w.CloseWithError(err)
+			}
+		}()
+
+		_ = w.CloseWithError(writeLogArchive(ctx, w, logs, opts))
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = r.CloseWithError(ctx.Err())
+		case <-done:
+		}
+	}()
+
+	return r
+}
+
+func writeLogArchive(ctx context.Context, w io.Writer, logs []Log, opts LogArchiveOptions) error {
+	zw := zip.NewWriter(w)
+
+	mw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     LogArchiveManifestName,
+		Method:   zip.Deflate,
+		Modified: opts.TimeRange.EndAt,
+	})
+	if err != nil {
+		return errors.Wrap(err, "writing manifest header")
+	}
+	if _, err = mw.Write(opts.Manifest); err != nil {
+		return errors.Wrap(err, "writing manifest")
+	}
+
+	for i, name := range LogArchiveFileNames(logs) {
+		if err = writeLogArchiveFile(ctx, zw, &logs[i], name, opts); err != nil {
+			return errors.Wrapf(err, "archiving log '%s'", logs[i].ID)
+		}
+	}
+
+	return errors.Wrap(zw.Close(), "closing zip writer")
+}
+
+func writeLogArchiveFile(ctx context.Context, zw *zip.Writer, log *Log, name string, opts LogArchiveOptions) error {
+	it, err := opts.Download(ctx, log, opts.TimeRange)
+	if err != nil {
+		return errors.Wrap(err, "downloading log")
+	}
+
+	modTime := log.CompletedAt
+	if modTime.IsZero() {
+		modTime = log.CreatedAt
+	}
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	})
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrap(err, "writing header")
+		catcher.Wrap(it.Close(), "closing log iterator")
+		return catcher.Resolve()
+	}
+
+	_, err = io.Copy(fw, NewLogIteratorReader(ctx, it, LogIteratorReaderOptions{
+		PrintTime:     opts.PrintTime,
+		PrintPriority: opts.PrintPriority,
+	}))
+	catcher := grip.NewBasicCatcher()
+	catcher.Wrap(err, "writing lines")
+	if !it.Exhausted() {
+		catcher.Wrap(it.Close(), "closing log iterator")
+	}
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/pail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogArchiveFileNames(t *testing.T) {
+	logs := []Log{
+		{Info: LogInfo{ProcessName: "mongod", Tags: []string{"b", "a"}}},
+		{Info: LogInfo{TestName: "jstests/core/find.js", ProcessName: "mongo"}},
+		{Info: LogInfo{ProcessName: "mongod", Tags: []string{"a", "b"}}},
+		{Info: LogInfo{ProcessName: "mongod", Trial: 1}},
+		{},
+		{Info: LogInfo{ProcessName: "../"}},
+	}
+
+	assert.Equal(t, []string{
+		"mongod_a_b.log",
+		"jstests_core_find.js_mongo.log",
+		"mongod_a_b_1.log",
+		"mongod_trial-1.log",
+		"log.log",
+		"log_1.log",
+	}, LogArchiveFileNames(logs))
+}
+
+func TestNewLogArchiveReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpDir, err := ioutil.TempDir(".", "log-archive-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	logs := []Log{
+		{ID: "log0", Info: LogInfo{ProcessName: "proc0"}, Artifact: LogArtifactInfo{Prefix: "log0"}},
+		{ID: "log1", Info: LogInfo{ProcessName: "proc1"}, Artifact: LogArtifactInfo{Prefix: "log1"}},
+	}
+	expected := map[string]string{}
+	for i, name := range LogArchiveFileNames(logs) {
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: logs[i].Artifact.Prefix})
+		require.NoError(t, err)
+		chunks, lines, err := GenerateTestLog(ctx, bucket, 50, 10)
+		require.NoError(t, err)
+		logs[i].Artifact.Chunks = chunks
+
+		var data string
+		for _, line := range lines {
+			data += line.Data
+		}
+		expected[name] = data
+	}
+	download := func(_ context.Context, log *Log, timeRange TimeRange) (LogIterator, error) {
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: log.Artifact.Prefix})
+		if err != nil {
+			return nil, err
+		}
+		return NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, timeRange), nil
+	}
+	readArchive := func(t *testing.T, r io.Reader) map[string]string {
+		archive, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		require.NoError(t, err)
+
+		files := map[string]string{}
+		for _, f := range zr.File {
+			assert.Equal(t, zip.Deflate, f.Method)
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, err := ioutil.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+			assert.EqualValues(t, f.UncompressedSize64, len(data))
+			files[f.Name] = string(data)
+		}
+
+		return files
+	}
+
+	t.Run("ManifestAndLogs", func(t *testing.T) {
+		manifest := `[{"id":"log0"},{"id":"log1"}]`
+		r := NewLogArchiveReader(ctx, logs, LogArchiveOptions{
+			Manifest:  []byte(manifest),
+			TimeRange: TimeRange{EndAt: time.Now().Add(24 * time.Hour)},
+			Download:  download,
+		})
+		defer func() {
+			assert.NoError(t, r.Close())
+		}()
+
+		files := readArchive(t, r)
+		require.Len(t, files, len(logs)+1)
+		assert.Equal(t, manifest, files[LogArchiveManifestName])
+		for name, data := range expected {
+			assert.Equal(t, data, files[name])
+		}
+	})
+	t.Run("DownloadsEachLogOnce", func(t *testing.T) {
+		downloads := map[string]int{}
+		r := NewLogArchiveReader(ctx, logs, LogArchiveOptions{
+			TimeRange: TimeRange{EndAt: time.Now().Add(24 * time.Hour)},
+			Download: func(ctx context.Context, log *Log, timeRange TimeRange) (LogIterator, error) {
+				downloads[log.ID]++
+				return download(ctx, log, timeRange)
+			},
+		})
+		defer func() {
+			assert.NoError(t, r.Close())
+		}()
+
+		files := readArchive(t, r)
+		require.Len(t, files, len(logs)+1)
+		for name, data := range expected {
+			assert.Equal(t, data, files[name])
+		}
+		require.Len(t, downloads, len(logs))
+		for _, count := range downloads {
+			assert.Equal(t, 1, count)
+		}
+	})
+	t.Run("PrintPriority", func(t *testing.T) {
+		r := NewLogArchiveReader(ctx, logs, LogArchiveOptions{
+			TimeRange:     TimeRange{EndAt: time.Now().Add(24 * time.Hour)},
+			PrintPriority: true,
+			Download:      download,
+		})
+		defer func() {
+			assert.NoError(t, r.Close())
+		}()
+
+		files := readArchive(t, r)
+		require.Len(t, files, len(logs)+1)
+		for name, data := range expected {
+			assert.True(t, strings.HasPrefix(files[name], "[P:"))
+			assert.True(t, len(files[name]) > len(data))
+		}
+	})
+	t.Run("DownloadError", func(t *testing.T) {
+		r := NewLogArchiveReader(ctx, logs, LogArchiveOptions{
+			Download: func(context.Context, *Log, TimeRange) (LogIterator, error) {
+				return nil, io.ErrUnexpectedEOF
+			},
+		})
+		defer func() {
+			assert.NoError(t, r.Close())
+		}()
+
+		_, err := ioutil.ReadAll(r)
+		assert.Error(t, err)
+	})
+	t.Run("ContextCanceled", func(t *testing.T) {
+		cctx, ccancel := context.WithCancel(ctx)
+		r := NewLogArchiveReader(cctx, logs, LogArchiveOptions{Download: download})
+		ccancel()
+		defer func() {
+			assert.NoError(t, r.Close())
+		}()
+
+		_, err := ioutil.ReadAll(r)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,247 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultLogSearchLimit = 100
+	maxLogSearchLimit     = 1000
+	maxLogSearchContext   = 20
+	maxLogSearchLogs      = 1000
+)
+
+// LogSearchOptions describes the search criteria for scanning the lines of
+// multiple buildlogger logs.
+type LogSearchOptions struct {
+	// Info filters the logs to search. Only the project, version,
+	// variant, task name, and task ID fields are respected. Project is
+	// required.
+	Info LogInfo
+	// Pattern is the literal string, or regular expression if Regex is
+	// true, to match log lines against.
+	Pattern         string
+	Regex           bool
+	CaseInsensitive bool
+	// Context is the number of lines before and after each matching line
+	// to return along with the match.
+	Context int
+	// TimeRange bounds both the logs searched and the lines scanned
+	// within those logs.
+	TimeRange TimeRange
+	// Limit is the maximum number of matching lines to return. Defaults
+	// to 100 if not set.
+	Limit int
+	// LogOffset is the number of logs matching the filters, in search
+	// order, to skip. It continues a search that stopped after scanning
+	// the maximum number of logs searched at once.
+	LogOffset int
+}
+
+// Validate ensures that the LogSearchOptions are valid and sets any
+// defaults.
+func (o *LogSearchOptions) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen(o.Info.Project == "", "must specify a project")
+	catcher.NewWhen(o.Pattern == "", "must specify a search pattern")
+	catcher.NewWhen(o.Context < 0 || o.Context > maxLogSearchContext, "invalid number of context lines")
+	catcher.NewWhen(o.Limit < 0 || o.Limit > maxLogSearchLimit, "invalid limit")
+	catcher.NewWhen(o.LogOffset < 0, "log offset cannot be negative")
+	catcher.NewWhen(!o.TimeRange.IsValid(), "invalid time range")
+	if o.Pattern != "" {
+		_, err := o.compile()
+		catcher.Wrap(err, "compiling search pattern")
+	}
+
+	if o.Limit == 0 {
+		o.Limit = defaultLogSearchLimit
+	}
+
+	return catcher.Resolve()
+}
+
+func (o *LogSearchOptions) compile() (*regexp.Regexp, error) {
+	pattern := o.Pattern
+	if !o.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if o.CaseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// LogSearchResult describes a single buildlogger log line that matched a
+// search along with its surrounding context lines.
+type LogSearchResult struct {
+	LogID string
+	Info  LogInfo
+	// LineNum is the 1-based position of the matching line relative to
+	// the first line scanned in the log.
+	LineNum int
+	Line    LogLine
+	Before  []LogLine
+	After   []LogLine
+}
+
+// SearchLogs scans the lines of the buildlogger logs matching the given
+// options and returns the lines that match the search pattern, ordered by log
+// and then line. At most 1000 logs are scanned at once; if more logs match
+// and the limit was not reached, the returned log offset is non-zero and
+// continues the search from the first log not scanned. The environment should
+// not be nil.
+func SearchLogs(ctx context.Context, env cedar.Environment, opts LogSearchOptions) ([]LogSearchResult, int, error) {
+	if env == nil {
+		return nil, 0, errors.New("cannot search logs with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, 0, errors.Wrap(err, "invalid log search options")
+	}
+
+	// Find one extra log to know whether there are more logs to search.
+	findOpts := options.Find().SetSkip(int64(opts.LogOffset)).SetLimit(maxLogSearchLogs + 1)
+	findOpts.SetSort(bson.D{
+		{Key: bsonutil.GetDottedKeyName(logInfoKey, logInfoTaskIDKey), Value: 1},
+		{Key: bsonutil.GetDottedKeyName(logInfoKey, logInfoExecutionKey), Value: 1},
+		{Key: logCreatedAtKey, Value: 1},
+	})
+	cur, err := env.GetDB().Collection(buildloggerCollection).Find(ctx, createSearchQuery(opts), findOpts)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "finding logs to search")
+	}
+	var logs []Log
+	if err = cur.All(ctx, &logs); err != nil {
+		return nil, 0, errors.Wrap(err, "decoding logs to search")
+	}
+	hasMoreLogs := len(logs) > maxLogSearchLogs
+	if hasMoreLogs {
+		logs = logs[:maxLogSearchLogs]
+	}
+
+	results := []LogSearchResult{}
+	for i := 0; i < len(logs) && len(results) < opts.Limit; i++ {
+		logs[i].Setup(env)
+		it, err := logs[i].Download(ctx, opts.TimeRange)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "downloading log '%s'", logs[i].ID)
+		}
+
+		logOpts := opts
+		logOpts.Limit = opts.Limit - len(results)
+		matches, err := SearchLogIterator(ctx, it, logOpts)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "searching log '%s'", logs[i].ID)
+		}
+		for j := range matches {
+			matches[j].LogID = logs[i].ID
+			matches[j].Info = logs[i].Info
+		}
+		results = append(results, matches...)
+	}
+
+	if hasMoreLogs && len(results) < opts.Limit {
+		return results, opts.LogOffset + maxLogSearchLogs, nil
+	}
+
+	return results, 0, nil
+}
+
+// SearchLogIterator scans the lines of the given iterator and returns up to
+// opts.Limit lines matching the search pattern, each with up to opts.Context
+// lines before and after it. The LogID and Info fields of the returned results
+// are not populated. The iterator is closed before returning.
+func SearchLogIterator(ctx context.Context, it LogIterator, opts LogSearchOptions) ([]LogSearchResult, error) {
+	re, err := opts.compile()
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrap(err, "compiling search pattern")
+		catcher.Add(it.Close())
+		return nil, catcher.Resolve()
+	}
+
+	var (
+		results []LogSearchResult
+		before  []LogLine
+		pending []int
+		lineNum int
+	)
+	for (opts.Limit <= 0 || len(results) < opts.Limit || len(pending) > 0) && it.Next(ctx) {
+		line := it.Item()
+		lineNum++
+
+		stillPending := pending[:0]
+		for _, idx := range pending {
+			results[idx].After = append(results[idx].After, line)
+			if len(results[idx].After) < opts.Context {
+				stillPending = append(stillPending, idx)
+			}
+		}
+		pending = stillPending
+
+		if (opts.Limit <= 0 || len(results) < opts.Limit) && re.MatchString(strings.TrimSuffix(line.Data, "\n")) {
+			results = append(results, LogSearchResult{
+				LineNum: lineNum,
+				Line:    line,
+				Before:  append([]LogLine{}, before...),
+			})
+			if opts.Context > 0 {
+				pending = append(pending, len(results)-1)
+			}
+		}
+
+		if opts.Context > 0 {
+			before = append(before, line)
+			if len(before) > opts.Context {
+				before = before[1:]
+			}
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(it.Err())
+	catcher.Add(it.Close())
+
+	return results, catcher.Resolve()
+}
+
+func createSearchQuery(opts LogSearchOptions) bson.M {
+	search := bson.M{
+		bsonutil.GetDottedKeyName(logInfoKey, logInfoProjectKey): opts.Info.Project,
+		logCreatedAtKey: bson.M{"$lte": opts.TimeRange.EndAt},
+	}
+	if !opts.TimeRange.StartAt.IsZero() {
+		// In-progress logs have a zero completed at time and may still
+		// have lines in the time range.
+		search["$or"] = []bson.M{
+			{logCompletedAtKey: bson.M{"$gte": opts.TimeRange.StartAt}},
+			{logCompletedAtKey: time.Time{}},
+			{logCompletedAtKey: nil},
+		}
+	}
+	if opts.Info.Version != "" {
+		search[bsonutil.GetDottedKeyName(logInfoKey, logInfoVersionKey)] = opts.Info.Version
+	}
+	if opts.Info.Variant != "" {
+		search[bsonutil.GetDottedKeyName(logInfoKey, logInfoVariantKey)] = opts.Info.Variant
+	}
+	if opts.Info.TaskName != "" {
+		search[bsonutil.GetDottedKeyName(logInfoKey, logInfoTaskNameKey)] = opts.Info.TaskName
+	}
+	if opts.Info.TaskID != "" {
+		search[bsonutil.GetDottedKeyName(logInfoKey, logInfoTaskIDKey)] = opts.Info.TaskID
+	}
+
+	return search
+}
@@ -0,0 +1,357 @@
+package model
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/pail"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLogSearchOptionsValidate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		opts   LogSearchOptions
+		hasErr bool
+	}{
+		{
+			name: "MissingProject",
+			opts: LogSearchOptions{
+				Pattern:   "foo",
+				TimeRange: TimeRange{EndAt: time.Now()},
+			},
+			hasErr: true,
+		},
+		{
+			name: "MissingPattern",
+			opts: LogSearchOptions{
+				Info:      LogInfo{Project: "project"},
+				TimeRange: TimeRange{EndAt: time.Now()},
+			},
+			hasErr: true,
+		},
+		{
+			name: "InvalidRegex",
+			opts: LogSearchOptions{
+				Info:      LogInfo{Project: "project"},
+				Pattern:   "f(o",
+				Regex:     true,
+				TimeRange: TimeRange{EndAt: time.Now()},
+			},
+			hasErr: true,
+		},
+		{
+			name: "InvalidContext",
+			opts: LogSearchOptions{
+				Info:      LogInfo{Project: "project"},
+				Pattern:   "foo",
+				Context:   maxLogSearchContext + 1,
+				TimeRange: TimeRange{EndAt: time.Now()},
+			},
+			hasErr: true,
+		},
+		{
+			name: "InvalidLimit",
+			opts: LogSearchOptions{
+				Info:      LogInfo{Project: "project"},
+				Pattern:   "foo",
+				Limit:     maxLogSearchLimit + 1,
+				TimeRange: TimeRange{EndAt: time.Now()},
+			},
+			hasErr: true,
+		},
+		{
+			name: "NegativeLogOffset",
+			opts: LogSearchOptions{
+				Info:      LogInfo{Project: "project"},
+				Pattern:   "foo",
+				LogOffset: -1,
+				TimeRange: TimeRange{EndAt: time.Now()},
+			},
+			hasErr: true,
+		},
+		{
+			name: "InvalidTimeRange",
+			opts: LogSearchOptions{
+				Info:    LogInfo{Project: "project"},
+				Pattern: "foo",
+				TimeRange: TimeRange{
+					StartAt: time.Now(),
+					EndAt:   time.Now().Add(-time.Hour),
+				},
+			},
+			hasErr: true,
+		},
+		{
+			name: "LiteralPatternWithRegexCharacters",
+			opts: LogSearchOptions{
+				Info:      LogInfo{Project: "project"},
+				Pattern:   "f(o",
+				TimeRange: TimeRange{EndAt: time.Now()},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.opts.Validate()
+			if test.hasErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultLogSearchLimit, test.opts.Limit)
+			}
+		})
+	}
+}
+
+func TestSearchLogIterator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := time.Now().Round(time.Millisecond).UTC()
+	lines := []LogLine{
+		{Priority: level.Info, Timestamp: ts, Data: "starting test\n"},
+		{Priority: level.Info, Timestamp: ts.Add(time.Millisecond), Data: "running a.b\n"},
+		{Priority: level.Error, Timestamp: ts.Add(2 * time.Millisecond), Data: "assertion FAILED\n"},
+		{Priority: level.Info, Timestamp: ts.Add(3 * time.Millisecond), Data: "running a+b\n"},
+		{Priority: level.Error, Timestamp: ts.Add(4 * time.Millisecond), Data: "assertion failed\n"},
+		{Priority: level.Info, Timestamp: ts.Add(5 * time.Millisecond), Data: "done\n"},
+	}
+	newIterator := func() LogIterator {
+		return &sliceLogIterator{lines: lines}
+	}
+
+	t.Run("Literal", func(t *testing.T) {
+		results, err := SearchLogIterator(ctx, newIterator(), LogSearchOptions{Pattern: "a+b"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, 4, results[0].LineNum)
+		assert.Equal(t, lines[3], results[0].Line)
+		assert.Empty(t, results[0].Before)
+		assert.Empty(t, results[0].After)
+	})
+	t.Run("Regex", func(t *testing.T) {
+		results, err := SearchLogIterator(ctx, newIterator(), LogSearchOptions{Pattern: "^running a.b$", Regex: true})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, lines[1], results[0].Line)
+		assert.Equal(t, lines[3], results[1].Line)
+	})
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		results, err := SearchLogIterator(ctx, newIterator(), LogSearchOptions{Pattern: "failed", CaseInsensitive: true})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, lines[2], results[0].Line)
+		assert.Equal(t, lines[4], results[1].Line)
+	})
+	t.Run("Context", func(t *testing.T) {
+		results, err := SearchLogIterator(ctx, newIterator(), LogSearchOptions{Pattern: "assertion", Context: 2})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, lines[0:2], results[0].Before)
+		assert.Equal(t, lines[3:5], results[0].After)
+		assert.Equal(t, lines[2:4], results[1].Before)
+		assert.Equal(t, lines[5:], results[1].After)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		results, err := SearchLogIterator(ctx, newIterator(), LogSearchOptions{Pattern: "running", Context: 1, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, lines[1], results[0].Line)
+		assert.Equal(t, lines[0:1], results[0].Before)
+		assert.Equal(t, lines[2:3], results[0].After)
+	})
+	t.Run("NoMatches", func(t *testing.T) {
+		results, err := SearchLogIterator(ctx, newIterator(), LogSearchOptions{Pattern: "DNE"})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestSearchLogs(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "search-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, db.Collection(buildloggerCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(configurationCollection).Drop(ctx))
+	}()
+	conf := &CedarConfig{
+		populated: true,
+		Bucket:    BucketConfig{BuildLogsBucket: tmpDir},
+	}
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	log1, log2 := getTestLogs(time.Now())
+	log2.Info.Project = log1.Info.Project
+	log2.Info.Version = "other_version"
+	var log1Lines, log2Lines []LogLine
+	for _, l := range []struct {
+		log   *Log
+		lines *[]LogLine
+	}{
+		{log: log1, lines: &log1Lines},
+		{log: log2, lines: &log2Lines},
+	} {
+		l.log.ID = l.log.Info.ID()
+		l.log.Artifact = LogArtifactInfo{Type: PailLocal, Prefix: l.log.ID, Version: 1}
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: l.log.ID})
+		require.NoError(t, err)
+		l.log.Artifact.Chunks, *l.lines, err = GenerateTestLog(ctx, bucket, 50, 10)
+		require.NoError(t, err)
+		l.log.CreatedAt = l.log.Artifact.Chunks[0].Start
+		l.log.CompletedAt = l.log.Artifact.Chunks[len(l.log.Artifact.Chunks)-1].End
+		_, err = db.Collection(buildloggerCollection).InsertOne(ctx, l.log)
+		require.NoError(t, err)
+	}
+	tr := TimeRange{EndAt: time.Now().Add(time.Hour)}
+
+	t.Run("NoEnv", func(t *testing.T) {
+		results, _, err := SearchLogs(ctx, nil, LogSearchOptions{Info: LogInfo{Project: log1.Info.Project}, Pattern: "a", TimeRange: tr})
+		assert.Error(t, err)
+		assert.Nil(t, results)
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		results, _, err := SearchLogs(ctx, env, LogSearchOptions{Pattern: "a", TimeRange: tr})
+		assert.Error(t, err)
+		assert.Nil(t, results)
+	})
+	t.Run("AcrossLogs", func(t *testing.T) {
+		results, nextLogOffset, err := SearchLogs(ctx, env, LogSearchOptions{
+			Info:      LogInfo{Project: log1.Info.Project},
+			Pattern:   "(" + strings.TrimSuffix(log1Lines[10].Data, "\n") + "|" + strings.TrimSuffix(log2Lines[20].Data, "\n") + ")",
+			Regex:     true,
+			Context:   1,
+			TimeRange: tr,
+		})
+		require.NoError(t, err)
+		assert.Zero(t, nextLogOffset)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			switch result.LogID {
+			case log1.ID:
+				assert.Equal(t, log1.Info, result.Info)
+				assert.Equal(t, 11, result.LineNum)
+				assert.Equal(t, log1Lines[10].Data, result.Line.Data)
+				assert.Equal(t, log1Lines[9].Data, result.Before[0].Data)
+				assert.Equal(t, log1Lines[11].Data, result.After[0].Data)
+			case log2.ID:
+				assert.Equal(t, log2.Info, result.Info)
+				assert.Equal(t, 21, result.LineNum)
+				assert.Equal(t, log2Lines[20].Data, result.Line.Data)
+			default:
+				assert.Fail(t, "unexpected log ID", result.LogID)
+			}
+		}
+	})
+	t.Run("FilterByVersion", func(t *testing.T) {
+		results, _, err := SearchLogs(ctx, env, LogSearchOptions{
+			Info:      LogInfo{Project: log1.Info.Project, Version: log2.Info.Version},
+			Pattern:   strings.TrimSuffix(log1Lines[10].Data, "\n"),
+			TimeRange: tr,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+	t.Run("InProgressLogWithStartAt", func(t *testing.T) {
+		_, err := db.Collection(buildloggerCollection).UpdateOne(ctx, bson.M{"_id": log2.ID}, bson.M{"$set": bson.M{logCompletedAtKey: time.Time{}}})
+		require.NoError(t, err)
+		defer func() {
+			_, err := db.Collection(buildloggerCollection).UpdateOne(ctx, bson.M{"_id": log2.ID}, bson.M{"$set": bson.M{logCompletedAtKey: log2.CompletedAt}})
+			assert.NoError(t, err)
+		}()
+
+		results, _, err := SearchLogs(ctx, env, LogSearchOptions{
+			Info:      LogInfo{Project: log1.Info.Project},
+			Pattern:   strings.TrimSuffix(log2Lines[20].Data, "\n"),
+			TimeRange: TimeRange{StartAt: log1.CompletedAt.Add(time.Hour), EndAt: tr.EndAt},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		for _, result := range results {
+			assert.Equal(t, log2.ID, result.LogID)
+		}
+	})
+	t.Run("LogOffset", func(t *testing.T) {
+		opts := LogSearchOptions{
+			Info:      LogInfo{Project: log1.Info.Project},
+			Pattern:   "(" + strings.TrimSuffix(log1Lines[10].Data, "\n") + "|" + strings.TrimSuffix(log2Lines[20].Data, "\n") + ")",
+			Regex:     true,
+			TimeRange: tr,
+			LogOffset: 1,
+		}
+		results, nextLogOffset, err := SearchLogs(ctx, env, opts)
+		require.NoError(t, err)
+		assert.Zero(t, nextLogOffset)
+		assert.Len(t, results, 1)
+
+		opts.LogOffset = 2
+		results, _, err = SearchLogs(ctx, env, opts)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		results, _, err := SearchLogs(ctx, env, LogSearchOptions{
+			Info:      LogInfo{Project: log1.Info.Project},
+			Pattern:   ".",
+			Regex:     true,
+			Limit:     60,
+			TimeRange: tr,
+		})
+		require.NoError(t, err)
+		assert.Len(t, results, 60)
+	})
+}
+
+func TestCreateSearchQuery(t *testing.T) {
+	endAt := time.Now()
+	t.Run("NoStartAt", func(t *testing.T) {
+		query := createSearchQuery(LogSearchOptions{Info: LogInfo{Project: "project"}, TimeRange: TimeRange{EndAt: endAt}})
+		assert.Equal(t, bson.M{"$lte": endAt}, query[logCreatedAtKey])
+		assert.NotContains(t, query, "$or")
+	})
+	t.Run("StartAtIncludesInProgressLogs", func(t *testing.T) {
+		startAt := endAt.Add(-time.Hour)
+		query := createSearchQuery(LogSearchOptions{Info: LogInfo{Project: "project"}, TimeRange: TimeRange{StartAt: startAt, EndAt: endAt}})
+		assert.NotContains(t, query, logCompletedAtKey)
+		assert.Equal(t, []bson.M{
+			{logCompletedAtKey: bson.M{"$gte": startAt}},
+			{logCompletedAtKey: time.Time{}},
+			{logCompletedAtKey: nil},
+		}, query["$or"])
+	})
+}
+
+type sliceLogIterator struct {
+	lines []LogLine
+	idx   int
+	item  LogLine
+}
+
+func (i *sliceLogIterator) Next(_ context.Context) bool {
+	if i.idx >= len(i.lines) {
+		return false
+	}
+	i.item = i.lines[i.idx]
+	i.idx++
+	return true
+}
+
+func (i *sliceLogIterator) Exhausted() bool      { return i.idx >= len(i.lines) }
+func (i *sliceLogIterator) Err() error           { return nil }
+func (i *sliceLogIterator) Close() error         { return nil }
+func (i *sliceLogIterator) Item() LogLine        { return i.item }
+func (i *sliceLogIterator) Reverse() LogIterator { return i }
+func (i *sliceLogIterator) IsReversed() bool     { return false }
@@ -0,0 +1,370 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultLogSignatureTailLines   = 1000
+	maxLogFailureSignatures        = 20
+	maxLogFailureSignatureLength   = 200
+	maxLogFailureSnippetLength     = 1024
+	defaultTopLogSignaturesLimit   = 20
+	maxTopLogSignaturesLimit       = 100
+	logFailureSignatureNumberToken = "N"
+)
+
+// LogSignatureRule is a named regular expression matched against the lines
+// of a log to find failure signatures. If the pattern has a capture group,
+// the first group is used as the signature, otherwise the whole match is.
+type LogSignatureRule struct {
+	Name    string `bson:"name" json:"name" yaml:"name"`
+	Pattern string `bson:"pattern" json:"pattern" yaml:"pattern"`
+}
+
+var (
+	logSignatureRuleNameKey    = bsonutil.MustHaveTag(LogSignatureRule{}, "Name")
+	logSignatureRulePatternKey = bsonutil.MustHaveTag(LogSignatureRule{}, "Pattern")
+)
+
+// Validate ensures that the LogSignatureRule is valid.
+func (r LogSignatureRule) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(r.Name == "", "must specify a rule name")
+	catcher.NewWhen(r.Pattern == "", "must specify a rule pattern")
+	if r.Pattern != "" {
+		_, err := regexp.Compile(r.Pattern)
+		catcher.Wrap(err, "compiling rule pattern")
+	}
+
+	return catcher.Resolve()
+}
+
+// DefaultLogSignatureRules returns the rules used to find failure signatures
+// when none are configured: Go panics, assertion failures, segmentation
+// faults, and Go test failures.
+func DefaultLogSignatureRules() []LogSignatureRule {
+	return []LogSignatureRule{
+		{Name: "panic", Pattern: `\bpanic: (.+)`},
+		{Name: "assertion", Pattern: `(?i)\b(?:assertion (?:failure|failed)|assert(?:ion)?error)\b:?\s*(.*)`},
+		{Name: "segfault", Pattern: `(?i)(SIGSEGV|segmentation (?:fault|violation))`},
+		{Name: "go_test_fail", Pattern: `^\s*--- FAIL: (\S+)`},
+	}
+}
+
+// LogFailureSignature is a likely failure line found in the tail of a log.
+type LogFailureSignature struct {
+	// Rule is the name of the rule that matched the line.
+	Rule string `bson:"rule"`
+	// Signature is the normalized match, with numbers and addresses
+	// replaced, used to group the same failure across logs.
+	Signature string `bson:"signature"`
+	// Snippet is the matched line.
+	Snippet string `bson:"snippet"`
+	// LineNum is the zero-indexed offset of the matched line from the
+	// start of the log.
+	LineNum int `bson:"line_num"`
+}
+
+var (
+	logFailureSignatureRuleKey      = bsonutil.MustHaveTag(LogFailureSignature{}, "Rule")
+	logFailureSignatureSignatureKey = bsonutil.MustHaveTag(LogFailureSignature{}, "Signature")
+	logFailureSignatureSnippetKey   = bsonutil.MustHaveTag(LogFailureSignature{}, "Snippet")
+	logFailureSignatureLineNumKey   = bsonutil.MustHaveTag(LogFailureSignature{}, "LineNum")
+)
+
+// LogSignatureOptions describes the options for finding the failure
+// signatures of a log.
+type LogSignatureOptions struct {
+	// Rules are the rules matched against the tail of the log. Defaults
+	// to DefaultLogSignatureRules if empty.
+	Rules []LogSignatureRule
+	// TailLines is the number of lines from the end of the log to match
+	// against. Defaults to 1000 if not set.
+	TailLines int
+}
+
+// FindFailureSignatures returns the failure signatures found in the tail of
+// the log, in log order. Only the first line matching each distinct signature
+// is returned. The environment should not be nil.
+func (l *Log) FindFailureSignatures(ctx context.Context, opts LogSignatureOptions) ([]LogFailureSignature, error) {
+	if l.env == nil {
+		return nil, errors.New("cannot find failure signatures with a nil environment")
+	}
+
+	if len(opts.Rules) == 0 {
+		opts.Rules = DefaultLogSignatureRules()
+	}
+	if opts.TailLines <= 0 {
+		opts.TailLines = defaultLogSignatureTailLines
+	}
+	rules, err := compileLogSignatureRules(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, chunks, err := l.getBucketAndChunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var total int
+	for _, chunk := range chunks {
+		total += chunk.NumLines
+	}
+
+	it := NewStructuredLogIterator(NewBatchedLogIterator(bucket, chunks, 2, TimeRange{EndAt: maxLogFollowTime}), l.Info.Format).Reverse()
+	lines := make([]LogLine, 0, opts.TailLines)
+	for len(lines) < opts.TailLines && it.Next(ctx) {
+		lines = append(lines, it.Item())
+	}
+	catcher := grip.NewBasicCatcher()
+	catcher.Wrap(it.Err(), "iterating log lines")
+	if !it.Exhausted() {
+		catcher.Wrap(it.Close(), "closing log iterator")
+	}
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return matchLogFailureSignatures(lines, total-len(lines), rules), nil
+}
+
+// SetFailureSignatures sets the failure signatures of the log. The
+// environment should not be nil.
+func (l *Log) SetFailureSignatures(ctx context.Context, signatures []LogFailureSignature) error {
+	if l.env == nil {
+		return errors.New("cannot set failure signatures with a nil environment")
+	}
+
+	if l.ID == "" {
+		l.ID = l.Info.ID()
+	}
+
+	updateResult, err := l.env.GetDB().Collection(buildloggerCollection).UpdateOne(
+		ctx,
+		bson.M{"_id": l.ID},
+		bson.M{"$set": bson.M{logFailureSignaturesKey: signatures}},
+	)
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection":   buildloggerCollection,
+		"id":           l.ID,
+		"signatures":   len(signatures),
+		"updateResult": updateResult,
+		"op":           "set buildlogger log failure signatures",
+	})
+	if err == nil && updateResult.MatchedCount == 0 {
+		return errors.Errorf("could not find log record '%s'", l.ID)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "setting failure signatures for log '%s'", l.ID)
+	}
+
+	l.FailureSignatures = signatures
+	return nil
+}
+
+type compiledLogSignatureRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+func compileLogSignatureRules(rules []LogSignatureRule) ([]compiledLogSignatureRule, error) {
+	compiled := make([]compiledLogSignatureRule, len(rules))
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid log signature rule '%s'", rule.Name)
+		}
+		compiled[i] = compiledLogSignatureRule{name: rule.Name, re: regexp.MustCompile(rule.Pattern)}
+	}
+
+	return compiled, nil
+}
+
+var logFailureSignatureNumberRegexp = regexp.MustCompile(`0x[0-9a-fA-F]+|\d+`)
+
+// matchLogFailureSignatures matches the rules against the lines, where the
+// first line is at the given offset from the start of the log. Each line is
+// matched by at most one rule, in rule order.
+func matchLogFailureSignatures(lines []LogLine, offset int, rules []compiledLogSignatureRule) []LogFailureSignature {
+	var signatures []LogFailureSignature
+	seen := map[string]bool{}
+	for i, line := range lines {
+		if len(signatures) >= maxLogFailureSignatures {
+			break
+		}
+
+		data := strings.TrimRight(line.Data, "\n")
+		for _, rule := range rules {
+			match := rule.re.FindStringSubmatch(data)
+			if match == nil {
+				continue
+			}
+
+			signature := match[0]
+			if len(match) > 1 && strings.TrimSpace(match[1]) != "" {
+				signature = match[1]
+			}
+			signature = normalizeLogFailureSignature(signature)
+			if key := rule.name + "\x00" + signature; !seen[key] {
+				seen[key] = true
+				signatures = append(signatures, LogFailureSignature{
+					Rule:      rule.name,
+					Signature: signature,
+					Snippet:   truncateString(data, maxLogFailureSnippetLength),
+					LineNum:   offset + i,
+				})
+			}
+			break
+		}
+	}
+
+	return signatures
+}
+
+func normalizeLogFailureSignature(signature string) string {
+	signature = logFailureSignatureNumberRegexp.ReplaceAllStringFunc(signature, func(match string) string {
+		if strings.HasPrefix(match, "0x") {
+			return "0x" + logFailureSignatureNumberToken
+		}
+		return logFailureSignatureNumberToken
+	})
+	signature = strings.Join(strings.Fields(signature), " ")
+
+	return truncateString(signature, maxLogFailureSignatureLength)
+}
+
+// truncateString truncates the string to at most n bytes without splitting a
+// UTF-8 encoded rune.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// TopLogFailureSignaturesOptions describes the search criteria for finding
+// the most common failure signatures of a project's logs.
+type TopLogFailureSignaturesOptions struct {
+	Project string
+	// Variant optionally restricts the logs to a single build variant.
+	Variant string
+	// TimeRange bounds the creation time of the logs.
+	TimeRange TimeRange
+	// Limit is the maximum number of signatures returned. Defaults to 20
+	// if not set.
+	Limit int
+}
+
+// Validate ensures that the TopLogFailureSignaturesOptions are valid and sets
+// any defaults.
+func (o *TopLogFailureSignaturesOptions) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(o.Project == "", "must specify a project")
+	catcher.NewWhen(!o.TimeRange.IsValid(), "invalid time range")
+	catcher.NewWhen(o.Limit < 0 || o.Limit > maxTopLogSignaturesLimit, "invalid limit")
+	if o.Limit == 0 {
+		o.Limit = defaultTopLogSignaturesLimit
+	}
+
+	return catcher.Resolve()
+}
+
+// LogFailureSignatureCount is the number of logs with a failure signature
+// along with the most recent occurrence of the signature.
+type LogFailureSignatureCount struct {
+	Rule         string    `bson:"rule"`
+	Signature    string    `bson:"signature"`
+	Count        int       `bson:"count"`
+	LastSeen     time.Time `bson:"last_seen"`
+	LatestLogID  string    `bson:"latest_log_id"`
+	LatestTaskID string    `bson:"latest_task_id"`
+	Snippet      string    `bson:"snippet"`
+}
+
+// FindTopLogFailureSignatures returns the failure signatures found in the
+// most logs of the given project, sorted by count and then recency. The
+// environment should not be nil.
+func FindTopLogFailureSignatures(ctx context.Context, env cedar.Environment, opts TopLogFailureSignaturesOptions) ([]LogFailureSignatureCount, error) {
+	if env == nil {
+		return nil, errors.New("cannot find top failure signatures with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid top failure signatures options")
+	}
+
+	match := bson.M{
+		bsonutil.GetDottedKeyName(logInfoKey, logInfoProjectKey): opts.Project,
+		logCreatedAtKey: bson.M{
+			"$gte": opts.TimeRange.StartAt,
+			"$lte": opts.TimeRange.EndAt,
+		},
+		bsonutil.GetDottedKeyName(logFailureSignaturesKey, "0"): bson.M{"$exists": true},
+	}
+	if opts.Variant != "" {
+		match[bsonutil.GetDottedKeyName(logInfoKey, logInfoVariantKey)] = opts.Variant
+	}
+	signatureField := func(key string) string {
+		return "$" + bsonutil.GetDottedKeyName(logFailureSignaturesKey, key)
+	}
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{logCreatedAtKey: -1}},
+		{"$unwind": "$" + logFailureSignaturesKey},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"rule":      signatureField(logFailureSignatureRuleKey),
+				"signature": signatureField(logFailureSignatureSignatureKey),
+			},
+			"count":          bson.M{"$sum": 1},
+			"last_seen":      bson.M{"$first": "$" + logCreatedAtKey},
+			"latest_log_id":  bson.M{"$first": "$" + logIDKey},
+			"latest_task_id": bson.M{"$first": "$" + bsonutil.GetDottedKeyName(logInfoKey, logInfoTaskIDKey)},
+			"snippet":        bson.M{"$first": signatureField(logFailureSignatureSnippetKey)},
+		}},
+		{"$sort": bson.D{
+			{Key: "count", Value: -1},
+			{Key: "last_seen", Value: -1},
+		}},
+		{"$limit": opts.Limit},
+		{"$project": bson.M{
+			"_id":            0,
+			"rule":           "$_id.rule",
+			"signature":      "$_id.signature",
+			"count":          1,
+			"last_seen":      1,
+			"latest_log_id":  1,
+			"latest_task_id": 1,
+			"snippet":        1,
+		}},
+	}
+
+	cur, err := env.GetDB().Collection(buildloggerCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, "aggregating failure signatures")
+	}
+
+	var counts []LogFailureSignatureCount
+	if err = cur.All(ctx, &counts); err != nil {
+		return nil, errors.Wrap(err, "decoding failure signature counts")
+	}
+
+	return counts, nil
+}
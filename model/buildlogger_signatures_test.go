@@ -0,0 +1,366 @@
+package model
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/pail"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSignatureConfig(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		conf    LogSignatureConfig
+		invalid bool
+	}{
+		{name: "Empty"},
+		{
+			name: "Valid",
+			conf: LogSignatureConfig{
+				Rules:     DefaultLogSignatureRules(),
+				TailLines: 500,
+			},
+		},
+		{
+			name:    "NegativeTailLines",
+			conf:    LogSignatureConfig{TailLines: -1},
+			invalid: true,
+		},
+		{
+			name:    "MissingRuleName",
+			conf:    LogSignatureConfig{Rules: []LogSignatureRule{{Pattern: "panic"}}},
+			invalid: true,
+		},
+		{
+			name:    "MissingRulePattern",
+			conf:    LogSignatureConfig{Rules: []LogSignatureRule{{Name: "panic"}}},
+			invalid: true,
+		},
+		{
+			name:    "InvalidRulePattern",
+			conf:    LogSignatureConfig{Rules: []LogSignatureRule{{Name: "panic", Pattern: "pa(nic"}}},
+			invalid: true,
+		},
+		{
+			name: "DuplicateRuleNames",
+			conf: LogSignatureConfig{Rules: []LogSignatureRule{
+				{Name: "panic", Pattern: "panic"},
+				{Name: "panic", Pattern: "fatal"},
+			}},
+			invalid: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.invalid {
+				assert.Error(t, test.conf.Validate())
+			} else {
+				assert.NoError(t, test.conf.Validate())
+			}
+		})
+	}
+}
+
+func TestMatchLogFailureSignatures(t *testing.T) {
+	rules, err := compileLogSignatureRules(DefaultLogSignatureRules())
+	require.NoError(t, err)
+
+	t.Run("DefaultRules", func(t *testing.T) {
+		lines := []LogLine{
+			{Data: "=== RUN   TestFoo\n"},
+			{Data: "panic: runtime error: index out of range [3] with length 3\n"},
+			{Data: "goroutine 12 [running]:\n"},
+			{Data: "[signal SIGSEGV: segmentation violation code=0x1 addr=0xc0001 pc=0x4a2b3c]\n"},
+			{Data: "    --- FAIL: TestFoo/Subtest (0.01s)\n"},
+			{Data: "AssertionError: expected 1 to equal 2\n"},
+			{Data: "ok  \tgithub.com/evergreen-ci/cedar\t0.012s\n"},
+		}
+
+		assert.Equal(t, []LogFailureSignature{
+			{
+				Rule:      "panic",
+				Signature: "runtime error: index out of range [N] with length N",
+				Snippet:   "panic: runtime error: index out of range [3] with length 3",
+				LineNum:   101,
+			},
+			{
+				Rule:      "segfault",
+				Signature: "SIGSEGV",
+				Snippet:   "[signal SIGSEGV: segmentation violation code=0x1 addr=0xc0001 pc=0x4a2b3c]",
+				LineNum:   103,
+			},
+			{
+				Rule:      "go_test_fail",
+				Signature: "TestFoo/Subtest",
+				Snippet:   "    --- FAIL: TestFoo/Subtest (0.01s)",
+				LineNum:   104,
+			},
+			{
+				Rule:      "assertion",
+				Signature: "expected N to equal N",
+				Snippet:   "AssertionError: expected 1 to equal 2",
+				LineNum:   105,
+			},
+		}, matchLogFailureSignatures(lines, 100, rules))
+	})
+	t.Run("NoMatches", func(t *testing.T) {
+		assert.Empty(t, matchLogFailureSignatures([]LogLine{{Data: "all good\n"}}, 0, rules))
+	})
+	t.Run("Dedupes", func(t *testing.T) {
+		lines := []LogLine{
+			{Data: "panic: nil pointer at 0xdeadbeef\n"},
+			{Data: "panic: nil pointer at 0x1234\n"},
+			{Data: "panic: other\n"},
+		}
+
+		signatures := matchLogFailureSignatures(lines, 0, rules)
+		require.Len(t, signatures, 2)
+		assert.Equal(t, "nil pointer at 0xN", signatures[0].Signature)
+		assert.Equal(t, 0, signatures[0].LineNum)
+		assert.Equal(t, "other", signatures[1].Signature)
+		assert.Equal(t, 2, signatures[1].LineNum)
+	})
+	t.Run("WholeMatchWithoutCaptureGroup", func(t *testing.T) {
+		customRules, err := compileLogSignatureRules([]LogSignatureRule{{Name: "oom", Pattern: `out of memory\s+in\s+\w+`}})
+		require.NoError(t, err)
+
+		signatures := matchLogFailureSignatures([]LogLine{{Data: "fatal: out of memory   in worker\n"}}, 0, customRules)
+		require.Len(t, signatures, 1)
+		assert.Equal(t, "out of memory in worker", signatures[0].Signature)
+	})
+	t.Run("Limits", func(t *testing.T) {
+		var lines []LogLine
+		for i := 0; i < 2*maxLogFailureSignatures; i++ {
+			lines = append(lines, LogLine{Data: "--- FAIL: Test" + strings.Repeat("x", i+1) + "\n"})
+		}
+		lines = append(lines, LogLine{Data: "panic: " + strings.Repeat("é", maxLogFailureSnippetLength)})
+
+		signatures := matchLogFailureSignatures(lines, 0, rules)
+		assert.Len(t, signatures, maxLogFailureSignatures)
+
+		signatures = matchLogFailureSignatures(lines[len(lines)-1:], 0, rules)
+		require.Len(t, signatures, 1)
+		assert.True(t, len(signatures[0].Signature) <= maxLogFailureSignatureLength)
+		assert.True(t, len(signatures[0].Snippet) <= maxLogFailureSnippetLength)
+		assert.True(t, strings.HasSuffix(signatures[0].Snippet, "é"))
+	})
+}
+
+func TestBuildloggerFindFailureSignatures(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpDir, err := ioutil.TempDir(".", "failure-signatures-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, db.Collection(buildloggerCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(configurationCollection).Drop(ctx))
+	}()
+
+	conf := &CedarConfig{
+		populated: true,
+		Bucket:    BucketConfig{BuildLogsBucket: tmpDir},
+	}
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	log := &Log{
+		Info: LogInfo{
+			Project:  "project",
+			TaskID:   "task",
+			ExitCode: 1,
+		},
+		CreatedAt: time.Now().Add(-time.Hour),
+		Artifact: LogArtifactInfo{
+			Type:    PailLocal,
+			Prefix:  "log",
+			Version: 1,
+		},
+	}
+	log.ID = log.Info.ID()
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: log.Artifact.Prefix})
+	require.NoError(t, err)
+	ts := time.Now().Add(-time.Hour).Round(time.Millisecond).UTC()
+	for i, data := range [][]string{
+		{"panic: early failure", "line 1", "line 2"},
+		{"line 3", "--- FAIL: TestBar (0.50s)", "line 5"},
+	} {
+		var raw string
+		start := ts.Add(time.Duration(i) * time.Minute)
+		for j, line := range data {
+			raw += prependPriorityAndTimestamp(level.Info, start.Add(time.Duration(j)*time.Millisecond), line)
+		}
+		key := createBuildloggerChunkKey(start, start.Add(time.Duration(len(data)-1)*time.Millisecond), len(data))
+		require.NoError(t, bucket.Put(ctx, key, strings.NewReader(raw)))
+	}
+	_, err = db.Collection(buildloggerCollection).InsertOne(ctx, log)
+	require.NoError(t, err)
+
+	t.Run("NoEnv", func(t *testing.T) {
+		l := &Log{ID: log.ID}
+		_, err := l.FindFailureSignatures(ctx, LogSignatureOptions{})
+		assert.Error(t, err)
+		assert.Error(t, l.SetFailureSignatures(ctx, nil))
+	})
+	t.Run("InvalidRules", func(t *testing.T) {
+		l := *log
+		l.Setup(env)
+		_, err := l.FindFailureSignatures(ctx, LogSignatureOptions{Rules: []LogSignatureRule{{Name: "bad", Pattern: "("}}})
+		assert.Error(t, err)
+	})
+	t.Run("EntireLog", func(t *testing.T) {
+		l := *log
+		l.Setup(env)
+		signatures, err := l.FindFailureSignatures(ctx, LogSignatureOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []LogFailureSignature{
+			{Rule: "panic", Signature: "early failure", Snippet: "panic: early failure", LineNum: 0},
+			{Rule: "go_test_fail", Signature: "TestBar", Snippet: "--- FAIL: TestBar (0.50s)", LineNum: 4},
+		}, signatures)
+	})
+	t.Run("Tail", func(t *testing.T) {
+		l := *log
+		l.Setup(env)
+		signatures, err := l.FindFailureSignatures(ctx, LogSignatureOptions{TailLines: 3})
+		require.NoError(t, err)
+		assert.Equal(t, []LogFailureSignature{
+			{Rule: "go_test_fail", Signature: "TestBar", Snippet: "--- FAIL: TestBar (0.50s)", LineNum: 4},
+		}, signatures)
+	})
+	t.Run("SetDNE", func(t *testing.T) {
+		l := &Log{ID: "DNE"}
+		l.Setup(env)
+		assert.Error(t, l.SetFailureSignatures(ctx, []LogFailureSignature{{Rule: "panic"}}))
+	})
+	t.Run("Set", func(t *testing.T) {
+		l := *log
+		l.Setup(env)
+		signatures := []LogFailureSignature{{Rule: "panic", Signature: "early failure", Snippet: "panic: early failure"}}
+		require.NoError(t, l.SetFailureSignatures(ctx, signatures))
+		assert.Equal(t, signatures, l.FailureSignatures)
+
+		found := &Log{ID: log.ID}
+		found.Setup(env)
+		require.NoError(t, found.Find(ctx))
+		assert.Equal(t, signatures, found.FailureSignatures)
+	})
+}
+
+func TestFindTopLogFailureSignatures(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(buildloggerCollection).Drop(ctx))
+	}()
+
+	now := time.Now().Round(time.Millisecond).UTC()
+	panicSig := LogFailureSignature{Rule: "panic", Signature: "runtime error", Snippet: "panic: runtime error"}
+	testSig := LogFailureSignature{Rule: "go_test_fail", Signature: "TestFoo", Snippet: "--- FAIL: TestFoo"}
+	for _, log := range []Log{
+		{
+			ID:                "log0",
+			Info:              LogInfo{Project: "project", Variant: "v0", TaskID: "task0"},
+			CreatedAt:         now.Add(-3 * time.Hour),
+			FailureSignatures: []LogFailureSignature{panicSig, testSig},
+		},
+		{
+			ID:                "log1",
+			Info:              LogInfo{Project: "project", Variant: "v1", TaskID: "task1"},
+			CreatedAt:         now.Add(-2 * time.Hour),
+			FailureSignatures: []LogFailureSignature{panicSig},
+		},
+		{
+			ID:        "log2",
+			Info:      LogInfo{Project: "project", Variant: "v1", TaskID: "task2"},
+			CreatedAt: now.Add(-time.Hour),
+		},
+		{
+			ID:                "log3",
+			Info:              LogInfo{Project: "other", Variant: "v1", TaskID: "task3"},
+			CreatedAt:         now.Add(-time.Hour),
+			FailureSignatures: []LogFailureSignature{testSig},
+		},
+	} {
+		_, err := db.Collection(buildloggerCollection).InsertOne(ctx, log)
+		require.NoError(t, err)
+	}
+
+	t.Run("NoEnv", func(t *testing.T) {
+		_, err := FindTopLogFailureSignatures(ctx, nil, TopLogFailureSignaturesOptions{Project: "project", TimeRange: TimeRange{EndAt: now}})
+		assert.Error(t, err)
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		_, err := FindTopLogFailureSignatures(ctx, env, TopLogFailureSignaturesOptions{TimeRange: TimeRange{EndAt: now}})
+		assert.Error(t, err)
+	})
+	t.Run("Project", func(t *testing.T) {
+		counts, err := FindTopLogFailureSignatures(ctx, env, TopLogFailureSignaturesOptions{
+			Project:   "project",
+			TimeRange: TimeRange{StartAt: now.Add(-24 * time.Hour), EndAt: now},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []LogFailureSignatureCount{
+			{
+				Rule:         panicSig.Rule,
+				Signature:    panicSig.Signature,
+				Count:        2,
+				LastSeen:     now.Add(-2 * time.Hour),
+				LatestLogID:  "log1",
+				LatestTaskID: "task1",
+				Snippet:      panicSig.Snippet,
+			},
+			{
+				Rule:         testSig.Rule,
+				Signature:    testSig.Signature,
+				Count:        1,
+				LastSeen:     now.Add(-3 * time.Hour),
+				LatestLogID:  "log0",
+				LatestTaskID: "task0",
+				Snippet:      testSig.Snippet,
+			},
+		}, counts)
+	})
+	t.Run("Variant", func(t *testing.T) {
+		counts, err := FindTopLogFailureSignatures(ctx, env, TopLogFailureSignaturesOptions{
+			Project:   "project",
+			Variant:   "v0",
+			TimeRange: TimeRange{StartAt: now.Add(-24 * time.Hour), EndAt: now},
+		})
+		require.NoError(t, err)
+		require.Len(t, counts, 2)
+		assert.Equal(t, 1, counts[0].Count)
+		assert.Equal(t, 1, counts[1].Count)
+	})
+	t.Run("TimeRange", func(t *testing.T) {
+		counts, err := FindTopLogFailureSignatures(ctx, env, TopLogFailureSignaturesOptions{
+			Project:   "project",
+			TimeRange: TimeRange{StartAt: now.Add(-150 * time.Minute), EndAt: now},
+		})
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		assert.Equal(t, panicSig.Signature, counts[0].Signature)
+		assert.Equal(t, 1, counts[0].Count)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		counts, err := FindTopLogFailureSignatures(ctx, env, TopLogFailureSignaturesOptions{
+			Project:   "project",
+			TimeRange: TimeRange{StartAt: now.Add(-24 * time.Hour), EndAt: now},
+			Limit:     1,
+		})
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		assert.Equal(t, panicSig.Signature, counts[0].Signature)
+	})
+}
@@ -1,13 +1,24 @@
 package model
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"path"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/pail"
+	"github.com/klauspost/compress/zstd"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // LogFormat is a type that describes the format of a log.
@@ -31,6 +42,82 @@ func (lf LogFormat) Validate() error {
 	}
 }
 
+// IsStructured returns whether lines of the log format are structured
+// records rather than plain text.
+func (lf LogFormat) IsStructured() bool {
+	return lf == LogFormatJSON || lf == LogFormatBSON
+}
+
+// encodeLineData encodes the data of a log line for storage. JSON objects
+// are compacted onto a single line, while JSON log lines that are not JSON
+// objects, such as plain text output interleaved with the records, are
+// stored as is and read back as unstructured lines. BSON lines must be BSON
+// documents and are base64 encoded so that they do not break the newline
+// delimited chunk encoding. Lines of any other format are stored as is.
+func (lf LogFormat) encodeLineData(data string) (string, error) {
+	switch lf {
+	case LogFormatJSON:
+		trimmed := strings.TrimSpace(data)
+		if !strings.HasPrefix(trimmed, "{") || !json.Valid([]byte(trimmed)) {
+			return data, nil
+		}
+
+		buf := &bytes.Buffer{}
+		if err := json.Compact(buf, []byte(trimmed)); err != nil {
+			return "", errors.Wrap(err, "compacting JSON log line")
+		}
+		return buf.String(), nil
+	case LogFormatBSON:
+		if err := bson.Raw(data).Validate(); err != nil {
+			return "", errors.Wrap(err, "BSON log lines must be BSON documents")
+		}
+
+		return base64.StdEncoding.EncodeToString([]byte(data)), nil
+	default:
+		return data, nil
+	}
+}
+
+// decodeLineData decodes the stored data of a log line into its structured
+// fields, returning the line's data as a JSON object along with the fields.
+// BSON documents are returned as relaxed extended JSON. An error is returned
+// if the data is not a structured record of the log format, such as lines
+// stored before the format was supported.
+func (lf LogFormat) decodeLineData(data string) (string, map[string]interface{}, error) {
+	data = strings.TrimSuffix(data, "\n")
+
+	switch lf {
+	case LogFormatJSON:
+	case LogFormatBSON:
+		raw, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "decoding base64 BSON log line")
+		}
+		extJSON, err := bson.MarshalExtJSON(bson.Raw(raw), false, false)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "converting BSON log line to JSON")
+		}
+		data = string(extJSON)
+	default:
+		return "", nil, errors.Errorf("log format '%s' is not structured", lf)
+	}
+
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return "", nil, errors.Wrap(err, "decoding JSON log line")
+	}
+	if fields == nil {
+		return "", nil, errors.New("log line is not an object")
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", nil, errors.New("log line has data after the object")
+	}
+
+	return data + "\n", fields, nil
+}
+
 // LogArtifact describes a bucket of logs stored in some kind of offline blob
 // storage. It is the bridge between pail-backed offline log storage and the
 // cedar-based log metadata storage. The prefix field indicates the name of the
@@ -40,16 +127,30 @@ type LogArtifactInfo struct {
 	Type    PailType `bson:"type"`
 	Prefix  string   `bson:"prefix"`
 	Version int      `bson:"version"`
+	// Compression is the codec used to compress the log's chunks. This
+	// field is part of the version 2 LogArtifactInfo model.
+	Compression LogCompression `bson:"compression,omitempty"`
 	// This field is part of the version 0 LogArtifactInfo model, we are
 	// keeping it for backwards compatibility.
 	Chunks []LogChunkInfo `bson:"chunks,omitempty"`
 }
 
+// getCompression returns the codec used to compress the log's chunks. Logs
+// with an artifact version before 2 are never compressed.
+func (a LogArtifactInfo) getCompression() LogCompression {
+	if a.Version < 2 {
+		return LogCompressionNone
+	}
+
+	return a.Compression
+}
+
 var (
-	logArtifactInfoTypeKey    = bsonutil.MustHaveTag(LogArtifactInfo{}, "Type")
-	logArtifactInfoPrefixKey  = bsonutil.MustHaveTag(LogArtifactInfo{}, "Prefix")
-	logArtifactInfoVersionKey = bsonutil.MustHaveTag(LogArtifactInfo{}, "Version")
-	logArtifactInfoChunksKey  = bsonutil.MustHaveTag(LogArtifactInfo{}, "Chunks")
+	logArtifactInfoTypeKey        = bsonutil.MustHaveTag(LogArtifactInfo{}, "Type")
+	logArtifactInfoPrefixKey      = bsonutil.MustHaveTag(LogArtifactInfo{}, "Prefix")
+	logArtifactInfoVersionKey     = bsonutil.MustHaveTag(LogArtifactInfo{}, "Version")
+	logArtifactInfoChunksKey      = bsonutil.MustHaveTag(LogArtifactInfo{}, "Chunks")
+	logArtifactInfoCompressionKey = bsonutil.MustHaveTag(LogArtifactInfo{}, "Compression")
 )
 
 // LogChunkInfo describes a chunk of log lines stored in pail-backed offline
@@ -59,6 +160,10 @@ type LogChunkInfo struct {
 	NumLines int       `bson:"num_lines"`
 	Start    time.Time `bson:"start"`
 	End      time.Time `bson:"end"`
+	// Compression is the codec used to compress the chunk. It is not
+	// stored and is instead populated from the log's artifact info when
+	// the chunks are fetched.
+	Compression LogCompression `bson:"-"`
 }
 
 var (
@@ -81,9 +186,10 @@ func createBuildloggerChunkKey(start, end time.Time, numLines int) string {
 
 // parseBuildloggerChunkKey returns a LogChunkInfo object with the information
 // encoded in the given key. The key must have been created by
-// createBuildloggerChunkKey (see above).
+// createBuildloggerChunkKey (see above), optionally suffixed with the
+// extension of a compression codec.
 func parseBuildloggerChunkKey(key string) (LogChunkInfo, error) {
-	chunkInfo := strings.Split(key, "_")
+	chunkInfo := strings.Split(strings.TrimSuffix(key, path.Ext(key)), "_")
 	if len(chunkInfo) != 3 {
 		return LogChunkInfo{}, errors.New("invalid buildlogger chunk key")
 	}
@@ -108,3 +214,124 @@ func parseBuildloggerChunkKey(key string) (LogChunkInfo, error) {
 		End:      time.Unix(0, end).UTC(),
 	}, nil
 }
+
+// LogCompression is a type that describes the codec used to compress the
+// chunks of a log in pail-backed offline storage.
+type LogCompression string
+
+// Valid log compression codecs.
+const (
+	LogCompressionNone LogCompression = ""
+	LogCompressionGzip LogCompression = "gzip"
+	LogCompressionZstd LogCompression = "zstd"
+)
+
+// Validate the log compression codec.
+func (lc LogCompression) Validate() error {
+	switch lc {
+	case LogCompressionNone, LogCompressionGzip, LogCompressionZstd:
+		return nil
+	default:
+		return errors.Errorf("invalid log compression codec '%s'", lc)
+	}
+}
+
+// extension returns the file extension appended to the keys of chunks
+// compressed with this codec.
+func (lc LogCompression) extension() string {
+	switch lc {
+	case LogCompressionGzip:
+		return ".gz"
+	case LogCompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+func (lc LogCompression) compress(data []byte) ([]byte, error) {
+	switch lc {
+	case LogCompressionNone:
+		return data, nil
+	case LogCompressionGzip:
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		catcher := grip.NewBasicCatcher()
+		_, err := w.Write(data)
+		catcher.Wrap(err, "writing gzip data")
+		catcher.Wrap(w.Close(), "closing gzip writer")
+		return buf.Bytes(), catcher.Resolve()
+	case LogCompressionZstd:
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Wrap(err, "creating zstd encoder")
+		}
+		defer enc.Close()
+		return enc.EncodeAll(data, nil), nil
+	default:
+		return nil, errors.Errorf("invalid log compression codec '%s'", lc)
+	}
+}
+
+// newReader returns a ReadCloser that decompresses the data read from the
+// given ReadCloser. Closing the returned ReadCloser also closes the given
+// ReadCloser.
+func (lc LogCompression) newReader(rc io.ReadCloser) (io.ReadCloser, error) {
+	switch lc {
+	case LogCompressionNone:
+		return rc, nil
+	case LogCompressionGzip:
+		r, err := gzip.NewReader(rc)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating gzip reader")
+		}
+		return &decompressingReadCloser{Reader: r, closers: []func() error{r.Close, rc.Close}}, nil
+	case LogCompressionZstd:
+		dec, err := zstd.NewReader(rc, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Wrap(err, "creating zstd reader")
+		}
+		return &decompressingReadCloser{
+			Reader: dec,
+			closers: []func() error{
+				func() error { dec.Close(); return nil },
+				rc.Close,
+			},
+		}, nil
+	default:
+		return nil, errors.Errorf("invalid log compression codec '%s'", lc)
+	}
+}
+
+type decompressingReadCloser struct {
+	io.Reader
+	closers []func() error
+}
+
+func (r *decompressingReadCloser) Close() error {
+	catcher := grip.NewBasicCatcher()
+	for _, closer := range r.closers {
+		catcher.Add(closer())
+	}
+
+	return catcher.Resolve()
+}
+
+// getLogChunk returns a ReadCloser for the given chunk's log lines,
+// decompressing them if necessary.
+func getLogChunk(ctx context.Context, bucket pail.Bucket, chunk LogChunkInfo) (io.ReadCloser, error) {
+	rc, err := bucket.Get(ctx, chunk.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := chunk.Compression.newReader(rc)
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(err)
+		catcher.Add(rc.Close())
+		return nil, catcher.Resolve()
+	}
+
+	return r, nil
+}
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/pail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseBuildloggerChunkKey(t *testing.T) {
+	start := time.Now().Add(-time.Hour).Truncate(time.Millisecond).UTC()
+	end := time.Now().Truncate(time.Millisecond).UTC()
+	key := createBuildloggerChunkKey(start, end, 10)
+
+	for _, compression := range []LogCompression{LogCompressionNone, LogCompressionGzip, LogCompressionZstd} {
+		t.Run(string(compression), func(t *testing.T) {
+			chunk, err := parseBuildloggerChunkKey(key + compression.extension())
+			require.NoError(t, err)
+			assert.Equal(t, LogChunkInfo{
+				Key:      key + compression.extension(),
+				NumLines: 10,
+				Start:    start,
+				End:      end,
+			}, chunk)
+		})
+	}
+	t.Run("InvalidKey", func(t *testing.T) {
+		_, err := parseBuildloggerChunkKey("1_2")
+		assert.Error(t, err)
+	})
+}
+
+func TestLogCompression(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "log-compression-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, LogCompressionNone.Validate())
+		assert.NoError(t, LogCompressionGzip.Validate())
+		assert.NoError(t, LogCompressionZstd.Validate())
+		assert.Error(t, LogCompression("lz4").Validate())
+	})
+	for _, compression := range []LogCompression{LogCompressionNone, LogCompressionGzip, LogCompressionZstd} {
+		t.Run(string(compression), func(t *testing.T) {
+			bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: string(compression)})
+			require.NoError(t, err)
+			chunks, lines, err := GenerateTestLog(ctx, bucket, 95, 10)
+			require.NoError(t, err)
+
+			keys := make([]string, len(chunks))
+			for i := range chunks {
+				keys[i] = chunks[i].Key
+				if compression == LogCompressionNone {
+					continue
+				}
+				require.NoError(t, recompressLogChunk(ctx, bucket, chunks[i], compression))
+				chunks[i].Key += compression.extension()
+				chunks[i].Compression = compression
+			}
+			if compression != LogCompressionNone {
+				require.NoError(t, bucket.RemoveMany(ctx, keys...))
+			}
+
+			log := &Log{Artifact: LogArtifactInfo{Version: 2, Compression: compression}}
+			if compression == LogCompressionNone {
+				log.Artifact.Version = 1
+			}
+			bucketChunks, err := log.getChunks(ctx, bucket)
+			require.NoError(t, err)
+			assert.Equal(t, chunks, bucketChunks)
+
+			timeRange := TimeRange{EndAt: chunks[len(chunks)-1].End}
+			for name, it := range map[string]LogIterator{
+				serialized:  NewSerializedLogIterator(bucket, bucketChunks, timeRange),
+				serializedR: NewSerializedLogIterator(bucket, bucketChunks, timeRange).Reverse(),
+				batched:     NewBatchedLogIterator(bucket, bucketChunks, 2, timeRange),
+				batchedR:    NewBatchedLogIterator(bucket, bucketChunks, 2, timeRange).Reverse(),
+			} {
+				t.Run(name, func(t *testing.T) {
+					var count int
+					for it.Next(ctx) {
+						idx := count
+						if it.IsReversed() {
+							idx = len(lines) - count - 1
+						}
+						require.Equal(t, lines[idx], it.Item())
+						count++
+					}
+					assert.NoError(t, it.Err())
+					assert.NoError(t, it.Close())
+					assert.Equal(t, len(lines), count)
+				})
+			}
+		})
+	}
+	t.Run("MixedChunks", func(t *testing.T) {
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: "mixed"})
+		require.NoError(t, err)
+		chunks, _, err := GenerateTestLog(ctx, bucket, 20, 10)
+		require.NoError(t, err)
+		require.NoError(t, recompressLogChunk(ctx, bucket, chunks[0], LogCompressionZstd))
+
+		log := &Log{Artifact: LogArtifactInfo{Version: 1}}
+		bucketChunks, err := log.getChunks(ctx, bucket)
+		require.NoError(t, err)
+		assert.Equal(t, chunks, bucketChunks)
+
+		log.Artifact = LogArtifactInfo{Version: 2, Compression: LogCompressionZstd}
+		bucketChunks, err = log.getChunks(ctx, bucket)
+		require.NoError(t, err)
+		require.Len(t, bucketChunks, 1)
+		assert.Equal(t, chunks[0].Key+LogCompressionZstd.extension(), bucketChunks[0].Key)
+
+		log.Artifact = LogArtifactInfo{Version: 2, Compression: "lz4"}
+		_, err = log.getChunks(ctx, bucket)
+		assert.Error(t, err)
+	})
+}
+
+func TestLogFormatLineData(t *testing.T) {
+	bsonRecord, err := bson.Marshal(bson.D{
+		{Key: "level", Value: "error"},
+		{Key: "count", Value: int32(3)},
+		{Key: "ctx", Value: bson.D{{Key: "host", Value: "localhost"}}},
+	})
+	require.NoError(t, err)
+
+	t.Run("IsStructured", func(t *testing.T) {
+		assert.True(t, LogFormatJSON.IsStructured())
+		assert.True(t, LogFormatBSON.IsStructured())
+		assert.False(t, LogFormatText.IsStructured())
+		assert.False(t, LogFormatUnknown.IsStructured())
+	})
+	t.Run("JSON", func(t *testing.T) {
+		data, err := LogFormatJSON.encodeLineData("{\n  \"level\": \"error\",\n  \"count\": 3,\n  \"ctx\": {\"host\": \"localhost\"}\n}\n")
+		require.NoError(t, err)
+		assert.Equal(t, `{"level":"error","count":3,"ctx":{"host":"localhost"}}`, data)
+
+		decoded, fields, err := LogFormatJSON.decodeLineData(data + "\n")
+		require.NoError(t, err)
+		assert.Equal(t, data+"\n", decoded)
+		assert.Equal(t, map[string]interface{}{
+			"level": "error",
+			"count": json.Number("3"),
+			"ctx":   map[string]interface{}{"host": "localhost"},
+		}, fields)
+	})
+	t.Run("BSON", func(t *testing.T) {
+		data, err := LogFormatBSON.encodeLineData(string(bsonRecord))
+		require.NoError(t, err)
+		assert.NotContains(t, data, "\n")
+
+		decoded, fields, err := LogFormatBSON.decodeLineData(data + "\n")
+		require.NoError(t, err)
+		assert.Equal(t, `{"level":"error","count":3,"ctx":{"host":"localhost"}}`+"\n", decoded)
+		assert.Equal(t, map[string]interface{}{
+			"level": "error",
+			"count": json.Number("3"),
+			"ctx":   map[string]interface{}{"host": "localhost"},
+		}, fields)
+	})
+	t.Run("Text", func(t *testing.T) {
+		data, err := LogFormatText.encodeLineData("{not json")
+		require.NoError(t, err)
+		assert.Equal(t, "{not json", data)
+
+		_, _, err = LogFormatText.decodeLineData(data)
+		assert.Error(t, err)
+	})
+	t.Run("UnstructuredJSONLines", func(t *testing.T) {
+		for _, data := range []string{"not json", `["array"]`, `{"level": "error"`, `{"level": "error"} trailing`} {
+			encoded, err := LogFormatJSON.encodeLineData(data)
+			require.NoError(t, err, data)
+			assert.Equal(t, data, encoded)
+
+			_, _, err = LogFormatJSON.decodeLineData(encoded + "\n")
+			assert.Error(t, err, data)
+		}
+	})
+	t.Run("InvalidLines", func(t *testing.T) {
+		for _, data := range []string{"not bson", string(bsonRecord[:len(bsonRecord)-1])} {
+			_, err := LogFormatBSON.encodeLineData(data)
+			assert.Error(t, err)
+		}
+	})
+	t.Run("UndecodableLines", func(t *testing.T) {
+		_, _, err := LogFormatJSON.decodeLineData("plain text\n")
+		assert.Error(t, err)
+		_, _, err = LogFormatJSON.decodeLineData("null\n")
+		assert.Error(t, err)
+		_, _, err = LogFormatBSON.decodeLineData("plain text\n")
+		assert.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -309,6 +310,107 @@ func TestBuildloggerAppend(t *testing.T) {
 		assert.True(t, filenames[createBuildloggerChunkKey(chunk1[0].Timestamp, chunk1[len(chunk1)-1].Timestamp, len(chunk1))])
 		assert.True(t, filenames[createBuildloggerChunkKey(chunk2[0].Timestamp, chunk2[len(chunk2)-1].Timestamp, len(chunk2))])
 	})
+	t.Run("AppendCompressedToBucket", func(t *testing.T) {
+		compressedLog := Log{
+			ID: "compressed",
+			Artifact: LogArtifactInfo{
+				Type:        PailLocal,
+				Prefix:      "compressed",
+				Version:     2,
+				Compression: LogCompressionGzip,
+			},
+			populated: true,
+		}
+		compressedLog.Setup(env)
+		require.NoError(t, compressedLog.Append(ctx, chunk1))
+		time.Sleep(time.Millisecond)
+		require.NoError(t, compressedLog.Append(ctx, chunk2))
+
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: compressedLog.Artifact.Prefix})
+		require.NoError(t, err)
+		chunks, err := compressedLog.getChunks(ctx, bucket)
+		require.NoError(t, err)
+		require.Len(t, chunks, 2)
+		assert.Equal(t, createBuildloggerChunkKey(chunk1[0].Timestamp, chunk1[len(chunk1)-1].Timestamp, len(chunk1))+".gz", chunks[0].Key)
+		assert.Equal(t, createBuildloggerChunkKey(chunk2[0].Timestamp, chunk2[len(chunk2)-1].Timestamp, len(chunk2))+".gz", chunks[1].Key)
+
+		it := NewSerializedLogIterator(bucket, chunks, TimeRange{EndAt: time.Now()})
+		var count int
+		for _, line := range append(chunk1, chunk2...) {
+			require.True(t, it.Next(ctx))
+			assert.Equal(t, line.Data+"\n", it.Item().Data)
+			count++
+		}
+		assert.False(t, it.Next(ctx))
+		assert.NoError(t, it.Err())
+		assert.NoError(t, it.Close())
+	})
+	t.Run("AppendStructuredToBucket", func(t *testing.T) {
+		bsonRecord, err := bson.Marshal(bson.D{{Key: "level", Value: "error"}, {Key: "msg", Value: "failed"}})
+		require.NoError(t, err)
+		ts := time.Now().Add(-time.Hour).Round(time.Millisecond).UTC()
+
+		for _, test := range []struct {
+			format         LogFormat
+			data           string
+			expectedData   string
+			expectedFields map[string]interface{}
+			storesRaw      bool
+		}{
+			{
+				format:         LogFormatJSON,
+				data:           "{\n  \"level\": \"error\",\n  \"count\": 3\n}\n",
+				expectedData:   `{"level":"error","count":3}` + "\n",
+				expectedFields: map[string]interface{}{"level": "error", "count": json.Number("3")},
+				storesRaw:      true,
+			},
+			{
+				format:         LogFormatBSON,
+				data:           string(bsonRecord),
+				expectedData:   `{"level":"error","msg":"failed"}` + "\n",
+				expectedFields: map[string]interface{}{"level": "error", "msg": "failed"},
+			},
+		} {
+			t.Run(string(test.format), func(t *testing.T) {
+				structuredLog := Log{
+					ID:   "structured-" + string(test.format),
+					Info: LogInfo{Format: test.format},
+					Artifact: LogArtifactInfo{
+						Type:    PailLocal,
+						Prefix:  "structured-" + string(test.format),
+						Version: 1,
+					},
+					populated: true,
+				}
+				structuredLog.Setup(env)
+				lines := []LogLine{
+					{Priority: level.Info, Timestamp: ts, Data: "not structured"},
+					{Priority: level.Info, Timestamp: ts, Data: test.data},
+				}
+				if test.storesRaw {
+					require.NoError(t, structuredLog.Append(ctx, lines))
+				} else {
+					assert.Error(t, structuredLog.Append(ctx, lines))
+					require.NoError(t, structuredLog.Append(ctx, lines[1:]))
+				}
+
+				structuredLog.Setup(env)
+				it, err := structuredLog.Download(ctx, TimeRange{EndAt: time.Now()})
+				require.NoError(t, err)
+				if test.storesRaw {
+					require.True(t, it.Next(ctx))
+					assert.Equal(t, "not structured\n", it.Item().Data)
+					assert.Nil(t, it.Item().Fields)
+				}
+				require.True(t, it.Next(ctx))
+				assert.Equal(t, test.expectedData, it.Item().Data)
+				assert.Equal(t, test.expectedFields, it.Item().Fields)
+				assert.False(t, it.Next(ctx))
+				assert.NoError(t, it.Err())
+				assert.NoError(t, it.Close())
+			})
+		}
+	})
 }
 
 func TestBuildloggerDownload(t *testing.T) {
@@ -430,6 +532,76 @@ func TestBuildloggerDownload(t *testing.T) {
 	})
 }
 
+func TestBuildloggerFollow(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "follow-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, db.Collection(buildloggerCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(configurationCollection).Drop(ctx))
+	}()
+
+	l := &Log{
+		ID:        "follow",
+		CreatedAt: time.Now(),
+		Artifact:  LogArtifactInfo{Type: PailLocal, Prefix: "follow", Version: 1},
+	}
+	_, err = db.Collection(buildloggerCollection).InsertOne(ctx, l)
+	require.NoError(t, err)
+
+	t.Run("NoEnv", func(t *testing.T) {
+		it, err := l.Follow(ctx, LogFollowOptions{})
+		assert.Error(t, err)
+		assert.Nil(t, it)
+	})
+	t.Run("NoConfig", func(t *testing.T) {
+		l.Setup(env)
+		it, err := l.Follow(ctx, LogFollowOptions{})
+		assert.Error(t, err)
+		assert.Nil(t, it)
+	})
+	conf := &CedarConfig{
+		populated: true,
+		Bucket:    BucketConfig{BuildLogsBucket: tmpDir},
+	}
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+	t.Run("InProgressLog", func(t *testing.T) {
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: l.Artifact.Prefix})
+		require.NoError(t, err)
+		_, lines, err := GenerateTestLog(ctx, bucket, 20, 10)
+		require.NoError(t, err)
+
+		l.Setup(env)
+		it, err := l.Follow(ctx, LogFollowOptions{PollInterval: 10 * time.Millisecond})
+		require.NoError(t, err)
+
+		var followed []LogLine
+		for len(followed) < len(lines) && it.Next(ctx) {
+			followed = append(followed, it.Item())
+		}
+		assert.Equal(t, lines, followed)
+
+		newLine := LogLine{Priority: level.Info, Timestamp: time.Now().Round(time.Millisecond).UTC(), Data: "new line"}
+		require.NoError(t, l.Append(ctx, []LogLine{newLine}))
+		_, err = db.Collection(buildloggerCollection).UpdateOne(ctx, bson.M{"_id": l.ID}, bson.M{"$set": bson.M{logCompletedAtKey: time.Now()}})
+		require.NoError(t, err)
+
+		for it.Next(ctx) {
+			followed = append(followed, it.Item())
+		}
+		assert.NoError(t, it.Err())
+		assert.True(t, it.Exhausted())
+		newLine.Data += "\n"
+		assert.Equal(t, append(lines, newLine), followed)
+		assert.NoError(t, it.Close())
+	})
+}
+
 func TestBuildloggerGetChunks(t *testing.T) {
 	env := cedar.GetEnvironment()
 	db := env.GetDB()
@@ -469,6 +641,123 @@ func TestBuildloggerGetChunks(t *testing.T) {
 	})
 }
 
+func TestBuildloggerRecompress(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "recompress-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, db.Collection(buildloggerCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(configurationCollection).Drop(ctx))
+	}()
+	conf := &CedarConfig{
+		populated: true,
+		Bucket:    BucketConfig{BuildLogsBucket: tmpDir},
+	}
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	log1, log2 := getTestLogs(time.Now())
+	incomplete := &Log{
+		ID:        "incomplete",
+		CreatedAt: time.Now(),
+		Artifact:  LogArtifactInfo{Type: PailLocal, Prefix: "incomplete", Version: 1},
+	}
+	for _, l := range []*Log{log1, log2, incomplete} {
+		_, err = db.Collection(buildloggerCollection).InsertOne(ctx, l)
+		require.NoError(t, err)
+	}
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir, Prefix: log1.Artifact.Prefix})
+	require.NoError(t, err)
+	_, lines, err := GenerateTestLog(ctx, bucket, 55, 10)
+	require.NoError(t, err)
+
+	t.Run("FindLogsToRecompress", func(t *testing.T) {
+		logs, err := FindLogsToRecompress(ctx, env, FindLogsToRecompressOptions{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, log1.ID, logs[0].ID)
+
+		logs, err = FindLogsToRecompress(ctx, env, FindLogsToRecompressOptions{ExcludeIDs: []string{log1.ID}})
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+	})
+	t.Run("FailedRecompressionAttempts", func(t *testing.T) {
+		l := *log1
+		l.Setup(env)
+		require.NoError(t, l.IncFailedRecompressionAttempts(ctx))
+		assert.Equal(t, 1, l.FailedRecompressionAttempts)
+
+		logs, err := FindLogsToRecompress(ctx, env, FindLogsToRecompressOptions{FailureLimit: 2})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, 1, logs[0].FailedRecompressionAttempts)
+
+		logs, err = FindLogsToRecompress(ctx, env, FindLogsToRecompressOptions{FailureLimit: 1})
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+
+		missing := &Log{ID: "DNE"}
+		missing.Setup(env)
+		assert.Error(t, missing.IncFailedRecompressionAttempts(ctx))
+	})
+	t.Run("NoEnv", func(t *testing.T) {
+		l := *log1
+		assert.Error(t, l.Recompress(ctx, LogCompressionZstd))
+	})
+	t.Run("InvalidCompression", func(t *testing.T) {
+		l := *log1
+		l.Setup(env)
+		assert.Error(t, l.Recompress(ctx, LogCompressionNone))
+		assert.Error(t, l.Recompress(ctx, "lz4"))
+	})
+	t.Run("InvalidArtifactVersion", func(t *testing.T) {
+		log2.Setup(env)
+		assert.Error(t, log2.Recompress(ctx, LogCompressionZstd))
+	})
+	t.Run("IncompleteLog", func(t *testing.T) {
+		incomplete.Setup(env)
+		assert.Error(t, incomplete.Recompress(ctx, LogCompressionZstd))
+	})
+	t.Run("Recompress", func(t *testing.T) {
+		log1.Setup(env)
+		require.NoError(t, log1.Recompress(ctx, LogCompressionZstd))
+		assert.Equal(t, 2, log1.Artifact.Version)
+		assert.Equal(t, LogCompressionZstd, log1.Artifact.Compression)
+
+		l := &Log{ID: log1.ID}
+		l.Setup(env)
+		require.NoError(t, l.Find(ctx))
+		assert.Equal(t, log1.Artifact, l.Artifact)
+
+		it, err := l.Download(ctx, TimeRange{EndAt: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		var count int
+		for it.Next(ctx) {
+			require.Equal(t, lines[count], it.Item())
+			count++
+		}
+		assert.NoError(t, it.Err())
+		assert.NoError(t, it.Close())
+		assert.Equal(t, len(lines), count)
+
+		bucketIt, err := bucket.List(ctx, "")
+		require.NoError(t, err)
+		for bucketIt.Next(ctx) {
+			assert.Equal(t, LogCompressionZstd.extension(), filepath.Ext(bucketIt.Item().Name()))
+		}
+		assert.NoError(t, bucketIt.Err())
+
+		logs, err := FindLogsToRecompress(ctx, env, FindLogsToRecompressOptions{Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+		assert.Error(t, log1.Recompress(ctx, LogCompressionZstd))
+	})
+}
+
 func TestBuildloggerClose(t *testing.T) {
 	env := cedar.GetEnvironment()
 	db := env.GetDB()
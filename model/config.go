@@ -2,6 +2,9 @@ package model
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"io"
 	"time"
 
 	"github.com/evergreen-ci/cedar"
@@ -37,6 +40,9 @@ type CedarConfig struct {
 	Flags          OperationalFlags          `bson:"flags" json:"flags" yaml:"flags"`
 	Service        ServiceConfig             `bson:"service" json:"service" yaml:"service"`
 	ChangeDetector ChangeDetectorConfig      `bson:"change_detector" json:"change_detector" yaml:"change_detector"`
+	Retention      RetentionConfig           `bson:"retention" json:"retention" yaml:"retention"`
+	LogSignatures  LogSignatureConfig        `bson:"log_signatures" json:"log_signatures" yaml:"log_signatures"`
+	Rollups        RollupConfig              `bson:"rollups" json:"rollups" yaml:"rollups"`
 
 	populated bool
 	env       cedar.Environment
@@ -65,6 +71,9 @@ var (
 	cedarConfigurationFlagsKey          = bsonutil.MustHaveTag(CedarConfig{}, "Flags")
 	cedarConfigurationServiceKey        = bsonutil.MustHaveTag(CedarConfig{}, "Service")
 	cedarConfigurationChangeDetectorKey = bsonutil.MustHaveTag(CedarConfig{}, "ChangeDetector")
+	cedarConfigurationRetentionKey      = bsonutil.MustHaveTag(CedarConfig{}, "Retention")
+	cedarConfigurationLogSignaturesKey  = bsonutil.MustHaveTag(CedarConfig{}, "LogSignatures")
+	cedarConfigurationRollupsKey        = bsonutil.MustHaveTag(CedarConfig{}, "Rollups")
 )
 
 type EvergreenConfig struct {
@@ -87,6 +96,10 @@ var (
 	cedarEvergreenConfigServiceUserAPIKey  = bsonutil.MustHaveTag(EvergreenConfig{}, "ServiceUserAPIKey")
 )
 
+// ChangeDetectorConfig describes the change point detection service used to
+// analyze performance result series. The signal processing service at the
+// URI is used by default, while the local implementation detects change
+// points in process.
 type ChangeDetectorConfig struct {
 	Implementation string `bson:"implementation" json:"implementation" yaml:"implementation"`
 	URI            string `bson:"uri" json:"uri" yaml:"uri"`
@@ -99,6 +112,221 @@ var (
 	cedarChangeDetectorConfigTokenKey = bsonutil.MustHaveTag(ChangeDetectorConfig{}, "Token")
 )
 
+// Change detector implementations.
+const (
+	ChangeDetectorImplementationSignalProcessing = "signal_processing"
+	ChangeDetectorImplementationLocal            = "local"
+)
+
+// IsLocal returns whether change points are detected in process.
+func (c ChangeDetectorConfig) IsLocal() bool {
+	return c.Implementation == ChangeDetectorImplementationLocal
+}
+
+// Validate ensures that the change detector config is valid.
+func (c ChangeDetectorConfig) Validate() error {
+	switch c.Implementation {
+	case "", ChangeDetectorImplementationSignalProcessing, ChangeDetectorImplementationLocal:
+		return nil
+	default:
+		return errors.Errorf("unrecognized change detector implementation '%s'", c.Implementation)
+	}
+}
+
+// RetentionConfig describes how long buildlogger logs, test results, system
+// metrics and performance results are kept before the retention job removes
+// them. Projects without their own policy use the default policy.
+type RetentionConfig struct {
+	Default  RetentionPolicy          `bson:"default" json:"default" yaml:"default"`
+	Projects []ProjectRetentionPolicy `bson:"projects" json:"projects" yaml:"projects"`
+}
+
+var (
+	cedarRetentionConfigDefaultKey  = bsonutil.MustHaveTag(RetentionConfig{}, "Default")
+	cedarRetentionConfigProjectsKey = bsonutil.MustHaveTag(RetentionConfig{}, "Projects")
+)
+
+// RetentionPolicy describes the number of days data is kept, by creation
+// time, for mainline and patch tasks. A value of 0 keeps the data forever.
+type RetentionPolicy struct {
+	MainlineDays int `bson:"mainline_days" json:"mainline_days" yaml:"mainline_days"`
+	PatchDays    int `bson:"patch_days" json:"patch_days" yaml:"patch_days"`
+}
+
+var (
+	cedarRetentionPolicyMainlineDaysKey = bsonutil.MustHaveTag(RetentionPolicy{}, "MainlineDays")
+	cedarRetentionPolicyPatchDaysKey    = bsonutil.MustHaveTag(RetentionPolicy{}, "PatchDays")
+)
+
+// IsZero returns whether the policy keeps all data forever.
+func (p RetentionPolicy) IsZero() bool { return p.MainlineDays <= 0 && p.PatchDays <= 0 }
+
+// Days returns the number of days mainline or patch data is kept.
+func (p RetentionPolicy) Days(mainline bool) int {
+	if mainline {
+		return p.MainlineDays
+	}
+	return p.PatchDays
+}
+
+// ProjectRetentionPolicy is the retention policy for a single project.
+type ProjectRetentionPolicy struct {
+	Project string          `bson:"project" json:"project" yaml:"project"`
+	Policy  RetentionPolicy `bson:"policy" json:"policy" yaml:"policy"`
+}
+
+var (
+	cedarProjectRetentionPolicyProjectKey = bsonutil.MustHaveTag(ProjectRetentionPolicy{}, "Project")
+	cedarProjectRetentionPolicyPolicyKey  = bsonutil.MustHaveTag(ProjectRetentionPolicy{}, "Policy")
+)
+
+// IsZero returns whether the retention config keeps all data forever.
+func (c RetentionConfig) IsZero() bool {
+	if !c.Default.IsZero() {
+		return false
+	}
+	for _, p := range c.Projects {
+		if !p.Policy.IsZero() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate ensures that the retention config is valid.
+func (c RetentionConfig) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(c.Default.MainlineDays < 0 || c.Default.PatchDays < 0, "default retention days cannot be negative")
+
+	seen := map[string]bool{}
+	for _, p := range c.Projects {
+		catcher.NewWhen(p.Project == "", "project retention policy must specify a project")
+		catcher.ErrorfWhen(seen[p.Project], "duplicate retention policy for project '%s'", p.Project)
+		catcher.ErrorfWhen(p.Policy.MainlineDays < 0 || p.Policy.PatchDays < 0, "retention days for project '%s' cannot be negative", p.Project)
+		seen[p.Project] = true
+	}
+
+	return catcher.Resolve()
+}
+
+// LogSignatureConfig describes the rules used to extract failure signatures
+// from the tail of buildlogger logs closed with a non-zero exit code. If no
+// rules are configured, the default rules are used.
+type LogSignatureConfig struct {
+	Rules     []LogSignatureRule `bson:"rules" json:"rules" yaml:"rules"`
+	TailLines int                `bson:"tail_lines" json:"tail_lines" yaml:"tail_lines"`
+	Disabled  bool               `bson:"disabled" json:"disabled" yaml:"disabled"`
+}
+
+var (
+	cedarLogSignatureConfigRulesKey     = bsonutil.MustHaveTag(LogSignatureConfig{}, "Rules")
+	cedarLogSignatureConfigTailLinesKey = bsonutil.MustHaveTag(LogSignatureConfig{}, "TailLines")
+	cedarLogSignatureConfigDisabledKey  = bsonutil.MustHaveTag(LogSignatureConfig{}, "Disabled")
+)
+
+// Validate ensures that the log signature config is valid.
+func (c LogSignatureConfig) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(c.TailLines < 0, "tail lines cannot be negative")
+
+	seen := map[string]bool{}
+	for _, rule := range c.Rules {
+		catcher.Wrapf(rule.Validate(), "invalid rule '%s'", rule.Name)
+		catcher.ErrorfWhen(seen[rule.Name], "duplicate rule '%s'", rule.Name)
+		seen[rule.Name] = true
+	}
+
+	return catcher.Resolve()
+}
+
+// RollupConfig describes the user-defined rollups calculated from the raw
+// events of performance results in addition to the default rollups.
+type RollupConfig struct {
+	Custom []CustomRollup `bson:"custom" json:"custom" yaml:"custom"`
+}
+
+var (
+	cedarRollupConfigCustomKey = bsonutil.MustHaveTag(RollupConfig{}, "Custom")
+)
+
+// Validate ensures that the rollup config is valid.
+func (c RollupConfig) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	seen := map[string]bool{}
+	for _, rollup := range c.Custom {
+		catcher.Wrapf(rollup.Validate(), "invalid custom rollup '%s'", rollup.Name)
+		catcher.ErrorfWhen(seen[rollup.Name], "duplicate custom rollup '%s'", rollup.Name)
+		seen[rollup.Name] = true
+	}
+
+	return catcher.Resolve()
+}
+
+// CustomRollup is a named rollup whose value is calculated by evaluating the
+// expression over the statistics of a performance result's raw events. If no
+// metric type is specified, the rollup is a mean.
+type CustomRollup struct {
+	Name       string     `bson:"name" json:"name" yaml:"name"`
+	Expression string     `bson:"expression" json:"expression" yaml:"expression"`
+	MetricType MetricType `bson:"metric_type" json:"metric_type" yaml:"metric_type"`
+}
+
+var (
+	customRollupNameKey       = bsonutil.MustHaveTag(CustomRollup{}, "Name")
+	customRollupExpressionKey = bsonutil.MustHaveTag(CustomRollup{}, "Expression")
+	customRollupMetricTypeKey = bsonutil.MustHaveTag(CustomRollup{}, "MetricType")
+)
+
+// Validate ensures that the custom rollup is valid.
+func (r CustomRollup) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(r.Name == "", "must specify a rollup name")
+	catcher.NewWhen(r.Expression == "", "must specify a rollup expression")
+	if r.MetricType != "" {
+		catcher.Add(validateCustomRollupMetricType(r.MetricType))
+	}
+
+	return catcher.Resolve()
+}
+
+// validateCustomRollupMetricType ensures that the metric type is valid for a
+// custom rollup, which, unlike other rollups, may also be a sum, 90th
+// percentile, throughput or latency.
+func validateCustomRollupMetricType(t MetricType) error {
+	switch t {
+	case MetricTypeSum, MetricTypePercentile90, MetricTypeThroughput, MetricTypeLatency:
+		return nil
+	default:
+		return t.Validate()
+	}
+}
+
+// Version returns the version of the custom rollup, which is derived from a
+// hash of its definition so that it changes, and existing rollup values are
+// recalculated, whenever the definition changes. Since the version is not
+// increasing, rollup values are outdated whenever their version differs.
+func (r CustomRollup) Version() int {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, r.Name)
+	_, _ = io.WriteString(hash, "\x00")
+	_, _ = io.WriteString(hash, r.Expression)
+	_, _ = io.WriteString(hash, "\x00")
+	_, _ = io.WriteString(hash, string(r.GetMetricType()))
+
+	return int(binary.BigEndian.Uint32(hash.Sum(nil)) >> 1)
+}
+
+// GetMetricType returns the metric type of the rollup values, defaulting to
+// mean.
+func (r CustomRollup) GetMetricType() MetricType {
+	if r.MetricType == "" {
+		return MetricTypeMean
+	}
+	return r.MetricType
+}
+
 type SlackConfig struct {
 	Options *send.SlackOptions `bson:"options" json:"options" yaml:"options"`
 	Token   string             `bson:"token" json:"token" yaml:"token"`
@@ -193,13 +421,14 @@ var (
 
 // Credentials and other configuration information for pail Bucket usage.
 type BucketConfig struct {
-	AWSKey                  string   `bson:"aws_key" json:"aws_key" yaml:"aws_key"`
-	AWSSecret               string   `bson:"aws_secret" json:"aws_secret" yaml:"aws_secret"`
-	BuildLogsBucket         string   `bson:"build_logs_bucket" json:"build_logs_bucket" yaml:"build_logs_bucket"`
-	SystemMetricsBucket     string   `bson:"system_metrics_bucket" json:"system_metrics_bucket" yaml:"system_metrics_bucket"`
-	SystemMetricsBucketType PailType `bson:"system_metrics_bucket_type" json:"system_metrics_bucket_type" yaml:"system_metrics_bucket_type"`
-	TestResultsBucket       string   `bson:"test_results_bucket" json:"test_results_bucket" yaml:"test_results_bucket"`
-	TestResultsBucketType   PailType `bson:"test_results_bucket_type" json:"test_results_bucket_type" yaml:"test_results_bucket_type"`
+	AWSKey                  string         `bson:"aws_key" json:"aws_key" yaml:"aws_key"`
+	AWSSecret               string         `bson:"aws_secret" json:"aws_secret" yaml:"aws_secret"`
+	BuildLogsBucket         string         `bson:"build_logs_bucket" json:"build_logs_bucket" yaml:"build_logs_bucket"`
+	BuildLogsCompression    LogCompression `bson:"build_logs_compression" json:"build_logs_compression" yaml:"build_logs_compression"`
+	SystemMetricsBucket     string         `bson:"system_metrics_bucket" json:"system_metrics_bucket" yaml:"system_metrics_bucket"`
+	SystemMetricsBucketType PailType       `bson:"system_metrics_bucket_type" json:"system_metrics_bucket_type" yaml:"system_metrics_bucket_type"`
+	TestResultsBucket       string         `bson:"test_results_bucket" json:"test_results_bucket" yaml:"test_results_bucket"`
+	TestResultsBucketType   PailType       `bson:"test_results_bucket_type" json:"test_results_bucket_type" yaml:"test_results_bucket_type"`
 
 	PrestoRoleARN           string `bson:"presto_role_arn" json:"presto_role_arn" yaml:"presto_role_arn"`
 	PrestoBucket            string `bson:"presto_bucket" json:"presto_bucket" yaml:"presto_bucket"`
@@ -212,6 +441,21 @@ var (
 	cedarS3BucketConfigBuildLogsBucketKey = bsonutil.MustHaveTag(BucketConfig{}, "BuildLogsBucket")
 )
 
+// isManaged returns whether the given bucket is one of the buckets managed by
+// Cedar.
+func (c BucketConfig) isManaged(bucket string) bool {
+	if bucket == "" {
+		return false
+	}
+	for _, managed := range []string{c.BuildLogsBucket, c.SystemMetricsBucket, c.TestResultsBucket, c.PrestoBucket} {
+		if bucket == managed {
+			return true
+		}
+	}
+
+	return false
+}
+
 type ServiceConfig struct {
 	AppServers  []string `bson:"app_servers" json:"app_servers" yaml:"app_servers"`
 	CORSOrigins []string `bson:"cors_origins" json:"cors_origins" yaml:"cors_origins"`
@@ -346,6 +590,9 @@ func (c *CedarConfig) Save() error {
 	if c.env == nil {
 		return errors.New("cannot save Cedar configuration with a nil environment")
 	}
+	if err := c.Rollups.Validate(); err != nil {
+		return errors.Wrap(err, "invalid rollup configuration")
+	}
 
 	ctx, cancel := c.env.Context()
 	defer cancel()
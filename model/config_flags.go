@@ -11,6 +11,7 @@ type OperationalFlags struct {
 	DisableInternalMetricsReporting bool `bson:"disable_internal_metrics_reporting" json:"disable_internal_metrics_reporting" yaml:"disable_internal_metrics_reporting"`
 	DisableSignalProcessing         bool `bson:"disable_signal_processing" json:"disable_signal_processing" yaml:"disable_signal_processing"`
 	DisableHistoricalTestData       bool `bson:"disable_historical_test_data" json:"disable_historical_test_data" yaml:"disable_historical_test_data"`
+	DisableTestDurationSlowdowns    bool `bson:"disable_test_duration_slowdowns" json:"disable_test_duration_slowdowns" yaml:"disable_test_duration_slowdowns"`
 
 	env cedar.Environment
 }
@@ -18,6 +19,7 @@ type OperationalFlags struct {
 var (
 	opsFlagsDisableInternalMetricsReporting = bsonutil.MustHaveTag(OperationalFlags{}, "DisableInternalMetricsReporting")
 	opsFlagsDisableSignalProcessing         = bsonutil.MustHaveTag(OperationalFlags{}, "DisableSignalProcessing")
+	opsFlagsDisableTestDurationSlowdowns    = bsonutil.MustHaveTag(OperationalFlags{}, "DisableTestDurationSlowdowns")
 )
 
 func (f *OperationalFlags) findAndSet(name string, v bool) error {
@@ -26,6 +28,8 @@ func (f *OperationalFlags) findAndSet(name string, v bool) error {
 		return f.SetDisableInternalMetricsReporting(v)
 	case "disable_signal_processing":
 		return f.SetDisableSignalProcessing(v)
+	case "disable_test_duration_slowdowns":
+		return f.SetDisableTestDurationSlowdowns(v)
 	default:
 		return errors.Errorf("%s is not a known feature flag name", name)
 	}
@@ -57,6 +61,14 @@ func (f *OperationalFlags) SetDisableSignalProcessing(v bool) error {
 
 }
 
+func (f *OperationalFlags) SetDisableTestDurationSlowdowns(v bool) error {
+	if err := f.update(opsFlagsDisableTestDurationSlowdowns, v); err != nil {
+		return errors.WithStack(err)
+	}
+	f.DisableTestDurationSlowdowns = v
+	return nil
+}
+
 func (f *OperationalFlags) update(key string, value bool) error {
 	conf, session, err := cedar.GetSessionWithConfig(f.env)
 	if err != nil {
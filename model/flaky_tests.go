@@ -0,0 +1,417 @@
+package model
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const flakyTestsCollection = "flaky_tests"
+
+// FlakyTestData describes the flakiness of a test for a given day. A test
+// flakes when it both passes and fails in the same task execution, across
+// trials, or when its status changes between retried executions of the same
+// task in a version. The score is the ratio of flakes to the total number of
+// runs of the test recorded in the historical test data for the same day.
+type FlakyTestData struct {
+	ID         string                 `bson:"_id"`
+	Info       HistoricalTestDataInfo `bson:"info"`
+	NumFlaky   int                    `bson:"num_flaky"`
+	NumRuns    int                    `bson:"num_runs"`
+	Score      float64                `bson:"score"`
+	LastUpdate time.Time              `bson:"last_update"`
+
+	env       cedar.Environment
+	populated bool
+}
+
+var (
+	flakyTestDataIDKey         = bsonutil.MustHaveTag(FlakyTestData{}, "ID")
+	flakyTestDataInfoKey       = bsonutil.MustHaveTag(FlakyTestData{}, "Info")
+	flakyTestDataNumFlakyKey   = bsonutil.MustHaveTag(FlakyTestData{}, "NumFlaky")
+	flakyTestDataNumRunsKey    = bsonutil.MustHaveTag(FlakyTestData{}, "NumRuns")
+	flakyTestDataScoreKey      = bsonutil.MustHaveTag(FlakyTestData{}, "Score")
+	flakyTestDataLastUpdateKey = bsonutil.MustHaveTag(FlakyTestData{}, "LastUpdate")
+)
+
+// CreateFlakyTestData is an entry point for creating a new FlakyTestData.
+func CreateFlakyTestData(info HistoricalTestDataInfo) (*FlakyTestData, error) {
+	if err := info.validate(); err != nil {
+		return nil, err
+	}
+
+	info.Date = utility.GetUTCDay(info.Date)
+
+	return &FlakyTestData{
+		ID:        info.ID(),
+		Info:      info,
+		populated: true,
+	}, nil
+}
+
+// Setup sets the environment. The environment is required for numerous
+// functions on FlakyTestData.
+func (d *FlakyTestData) Setup(e cedar.Environment) { d.env = e }
+
+// IsNil returns if the FlakyTestData is populated or not.
+func (d *FlakyTestData) IsNil() bool { return !d.populated }
+
+// Find searches the DB for the FlakyTestData by ID. The environment should
+// not be nil.
+func (d *FlakyTestData) Find(ctx context.Context) error {
+	if d.env == nil {
+		return errors.New("cannot find with a nil environment")
+	}
+
+	if d.ID == "" {
+		d.ID = d.Info.ID()
+	}
+
+	d.populated = false
+	if err := d.env.GetDB().Collection(flakyTestsCollection).FindOne(ctx, bson.M{"_id": d.ID}).Decode(d); err != nil {
+		return errors.Wrapf(err, "finding flaky test data record '%s'", d.ID)
+	}
+	d.populated = true
+
+	return nil
+}
+
+// AddFlake records a new flake of the test and recomputes its score using
+// the given number of runs of the test, typically the sum of the passes and
+// failures in the historical test data for the same day. The number of runs
+// never drops below the number of flakes. If the FlakyTestData does not
+// exist, it is created. The FlakyTestData should be populated and the
+// environment should not be nil.
+func (d *FlakyTestData) AddFlake(ctx context.Context, numRuns int) error {
+	if !d.populated {
+		return errors.New("cannot update unpopulated flaky test data")
+	}
+	if d.env == nil {
+		return errors.New("cannot update with a nil environment")
+	}
+
+	if d.ID == "" {
+		d.ID = d.Info.ID()
+	}
+
+	d.populated = false
+
+	query := bson.M{
+		flakyTestDataIDKey:   d.ID,
+		flakyTestDataInfoKey: d.Info,
+	}
+	pipeline := []bson.M{
+		{"$set": bson.M{
+			flakyTestDataLastUpdateKey: "$$NOW",
+			flakyTestDataNumFlakyKey: bson.M{"$add": []interface{}{
+				bson.M{"$ifNull": []interface{}{"$" + flakyTestDataNumFlakyKey, 0}},
+				1,
+			}},
+		}},
+		{"$set": bson.M{
+			flakyTestDataNumRunsKey: bson.M{"$max": []interface{}{
+				"$" + flakyTestDataNumFlakyKey,
+				"$" + flakyTestDataNumRunsKey,
+				numRuns,
+			}},
+		}},
+		{"$set": bson.M{
+			flakyTestDataScoreKey: bson.M{"$divide": []interface{}{
+				"$" + flakyTestDataNumFlakyKey,
+				"$" + flakyTestDataNumRunsKey,
+			}},
+		}},
+	}
+
+	err := d.env.GetDB().Collection(flakyTestsCollection).FindOneAndUpdate(
+		ctx,
+		query,
+		pipeline,
+		options.FindOneAndUpdate().SetUpsert(true),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+		options.FindOneAndUpdate().SetMaxTime(time.Minute),
+	).Decode(d)
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection": flakyTestsCollection,
+		"id":         d.ID,
+		"op":         "add flake to flaky test data record",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "updating flaky test data '%s'", d.ID)
+	}
+
+	d.populated = true
+
+	return nil
+}
+
+// FindFlakyTests returns the latest trial of each test in the TestResults
+// that flaked. A test flaked if its trials in this execution both passed and
+// failed, or if it passed in this execution and failed in the previous
+// execution of the same task and version, or vice versa. The TestResults
+// should be populated and the environment should not be nil.
+func (t *TestResults) FindFlakyTests(ctx context.Context) ([]TestResult, error) {
+	if !t.populated {
+		return nil, errors.New("cannot find flaky tests for unpopulated test results")
+	}
+	if t.env == nil {
+		return nil, errors.New("cannot find flaky tests with a nil environment")
+	}
+
+	current, err := t.Download(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "downloading test results for '%s'", t.ID)
+	}
+	if t.Info.Execution == 0 {
+		return findFlakyTests(current, nil), nil
+	}
+
+	cur, err := t.env.GetDB().Collection(testResultsCollection).Find(ctx, bson.M{
+		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskIDKey):    t.Info.TaskID,
+		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoExecutionKey): t.Info.Execution - 1,
+		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVersionKey):   t.Info.Version,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "finding test results of the previous execution")
+	}
+	var records []TestResults
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, errors.Wrap(err, "decoding test results of the previous execution")
+	}
+
+	var previous []TestResult
+	for i := range records {
+		records[i].Setup(t.env)
+		records[i].populated = true
+		results, err := records[i].Download(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "downloading test results for '%s'", records[i].ID)
+		}
+		previous = append(previous, results...)
+	}
+
+	return findFlakyTests(current, previous), nil
+}
+
+// testOutcome summarizes the outcome of the trials of a test in a single task
+// execution.
+type testOutcome struct {
+	latest TestResult
+	passed bool
+	failed bool
+}
+
+func (o testOutcome) flaked() bool { return o.passed && o.failed }
+
+// summarizeTestOutcomes groups the given results by display test name.
+func summarizeTestOutcomes(results []TestResult) map[string]testOutcome {
+	outcomes := map[string]testOutcome{}
+	for _, result := range results {
+		name := result.GetDisplayName()
+		outcome, ok := outcomes[name]
+		if !ok || result.Trial >= outcome.latest.Trial {
+			outcome.latest = result
+		}
+		switch result.Status {
+		case "pass":
+			outcome.passed = true
+		case "fail", "silentfail":
+			outcome.failed = true
+		}
+		outcomes[name] = outcome
+	}
+
+	return outcomes
+}
+
+// findFlakyTests returns the latest trial of each test in the current results
+// that flaked, either across its trials or relative to the previous
+// execution's results. The returned results are sorted by display test name.
+func findFlakyTests(current, previous []TestResult) []TestResult {
+	currentOutcomes := summarizeTestOutcomes(current)
+	previousOutcomes := summarizeTestOutcomes(previous)
+
+	var flaky []TestResult
+	for name, outcome := range currentOutcomes {
+		prev, ok := previousOutcomes[name]
+		if !outcome.flaked() && !(ok && isFlip(prev.latest.Status, outcome.latest.Status)) {
+			continue
+		}
+		flaky = append(flaky, outcome.latest)
+	}
+	sort.Slice(flaky, func(i, j int) bool {
+		return flaky[i].GetDisplayName() < flaky[j].GetDisplayName()
+	})
+
+	return flaky
+}
+
+// isFlip returns whether the two test statuses are a pass and a failure.
+func isFlip(a, b string) bool {
+	isPass := func(s string) bool { return s == "pass" }
+	isFail := func(s string) bool { return s == "fail" || s == "silentfail" }
+
+	return (isPass(a) && isFail(b)) || (isFail(a) && isPass(b))
+}
+
+///////////////////
+// Find aggregation
+///////////////////
+
+const flakyTestsMaxQueryLimit = 1000
+
+// AggregatedFlakyTest represents the flakiness of a test over a date range,
+// along with the daily trend.
+type AggregatedFlakyTest struct {
+	TestName string                `bson:"test_name"`
+	TaskName string                `bson:"task_name"`
+	Variant  string                `bson:"variant"`
+	NumFlaky int                   `bson:"num_flaky"`
+	NumRuns  int                   `bson:"num_runs"`
+	Score    float64               `bson:"score"`
+	Trend    []FlakyTestTrendPoint `bson:"trend"`
+}
+
+// FlakyTestTrendPoint represents the flakiness of a test on a single day.
+type FlakyTestTrendPoint struct {
+	Date     time.Time `bson:"date"`
+	NumFlaky int       `bson:"num_flaky"`
+	NumRuns  int       `bson:"num_runs"`
+	Score    float64   `bson:"score"`
+}
+
+var (
+	aggregatedFlakyTestTestNameKey = bsonutil.MustHaveTag(AggregatedFlakyTest{}, "TestName")
+	aggregatedFlakyTestTaskNameKey = bsonutil.MustHaveTag(AggregatedFlakyTest{}, "TaskName")
+	aggregatedFlakyTestVariantKey  = bsonutil.MustHaveTag(AggregatedFlakyTest{}, "Variant")
+	aggregatedFlakyTestNumFlakyKey = bsonutil.MustHaveTag(AggregatedFlakyTest{}, "NumFlaky")
+	aggregatedFlakyTestNumRunsKey  = bsonutil.MustHaveTag(AggregatedFlakyTest{}, "NumRuns")
+	aggregatedFlakyTestScoreKey    = bsonutil.MustHaveTag(AggregatedFlakyTest{}, "Score")
+	aggregatedFlakyTestTrendKey    = bsonutil.MustHaveTag(AggregatedFlakyTest{}, "Trend")
+
+	flakyTestTrendPointDateKey     = bsonutil.MustHaveTag(FlakyTestTrendPoint{}, "Date")
+	flakyTestTrendPointNumFlakyKey = bsonutil.MustHaveTag(FlakyTestTrendPoint{}, "NumFlaky")
+	flakyTestTrendPointNumRunsKey  = bsonutil.MustHaveTag(FlakyTestTrendPoint{}, "NumRuns")
+	flakyTestTrendPointScoreKey    = bsonutil.MustHaveTag(FlakyTestTrendPoint{}, "Score")
+)
+
+// FlakyTestsFilter represents search parameters when querying the flaky
+// tests of a project.
+type FlakyTestsFilter struct {
+	Project    string
+	Requesters []string
+	AfterDate  time.Time
+	BeforeDate time.Time
+
+	Tasks    []string
+	Variants []string
+
+	Limit int
+}
+
+// Validate ensures that the FlakyTestsFilter is valid.
+func (f *FlakyTestsFilter) Validate() error {
+	if f == nil {
+		return errors.New("flaky tests filter should not be nil")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(f.Project == "", "missing Project value")
+	catcher.NewWhen(len(f.Requesters) == 0, "missing Requesters values")
+	catcher.NewWhen(f.Limit > flakyTestsMaxQueryLimit || f.Limit <= 0, "invalid Limit value")
+	catcher.NewWhen(!f.AfterDate.Equal(utility.GetUTCDay(f.AfterDate)), "invalid AfterDate value")
+	catcher.NewWhen(!f.BeforeDate.Equal(utility.GetUTCDay(f.BeforeDate)), "invalid BeforeDate value")
+	catcher.NewWhen(!f.BeforeDate.After(f.AfterDate), "invalid AfterDate/BeforeDate values")
+	return catcher.Resolve()
+}
+
+// GetFlakyTests returns the flaky tests matching the filter, ranked by score
+// and then by number of flakes, with the daily trend of each test in
+// chronological order.
+func GetFlakyTests(ctx context.Context, env cedar.Environment, filter FlakyTestsFilter) ([]AggregatedFlakyTest, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, errors.Wrap(err, "the provided FlakyTestsFilter is invalid")
+	}
+
+	var flaky []AggregatedFlakyTest
+	cursor, err := env.GetDB().Collection(flakyTestsCollection).Aggregate(ctx, filter.queryPipeline())
+	if err != nil {
+		return nil, errors.Wrap(err, "aggregating flaky tests")
+	}
+	if err = cursor.All(ctx, &flaky); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling aggregated flaky tests")
+	}
+
+	return flaky, nil
+}
+
+// queryPipeline creates an aggregation pipeline to query flaky tests.
+func (f FlakyTestsFilter) queryPipeline() []bson.M {
+	infoKey := func(key string) string {
+		return "$" + bsonutil.GetDottedKeyName(flakyTestDataInfoKey, key)
+	}
+	match := bson.M{
+		bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoDateKey): bson.M{
+			"$gte": f.AfterDate,
+			"$lt":  f.BeforeDate,
+		},
+		bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoProjectKey):     f.Project,
+		bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoRequestTypeKey): bson.M{"$in": f.Requesters},
+	}
+	if len(f.Tasks) > 0 {
+		match[bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoTaskNameKey)] = bson.M{"$in": f.Tasks}
+	}
+	if len(f.Variants) > 0 {
+		match[bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoVariantKey)] = bson.M{"$in": f.Variants}
+	}
+
+	return []bson.M{
+		{"$match": match},
+		{"$sort": bson.D{{Key: bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoDateKey), Value: 1}}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				aggregatedFlakyTestVariantKey:  infoKey(historicalTestDataInfoVariantKey),
+				aggregatedFlakyTestTaskNameKey: infoKey(historicalTestDataInfoTaskNameKey),
+				aggregatedFlakyTestTestNameKey: infoKey(historicalTestDataInfoTestNameKey),
+			},
+			aggregatedFlakyTestNumFlakyKey: bson.M{"$sum": "$" + flakyTestDataNumFlakyKey},
+			aggregatedFlakyTestNumRunsKey:  bson.M{"$sum": "$" + flakyTestDataNumRunsKey},
+			aggregatedFlakyTestTrendKey: bson.M{"$push": bson.M{
+				flakyTestTrendPointDateKey:     infoKey(historicalTestDataInfoDateKey),
+				flakyTestTrendPointNumFlakyKey: "$" + flakyTestDataNumFlakyKey,
+				flakyTestTrendPointNumRunsKey:  "$" + flakyTestDataNumRunsKey,
+				flakyTestTrendPointScoreKey:    "$" + flakyTestDataScoreKey,
+			}},
+		}},
+		{"$project": bson.M{
+			"_id":                          0,
+			aggregatedFlakyTestTestNameKey: "$_id." + aggregatedFlakyTestTestNameKey,
+			aggregatedFlakyTestTaskNameKey: "$_id." + aggregatedFlakyTestTaskNameKey,
+			aggregatedFlakyTestVariantKey:  "$_id." + aggregatedFlakyTestVariantKey,
+			aggregatedFlakyTestNumFlakyKey: 1,
+			aggregatedFlakyTestNumRunsKey:  1,
+			aggregatedFlakyTestTrendKey:    1,
+			aggregatedFlakyTestScoreKey: bson.M{"$divide": []interface{}{
+				"$" + aggregatedFlakyTestNumFlakyKey,
+				"$" + aggregatedFlakyTestNumRunsKey,
+			}},
+		}},
+		{"$sort": bson.D{
+			{Key: aggregatedFlakyTestScoreKey, Value: -1},
+			{Key: aggregatedFlakyTestNumFlakyKey, Value: -1},
+			{Key: aggregatedFlakyTestVariantKey, Value: 1},
+			{Key: aggregatedFlakyTestTaskNameKey, Value: 1},
+			{Key: aggregatedFlakyTestTestNameKey, Value: 1},
+		}},
+		{"$limit": f.Limit},
+	}
+}
@@ -0,0 +1,306 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCreateFlakyTestData(t *testing.T) {
+	info := HistoricalTestDataInfo{
+		Project:     "project",
+		Variant:     "variant",
+		TaskName:    "task_name",
+		TestName:    "test_name",
+		RequestType: "request_type",
+		Date:        time.Now(),
+	}
+
+	t.Run("InvalidInfo", func(t *testing.T) {
+		invalid := info
+		invalid.TestName = ""
+		data, err := CreateFlakyTestData(invalid)
+		assert.Nil(t, data)
+		assert.Error(t, err)
+	})
+	t.Run("ValidInfo", func(t *testing.T) {
+		data, err := CreateFlakyTestData(info)
+		require.NoError(t, err)
+		require.NotNil(t, data)
+		assert.Equal(t, utility.GetUTCDay(info.Date), data.Info.Date)
+		assert.Equal(t, data.Info.ID(), data.ID)
+		assert.True(t, data.populated)
+	})
+}
+
+func TestFlakyTestDataAddFlake(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(flakyTestsCollection).Drop(ctx))
+	}()
+	info := getHistoricalTestData(t).Info
+
+	t.Run("NoEnv", func(t *testing.T) {
+		data, err := CreateFlakyTestData(info)
+		require.NoError(t, err)
+
+		assert.Error(t, data.AddFlake(ctx, 1))
+	})
+	t.Run("Unpopulated", func(t *testing.T) {
+		data, err := CreateFlakyTestData(info)
+		require.NoError(t, err)
+		data.Setup(env)
+		data.populated = false
+
+		assert.Error(t, data.AddFlake(ctx, 1))
+	})
+	t.Run("UpsertAndUpdate", func(t *testing.T) {
+		data, err := CreateFlakyTestData(info)
+		require.NoError(t, err)
+		data.Setup(env)
+
+		require.NoError(t, data.AddFlake(ctx, 4))
+		actual := &FlakyTestData{}
+		require.NoError(t, db.Collection(flakyTestsCollection).FindOne(ctx, bson.M{"_id": data.ID}).Decode(actual))
+		assert.Equal(t, 1, actual.NumFlaky)
+		assert.Equal(t, 4, actual.NumRuns)
+		assert.Equal(t, 0.25, actual.Score)
+		assert.True(t, time.Since(actual.LastUpdate) <= time.Second)
+
+		require.NoError(t, data.AddFlake(ctx, 8))
+		actual = &FlakyTestData{}
+		require.NoError(t, db.Collection(flakyTestsCollection).FindOne(ctx, bson.M{"_id": data.ID}).Decode(actual))
+		assert.Equal(t, 2, actual.NumFlaky)
+		assert.Equal(t, 8, actual.NumRuns)
+		assert.Equal(t, 0.25, actual.Score)
+
+		// The number of runs never drops below the number of flakes.
+		require.NoError(t, data.AddFlake(ctx, 0))
+		require.NoError(t, data.AddFlake(ctx, 0))
+		require.NoError(t, data.AddFlake(ctx, 0))
+		require.NoError(t, data.AddFlake(ctx, 0))
+		require.NoError(t, data.AddFlake(ctx, 0))
+		require.NoError(t, data.AddFlake(ctx, 0))
+		require.NoError(t, data.AddFlake(ctx, 0))
+		actual = &FlakyTestData{}
+		require.NoError(t, db.Collection(flakyTestsCollection).FindOne(ctx, bson.M{"_id": data.ID}).Decode(actual))
+		assert.Equal(t, 9, actual.NumFlaky)
+		assert.Equal(t, 9, actual.NumRuns)
+		assert.Equal(t, 1.0, actual.Score)
+	})
+}
+
+func TestFindFlakyTests(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		current  []TestResult
+		previous []TestResult
+		expected []TestResult
+	}{
+		{
+			name: "NoFlakes",
+			current: []TestResult{
+				{TestName: "test0", Status: "pass"},
+				{TestName: "test1", Status: "fail"},
+			},
+		},
+		{
+			name: "FlakyTrials",
+			current: []TestResult{
+				{TestName: "test0", Trial: 0, Status: "fail"},
+				{TestName: "test0", Trial: 1, Status: "pass"},
+				{TestName: "test1", Trial: 0, Status: "fail"},
+				{TestName: "test1", Trial: 1, Status: "fail"},
+			},
+			expected: []TestResult{{TestName: "test0", Trial: 1, Status: "pass"}},
+		},
+		{
+			name: "FlakyAcrossExecutions",
+			current: []TestResult{
+				{TestName: "test0", Execution: 1, Status: "pass"},
+				{TestName: "test1", Execution: 1, Status: "silentfail"},
+				{TestName: "test2", Execution: 1, Status: "pass"},
+				{TestName: "test3", Execution: 1, Status: "skip"},
+			},
+			previous: []TestResult{
+				{TestName: "test0", Execution: 0, Status: "fail"},
+				{TestName: "test1", Execution: 0, Status: "pass"},
+				{TestName: "test2", Execution: 0, Status: "pass"},
+				{TestName: "test3", Execution: 0, Status: "fail"},
+			},
+			expected: []TestResult{
+				{TestName: "test0", Execution: 1, Status: "pass"},
+				{TestName: "test1", Execution: 1, Status: "silentfail"},
+			},
+		},
+		{
+			name: "DisplayTestName",
+			current: []TestResult{
+				{TestName: "test0", DisplayTestName: "display0", Trial: 0, Status: "pass"},
+				{TestName: "test0", DisplayTestName: "display0", Trial: 1, Status: "fail"},
+			},
+			expected: []TestResult{{TestName: "test0", DisplayTestName: "display0", Trial: 1, Status: "fail"}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, findFlakyTests(test.current, test.previous))
+		})
+	}
+}
+
+func TestFlakyTestsFilterValidate(t *testing.T) {
+	day := utility.GetUTCDay(time.Now())
+	valid := FlakyTestsFilter{
+		Project:    "project",
+		Requesters: []string{"requester"},
+		AfterDate:  day.AddDate(0, 0, -7),
+		BeforeDate: day,
+		Limit:      10,
+	}
+	assert.NoError(t, valid.Validate())
+
+	for _, test := range []struct {
+		name   string
+		modify func(*FlakyTestsFilter)
+	}{
+		{name: "MissingProject", modify: func(f *FlakyTestsFilter) { f.Project = "" }},
+		{name: "MissingRequesters", modify: func(f *FlakyTestsFilter) { f.Requesters = nil }},
+		{name: "ZeroLimit", modify: func(f *FlakyTestsFilter) { f.Limit = 0 }},
+		{name: "LimitTooLarge", modify: func(f *FlakyTestsFilter) { f.Limit = flakyTestsMaxQueryLimit + 1 }},
+		{name: "AfterDateNotDay", modify: func(f *FlakyTestsFilter) { f.AfterDate = f.AfterDate.Add(time.Hour) }},
+		{name: "BeforeDateNotAfterAfterDate", modify: func(f *FlakyTestsFilter) { f.BeforeDate = f.AfterDate }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			filter := valid
+			test.modify(&filter)
+			assert.Error(t, filter.Validate())
+		})
+	}
+}
+
+func TestGetFlakyTests(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(flakyTestsCollection).Drop(ctx))
+	}()
+
+	day := utility.GetUTCDay(time.Now())
+	for _, data := range []FlakyTestData{
+		getFlakyTestData("variant0", "task0", "test0", "mainline", day.AddDate(0, 0, -2), 1, 10),
+		getFlakyTestData("variant0", "task0", "test0", "mainline", day, 3, 10),
+		getFlakyTestData("variant0", "task0", "test1", "mainline", day, 1, 2),
+		getFlakyTestData("variant1", "task1", "test0", "mainline", day, 1, 20),
+		getFlakyTestData("variant1", "task1", "test2", "patch", day, 5, 5),
+		getFlakyTestData("variant1", "task1", "test3", "mainline", day.AddDate(0, 0, -30), 5, 5),
+	} {
+		_, err := db.Collection(flakyTestsCollection).InsertOne(ctx, data)
+		require.NoError(t, err)
+	}
+	baseFilter := func() FlakyTestsFilter {
+		return FlakyTestsFilter{
+			Project:    "project",
+			Requesters: []string{"mainline"},
+			AfterDate:  day.AddDate(0, 0, -7),
+			BeforeDate: day.AddDate(0, 0, 1),
+			Limit:      10,
+		}
+	}
+
+	t.Run("InvalidFilter", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Limit = 0
+		_, err := GetFlakyTests(ctx, env, filter)
+		assert.Error(t, err)
+	})
+	t.Run("RankedWithTrend", func(t *testing.T) {
+		flaky, err := GetFlakyTests(ctx, env, baseFilter())
+		require.NoError(t, err)
+		require.Len(t, flaky, 3)
+
+		assert.Equal(t, "test1", flaky[0].TestName)
+		assert.Equal(t, 0.5, flaky[0].Score)
+
+		assert.Equal(t, "variant0", flaky[1].Variant)
+		assert.Equal(t, "task0", flaky[1].TaskName)
+		assert.Equal(t, "test0", flaky[1].TestName)
+		assert.Equal(t, 4, flaky[1].NumFlaky)
+		assert.Equal(t, 20, flaky[1].NumRuns)
+		assert.Equal(t, 0.2, flaky[1].Score)
+		require.Len(t, flaky[1].Trend, 2)
+		assert.True(t, day.AddDate(0, 0, -2).Equal(flaky[1].Trend[0].Date))
+		assert.Equal(t, 1, flaky[1].Trend[0].NumFlaky)
+		assert.Equal(t, 0.1, flaky[1].Trend[0].Score)
+		assert.True(t, day.Equal(flaky[1].Trend[1].Date))
+		assert.Equal(t, 3, flaky[1].Trend[1].NumFlaky)
+		assert.Equal(t, 0.3, flaky[1].Trend[1].Score)
+
+		assert.Equal(t, "variant1", flaky[2].Variant)
+		assert.Equal(t, 0.05, flaky[2].Score)
+	})
+	t.Run("Variants", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Variants = []string{"variant1"}
+		flaky, err := GetFlakyTests(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, flaky, 1)
+		assert.Equal(t, "variant1", flaky[0].Variant)
+	})
+	t.Run("Tasks", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Tasks = []string{"task0"}
+		flaky, err := GetFlakyTests(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, flaky, 2)
+		for _, ft := range flaky {
+			assert.Equal(t, "task0", ft.TaskName)
+		}
+	})
+	t.Run("Requesters", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Requesters = []string{"patch"}
+		flaky, err := GetFlakyTests(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, flaky, 1)
+		assert.Equal(t, "test2", flaky[0].TestName)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Limit = 1
+		flaky, err := GetFlakyTests(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, flaky, 1)
+		assert.Equal(t, "test1", flaky[0].TestName)
+	})
+}
+
+func getFlakyTestData(variant, taskName, testName, requestType string, date time.Time, numFlaky, numRuns int) FlakyTestData {
+	info := HistoricalTestDataInfo{
+		Project:     "project",
+		Variant:     variant,
+		TaskName:    taskName,
+		TestName:    testName,
+		RequestType: requestType,
+		Date:        date,
+	}
+
+	return FlakyTestData{
+		ID:         info.ID(),
+		Info:       info,
+		NumFlaky:   numFlaky,
+		NumRuns:    numRuns,
+		Score:      float64(numFlaky) / float64(numRuns),
+		LastUpdate: time.Now(),
+	}
+}
@@ -84,6 +84,16 @@ func GetRequiredIndexes() []SystemIndexes {
 			},
 			Collection: perfResultCollection,
 		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoVariantKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTaskNameKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTestNameKey), Value: 1},
+				{Key: perfCreatedAtKey, Value: 1},
+			},
+			Collection: perfResultCollection,
+		},
 		{
 			Keys: bson.D{
 				{Key: bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoProjectKey), Value: 1},
@@ -164,6 +174,49 @@ func GetRequiredIndexes() []SystemIndexes {
 			},
 			Collection: buildloggerCollection,
 		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(logInfoKey, logInfoProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(logInfoKey, logInfoVersionKey), Value: 1},
+				{Key: logCreatedAtKey, Value: -1},
+			},
+			Collection: buildloggerCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(logInfoKey, logInfoProjectKey), Value: 1},
+				{Key: logCreatedAtKey, Value: -1},
+			},
+			Collection: buildloggerCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(logArtifactKey, logArtifactInfoVersionKey), Value: 1},
+				{Key: logCompletedAtKey, Value: 1},
+			},
+			Collection: buildloggerCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(logInfoKey, logInfoMainlineKey), Value: 1},
+				{Key: logCreatedAtKey, Value: 1},
+			},
+			Collection: buildloggerCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(systemMetricsInfoKey, systemMetricsInfoMainlineKey), Value: 1},
+				{Key: systemMetricsCreatedAtKey, Value: 1},
+			},
+			Collection: systemMetricsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoMainlineKey), Value: 1},
+				{Key: perfCreatedAtKey, Value: 1},
+			},
+			Collection: perfResultCollection,
+		},
 		{
 			Keys: bson.D{
 				{Key: bsonutil.GetDottedKeyName(systemMetricsInfoKey, systemMetricsInfoTaskIDKey), Value: 1},
@@ -249,6 +302,105 @@ func GetRequiredIndexes() []SystemIndexes {
 			Options:    bson.D{{Key: "expireAfterSeconds", Value: 15552000}},
 			Collection: historicalTestDataCollection,
 		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoRequestTypeKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoDateKey), Value: 1},
+			},
+			Collection: flakyTestsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(flakyTestDataInfoKey, historicalTestDataInfoDateKey), Value: 1},
+			},
+			Options:    bson.D{{Key: "expireAfterSeconds", Value: 15552000}},
+			Collection: flakyTestsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoMainlineKey), Value: 1},
+				{Key: testResultsCreatedAtKey, Value: 1},
+			},
+			Collection: testResultsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVariantKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskNameKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoMainlineKey), Value: 1},
+				{Key: testResultsCreatedAtKey, Value: 1},
+			},
+			Collection: testResultsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVersionKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVariantKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskNameKey), Value: 1},
+			},
+			Collection: testResultsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey), Value: 1},
+				{Key: testResultsCreatedAtKey, Value: 1},
+			},
+			Collection: testResultsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoDateKey), Value: 1},
+			},
+			Collection: testDurationSlowdownsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoDateKey), Value: 1},
+			},
+			Options:    bson.D{{Key: "expireAfterSeconds", Value: 15552000}},
+			Collection: testDurationSlowdownsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: testOwnershipProjectKey, Value: 1},
+				{Key: testOwnershipTestNamePatternKey, Value: 1},
+			},
+			Collection: testOwnershipCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: testAnnotationProjectKey, Value: 1},
+				{Key: testAnnotationTestNameKey, Value: 1},
+			},
+			Collection: testAnnotationsCollection,
+		},
+		{
+			Keys:       bson.D{{Key: testAnnotationExpiresAtKey, Value: 1}},
+			Options:    bson.D{{Key: "expireAfterSeconds", Value: 0}},
+			Collection: testAnnotationsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, perfResultSeriesIDProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, perfResultSeriesIDVariantKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, perfResultSeriesIDTaskKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, perfResultSeriesIDTestKey), Value: 1},
+				{Key: perfChangePointOrderKey, Value: 1},
+			},
+			Collection: perfChangePointsCollection,
+		},
+		{
+			Keys: bson.D{
+				{Key: bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, perfResultSeriesIDProjectKey), Value: 1},
+				{Key: bsonutil.GetDottedKeyName(perfChangePointTriageKey, perfChangePointTriageStatusKey), Value: 1},
+				{Key: perfChangePointDetectedAtKey, Value: -1},
+			},
+			Collection: perfChangePointsCollection,
+		},
 		{
 			Keys:       bson.D{{Key: dbUserAPIKeyKey, Value: 1}},
 			Collection: userCollection,
@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"container/heap"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -94,7 +97,7 @@ func (i *serializedIterator) Next(ctx context.Context) bool {
 			}
 
 			var err error
-			i.currentReadCloser, err = i.bucket.Get(ctx, i.chunks[i.keyIndex].Key)
+			i.currentReadCloser, err = getLogChunk(ctx, i.bucket, i.chunks[i.keyIndex])
 			if err != nil {
 				i.catcher.Wrap(err, "downloading log artifact")
 				return false
@@ -278,7 +281,7 @@ func (i *batchedIterator) getNextBatch(ctx context.Context) error {
 					return
 				}
 
-				r, err := i.bucket.Get(ctx, chunk.Key)
+				r, err := getLogChunk(ctx, i.bucket, chunk)
 				if err != nil {
 					catcher.Add(err)
 					return
@@ -499,6 +502,652 @@ func (i *mergingIterator) Close() error {
 	return catcher.Resolve()
 }
 
+//////////////////////
+// Filtering Iterators
+//////////////////////
+
+// LogFilterOptions describes the server-side filters that may be applied to
+// the lines of a buildlogger log. The zero value applies no filters.
+type LogFilterOptions struct {
+	// MinPriority and MaxPriority, when set, bound the priority of the
+	// lines returned, inclusively.
+	MinPriority level.Priority
+	MaxPriority level.Priority
+	// Include and Exclude are regular expressions, when set, that lines
+	// must and must not match, respectively.
+	Include string
+	Exclude string
+	// MaxLines, when greater than 0, limits the number of lines returned
+	// after all other filters are applied.
+	MaxLines int
+	// Where maps field names of structured lines to the values they must
+	// have. Nested fields are named using dot notation. Lines that are not
+	// structured never match.
+	Where map[string]string
+	// Fields, when set, projects structured lines onto the named fields.
+	// Nested fields are named using dot notation. Lines that are not
+	// structured are returned as is.
+	Fields []string
+}
+
+// IsZero returns whether the LogFilterOptions applies no filters.
+func (o LogFilterOptions) IsZero() bool {
+	return o.MinPriority == 0 && o.MaxPriority == 0 && o.Include == "" && o.Exclude == "" && o.MaxLines == 0 &&
+		len(o.Where) == 0 && len(o.Fields) == 0
+}
+
+// Validate ensures that the LogFilterOptions are valid.
+func (o LogFilterOptions) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen(o.MinPriority != 0 && !o.MinPriority.IsValid(), "invalid minimum priority")
+	catcher.NewWhen(o.MaxPriority != 0 && !o.MaxPriority.IsValid(), "invalid maximum priority")
+	catcher.NewWhen(o.MaxPriority != 0 && o.MinPriority > o.MaxPriority, "minimum priority cannot be greater than maximum priority")
+	catcher.NewWhen(o.MaxLines < 0, "max lines cannot be negative")
+	_, err := regexp.Compile(o.Include)
+	catcher.Wrap(err, "compiling include pattern")
+	_, err = regexp.Compile(o.Exclude)
+	catcher.Wrap(err, "compiling exclude pattern")
+	for field := range o.Where {
+		catcher.ErrorfWhen(!isValidLogFieldName(field), "invalid where field name '%s'", field)
+	}
+	for _, field := range o.Fields {
+		catcher.ErrorfWhen(!isValidLogFieldName(field), "invalid field name '%s'", field)
+	}
+
+	return catcher.Resolve()
+}
+
+// NewFilteredLogIterator wraps the given LogIterator with the filtering
+// iterators described by the options. The given iterator is returned as is
+// if the options apply no filters.
+func NewFilteredLogIterator(it LogIterator, opts LogFilterOptions) (LogIterator, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid log filter options")
+	}
+
+	if opts.MinPriority != 0 || opts.MaxPriority != 0 {
+		it = NewPriorityFilterLogIterator(it, opts.MinPriority, opts.MaxPriority)
+	}
+	if len(opts.Where) > 0 {
+		it = NewFieldFilterLogIterator(it, opts.Where)
+	}
+	if opts.Include != "" {
+		it = NewRegexFilterLogIterator(it, regexp.MustCompile(opts.Include), false)
+	}
+	if opts.Exclude != "" {
+		it = NewRegexFilterLogIterator(it, regexp.MustCompile(opts.Exclude), true)
+	}
+	if opts.MaxLines > 0 {
+		it = NewLimitLogIterator(it, opts.MaxLines)
+	}
+	if len(opts.Fields) > 0 {
+		it = NewFieldProjectionLogIterator(it, opts.Fields)
+	}
+
+	return it, nil
+}
+
+type filteringIterator struct {
+	it     LogIterator
+	filter func(LogLine) bool
+	item   LogLine
+	// ctx is the context of the last call to Next, used to look ahead
+	// for the next matching line when checking for exhaustion.
+	ctx  context.Context
+	next *LogLine
+}
+
+// NewPriorityFilterLogIterator returns a LogIterator that wraps the given
+// LogIterator and only returns lines with a priority between min and max,
+// inclusively. A min or max of 0 leaves that bound unset.
+func NewPriorityFilterLogIterator(it LogIterator, min, max level.Priority) LogIterator {
+	return &filteringIterator{
+		it: it,
+		filter: func(line LogLine) bool {
+			return line.Priority >= min && (max == 0 || line.Priority <= max)
+		},
+	}
+}
+
+// NewRegexFilterLogIterator returns a LogIterator that wraps the given
+// LogIterator and only returns lines whose data matches the regular
+// expression. When exclude is true, only lines that do not match are
+// returned.
+func NewRegexFilterLogIterator(it LogIterator, re *regexp.Regexp, exclude bool) LogIterator {
+	return &filteringIterator{
+		it: it,
+		filter: func(line LogLine) bool {
+			return re.MatchString(strings.TrimSuffix(line.Data, "\n")) != exclude
+		},
+	}
+}
+
+// NewFieldFilterLogIterator returns a LogIterator that wraps the given
+// LogIterator and only returns structured lines whose fields have the given
+// values. Nested fields are named using dot notation.
+func NewFieldFilterLogIterator(it LogIterator, where map[string]string) LogIterator {
+	return &filteringIterator{
+		it: it,
+		filter: func(line LogLine) bool {
+			if line.Fields == nil {
+				return false
+			}
+			for field, value := range where {
+				actual, ok := lookupLogField(line.Fields, field)
+				if !ok || formatLogFieldValue(actual) != value {
+					return false
+				}
+			}
+
+			return true
+		},
+	}
+}
+
+func (i *filteringIterator) Reverse() LogIterator {
+	return &filteringIterator{
+		it:     i.it.Reverse(),
+		filter: i.filter,
+	}
+}
+
+func (i *filteringIterator) IsReversed() bool { return i.it.IsReversed() }
+
+func (i *filteringIterator) Next(ctx context.Context) bool {
+	i.ctx = ctx
+	if i.next != nil {
+		i.item = *i.next
+		i.next = nil
+		return true
+	}
+
+	line, ok := i.nextMatch(ctx)
+	if ok {
+		i.item = line
+	}
+
+	return ok
+}
+
+func (i *filteringIterator) nextMatch(ctx context.Context) (LogLine, bool) {
+	for i.it.Next(ctx) {
+		if line := i.it.Item(); i.filter(line) {
+			return line, true
+		}
+	}
+
+	return LogLine{}, false
+}
+
+// Exhausted returns true if the underlying iterator is exhausted or only
+// lines that are filtered out remain. The remaining lines are only read ahead
+// after iteration has started, keeping the next matching line for Next.
+func (i *filteringIterator) Exhausted() bool {
+	if i.next != nil {
+		return false
+	}
+	if i.it.Exhausted() || i.ctx == nil {
+		return i.it.Exhausted()
+	}
+
+	if line, ok := i.nextMatch(i.ctx); ok {
+		i.next = &line
+		return false
+	}
+
+	return i.it.Exhausted()
+}
+
+func (i *filteringIterator) Err() error { return i.it.Err() }
+
+func (i *filteringIterator) Item() LogLine { return i.item }
+
+func (i *filteringIterator) Close() error { return i.it.Close() }
+
+type limitIterator struct {
+	it        LogIterator
+	limit     int
+	lineCount int
+}
+
+// NewLimitLogIterator returns a LogIterator that wraps the given LogIterator
+// and returns at most n lines.
+func NewLimitLogIterator(it LogIterator, n int) LogIterator {
+	return &limitIterator{
+		it:    it,
+		limit: n,
+	}
+}
+
+func (i *limitIterator) Reverse() LogIterator {
+	return &limitIterator{
+		it:    i.it.Reverse(),
+		limit: i.limit,
+	}
+}
+
+func (i *limitIterator) IsReversed() bool { return i.it.IsReversed() }
+
+func (i *limitIterator) Next(ctx context.Context) bool {
+	if i.lineCount >= i.limit {
+		return false
+	}
+	if !i.it.Next(ctx) {
+		return false
+	}
+
+	i.lineCount++
+	return true
+}
+
+func (i *limitIterator) Exhausted() bool { return i.lineCount >= i.limit || i.it.Exhausted() }
+
+func (i *limitIterator) Err() error { return i.it.Err() }
+
+func (i *limitIterator) Item() LogLine { return i.it.Item() }
+
+func (i *limitIterator) Close() error { return i.it.Close() }
+
+type projectionIterator struct {
+	it     LogIterator
+	fields []string
+	item   LogLine
+	err    error
+}
+
+// NewFieldProjectionLogIterator returns a LogIterator that wraps the given
+// LogIterator and projects structured lines onto the given fields, replacing
+// each line's data with a JSON object of the fields. Nested fields are named
+// using dot notation and missing fields are omitted. Lines that are not
+// structured are returned as is.
+func NewFieldProjectionLogIterator(it LogIterator, fields []string) LogIterator {
+	return &projectionIterator{
+		it:     it,
+		fields: fields,
+	}
+}
+
+func (i *projectionIterator) Reverse() LogIterator {
+	return &projectionIterator{
+		it:     i.it.Reverse(),
+		fields: i.fields,
+	}
+}
+
+func (i *projectionIterator) IsReversed() bool { return i.it.IsReversed() }
+
+func (i *projectionIterator) Next(ctx context.Context) bool {
+	if i.err != nil || !i.it.Next(ctx) {
+		return false
+	}
+
+	i.item = i.it.Item()
+	if i.item.Fields == nil {
+		return true
+	}
+
+	projected := map[string]interface{}{}
+	for _, field := range i.fields {
+		if value, ok := lookupLogField(i.item.Fields, field); ok {
+			projected[field] = value
+		}
+	}
+	data, err := json.Marshal(projected)
+	if err != nil {
+		i.err = errors.Wrap(err, "marshalling projected log line")
+		return false
+	}
+	i.item.Data = string(data) + "\n"
+	i.item.Fields = projected
+
+	return true
+}
+
+func (i *projectionIterator) Exhausted() bool { return i.it.Exhausted() }
+
+func (i *projectionIterator) Err() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(i.it.Err())
+	catcher.Add(i.err)
+
+	return catcher.Resolve()
+}
+
+func (i *projectionIterator) Item() LogLine { return i.item }
+
+func (i *projectionIterator) Close() error { return i.it.Close() }
+
+func isValidLogFieldName(field string) bool {
+	for _, name := range strings.Split(field, ".") {
+		if name == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookupLogField returns the value of the field, named using dot notation, in
+// the fields of a structured log line.
+func lookupLogField(fields map[string]interface{}, field string) (interface{}, bool) {
+	var value interface{} = fields
+	for _, name := range strings.Split(field, ".") {
+		doc, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = doc[name]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// formatLogFieldValue returns the string representation of a structured log
+// line's field value used for matching. Strings are returned as is and all
+// other values are returned as JSON.
+func formatLogFieldValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+//////////////////////
+// Structured Iterator
+//////////////////////
+
+type structuredIterator struct {
+	it     LogIterator
+	format LogFormat
+	item   LogLine
+}
+
+// NewStructuredLogIterator returns a LogIterator that wraps the given
+// LogIterator and decodes the lines of a log with a structured format, such
+// as JSON or BSON, into records, setting each line's fields and replacing its
+// data with the record as a JSON object. Lines that cannot be decoded, such
+// as lines stored before the format was supported, are returned as is. The
+// given iterator is returned as is if the format is not structured.
+func NewStructuredLogIterator(it LogIterator, format LogFormat) LogIterator {
+	if !format.IsStructured() {
+		return it
+	}
+
+	return &structuredIterator{
+		it:     it,
+		format: format,
+	}
+}
+
+func (i *structuredIterator) Reverse() LogIterator {
+	return &structuredIterator{
+		it:     i.it.Reverse(),
+		format: i.format,
+	}
+}
+
+func (i *structuredIterator) IsReversed() bool { return i.it.IsReversed() }
+
+func (i *structuredIterator) Next(ctx context.Context) bool {
+	if !i.it.Next(ctx) {
+		return false
+	}
+
+	i.item = i.it.Item()
+	if data, fields, err := i.format.decodeLineData(i.item.Data); err == nil {
+		i.item.Data = data
+		i.item.Fields = fields
+	}
+
+	return true
+}
+
+func (i *structuredIterator) Exhausted() bool { return i.it.Exhausted() }
+
+func (i *structuredIterator) Err() error { return i.it.Err() }
+
+func (i *structuredIterator) Item() LogLine { return i.item }
+
+func (i *structuredIterator) Close() error { return i.it.Close() }
+
+/////////////////////
+// Following Iterator
+/////////////////////
+
+const defaultLogFollowPollInterval = 2 * time.Second
+
+// maxLogFollowTime is used as the end of the time range when following a log
+// since lines may be appended with any timestamp.
+var maxLogFollowTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// LogFollowPollFunc returns the current chunks of a log that is being followed
+// and whether the log is complete, i.e. no more chunks will be added.
+// Implementations should check whether the log is complete before listing its
+// chunks, otherwise chunks appended right before completion may be missed.
+type LogFollowPollFunc func(context.Context) ([]LogChunkInfo, bool, error)
+
+// LogFollowOptions describes the options for following a buildlogger log.
+type LogFollowOptions struct {
+	// StartAt is the earliest timestamp of the lines returned. Lines
+	// before it are skipped.
+	StartAt time.Time
+	// PollInterval is the amount of time to wait between polls for new
+	// chunks when no new chunks were found. Defaults to 2 seconds.
+	PollInterval time.Duration
+	// Timeout is the maximum amount of time to follow the log. Once
+	// reached, iteration stops at the end of the current chunk without
+	// error even if the log is not complete. If equal to 0, the log is
+	// followed until it is complete or the context errors.
+	Timeout time.Duration
+	// AfterChunkKey, when set, resumes following the log after the chunk
+	// with the given key, as returned by FollowingLogIterator.ChunkKey.
+	// The chunks up to and including it are never downloaded.
+	AfterChunkKey string
+	// Format is the format of the log's lines. Lines of structured
+	// formats are decoded as by NewStructuredLogIterator.
+	Format LogFormat
+}
+
+// FollowingLogIterator is a LogIterator that follows an in-progress
+// buildlogger log.
+type FollowingLogIterator interface {
+	LogIterator
+	// ChunkKey returns the key of the last chunk whose lines were all
+	// returned, or the key the iterator resumed after if no chunk was
+	// returned yet. The key does not depend on the chunk's compression.
+	ChunkKey() string
+}
+
+type followingIterator struct {
+	bucket        pail.Bucket
+	poll          LogFollowPollFunc
+	startAt       time.Time
+	pollInterval  time.Duration
+	deadline      time.Time
+	afterChunkKey string
+	format        LogFormat
+	sent          map[string]bool
+	pending       []LogChunkInfo
+	currentChunk  LogChunkInfo
+	chunkKey      string
+	current       LogIterator
+	currentItem   LogLine
+	completed     bool
+	wait          bool
+	exhausted     bool
+	closed        bool
+	catcher       grip.Catcher
+}
+
+// NewFollowingLogIterator returns a FollowingLogIterator that follows an
+// in-progress buildlogger log. The given poll function is called to find
+// chunks that were not yet returned, waiting between polls when none are
+// found, until the log is complete. Chunks are downloaded one at a time so
+// that the key of the last chunk returned can be used to resume following
+// the log. Next blocks until a new line is available, the log completes, or
+// the context errors. Reversing a following iterator is not supported.
+func NewFollowingLogIterator(bucket pail.Bucket, poll LogFollowPollFunc, opts LogFollowOptions) FollowingLogIterator {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultLogFollowPollInterval
+	}
+
+	it := &followingIterator{
+		bucket:        bucket,
+		poll:          poll,
+		startAt:       opts.StartAt,
+		pollInterval:  opts.PollInterval,
+		afterChunkKey: opts.AfterChunkKey,
+		format:        opts.Format,
+		chunkKey:      opts.AfterChunkKey,
+		sent:          map[string]bool{},
+		catcher:       grip.NewBasicCatcher(),
+	}
+	if opts.Timeout > 0 {
+		it.deadline = time.Now().Add(opts.Timeout)
+	}
+
+	return it
+}
+
+// Reverse returns the iterator unchanged since a log cannot be followed in
+// reverse.
+func (i *followingIterator) Reverse() LogIterator { return i }
+
+func (i *followingIterator) IsReversed() bool { return false }
+
+func (i *followingIterator) Next(ctx context.Context) bool {
+	if i.closed || i.exhausted {
+		return false
+	}
+
+	for {
+		if i.current != nil {
+			if i.current.Next(ctx) {
+				i.currentItem = i.current.Item()
+				return true
+			}
+
+			i.catcher.Add(i.current.Err())
+			i.catcher.Add(i.current.Close())
+			i.current = nil
+			if i.catcher.HasErrors() {
+				return false
+			}
+			i.chunkKey = logFollowChunkKey(i.currentChunk.Key)
+		}
+
+		if len(i.pending) > 0 {
+			i.currentChunk = i.pending[0]
+			i.pending = i.pending[1:]
+			i.current = NewStructuredLogIterator(NewBatchedLogIterator(i.bucket, []LogChunkInfo{i.currentChunk}, 1, TimeRange{
+				StartAt: i.startAt,
+				EndAt:   maxLogFollowTime,
+			}), i.format)
+			continue
+		}
+
+		if i.completed {
+			i.exhausted = true
+			return false
+		}
+
+		if i.timedOut() {
+			return false
+		}
+		if i.wait {
+			wait := i.pollInterval
+			if !i.deadline.IsZero() && time.Until(i.deadline) < wait {
+				wait = time.Until(i.deadline)
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				i.catcher.Add(ctx.Err())
+				return false
+			case <-timer.C:
+			}
+			if i.timedOut() {
+				return false
+			}
+		}
+
+		chunks, completed, err := i.poll(ctx)
+		if err != nil {
+			i.catcher.Wrap(err, "polling for new chunks")
+			return false
+		}
+		i.completed = completed
+
+		if i.afterChunkKey != "" {
+			if !i.skipThroughChunk(chunks) {
+				i.catcher.Errorf("chunk '%s' to resume after not found", i.afterChunkKey)
+				return false
+			}
+			i.afterChunkKey = ""
+		}
+		for _, chunk := range chunks {
+			if !i.sent[chunk.Key] {
+				i.pending = append(i.pending, chunk)
+				i.sent[chunk.Key] = true
+			}
+		}
+		i.wait = len(i.pending) == 0
+	}
+}
+
+// skipThroughChunk marks the chunks up to and including the chunk to resume
+// after as sent, returning whether the chunk was found.
+func (i *followingIterator) skipThroughChunk(chunks []LogChunkInfo) bool {
+	for idx, chunk := range chunks {
+		if logFollowChunkKey(chunk.Key) != i.afterChunkKey {
+			continue
+		}
+		for _, skipped := range chunks[:idx+1] {
+			i.sent[skipped.Key] = true
+		}
+		return true
+	}
+
+	return false
+}
+
+// logFollowChunkKey returns the key of the chunk without the extension of its
+// compression codec, so that it remains valid if the log is recompressed.
+func logFollowChunkKey(key string) string {
+	return strings.TrimSuffix(key, path.Ext(key))
+}
+
+func (i *followingIterator) ChunkKey() string { return i.chunkKey }
+
+func (i *followingIterator) timedOut() bool {
+	return !i.deadline.IsZero() && !time.Now().Before(i.deadline)
+}
+
+func (i *followingIterator) Exhausted() bool { return i.exhausted }
+
+func (i *followingIterator) Err() error { return i.catcher.Resolve() }
+
+func (i *followingIterator) Item() LogLine { return i.currentItem }
+
+func (i *followingIterator) Close() error {
+	i.closed = true
+	if i.current != nil {
+		return i.current.Close()
+	}
+
+	return nil
+}
+
 ///////////////////
 // Helper functions
 ///////////////////
@@ -614,6 +1263,10 @@ type LogIteratorReaderOptions struct {
 	// lines will be read until the iterator is exhausted. If TailN is
 	// greater than 0, Limit will be ignored.
 	Limit int
+	// Skip is the number of lines to skip before reading, e.g. when
+	// resuming a read. Skipped lines count towards Limit. If TailN is
+	// set, this will be ignored.
+	Skip int
 	// TailN is the number of lines to read from the tail of the log. If
 	// equal to 0, the reader returned will read log lines in normal order.
 	TailN int
@@ -632,6 +1285,12 @@ type LogIteratorReaderOptions struct {
 	// also reading every line for each timestamp reached. If TailN is set,
 	// this will be ignored.
 	SoftSizeLimit int
+	// LineBuffered, when true, returns from each read as soon as a line
+	// is read instead of filling the buffer. This should be set when
+	// reading from an iterator that blocks waiting for new lines, such as
+	// one following an in-progress log. If TailN is set, this will be
+	// ignored.
+	LineBuffered bool
 }
 
 // NewLogIteratorReader returns an io.Reader that reads the log lines from the
@@ -657,7 +1316,9 @@ func NewLogIteratorReader(ctx context.Context, it LogIterator, opts LogIteratorR
 		limit:         opts.Limit,
 		printTime:     opts.PrintTime,
 		printPriority: opts.PrintPriority,
+		skip:          opts.Skip,
 		softSizeLimit: opts.SoftSizeLimit,
+		lineBuffered:  opts.LineBuffered,
 	}
 }
 
@@ -666,10 +1327,12 @@ type logIteratorReader struct {
 	it             LogIterator
 	lineCount      int
 	limit          int
+	skip           int
 	leftOver       []byte
 	printTime      bool
 	printPriority  bool
 	softSizeLimit  int
+	lineBuffered   bool
 	totalBytesRead int
 	lastItem       LogLine
 }
@@ -694,6 +1357,9 @@ func (r *logIteratorReader) Read(p []byte) (int, error) {
 		if r.softSizeLimit > 0 && r.totalBytesRead >= r.softSizeLimit && !r.lastItem.Timestamp.Equal(r.it.Item().Timestamp) {
 			break
 		}
+		if r.lineCount <= r.skip {
+			continue
+		}
 
 		r.lastItem = r.it.Item()
 		data := r.it.Item().Data
@@ -705,7 +1371,7 @@ func (r *logIteratorReader) Read(p []byte) (int, error) {
 			data = fmt.Sprintf("[P:%3d] %s", r.it.Item().Priority, data)
 		}
 		n = r.writeToBuffer([]byte(data), p, n)
-		if n == len(p) {
+		if n == len(p) || r.lineBuffered {
 			return n, nil
 		}
 	}
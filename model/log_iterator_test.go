@@ -2,15 +2,19 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/evergreen-ci/pail"
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -319,6 +323,422 @@ func TestMergeLogIterator(t *testing.T) {
 	})
 }
 
+func TestFilteringLogIterator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpDir, err := ioutil.TempDir(".", "filtering-log-iterator-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir})
+	require.NoError(t, err)
+
+	ts := time.Now().Round(time.Millisecond).UTC()
+	lines := []LogLine{
+		{Priority: level.Debug, Timestamp: ts, Data: "connecting\n"},
+		{Priority: level.Info, Timestamp: ts.Add(time.Millisecond), Data: "connected to host\n"},
+		{Priority: level.Warning, Timestamp: ts.Add(2 * time.Millisecond), Data: "slow query\n"},
+		{Priority: level.Error, Timestamp: ts.Add(3 * time.Millisecond), Data: "query failed\n"},
+		{Priority: level.Info, Timestamp: ts.Add(4 * time.Millisecond), Data: "retrying query\n"},
+		{Priority: level.Critical, Timestamp: ts.Add(5 * time.Millisecond), Data: "connection lost\n"},
+	}
+	var rawLines string
+	for _, line := range lines {
+		rawLines += prependPriorityAndTimestamp(line.Priority, line.Timestamp, strings.TrimSuffix(line.Data, "\n"))
+	}
+	chunks := []LogChunkInfo{
+		{
+			Key:      createBuildloggerChunkKey(lines[0].Timestamp, lines[len(lines)-1].Timestamp, len(lines)),
+			NumLines: len(lines),
+			Start:    lines[0].Timestamp,
+			End:      lines[len(lines)-1].Timestamp,
+		},
+	}
+	require.NoError(t, bucket.Put(ctx, chunks[0].Key, strings.NewReader(rawLines)))
+	timeRange := TimeRange{EndAt: lines[len(lines)-1].Timestamp}
+	newIterator := func() LogIterator {
+		return NewSerializedLogIterator(bucket, chunks, timeRange)
+	}
+	readAll := func(t *testing.T, it LogIterator) []LogLine {
+		var out []LogLine
+		for it.Next(ctx) {
+			out = append(out, it.Item())
+		}
+		assert.NoError(t, it.Err())
+		assert.True(t, it.Exhausted())
+		assert.NoError(t, it.Close())
+		return out
+	}
+
+	t.Run("Priority", func(t *testing.T) {
+		assert.Equal(t, []LogLine{lines[2], lines[3], lines[5]}, readAll(t, NewPriorityFilterLogIterator(newIterator(), level.Warning, 0)))
+		assert.Equal(t, []LogLine{lines[0], lines[1], lines[4]}, readAll(t, NewPriorityFilterLogIterator(newIterator(), 0, level.Info)))
+		assert.Equal(t, []LogLine{lines[2], lines[3]}, readAll(t, NewPriorityFilterLogIterator(newIterator(), level.Warning, level.Error)))
+	})
+	t.Run("Regex", func(t *testing.T) {
+		re := regexp.MustCompile("query")
+		assert.Equal(t, []LogLine{lines[2], lines[3], lines[4]}, readAll(t, NewRegexFilterLogIterator(newIterator(), re, false)))
+		assert.Equal(t, []LogLine{lines[0], lines[1], lines[5]}, readAll(t, NewRegexFilterLogIterator(newIterator(), re, true)))
+		assert.Equal(t, []LogLine{lines[3]}, readAll(t, NewRegexFilterLogIterator(newIterator(), regexp.MustCompile("failed$"), false)))
+	})
+	t.Run("ExhaustedWhenRemainingLinesFilteredOut", func(t *testing.T) {
+		it := NewPriorityFilterLogIterator(newIterator(), level.Warning, level.Error)
+		assert.False(t, it.Exhausted())
+		require.True(t, it.Next(ctx))
+		assert.Equal(t, lines[2], it.Item())
+		assert.False(t, it.Exhausted())
+		assert.Equal(t, lines[2], it.Item())
+		require.True(t, it.Next(ctx))
+		assert.Equal(t, lines[3], it.Item())
+		assert.True(t, it.Exhausted())
+		assert.Equal(t, lines[3], it.Item())
+		assert.False(t, it.Next(ctx))
+		assert.NoError(t, it.Err())
+		assert.NoError(t, it.Close())
+	})
+	t.Run("Limit", func(t *testing.T) {
+		assert.Equal(t, lines[:2], readAll(t, NewLimitLogIterator(newIterator(), 2)))
+		assert.Equal(t, lines, readAll(t, NewLimitLogIterator(newIterator(), len(lines)+1)))
+	})
+	t.Run("Reversed", func(t *testing.T) {
+		it := NewLimitLogIterator(NewPriorityFilterLogIterator(newIterator(), level.Warning, 0), 2).Reverse()
+		assert.True(t, it.IsReversed())
+		assert.Equal(t, []LogLine{lines[5], lines[3]}, readAll(t, it))
+	})
+	t.Run("Composed", func(t *testing.T) {
+		it, err := NewFilteredLogIterator(newIterator(), LogFilterOptions{
+			MinPriority: level.Info,
+			Include:     "query|connect",
+			Exclude:     "retrying",
+			MaxLines:    3,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []LogLine{lines[1], lines[2], lines[3]}, readAll(t, it))
+	})
+	t.Run("NoFilters", func(t *testing.T) {
+		base := newIterator()
+		it, err := NewFilteredLogIterator(base, LogFilterOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, base, it)
+		assert.NoError(t, it.Close())
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		for _, opts := range []LogFilterOptions{
+			{MinPriority: 200},
+			{MaxPriority: -1},
+			{MinPriority: level.Error, MaxPriority: level.Info},
+			{Include: "("},
+			{Exclude: "["},
+			{MaxLines: -1},
+		} {
+			it, err := NewFilteredLogIterator(newIterator(), opts)
+			assert.Error(t, err)
+			assert.Nil(t, it)
+		}
+	})
+}
+
+func TestStructuredLogIterator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpDir, err := ioutil.TempDir(".", "structured-log-iterator-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir})
+	require.NoError(t, err)
+
+	ts := time.Now().Round(time.Millisecond).UTC()
+	records := []string{
+		`{"level":"info","component":"network","ctx":{"host":"a"}}`,
+		"legacy text line",
+		`{"level":"error","component":"storage","ctx":{"host":"b"}}`,
+		`{"level":"error","component":"network","ctx":{"host":"a"},"code":7}`,
+	}
+	var rawLines string
+	for i, record := range records {
+		rawLines += prependPriorityAndTimestamp(level.Info, ts.Add(time.Duration(i)*time.Millisecond), record)
+	}
+	chunks := []LogChunkInfo{
+		{
+			Key:      createBuildloggerChunkKey(ts, ts.Add(time.Duration(len(records)-1)*time.Millisecond), len(records)),
+			NumLines: len(records),
+			Start:    ts,
+			End:      ts.Add(time.Duration(len(records)-1) * time.Millisecond),
+		},
+	}
+	require.NoError(t, bucket.Put(ctx, chunks[0].Key, strings.NewReader(rawLines)))
+	newIterator := func(format LogFormat) LogIterator {
+		return NewStructuredLogIterator(NewSerializedLogIterator(bucket, chunks, TimeRange{EndAt: chunks[0].End}), format)
+	}
+	readData := func(t *testing.T, it LogIterator) []string {
+		var out []string
+		for it.Next(ctx) {
+			out = append(out, strings.TrimSuffix(it.Item().Data, "\n"))
+		}
+		assert.NoError(t, it.Err())
+		assert.NoError(t, it.Close())
+		return out
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		it := newIterator(LogFormatText)
+		_, ok := it.(*structuredIterator)
+		assert.False(t, ok)
+		for it.Next(ctx) {
+			assert.Nil(t, it.Item().Fields)
+		}
+		assert.NoError(t, it.Close())
+	})
+	t.Run("JSON", func(t *testing.T) {
+		it := newIterator(LogFormatJSON)
+		var fields []map[string]interface{}
+		for it.Next(ctx) {
+			fields = append(fields, it.Item().Fields)
+		}
+		assert.NoError(t, it.Err())
+		assert.NoError(t, it.Close())
+		require.Len(t, fields, len(records))
+		assert.Equal(t, map[string]interface{}{
+			"level":     "info",
+			"component": "network",
+			"ctx":       map[string]interface{}{"host": "a"},
+		}, fields[0])
+		assert.Nil(t, fields[1])
+		assert.Equal(t, json.Number("7"), fields[3]["code"])
+	})
+	t.Run("Reversed", func(t *testing.T) {
+		it := newIterator(LogFormatJSON).Reverse()
+		assert.True(t, it.IsReversed())
+		assert.Equal(t, []string{records[3], records[2], records[1], records[0]}, readData(t, it))
+	})
+	t.Run("FieldFilter", func(t *testing.T) {
+		it := NewFieldFilterLogIterator(newIterator(LogFormatJSON), map[string]string{"level": "error"})
+		assert.Equal(t, []string{records[2], records[3]}, readData(t, it))
+
+		it = NewFieldFilterLogIterator(newIterator(LogFormatJSON), map[string]string{"level": "error", "ctx.host": "a"})
+		assert.Equal(t, []string{records[3]}, readData(t, it))
+
+		it = NewFieldFilterLogIterator(newIterator(LogFormatJSON), map[string]string{"code": "7"})
+		assert.Equal(t, []string{records[3]}, readData(t, it))
+
+		it = NewFieldFilterLogIterator(newIterator(LogFormatJSON), map[string]string{"ctx": `{"host":"b"}`})
+		assert.Equal(t, []string{records[2]}, readData(t, it))
+
+		it = NewFieldFilterLogIterator(newIterator(LogFormatJSON), map[string]string{"missing": "value"})
+		assert.Empty(t, readData(t, it))
+	})
+	t.Run("FieldProjection", func(t *testing.T) {
+		it := NewFieldProjectionLogIterator(newIterator(LogFormatJSON), []string{"component", "ctx.host", "code"})
+		assert.Equal(t, []string{
+			`{"component":"network","ctx.host":"a"}`,
+			records[1],
+			`{"component":"storage","ctx.host":"b"}`,
+			`{"code":7,"component":"network","ctx.host":"a"}`,
+		}, readData(t, it))
+
+		it = NewFieldProjectionLogIterator(newIterator(LogFormatJSON), []string{"component"}).Reverse()
+		assert.True(t, it.IsReversed())
+		assert.Equal(t, []string{
+			`{"component":"network"}`,
+			`{"component":"storage"}`,
+			records[1],
+			`{"component":"network"}`,
+		}, readData(t, it))
+	})
+	t.Run("Composed", func(t *testing.T) {
+		it, err := NewFilteredLogIterator(newIterator(LogFormatJSON), LogFilterOptions{
+			Where:  map[string]string{"component": "network"},
+			Fields: []string{"level"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{`{"level":"info"}`, `{"level":"error"}`}, readData(t, it))
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		for _, opts := range []LogFilterOptions{
+			{Where: map[string]string{"": "value"}},
+			{Where: map[string]string{"ctx.": "value"}},
+			{Fields: []string{"ctx..host"}},
+		} {
+			it, err := NewFilteredLogIterator(newIterator(LogFormatJSON), opts)
+			assert.Error(t, err)
+			assert.Nil(t, it)
+		}
+	})
+}
+
+func TestFollowingLogIterator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpDir, err := ioutil.TempDir(".", "following-log-iterator-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir})
+	require.NoError(t, err)
+	chunks, lines, err := GenerateTestLog(ctx, bucket, 30, 10)
+	require.NoError(t, err)
+	opts := LogFollowOptions{PollInterval: time.Millisecond}
+
+	readAll := func(t *testing.T, it LogIterator) []LogLine {
+		var out []LogLine
+		for it.Next(ctx) {
+			out = append(out, it.Item())
+		}
+		assert.NoError(t, it.Err())
+		assert.NoError(t, it.Close())
+		return out
+	}
+
+	t.Run("CompletedLog", func(t *testing.T) {
+		var polls int
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			polls++
+			return chunks, true, nil
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, opts)
+		assert.Equal(t, lines, readAll(t, it))
+		assert.True(t, it.Exhausted())
+		assert.Equal(t, 1, polls)
+	})
+	t.Run("InProgressLog", func(t *testing.T) {
+		var polls int
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			polls++
+			switch polls {
+			case 1, 2, 3:
+				return chunks[:1], false, nil
+			case 4:
+				return chunks[:2], false, nil
+			default:
+				return chunks, true, nil
+			}
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, opts)
+		assert.Equal(t, lines, readAll(t, it))
+		assert.True(t, it.Exhausted())
+		assert.Equal(t, 5, polls)
+	})
+	t.Run("StartAt", func(t *testing.T) {
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return chunks, true, nil
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, LogFollowOptions{StartAt: lines[15].Timestamp})
+		assert.Equal(t, lines[15:], readAll(t, it))
+	})
+	t.Run("ContextError", func(t *testing.T) {
+		tctx, tcancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer tcancel()
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return chunks[:1], false, nil
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, opts)
+		var count int
+		for it.Next(tctx) {
+			count++
+		}
+		assert.Equal(t, chunks[0].NumLines, count)
+		assert.Error(t, it.Err())
+		assert.False(t, it.Exhausted())
+		assert.NoError(t, it.Close())
+	})
+	t.Run("Timeout", func(t *testing.T) {
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return chunks[:1], false, nil
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, LogFollowOptions{
+			PollInterval: time.Millisecond,
+			Timeout:      20 * time.Millisecond,
+		})
+		assert.Equal(t, lines[:chunks[0].NumLines], readAll(t, it))
+		assert.False(t, it.Exhausted())
+	})
+	t.Run("ChunkKey", func(t *testing.T) {
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return chunks[:2], false, nil
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, LogFollowOptions{
+			PollInterval: time.Millisecond,
+			Timeout:      20 * time.Millisecond,
+		})
+		assert.Empty(t, it.ChunkKey())
+		for i := 0; i < chunks[0].NumLines; i++ {
+			require.True(t, it.Next(ctx))
+		}
+		assert.Empty(t, it.ChunkKey())
+		assert.Equal(t, lines[chunks[0].NumLines:chunks[0].NumLines+chunks[1].NumLines], readAll(t, it))
+		assert.Equal(t, chunks[1].Key, it.ChunkKey())
+	})
+	t.Run("AfterChunkKey", func(t *testing.T) {
+		var downloaded []string
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return chunks, true, nil
+		}
+		countingBucket := &keyRecordingBucket{Bucket: bucket, keys: &downloaded}
+
+		it := NewFollowingLogIterator(countingBucket, poll, LogFollowOptions{AfterChunkKey: chunks[1].Key})
+		assert.Equal(t, chunks[1].Key, it.ChunkKey())
+		assert.Equal(t, lines[chunks[0].NumLines+chunks[1].NumLines:], readAll(t, it))
+		assert.True(t, it.Exhausted())
+		assert.Equal(t, chunks[len(chunks)-1].Key, it.ChunkKey())
+		for _, chunk := range chunks[:2] {
+			assert.NotContains(t, downloaded, chunk.Key)
+		}
+	})
+	t.Run("ChunkKeyIgnoresCompression", func(t *testing.T) {
+		assert.Equal(t, "1_2_3", logFollowChunkKey("1_2_3.gz"))
+		assert.Equal(t, "1_2_3", logFollowChunkKey("1_2_3"))
+	})
+	t.Run("AfterUnknownChunkKey", func(t *testing.T) {
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return chunks, true, nil
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, LogFollowOptions{AfterChunkKey: "DNE"})
+		assert.False(t, it.Next(ctx))
+		assert.Error(t, it.Err())
+		assert.NoError(t, it.Close())
+	})
+	t.Run("PollError", func(t *testing.T) {
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return nil, false, errors.New("poll error")
+		}
+
+		it := NewFollowingLogIterator(bucket, poll, opts)
+		assert.False(t, it.Next(ctx))
+		assert.Error(t, it.Err())
+		assert.NoError(t, it.Close())
+	})
+	t.Run("LineBufferedReader", func(t *testing.T) {
+		poll := func(_ context.Context) ([]LogChunkInfo, bool, error) {
+			return chunks, true, nil
+		}
+
+		r := NewLogIteratorReader(ctx, NewFollowingLogIterator(bucket, poll, opts), LogIteratorReaderOptions{LineBuffered: true})
+		buf := make([]byte, 4096)
+		for _, line := range lines {
+			n, err := r.Read(buf)
+			require.NoError(t, err)
+			assert.Equal(t, line.Data, string(buf[:n]))
+		}
+		n, err := r.Read(buf)
+		assert.Zero(t, n)
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
 func TestLogIteratorReader(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -525,6 +945,18 @@ func TestLogIteratorReader(t *testing.T) {
 		assert.Zero(t, n)
 		assert.Error(t, err)
 	})
+	t.Run("WithSkip", func(t *testing.T) {
+		opts := LogIteratorReaderOptions{Skip: 30, Limit: 40}
+		r := NewLogIteratorReader(ctx, NewBatchedLogIterator(bucket, chunks, 2, timeRange), opts)
+		readData, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		var expected string
+		for _, line := range lines[30:40] {
+			expected += line.Data
+		}
+		assert.Equal(t, expected, string(readData))
+	})
 	t.Run("WithSoftSizeLimit", func(t *testing.T) {
 		opts := LogIteratorReaderOptions{SoftSizeLimit: 5000}
 		it := NewMergingIterator(
@@ -779,3 +1211,15 @@ func TestLogIteratorTailReader(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+// keyRecordingBucket is a pail.Bucket that records the keys of the objects
+// it gets.
+type keyRecordingBucket struct {
+	pail.Bucket
+	keys *[]string
+}
+
+func (b *keyRecordingBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	*b.keys = append(*b.keys, key)
+	return b.Bucket.Get(ctx, key)
+}
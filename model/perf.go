@@ -297,6 +297,54 @@ func (result *PerformanceResult) Remove(ctx context.Context) (int, error) {
 	return int(deleteResult.DeletedCount), nil
 }
 
+// RemoveArtifacts removes the artifacts of the performance result and all of
+// its children, which are removed along with it, from their offline blob
+// storage buckets. Artifacts are uploaded by clients, so only the ones stored
+// in a bucket managed by Cedar, as set in the application configuration, are
+// removed and the others are left to their owners. The environment should not
+// be nil.
+func (result *PerformanceResult) RemoveArtifacts(ctx context.Context) error {
+	if result.env == nil {
+		return errors.New("cannot remove performance result artifacts with a nil environment")
+	}
+
+	if result.ID == "" {
+		result.ID = result.Info.ID()
+	}
+
+	children := PerformanceResults{env: result.env}
+	if err := children.findAllChildrenGraphLookup(ctx, result.ID, -1, []string{}); err != nil {
+		return errors.Wrap(err, "getting children to remove artifacts")
+	}
+
+	artifacts := append([]ArtifactInfo{}, result.Artifacts...)
+	for _, res := range children.Results {
+		artifacts = append(artifacts, res.Artifacts...)
+	}
+
+	conf := &CedarConfig{}
+	conf.Setup(result.env)
+	if err := conf.Find(); err != nil {
+		return errors.Wrap(err, "getting application configuration")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, artifact := range artifacts {
+		if !conf.Bucket.isManaged(artifact.Bucket) {
+			continue
+		}
+
+		bucket, err := artifact.Type.Create(ctx, result.env, artifact.Bucket, artifact.Prefix, "", false)
+		if err != nil {
+			catcher.Wrapf(err, "creating bucket for artifact '%s'", artifact.Path)
+			continue
+		}
+		catcher.Wrapf(bucket.Remove(ctx, artifact.Path), "removing artifact '%s'", artifact.Path)
+	}
+
+	return errors.Wrapf(catcher.Resolve(), "removing artifacts for performance result '%s'", result.ID)
+}
+
 // Close "closes out" the performance result by populating the completed_at
 // field. The envirnment should not be nil.
 func (result *PerformanceResult) Close(ctx context.Context, completedAt time.Time) error {
@@ -700,6 +748,17 @@ func (r *PerformanceResults) findAllChildrenGraphLookup(ctx context.Context, par
 // FindOutdatedRollups returns performance results with missing or outdated
 // rollup information for the given `name` and `version`.
 func (r *PerformanceResults) FindOutdatedRollups(ctx context.Context, name string, version int, after time.Time, failureLimit int) error {
+	return r.findOutdatedRollups(ctx, name, version, bson.M{"$lt": version}, after, failureLimit)
+}
+
+// FindOutdatedCustomRollups returns performance results with missing rollup
+// information for the given custom rollup `name` or whose rollup `version`
+// differs, since custom rollup versions are hashes of their definitions.
+func (r *PerformanceResults) FindOutdatedCustomRollups(ctx context.Context, name string, version int, after time.Time, failureLimit int) error {
+	return r.findOutdatedRollups(ctx, name, version, bson.M{"$ne": version}, after, failureLimit)
+}
+
+func (r *PerformanceResults) findOutdatedRollups(ctx context.Context, name string, version int, versionQuery bson.M, after time.Time, failureLimit int) error {
 	if r.env == nil {
 		return errors.New("cannot find outdated rollups with a nil env")
 	}
@@ -713,7 +772,7 @@ func (r *PerformanceResults) FindOutdatedRollups(ctx context.Context, name strin
 				bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey): bson.M{
 					"$elemMatch": bson.M{
 						perfRollupValueNameKey:    name,
-						perfRollupValueVersionKey: bson.M{"$lt": version},
+						perfRollupValueVersionKey: versionQuery,
 					},
 				},
 			},
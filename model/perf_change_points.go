@@ -0,0 +1,316 @@
+package model
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	perfChangePointsCollection    = "perf_change_points"
+	perfChangePointsMaxQueryLimit = 10000
+)
+
+// PerformanceChangePointTriageStatus describes how a user triaged a change
+// point.
+type PerformanceChangePointTriageStatus string
+
+// Triage statuses of a change point. New change points are untriaged.
+const (
+	PerformanceChangePointUntriaged    PerformanceChangePointTriageStatus = "untriaged"
+	PerformanceChangePointAcknowledged PerformanceChangePointTriageStatus = "acknowledged"
+	PerformanceChangePointHidden       PerformanceChangePointTriageStatus = "hidden"
+	PerformanceChangePointLinked       PerformanceChangePointTriageStatus = "linked"
+)
+
+// Validate ensures that the triage status is recognized.
+func (s PerformanceChangePointTriageStatus) Validate() error {
+	switch s {
+	case PerformanceChangePointUntriaged, PerformanceChangePointAcknowledged, PerformanceChangePointHidden, PerformanceChangePointLinked:
+		return nil
+	default:
+		return errors.Errorf("unrecognized change point triage status '%s'", s)
+	}
+}
+
+// PerformanceChangePointTriage describes the triage of a change point. A
+// change point is acknowledged as a real change, hidden as a false positive,
+// or linked to the tickets tracking it.
+type PerformanceChangePointTriage struct {
+	Status    PerformanceChangePointTriageStatus `bson:"status"`
+	TriagedBy string                             `bson:"triaged_by,omitempty"`
+	Notes     string                             `bson:"notes,omitempty"`
+	Tickets   []string                           `bson:"tickets,omitempty"`
+	TriagedAt time.Time                          `bson:"triaged_at,omitempty"`
+}
+
+var (
+	perfChangePointTriageStatusKey = bsonutil.MustHaveTag(PerformanceChangePointTriage{}, "Status")
+)
+
+// Validate ensures that the triage is valid.
+func (t PerformanceChangePointTriage) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.Add(t.Status.Validate())
+	catcher.NewWhen(t.Status == PerformanceChangePointLinked && len(t.Tickets) == 0, "must specify a ticket to link the change point to")
+
+	return catcher.Resolve()
+}
+
+// PerformanceChangePoint describes a change in the distribution of the values
+// of a performance result series, detected at the first order after the
+// change. The magnitude is the difference between the mean value of the
+// series after and before the change point and the confidence is the
+// estimated probability that the change is not due to noise.
+type PerformanceChangePoint struct {
+	ID         string                       `bson:"_id"`
+	SeriesID   PerformanceResultSeriesID    `bson:"series_id"`
+	Order      int                          `bson:"order"`
+	Version    string                       `bson:"version"`
+	Algorithm  string                       `bson:"algorithm"`
+	Magnitude  float64                      `bson:"magnitude"`
+	Confidence float64                      `bson:"confidence"`
+	DetectedAt time.Time                    `bson:"detected_at"`
+	Triage     PerformanceChangePointTriage `bson:"triage"`
+}
+
+var (
+	perfChangePointIDKey         = bsonutil.MustHaveTag(PerformanceChangePoint{}, "ID")
+	perfChangePointSeriesIDKey   = bsonutil.MustHaveTag(PerformanceChangePoint{}, "SeriesID")
+	perfChangePointOrderKey      = bsonutil.MustHaveTag(PerformanceChangePoint{}, "Order")
+	perfChangePointVersionKey    = bsonutil.MustHaveTag(PerformanceChangePoint{}, "Version")
+	perfChangePointAlgorithmKey  = bsonutil.MustHaveTag(PerformanceChangePoint{}, "Algorithm")
+	perfChangePointMagnitudeKey  = bsonutil.MustHaveTag(PerformanceChangePoint{}, "Magnitude")
+	perfChangePointConfidenceKey = bsonutil.MustHaveTag(PerformanceChangePoint{}, "Confidence")
+	perfChangePointDetectedAtKey = bsonutil.MustHaveTag(PerformanceChangePoint{}, "DetectedAt")
+	perfChangePointTriageKey     = bsonutil.MustHaveTag(PerformanceChangePoint{}, "Triage")
+
+	perfResultSeriesIDProjectKey     = bsonutil.MustHaveTag(PerformanceResultSeriesID{}, "Project")
+	perfResultSeriesIDVariantKey     = bsonutil.MustHaveTag(PerformanceResultSeriesID{}, "Variant")
+	perfResultSeriesIDTaskKey        = bsonutil.MustHaveTag(PerformanceResultSeriesID{}, "Task")
+	perfResultSeriesIDTestKey        = bsonutil.MustHaveTag(PerformanceResultSeriesID{}, "Test")
+	perfResultSeriesIDMeasurementKey = bsonutil.MustHaveTag(PerformanceResultSeriesID{}, "Measurement")
+	perfResultSeriesIDArgumentsKey   = bsonutil.MustHaveTag(PerformanceResultSeriesID{}, "Arguments")
+)
+
+// PerformanceChangePointID creates a unique hash for the change point of a
+// performance result series at the given order.
+func PerformanceChangePointID(id PerformanceResultSeriesID, order int) string {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, id.Project)
+	_, _ = io.WriteString(hash, id.Variant)
+	_, _ = io.WriteString(hash, id.Task)
+	_, _ = io.WriteString(hash, id.Test)
+	_, _ = io.WriteString(hash, id.Measurement)
+	_, _ = io.WriteString(hash, id.Arguments.String())
+	_, _ = io.WriteString(hash, fmt.Sprint(order))
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// ReplacePerformanceChangePoints stores the given change points detected in
+// the performance result series and removes the previously detected change
+// points of the series that were not detected again, unless they were
+// already triaged. Change points detected again keep their original
+// detection time and triage.
+func ReplacePerformanceChangePoints(ctx context.Context, env cedar.Environment, id PerformanceResultSeriesID, changePoints []PerformanceChangePoint) error {
+	if env == nil {
+		return errors.New("cannot replace performance change points with a nil environment")
+	}
+
+	collection := env.GetDB().Collection(perfChangePointsCollection)
+	ids := make([]string, len(changePoints))
+	for i, cp := range changePoints {
+		ids[i] = PerformanceChangePointID(id, cp.Order)
+		update := bson.M{
+			"$set": bson.M{
+				perfChangePointSeriesIDKey:   id,
+				perfChangePointOrderKey:      cp.Order,
+				perfChangePointVersionKey:    cp.Version,
+				perfChangePointAlgorithmKey:  cp.Algorithm,
+				perfChangePointMagnitudeKey:  cp.Magnitude,
+				perfChangePointConfidenceKey: cp.Confidence,
+			},
+			"$setOnInsert": bson.M{
+				perfChangePointDetectedAtKey: time.Now(),
+				perfChangePointTriageKey:     PerformanceChangePointTriage{Status: PerformanceChangePointUntriaged},
+			},
+		}
+
+		_, err := collection.UpdateOne(ctx, bson.M{perfChangePointIDKey: ids[i]}, update, options.Update().SetUpsert(true))
+		if err != nil {
+			return errors.Wrapf(err, "saving change point at order %d of performance result series '%s %s'", cp.Order, id, id.Measurement)
+		}
+	}
+
+	query := performanceResultSeriesQuery(id)
+	query[perfChangePointIDKey] = bson.M{"$nin": ids}
+	query[bsonutil.GetDottedKeyName(perfChangePointTriageKey, perfChangePointTriageStatusKey)] = PerformanceChangePointUntriaged
+	if _, err := collection.DeleteMany(ctx, query); err != nil {
+		return errors.Wrapf(err, "removing outdated change points of performance result series '%s %s'", id, id.Measurement)
+	}
+
+	return nil
+}
+
+// FindPerformanceChangePoints returns the change points detected in the
+// performance result series, sorted by order.
+func FindPerformanceChangePoints(ctx context.Context, env cedar.Environment, id PerformanceResultSeriesID) ([]PerformanceChangePoint, error) {
+	if env == nil {
+		return nil, errors.New("cannot find performance change points with a nil environment")
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: perfChangePointOrderKey, Value: 1}})
+	cur, err := env.GetDB().Collection(perfChangePointsCollection).Find(ctx, performanceResultSeriesQuery(id), opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding change points of performance result series '%s %s'", id, id.Measurement)
+	}
+	var changePoints []PerformanceChangePoint
+	if err = cur.All(ctx, &changePoints); err != nil {
+		return nil, errors.Wrapf(err, "decoding change points of performance result series '%s %s'", id, id.Measurement)
+	}
+
+	return changePoints, nil
+}
+
+// PerformanceChangePointsFilter represents search parameters when finding
+// the change points of a project.
+type PerformanceChangePointsFilter struct {
+	Project string
+	// Variant, Task, Test, and Measurement, if not empty, must equal the
+	// corresponding field of the change points' series.
+	Variant     string
+	Task        string
+	Test        string
+	Measurement string
+	// Statuses, if not empty, are the triage statuses of the change
+	// points.
+	Statuses []PerformanceChangePointTriageStatus
+
+	Limit int
+}
+
+// Validate ensures that the PerformanceChangePointsFilter is valid.
+func (f *PerformanceChangePointsFilter) Validate() error {
+	if f == nil {
+		return errors.New("performance change points filter should not be nil")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(f.Project == "", "missing Project value")
+	catcher.NewWhen(f.Limit > perfChangePointsMaxQueryLimit || f.Limit <= 0, "invalid Limit value")
+	for _, status := range f.Statuses {
+		catcher.Add(status.Validate())
+	}
+
+	return catcher.Resolve()
+}
+
+func (f *PerformanceChangePointsFilter) createFindQuery() bson.M {
+	seriesKey := func(key string) string {
+		return bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, key)
+	}
+
+	query := bson.M{seriesKey(perfResultSeriesIDProjectKey): f.Project}
+	if f.Variant != "" {
+		query[seriesKey(perfResultSeriesIDVariantKey)] = f.Variant
+	}
+	if f.Task != "" {
+		query[seriesKey(perfResultSeriesIDTaskKey)] = f.Task
+	}
+	if f.Test != "" {
+		query[seriesKey(perfResultSeriesIDTestKey)] = f.Test
+	}
+	if f.Measurement != "" {
+		query[seriesKey(perfResultSeriesIDMeasurementKey)] = f.Measurement
+	}
+	if len(f.Statuses) > 0 {
+		query[bsonutil.GetDottedKeyName(perfChangePointTriageKey, perfChangePointTriageStatusKey)] = bson.M{"$in": f.Statuses}
+	}
+
+	return query
+}
+
+// SearchPerformanceChangePoints returns the change points matching the
+// filter, most recently detected first.
+func SearchPerformanceChangePoints(ctx context.Context, env cedar.Environment, filter PerformanceChangePointsFilter) ([]PerformanceChangePoint, error) {
+	if env == nil {
+		return nil, errors.New("cannot search performance change points with a nil environment")
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, errors.Wrap(err, "the provided PerformanceChangePointsFilter is invalid")
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{
+			{Key: perfChangePointDetectedAtKey, Value: -1},
+			{Key: perfChangePointIDKey, Value: 1},
+		}).
+		SetLimit(int64(filter.Limit))
+	cur, err := env.GetDB().Collection(perfChangePointsCollection).Find(ctx, filter.createFindQuery(), opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding change points for project '%s'", filter.Project)
+	}
+	var changePoints []PerformanceChangePoint
+	if err = cur.All(ctx, &changePoints); err != nil {
+		return nil, errors.Wrapf(err, "decoding change points for project '%s'", filter.Project)
+	}
+
+	return changePoints, nil
+}
+
+// TriagePerformanceChangePoint sets the triage of the change point with the
+// given ID in the project and returns the updated change point. The triage
+// time is set to the current time. If the change point does not exist, the
+// returned error is mongo.ErrNoDocuments.
+func TriagePerformanceChangePoint(ctx context.Context, env cedar.Environment, project, id string, triage PerformanceChangePointTriage) (*PerformanceChangePoint, error) {
+	if env == nil {
+		return nil, errors.New("cannot triage performance change point with a nil environment")
+	}
+	if err := triage.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid change point triage")
+	}
+
+	triage.TriagedAt = time.Now()
+	query := bson.M{
+		perfChangePointIDKey: id,
+		bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, perfResultSeriesIDProjectKey): project,
+	}
+	changePoint := &PerformanceChangePoint{}
+	err := env.GetDB().Collection(perfChangePointsCollection).FindOneAndUpdate(
+		ctx,
+		query,
+		bson.M{"$set": bson.M{perfChangePointTriageKey: triage}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(changePoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "triaging change point '%s' of project '%s'", id, project)
+	}
+
+	return changePoint, nil
+}
+
+func performanceResultSeriesQuery(id PerformanceResultSeriesID) bson.M {
+	seriesKey := func(key string) string {
+		return bsonutil.GetDottedKeyName(perfChangePointSeriesIDKey, key)
+	}
+
+	return bson.M{
+		seriesKey(perfResultSeriesIDProjectKey):     id.Project,
+		seriesKey(perfResultSeriesIDVariantKey):     id.Variant,
+		seriesKey(perfResultSeriesIDTaskKey):        id.Task,
+		seriesKey(perfResultSeriesIDTestKey):        id.Test,
+		seriesKey(perfResultSeriesIDMeasurementKey): id.Measurement,
+		seriesKey(perfResultSeriesIDArgumentsKey):   id.Arguments,
+	}
+}
@@ -0,0 +1,285 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestChangeDetectorConfigValidate(t *testing.T) {
+	for _, implementation := range []string{"", ChangeDetectorImplementationSignalProcessing, ChangeDetectorImplementationLocal} {
+		assert.NoError(t, ChangeDetectorConfig{Implementation: implementation}.Validate())
+	}
+	assert.Error(t, ChangeDetectorConfig{Implementation: "DNE"}.Validate())
+	assert.True(t, ChangeDetectorConfig{Implementation: ChangeDetectorImplementationLocal}.IsLocal())
+	assert.False(t, ChangeDetectorConfig{}.IsLocal())
+}
+
+func TestPerformanceChangePointID(t *testing.T) {
+	id := PerformanceResultSeriesID{
+		Project:     "project",
+		Variant:     "variant",
+		Task:        "task",
+		Test:        "test",
+		Measurement: "ops_per_sec",
+		Arguments:   PerformanceArguments{"thread_level": 20, "batch_size": 8},
+	}
+	assert.Equal(t, PerformanceChangePointID(id, 10), PerformanceChangePointID(id, 10))
+	assert.NotEqual(t, PerformanceChangePointID(id, 10), PerformanceChangePointID(id, 11))
+
+	other := id
+	other.Measurement = "latency"
+	assert.NotEqual(t, PerformanceChangePointID(id, 10), PerformanceChangePointID(other, 10))
+	other = id
+	other.Arguments = PerformanceArguments{"thread_level": 20}
+	assert.NotEqual(t, PerformanceChangePointID(id, 10), PerformanceChangePointID(other, 10))
+}
+
+func TestPerformanceChangePointTriageValidate(t *testing.T) {
+	for _, triage := range []PerformanceChangePointTriage{
+		{Status: PerformanceChangePointUntriaged},
+		{Status: PerformanceChangePointAcknowledged, Notes: "notes"},
+		{Status: PerformanceChangePointHidden},
+		{Status: PerformanceChangePointLinked, Tickets: []string{"PERF-1"}},
+	} {
+		assert.NoError(t, triage.Validate())
+	}
+	assert.Error(t, PerformanceChangePointTriage{}.Validate())
+	assert.Error(t, PerformanceChangePointTriage{Status: "DNE"}.Validate())
+	assert.Error(t, PerformanceChangePointTriage{Status: PerformanceChangePointLinked}.Validate())
+}
+
+func TestPerformanceChangePointsFilterValidate(t *testing.T) {
+	valid := PerformanceChangePointsFilter{
+		Project:  "project",
+		Statuses: []PerformanceChangePointTriageStatus{PerformanceChangePointUntriaged, PerformanceChangePointLinked},
+		Limit:    10,
+	}
+	assert.NoError(t, valid.Validate())
+
+	for _, test := range []struct {
+		name   string
+		modify func(*PerformanceChangePointsFilter)
+	}{
+		{name: "MissingProject", modify: func(f *PerformanceChangePointsFilter) { f.Project = "" }},
+		{name: "ZeroLimit", modify: func(f *PerformanceChangePointsFilter) { f.Limit = 0 }},
+		{name: "LimitTooLarge", modify: func(f *PerformanceChangePointsFilter) { f.Limit = perfChangePointsMaxQueryLimit + 1 }},
+		{name: "InvalidStatus", modify: func(f *PerformanceChangePointsFilter) {
+			f.Statuses = []PerformanceChangePointTriageStatus{"DNE"}
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			filter := valid
+			test.modify(&filter)
+			assert.Error(t, filter.Validate())
+		})
+	}
+}
+
+func TestReplacePerformanceChangePoints(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(perfChangePointsCollection).Drop(ctx))
+	}()
+
+	id := PerformanceResultSeriesID{
+		Project:     "project",
+		Variant:     "variant",
+		Task:        "task",
+		Test:        "test",
+		Measurement: "ops_per_sec",
+		Arguments:   PerformanceArguments{"thread_level": 20},
+	}
+	otherID := id
+	otherID.Measurement = "latency"
+
+	t.Run("NilEnv", func(t *testing.T) {
+		assert.Error(t, ReplacePerformanceChangePoints(ctx, nil, id, nil))
+		_, err := FindPerformanceChangePoints(ctx, nil, id)
+		assert.Error(t, err)
+	})
+	t.Run("Save", func(t *testing.T) {
+		require.NoError(t, ReplacePerformanceChangePoints(ctx, env, id, []PerformanceChangePoint{
+			{Order: 20, Version: "version20", Algorithm: "algorithm", Magnitude: -5, Confidence: 0.99},
+			{Order: 10, Version: "version10", Algorithm: "algorithm", Magnitude: 10, Confidence: 0.97},
+		}))
+		require.NoError(t, ReplacePerformanceChangePoints(ctx, env, otherID, []PerformanceChangePoint{
+			{Order: 10, Version: "version10", Algorithm: "algorithm", Magnitude: 1, Confidence: 0.96},
+		}))
+
+		changePoints, err := FindPerformanceChangePoints(ctx, env, id)
+		require.NoError(t, err)
+		require.Len(t, changePoints, 2)
+		assert.Equal(t, PerformanceChangePointID(id, 10), changePoints[0].ID)
+		assert.Equal(t, id, changePoints[0].SeriesID)
+		assert.Equal(t, 10, changePoints[0].Order)
+		assert.Equal(t, "version10", changePoints[0].Version)
+		assert.Equal(t, "algorithm", changePoints[0].Algorithm)
+		assert.Equal(t, 10.0, changePoints[0].Magnitude)
+		assert.Equal(t, 0.97, changePoints[0].Confidence)
+		assert.True(t, time.Since(changePoints[0].DetectedAt) < time.Minute)
+		assert.Equal(t, PerformanceChangePointUntriaged, changePoints[0].Triage.Status)
+		assert.Equal(t, 20, changePoints[1].Order)
+	})
+	t.Run("Replace", func(t *testing.T) {
+		before, err := FindPerformanceChangePoints(ctx, env, id)
+		require.NoError(t, err)
+		require.Len(t, before, 2)
+
+		require.NoError(t, ReplacePerformanceChangePoints(ctx, env, id, []PerformanceChangePoint{
+			{Order: 10, Version: "version10", Algorithm: "algorithm", Magnitude: 11, Confidence: 0.98},
+			{Order: 30, Version: "version30", Algorithm: "algorithm", Magnitude: 3, Confidence: 0.99},
+		}))
+
+		changePoints, err := FindPerformanceChangePoints(ctx, env, id)
+		require.NoError(t, err)
+		require.Len(t, changePoints, 2)
+		assert.Equal(t, 10, changePoints[0].Order)
+		assert.Equal(t, 11.0, changePoints[0].Magnitude)
+		assert.Equal(t, before[0].DetectedAt, changePoints[0].DetectedAt)
+		assert.Equal(t, 30, changePoints[1].Order)
+
+		count, err := db.Collection(perfChangePointsCollection).CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+	})
+	t.Run("KeepsTriaged", func(t *testing.T) {
+		_, err := TriagePerformanceChangePoint(ctx, env, id.Project, PerformanceChangePointID(id, 30), PerformanceChangePointTriage{
+			Status:    PerformanceChangePointAcknowledged,
+			TriagedBy: "user",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, ReplacePerformanceChangePoints(ctx, env, id, []PerformanceChangePoint{
+			{Order: 10, Version: "version10", Algorithm: "algorithm", Magnitude: 11, Confidence: 0.98},
+		}))
+
+		changePoints, err := FindPerformanceChangePoints(ctx, env, id)
+		require.NoError(t, err)
+		require.Len(t, changePoints, 2)
+		assert.Equal(t, 30, changePoints[1].Order)
+		assert.Equal(t, PerformanceChangePointAcknowledged, changePoints[1].Triage.Status)
+		assert.Equal(t, "user", changePoints[1].Triage.TriagedBy)
+	})
+	t.Run("RemoveAll", func(t *testing.T) {
+		require.NoError(t, ReplacePerformanceChangePoints(ctx, env, id, nil))
+		changePoints, err := FindPerformanceChangePoints(ctx, env, id)
+		require.NoError(t, err)
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, PerformanceChangePointAcknowledged, changePoints[0].Triage.Status)
+
+		changePoints, err = FindPerformanceChangePoints(ctx, env, otherID)
+		require.NoError(t, err)
+		assert.Len(t, changePoints, 1)
+	})
+}
+
+func TestSearchAndTriagePerformanceChangePoints(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(perfChangePointsCollection).Drop(ctx))
+	}()
+
+	id := PerformanceResultSeriesID{
+		Project:     "project",
+		Variant:     "variant",
+		Task:        "task",
+		Test:        "test",
+		Measurement: "ops_per_sec",
+	}
+	otherVariantID := id
+	otherVariantID.Variant = "variant1"
+	otherProjectID := id
+	otherProjectID.Project = "project1"
+	for _, seriesID := range []PerformanceResultSeriesID{id, otherVariantID, otherProjectID} {
+		require.NoError(t, ReplacePerformanceChangePoints(ctx, env, seriesID, []PerformanceChangePoint{
+			{Order: 10, Version: "version10", Algorithm: "algorithm", Magnitude: 10, Confidence: 0.99},
+		}))
+	}
+
+	t.Run("NilEnv", func(t *testing.T) {
+		_, err := SearchPerformanceChangePoints(ctx, nil, PerformanceChangePointsFilter{Project: "project", Limit: 10})
+		assert.Error(t, err)
+		_, err = TriagePerformanceChangePoint(ctx, nil, "project", PerformanceChangePointID(id, 10), PerformanceChangePointTriage{Status: PerformanceChangePointHidden})
+		assert.Error(t, err)
+	})
+	t.Run("InvalidFilter", func(t *testing.T) {
+		_, err := SearchPerformanceChangePoints(ctx, env, PerformanceChangePointsFilter{Project: "project"})
+		assert.Error(t, err)
+	})
+	t.Run("InvalidTriage", func(t *testing.T) {
+		_, err := TriagePerformanceChangePoint(ctx, env, "project", PerformanceChangePointID(id, 10), PerformanceChangePointTriage{Status: PerformanceChangePointLinked})
+		assert.Error(t, err)
+	})
+	t.Run("TriageNotFound", func(t *testing.T) {
+		_, err := TriagePerformanceChangePoint(ctx, env, "project", PerformanceChangePointID(id, 20), PerformanceChangePointTriage{Status: PerformanceChangePointHidden})
+		assert.Equal(t, mongo.ErrNoDocuments, errors.Cause(err))
+		_, err = TriagePerformanceChangePoint(ctx, env, "project1", PerformanceChangePointID(id, 10), PerformanceChangePointTriage{Status: PerformanceChangePointHidden})
+		assert.Equal(t, mongo.ErrNoDocuments, errors.Cause(err))
+	})
+	t.Run("Triage", func(t *testing.T) {
+		triage := PerformanceChangePointTriage{
+			Status:    PerformanceChangePointLinked,
+			TriagedBy: "user",
+			Notes:     "notes",
+			Tickets:   []string{"PERF-1"},
+		}
+		changePoint, err := TriagePerformanceChangePoint(ctx, env, "project", PerformanceChangePointID(id, 10), triage)
+		require.NoError(t, err)
+		assert.Equal(t, PerformanceChangePointID(id, 10), changePoint.ID)
+		assert.Equal(t, triage.Status, changePoint.Triage.Status)
+		assert.Equal(t, triage.TriagedBy, changePoint.Triage.TriagedBy)
+		assert.Equal(t, triage.Notes, changePoint.Triage.Notes)
+		assert.Equal(t, triage.Tickets, changePoint.Triage.Tickets)
+		assert.True(t, time.Since(changePoint.Triage.TriagedAt) < time.Minute)
+	})
+	t.Run("SearchProject", func(t *testing.T) {
+		changePoints, err := SearchPerformanceChangePoints(ctx, env, PerformanceChangePointsFilter{Project: "project", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, changePoints, 2)
+		for _, cp := range changePoints {
+			assert.Equal(t, "project", cp.SeriesID.Project)
+		}
+	})
+	t.Run("SearchVariant", func(t *testing.T) {
+		changePoints, err := SearchPerformanceChangePoints(ctx, env, PerformanceChangePointsFilter{
+			Project:     "project",
+			Variant:     "variant1",
+			Task:        "task",
+			Test:        "test",
+			Measurement: "ops_per_sec",
+			Limit:       10,
+		})
+		require.NoError(t, err)
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, otherVariantID, changePoints[0].SeriesID)
+	})
+	t.Run("SearchStatus", func(t *testing.T) {
+		changePoints, err := SearchPerformanceChangePoints(ctx, env, PerformanceChangePointsFilter{
+			Project:  "project",
+			Statuses: []PerformanceChangePointTriageStatus{PerformanceChangePointLinked, PerformanceChangePointHidden},
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, PerformanceChangePointID(id, 10), changePoints[0].ID)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		changePoints, err := SearchPerformanceChangePoints(ctx, env, PerformanceChangePointsFilter{Project: "project", Limit: 1})
+		require.NoError(t, err)
+		assert.Len(t, changePoints, 1)
+	})
+}
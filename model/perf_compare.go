@@ -0,0 +1,366 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aclements/go-moremath/stats"
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultPerfComparisonSignificanceLevel = 0.05
+
+// PerformanceComparisonOptions describe the two sets of performance results
+// to compare: either the results of two versions or the results of two
+// tasks. Only the latest execution of each task is compared.
+type PerformanceComparisonOptions struct {
+	BaseVersion string
+	Version     string
+	BaseTaskID  string
+	TaskID      string
+	// SignificanceLevel is the p-value below which the difference between
+	// the trials of a test is significant. Defaults to 0.05.
+	SignificanceLevel float64
+}
+
+// Validate ensures that the PerformanceComparisonOptions are valid.
+func (opts *PerformanceComparisonOptions) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	byVersion := opts.BaseVersion != "" || opts.Version != ""
+	byTask := opts.BaseTaskID != "" || opts.TaskID != ""
+	catcher.NewWhen(byVersion == byTask, "must compare either two versions or two tasks")
+	catcher.NewWhen(byVersion && (opts.BaseVersion == "" || opts.Version == ""), "must specify both a base version and a version")
+	catcher.NewWhen(byTask && (opts.BaseTaskID == "" || opts.TaskID == ""), "must specify both a base task ID and a task ID")
+	catcher.NewWhen(opts.SignificanceLevel < 0 || opts.SignificanceLevel >= 1, "significance level must be between 0 and 1")
+
+	if opts.SignificanceLevel == 0 {
+		opts.SignificanceLevel = defaultPerfComparisonSignificanceLevel
+	}
+
+	return catcher.Resolve()
+}
+
+// PerformanceComparison is the comparison of the rollups of two sets of
+// performance results, paired by test.
+type PerformanceComparison struct {
+	BaseVersion string
+	Version     string
+	BaseTaskID  string
+	TaskID      string
+	Tests       []PerformanceTestComparison
+}
+
+// PerformanceTestComparison compares the rollups of the trials of a single
+// test, identified by its name and arguments and, when comparing versions,
+// by its variant and task name. Rollups summarizes the trials with the mean
+// of each rollup and, if both sides have several trials, the significance of
+// the difference.
+type PerformanceTestComparison struct {
+	Variant   string
+	TaskName  string
+	TestName  string
+	Arguments PerformanceArguments
+	Trials    []PerformanceTrialComparison
+	Rollups   []PerformanceRollupComparison
+}
+
+// PerformanceTrialComparison compares the rollups of a single trial of a
+// test. The ID of a side is empty if that side has no result for the trial.
+type PerformanceTrialComparison struct {
+	Trial   int
+	BaseID  string
+	ID      string
+	Rollups []PerformanceRollupComparison
+}
+
+// PerformanceRollupComparison compares the value of a rollup between the
+// base and the compared results. Values are nil if the rollup is missing or
+// has no value, and the deltas are nil unless both values exist. The percent
+// delta is also nil if the base value is 0. The counts are the number of
+// values compared on each side, and the p-value, from Welch's t-test, is
+// only set if both sides have at least two values.
+type PerformanceRollupComparison struct {
+	Name         string
+	BaseValue    *float64
+	Value        *float64
+	Delta        *float64
+	PercentDelta *float64
+	BaseCount    int
+	Count        int
+	PValue       *float64
+	Significant  bool
+}
+
+// ComparePerformanceResults finds and compares the performance results
+// described by the given options. If either side has no performance
+// results, the returned error's cause is mongo.ErrNoDocuments.
+func ComparePerformanceResults(ctx context.Context, env cedar.Environment, opts PerformanceComparisonOptions) (*PerformanceComparison, error) {
+	if env == nil {
+		return nil, errors.New("cannot compare performance results with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid performance comparison options")
+	}
+
+	find := func(version, taskID string) ([]PerformanceResult, error) {
+		results := PerformanceResults{}
+		results.Setup(env)
+		if err := results.Find(ctx, PerfFindOptions{Info: PerformanceResultInfo{Version: version, TaskID: taskID}}); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if len(results.Results) == 0 {
+			return nil, errors.Wrap(mongo.ErrNoDocuments, "no performance results found")
+		}
+		return results.Results, nil
+	}
+
+	base, err := find(opts.BaseVersion, opts.BaseTaskID)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding base performance results")
+	}
+	compare, err := find(opts.Version, opts.TaskID)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding performance results")
+	}
+
+	return ComparePerformanceResultSets(base, compare, opts), nil
+}
+
+// ComparePerformanceResultSets compares the rollups of the base and the
+// compared performance results, pairing them by test name, arguments and
+// trial and, when comparing versions, by variant and task name. Only the
+// latest execution of each task and only results with rollups are compared.
+// The options must be valid.
+func ComparePerformanceResultSets(base, compare []PerformanceResult, opts PerformanceComparisonOptions) *PerformanceComparison {
+	byVersion := opts.BaseVersion != ""
+	testKey := func(info PerformanceResultInfo) string {
+		key := fmt.Sprintf("%s|%s", info.TestName, info.Arguments.String())
+		if byVersion {
+			key = fmt.Sprintf("%s|%s|%s", info.Variant, info.TaskName, key)
+		}
+		return key
+	}
+
+	type trialPair struct {
+		base    *PerformanceResult
+		compare *PerformanceResult
+	}
+	tests := map[string]*PerformanceTestComparison{}
+	trials := map[string]map[int]*trialPair{}
+	add := func(result PerformanceResult, isBase bool) {
+		key := testKey(result.Info)
+		test, ok := tests[key]
+		if !ok {
+			test = &PerformanceTestComparison{TestName: result.Info.TestName, Arguments: result.Info.Arguments}
+			tests[key] = test
+			trials[key] = map[int]*trialPair{}
+		}
+		if !isBase || test.Variant == "" {
+			test.Variant = result.Info.Variant
+			test.TaskName = result.Info.TaskName
+		}
+
+		pair, ok := trials[key][result.Info.Trial]
+		if !ok {
+			pair = &trialPair{}
+			trials[key][result.Info.Trial] = pair
+		}
+		if isBase {
+			pair.base = &result
+		} else {
+			pair.compare = &result
+		}
+	}
+	for _, result := range latestPerformanceResultExecutions(base) {
+		add(result, true)
+	}
+	for _, result := range latestPerformanceResultExecutions(compare) {
+		add(result, false)
+	}
+
+	comparison := &PerformanceComparison{
+		BaseVersion: opts.BaseVersion,
+		Version:     opts.Version,
+		BaseTaskID:  opts.BaseTaskID,
+		TaskID:      opts.TaskID,
+		Tests:       []PerformanceTestComparison{},
+	}
+	for key, test := range tests {
+		baseValues := map[string][]float64{}
+		values := map[string][]float64{}
+		names := []string{}
+		seen := map[string]bool{}
+		for trial, pair := range trials[key] {
+			trialComparison := PerformanceTrialComparison{Trial: trial}
+			baseRollups, rollups := map[string]*float64{}, map[string]*float64{}
+			if pair.base != nil {
+				trialComparison.BaseID = pair.base.ID
+				baseRollups = perfRollupFloats(pair.base.Rollups.Stats)
+			}
+			if pair.compare != nil {
+				trialComparison.ID = pair.compare.ID
+				rollups = perfRollupFloats(pair.compare.Rollups.Stats)
+			}
+
+			for _, name := range sortedRollupNames(baseRollups, rollups) {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+				var baseValue, value []float64
+				if v := baseRollups[name]; v != nil {
+					baseValue = []float64{*v}
+					baseValues[name] = append(baseValues[name], *v)
+				}
+				if v := rollups[name]; v != nil {
+					value = []float64{*v}
+					values[name] = append(values[name], *v)
+				}
+				trialComparison.Rollups = append(trialComparison.Rollups, comparePerfRollup(name, baseValue, value, opts.SignificanceLevel))
+			}
+			test.Trials = append(test.Trials, trialComparison)
+		}
+		sort.Slice(test.Trials, func(i, j int) bool { return test.Trials[i].Trial < test.Trials[j].Trial })
+
+		sort.Strings(names)
+		for _, name := range names {
+			test.Rollups = append(test.Rollups, comparePerfRollup(name, baseValues[name], values[name], opts.SignificanceLevel))
+		}
+
+		comparison.Tests = append(comparison.Tests, *test)
+	}
+	sort.Slice(comparison.Tests, func(i, j int) bool {
+		a, b := comparison.Tests[i], comparison.Tests[j]
+		if a.Variant != b.Variant {
+			return a.Variant < b.Variant
+		}
+		if a.TaskName != b.TaskName {
+			return a.TaskName < b.TaskName
+		}
+		if a.TestName != b.TestName {
+			return a.TestName < b.TestName
+		}
+		return a.Arguments.String() < b.Arguments.String()
+	})
+
+	return comparison
+}
+
+// comparePerfRollup compares the mean of the base values of a rollup with
+// the mean of its compared values.
+func comparePerfRollup(name string, baseValues, values []float64, significanceLevel float64) PerformanceRollupComparison {
+	comparison := PerformanceRollupComparison{
+		Name:      name,
+		BaseCount: len(baseValues),
+		Count:     len(values),
+	}
+	if len(baseValues) > 0 {
+		mean := stats.Mean(baseValues)
+		comparison.BaseValue = &mean
+	}
+	if len(values) > 0 {
+		mean := stats.Mean(values)
+		comparison.Value = &mean
+	}
+	if comparison.BaseValue == nil || comparison.Value == nil {
+		return comparison
+	}
+
+	delta := *comparison.Value - *comparison.BaseValue
+	comparison.Delta = &delta
+	if *comparison.BaseValue != 0 {
+		percent := delta / math.Abs(*comparison.BaseValue) * 100
+		comparison.PercentDelta = &percent
+	}
+
+	if len(baseValues) < 2 || len(values) < 2 {
+		return comparison
+	}
+	var p float64
+	result, err := stats.TwoSampleWelchTTest(stats.Sample{Xs: baseValues}, stats.Sample{Xs: values}, stats.LocationDiffers)
+	switch {
+	case err == nil:
+		p = result.P
+	case err == stats.ErrZeroVariance && delta == 0:
+		// The trials are identical, so there is no difference.
+		p = 1
+	case err == stats.ErrZeroVariance:
+		// The trials of each side are noiseless but differ, so
+		// the difference is certain.
+		p = 0
+	default:
+		return comparison
+	}
+	comparison.PValue = &p
+	comparison.Significant = p < significanceLevel
+
+	return comparison
+}
+
+// latestPerformanceResultExecutions returns the results of the latest
+// execution of each task.
+func latestPerformanceResultExecutions(results []PerformanceResult) []PerformanceResult {
+	latest := map[string]int{}
+	for _, result := range results {
+		if execution, ok := latest[result.Info.TaskID]; !ok || result.Info.Execution > execution {
+			latest[result.Info.TaskID] = result.Info.Execution
+		}
+	}
+
+	filtered := []PerformanceResult{}
+	for _, result := range results {
+		if len(result.Rollups.Stats) == 0 || result.Info.Execution != latest[result.Info.TaskID] {
+			continue
+		}
+		filtered = append(filtered, result)
+	}
+
+	return filtered
+}
+
+// perfRollupFloats returns the rollup values by name, converted to floats.
+// Rollups without a numeric value map to nil.
+func perfRollupFloats(rollups []PerfRollupValue) map[string]*float64 {
+	values := map[string]*float64{}
+	for _, rollup := range rollups {
+		var value float64
+		switch v := rollup.Value.(type) {
+		case float64:
+			value = v
+		case int64:
+			value = float64(v)
+		case int32:
+			value = float64(v)
+		case int:
+			value = float64(v)
+		default:
+			values[rollup.Name] = nil
+			continue
+		}
+		values[rollup.Name] = &value
+	}
+
+	return values
+}
+
+func sortedRollupNames(rollups ...map[string]*float64) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, r := range rollups {
+		for name := range r {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
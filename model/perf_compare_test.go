@@ -0,0 +1,217 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformanceComparisonOptionsValidate(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		opts    PerformanceComparisonOptions
+		invalid bool
+	}{
+		{name: "Versions", opts: PerformanceComparisonOptions{BaseVersion: "base", Version: "patch"}},
+		{name: "Tasks", opts: PerformanceComparisonOptions{BaseTaskID: "base", TaskID: "patch", SignificanceLevel: 0.01}},
+		{name: "Empty", opts: PerformanceComparisonOptions{}, invalid: true},
+		{name: "MissingBaseVersion", opts: PerformanceComparisonOptions{Version: "patch"}, invalid: true},
+		{name: "MissingTaskID", opts: PerformanceComparisonOptions{BaseTaskID: "base"}, invalid: true},
+		{
+			name:    "VersionsAndTasks",
+			opts:    PerformanceComparisonOptions{BaseVersion: "base", Version: "patch", BaseTaskID: "base", TaskID: "patch"},
+			invalid: true,
+		},
+		{name: "InvalidSignificanceLevel", opts: PerformanceComparisonOptions{BaseVersion: "base", Version: "patch", SignificanceLevel: 1}, invalid: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.invalid {
+				assert.Error(t, test.opts.Validate())
+				return
+			}
+			assert.NoError(t, test.opts.Validate())
+			assert.NotZero(t, test.opts.SignificanceLevel)
+		})
+	}
+}
+
+func createComparisonPerformanceResult(version, taskName, testName string, execution, trial int, args PerformanceArguments, rollups map[string]interface{}) PerformanceResult {
+	stats := []PerfRollupValue{}
+	for name, value := range rollups {
+		stats = append(stats, PerfRollupValue{Name: name, Value: value, Version: 1, MetricType: MetricTypeMean})
+	}
+
+	return *CreatePerformanceResult(PerformanceResultInfo{
+		Project:   "project",
+		Version:   version,
+		Variant:   "variant",
+		TaskName:  taskName,
+		TaskID:    version + "_" + taskName,
+		Execution: execution,
+		TestName:  testName,
+		Trial:     trial,
+		Arguments: args,
+	}, nil, stats)
+}
+
+func TestComparePerformanceResultSets(t *testing.T) {
+	args := PerformanceArguments{"thread_level": 8}
+	base := []PerformanceResult{
+		createComparisonPerformanceResult("base", "task", "insert", 0, 0, args, map[string]interface{}{"ops": 100.0, "errors": int64(0)}),
+		createComparisonPerformanceResult("base", "task", "insert", 0, 1, args, map[string]interface{}{"ops": 102.0, "errors": int64(0)}),
+		createComparisonPerformanceResult("base", "task", "insert", 0, 2, args, map[string]interface{}{"ops": 98.0, "errors": int64(0)}),
+		createComparisonPerformanceResult("base", "task", "query", 0, 0, nil, map[string]interface{}{"ops": 50.0}),
+		createComparisonPerformanceResult("base", "other", "query", 0, 0, nil, map[string]interface{}{"ops": 10.0, "removed": 1.0}),
+		createComparisonPerformanceResult("base", "task", "parent", 0, 0, nil, nil),
+	}
+	patch := []PerformanceResult{
+		createComparisonPerformanceResult("patch", "task", "insert", 0, 0, args, map[string]interface{}{"ops": 1.0}),
+		createComparisonPerformanceResult("patch", "task", "insert", 1, 0, args, map[string]interface{}{"ops": 150.0, "errors": int64(0)}),
+		createComparisonPerformanceResult("patch", "task", "insert", 1, 1, args, map[string]interface{}{"ops": 151.0, "errors": int64(0)}),
+		createComparisonPerformanceResult("patch", "task", "insert", 1, 2, args, map[string]interface{}{"ops": 149.0, "errors": int64(0)}),
+		createComparisonPerformanceResult("patch", "task", "insert", 1, 0, PerformanceArguments{"thread_level": 16}, map[string]interface{}{"ops": 300.0}),
+		createComparisonPerformanceResult("patch", "task", "query", 1, 0, nil, map[string]interface{}{"ops": 40.0}),
+		createComparisonPerformanceResult("patch", "other", "query", 0, 0, nil, map[string]interface{}{"ops": 10.0}),
+	}
+
+	t.Run("Versions", func(t *testing.T) {
+		opts := PerformanceComparisonOptions{BaseVersion: "base", Version: "patch"}
+		require.NoError(t, opts.Validate())
+		comparison := ComparePerformanceResultSets(base, patch, opts)
+		assert.Equal(t, "base", comparison.BaseVersion)
+		assert.Equal(t, "patch", comparison.Version)
+		require.Len(t, comparison.Tests, 4)
+
+		other := comparison.Tests[0]
+		assert.Equal(t, "other", other.TaskName)
+		assert.Equal(t, "query", other.TestName)
+		require.Len(t, other.Rollups, 2)
+		assert.Equal(t, "ops", other.Rollups[0].Name)
+		require.NotNil(t, other.Rollups[0].Delta)
+		assert.Zero(t, *other.Rollups[0].Delta)
+		assert.Equal(t, "removed", other.Rollups[1].Name)
+		require.NotNil(t, other.Rollups[1].BaseValue)
+		assert.Nil(t, other.Rollups[1].Value)
+		assert.Nil(t, other.Rollups[1].Delta)
+		assert.Nil(t, other.Rollups[1].PercentDelta)
+
+		insert := comparison.Tests[2]
+		assert.Equal(t, "task", insert.TaskName)
+		assert.Equal(t, "insert", insert.TestName)
+		assert.Equal(t, args, insert.Arguments)
+		require.Len(t, insert.Trials, 3)
+		for i, trial := range insert.Trials {
+			assert.Equal(t, i, trial.Trial)
+			assert.NotEmpty(t, trial.BaseID)
+			assert.NotEmpty(t, trial.ID)
+			require.Len(t, trial.Rollups, 2)
+			assert.Equal(t, 1, trial.Rollups[1].BaseCount)
+			assert.Equal(t, 1, trial.Rollups[1].Count)
+			assert.Nil(t, trial.Rollups[1].PValue)
+		}
+		require.NotNil(t, insert.Trials[0].Rollups[1].Delta)
+		assert.Equal(t, 50.0, *insert.Trials[0].Rollups[1].Delta)
+		require.NotNil(t, insert.Trials[0].Rollups[1].PercentDelta)
+		assert.Equal(t, 50.0, *insert.Trials[0].Rollups[1].PercentDelta)
+
+		require.Len(t, insert.Rollups, 2)
+		errs := insert.Rollups[0]
+		assert.Equal(t, "errors", errs.Name)
+		require.NotNil(t, errs.Delta)
+		assert.Zero(t, *errs.Delta)
+		assert.Nil(t, errs.PercentDelta)
+		require.NotNil(t, errs.PValue)
+		assert.False(t, errs.Significant)
+		ops := insert.Rollups[1]
+		assert.Equal(t, "ops", ops.Name)
+		assert.Equal(t, 3, ops.BaseCount)
+		assert.Equal(t, 3, ops.Count)
+		require.NotNil(t, ops.BaseValue)
+		assert.Equal(t, 100.0, *ops.BaseValue)
+		require.NotNil(t, ops.Value)
+		assert.Equal(t, 150.0, *ops.Value)
+		require.NotNil(t, ops.PercentDelta)
+		assert.Equal(t, 50.0, *ops.PercentDelta)
+		require.NotNil(t, ops.PValue)
+		assert.True(t, *ops.PValue < 0.05)
+		assert.True(t, ops.Significant)
+
+		newArgs := comparison.Tests[1]
+		assert.Equal(t, "insert", newArgs.TestName)
+		assert.Equal(t, PerformanceArguments{"thread_level": 16}, newArgs.Arguments)
+		require.Len(t, newArgs.Trials, 1)
+		assert.Empty(t, newArgs.Trials[0].BaseID)
+		assert.NotEmpty(t, newArgs.Trials[0].ID)
+
+		query := comparison.Tests[3]
+		assert.Equal(t, "query", query.TestName)
+		require.Len(t, query.Rollups, 1)
+		require.NotNil(t, query.Rollups[0].PercentDelta)
+		assert.Equal(t, -20.0, *query.Rollups[0].PercentDelta)
+		assert.Nil(t, query.Rollups[0].PValue)
+		assert.False(t, query.Rollups[0].Significant)
+	})
+	t.Run("Tasks", func(t *testing.T) {
+		opts := PerformanceComparisonOptions{BaseTaskID: "base_task", TaskID: "patch_other"}
+		require.NoError(t, opts.Validate())
+		comparison := ComparePerformanceResultSets(base[3:4], patch[6:], opts)
+		assert.Equal(t, "base_task", comparison.BaseTaskID)
+		assert.Equal(t, "patch_other", comparison.TaskID)
+		require.Len(t, comparison.Tests, 1)
+		assert.Equal(t, "other", comparison.Tests[0].TaskName)
+		require.Len(t, comparison.Tests[0].Trials, 1)
+		assert.NotEmpty(t, comparison.Tests[0].Trials[0].BaseID)
+		assert.NotEmpty(t, comparison.Tests[0].Trials[0].ID)
+		require.Len(t, comparison.Tests[0].Rollups, 1)
+		require.NotNil(t, comparison.Tests[0].Rollups[0].Delta)
+		assert.Equal(t, -40.0, *comparison.Tests[0].Rollups[0].Delta)
+	})
+}
+
+func TestComparePerformanceResults(t *testing.T) {
+	env := cedar.GetEnvironment()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, env.GetDB().Collection(perfResultCollection).Drop(ctx))
+	}()
+
+	for _, result := range []PerformanceResult{
+		createComparisonPerformanceResult("base", "task", "insert", 0, 0, nil, map[string]interface{}{"ops": 100.0}),
+		createComparisonPerformanceResult("patch", "task", "insert", 0, 0, nil, map[string]interface{}{"ops": 110.0}),
+	} {
+		_, err := env.GetDB().Collection(perfResultCollection).InsertOne(ctx, result)
+		require.NoError(t, err)
+	}
+
+	t.Run("NilEnv", func(t *testing.T) {
+		_, err := ComparePerformanceResults(ctx, nil, PerformanceComparisonOptions{BaseVersion: "base", Version: "patch"})
+		assert.Error(t, err)
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		_, err := ComparePerformanceResults(ctx, env, PerformanceComparisonOptions{BaseVersion: "base"})
+		assert.Error(t, err)
+	})
+	t.Run("Versions", func(t *testing.T) {
+		comparison, err := ComparePerformanceResults(ctx, env, PerformanceComparisonOptions{BaseVersion: "base", Version: "patch"})
+		require.NoError(t, err)
+		require.Len(t, comparison.Tests, 1)
+		require.Len(t, comparison.Tests[0].Rollups, 1)
+		require.NotNil(t, comparison.Tests[0].Rollups[0].PercentDelta)
+		assert.InDelta(t, 10.0, *comparison.Tests[0].Rollups[0].PercentDelta, 1e-9)
+	})
+	t.Run("Tasks", func(t *testing.T) {
+		comparison, err := ComparePerformanceResults(ctx, env, PerformanceComparisonOptions{BaseTaskID: "base_task", TaskID: "patch_task"})
+		require.NoError(t, err)
+		require.Len(t, comparison.Tests, 1)
+		assert.Len(t, comparison.Tests[0].Trials, 1)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := ComparePerformanceResults(ctx, env, PerformanceComparisonOptions{BaseVersion: "base", Version: "DNE"})
+		assert.True(t, db.ResultsNotFound(err))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -358,3 +359,78 @@ func (s *perfRollupSuite) TestMergeRollups() {
 	result := &PerformanceResult{}
 	s.Error(result.MergeRollups(s.ctx, rollups))
 }
+
+func TestRollupConfigValidate(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		conf    RollupConfig
+		invalid bool
+	}{
+		{name: "Empty"},
+		{
+			name: "Valid",
+			conf: RollupConfig{Custom: []CustomRollup{
+				{Name: "ErrorRatio", Expression: "errors / operations"},
+				{Name: "Latency999thPercentile", Expression: "percentile(latency, 99.9)", MetricType: MetricTypeLatency},
+				{Name: "DurationSum", Expression: "sum(latency)", MetricType: MetricTypeSum},
+			}},
+		},
+		{
+			name:    "MissingName",
+			conf:    RollupConfig{Custom: []CustomRollup{{Expression: "errors"}}},
+			invalid: true,
+		},
+		{
+			name:    "MissingExpression",
+			conf:    RollupConfig{Custom: []CustomRollup{{Name: "Errors"}}},
+			invalid: true,
+		},
+		{
+			name:    "InvalidMetricType",
+			conf:    RollupConfig{Custom: []CustomRollup{{Name: "Errors", Expression: "errors", MetricType: "ratio"}}},
+			invalid: true,
+		},
+		{
+			name: "DuplicateNames",
+			conf: RollupConfig{Custom: []CustomRollup{
+				{Name: "Errors", Expression: "errors"},
+				{Name: "Errors", Expression: "errors * 2"},
+			}},
+			invalid: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.invalid {
+				assert.Error(t, test.conf.Validate())
+			} else {
+				assert.NoError(t, test.conf.Validate())
+			}
+		})
+	}
+}
+
+func TestCustomRollupVersion(t *testing.T) {
+	rollup := CustomRollup{Name: "ErrorRatio", Expression: "errors / operations"}
+	assert.Equal(t, rollup.Version(), rollup.Version())
+	assert.True(t, rollup.Version() >= 0)
+
+	for _, changed := range []CustomRollup{
+		{Name: "ErrorRate", Expression: rollup.Expression},
+		{Name: rollup.Name, Expression: "errors / (operations + errors)"},
+		{Name: rollup.Name, Expression: rollup.Expression, MetricType: MetricTypeThroughput},
+	} {
+		assert.NotEqual(t, rollup.Version(), changed.Version())
+	}
+	assert.Equal(t, rollup.Version(), CustomRollup{Name: rollup.Name, Expression: rollup.Expression, MetricType: MetricTypeMean}.Version())
+}
+
+func TestMetricTypeValidate(t *testing.T) {
+	assert.NoError(t, MetricTypeMean.Validate())
+	assert.NoError(t, MetricTypePercentile99.Validate())
+	// The additional metric types are only valid for custom rollups.
+	for _, metricType := range []MetricType{MetricTypeSum, MetricTypePercentile90, MetricTypeThroughput, MetricTypeLatency} {
+		assert.Error(t, metricType.Validate())
+		assert.NoError(t, validateCustomRollupMetricType(metricType))
+	}
+	assert.Error(t, validateCustomRollupMetricType("ratio"))
+}
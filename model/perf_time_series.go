@@ -0,0 +1,159 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PerformanceTimeSeriesPoint is the value of a single rollup of a
+// performance result in a performance result series.
+type PerformanceTimeSeriesPoint struct {
+	PerformanceResultID string    `bson:"perf_result_id"`
+	Order               int       `bson:"order"`
+	Version             string    `bson:"version"`
+	TaskID              string    `bson:"task_id"`
+	Execution           int       `bson:"execution"`
+	Value               float64   `bson:"value"`
+	CreatedAt           time.Time `bson:"created_at"`
+}
+
+var (
+	perfTimeSeriesPointPerformanceResultIDKey = bsonutil.MustHaveTag(PerformanceTimeSeriesPoint{}, "PerformanceResultID")
+	perfTimeSeriesPointOrderKey               = bsonutil.MustHaveTag(PerformanceTimeSeriesPoint{}, "Order")
+	perfTimeSeriesPointVersionKey             = bsonutil.MustHaveTag(PerformanceTimeSeriesPoint{}, "Version")
+	perfTimeSeriesPointTaskIDKey              = bsonutil.MustHaveTag(PerformanceTimeSeriesPoint{}, "TaskID")
+	perfTimeSeriesPointExecutionKey           = bsonutil.MustHaveTag(PerformanceTimeSeriesPoint{}, "Execution")
+	perfTimeSeriesPointValueKey               = bsonutil.MustHaveTag(PerformanceTimeSeriesPoint{}, "Value")
+	perfTimeSeriesPointCreatedAtKey           = bsonutil.MustHaveTag(PerformanceTimeSeriesPoint{}, "CreatedAt")
+)
+
+// PerformanceTimeSeriesOptions describe the performance time series to get
+// and the range of its points.
+type PerformanceTimeSeriesOptions struct {
+	ID PerformanceResultSeriesID
+	// IncludePatches, if true, also includes the values of non-mainline
+	// performance results.
+	IncludePatches bool
+	// MinOrder and MaxOrder, if not 0, bound the order of the points,
+	// inclusively.
+	MinOrder int
+	MaxOrder int
+	// Interval, if set, bounds the creation time of the points'
+	// performance results, inclusively. A zero start or end is unbounded.
+	Interval TimeRange
+	// Limit, if not 0, keeps only the latest points of the series.
+	Limit int
+}
+
+// Validate ensures that the PerformanceTimeSeriesOptions are valid.
+func (opts *PerformanceTimeSeriesOptions) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen(opts.ID.Measurement == "", "performance result series ID must specify a measurement")
+	catcher.NewWhen(opts.MinOrder < 0 || opts.MaxOrder < 0, "order bounds cannot be negative")
+	catcher.NewWhen(opts.MinOrder > 0 && opts.MaxOrder > 0 && opts.MinOrder > opts.MaxOrder, "min order cannot be greater than max order")
+	catcher.NewWhen(!opts.Interval.StartAt.IsZero() && !opts.Interval.EndAt.IsZero() && !opts.Interval.IsValid(), "time interval must have a start time before its end time")
+	catcher.NewWhen(opts.Limit < 0, "limit cannot be negative")
+
+	return catcher.Resolve()
+}
+
+// GetPerformanceTimeSeries returns the values of the series' measurement in
+// the performance results of the series, sorted by order. Only mainline
+// results are included unless specified otherwise. Results with the same
+// order, such as trials and task executions, each have their own point.
+func GetPerformanceTimeSeries(ctx context.Context, env cedar.Environment, opts PerformanceTimeSeriesOptions) ([]PerformanceTimeSeriesPoint, error) {
+	if env == nil {
+		return nil, errors.New("cannot get performance time series with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid performance time series options")
+	}
+
+	id := opts.ID
+	cur, err := env.GetDB().Collection(perfResultCollection).Aggregate(ctx, opts.pipeline())
+	if err != nil {
+		return nil, errors.Wrapf(err, "aggregating performance time series '%s %s'", id, id.Measurement)
+	}
+	var points []PerformanceTimeSeriesPoint
+	if err = cur.All(ctx, &points); err != nil {
+		return nil, errors.Wrapf(err, "decoding performance time series '%s %s'", id, id.Measurement)
+	}
+
+	return points, nil
+}
+
+func (opts *PerformanceTimeSeriesOptions) pipeline() []bson.M {
+	infoKey := func(key string) string {
+		return bsonutil.GetDottedKeyName(perfInfoKey, key)
+	}
+	statsKey := bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey)
+
+	orderQuery := bson.M{"$exists": true}
+	if opts.MinOrder > 0 {
+		orderQuery["$gte"] = opts.MinOrder
+	}
+	if opts.MaxOrder > 0 {
+		orderQuery["$lte"] = opts.MaxOrder
+	}
+	match := bson.M{
+		infoKey(perfResultInfoProjectKey):                           opts.ID.Project,
+		infoKey(perfResultInfoVariantKey):                           opts.ID.Variant,
+		infoKey(perfResultInfoTaskNameKey):                          opts.ID.Task,
+		infoKey(perfResultInfoTestNameKey):                          opts.ID.Test,
+		infoKey(perfResultInfoOrderKey):                             orderQuery,
+		infoKey(perfResultInfoArgumentsKey):                         opts.ID.Arguments,
+		bsonutil.GetDottedKeyName(statsKey, perfRollupValueNameKey): opts.ID.Measurement,
+	}
+	if !opts.IncludePatches {
+		match[infoKey(perfResultInfoMainlineKey)] = true
+	}
+	if !opts.Interval.StartAt.IsZero() || !opts.Interval.EndAt.IsZero() {
+		createdAtQuery := bson.M{}
+		if !opts.Interval.StartAt.IsZero() {
+			createdAtQuery["$gte"] = opts.Interval.StartAt
+		}
+		if !opts.Interval.EndAt.IsZero() {
+			createdAtQuery["$lte"] = opts.Interval.EndAt
+		}
+		match[perfCreatedAtKey] = createdAtQuery
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$unwind": "$" + statsKey},
+		{"$match": bson.M{bsonutil.GetDottedKeyName(statsKey, perfRollupValueNameKey): opts.ID.Measurement}},
+		{"$project": bson.M{
+			"_id": 0,
+			perfTimeSeriesPointPerformanceResultIDKey: "$" + perfIDKey,
+			perfTimeSeriesPointOrderKey:               "$" + infoKey(perfResultInfoOrderKey),
+			perfTimeSeriesPointVersionKey:             "$" + infoKey(perfResultInfoVersionKey),
+			perfTimeSeriesPointTaskIDKey:              "$" + infoKey(perfResultInfoTaskIDKey),
+			perfTimeSeriesPointExecutionKey:           "$" + infoKey(perfResultInfoExecutionKey),
+			perfTimeSeriesPointValueKey:               bson.M{"$toDouble": "$" + bsonutil.GetDottedKeyName(statsKey, perfRollupValueValueKey)},
+			perfTimeSeriesPointCreatedAtKey:           "$" + perfCreatedAtKey,
+		}},
+	}
+	sortAscending := bson.M{"$sort": bson.D{
+		{Key: perfTimeSeriesPointOrderKey, Value: 1},
+		{Key: perfTimeSeriesPointCreatedAtKey, Value: 1},
+	}}
+	if opts.Limit > 0 {
+		// Keep the latest points, then restore the ascending order.
+		pipeline = append(pipeline,
+			bson.M{"$sort": bson.D{
+				{Key: perfTimeSeriesPointOrderKey, Value: -1},
+				{Key: perfTimeSeriesPointCreatedAtKey, Value: -1},
+			}},
+			bson.M{"$limit": opts.Limit},
+		)
+	}
+
+	return append(pipeline, sortAscending)
+}
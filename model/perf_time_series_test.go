@@ -0,0 +1,167 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPerformanceTimeSeries(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(perfResultCollection).Drop(ctx))
+	}()
+
+	id := PerformanceResultSeriesID{
+		Project:     "project",
+		Variant:     "variant",
+		Task:        "task",
+		Test:        "test",
+		Measurement: "ops_per_sec",
+		Arguments:   PerformanceArguments{"thread_level": 20},
+	}
+	createdAt := time.Now().UTC().Round(time.Millisecond)
+	for _, test := range []struct {
+		order    int
+		trial    int
+		mainline bool
+		args     PerformanceArguments
+		value    interface{}
+	}{
+		{order: 2, mainline: true, args: id.Arguments, value: 20.5},
+		{order: 1, mainline: true, args: id.Arguments, value: int64(10)},
+		{order: 2, trial: 1, mainline: true, args: id.Arguments, value: int32(21)},
+		{order: 3, mainline: false, args: id.Arguments, value: 30.0},
+		{order: 4, mainline: true, args: PerformanceArguments{"thread_level": 1}, value: 40.0},
+	} {
+		result := CreatePerformanceResult(PerformanceResultInfo{
+			Project:   id.Project,
+			Version:   fmt.Sprintf("version%d", test.order),
+			Variant:   id.Variant,
+			Order:     test.order,
+			TaskName:  id.Task,
+			TaskID:    fmt.Sprintf("task%d", test.order),
+			TestName:  id.Test,
+			Trial:     test.trial,
+			Arguments: test.args,
+			Mainline:  test.mainline,
+		}, nil, []PerfRollupValue{
+			{Name: "latency", Value: 1.0, Version: 1, MetricType: MetricTypeLatency},
+			{Name: id.Measurement, Value: test.value, Version: 1, MetricType: MetricTypeThroughput},
+		})
+		result.CreatedAt = createdAt.Add(time.Duration(test.order) * time.Hour)
+		_, err := db.Collection(perfResultCollection).InsertOne(ctx, result)
+		require.NoError(t, err)
+	}
+
+	t.Run("NilEnv", func(t *testing.T) {
+		_, err := GetPerformanceTimeSeries(ctx, nil, PerformanceTimeSeriesOptions{ID: id})
+		assert.Error(t, err)
+	})
+	t.Run("MissingMeasurement", func(t *testing.T) {
+		noMeasurement := id
+		noMeasurement.Measurement = ""
+		_, err := GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{ID: noMeasurement})
+		assert.Error(t, err)
+	})
+	t.Run("MainlineSeries", func(t *testing.T) {
+		points, err := GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{ID: id})
+		require.NoError(t, err)
+		require.Len(t, points, 3)
+
+		assert.Equal(t, 1, points[0].Order)
+		assert.Equal(t, "version1", points[0].Version)
+		assert.Equal(t, "task1", points[0].TaskID)
+		assert.Equal(t, 10.0, points[0].Value)
+		assert.Equal(t, createdAt.Add(time.Hour), points[0].CreatedAt)
+		for _, point := range points[1:] {
+			assert.Equal(t, 2, point.Order)
+		}
+		assert.ElementsMatch(t, []float64{20.5, 21}, []float64{points[1].Value, points[2].Value})
+	})
+	t.Run("UnknownMeasurement", func(t *testing.T) {
+		unknown := id
+		unknown.Measurement = "DNE"
+		points, err := GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{ID: unknown})
+		require.NoError(t, err)
+		assert.Empty(t, points)
+	})
+	t.Run("IncludePatches", func(t *testing.T) {
+		points, err := GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{ID: id, IncludePatches: true})
+		require.NoError(t, err)
+		require.Len(t, points, 4)
+		assert.Equal(t, 3, points[3].Order)
+		assert.Equal(t, 30.0, points[3].Value)
+	})
+	t.Run("OrderRange", func(t *testing.T) {
+		points, err := GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{ID: id, IncludePatches: true, MinOrder: 2, MaxOrder: 2})
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		for _, point := range points {
+			assert.Equal(t, 2, point.Order)
+		}
+	})
+	t.Run("Interval", func(t *testing.T) {
+		points, err := GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{
+			ID:             id,
+			IncludePatches: true,
+			Interval:       TimeRange{StartAt: createdAt.Add(2 * time.Hour)},
+		})
+		require.NoError(t, err)
+		require.Len(t, points, 3)
+		assert.Equal(t, 2, points[0].Order)
+
+		points, err = GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{
+			ID:       id,
+			Interval: TimeRange{EndAt: createdAt.Add(time.Hour)},
+		})
+		require.NoError(t, err)
+		require.Len(t, points, 1)
+		assert.Equal(t, 1, points[0].Order)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		points, err := GetPerformanceTimeSeries(ctx, env, PerformanceTimeSeriesOptions{ID: id, IncludePatches: true, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		assert.Equal(t, 2, points[0].Order)
+		assert.Equal(t, 3, points[1].Order)
+	})
+}
+
+func TestPerformanceTimeSeriesOptionsValidate(t *testing.T) {
+	valid := PerformanceTimeSeriesOptions{
+		ID:       PerformanceResultSeriesID{Measurement: "ops_per_sec"},
+		MinOrder: 1,
+		MaxOrder: 10,
+		Interval: TimeRange{StartAt: time.Now().Add(-time.Hour), EndAt: time.Now()},
+		Limit:    10,
+	}
+	assert.NoError(t, valid.Validate())
+
+	for _, test := range []struct {
+		name   string
+		modify func(*PerformanceTimeSeriesOptions)
+	}{
+		{name: "MissingMeasurement", modify: func(opts *PerformanceTimeSeriesOptions) { opts.ID.Measurement = "" }},
+		{name: "NegativeOrder", modify: func(opts *PerformanceTimeSeriesOptions) { opts.MinOrder = -1 }},
+		{name: "MinOrderAfterMaxOrder", modify: func(opts *PerformanceTimeSeriesOptions) { opts.MinOrder = 11 }},
+		{name: "InvalidInterval", modify: func(opts *PerformanceTimeSeriesOptions) {
+			opts.Interval.StartAt, opts.Interval.EndAt = opts.Interval.EndAt, opts.Interval.StartAt
+		}},
+		{name: "NegativeLimit", modify: func(opts *PerformanceTimeSeriesOptions) { opts.Limit = -1 }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			opts := valid
+			test.modify(&opts)
+			assert.Error(t, opts.Validate())
+		})
+	}
+}
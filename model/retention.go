@@ -0,0 +1,199 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RetentionFindOptions describes the search criteria for finding data that
+// has expired according to a retention policy.
+type RetentionFindOptions struct {
+	// Project is the project of the expired data. If empty, data from any
+	// project not in ExcludeProjects matches.
+	Project         string
+	ExcludeProjects []string
+	// Mainline specifies whether to match mainline or patch data. Data
+	// without a mainline flag is considered patch data.
+	Mainline bool
+	// ExcludeIDs are the IDs of documents that should not match, such as
+	// those that previously failed to be removed.
+	ExcludeIDs []string
+	// CreatedBefore is the expiration cutoff, data created before it
+	// matches.
+	CreatedBefore time.Time
+	// Limit, when greater than 0, limits the number of documents returned.
+	Limit int
+}
+
+// Validate ensures that the RetentionFindOptions are valid.
+func (opts RetentionFindOptions) Validate() error {
+	if opts.CreatedBefore.IsZero() {
+		return errors.New("must specify an expiration cutoff")
+	}
+	if opts.Project != "" && len(opts.ExcludeProjects) > 0 {
+		return errors.New("cannot specify both a project and projects to exclude")
+	}
+
+	return nil
+}
+
+func (opts RetentionFindOptions) query(idKey, infoKey, projectKey, mainlineKey, createdAtKey string) bson.M {
+	query := bson.M{createdAtKey: bson.M{"$lt": opts.CreatedBefore}}
+	if opts.Mainline {
+		query[bsonutil.GetDottedKeyName(infoKey, mainlineKey)] = true
+	} else {
+		query[bsonutil.GetDottedKeyName(infoKey, mainlineKey)] = bson.M{"$ne": true}
+	}
+	if len(opts.ExcludeIDs) > 0 {
+		query[idKey] = bson.M{"$nin": opts.ExcludeIDs}
+	}
+	if opts.Project != "" {
+		query[bsonutil.GetDottedKeyName(infoKey, projectKey)] = opts.Project
+	} else if len(opts.ExcludeProjects) > 0 {
+		query[bsonutil.GetDottedKeyName(infoKey, projectKey)] = bson.M{"$nin": opts.ExcludeProjects}
+	}
+
+	return query
+}
+
+func (opts RetentionFindOptions) findOptions(createdAtKey string) *options.FindOptions {
+	findOpts := options.Find().SetSort(bson.M{createdAtKey: 1})
+	if opts.Limit > 0 {
+		findOpts.SetLimit(int64(opts.Limit))
+	}
+
+	return findOpts
+}
+
+// FindExpiredLogs returns the buildlogger logs that have expired according to
+// the given options, oldest first. The environment should not be nil.
+func FindExpiredLogs(ctx context.Context, env cedar.Environment, opts RetentionFindOptions) ([]Log, error) {
+	if env == nil {
+		return nil, errors.New("cannot find expired logs with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid retention find options")
+	}
+
+	cur, err := env.GetDB().Collection(buildloggerCollection).Find(
+		ctx,
+		opts.query(logIDKey, logInfoKey, logInfoProjectKey, logInfoMainlineKey, logCreatedAtKey),
+		opts.findOptions(logCreatedAtKey),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding expired logs")
+	}
+
+	var logs []Log
+	if err = cur.All(ctx, &logs); err != nil {
+		return nil, errors.Wrap(err, "decoding expired logs")
+	}
+	for i := range logs {
+		logs[i].Setup(env)
+		logs[i].populated = true
+	}
+
+	return logs, nil
+}
+
+// FindExpiredTestResults returns the test results records that have expired
+// according to the given options, oldest first. The environment should not be
+// nil.
+func FindExpiredTestResults(ctx context.Context, env cedar.Environment, opts RetentionFindOptions) ([]TestResults, error) {
+	if env == nil {
+		return nil, errors.New("cannot find expired test results with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid retention find options")
+	}
+
+	cur, err := env.GetDB().Collection(testResultsCollection).Find(
+		ctx,
+		opts.query(testResultsIDKey, testResultsInfoKey, testResultsInfoProjectKey, testResultsInfoMainlineKey, testResultsCreatedAtKey),
+		opts.findOptions(testResultsCreatedAtKey),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding expired test results")
+	}
+
+	var results []TestResults
+	if err = cur.All(ctx, &results); err != nil {
+		return nil, errors.Wrap(err, "decoding expired test results")
+	}
+	for i := range results {
+		results[i].Setup(env)
+		results[i].populated = true
+	}
+
+	return results, nil
+}
+
+// FindExpiredSystemMetrics returns the system metrics records that have
+// expired according to the given options, oldest first. The environment should
+// not be nil.
+func FindExpiredSystemMetrics(ctx context.Context, env cedar.Environment, opts RetentionFindOptions) ([]SystemMetrics, error) {
+	if env == nil {
+		return nil, errors.New("cannot find expired system metrics with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid retention find options")
+	}
+
+	cur, err := env.GetDB().Collection(systemMetricsCollection).Find(
+		ctx,
+		opts.query(systemMetricsIDKey, systemMetricsInfoKey, systemMetricsInfoProjectKey, systemMetricsInfoMainlineKey, systemMetricsCreatedAtKey),
+		opts.findOptions(systemMetricsCreatedAtKey),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding expired system metrics")
+	}
+
+	var metrics []SystemMetrics
+	if err = cur.All(ctx, &metrics); err != nil {
+		return nil, errors.Wrap(err, "decoding expired system metrics")
+	}
+	for i := range metrics {
+		metrics[i].Setup(env)
+		metrics[i].populated = true
+	}
+
+	return metrics, nil
+}
+
+// FindExpiredPerformanceResults returns the performance results that have
+// expired according to the given options, oldest first. The environment should
+// not be nil.
+func FindExpiredPerformanceResults(ctx context.Context, env cedar.Environment, opts RetentionFindOptions) ([]PerformanceResult, error) {
+	if env == nil {
+		return nil, errors.New("cannot find expired performance results with a nil environment")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid retention find options")
+	}
+
+	cur, err := env.GetDB().Collection(perfResultCollection).Find(
+		ctx,
+		opts.query(perfIDKey, perfInfoKey, perfResultInfoProjectKey, perfResultInfoMainlineKey, perfCreatedAtKey),
+		opts.findOptions(perfCreatedAtKey),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding expired performance results")
+	}
+
+	var results []PerformanceResult
+	if err = cur.All(ctx, &results); err != nil {
+		return nil, errors.Wrap(err, "decoding expired performance results")
+	}
+	for i := range results {
+		results[i].Setup(env)
+		results[i].populated = true
+	}
+
+	return results, nil
+}
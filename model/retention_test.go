@@ -0,0 +1,277 @@
+package model
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRetentionConfig(t *testing.T) {
+	t.Run("IsZero", func(t *testing.T) {
+		assert.True(t, RetentionConfig{}.IsZero())
+		assert.True(t, RetentionConfig{Projects: []ProjectRetentionPolicy{{Project: "p"}}}.IsZero())
+		assert.False(t, RetentionConfig{Default: RetentionPolicy{PatchDays: 1}}.IsZero())
+		assert.False(t, RetentionConfig{Projects: []ProjectRetentionPolicy{{Project: "p", Policy: RetentionPolicy{MainlineDays: 1}}}}.IsZero())
+	})
+	t.Run("Days", func(t *testing.T) {
+		policy := RetentionPolicy{MainlineDays: 90, PatchDays: 7}
+		assert.Equal(t, 90, policy.Days(true))
+		assert.Equal(t, 7, policy.Days(false))
+	})
+	t.Run("Validate", func(t *testing.T) {
+		for _, test := range []struct {
+			name    string
+			conf    RetentionConfig
+			invalid bool
+		}{
+			{name: "Empty"},
+			{
+				name: "Valid",
+				conf: RetentionConfig{
+					Default: RetentionPolicy{MainlineDays: 90, PatchDays: 7},
+					Projects: []ProjectRetentionPolicy{
+						{Project: "p0", Policy: RetentionPolicy{MainlineDays: 365}},
+						{Project: "p1"},
+					},
+				},
+			},
+			{
+				name:    "NegativeDefault",
+				conf:    RetentionConfig{Default: RetentionPolicy{PatchDays: -1}},
+				invalid: true,
+			},
+			{
+				name:    "MissingProject",
+				conf:    RetentionConfig{Projects: []ProjectRetentionPolicy{{Policy: RetentionPolicy{PatchDays: 1}}}},
+				invalid: true,
+			},
+			{
+				name:    "DuplicateProject",
+				conf:    RetentionConfig{Projects: []ProjectRetentionPolicy{{Project: "p"}, {Project: "p"}}},
+				invalid: true,
+			},
+			{
+				name:    "NegativeProject",
+				conf:    RetentionConfig{Projects: []ProjectRetentionPolicy{{Project: "p", Policy: RetentionPolicy{MainlineDays: -1}}}},
+				invalid: true,
+			},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				if test.invalid {
+					assert.Error(t, test.conf.Validate())
+				} else {
+					assert.NoError(t, test.conf.Validate())
+				}
+			})
+		}
+	})
+}
+
+func TestFindExpiredLogs(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(buildloggerCollection).Drop(ctx))
+	}()
+
+	now := time.Now().UTC().Round(time.Millisecond)
+	logs := []*Log{
+		CreateLog(LogInfo{Project: "p0", TaskID: "t0", Mainline: true}, PailLocal),
+		CreateLog(LogInfo{Project: "p0", TaskID: "t1"}, PailLocal),
+		CreateLog(LogInfo{Project: "p0", TaskID: "t2"}, PailLocal),
+		CreateLog(LogInfo{Project: "p1", TaskID: "t3"}, PailLocal),
+		CreateLog(LogInfo{Project: "p0", TaskID: "t4"}, PailLocal),
+	}
+	logs[0].CreatedAt = now.Add(-10 * 24 * time.Hour)
+	logs[1].CreatedAt = now.Add(-10 * 24 * time.Hour)
+	logs[2].CreatedAt = now.Add(-20 * 24 * time.Hour)
+	logs[3].CreatedAt = now.Add(-10 * 24 * time.Hour)
+	logs[4].CreatedAt = now
+	for _, log := range logs {
+		_, err := db.Collection(buildloggerCollection).InsertOne(ctx, log)
+		require.NoError(t, err)
+	}
+	cutoff := now.Add(-5 * 24 * time.Hour)
+
+	t.Run("NilEnv", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, nil, RetentionFindOptions{CreatedBefore: cutoff})
+		assert.Error(t, err)
+		assert.Nil(t, found)
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{})
+		assert.Error(t, err)
+		assert.Nil(t, found)
+
+		found, err = FindExpiredLogs(ctx, env, RetentionFindOptions{Project: "p0", ExcludeProjects: []string{"p1"}, CreatedBefore: cutoff})
+		assert.Error(t, err)
+		assert.Nil(t, found)
+	})
+	t.Run("Mainline", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{Mainline: true, CreatedBefore: cutoff})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, logs[0].ID, found[0].ID)
+		assert.Equal(t, env, found[0].env)
+		assert.True(t, found[0].populated)
+	})
+	t.Run("PatchOldestFirst", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{CreatedBefore: cutoff})
+		require.NoError(t, err)
+		require.Len(t, found, 3)
+		assert.Equal(t, logs[2].ID, found[0].ID)
+	})
+	t.Run("PatchMissingMainline", func(t *testing.T) {
+		log := CreateLog(LogInfo{Project: "p2", TaskID: "t5"}, PailLocal)
+		_, err := db.Collection(buildloggerCollection).InsertOne(ctx, bson.M{
+			"_id":        log.ID,
+			"info":       bson.M{"project": "p2"},
+			"created_at": now.Add(-10 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		defer func() {
+			_, err = db.Collection(buildloggerCollection).DeleteOne(ctx, bson.M{"_id": log.ID})
+			assert.NoError(t, err)
+		}()
+
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{Project: "p2", CreatedBefore: cutoff})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, log.ID, found[0].ID)
+
+		found, err = FindExpiredLogs(ctx, env, RetentionFindOptions{Project: "p2", Mainline: true, CreatedBefore: cutoff})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+	t.Run("ExcludeIDs", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{ExcludeIDs: []string{logs[2].ID}, CreatedBefore: cutoff})
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		for _, log := range found {
+			assert.NotEqual(t, logs[2].ID, log.ID)
+		}
+	})
+	t.Run("Project", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{Project: "p1", CreatedBefore: cutoff})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, logs[3].ID, found[0].ID)
+	})
+	t.Run("ExcludeProjects", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{ExcludeProjects: []string{"p1"}, CreatedBefore: cutoff})
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		for _, log := range found {
+			assert.Equal(t, "p0", log.Info.Project)
+		}
+	})
+	t.Run("Limit", func(t *testing.T) {
+		found, err := FindExpiredLogs(ctx, env, RetentionFindOptions{CreatedBefore: cutoff, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, logs[2].ID, found[0].ID)
+	})
+}
+
+func TestFindExpiredTestResults(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(testResultsCollection).Drop(ctx))
+	}()
+
+	now := time.Now().UTC().Round(time.Millisecond)
+	expired := CreateTestResults(TestResultsInfo{Project: "p0", TaskID: "t0"}, PailLocal)
+	expired.CreatedAt = now.Add(-10 * 24 * time.Hour)
+	current := CreateTestResults(TestResultsInfo{Project: "p0", TaskID: "t1"}, PailLocal)
+	current.CreatedAt = now
+	for _, record := range []*TestResults{expired, current} {
+		_, err := db.Collection(testResultsCollection).InsertOne(ctx, record)
+		require.NoError(t, err)
+	}
+
+	found, err := FindExpiredTestResults(ctx, env, RetentionFindOptions{CreatedBefore: now.Add(-5 * 24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, expired.ID, found[0].ID)
+	assert.True(t, found[0].populated)
+}
+
+func TestRemoveArtifactsEmptyPrefix(t *testing.T) {
+	env := cedar.GetEnvironment()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// An empty prefix would remove the whole bucket, so it should be
+	// rejected before the bucket is created.
+	t.Run("Log", func(t *testing.T) {
+		log := CreateLog(LogInfo{Project: "p0", TaskID: "t0"}, PailLocal)
+		log.Setup(env)
+		log.Artifact.Prefix = ""
+		assert.Error(t, log.RemoveArtifacts(ctx))
+	})
+	t.Run("TestResults", func(t *testing.T) {
+		record := CreateTestResults(TestResultsInfo{Project: "p0", TaskID: "t0"}, PailLocal)
+		record.Setup(env)
+		record.Artifact.Version = 0
+		record.Artifact.Prefix = ""
+		assert.Error(t, record.RemoveArtifacts(ctx))
+	})
+	t.Run("SystemMetrics", func(t *testing.T) {
+		sm := CreateSystemMetrics(SystemMetricsInfo{Project: "p0", TaskID: "t0"}, SystemMetricsArtifactOptions{Type: PailLocal})
+		sm.Setup(env)
+		sm.Artifact.Prefix = ""
+		assert.Error(t, sm.RemoveArtifacts(ctx))
+	})
+}
+
+func TestPerformanceResultRemoveArtifacts(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	managedDir, err := ioutil.TempDir(".", "perf-remove-artifacts-managed")
+	require.NoError(t, err)
+	clientDir, err := ioutil.TempDir(".", "perf-remove-artifacts-client")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(managedDir))
+		assert.NoError(t, os.RemoveAll(clientDir))
+		assert.NoError(t, db.Collection(configurationCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(perfResultCollection).Drop(ctx))
+	}()
+
+	conf := &CedarConfig{Bucket: BucketConfig{SystemMetricsBucket: managedDir}, populated: true}
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	artifacts := []ArtifactInfo{
+		{Type: PailLocal, Bucket: managedDir, Path: "managed.ftdc"},
+		{Type: PailLocal, Bucket: clientDir, Path: "client.ftdc"},
+	}
+	for _, artifact := range artifacts {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(artifact.Bucket, artifact.Path), []byte("data"), 0644))
+	}
+
+	result := CreatePerformanceResult(PerformanceResultInfo{Project: "p0", TaskID: "t0"}, artifacts, nil)
+	result.Setup(env)
+	require.NoError(t, result.SaveNew(ctx))
+	require.NoError(t, result.RemoveArtifacts(ctx))
+
+	_, err = os.Stat(filepath.Join(managedDir, "managed.ftdc"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(clientDir, "client.ftdc"))
+	assert.NoError(t, err)
+}
@@ -211,6 +211,43 @@ func (sm *SystemMetrics) Remove(ctx context.Context) error {
 	return errors.Wrapf(err, "removing system metrics record '%s'", sm.ID)
 }
 
+// RemoveArtifacts removes the system metrics data from the offline blob
+// storage bucket configured for the system metrics. The environment should not
+// be nil.
+func (sm *SystemMetrics) RemoveArtifacts(ctx context.Context) error {
+	if sm.env == nil {
+		return errors.New("cannot remove system metrics artifacts with a nil environment")
+	}
+
+	if sm.ID == "" {
+		sm.ID = sm.Info.ID()
+	}
+	// Removing an empty prefix would remove every record in the bucket.
+	if sm.Artifact.Prefix == "" {
+		return errors.Errorf("cannot remove artifacts for system metrics record '%s' without an artifact prefix", sm.ID)
+	}
+
+	conf := &CedarConfig{}
+	conf.Setup(sm.env)
+	if err := conf.Find(); err != nil {
+		return errors.Wrap(err, "getting application configuration")
+	}
+
+	bucket, err := sm.Artifact.Options.Type.Create(
+		ctx,
+		sm.env,
+		conf.Bucket.SystemMetricsBucket,
+		sm.Artifact.Prefix,
+		string(pail.S3PermissionsPrivate),
+		false,
+	)
+	if err != nil {
+		return errors.Wrap(err, "creating bucket")
+	}
+
+	return errors.Wrapf(bucket.RemovePrefix(ctx, ""), "removing artifacts for system metrics record '%s'", sm.ID)
+}
+
 // Append uploads a chunk of system metrics data to the offline blob storage
 // bucket configured for the system metrics and updates the metadata in the
 // DB to reflect the uploaded data. The environment should not be nil.
@@ -0,0 +1,433 @@
+package model
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testAnnotationsCollection = "test_annotations"
+
+// TestAnnotation attaches a note, ticket links, and a quarantine flag to a
+// test, keyed by project, variant, task name, and test display name. An
+// empty variant or task name applies the annotation to the test in all
+// variants or tasks of the project, respectively. Annotations with an expiry
+// stop applying, and are eventually removed, once they expire.
+type TestAnnotation struct {
+	ID            string    `bson:"_id"`
+	Project       string    `bson:"project"`
+	Variant       string    `bson:"variant"`
+	TaskName      string    `bson:"task_name"`
+	TestName      string    `bson:"test_name"`
+	Note          string    `bson:"note,omitempty"`
+	Tickets       []string  `bson:"tickets,omitempty"`
+	Quarantined   bool      `bson:"quarantined"`
+	ExpiresAt     time.Time `bson:"expires_at,omitempty"`
+	CreatedBy     string    `bson:"created_by"`
+	CreatedAt     time.Time `bson:"created_at"`
+	LastUpdatedBy string    `bson:"last_updated_by"`
+	LastUpdate    time.Time `bson:"last_update"`
+
+	env       cedar.Environment
+	populated bool
+}
+
+var (
+	testAnnotationIDKey            = bsonutil.MustHaveTag(TestAnnotation{}, "ID")
+	testAnnotationProjectKey       = bsonutil.MustHaveTag(TestAnnotation{}, "Project")
+	testAnnotationVariantKey       = bsonutil.MustHaveTag(TestAnnotation{}, "Variant")
+	testAnnotationTaskNameKey      = bsonutil.MustHaveTag(TestAnnotation{}, "TaskName")
+	testAnnotationTestNameKey      = bsonutil.MustHaveTag(TestAnnotation{}, "TestName")
+	testAnnotationNoteKey          = bsonutil.MustHaveTag(TestAnnotation{}, "Note")
+	testAnnotationTicketsKey       = bsonutil.MustHaveTag(TestAnnotation{}, "Tickets")
+	testAnnotationQuarantinedKey   = bsonutil.MustHaveTag(TestAnnotation{}, "Quarantined")
+	testAnnotationExpiresAtKey     = bsonutil.MustHaveTag(TestAnnotation{}, "ExpiresAt")
+	testAnnotationCreatedByKey     = bsonutil.MustHaveTag(TestAnnotation{}, "CreatedBy")
+	testAnnotationCreatedAtKey     = bsonutil.MustHaveTag(TestAnnotation{}, "CreatedAt")
+	testAnnotationLastUpdatedByKey = bsonutil.MustHaveTag(TestAnnotation{}, "LastUpdatedBy")
+	testAnnotationLastUpdateKey    = bsonutil.MustHaveTag(TestAnnotation{}, "LastUpdate")
+)
+
+// TestAnnotationID creates a unique hash for the annotation of a test.
+func TestAnnotationID(project, variant, taskName, testName string) string {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, project)
+	_, _ = io.WriteString(hash, variant)
+	_, _ = io.WriteString(hash, taskName)
+	_, _ = io.WriteString(hash, testName)
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// Setup sets the environment. The environment is required for numerous
+// functions on TestAnnotation.
+func (a *TestAnnotation) Setup(e cedar.Environment) { a.env = e }
+
+// IsNil returns if the TestAnnotation is populated or not.
+func (a *TestAnnotation) IsNil() bool { return !a.populated }
+
+// IsExpired returns whether the TestAnnotation has expired at the given time.
+func (a *TestAnnotation) IsExpired(at time.Time) bool {
+	return !a.ExpiresAt.IsZero() && !a.ExpiresAt.After(at)
+}
+
+// Validate ensures that the TestAnnotation is valid.
+func (a *TestAnnotation) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen(a.Project == "", "must specify a project")
+	catcher.NewWhen(a.TestName == "", "must specify a test name")
+	catcher.NewWhen(a.Note == "" && len(a.Tickets) == 0 && !a.Quarantined, "must specify a note, a ticket, or a quarantine")
+	catcher.NewWhen(a.IsExpired(time.Now()), "expiry must be in the future")
+
+	return catcher.Resolve()
+}
+
+// Find searches the DB for the TestAnnotation by ID. The environment should
+// not be nil.
+func (a *TestAnnotation) Find(ctx context.Context) error {
+	if a.env == nil {
+		return errors.New("cannot find with a nil environment")
+	}
+
+	if a.ID == "" {
+		a.ID = TestAnnotationID(a.Project, a.Variant, a.TaskName, a.TestName)
+	}
+
+	a.populated = false
+	if err := a.env.GetDB().Collection(testAnnotationsCollection).FindOne(ctx, bson.M{testAnnotationIDKey: a.ID}).Decode(a); err != nil {
+		return errors.Wrapf(err, "finding test annotation '%s'", a.ID)
+	}
+	a.populated = true
+
+	return nil
+}
+
+// Save validates and upserts the TestAnnotation, replacing the note,
+// tickets, quarantine, and expiry of the test, if any. The creation fields
+// are only set when the annotation is first created. The environment should
+// not be nil.
+func (a *TestAnnotation) Save(ctx context.Context) error {
+	if a.env == nil {
+		return errors.New("cannot save with a nil environment")
+	}
+	if err := a.Validate(); err != nil {
+		return errors.Wrap(err, "invalid test annotation")
+	}
+
+	a.ID = TestAnnotationID(a.Project, a.Variant, a.TaskName, a.TestName)
+	a.LastUpdate = time.Now()
+
+	set := bson.M{
+		testAnnotationProjectKey:       a.Project,
+		testAnnotationVariantKey:       a.Variant,
+		testAnnotationTaskNameKey:      a.TaskName,
+		testAnnotationTestNameKey:      a.TestName,
+		testAnnotationNoteKey:          a.Note,
+		testAnnotationTicketsKey:       a.Tickets,
+		testAnnotationQuarantinedKey:   a.Quarantined,
+		testAnnotationLastUpdatedByKey: a.LastUpdatedBy,
+		testAnnotationLastUpdateKey:    a.LastUpdate,
+	}
+	unset := bson.M{}
+	if a.ExpiresAt.IsZero() {
+		unset[testAnnotationExpiresAtKey] = 1
+	} else {
+		set[testAnnotationExpiresAtKey] = a.ExpiresAt
+	}
+	update := bson.M{
+		"$set": set,
+		"$setOnInsert": bson.M{
+			testAnnotationCreatedByKey: a.LastUpdatedBy,
+			testAnnotationCreatedAtKey: a.LastUpdate,
+		},
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	_, err := a.env.GetDB().Collection(testAnnotationsCollection).UpdateOne(ctx, bson.M{testAnnotationIDKey: a.ID}, update, options.Update().SetUpsert(true))
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection": testAnnotationsCollection,
+		"id":         a.ID,
+		"project":    a.Project,
+		"test_name":  a.TestName,
+		"op":         "save test annotation",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "saving test annotation '%s'", a.ID)
+	}
+
+	return a.Find(ctx)
+}
+
+// Remove removes the TestAnnotation from the DB. The environment should not
+// be nil.
+func (a *TestAnnotation) Remove(ctx context.Context) error {
+	if a.env == nil {
+		return errors.New("cannot remove with a nil environment")
+	}
+
+	if a.ID == "" {
+		a.ID = TestAnnotationID(a.Project, a.Variant, a.TaskName, a.TestName)
+	}
+
+	deleteResult, err := a.env.GetDB().Collection(testAnnotationsCollection).DeleteOne(ctx, bson.M{testAnnotationIDKey: a.ID})
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection":   testAnnotationsCollection,
+		"id":           a.ID,
+		"deleteResult": deleteResult,
+		"op":           "remove test annotation",
+	})
+
+	return errors.Wrapf(err, "removing test annotation '%s'", a.ID)
+}
+
+// TestAnnotationsFilter represents a filter for finding the test annotations
+// of a project.
+type TestAnnotationsFilter struct {
+	Project string
+	// Variant, TaskName, and TestName, if not empty, must equal the
+	// corresponding field of the annotations.
+	Variant  string
+	TaskName string
+	TestName string
+	// QuarantinedOnly, if true, only finds quarantine annotations.
+	QuarantinedOnly bool
+	// IncludeExpired, if true, also finds expired annotations that have
+	// not been removed yet.
+	IncludeExpired bool
+}
+
+func (f TestAnnotationsFilter) createFindQuery() bson.M {
+	query := bson.M{testAnnotationProjectKey: f.Project}
+	if f.Variant != "" {
+		query[testAnnotationVariantKey] = f.Variant
+	}
+	if f.TaskName != "" {
+		query[testAnnotationTaskNameKey] = f.TaskName
+	}
+	if f.TestName != "" {
+		query[testAnnotationTestNameKey] = f.TestName
+	}
+	if f.QuarantinedOnly {
+		query[testAnnotationQuarantinedKey] = true
+	}
+	if !f.IncludeExpired {
+		query["$or"] = []bson.M{
+			{testAnnotationExpiresAtKey: bson.M{"$exists": false}},
+			{testAnnotationExpiresAtKey: bson.M{"$gt": time.Now()}},
+		}
+	}
+
+	return query
+}
+
+// FindTestAnnotations returns the test annotations matching the filter,
+// sorted by test name, variant, and task name. The environment should not be
+// nil.
+func FindTestAnnotations(ctx context.Context, env cedar.Environment, f TestAnnotationsFilter) ([]TestAnnotation, error) {
+	if env == nil {
+		return nil, errors.New("cannot find with a nil environment")
+	}
+	if f.Project == "" {
+		return nil, errors.New("must specify a project")
+	}
+
+	cur, err := env.GetDB().Collection(testAnnotationsCollection).Find(
+		ctx,
+		f.createFindQuery(),
+		options.Find().SetSort(bson.D{
+			{Key: testAnnotationTestNameKey, Value: 1},
+			{Key: testAnnotationVariantKey, Value: 1},
+			{Key: testAnnotationTaskNameKey, Value: 1},
+		}),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding test annotations for project '%s'", f.Project)
+	}
+
+	var annotations []TestAnnotation
+	if err = cur.All(ctx, &annotations); err != nil {
+		return nil, errors.Wrapf(err, "decoding test annotations for project '%s'", f.Project)
+	}
+	for i := range annotations {
+		annotations[i].env = env
+		annotations[i].populated = true
+	}
+
+	return annotations, nil
+}
+
+// TestQuarantines is the set of active quarantines of a project's tests.
+type TestQuarantines struct {
+	keys map[testQuarantineKey]bool
+}
+
+type testQuarantineKey struct {
+	variant  string
+	taskName string
+	testName string
+}
+
+// NewTestQuarantines returns the set of quarantines of the given
+// annotations, ignoring the annotations that are not quarantines.
+func NewTestQuarantines(annotations []TestAnnotation) *TestQuarantines {
+	q := &TestQuarantines{keys: map[testQuarantineKey]bool{}}
+	for _, annotation := range annotations {
+		if annotation.Quarantined {
+			q.keys[testQuarantineKey{
+				variant:  annotation.Variant,
+				taskName: annotation.TaskName,
+				testName: annotation.TestName,
+			}] = true
+		}
+	}
+
+	return q
+}
+
+// GetTestQuarantines returns the set of active quarantines of the given
+// project's tests. The environment should not be nil.
+func GetTestQuarantines(ctx context.Context, env cedar.Environment, project string) (*TestQuarantines, error) {
+	annotations, err := FindTestAnnotations(ctx, env, TestAnnotationsFilter{Project: project, QuarantinedOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTestQuarantines(annotations), nil
+}
+
+// IsEmpty returns whether there are no quarantines in the set. A nil
+// TestQuarantines is empty.
+func (q *TestQuarantines) IsEmpty() bool {
+	return q == nil || len(q.keys) == 0
+}
+
+// IsQuarantined returns whether the test with the given display name is
+// quarantined in the given variant and task.
+func (q *TestQuarantines) IsQuarantined(variant, taskName, testName string) bool {
+	if q.IsEmpty() {
+		return false
+	}
+
+	for _, key := range []testQuarantineKey{
+		{variant: variant, taskName: taskName, testName: testName},
+		{variant: variant, testName: testName},
+		{taskName: taskName, testName: testName},
+		{testName: testName},
+	} {
+		if q.keys[key] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getTestQuarantinesByProject returns the active quarantines of the projects
+// of the given test results records, keyed by project.
+func getTestQuarantinesByProject(ctx context.Context, env cedar.Environment, records []TestResults) (map[string]*TestQuarantines, error) {
+	quarantines := map[string]*TestQuarantines{}
+	for _, record := range records {
+		if _, ok := quarantines[record.Info.Project]; ok {
+			continue
+		}
+
+		q, err := GetTestQuarantines(ctx, env, record.Info.Project)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting test quarantines for project '%s'", record.Info.Project)
+		}
+		quarantines[record.Info.Project] = q
+	}
+
+	return quarantines, nil
+}
+
+// countQuarantinedFailures counts the quarantined tests of the failed tests
+// of the given records. The failed tests sample of a record is used when it
+// includes every failed test, otherwise the record's failed test results are
+// streamed from the offline blob storage.
+func countQuarantinedFailures(ctx context.Context, env cedar.Environment, records []TestResults) (int, error) {
+	quarantines, err := getTestQuarantinesByProject(ctx, env, records)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		count      int
+		incomplete []TestResults
+	)
+	for _, record := range records {
+		q := quarantines[record.Info.Project]
+		if q.IsEmpty() || record.Stats.FailedCount == 0 {
+			continue
+		}
+		if len(record.FailedTestsSample) < record.Stats.FailedCount {
+			incomplete = append(incomplete, record)
+			continue
+		}
+		for _, name := range record.FailedTestsSample {
+			if q.IsQuarantined(record.Info.Variant, record.Info.TaskName, name) {
+				count++
+			}
+		}
+	}
+	if len(incomplete) == 0 {
+		return count, nil
+	}
+
+	err = streamTestResults(ctx, incomplete, &TestResultsIteratorFilter{FailedOnly: true}, func(item testResultsPageItem) {
+		record := incomplete[item.record]
+		if quarantines[record.Info.Project].IsQuarantined(record.Info.Variant, record.Info.TaskName, item.result.GetDisplayName()) {
+			count++
+		}
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "streaming failed test results")
+	}
+
+	return count, nil
+}
+
+type testResultsSampleKey struct {
+	taskID    string
+	execution int
+	testName  string
+}
+
+// quarantinedFailedTestsSample returns the quarantined tests of the failed
+// tests samples of the given records, keyed by both the task ID and display
+// task ID of the records.
+func quarantinedFailedTestsSample(ctx context.Context, env cedar.Environment, records []TestResults) (map[testResultsSampleKey]bool, error) {
+	quarantines, err := getTestQuarantinesByProject(ctx, env, records)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantined := map[testResultsSampleKey]bool{}
+	for _, record := range records {
+		q := quarantines[record.Info.Project]
+		for _, name := range record.FailedTestsSample {
+			if !q.IsQuarantined(record.Info.Variant, record.Info.TaskName, name) {
+				continue
+			}
+			quarantined[testResultsSampleKey{taskID: record.Info.TaskID, execution: record.Info.Execution, testName: name}] = true
+			if record.Info.DisplayTaskID != "" {
+				quarantined[testResultsSampleKey{taskID: record.Info.DisplayTaskID, execution: record.Info.Execution, testName: name}] = true
+			}
+		}
+	}
+
+	return quarantined, nil
+}
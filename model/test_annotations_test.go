@@ -0,0 +1,335 @@
+package model
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTestAnnotationValidate(t *testing.T) {
+	for testName, testCase := range map[string]struct {
+		annotation TestAnnotation
+		hasErr     bool
+	}{
+		"Note": {
+			annotation: TestAnnotation{Project: "p", TestName: "TestFoo", Note: "note"},
+		},
+		"Tickets": {
+			annotation: TestAnnotation{Project: "p", Variant: "v", TestName: "TestFoo", Tickets: []string{"BF-1"}},
+		},
+		"QuarantineWithExpiry": {
+			annotation: TestAnnotation{Project: "p", TaskName: "t", TestName: "TestFoo", Quarantined: true, ExpiresAt: time.Now().Add(time.Hour)},
+		},
+		"MissingProject": {
+			annotation: TestAnnotation{TestName: "TestFoo", Note: "note"},
+			hasErr:     true,
+		},
+		"MissingTestName": {
+			annotation: TestAnnotation{Project: "p", Note: "note"},
+			hasErr:     true,
+		},
+		"Empty": {
+			annotation: TestAnnotation{Project: "p", TestName: "TestFoo"},
+			hasErr:     true,
+		},
+		"Expired": {
+			annotation: TestAnnotation{Project: "p", TestName: "TestFoo", Quarantined: true, ExpiresAt: time.Now().Add(-time.Hour)},
+			hasErr:     true,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			err := testCase.annotation.Validate()
+			if testCase.hasErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTestAnnotationIsExpired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, (&TestAnnotation{}).IsExpired(now))
+	assert.False(t, (&TestAnnotation{ExpiresAt: now.Add(time.Minute)}).IsExpired(now))
+	assert.True(t, (&TestAnnotation{ExpiresAt: now}).IsExpired(now))
+	assert.True(t, (&TestAnnotation{ExpiresAt: now.Add(-time.Minute)}).IsExpired(now))
+}
+
+func TestTestQuarantines(t *testing.T) {
+	t.Run("Nil", func(t *testing.T) {
+		var q *TestQuarantines
+		assert.True(t, q.IsEmpty())
+		assert.False(t, q.IsQuarantined("v", "t", "TestFoo"))
+	})
+	t.Run("IgnoresNonQuarantines", func(t *testing.T) {
+		q := NewTestQuarantines([]TestAnnotation{{Project: "p", TestName: "TestFoo", Note: "note"}})
+		assert.True(t, q.IsEmpty())
+		assert.False(t, q.IsQuarantined("v", "t", "TestFoo"))
+	})
+	t.Run("Wildcards", func(t *testing.T) {
+		q := NewTestQuarantines([]TestAnnotation{
+			{Project: "p", Variant: "v1", TaskName: "t1", TestName: "TestExact", Quarantined: true},
+			{Project: "p", Variant: "v1", TestName: "TestVariant", Quarantined: true},
+			{Project: "p", TaskName: "t1", TestName: "TestTask", Quarantined: true},
+			{Project: "p", TestName: "TestAll", Quarantined: true},
+		})
+		assert.False(t, q.IsEmpty())
+
+		assert.True(t, q.IsQuarantined("v1", "t1", "TestExact"))
+		assert.False(t, q.IsQuarantined("v1", "t2", "TestExact"))
+		assert.False(t, q.IsQuarantined("v2", "t1", "TestExact"))
+
+		assert.True(t, q.IsQuarantined("v1", "t1", "TestVariant"))
+		assert.True(t, q.IsQuarantined("v1", "t2", "TestVariant"))
+		assert.False(t, q.IsQuarantined("v2", "t1", "TestVariant"))
+
+		assert.True(t, q.IsQuarantined("v1", "t1", "TestTask"))
+		assert.True(t, q.IsQuarantined("v2", "t1", "TestTask"))
+		assert.False(t, q.IsQuarantined("v1", "t2", "TestTask"))
+
+		assert.True(t, q.IsQuarantined("v2", "t2", "TestAll"))
+		assert.False(t, q.IsQuarantined("v1", "t1", "TestOther"))
+	})
+}
+
+func TestTestAnnotationsFilterCreateFindQuery(t *testing.T) {
+	t.Run("ProjectOnly", func(t *testing.T) {
+		query := TestAnnotationsFilter{Project: "p"}.createFindQuery()
+		assert.Equal(t, "p", query[testAnnotationProjectKey])
+		assert.Contains(t, query, "$or")
+		assert.NotContains(t, query, testAnnotationQuarantinedKey)
+	})
+	t.Run("AllFields", func(t *testing.T) {
+		query := TestAnnotationsFilter{
+			Project:         "p",
+			Variant:         "v",
+			TaskName:        "t",
+			TestName:        "TestFoo",
+			QuarantinedOnly: true,
+			IncludeExpired:  true,
+		}.createFindQuery()
+		assert.Equal(t, bson.M{
+			testAnnotationProjectKey:     "p",
+			testAnnotationVariantKey:     "v",
+			testAnnotationTaskNameKey:    "t",
+			testAnnotationTestNameKey:    "TestFoo",
+			testAnnotationQuarantinedKey: true,
+		}, query)
+	})
+}
+
+func TestQuarantinedFailedTestsSample(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(testAnnotationsCollection).Drop(ctx))
+	}()
+	require.NoError(t, db.Collection(testAnnotationsCollection).Drop(ctx))
+
+	annotation := &TestAnnotation{Project: "p", Variant: "v", TestName: "TestFlaky", Quarantined: true}
+	annotation.Setup(env)
+	require.NoError(t, annotation.Save(ctx))
+
+	records := []TestResults{
+		{
+			Info:              TestResultsInfo{Project: "p", Variant: "v", TaskName: "t", TaskID: "task0", DisplayTaskID: "display"},
+			FailedTestsSample: []string{"TestFlaky", "TestBroken"},
+		},
+		{
+			Info:              TestResultsInfo{Project: "p", Variant: "v2", TaskName: "t", TaskID: "task1"},
+			FailedTestsSample: []string{"TestFlaky"},
+		},
+	}
+	quarantined, err := quarantinedFailedTestsSample(ctx, env, records)
+	require.NoError(t, err)
+	assert.Equal(t, map[testResultsSampleKey]bool{
+		{taskID: "task0", testName: "TestFlaky"}:   true,
+		{taskID: "display", testName: "TestFlaky"}: true,
+	}, quarantined)
+}
+
+func TestCountQuarantinedFailures(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "count-quarantined-failures-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, db.Collection(testAnnotationsCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(configurationCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(testResultsCollection).Drop(ctx))
+	}()
+	require.NoError(t, db.Collection(testAnnotationsCollection).Drop(ctx))
+
+	annotation := &TestAnnotation{Project: "p", Variant: "v", TestName: "TestFlaky", Quarantined: true}
+	annotation.Setup(env)
+	require.NoError(t, annotation.Save(ctx))
+
+	t.Run("FromSamples", func(t *testing.T) {
+		// The records have no artifacts, so counting must only rely on
+		// the complete failed tests samples.
+		records := []TestResults{
+			{
+				Info:              TestResultsInfo{Project: "p", Variant: "v", TaskName: "t", TaskID: "task0"},
+				Stats:             TestResultsStats{FailedCount: 2},
+				FailedTestsSample: []string{"TestFlaky", "TestBroken"},
+			},
+			{
+				Info:              TestResultsInfo{Project: "p", Variant: "v", TaskName: "t2", TaskID: "task1"},
+				Stats:             TestResultsStats{FailedCount: 1},
+				FailedTestsSample: []string{"TestFlaky"},
+			},
+			{
+				Info:              TestResultsInfo{Project: "other", Variant: "v", TaskName: "t", TaskID: "task2"},
+				Stats:             TestResultsStats{FailedCount: 1},
+				FailedTestsSample: []string{"TestFlaky"},
+			},
+		}
+		count, err := countQuarantinedFailures(ctx, env, records)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+	t.Run("FromFailedTestResults", func(t *testing.T) {
+		conf := &CedarConfig{
+			Bucket: BucketConfig{
+				TestResultsBucket:       tmpDir,
+				PrestoBucket:            tmpDir,
+				PrestoTestResultsPrefix: "presto-test-results",
+			},
+			populated: true,
+		}
+		conf.Setup(env)
+		require.NoError(t, conf.Save())
+
+		record := getTestResults()
+		record.Info.Project = "p"
+		record.Info.Variant = "v"
+		record.populated = true
+		_, err := db.Collection(testResultsCollection).InsertOne(ctx, record)
+		require.NoError(t, err)
+
+		// The quarantined failure is not in the failed tests sample.
+		results := make([]TestResult, FailedTestsSampleSize+2)
+		for i := range results {
+			results[i] = getTestResult()
+			results[i].TaskID = record.Info.TaskID
+			results[i].Execution = record.Info.Execution
+			results[i].DisplayTestName = ""
+			results[i].Status = "fail"
+		}
+		results[len(results)-2].TestName = "TestFlaky"
+		results[len(results)-1].Status = "pass"
+		results[len(results)-1].TestName = "TestFlaky"
+		record.Setup(env)
+		require.NoError(t, record.Append(ctx, results))
+		require.NotContains(t, record.FailedTestsSample, "TestFlaky")
+
+		count, err := countQuarantinedFailures(ctx, env, []TestResults{*record})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestTestAnnotationSaveFindRemove(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(testAnnotationsCollection).Drop(ctx))
+	}()
+	require.NoError(t, db.Collection(testAnnotationsCollection).Drop(ctx))
+
+	t.Run("NoEnv", func(t *testing.T) {
+		annotation := &TestAnnotation{Project: "p", TestName: "TestFoo", Note: "note"}
+		assert.Error(t, annotation.Save(ctx))
+		assert.Error(t, annotation.Find(ctx))
+		assert.Error(t, annotation.Remove(ctx))
+	})
+	t.Run("InvalidSave", func(t *testing.T) {
+		annotation := &TestAnnotation{Project: "p", TestName: "TestFoo"}
+		annotation.Setup(env)
+		assert.Error(t, annotation.Save(ctx))
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		annotation := &TestAnnotation{
+			Project:       "p",
+			Variant:       "v",
+			TestName:      "TestB",
+			Quarantined:   true,
+			ExpiresAt:     time.Now().Add(time.Hour),
+			LastUpdatedBy: "creator",
+		}
+		annotation.Setup(env)
+		require.NoError(t, annotation.Save(ctx))
+		assert.Equal(t, TestAnnotationID("p", "v", "", "TestB"), annotation.ID)
+		assert.Equal(t, "creator", annotation.CreatedBy)
+
+		annotation = &TestAnnotation{Project: "p", Variant: "v", TestName: "TestB", Tickets: []string{"BF-1"}, LastUpdatedBy: "editor"}
+		annotation.Setup(env)
+		require.NoError(t, annotation.Save(ctx))
+
+		other := &TestAnnotation{Project: "p", TestName: "TestA", Quarantined: true}
+		other.Setup(env)
+		require.NoError(t, other.Save(ctx))
+		unrelated := &TestAnnotation{Project: "p2", TestName: "TestA", Quarantined: true}
+		unrelated.Setup(env)
+		require.NoError(t, unrelated.Save(ctx))
+
+		found := &TestAnnotation{ID: annotation.ID}
+		found.Setup(env)
+		require.NoError(t, found.Find(ctx))
+		assert.False(t, found.IsNil())
+		assert.False(t, found.Quarantined)
+		assert.Equal(t, []string{"BF-1"}, found.Tickets)
+		assert.True(t, found.ExpiresAt.IsZero())
+		assert.Equal(t, "creator", found.CreatedBy)
+		assert.Equal(t, "editor", found.LastUpdatedBy)
+
+		annotations, err := FindTestAnnotations(ctx, env, TestAnnotationsFilter{Project: "p"})
+		require.NoError(t, err)
+		require.Len(t, annotations, 2)
+		assert.Equal(t, "TestA", annotations[0].TestName)
+		assert.Equal(t, "TestB", annotations[1].TestName)
+
+		quarantines, err := GetTestQuarantines(ctx, env, "p")
+		require.NoError(t, err)
+		assert.True(t, quarantines.IsQuarantined("v", "t", "TestA"))
+		assert.False(t, quarantines.IsQuarantined("v", "t", "TestB"))
+
+		require.NoError(t, found.Remove(ctx))
+		assert.Error(t, found.Find(ctx))
+		annotations, err = FindTestAnnotations(ctx, env, TestAnnotationsFilter{Project: "p"})
+		require.NoError(t, err)
+		assert.Len(t, annotations, 1)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		_, err := db.Collection(testAnnotationsCollection).InsertOne(ctx, TestAnnotation{
+			ID:          TestAnnotationID("p", "", "", "TestExpired"),
+			Project:     "p",
+			TestName:    "TestExpired",
+			Quarantined: true,
+			ExpiresAt:   time.Now().Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		annotations, err := FindTestAnnotations(ctx, env, TestAnnotationsFilter{Project: "p", TestName: "TestExpired"})
+		require.NoError(t, err)
+		assert.Empty(t, annotations)
+		annotations, err = FindTestAnnotations(ctx, env, TestAnnotationsFilter{Project: "p", TestName: "TestExpired", IncludeExpired: true})
+		require.NoError(t, err)
+		assert.Len(t, annotations, 1)
+	})
+}
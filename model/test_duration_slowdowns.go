@@ -0,0 +1,517 @@
+package model
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aclements/go-moremath/stats"
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testDurationSlowdownsCollection = "test_duration_slowdowns"
+
+// TestDurationSlowdown describes a statistically significant increase in the
+// duration of a mainline test, detected on a given day. The recent durations
+// of the test are compared against its baseline durations from earlier in a
+// rolling window.
+type TestDurationSlowdown struct {
+	ID         string                   `bson:"_id"`
+	Info       TestDurationSlowdownInfo `bson:"info"`
+	Baseline   TestDurationStats        `bson:"baseline"`
+	Recent     TestDurationStats        `bson:"recent"`
+	PValue     float64                  `bson:"p_value"`
+	LastUpdate time.Time                `bson:"last_update"`
+
+	env       cedar.Environment
+	populated bool
+}
+
+var (
+	testDurationSlowdownIDKey         = bsonutil.MustHaveTag(TestDurationSlowdown{}, "ID")
+	testDurationSlowdownInfoKey       = bsonutil.MustHaveTag(TestDurationSlowdown{}, "Info")
+	testDurationSlowdownBaselineKey   = bsonutil.MustHaveTag(TestDurationSlowdown{}, "Baseline")
+	testDurationSlowdownRecentKey     = bsonutil.MustHaveTag(TestDurationSlowdown{}, "Recent")
+	testDurationSlowdownPValueKey     = bsonutil.MustHaveTag(TestDurationSlowdown{}, "PValue")
+	testDurationSlowdownLastUpdateKey = bsonutil.MustHaveTag(TestDurationSlowdown{}, "LastUpdate")
+)
+
+// TestDurationSlowdownInfo describes information unique to a single test
+// duration slowdown.
+type TestDurationSlowdownInfo struct {
+	Project  string    `bson:"project"`
+	Variant  string    `bson:"variant"`
+	TaskName string    `bson:"task_name"`
+	TestName string    `bson:"test_name"`
+	Date     time.Time `bson:"date"`
+}
+
+var (
+	testDurationSlowdownInfoProjectKey  = bsonutil.MustHaveTag(TestDurationSlowdownInfo{}, "Project")
+	testDurationSlowdownInfoVariantKey  = bsonutil.MustHaveTag(TestDurationSlowdownInfo{}, "Variant")
+	testDurationSlowdownInfoTaskNameKey = bsonutil.MustHaveTag(TestDurationSlowdownInfo{}, "TaskName")
+	testDurationSlowdownInfoTestNameKey = bsonutil.MustHaveTag(TestDurationSlowdownInfo{}, "TestName")
+	testDurationSlowdownInfoDateKey     = bsonutil.MustHaveTag(TestDurationSlowdownInfo{}, "Date")
+)
+
+// ID creates a unique hash for a TestDurationSlowdown.
+func (i *TestDurationSlowdownInfo) ID() string {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, i.Project)
+	_, _ = io.WriteString(hash, i.Variant)
+	_, _ = io.WriteString(hash, i.TaskName)
+	_, _ = io.WriteString(hash, i.TestName)
+	_, _ = io.WriteString(hash, i.Date.Format(HistoricalTestDataDateFormat))
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// TestDurationStats describes the distribution of a test's durations.
+type TestDurationStats struct {
+	NumRuns int           `bson:"num_runs"`
+	Median  time.Duration `bson:"median"`
+	P90     time.Duration `bson:"p90"`
+}
+
+var testDurationStatsMedianKey = bsonutil.MustHaveTag(TestDurationStats{}, "Median")
+
+func newTestDurationStats(durations []float64) TestDurationStats {
+	sample := stats.Sample{Xs: durations}
+	sample.Sort()
+
+	return TestDurationStats{
+		NumRuns: len(durations),
+		Median:  time.Duration(sample.Quantile(0.5)),
+		P90:     time.Duration(sample.Quantile(0.9)),
+	}
+}
+
+// Setup sets the environment. The environment is required for numerous
+// functions on TestDurationSlowdown.
+func (s *TestDurationSlowdown) Setup(e cedar.Environment) { s.env = e }
+
+// IsNil returns if the TestDurationSlowdown is populated or not.
+func (s *TestDurationSlowdown) IsNil() bool { return !s.populated }
+
+// Find searches the DB for the TestDurationSlowdown by ID. The environment
+// should not be nil.
+func (s *TestDurationSlowdown) Find(ctx context.Context) error {
+	if s.env == nil {
+		return errors.New("cannot find with a nil environment")
+	}
+
+	if s.ID == "" {
+		s.ID = s.Info.ID()
+	}
+
+	s.populated = false
+	if err := s.env.GetDB().Collection(testDurationSlowdownsCollection).FindOne(ctx, bson.M{"_id": s.ID}).Decode(s); err != nil {
+		return errors.Wrapf(err, "finding test duration slowdown '%s'", s.ID)
+	}
+	s.populated = true
+
+	return nil
+}
+
+// Save upserts the TestDurationSlowdown, replacing the slowdown detected for
+// the same test on the same day, if any. The environment should not be nil.
+func (s *TestDurationSlowdown) Save(ctx context.Context) error {
+	if s.env == nil {
+		return errors.New("cannot save with a nil environment")
+	}
+
+	s.Info.Date = utility.GetUTCDay(s.Info.Date)
+	s.ID = s.Info.ID()
+	s.LastUpdate = time.Now()
+
+	_, err := s.env.GetDB().Collection(testDurationSlowdownsCollection).UpdateOne(
+		ctx,
+		bson.M{testDurationSlowdownIDKey: s.ID},
+		bson.M{"$set": bson.M{
+			testDurationSlowdownInfoKey:       s.Info,
+			testDurationSlowdownBaselineKey:   s.Baseline,
+			testDurationSlowdownRecentKey:     s.Recent,
+			testDurationSlowdownPValueKey:     s.PValue,
+			testDurationSlowdownLastUpdateKey: s.LastUpdate,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection": testDurationSlowdownsCollection,
+		"id":         s.ID,
+		"op":         "save test duration slowdown",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "saving test duration slowdown '%s'", s.ID)
+	}
+	s.populated = true
+
+	return nil
+}
+
+////////////
+// Detection
+////////////
+
+const (
+	defaultTestDurationWindow              = 14 * 24 * time.Hour
+	defaultTestDurationRecentWindow        = 2 * 24 * time.Hour
+	defaultTestDurationMinRuns             = 5
+	defaultTestDurationMaxRuns             = 100
+	defaultTestDurationMaxPValue           = 0.01
+	defaultTestDurationMinIncrease         = 0.2
+	defaultTestDurationMinAbsoluteIncrease = 100 * time.Millisecond
+)
+
+// DetectTestDurationSlowdownsOptions specify how to detect test duration
+// slowdowns. Zero values use the defaults.
+type DetectTestDurationSlowdownsOptions struct {
+	// EndAt is the end of the rolling window. Defaults to the current
+	// time.
+	EndAt time.Time `bson:"end_at"`
+	// Window is the length of the rolling window of mainline test results.
+	// Defaults to two weeks.
+	Window time.Duration `bson:"window"`
+	// RecentWindow is the length of the end of the rolling window whose
+	// test durations are compared against the rest of the window. Defaults
+	// to two days.
+	RecentWindow time.Duration `bson:"recent_window"`
+	// MinRuns is the minimum number of passing runs of a test required in
+	// both the recent and the baseline durations. Defaults to 5.
+	MinRuns int `bson:"min_runs"`
+	// MaxRuns is the maximum number of the latest task executions whose
+	// test results are downloaded, for each of the recent and the baseline
+	// durations. Defaults to 100.
+	MaxRuns int `bson:"max_runs"`
+	// MaxPValue is the maximum p-value of the one-sided Mann-Whitney U-test
+	// for a slowdown to be significant. Defaults to 0.01.
+	MaxPValue float64 `bson:"max_p_value"`
+	// MinIncrease is the minimum increase of the median duration, relative
+	// to the baseline median. Defaults to 0.2.
+	MinIncrease float64 `bson:"min_increase"`
+	// MinAbsoluteIncrease is the minimum increase of the median duration,
+	// so very short tests do not show up because of noise. Defaults to
+	// 100 milliseconds.
+	MinAbsoluteIncrease time.Duration `bson:"min_absolute_increase"`
+}
+
+func (opts *DetectTestDurationSlowdownsOptions) validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if opts.EndAt.IsZero() {
+		opts.EndAt = time.Now()
+	}
+	if opts.Window == 0 {
+		opts.Window = defaultTestDurationWindow
+	}
+	if opts.RecentWindow == 0 {
+		opts.RecentWindow = defaultTestDurationRecentWindow
+	}
+	if opts.MinRuns == 0 {
+		opts.MinRuns = defaultTestDurationMinRuns
+	}
+	if opts.MaxRuns == 0 {
+		opts.MaxRuns = defaultTestDurationMaxRuns
+	}
+	if opts.MaxPValue == 0 {
+		opts.MaxPValue = defaultTestDurationMaxPValue
+	}
+	if opts.MinIncrease == 0 {
+		opts.MinIncrease = defaultTestDurationMinIncrease
+	}
+	if opts.MinAbsoluteIncrease == 0 {
+		opts.MinAbsoluteIncrease = defaultTestDurationMinAbsoluteIncrease
+	}
+
+	catcher.NewWhen(opts.Window < 0, "window cannot be negative")
+	catcher.NewWhen(opts.RecentWindow < 0, "recent window cannot be negative")
+	catcher.NewWhen(opts.RecentWindow >= opts.Window, "recent window must be shorter than the window")
+	catcher.NewWhen(opts.MinRuns < 0, "minimum number of runs cannot be negative")
+	catcher.NewWhen(opts.MaxRuns < opts.MinRuns, "maximum number of runs cannot be less than the minimum number of runs")
+	catcher.NewWhen(opts.MaxPValue < 0 || opts.MaxPValue > 1, "maximum p-value must be between 0 and 1")
+	catcher.NewWhen(opts.MinIncrease < 0, "minimum increase cannot be negative")
+	catcher.NewWhen(opts.MinAbsoluteIncrease < 0, "minimum absolute increase cannot be negative")
+
+	return catcher.Resolve()
+}
+
+// TestDurationTask identifies the mainline test results of a task over time.
+type TestDurationTask struct {
+	Project  string `bson:"project"`
+	Variant  string `bson:"variant"`
+	TaskName string `bson:"task_name"`
+}
+
+// testDurationSamples holds the passing durations, in nanoseconds, of a test.
+type testDurationSamples struct {
+	baseline []float64
+	recent   []float64
+}
+
+// FindTestDurationTasks returns the tasks with mainline test results in the
+// rolling window of the given options, so that the slowdowns of each task can
+// be detected separately. The environment should not be nil.
+func FindTestDurationTasks(ctx context.Context, env cedar.Environment, opts DetectTestDurationSlowdownsOptions) ([]TestDurationTask, error) {
+	if env == nil {
+		return nil, errors.New("cannot find test duration tasks with a nil environment")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid test duration slowdown options")
+	}
+
+	cur, err := env.GetDB().Collection(testResultsCollection).Aggregate(ctx, []bson.M{
+		{"$match": bson.M{
+			bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoMainlineKey): true,
+			testResultsCreatedAtKey: bson.M{
+				"$gte": opts.EndAt.Add(-opts.Window),
+				"$lt":  opts.EndAt,
+			},
+		}},
+		{"$group": bson.M{"_id": bson.M{
+			"project":   "$" + bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey),
+			"variant":   "$" + bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVariantKey),
+			"task_name": "$" + bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskNameKey),
+		}}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "aggregating mainline tasks")
+	}
+	var groups []struct {
+		Task TestDurationTask `bson:"_id"`
+	}
+	if err = cur.All(ctx, &groups); err != nil {
+		return nil, errors.Wrap(err, "decoding mainline tasks")
+	}
+
+	tasks := make([]TestDurationTask, len(groups))
+	for i, group := range groups {
+		tasks[i] = group.Task
+	}
+
+	return tasks, nil
+}
+
+// DetectTestDurationSlowdowns computes the distribution of the durations of
+// each passing mainline test of the given task over the rolling window and
+// returns the tests whose recent durations are significantly longer than their
+// baseline durations. The latest trial of each test in a task execution counts
+// as one run. Only the test results of the latest task executions, up to the
+// maximum number of runs, are downloaded for each of the recent and baseline
+// durations. The returned slowdowns are dated on the end of the window and are
+// not saved. The environment should not be nil.
+func DetectTestDurationSlowdowns(ctx context.Context, env cedar.Environment, task TestDurationTask, opts DetectTestDurationSlowdownsOptions) ([]TestDurationSlowdown, error) {
+	if env == nil {
+		return nil, errors.New("cannot detect test duration slowdowns with a nil environment")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid test duration slowdown options")
+	}
+
+	recentStart := opts.EndAt.Add(-opts.RecentWindow)
+	samples := map[string]*testDurationSamples{}
+	for _, window := range []struct {
+		start  time.Time
+		end    time.Time
+		recent bool
+	}{
+		{start: opts.EndAt.Add(-opts.Window), end: recentStart},
+		{start: recentStart, end: opts.EndAt, recent: true},
+	} {
+		cur, err := env.GetDB().Collection(testResultsCollection).Find(ctx, bson.M{
+			bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey):  task.Project,
+			bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVariantKey):  task.Variant,
+			bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskNameKey): task.TaskName,
+			bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoMainlineKey): true,
+			testResultsCreatedAtKey: bson.M{
+				"$gte": window.start,
+				"$lt":  window.end,
+			},
+		}, options.Find().SetSort(bson.M{testResultsCreatedAtKey: -1}).SetLimit(int64(opts.MaxRuns)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding mainline test results for task '%s'", task.TaskName)
+		}
+		var records []TestResults
+		if err = cur.All(ctx, &records); err != nil {
+			return nil, errors.Wrapf(err, "decoding mainline test results for task '%s'", task.TaskName)
+		}
+
+		for i := range records {
+			records[i].Setup(env)
+			records[i].populated = true
+			results, err := records[i].Download(ctx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "downloading test results for '%s'", records[i].ID)
+			}
+			addTestDurationSamples(samples, results, window.recent)
+		}
+	}
+
+	return findTestDurationSlowdowns(task, samples, opts), nil
+}
+
+// addTestDurationSamples adds the duration of the latest trial of each
+// passing test in the results of a task execution to the samples.
+func addTestDurationSamples(samples map[string]*testDurationSamples, results []TestResult, recent bool) {
+	for name, outcome := range summarizeTestOutcomes(results) {
+		if outcome.latest.Status != "pass" || outcome.latest.getDuration() <= 0 {
+			continue
+		}
+
+		sample, ok := samples[name]
+		if !ok {
+			sample = &testDurationSamples{}
+			samples[name] = sample
+		}
+		if recent {
+			sample.recent = append(sample.recent, float64(outcome.latest.getDuration()))
+		} else {
+			sample.baseline = append(sample.baseline, float64(outcome.latest.getDuration()))
+		}
+	}
+}
+
+// findTestDurationSlowdowns returns the slowdowns of the tests of a task,
+// sorted by test name.
+func findTestDurationSlowdowns(task TestDurationTask, samples map[string]*testDurationSamples, opts DetectTestDurationSlowdownsOptions) []TestDurationSlowdown {
+	var slowdowns []TestDurationSlowdown
+	for name, sample := range samples {
+		if len(sample.baseline) < opts.MinRuns || len(sample.recent) < opts.MinRuns {
+			continue
+		}
+
+		baseline := newTestDurationStats(sample.baseline)
+		recent := newTestDurationStats(sample.recent)
+		increase := recent.Median - baseline.Median
+		if increase < opts.MinAbsoluteIncrease || float64(increase) < opts.MinIncrease*float64(baseline.Median) {
+			continue
+		}
+
+		// The test fails when the samples are identical, in which
+		// case there is no slowdown.
+		test, err := stats.MannWhitneyUTest(sample.baseline, sample.recent, stats.LocationLess)
+		if err != nil || test.P > opts.MaxPValue {
+			continue
+		}
+
+		slowdowns = append(slowdowns, TestDurationSlowdown{
+			Info: TestDurationSlowdownInfo{
+				Project:  task.Project,
+				Variant:  task.Variant,
+				TaskName: task.TaskName,
+				TestName: name,
+				Date:     utility.GetUTCDay(opts.EndAt),
+			},
+			Baseline: baseline,
+			Recent:   recent,
+			PValue:   test.P,
+		})
+	}
+	sort.Slice(slowdowns, func(i, j int) bool {
+		return slowdowns[i].Info.TestName < slowdowns[j].Info.TestName
+	})
+
+	return slowdowns
+}
+
+/////////////////
+// Find slowdowns
+/////////////////
+
+const testDurationSlowdownsMaxQueryLimit = 1000
+
+// TestDurationSlowdownsFilter represents search parameters when querying the
+// test duration slowdowns of a project.
+type TestDurationSlowdownsFilter struct {
+	Project    string
+	AfterDate  time.Time
+	BeforeDate time.Time
+
+	Tasks    []string
+	Variants []string
+
+	Limit int
+}
+
+// Validate ensures that the TestDurationSlowdownsFilter is valid.
+func (f *TestDurationSlowdownsFilter) Validate() error {
+	if f == nil {
+		return errors.New("test duration slowdowns filter should not be nil")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(f.Project == "", "missing Project value")
+	catcher.NewWhen(f.Limit > testDurationSlowdownsMaxQueryLimit || f.Limit <= 0, "invalid Limit value")
+	catcher.NewWhen(!f.AfterDate.Equal(utility.GetUTCDay(f.AfterDate)), "invalid AfterDate value")
+	catcher.NewWhen(!f.BeforeDate.Equal(utility.GetUTCDay(f.BeforeDate)), "invalid BeforeDate value")
+	catcher.NewWhen(!f.BeforeDate.After(f.AfterDate), "invalid AfterDate/BeforeDate values")
+	return catcher.Resolve()
+}
+
+// GetTestDurationSlowdowns returns the test duration slowdowns matching the
+// filter, most recent first and then by the largest relative increase of the
+// median duration. The environment should not be nil.
+func GetTestDurationSlowdowns(ctx context.Context, env cedar.Environment, filter TestDurationSlowdownsFilter) ([]TestDurationSlowdown, error) {
+	if env == nil {
+		return nil, errors.New("cannot find with a nil environment")
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, errors.Wrap(err, "the provided TestDurationSlowdownsFilter is invalid")
+	}
+
+	match := bson.M{
+		bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoProjectKey): filter.Project,
+		bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoDateKey): bson.M{
+			"$gte": filter.AfterDate,
+			"$lt":  filter.BeforeDate,
+		},
+	}
+	if len(filter.Tasks) > 0 {
+		match[bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoTaskNameKey)] = bson.M{"$in": filter.Tasks}
+	}
+	if len(filter.Variants) > 0 {
+		match[bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoVariantKey)] = bson.M{"$in": filter.Variants}
+	}
+
+	medianKey := func(key string) string {
+		return "$" + bsonutil.GetDottedKeyName(key, testDurationStatsMedianKey)
+	}
+	cur, err := env.GetDB().Collection(testDurationSlowdownsCollection).Aggregate(ctx, []bson.M{
+		{"$match": match},
+		{"$addFields": bson.M{"increase": bson.M{"$divide": []interface{}{
+			medianKey(testDurationSlowdownRecentKey),
+			bson.M{"$max": []interface{}{medianKey(testDurationSlowdownBaselineKey), 1}},
+		}}}},
+		{"$sort": bson.D{
+			{Key: bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoDateKey), Value: -1},
+			{Key: "increase", Value: -1},
+			{Key: bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoVariantKey), Value: 1},
+			{Key: bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoTaskNameKey), Value: 1},
+			{Key: bsonutil.GetDottedKeyName(testDurationSlowdownInfoKey, testDurationSlowdownInfoTestNameKey), Value: 1},
+		}},
+		{"$limit": filter.Limit},
+		{"$project": bson.M{"increase": 0}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "aggregating test duration slowdowns")
+	}
+	var slowdowns []TestDurationSlowdown
+	if err = cur.All(ctx, &slowdowns); err != nil {
+		return nil, errors.Wrap(err, "decoding test duration slowdowns")
+	}
+	for i := range slowdowns {
+		slowdowns[i].Setup(env)
+		slowdowns[i].populated = true
+	}
+
+	return slowdowns, nil
+}
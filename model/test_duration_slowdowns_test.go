@@ -0,0 +1,204 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTestDurationSlowdownsOptionsValidate(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		opts := DetectTestDurationSlowdownsOptions{}
+		require.NoError(t, opts.validate())
+		assert.False(t, opts.EndAt.IsZero())
+		assert.Equal(t, defaultTestDurationWindow, opts.Window)
+		assert.Equal(t, defaultTestDurationRecentWindow, opts.RecentWindow)
+		assert.Equal(t, defaultTestDurationMinRuns, opts.MinRuns)
+		assert.Equal(t, defaultTestDurationMaxRuns, opts.MaxRuns)
+		assert.Equal(t, defaultTestDurationMaxPValue, opts.MaxPValue)
+		assert.Equal(t, defaultTestDurationMinIncrease, opts.MinIncrease)
+		assert.Equal(t, defaultTestDurationMinAbsoluteIncrease, opts.MinAbsoluteIncrease)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, opts := range []DetectTestDurationSlowdownsOptions{
+			{Window: -time.Hour},
+			{Window: time.Hour, RecentWindow: 2 * time.Hour},
+			{MinRuns: -1},
+			{MinRuns: 10, MaxRuns: 5},
+			{MaxPValue: 2},
+			{MinIncrease: -0.5},
+			{MinAbsoluteIncrease: -time.Second},
+		} {
+			assert.Error(t, opts.validate())
+		}
+	})
+}
+
+func TestFindTestDurationSlowdowns(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	newResult := func(name, status string, trial int, duration time.Duration) TestResult {
+		return TestResult{
+			TestName:      name,
+			Status:        status,
+			Trial:         trial,
+			TestStartTime: start,
+			TestEndTime:   start.Add(duration),
+		}
+	}
+	task := TestDurationTask{Project: "project", Variant: "variant", TaskName: "task"}
+	opts := DetectTestDurationSlowdownsOptions{}
+	require.NoError(t, opts.validate())
+
+	samples := map[string]*testDurationSamples{}
+	for i := 0; i < 10; i++ {
+		jitter := time.Duration(i) * 10 * time.Millisecond
+		addTestDurationSamples(samples, []TestResult{
+			newResult("slower", "pass", 0, 10*time.Second+jitter),
+			newResult("stable", "pass", 0, 10*time.Second+jitter),
+			newResult("short", "pass", 0, 10*time.Millisecond+jitter/100),
+			newResult("retried", "fail", 0, time.Second),
+			newResult("retried", "pass", 1, time.Second+jitter),
+			newResult("failing", "fail", 0, time.Second),
+		}, false)
+	}
+	for i := 0; i < 6; i++ {
+		jitter := time.Duration(i) * 10 * time.Millisecond
+		addTestDurationSamples(samples, []TestResult{
+			newResult("slower", "pass", 0, 15*time.Second+jitter),
+			newResult("stable", "pass", 0, 10*time.Second+jitter),
+			newResult("short", "pass", 0, 50*time.Millisecond+jitter/100),
+			newResult("retried", "pass", 0, 5*time.Second+jitter),
+			newResult("failing", "fail", 0, 5*time.Second),
+		}, true)
+	}
+	addTestDurationSamples(samples, []TestResult{newResult("few_runs", "pass", 0, time.Second)}, false)
+
+	assert.Len(t, samples["retried"].baseline, 10)
+	assert.NotContains(t, samples, "failing")
+
+	slowdowns := findTestDurationSlowdowns(task, samples, opts)
+	require.Len(t, slowdowns, 2)
+
+	assert.Equal(t, "retried", slowdowns[0].Info.TestName)
+	assert.Equal(t, "slower", slowdowns[1].Info.TestName)
+	assert.Equal(t, "project", slowdowns[1].Info.Project)
+	assert.Equal(t, "variant", slowdowns[1].Info.Variant)
+	assert.Equal(t, "task", slowdowns[1].Info.TaskName)
+	assert.Equal(t, utility.GetUTCDay(opts.EndAt), slowdowns[1].Info.Date)
+	assert.Equal(t, 10, slowdowns[1].Baseline.NumRuns)
+	assert.Equal(t, 6, slowdowns[1].Recent.NumRuns)
+	assert.True(t, slowdowns[1].Recent.Median > slowdowns[1].Baseline.Median)
+	assert.True(t, slowdowns[1].Recent.P90 >= slowdowns[1].Recent.Median)
+	assert.True(t, slowdowns[1].PValue <= opts.MaxPValue)
+
+	t.Run("MinIncrease", func(t *testing.T) {
+		strictOpts := opts
+		strictOpts.MinIncrease = 1
+		slowdowns := findTestDurationSlowdowns(task, samples, strictOpts)
+		require.Len(t, slowdowns, 1)
+		assert.Equal(t, "retried", slowdowns[0].Info.TestName)
+	})
+	t.Run("MinAbsoluteIncrease", func(t *testing.T) {
+		lenientOpts := opts
+		lenientOpts.MinAbsoluteIncrease = time.Millisecond
+		slowdowns := findTestDurationSlowdowns(task, samples, lenientOpts)
+		require.Len(t, slowdowns, 3)
+		assert.Equal(t, "short", slowdowns[1].Info.TestName)
+	})
+}
+
+func TestGetTestDurationSlowdowns(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(testDurationSlowdownsCollection).Drop(ctx))
+	}()
+
+	day := utility.GetUTCDay(time.Now())
+	for _, slowdown := range []TestDurationSlowdown{
+		getTestDurationSlowdown("variant0", "task0", "test0", day, 2*time.Second),
+		getTestDurationSlowdown("variant0", "task0", "test1", day, 3*time.Second),
+		getTestDurationSlowdown("variant1", "task1", "test0", day.AddDate(0, 0, -1), 4*time.Second),
+		getTestDurationSlowdown("variant1", "task1", "test2", day.AddDate(0, 0, -30), 4*time.Second),
+	} {
+		slowdown.Setup(env)
+		require.NoError(t, slowdown.Save(ctx))
+	}
+	baseFilter := func() TestDurationSlowdownsFilter {
+		return TestDurationSlowdownsFilter{
+			Project:    "project",
+			AfterDate:  day.AddDate(0, 0, -7),
+			BeforeDate: day.AddDate(0, 0, 1),
+			Limit:      10,
+		}
+	}
+
+	t.Run("SaveReplaces", func(t *testing.T) {
+		slowdown := getTestDurationSlowdown("variant0", "task0", "test0", day, 2*time.Second)
+		slowdown.Setup(env)
+		slowdown.PValue = 0.001
+		require.NoError(t, slowdown.Save(ctx))
+
+		found := &TestDurationSlowdown{ID: slowdown.ID}
+		found.Setup(env)
+		require.NoError(t, found.Find(ctx))
+		assert.Equal(t, 0.001, found.PValue)
+		assert.Equal(t, slowdown.Info, found.Info)
+	})
+	t.Run("InvalidFilter", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Limit = 0
+		_, err := GetTestDurationSlowdowns(ctx, env, filter)
+		assert.Error(t, err)
+	})
+	t.Run("Sorted", func(t *testing.T) {
+		slowdowns, err := GetTestDurationSlowdowns(ctx, env, baseFilter())
+		require.NoError(t, err)
+		require.Len(t, slowdowns, 3)
+		assert.Equal(t, "test1", slowdowns[0].Info.TestName)
+		assert.Equal(t, "test0", slowdowns[1].Info.TestName)
+		assert.Equal(t, "variant0", slowdowns[1].Info.Variant)
+		assert.Equal(t, "variant1", slowdowns[2].Info.Variant)
+	})
+	t.Run("VariantsAndTasks", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Variants = []string{"variant0"}
+		filter.Tasks = []string{"task0"}
+		slowdowns, err := GetTestDurationSlowdowns(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, slowdowns, 2)
+		for _, slowdown := range slowdowns {
+			assert.Equal(t, "variant0", slowdown.Info.Variant)
+		}
+	})
+	t.Run("Limit", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Limit = 1
+		slowdowns, err := GetTestDurationSlowdowns(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, slowdowns, 1)
+		assert.Equal(t, "test1", slowdowns[0].Info.TestName)
+	})
+}
+
+func getTestDurationSlowdown(variant, taskName, testName string, date time.Time, recentMedian time.Duration) TestDurationSlowdown {
+	return TestDurationSlowdown{
+		Info: TestDurationSlowdownInfo{
+			Project:  "project",
+			Variant:  variant,
+			TaskName: taskName,
+			TestName: testName,
+			Date:     date,
+		},
+		Baseline: TestDurationStats{NumRuns: 10, Median: time.Second, P90: time.Second},
+		Recent:   TestDurationStats{NumRuns: 5, Median: recentMedian, P90: recentMedian},
+		PValue:   0.005,
+	}
+}
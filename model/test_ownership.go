@@ -0,0 +1,383 @@
+package model
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testOwnershipCollection = "test_owners"
+
+// TestOwnership assigns an owner and a team to the tests of a project whose
+// display names match a regular expression.
+type TestOwnership struct {
+	ID              string    `bson:"_id"`
+	Project         string    `bson:"project"`
+	TestNamePattern string    `bson:"test_name_pattern"`
+	Owner           string    `bson:"owner"`
+	Team            string    `bson:"team"`
+	LastUpdatedBy   string    `bson:"last_updated_by"`
+	LastUpdate      time.Time `bson:"last_update"`
+
+	env       cedar.Environment
+	populated bool
+}
+
+var (
+	testOwnershipIDKey              = bsonutil.MustHaveTag(TestOwnership{}, "ID")
+	testOwnershipProjectKey         = bsonutil.MustHaveTag(TestOwnership{}, "Project")
+	testOwnershipTestNamePatternKey = bsonutil.MustHaveTag(TestOwnership{}, "TestNamePattern")
+	testOwnershipOwnerKey           = bsonutil.MustHaveTag(TestOwnership{}, "Owner")
+	testOwnershipTeamKey            = bsonutil.MustHaveTag(TestOwnership{}, "Team")
+	testOwnershipLastUpdatedByKey   = bsonutil.MustHaveTag(TestOwnership{}, "LastUpdatedBy")
+	testOwnershipLastUpdateKey      = bsonutil.MustHaveTag(TestOwnership{}, "LastUpdate")
+)
+
+// TestOwnershipID creates a unique hash for the test ownership of a project's
+// test name pattern.
+func TestOwnershipID(project, testNamePattern string) string {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, project)
+	_, _ = io.WriteString(hash, testNamePattern)
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// Setup sets the environment. The environment is required for numerous
+// functions on TestOwnership.
+func (o *TestOwnership) Setup(e cedar.Environment) { o.env = e }
+
+// IsNil returns if the TestOwnership is populated or not.
+func (o *TestOwnership) IsNil() bool { return !o.populated }
+
+// Validate ensures that the TestOwnership is valid.
+func (o *TestOwnership) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen(o.Project == "", "must specify a project")
+	catcher.NewWhen(o.TestNamePattern == "", "must specify a test name pattern")
+	catcher.NewWhen(o.Owner == "" && o.Team == "", "must specify an owner or a team")
+	if o.TestNamePattern != "" {
+		_, err := regexp.Compile(o.TestNamePattern)
+		catcher.Wrap(err, "compiling test name pattern")
+	}
+
+	return catcher.Resolve()
+}
+
+// Find searches the DB for the TestOwnership by ID. The environment should
+// not be nil.
+func (o *TestOwnership) Find(ctx context.Context) error {
+	if o.env == nil {
+		return errors.New("cannot find with a nil environment")
+	}
+
+	if o.ID == "" {
+		o.ID = TestOwnershipID(o.Project, o.TestNamePattern)
+	}
+
+	o.populated = false
+	if err := o.env.GetDB().Collection(testOwnershipCollection).FindOne(ctx, bson.M{testOwnershipIDKey: o.ID}).Decode(o); err != nil {
+		return errors.Wrapf(err, "finding test ownership '%s'", o.ID)
+	}
+	o.populated = true
+
+	return nil
+}
+
+// Save validates and upserts the TestOwnership, replacing the owner and team
+// of the project's test name pattern, if any. The environment should not be
+// nil.
+func (o *TestOwnership) Save(ctx context.Context) error {
+	if o.env == nil {
+		return errors.New("cannot save with a nil environment")
+	}
+	if err := o.Validate(); err != nil {
+		return errors.Wrap(err, "invalid test ownership")
+	}
+
+	o.ID = TestOwnershipID(o.Project, o.TestNamePattern)
+	o.LastUpdate = time.Now()
+
+	_, err := o.env.GetDB().Collection(testOwnershipCollection).UpdateOne(
+		ctx,
+		bson.M{testOwnershipIDKey: o.ID},
+		bson.M{"$set": bson.M{
+			testOwnershipProjectKey:         o.Project,
+			testOwnershipTestNamePatternKey: o.TestNamePattern,
+			testOwnershipOwnerKey:           o.Owner,
+			testOwnershipTeamKey:            o.Team,
+			testOwnershipLastUpdatedByKey:   o.LastUpdatedBy,
+			testOwnershipLastUpdateKey:      o.LastUpdate,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection": testOwnershipCollection,
+		"id":         o.ID,
+		"project":    o.Project,
+		"pattern":    o.TestNamePattern,
+		"op":         "save test ownership",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "saving test ownership '%s'", o.ID)
+	}
+	o.populated = true
+
+	return nil
+}
+
+// Remove removes the TestOwnership from the DB. The environment should not be
+// nil.
+func (o *TestOwnership) Remove(ctx context.Context) error {
+	if o.env == nil {
+		return errors.New("cannot remove with a nil environment")
+	}
+
+	if o.ID == "" {
+		o.ID = TestOwnershipID(o.Project, o.TestNamePattern)
+	}
+
+	deleteResult, err := o.env.GetDB().Collection(testOwnershipCollection).DeleteOne(ctx, bson.M{testOwnershipIDKey: o.ID})
+	grip.DebugWhen(err == nil, message.Fields{
+		"collection":   testOwnershipCollection,
+		"id":           o.ID,
+		"deleteResult": deleteResult,
+		"op":           "remove test ownership",
+	})
+
+	return errors.Wrapf(err, "removing test ownership '%s'", o.ID)
+}
+
+// FindTestOwnerships returns the test ownerships of the given project, sorted
+// by test name pattern. The environment should not be nil.
+func FindTestOwnerships(ctx context.Context, env cedar.Environment, project string) ([]TestOwnership, error) {
+	if env == nil {
+		return nil, errors.New("cannot find with a nil environment")
+	}
+
+	cur, err := env.GetDB().Collection(testOwnershipCollection).Find(
+		ctx,
+		bson.M{testOwnershipProjectKey: project},
+		options.Find().SetSort(bson.D{{Key: testOwnershipTestNamePatternKey, Value: 1}}),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding test ownerships for project '%s'", project)
+	}
+
+	var ownerships []TestOwnership
+	if err = cur.All(ctx, &ownerships); err != nil {
+		return nil, errors.Wrapf(err, "decoding test ownerships for project '%s'", project)
+	}
+	for i := range ownerships {
+		ownerships[i].env = env
+		ownerships[i].populated = true
+	}
+
+	return ownerships, nil
+}
+
+// TestOwnershipMatcher finds the ownership of tests by their display names.
+// When more than one test name pattern matches a test, the longest pattern,
+// which is assumed to be the most specific one, wins.
+type TestOwnershipMatcher struct {
+	ownerships []TestOwnership
+	patterns   []*regexp.Regexp
+}
+
+// NewTestOwnershipMatcher returns a TestOwnershipMatcher for the given test
+// ownerships.
+func NewTestOwnershipMatcher(ownerships []TestOwnership) (*TestOwnershipMatcher, error) {
+	sorted := make([]TestOwnership, len(ownerships))
+	copy(sorted, ownerships)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if len(sorted[i].TestNamePattern) != len(sorted[j].TestNamePattern) {
+			return len(sorted[i].TestNamePattern) > len(sorted[j].TestNamePattern)
+		}
+		return sorted[i].TestNamePattern < sorted[j].TestNamePattern
+	})
+
+	m := &TestOwnershipMatcher{
+		ownerships: sorted,
+		patterns:   make([]*regexp.Regexp, len(sorted)),
+	}
+	for i := range sorted {
+		var err error
+		m.patterns[i], err = regexp.Compile(sorted[i].TestNamePattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "compiling test name pattern '%s'", sorted[i].TestNamePattern)
+		}
+	}
+
+	return m, nil
+}
+
+// GetTestOwnershipMatcher returns a TestOwnershipMatcher for the test
+// ownerships of the given project. The environment should not be nil.
+func GetTestOwnershipMatcher(ctx context.Context, env cedar.Environment, project string) (*TestOwnershipMatcher, error) {
+	ownerships, err := FindTestOwnerships(ctx, env, project)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTestOwnershipMatcher(ownerships)
+}
+
+// Match returns the ownership of the test with the given display name, or nil
+// if the test has no owner. A nil TestOwnershipMatcher matches no tests.
+func (m *TestOwnershipMatcher) Match(testName string) *TestOwnership {
+	if m == nil {
+		return nil
+	}
+
+	for i, pattern := range m.patterns {
+		if pattern.MatchString(testName) {
+			return &m.ownerships[i]
+		}
+	}
+
+	return nil
+}
+
+func (m *TestOwnershipMatcher) setOwner(result *TestResult) {
+	if ownership := m.Match(result.GetDisplayName()); ownership != nil {
+		result.Owner = ownership.Owner
+		result.Team = ownership.Team
+	}
+}
+
+// SetOwners sets the owner and team of each of the given test results that has
+// an owner.
+func (m *TestOwnershipMatcher) SetOwners(results []TestResult) {
+	for i := range results {
+		m.setOwner(&results[i])
+	}
+}
+
+// TeamTestFailures describes the failed tests of a version owned by a team.
+// Failed tests without an owner are grouped under an empty team.
+type TeamTestFailures struct {
+	Team        string
+	NumFailures int
+	FailedTests []OwnedTestFailures
+}
+
+// OwnedTestFailures describes the failures of a single test and its owner.
+type OwnedTestFailures struct {
+	TestName    string
+	Owner       string
+	NumFailures int
+}
+
+// GetTestFailuresByTeam aggregates the failed test results of the latest
+// execution of each task of a version by the team owning the tests. Teams and
+// their tests are sorted by the most failures first. The environment should
+// not be nil.
+func GetTestFailuresByTeam(ctx context.Context, env cedar.Environment, project, version string) ([]TeamTestFailures, error) {
+	if env == nil {
+		return nil, errors.New("cannot aggregate with a nil environment")
+	}
+	if project == "" || version == "" {
+		return nil, errors.New("must specify a project and a version")
+	}
+
+	matcher, err := GetTestOwnershipMatcher(ctx, env, project)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting test ownerships")
+	}
+
+	cur, err := env.GetDB().Collection(testResultsCollection).Find(
+		ctx,
+		bson.M{
+			bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey): project,
+			bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVersionKey): version,
+		},
+		options.Find().SetSort(bson.D{
+			{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskIDKey), Value: 1},
+			{Key: bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoExecutionKey), Value: -1},
+		}),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding test results records")
+	}
+	var records []TestResults
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, errors.Wrap(err, "decoding test results records")
+	}
+
+	var failedRecords []TestResults
+	for i := range records {
+		if i > 0 && records[i].Info.TaskID == records[i-1].Info.TaskID {
+			continue
+		}
+		if records[i].Stats.FailedCount == 0 {
+			continue
+		}
+		records[i].populated = true
+		records[i].env = env
+		failedRecords = append(failedRecords, records[i])
+	}
+
+	failures := map[string]int{}
+	if err = streamTestResults(ctx, failedRecords, &TestResultsIteratorFilter{FailedOnly: true}, func(item testResultsPageItem) {
+		failures[item.result.GetDisplayName()]++
+	}); err != nil {
+		return nil, errors.Wrap(err, "streaming failed test results")
+	}
+
+	return groupTestFailuresByTeam(failures, matcher), nil
+}
+
+func groupTestFailuresByTeam(failures map[string]int, matcher *TestOwnershipMatcher) []TeamTestFailures {
+	teams := map[string]*TeamTestFailures{}
+	for testName, numFailures := range failures {
+		var owner, team string
+		if ownership := matcher.Match(testName); ownership != nil {
+			owner = ownership.Owner
+			team = ownership.Team
+		}
+
+		teamFailures, ok := teams[team]
+		if !ok {
+			teamFailures = &TeamTestFailures{Team: team}
+			teams[team] = teamFailures
+		}
+		teamFailures.NumFailures += numFailures
+		teamFailures.FailedTests = append(teamFailures.FailedTests, OwnedTestFailures{
+			TestName:    testName,
+			Owner:       owner,
+			NumFailures: numFailures,
+		})
+	}
+
+	byTeam := make([]TeamTestFailures, 0, len(teams))
+	for _, teamFailures := range teams {
+		sort.Slice(teamFailures.FailedTests, func(i, j int) bool {
+			if teamFailures.FailedTests[i].NumFailures != teamFailures.FailedTests[j].NumFailures {
+				return teamFailures.FailedTests[i].NumFailures > teamFailures.FailedTests[j].NumFailures
+			}
+			return teamFailures.FailedTests[i].TestName < teamFailures.FailedTests[j].TestName
+		})
+		byTeam = append(byTeam, *teamFailures)
+	}
+	sort.Slice(byTeam, func(i, j int) bool {
+		if byTeam[i].NumFailures != byTeam[j].NumFailures {
+			return byTeam[i].NumFailures > byTeam[j].NumFailures
+		}
+		return byTeam[i].Team < byTeam[j].Team
+	})
+
+	return byTeam
+}
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestOwnershipValidate(t *testing.T) {
+	for testName, testCase := range map[string]struct {
+		ownership TestOwnership
+		hasErr    bool
+	}{
+		"Valid": {
+			ownership: TestOwnership{Project: "p", TestNamePattern: "^TestFoo", Owner: "user", Team: "team"},
+		},
+		"OwnerOnly": {
+			ownership: TestOwnership{Project: "p", TestNamePattern: "^TestFoo", Owner: "user"},
+		},
+		"TeamOnly": {
+			ownership: TestOwnership{Project: "p", TestNamePattern: "^TestFoo", Team: "team"},
+		},
+		"MissingProject": {
+			ownership: TestOwnership{TestNamePattern: "^TestFoo", Owner: "user"},
+			hasErr:    true,
+		},
+		"MissingTestNamePattern": {
+			ownership: TestOwnership{Project: "p", Owner: "user"},
+			hasErr:    true,
+		},
+		"MissingOwnerAndTeam": {
+			ownership: TestOwnership{Project: "p", TestNamePattern: "^TestFoo"},
+			hasErr:    true,
+		},
+		"InvalidTestNamePattern": {
+			ownership: TestOwnership{Project: "p", TestNamePattern: "[", Owner: "user"},
+			hasErr:    true,
+		},
+	} {
+		t.Run(testName, func(t *testing.T) {
+			err := testCase.ownership.Validate()
+			if testCase.hasErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTestOwnershipMatcher(t *testing.T) {
+	matcher, err := NewTestOwnershipMatcher([]TestOwnership{
+		{Project: "p", TestNamePattern: "^Test", Team: "default"},
+		{Project: "p", TestNamePattern: "^TestStorage", Owner: "storage-owner", Team: "storage"},
+		{Project: "p", TestNamePattern: "^TestStorageCache", Owner: "cache-owner", Team: "cache"},
+		{Project: "p", TestNamePattern: "Replication$", Owner: "repl-owner", Team: "replication"},
+	})
+	require.NoError(t, err)
+
+	for testName, expectedTeam := range map[string]string{
+		"TestStorageCacheEviction": "cache",
+		"TestStorageEngine":        "storage",
+		"TestQuery":                "default",
+		"TestStorageReplication":   "replication",
+		"SuiteReplication":         "replication",
+	} {
+		ownership := matcher.Match(testName)
+		require.NotNil(t, ownership, testName)
+		assert.Equal(t, expectedTeam, ownership.Team, testName)
+	}
+	assert.Nil(t, matcher.Match("BenchmarkQuery"))
+
+	var nilMatcher *TestOwnershipMatcher
+	assert.Nil(t, nilMatcher.Match("TestQuery"))
+
+	_, err = NewTestOwnershipMatcher([]TestOwnership{{TestNamePattern: "["}})
+	assert.Error(t, err)
+}
+
+func TestGroupTestFailuresByTeam(t *testing.T) {
+	matcher, err := NewTestOwnershipMatcher([]TestOwnership{
+		{Project: "p", TestNamePattern: "^TestStorage", Owner: "storage-owner", Team: "storage"},
+		{Project: "p", TestNamePattern: "^TestQuery", Owner: "query-owner", Team: "query"},
+	})
+	require.NoError(t, err)
+
+	byTeam := groupTestFailuresByTeam(map[string]int{
+		"TestStorageA": 1,
+		"TestStorageB": 3,
+		"TestQueryA":   2,
+		"TestUnowned":  5,
+	}, matcher)
+	assert.Equal(t, []TeamTestFailures{
+		{
+			Team:        "",
+			NumFailures: 5,
+			FailedTests: []OwnedTestFailures{{TestName: "TestUnowned", NumFailures: 5}},
+		},
+		{
+			Team:        "storage",
+			NumFailures: 4,
+			FailedTests: []OwnedTestFailures{
+				{TestName: "TestStorageB", Owner: "storage-owner", NumFailures: 3},
+				{TestName: "TestStorageA", Owner: "storage-owner", NumFailures: 1},
+			},
+		},
+		{
+			Team:        "query",
+			NumFailures: 2,
+			FailedTests: []OwnedTestFailures{{TestName: "TestQueryA", Owner: "query-owner", NumFailures: 2}},
+		},
+	}, byTeam)
+
+	assert.Empty(t, groupTestFailuresByTeam(map[string]int{}, matcher))
+}
+
+func TestTestOwnershipSaveFindRemove(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(testOwnershipCollection).Drop(ctx))
+	}()
+	require.NoError(t, db.Collection(testOwnershipCollection).Drop(ctx))
+
+	t.Run("NoEnv", func(t *testing.T) {
+		ownership := &TestOwnership{Project: "p", TestNamePattern: "^Test", Owner: "user"}
+		assert.Error(t, ownership.Save(ctx))
+		assert.Error(t, ownership.Find(ctx))
+		assert.Error(t, ownership.Remove(ctx))
+	})
+	t.Run("InvalidSave", func(t *testing.T) {
+		ownership := &TestOwnership{Project: "p", TestNamePattern: "^Test"}
+		ownership.Setup(env)
+		assert.Error(t, ownership.Save(ctx))
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		ownership := &TestOwnership{Project: "p", TestNamePattern: "^TestB", Owner: "user", LastUpdatedBy: "admin"}
+		ownership.Setup(env)
+		require.NoError(t, ownership.Save(ctx))
+		assert.Equal(t, TestOwnershipID("p", "^TestB"), ownership.ID)
+
+		ownership = &TestOwnership{Project: "p", TestNamePattern: "^TestB", Owner: "other", Team: "team"}
+		ownership.Setup(env)
+		require.NoError(t, ownership.Save(ctx))
+
+		other := &TestOwnership{Project: "p", TestNamePattern: "^TestA", Team: "other-team"}
+		other.Setup(env)
+		require.NoError(t, other.Save(ctx))
+		unrelated := &TestOwnership{Project: "p2", TestNamePattern: "^Test", Team: "team"}
+		unrelated.Setup(env)
+		require.NoError(t, unrelated.Save(ctx))
+
+		found := &TestOwnership{ID: ownership.ID}
+		found.Setup(env)
+		require.NoError(t, found.Find(ctx))
+		assert.False(t, found.IsNil())
+		assert.Equal(t, "other", found.Owner)
+		assert.Equal(t, "team", found.Team)
+		assert.Empty(t, found.LastUpdatedBy)
+
+		ownerships, err := FindTestOwnerships(ctx, env, "p")
+		require.NoError(t, err)
+		require.Len(t, ownerships, 2)
+		assert.Equal(t, "^TestA", ownerships[0].TestNamePattern)
+		assert.Equal(t, "^TestB", ownerships[1].TestNamePattern)
+
+		require.NoError(t, found.Remove(ctx))
+		assert.Error(t, found.Find(ctx))
+		ownerships, err = FindTestOwnerships(ctx, env, "p")
+		require.NoError(t, err)
+		assert.Len(t, ownerships, 1)
+	})
+}
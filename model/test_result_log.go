@@ -0,0 +1,89 @@
+package model
+
+// TestResultLogRegion describes the region of a task's buildlogger logs that
+// belongs to a single test result.
+type TestResultLogRegion struct {
+	// Execution is the execution of the task the test result belongs to.
+	Execution int
+	// LogTestName is the test name of the buildlogger logs containing the
+	// region.
+	LogTestName string
+	// StartLine and EndLine are the zero-indexed bounds, [StartLine,
+	// EndLine), of the region in the merged lines of the logs. If EndLine
+	// is 0, the region runs to the end of the logs. These are ignored if
+	// ByTime is true.
+	StartLine int
+	EndLine   int
+	// ByTime is true if the region is bounded by TimeRange instead of by
+	// line numbers. This is the case for logs that interleave the lines of
+	// many tests.
+	ByTime bool
+	// TimeRange is the time range of the region's lines. Unless ByTime is
+	// true, this covers every line of the logs.
+	TimeRange TimeRange
+}
+
+// GetTestResultLogRegion finds the test result with the given name, or
+// display name, in the given results of a single task execution and returns
+// the region of the logs that belongs to it. The region is read from the
+// result's log test name, defaulting to its test name, starting at its line
+// number and ending at the next line number of another result in the same
+// log. If the test ran concurrently with another test in the same log, the
+// region is instead bounded by the test's start and end times. Returns false
+// if no result with the given name exists. If the test has multiple trials,
+// the latest is used.
+func GetTestResultLogRegion(testName string, results []TestResult) (TestResultLogRegion, bool) {
+	var (
+		result TestResult
+		found  bool
+	)
+	for _, r := range results {
+		if r.TestName != testName && r.DisplayTestName != testName {
+			continue
+		}
+		if !found || r.Trial > result.Trial {
+			result = r
+			found = true
+		}
+	}
+	if !found {
+		return TestResultLogRegion{}, false
+	}
+
+	region := TestResultLogRegion{
+		Execution:   result.Execution,
+		LogTestName: result.getLogTestName(),
+		StartLine:   result.LineNum,
+		TimeRange:   TimeRange{EndAt: maxLogFollowTime},
+	}
+	timed := !result.TestStartTime.IsZero() && !result.TestEndTime.IsZero()
+	for _, r := range results {
+		if r.getLogTestName() != region.LogTestName || (r.TestName == result.TestName && r.Trial == result.Trial) {
+			continue
+		}
+
+		if timed && r.TestStartTime.Before(result.TestEndTime) && result.TestStartTime.Before(r.TestEndTime) {
+			region.ByTime = true
+		}
+		if r.LineNum > region.StartLine && (region.EndLine == 0 || r.LineNum < region.EndLine) {
+			region.EndLine = r.LineNum
+		}
+	}
+	if region.ByTime {
+		region.StartLine = 0
+		region.EndLine = 0
+		region.TimeRange = TimeRange{
+			StartAt: result.TestStartTime,
+			EndAt:   result.TestEndTime,
+		}
+	}
+
+	return region, true
+}
+
+func (t TestResult) getLogTestName() string {
+	if t.LogTestName != "" {
+		return t.LogTestName
+	}
+	return t.TestName
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTestResultLogRegion(t *testing.T) {
+	start := time.Now().Round(time.Millisecond).UTC()
+	all := TimeRange{EndAt: maxLogFollowTime}
+	results := []TestResult{
+		{
+			TestName:      "test0",
+			Execution:     1,
+			LogTestName:   "job0",
+			LineNum:       10,
+			TestStartTime: start,
+			TestEndTime:   start.Add(time.Minute),
+		},
+		{
+			TestName:      "test1",
+			Execution:     1,
+			LogTestName:   "job0",
+			LineNum:       50,
+			TestStartTime: start.Add(time.Minute),
+			TestEndTime:   start.Add(2 * time.Minute),
+		},
+		{
+			TestName:      "test2",
+			Execution:     1,
+			LogTestName:   "job0",
+			LineNum:       25,
+			TestStartTime: start.Add(2 * time.Minute),
+			TestEndTime:   start.Add(3 * time.Minute),
+		},
+		{
+			TestName:      "test3",
+			Execution:     1,
+			LogTestName:   "job1",
+			LineNum:       10,
+			TestStartTime: start,
+			TestEndTime:   start.Add(2 * time.Minute),
+		},
+		{
+			TestName:      "test4",
+			Execution:     1,
+			LogTestName:   "job1",
+			LineNum:       20,
+			TestStartTime: start.Add(time.Minute),
+			TestEndTime:   start.Add(3 * time.Minute),
+		},
+		{
+			TestName:        "test5",
+			DisplayTestName: "display5",
+			Execution:       1,
+			LineNum:         5,
+		},
+		{
+			TestName:  "test6",
+			Execution: 1,
+			Trial:     0,
+			LineNum:   1,
+		},
+		{
+			TestName:  "test6",
+			Execution: 1,
+			Trial:     1,
+			LineNum:   2,
+		},
+	}
+
+	for _, test := range []struct {
+		name     string
+		testName string
+		expected TestResultLogRegion
+	}{
+		{
+			name:     "NextLineInSameLog",
+			testName: "test0",
+			expected: TestResultLogRegion{Execution: 1, LogTestName: "job0", StartLine: 10, EndLine: 25, TimeRange: all},
+		},
+		{
+			name:     "LastInLog",
+			testName: "test1",
+			expected: TestResultLogRegion{Execution: 1, LogTestName: "job0", StartLine: 50, TimeRange: all},
+		},
+		{
+			name:     "Interleaved",
+			testName: "test3",
+			expected: TestResultLogRegion{
+				Execution:   1,
+				LogTestName: "job1",
+				ByTime:      true,
+				TimeRange:   TimeRange{StartAt: start, EndAt: start.Add(2 * time.Minute)},
+			},
+		},
+		{
+			name:     "DisplayTestNameAndDefaultLogTestName",
+			testName: "display5",
+			expected: TestResultLogRegion{Execution: 1, LogTestName: "test5", StartLine: 5, TimeRange: all},
+		},
+		{
+			name:     "LatestTrial",
+			testName: "test6",
+			expected: TestResultLogRegion{Execution: 1, LogTestName: "test6", StartLine: 2, TimeRange: all},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			region, ok := GetTestResultLogRegion(test.testName, results)
+			assert.True(t, ok)
+			assert.Equal(t, test.expected, region)
+		})
+	}
+	t.Run("NotFound", func(t *testing.T) {
+		_, ok := GetTestResultLogRegion("DNE", results)
+		assert.False(t, ok)
+	})
+}
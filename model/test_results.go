@@ -1,14 +1,13 @@
 package model
 
 import (
-	"bytes"
+	"container/heap"
 	"context"
 	"crypto/sha1"
 	"fmt"
 
 	"hash"
 	"io"
-	"io/ioutil"
 	"regexp"
 	"runtime"
 	"sort"
@@ -177,6 +176,44 @@ func (t *TestResults) Remove(ctx context.Context) error {
 	return errors.Wrapf(err, "removing test results record '%s'", t.ID)
 }
 
+// RemoveArtifacts removes the test results from the offline blob storage
+// bucket configured for the task execution. The environment should not be
+// nil.
+func (t *TestResults) RemoveArtifacts(ctx context.Context) error {
+	if t.env == nil {
+		return errors.New("cannot remove test results artifacts with a nil environment")
+	}
+
+	if t.ID == "" {
+		t.ID = t.Info.ID()
+	}
+
+	switch t.Artifact.Version {
+	case 0:
+		// Removing an empty prefix would remove every record in the
+		// bucket.
+		if t.Artifact.Prefix == "" {
+			return errors.Errorf("cannot remove artifacts for test results record '%s' without an artifact prefix", t.ID)
+		}
+
+		bucket, err := t.GetBucket(ctx)
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrapf(bucket.RemovePrefix(ctx, ""), "removing artifacts for test results record '%s'", t.ID)
+	case 1:
+		bucket, err := t.GetPrestoBucket(ctx)
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrapf(bucket.Remove(ctx, t.PrestoPartitionKey()), "removing artifacts for test results record '%s'", t.ID)
+	default:
+		return errors.Errorf("unsupported test results artifact version '%d'", t.Artifact.Version)
+	}
+}
+
 // Append uploads test results to the offline blob storage bucket configured
 // for the task execution. The TestResults should be populated and the
 // environment should not be nil.
@@ -255,13 +292,22 @@ func (t *TestResults) uploadParquet(ctx context.Context, results *ParquetTestRes
 }
 
 func (t *TestResults) updateStatsAndFailedSample(ctx context.Context, results []TestResult) error {
-	var failedCount int
+	var (
+		failedCount   int
+		skippedCount  int
+		totalDuration time.Duration
+	)
 	for i := 0; i < len(results); i++ {
 		if strings.Contains(strings.ToLower(results[i].Status), "fail") {
 			if len(t.FailedTestsSample) < FailedTestsSampleSize {
 				t.FailedTestsSample = append(t.FailedTestsSample, results[i].GetDisplayName())
 			}
 			failedCount++
+		} else if isSkippedStatus(results[i].Status) {
+			skippedCount++
+		}
+		if duration := results[i].getDuration(); duration > 0 {
+			totalDuration += duration
 		}
 	}
 
@@ -270,8 +316,10 @@ func (t *TestResults) updateStatsAndFailedSample(ctx context.Context, results []
 		bson.M{testResultsIDKey: t.ID},
 		bson.M{
 			"$inc": bson.M{
-				bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsTotalCountKey):  len(results),
-				bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsFailedCountKey): failedCount,
+				bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsTotalCountKey):    len(results),
+				bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsFailedCountKey):   failedCount,
+				bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsSkippedCountKey):  skippedCount,
+				bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsTotalDurationKey): totalDuration,
 			},
 			"$set": bson.M{
 				testResultsFailedTestsSampleKey: t.FailedTestsSample,
@@ -283,6 +331,8 @@ func (t *TestResults) updateStatsAndFailedSample(ctx context.Context, results []
 		"id":                  t.ID,
 		"inc_total_count":     len(results),
 		"inc_failed_count":    failedCount,
+		"inc_skipped_count":   skippedCount,
+		"inc_total_duration":  totalDuration,
 		"failed_tests_sample": t.FailedTestsSample,
 		"update_result":       updateResult,
 		"op":                  "updating stats and failing tests sample",
@@ -293,6 +343,8 @@ func (t *TestResults) updateStatsAndFailedSample(ctx context.Context, results []
 
 	t.Stats.TotalCount += len(results)
 	t.Stats.FailedCount += failedCount
+	t.Stats.SkippedCount += skippedCount
+	t.Stats.TotalDuration += totalDuration
 
 	return errors.Wrapf(err, "appending to failing tests sample for test result record '%s'", t.ID)
 }
@@ -301,6 +353,31 @@ func (t *TestResults) updateStatsAndFailedSample(ctx context.Context, results []
 // the offline blob storage. The TestResults should be populated and the
 // environment should not be nil.
 func (t *TestResults) Download(ctx context.Context) ([]TestResult, error) {
+	iter, err := t.Stream(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []TestResult
+	for iter.Next(ctx) {
+		results = append(results, iter.Item())
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.Wrap(iter.Err(), "iterating test results")
+	catcher.Wrap(iter.Close(), "closing test results iterator")
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	return results, nil
+}
+
+// Stream returns a TestResultsIterator over the test results stored in the
+// offline blob storage that match the given filter, without loading all of
+// them into memory. A nil filter matches all test results. The TestResults
+// should be populated and the environment should not be nil.
+func (t *TestResults) Stream(ctx context.Context, filter *TestResultsIteratorFilter) (TestResultsIterator, error) {
 	if !t.populated {
 		return nil, errors.New("cannot download without populated test results")
 	}
@@ -319,19 +396,14 @@ func (t *TestResults) Download(ctx context.Context) ([]TestResult, error) {
 			return nil, err
 		}
 
-		var results []TestResult
-		iter := NewTestResultsIterator(bucket)
-		for iter.Next(ctx) {
-			results = append(results, iter.Item())
+		return NewFilteredTestResultsIterator(bucket, filter), nil
+	case 1:
+		prestoBucket, err := t.GetPrestoBucket(ctx)
+		if err != nil {
+			return nil, err
 		}
 
-		catcher := grip.NewBasicCatcher()
-		catcher.Wrap(iter.Err(), "iterating test results")
-		catcher.Wrap(iter.Close(), "closing test results iterator")
-
-		return results, catcher.Resolve()
-	case 1:
-		return t.downloadParquet(ctx)
+		return NewParquetTestResultsIterator(prestoBucket, t.PrestoPartitionKey(), filter), nil
 	default:
 		return nil, errors.Errorf("unsupported test results artifact version '%d'", t.Artifact.Version)
 	}
@@ -343,54 +415,18 @@ func (t *TestResults) downloadParquet(ctx context.Context) ([]TestResult, error)
 		return nil, err
 	}
 
-	r, err := prestoBucket.Get(ctx, t.PrestoPartitionKey())
-	if err != nil {
-		return nil, errors.Wrap(err, "getting Parquet test results")
-	}
-	defer func() {
-		err = r.Close()
-		grip.WarningWhen(err != nil, message.WrapError(err, message.Fields{
-			"message": "closing Presto test results bucket reader",
-		}))
-	}()
-
-	data, err := ioutil.ReadAll(r)
-	if err != nil {
-		return nil, errors.Wrap(err, "reading Parquet test results")
-	}
-
-	fr, err := goparquet.NewFileReader(bytes.NewReader(data))
-	if err != nil {
-		return nil, errors.Wrap(err, "creating Parquet reader")
-	}
-	pr := floor.NewReader(fr)
-	defer func() {
-		err = pr.Close()
-		grip.WarningWhen(err != nil, message.WrapError(err, message.Fields{
-			"message": "closing Parquet test results reader",
-		}))
-	}()
-
-	var parquetResults []ParquetTestResults
-	for pr.Next() {
-		row := ParquetTestResults{}
-		if err := pr.Scan(&row); err != nil {
-			return nil, errors.Wrap(err, "reading Parquet test results row")
-		}
-
-		parquetResults = append(parquetResults, row)
-	}
-
-	if err := pr.Err(); err != nil {
-		return nil, errors.Wrap(err, "reading Parquet test results rows")
-	}
-
 	var results []TestResult
-	for _, result := range parquetResults {
-		results = append(results, result.convertToTestResultSlice()...)
+	iter := NewParquetTestResultsIterator(prestoBucket, t.PrestoPartitionKey(), nil)
+	for iter.Next(ctx) {
+		results = append(results, iter.Item())
+	}
+	// The iterator error is returned as is so callers can check whether
+	// the Parquet object exists.
+	if err = iter.Err(); err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	return results, errors.Wrap(iter.Close(), "closing Parquet test results iterator")
 }
 
 func (t *TestResults) convertToParquet(results []TestResult) *ParquetTestResults {
@@ -454,6 +490,41 @@ func (t *TestResults) Close(ctx context.Context) error {
 	return errors.Wrapf(err, "closing test result record '%s'", t.ID)
 }
 
+// UpdateHistoricalTestData adds the given results of the TestResults to the
+// historical test data. Results are recorded under the display task name, if
+// the task belongs to one. The environment should not be nil.
+func (t *TestResults) UpdateHistoricalTestData(ctx context.Context, results []TestResult) error {
+	if t.env == nil {
+		return errors.New("cannot update historical test data with a nil environment")
+	}
+
+	taskName := t.Info.DisplayTaskName
+	if taskName == "" {
+		taskName = t.Info.TaskName
+	}
+	catcher := grip.NewBasicCatcher()
+	for _, result := range results {
+		info := HistoricalTestDataInfo{
+			Project:     t.Info.Project,
+			Variant:     t.Info.Variant,
+			TaskName:    taskName,
+			TestName:    result.GetDisplayName(),
+			RequestType: t.Info.RequestType,
+			Date:        result.TestEndTime,
+		}
+		htd, err := CreateHistoricalTestData(info)
+		if err != nil {
+			catcher.Wrapf(err, "creating historical test data for test '%s'", info.TestName)
+			continue
+		}
+		htd.Setup(t.env)
+
+		catcher.Wrapf(htd.Update(ctx, result), "updating historical test data for test '%s'", info.TestName)
+	}
+
+	return catcher.Resolve()
+}
+
 // GetBucket returns a bucket of all test results specified by the TestResults
 // metadata object it's called on. The environment should not be nil.
 func (t *TestResults) GetBucket(ctx context.Context) (pail.Bucket, error) {
@@ -565,21 +636,37 @@ func (id *TestResultsInfo) ID() string {
 
 // TestResultsStats describes basic stats of the test results.
 type TestResultsStats struct {
-	TotalCount  int `bson:"total_count"`
-	FailedCount int `bson:"failed_count"`
+	TotalCount   int `bson:"total_count"`
+	FailedCount  int `bson:"failed_count"`
+	SkippedCount int `bson:"skipped_count"`
+	// TotalDuration is the sum of the durations of the tests.
+	TotalDuration time.Duration `bson:"total_duration"`
+	// QuarantinedFailedCount is the number of failed tests, included in
+	// the failed count, that are quarantined. It is computed when the stats
+	// are fetched and is not stored.
+	QuarantinedFailedCount int `bson:"-"`
 }
 
 // TestResultsSample contains test names culled from a test result's FailedTestsSample.
 type TestResultsSample struct {
+	Project                 string
 	TaskID                  string
 	Execution               int
 	MatchingFailedTestNames []string
 	TotalFailedTestNames    int
+	// TestOwners maps the matching failed test names that have an owner to
+	// their ownership.
+	TestOwners map[string]TestOwnership
+	// QuarantinedFailedTestNames are the matching failed test names that
+	// are quarantined.
+	QuarantinedFailedTestNames []string
 }
 
 var (
-	testResultsStatsTotalCountKey  = bsonutil.MustHaveTag(TestResultsStats{}, "TotalCount")
-	testResultsStatsFailedCountKey = bsonutil.MustHaveTag(TestResultsStats{}, "FailedCount")
+	testResultsStatsTotalCountKey    = bsonutil.MustHaveTag(TestResultsStats{}, "TotalCount")
+	testResultsStatsFailedCountKey   = bsonutil.MustHaveTag(TestResultsStats{}, "FailedCount")
+	testResultsStatsSkippedCountKey  = bsonutil.MustHaveTag(TestResultsStats{}, "SkippedCount")
+	testResultsStatsTotalDurationKey = bsonutil.MustHaveTag(TestResultsStats{}, "TotalDuration")
 )
 
 // TestResult describes a single test result to be stored as a BSON object in
@@ -593,6 +680,8 @@ type TestResult struct {
 	Trial           int       `bson:"trial"`
 	Status          string    `bson:"status"`
 	BaseStatus      string    `bson:"-"`
+	Owner           string    `bson:"-"`
+	Team            string    `bson:"-"`
 	LogTestName     string    `bson:"log_test_name,omitempty"`
 	LogURL          string    `bson:"log_url,omitempty"`
 	RawLogURL       string    `bson:"raw_log_url,omitempty"`
@@ -600,6 +689,11 @@ type TestResult struct {
 	TaskCreateTime  time.Time `bson:"task_create_time"`
 	TestStartTime   time.Time `bson:"test_start_time"`
 	TestEndTime     time.Time `bson:"test_end_time"`
+	FailureMessage  string    `bson:"failure_message,omitempty"`
+	StackTrace      string    `bson:"stack_trace,omitempty"`
+	// Attributes are arbitrary key-value pairs describing the test run,
+	// such as a random seed or a shard.
+	Attributes map[string]string `bson:"attributes,omitempty"`
 }
 
 // GetDisplayName returns the human-readable name of the test.
@@ -614,6 +708,10 @@ func (t TestResult) getDuration() time.Duration {
 	return t.TestEndTime.Sub(t.TestStartTime)
 }
 
+func isSkippedStatus(status string) bool {
+	return strings.Contains(strings.ToLower(status), "skip")
+}
+
 func (t TestResult) convertToParquet() ParquetTestResult {
 	result := ParquetTestResult{
 		TestName:       t.TestName,
@@ -641,6 +739,15 @@ func (t TestResult) convertToParquet() ParquetTestResult {
 	if t.LogTestName != "" || t.LogURL != "" || t.RawLogURL != "" {
 		result.LineNum = utility.ToInt32Ptr(int32(t.LineNum))
 	}
+	if t.FailureMessage != "" {
+		result.FailureMessage = utility.ToStringPtr(t.FailureMessage)
+	}
+	if t.StackTrace != "" {
+		result.StackTrace = utility.ToStringPtr(t.StackTrace)
+	}
+	if len(t.Attributes) > 0 {
+		result.Attributes = t.Attributes
+	}
 
 	return result
 }
@@ -755,6 +862,9 @@ func (opts *FindTestSamplesOptions) createFindQuery() map[string]interface{} {
 
 func (opts *FindTestSamplesOptions) createFindOptions() *options.FindOptions {
 	return options.Find().SetProjection(bson.M{
+		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey):       1,
+		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVariantKey):       1,
+		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskNameKey):      1,
 		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskIDKey):        1,
 		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoDisplayTaskIDKey): 1,
 		bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoExecutionKey):     1,
@@ -778,6 +888,7 @@ func (opts *FindTestSamplesOptions) consolidateSamples(testResults []TestResults
 	}
 	displayTaskMap := make(map[taskExecutionPair][]string)
 	executionTaskMap := make(map[taskExecutionPair][]string)
+	projectMap := make(map[taskExecutionPair]string)
 	for _, result := range testResults {
 		if result.Info.DisplayTaskID != "" {
 			dt := taskExecutionPair{
@@ -785,12 +896,14 @@ func (opts *FindTestSamplesOptions) consolidateSamples(testResults []TestResults
 				execution: result.Info.Execution,
 			}
 			displayTaskMap[dt] = append(displayTaskMap[dt], result.FailedTestsSample...)
+			projectMap[dt] = result.Info.Project
 		}
 		et := taskExecutionPair{
 			taskID:    result.Info.TaskID,
 			execution: result.Info.Execution,
 		}
 		executionTaskMap[et] = result.FailedTestsSample
+		projectMap[et] = result.Info.Project
 	}
 
 	samples := make([]TestResultsSample, 0, len(opts.Tasks))
@@ -812,6 +925,7 @@ func (opts *FindTestSamplesOptions) consolidateSamples(testResults []TestResults
 		}
 
 		samples = append(samples, TestResultsSample{
+			Project:                 projectMap[pair],
 			TaskID:                  t.TaskID,
 			Execution:               utility.FromIntPtr(t.Execution),
 			MatchingFailedTestNames: names,
@@ -865,7 +979,40 @@ func GetTestResultsFilteredSamples(ctx context.Context, env cedar.Environment, o
 		return nil, errors.Wrap(err, "decoding test results record(s)")
 	}
 
-	return opts.makeTestSamples(results)
+	samples, err := opts.makeTestSamples(results)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantined, err := quarantinedFailedTestsSample(ctx, env, results)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting quarantined failed tests")
+	}
+
+	matchers := map[string]*TestOwnershipMatcher{}
+	for i := range samples {
+		matcher, ok := matchers[samples[i].Project]
+		if !ok {
+			matcher, err = GetTestOwnershipMatcher(ctx, env, samples[i].Project)
+			if err != nil {
+				return nil, errors.Wrap(err, "getting test ownerships")
+			}
+			matchers[samples[i].Project] = matcher
+		}
+		for _, name := range samples[i].MatchingFailedTestNames {
+			if ownership := matcher.Match(name); ownership != nil {
+				if samples[i].TestOwners == nil {
+					samples[i].TestOwners = map[string]TestOwnership{}
+				}
+				samples[i].TestOwners[name] = *ownership
+			}
+			if quarantined[testResultsSampleKey{taskID: samples[i].TaskID, execution: samples[i].Execution, testName: name}] {
+				samples[i].QuarantinedFailedTestNames = append(samples[i].QuarantinedFailedTestNames, name)
+			}
+		}
+	}
+
+	return samples, nil
 }
 
 // TestResultsSortBy describes the property by which to sort a set of test
@@ -901,11 +1048,25 @@ type FilterAndSortTestResultsOptions struct {
 	Limit        int
 	Page         int
 	BaseResults  *FindTestResultsOptions
+	// Attributes, if not empty, must all be attributes of the tests with
+	// the same values.
+	Attributes map[string]string
 
 	testNameRegex *regexp.Regexp
 	baseStatusMap map[string]string
 }
 
+// iteratorFilter returns the filter to push down into the test results
+// iterators.
+func (o *FilterAndSortTestResultsOptions) iteratorFilter() *TestResultsIteratorFilter {
+	return &TestResultsIteratorFilter{
+		TestNameRegex: o.testNameRegex,
+		Statuses:      o.Statuses,
+		GroupID:       o.GroupID,
+		Attributes:    o.Attributes,
+	}
+}
+
 func (o *FilterAndSortTestResultsOptions) validate() error {
 	catcher := grip.NewBasicCatcher()
 
@@ -935,36 +1096,108 @@ type FindAndDownloadTestResultsOptions struct {
 
 // FindAndDownloadTestResults searches the DB for the TestResults
 // associated with the provided options and returns the downloaded test
-// results filtered, sorted, and paginated. The environment should not be nil.
-// If execution is nil, it will default to the most recent execution.
+// results filtered, sorted, and paginated. The test results are streamed from
+// the offline blob storage: filters are applied while reading and, when
+// paginating, only the test results up to and including the requested page
+// are kept in memory. The environment should not be nil. If execution is nil,
+// it will default to the most recent execution.
 func FindAndDownloadTestResults(ctx context.Context, env cedar.Environment, opts FindAndDownloadTestResultsOptions) ([]TestResult, int, error) {
 	testResults, err := FindTestResults(ctx, env, opts.Find)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	testResultsChan := make(chan TestResults, len(testResults))
+	filterAndSort := opts.FilterAndSort
+	if filterAndSort == nil {
+		filterAndSort = &FilterAndSortTestResultsOptions{}
+	}
+	if err = filterAndSort.validate(); err != nil {
+		return nil, 0, errors.Wrap(err, "validating filter and sort test results options")
+	}
+	if err = filterAndSort.populateBaseStatusMap(ctx, env); err != nil {
+		return nil, 0, err
+	}
+
+	page := newTestResultsPage(filterAndSort)
+	if err = streamTestResults(ctx, testResults, filterAndSort.iteratorFilter(), page.add); err != nil {
+		return nil, 0, err
+	}
+
+	results, totalCount := page.results()
+	return results, totalCount, nil
+}
+
+// filterAndSortCedarTestResults takes a slice of TestResult objects and
+// returns a filtered sorted and paginated version of that slice.
+func filterAndSortTestResults(ctx context.Context, env cedar.Environment, results []TestResult, opts *FilterAndSortTestResultsOptions) ([]TestResult, int, error) {
+	if opts == nil {
+		return results, len(results), nil
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, 0, errors.Wrap(err, "validating filter and sort test results options")
+	}
+	if err := opts.populateBaseStatusMap(ctx, env); err != nil {
+		return nil, 0, err
+	}
+
+	page := newTestResultsPage(opts)
+	filter := opts.iteratorFilter()
+	for i, result := range results {
+		if filter.match(result.GetDisplayName(), result.Status, result.GroupID, result.Attributes) {
+			page.add(testResultsPageItem{result: result, position: i})
+		}
+	}
+
+	results, totalCount := page.results()
+	return results, totalCount, nil
+}
+
+// populateBaseStatusMap streams the base test results, if any, and records
+// the status of each test by display name.
+func (o *FilterAndSortTestResultsOptions) populateBaseStatusMap(ctx context.Context, env cedar.Environment) error {
+	if o.BaseResults == nil {
+		return nil
+	}
+
+	baseTestResults, err := FindTestResults(ctx, env, *o.BaseResults)
+	if err != nil {
+		return errors.Wrap(err, "getting base test results")
+	}
+
+	return errors.Wrap(streamTestResults(ctx, baseTestResults, nil, func(item testResultsPageItem) {
+		o.baseStatusMap[item.result.GetDisplayName()] = item.result.Status
+	}), "getting base test results")
+}
+
+// streamTestResults concurrently streams the test results of the given
+// TestResults records that match the filter and calls add for each one. The
+// add function is never called concurrently.
+func streamTestResults(ctx context.Context, testResults []TestResults, filter *TestResultsIteratorFilter, add func(testResultsPageItem)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	recordsChan := make(chan int, len(testResults))
 	for i := range testResults {
-		testResultsChan <- testResults[i]
+		recordsChan <- i
 	}
-	close(testResultsChan)
+	close(recordsChan)
 
 	var (
-		combinedResults []TestResult
-		cwg             sync.WaitGroup
-		pwg             sync.WaitGroup
+		cwg sync.WaitGroup
+		pwg sync.WaitGroup
 	)
-	combinedResultsChan := make(chan []TestResult, len(testResults))
+	itemsChan := make(chan testResultsPageItem, testResultsIteratorBatchSize)
 	catcher := grip.NewBasicCatcher()
 	cwg.Add(1)
 	go func() {
 		defer func() {
-			catcher.Add(recovery.HandlePanicWithError(recover(), nil, "test results download consumer"))
+			catcher.Add(recovery.HandlePanicWithError(recover(), nil, "test results stream consumer"))
 			cwg.Done()
 		}()
 
-		for results := range combinedResultsChan {
-			combinedResults = append(combinedResults, results...)
+		for item := range itemsChan {
+			add(item)
 		}
 	}()
 
@@ -972,149 +1205,192 @@ func FindAndDownloadTestResults(ctx context.Context, env cedar.Environment, opts
 		pwg.Add(1)
 		go func() {
 			defer func() {
-				catcher.Add(recovery.HandlePanicWithError(recover(), nil, "test results download producer"))
+				catcher.Add(recovery.HandlePanicWithError(recover(), nil, "test results stream producer"))
 				pwg.Done()
 			}()
 
-			for trs := range testResultsChan {
-				results, err := trs.Download(ctx)
-				if err != nil {
+			for record := range recordsChan {
+				if err := streamTestResultsRecord(ctx, &testResults[record], record, filter, itemsChan); err != nil {
 					catcher.Add(err)
+					cancel()
 					return
 				}
-
-				select {
-				case <-ctx.Done():
-					catcher.Add(ctx.Err())
-					return
-				case combinedResultsChan <- results:
-				}
 			}
 		}()
 	}
 	pwg.Wait()
-	close(combinedResultsChan)
+	close(itemsChan)
 	cwg.Wait()
 
-	if catcher.HasErrors() {
-		return nil, 0, catcher.Resolve()
-	}
-
-	return filterAndSortTestResults(ctx, env, combinedResults, opts.FilterAndSort)
+	return catcher.Resolve()
 }
 
-// filterAndSortCedarTestResults takes a slice of TestResult objects and
-// returns a filtered sorted and paginated version of that slice.
-func filterAndSortTestResults(ctx context.Context, env cedar.Environment, results []TestResult, opts *FilterAndSortTestResultsOptions) ([]TestResult, int, error) {
-	if opts == nil {
-		return results, len(results), nil
-	}
-
-	if err := opts.validate(); err != nil {
-		return nil, 0, errors.Wrap(err, "validating filter and sort test results options")
+func streamTestResultsRecord(ctx context.Context, testResults *TestResults, record int, filter *TestResultsIteratorFilter, itemsChan chan<- testResultsPageItem) error {
+	iter, err := testResults.Stream(ctx, filter)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		grip.Warning(message.WrapError(iter.Close(), message.Fields{
+			"message": "closing test results iterator",
+			"id":      testResults.ID,
+		}))
+	}()
 
-	if opts.BaseResults != nil {
-		baseResults, _, err := FindAndDownloadTestResults(ctx, env, FindAndDownloadTestResultsOptions{Find: *opts.BaseResults})
-		if err != nil {
-			return nil, 0, errors.Wrap(err, "getting base test results")
-		}
-		for _, result := range baseResults {
-			opts.baseStatusMap[result.GetDisplayName()] = result.Status
+	var position int
+	for iter.Next(ctx) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case itemsChan <- testResultsPageItem{result: iter.Item(), record: record, position: position}:
 		}
+		position++
 	}
 
-	results = filterTestResults(results, opts)
-	sortTestResults(results, opts)
+	return errors.Wrapf(iter.Err(), "iterating test results '%s'", testResults.ID)
+}
+
+// testResultsPageItem is a test result along with its position in the
+// stream of test results, which is used to break ties when sorting so that
+// the order is stable.
+type testResultsPageItem struct {
+	result   TestResult
+	record   int
+	position int
+}
+
+// testResultsPage collects the requested page of a stream of test results.
+// When paginating, it keeps at most the test results up to and including the
+// requested page in a heap, evicting the last one whenever a test result that
+// sorts before it is added.
+type testResultsPage struct {
+	opts       *FilterAndSortTestResultsOptions
+	maxItems   int
+	items      []testResultsPageItem
+	totalCount int
+}
 
-	totalCount := len(results)
+func newTestResultsPage(opts *FilterAndSortTestResultsOptions) *testResultsPage {
+	page := &testResultsPage{opts: opts}
 	if opts.Limit > 0 {
-		offset := opts.Limit * opts.Page
-		end := offset + opts.Limit
-		if offset > totalCount {
-			offset = totalCount
-		}
-		if end > totalCount {
-			end = totalCount
-		}
-		results = results[offset:end]
+		page.maxItems = opts.Limit * (opts.Page + 1)
 	}
 
-	if len(opts.baseStatusMap) > 0 {
-		for i := range results {
-			results[i].BaseStatus = opts.baseStatusMap[results[i].GetDisplayName()]
+	return page
+}
+
+func (p *testResultsPage) add(item testResultsPageItem) {
+	p.totalCount++
+
+	if p.maxItems == 0 || len(p.items) < p.maxItems {
+		heap.Push(p, item)
+		return
+	}
+	if p.less(item, p.items[0]) {
+		p.items[0] = item
+		heap.Fix(p, 0)
+	}
+}
+
+// results returns the requested page of test results, sorted, along with the
+// total number of test results that were added.
+func (p *testResultsPage) results() ([]TestResult, int) {
+	items, totalCount := p.sortedItems()
+
+	var results []TestResult
+	for _, item := range items {
+		result := item.result
+		if len(p.opts.baseStatusMap) > 0 {
+			result.BaseStatus = p.opts.baseStatusMap[result.GetDisplayName()]
 		}
+		results = append(results, result)
 	}
 
-	return results, totalCount, nil
+	return results, totalCount
 }
 
-func filterTestResults(results []TestResult, opts *FilterAndSortTestResultsOptions) []TestResult {
-	if opts.testNameRegex == nil && len(opts.Statuses) == 0 && opts.GroupID == "" {
-		return results
+// sortedItems returns the items of the requested page, sorted, along with the
+// total number of test results that were added.
+func (p *testResultsPage) sortedItems() ([]testResultsPageItem, int) {
+	sort.Slice(p.items, func(i, j int) bool { return p.less(p.items[i], p.items[j]) })
+
+	offset := p.opts.Limit * p.opts.Page
+	if offset > len(p.items) {
+		offset = len(p.items)
 	}
 
-	var filteredResults []TestResult
-	for _, result := range results {
-		if opts.testNameRegex != nil && !opts.testNameRegex.MatchString(result.GetDisplayName()) {
-			continue
-		}
-		if len(opts.Statuses) > 0 && !utility.StringSliceContains(opts.Statuses, result.Status) {
-			continue
-		}
-		if opts.GroupID != "" && opts.GroupID != result.GroupID {
-			continue
-		}
+	return p.items[offset:], p.totalCount
+}
 
-		filteredResults = append(filteredResults, result)
+// less returns whether the test result a sorts before the test result b.
+func (p *testResultsPage) less(a, b testResultsPageItem) bool {
+	if cmp := p.compare(a.result, b.result); cmp != 0 {
+		if p.opts.SortOrderDSC {
+			return cmp > 0
+		}
+		return cmp < 0
 	}
-
-	return filteredResults
+	if a.record != b.record {
+		return a.record < b.record
+	}
+	return a.position < b.position
 }
 
-func sortTestResults(results []TestResult, opts *FilterAndSortTestResultsOptions) {
-	switch opts.SortBy {
+func (p *testResultsPage) compare(a, b TestResult) int {
+	switch p.opts.SortBy {
 	case TestResultsSortByStart:
-		sort.SliceStable(results, func(i, j int) bool {
-			if opts.SortOrderDSC {
-				return results[i].TestStartTime.After(results[j].TestStartTime)
-			}
-			return results[i].TestStartTime.Before(results[j].TestStartTime)
-		})
+		return compareTimes(a.TestStartTime, b.TestStartTime)
 	case TestResultsSortByDuration:
-		sort.SliceStable(results, func(i, j int) bool {
-			if opts.SortOrderDSC {
-				return results[i].getDuration() > results[j].getDuration()
-			}
-			return results[i].getDuration() < results[j].getDuration()
-		})
+		return compareInts(int64(a.getDuration()), int64(b.getDuration()))
 	case TestResultsSortByTestName:
-		sort.SliceStable(results, func(i, j int) bool {
-			if opts.SortOrderDSC {
-				return results[i].GetDisplayName() > results[j].GetDisplayName()
-			}
-			return results[i].GetDisplayName() < results[j].GetDisplayName()
-		})
+		return strings.Compare(a.GetDisplayName(), b.GetDisplayName())
 	case TestResultsSortByStatus:
-		sort.SliceStable(results, func(i, j int) bool {
-			if opts.SortOrderDSC {
-				return results[i].Status > results[j].Status
-			}
-			return results[i].Status < results[j].Status
-		})
+		return strings.Compare(a.Status, b.Status)
 	case TestResultsSortByBaseStatus:
-		sort.SliceStable(results, func(i, j int) bool {
-			if opts.SortOrderDSC {
-				return opts.baseStatusMap[results[i].GetDisplayName()] > opts.baseStatusMap[results[j].GetDisplayName()]
-			}
-			return opts.baseStatusMap[results[i].GetDisplayName()] < opts.baseStatusMap[results[j].GetDisplayName()]
-		})
+		return strings.Compare(p.opts.baseStatusMap[a.GetDisplayName()], p.opts.baseStatusMap[b.GetDisplayName()])
+	default:
+		return 0
+	}
+}
+
+// Len, Less, Swap, Push, and Pop implement heap.Interface as a max-heap, so
+// the test result that sorts last is always at the root.
+func (p *testResultsPage) Len() int           { return len(p.items) }
+func (p *testResultsPage) Less(i, j int) bool { return p.less(p.items[j], p.items[i]) }
+func (p *testResultsPage) Swap(i, j int)      { p.items[i], p.items[j] = p.items[j], p.items[i] }
+func (p *testResultsPage) Push(x interface{}) { p.items = append(p.items, x.(testResultsPageItem)) }
+func (p *testResultsPage) Pop() interface{} {
+	item := p.items[len(p.items)-1]
+	p.items = p.items[:len(p.items)-1]
+	return item
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
 	}
 }
 
 // GetTestResultsStats fetches basic stats for the test results associated with
-// the provided options. The environment should not be nil. If execution is
-// nil, it will default to the most recent execution.
+// the provided options, counting the quarantined failures apart. The
+// environment should not be nil. If execution is nil, it will default to the
+// most recent execution.
 func GetTestResultsStats(ctx context.Context, env cedar.Environment, opts FindTestResultsOptions) (TestResultsStats, error) {
 	var stats TestResultsStats
 
@@ -1124,7 +1400,15 @@ func GetTestResultsStats(ctx context.Context, env cedar.Environment, opts FindTe
 			return stats, err
 		}
 
-		return testResultsRecords[0].Stats, nil
+		stats = testResultsRecords[0].Stats
+		if stats.FailedCount > 0 {
+			stats.QuarantinedFailedCount, err = countQuarantinedFailures(ctx, env, testResultsRecords)
+			if err != nil {
+				return stats, errors.Wrap(err, "counting quarantined failures")
+			}
+		}
+
+		return stats, nil
 	}
 
 	if env == nil {
@@ -1144,6 +1428,12 @@ func GetTestResultsStats(ctx context.Context, env cedar.Environment, opts FindTe
 			testResultsStatsFailedCountKey: bson.M{
 				"$sum": "$" + bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsFailedCountKey),
 			},
+			testResultsStatsSkippedCountKey: bson.M{
+				"$sum": "$" + bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsSkippedCountKey),
+			},
+			testResultsStatsTotalDurationKey: bson.M{
+				"$sum": "$" + bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsTotalDurationKey),
+			},
 		}},
 	}
 	if opts.Execution == nil {
@@ -1163,7 +1453,21 @@ func GetTestResultsStats(ctx context.Context, env cedar.Environment, opts FindTe
 		return stats, errors.Wrap(mongo.ErrNoDocuments, opts.createErrorMessage())
 	}
 
-	return stats, errors.Wrap(cur.Decode(&stats), "decoding aggregated test results stats")
+	if err = cur.Decode(&stats); err != nil {
+		return stats, errors.Wrap(err, "decoding aggregated test results stats")
+	}
+	if stats.FailedCount > 0 {
+		testResultsRecords, err := FindTestResults(ctx, env, opts)
+		if err != nil {
+			return stats, err
+		}
+		stats.QuarantinedFailedCount, err = countQuarantinedFailures(ctx, env, testResultsRecords)
+		if err != nil {
+			return stats, errors.Wrap(err, "counting quarantined failures")
+		}
+	}
+
+	return stats, nil
 }
 
 // FindTestResultsByProjectOptions represent the set of options for finding
@@ -1233,42 +1537,75 @@ type ParquetTestResults struct {
 }
 
 func (r ParquetTestResults) convertToTestResultSlice() []TestResult {
-	results := make([]TestResult, len(r.Results))
+	return r.convertToFilteredTestResultSlice(nil)
+}
+
+// convertToFilteredTestResultSlice converts the Parquet test results matching
+// the filter, skipping the conversion of the ones that do not match. A nil
+// filter matches all test results.
+func (r ParquetTestResults) convertToFilteredTestResultSlice(filter *TestResultsIteratorFilter) []TestResult {
+	results := make([]TestResult, 0, len(r.Results))
 	for i := range r.Results {
-		results[i] = TestResult{
-			TaskID:          r.TaskID,
-			Execution:       int(r.Execution),
-			TestName:        r.Results[i].TestName,
-			DisplayTestName: utility.FromStringPtr(r.Results[i].DisplayTestName),
-			GroupID:         utility.FromStringPtr(r.Results[i].GroupID),
-			Trial:           int(r.Results[i].Trial),
-			Status:          r.Results[i].Status,
-			LogTestName:     utility.FromStringPtr(r.Results[i].LogTestName),
-			LogURL:          utility.FromStringPtr(r.Results[i].LogURL),
-			RawLogURL:       utility.FromStringPtr(r.Results[i].RawLogURL),
-			LineNum:         int(utility.FromInt32Ptr(r.Results[i].LineNum)),
-			TaskCreateTime:  r.Results[i].TaskCreateTime,
-			TestStartTime:   r.Results[i].TestStartTime,
-			TestEndTime:     r.Results[i].TestEndTime,
+		if r.matches(i, filter) {
+			results = append(results, r.convertToTestResult(i))
 		}
 	}
 
 	return results
 }
 
+// matches returns whether the Parquet test result at the given index matches
+// the filter. A nil filter matches all test results.
+func (r ParquetTestResults) matches(i int, filter *TestResultsIteratorFilter) bool {
+	displayName := utility.FromStringPtr(r.Results[i].DisplayTestName)
+	if displayName == "" {
+		displayName = r.Results[i].TestName
+	}
+
+	return filter.match(displayName, r.Results[i].Status, utility.FromStringPtr(r.Results[i].GroupID), r.Results[i].Attributes)
+}
+
+// convertToTestResult converts the Parquet test result at the given index.
+func (r ParquetTestResults) convertToTestResult(i int) TestResult {
+	return TestResult{
+		TaskID:          r.TaskID,
+		Execution:       int(r.Execution),
+		TestName:        r.Results[i].TestName,
+		DisplayTestName: utility.FromStringPtr(r.Results[i].DisplayTestName),
+		GroupID:         utility.FromStringPtr(r.Results[i].GroupID),
+		Trial:           int(r.Results[i].Trial),
+		Status:          r.Results[i].Status,
+		LogTestName:     utility.FromStringPtr(r.Results[i].LogTestName),
+		LogURL:          utility.FromStringPtr(r.Results[i].LogURL),
+		RawLogURL:       utility.FromStringPtr(r.Results[i].RawLogURL),
+		LineNum:         int(utility.FromInt32Ptr(r.Results[i].LineNum)),
+		TaskCreateTime:  r.Results[i].TaskCreateTime,
+		TestStartTime:   r.Results[i].TestStartTime,
+		TestEndTime:     r.Results[i].TestEndTime,
+		FailureMessage:  utility.FromStringPtr(r.Results[i].FailureMessage),
+		StackTrace:      utility.FromStringPtr(r.Results[i].StackTrace),
+		Attributes:      r.Results[i].Attributes,
+	}
+}
+
 // ParquetTestResult describes a single test result to be stored in Apache
-// Parquet file format.
+// Parquet file format. The failure message, stack trace, and attributes
+// columns are optional, so files written before they were added are still
+// readable.
 type ParquetTestResult struct {
-	TestName        string    `parquet:"name=test_name"`
-	DisplayTestName *string   `parquet:"name=display_test_name"`
-	GroupID         *string   `parquet:"name=group_id"`
-	Trial           int32     `parquet:"name=trial"`
-	Status          string    `parquet:"name=status"`
-	LogTestName     *string   `parquet:"name=log_test_name"`
-	LogURL          *string   `parquet:"name=log_url"`
-	RawLogURL       *string   `parquet:"name=raw_log_url"`
-	LineNum         *int32    `parquet:"name=line_num"`
-	TaskCreateTime  time.Time `parquet:"name=task_create_time, timeunit=MILLIS"`
-	TestStartTime   time.Time `parquet:"name=test_start_time, timeunit=MILLIS"`
-	TestEndTime     time.Time `parquet:"name=test_end_time, timeunit=MILLIS"`
+	TestName        string            `parquet:"name=test_name"`
+	DisplayTestName *string           `parquet:"name=display_test_name"`
+	GroupID         *string           `parquet:"name=group_id"`
+	Trial           int32             `parquet:"name=trial"`
+	Status          string            `parquet:"name=status"`
+	LogTestName     *string           `parquet:"name=log_test_name"`
+	LogURL          *string           `parquet:"name=log_url"`
+	RawLogURL       *string           `parquet:"name=raw_log_url"`
+	LineNum         *int32            `parquet:"name=line_num"`
+	TaskCreateTime  time.Time         `parquet:"name=task_create_time, timeunit=MILLIS"`
+	TestStartTime   time.Time         `parquet:"name=test_start_time, timeunit=MILLIS"`
+	TestEndTime     time.Time         `parquet:"name=test_end_time, timeunit=MILLIS"`
+	FailureMessage  *string           `parquet:"name=failure_message"`
+	StackTrace      *string           `parquet:"name=stack_trace"`
+	Attributes      map[string]string `parquet:"name=attributes"`
 }
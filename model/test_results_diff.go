@@ -0,0 +1,196 @@
+package model
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDurationChangeThreshold = 0.5
+	defaultMinDurationChange       = time.Second
+)
+
+// TestResultsDiffOptions specify the two sets of test results to diff.
+type TestResultsDiffOptions struct {
+	Base    FindTestResultsOptions
+	Current FindTestResultsOptions
+	// DurationChangeThreshold is the minimum change in a test's duration,
+	// relative to its base duration, that is considered large. Defaults to
+	// 0.5. Tests without a recorded duration in either result never have a
+	// large duration change.
+	DurationChangeThreshold float64
+	// MinDurationChange is the minimum absolute change in a test's
+	// duration that is considered large, so short tests do not show up
+	// because of noise. Defaults to one second.
+	MinDurationChange time.Duration
+}
+
+func (opts *TestResultsDiffOptions) validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.Wrap(opts.Base.validate(), "invalid base test results options")
+	catcher.Wrap(opts.Current.validate(), "invalid current test results options")
+	catcher.NewWhen(opts.DurationChangeThreshold < 0, "duration change threshold cannot be negative")
+	catcher.NewWhen(opts.MinDurationChange < 0, "minimum duration change cannot be negative")
+
+	if opts.DurationChangeThreshold == 0 {
+		opts.DurationChangeThreshold = defaultDurationChangeThreshold
+	}
+	if opts.MinDurationChange == 0 {
+		opts.MinDurationChange = defaultMinDurationChange
+	}
+
+	return catcher.Resolve()
+}
+
+// TestResultsDiff describes the differences between a set of test results and
+// a base set of test results, for example a patch task and its mainline base
+// task. Tests are compared by display test name, using their latest trial.
+type TestResultsDiff struct {
+	// NewlyFailing are tests that did not fail in the base results but
+	// fail in the current results.
+	NewlyFailing []TestResultDiff
+	// NewlyPassing are tests that failed in the base results but pass in
+	// the current results.
+	NewlyPassing []TestResultDiff
+	// StillFailing are tests that fail in both sets of results.
+	StillFailing []TestResultDiff
+	// Added are tests that are only in the current results.
+	Added []TestResultDiff
+	// Removed are tests that are only in the base results.
+	Removed []TestResultDiff
+	// DurationChanges are tests whose duration changed by more than the
+	// thresholds, sorted by the largest absolute change first.
+	DurationChanges []TestResultDiff
+}
+
+// TestResultDiff describes a single test in a TestResultsDiff. Either the base
+// or the current result is nil if the test was added or removed.
+type TestResultDiff struct {
+	TestName string
+	Base     *TestResult
+	Current  *TestResult
+}
+
+// DurationChange returns the change in the test's duration from the base
+// result to the current result. Returns zero if either result is missing.
+func (d TestResultDiff) DurationChange() time.Duration {
+	if d.Base == nil || d.Current == nil {
+		return 0
+	}
+
+	return d.Current.getDuration() - d.Base.getDuration()
+}
+
+// DiffTestResults downloads the base and current test results associated with
+// the given options and returns the differences between them. The environment
+// should not be nil. If an execution is nil, it will default to the most
+// recent execution.
+func DiffTestResults(ctx context.Context, env cedar.Environment, opts TestResultsDiffOptions) (*TestResultsDiff, error) {
+	if err := opts.validate(); err != nil {
+		return nil, errors.Wrap(err, "validating test results diff options")
+	}
+
+	base, _, err := FindAndDownloadTestResults(ctx, env, FindAndDownloadTestResultsOptions{Find: opts.Base})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting base test results")
+	}
+	current, _, err := FindAndDownloadTestResults(ctx, env, FindAndDownloadTestResultsOptions{Find: opts.Current})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting current test results")
+	}
+
+	return diffTestResults(base, current, opts), nil
+}
+
+func diffTestResults(base, current []TestResult, opts TestResultsDiffOptions) *TestResultsDiff {
+	baseOutcomes := summarizeTestOutcomes(base)
+	currentOutcomes := summarizeTestOutcomes(current)
+
+	diff := &TestResultsDiff{}
+	for name, outcome := range currentOutcomes {
+		currentResult := outcome.latest
+		baseOutcome, ok := baseOutcomes[name]
+		if !ok {
+			diff.Added = append(diff.Added, TestResultDiff{TestName: name, Current: &currentResult})
+			continue
+		}
+
+		baseResult := baseOutcome.latest
+		entry := TestResultDiff{TestName: name, Base: &baseResult, Current: &currentResult}
+		baseFailed := isFailedStatus(baseResult.Status)
+		currentFailed := isFailedStatus(currentResult.Status)
+		switch {
+		case baseFailed && currentFailed:
+			diff.StillFailing = append(diff.StillFailing, entry)
+		case !baseFailed && currentFailed:
+			diff.NewlyFailing = append(diff.NewlyFailing, entry)
+		case baseFailed && currentResult.Status == "pass":
+			diff.NewlyPassing = append(diff.NewlyPassing, entry)
+		}
+
+		if isLargeDurationChange(baseResult.getDuration(), currentResult.getDuration(), opts) {
+			diff.DurationChanges = append(diff.DurationChanges, entry)
+		}
+	}
+	for name, outcome := range baseOutcomes {
+		if _, ok := currentOutcomes[name]; ok {
+			continue
+		}
+		baseResult := outcome.latest
+		diff.Removed = append(diff.Removed, TestResultDiff{TestName: name, Base: &baseResult})
+	}
+
+	for _, entries := range [][]TestResultDiff{diff.NewlyFailing, diff.NewlyPassing, diff.StillFailing, diff.Added, diff.Removed} {
+		sortTestResultDiffsByName(entries)
+	}
+	sort.SliceStable(diff.DurationChanges, func(i, j int) bool {
+		a := math.Abs(float64(diff.DurationChanges[i].DurationChange()))
+		b := math.Abs(float64(diff.DurationChanges[j].DurationChange()))
+		if a != b {
+			return a > b
+		}
+		return diff.DurationChanges[i].TestName < diff.DurationChanges[j].TestName
+	})
+
+	return diff
+}
+
+// isFailedStatus returns whether the test status is a failure, using the same
+// rule as the test results stats.
+func isFailedStatus(status string) bool {
+	return strings.Contains(strings.ToLower(status), "fail")
+}
+
+// isLargeDurationChange returns whether the change in a test's duration
+// exceeds both the relative threshold and the absolute minimum. Results
+// without a recorded duration have no meaningful change, so a zero base or
+// current duration is never a large change.
+func isLargeDurationChange(baseDuration, currentDuration time.Duration, opts TestResultsDiffOptions) bool {
+	if baseDuration <= 0 || currentDuration <= 0 {
+		return false
+	}
+
+	absChange := currentDuration - baseDuration
+	if absChange < 0 {
+		absChange = -absChange
+	}
+	if absChange == 0 || absChange < opts.MinDurationChange {
+		return false
+	}
+
+	return float64(absChange)/float64(baseDuration) >= opts.DurationChangeThreshold
+}
+
+func sortTestResultDiffsByName(entries []TestResultDiff) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].TestName < entries[j].TestName
+	})
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTestResults(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	newResult := func(name, status string, trial int, duration time.Duration) TestResult {
+		return TestResult{
+			TestName:      name,
+			Status:        status,
+			Trial:         trial,
+			TestStartTime: start,
+			TestEndTime:   start.Add(duration),
+		}
+	}
+	names := func(entries []TestResultDiff) []string {
+		var out []string
+		for _, entry := range entries {
+			out = append(out, entry.TestName)
+		}
+		return out
+	}
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		for _, opts := range []TestResultsDiffOptions{
+			{Current: FindTestResultsOptions{TaskID: "task"}},
+			{Base: FindTestResultsOptions{TaskID: "base"}},
+			{Base: FindTestResultsOptions{TaskID: "base"}, Current: FindTestResultsOptions{TaskID: "task"}, DurationChangeThreshold: -1},
+			{Base: FindTestResultsOptions{TaskID: "base"}, Current: FindTestResultsOptions{TaskID: "task"}, MinDurationChange: -time.Second},
+		} {
+			_, err := DiffTestResults(context.TODO(), nil, opts)
+			assert.Error(t, err)
+		}
+	})
+	t.Run("Categories", func(t *testing.T) {
+		base := []TestResult{
+			newResult("still_failing", "fail", 0, time.Second),
+			newResult("newly_failing", "pass", 0, time.Second),
+			newResult("newly_passing", "fail", 0, time.Second),
+			newResult("removed", "pass", 0, time.Second),
+			newResult("unchanged", "pass", 0, time.Second),
+			newResult("skipped", "fail", 0, time.Second),
+		}
+		current := []TestResult{
+			newResult("still_failing", "silentfail", 0, time.Second),
+			newResult("newly_failing", "fail", 0, time.Second),
+			newResult("newly_passing", "fail", 0, time.Second),
+			newResult("newly_passing", "pass", 1, time.Second),
+			newResult("added", "fail", 0, time.Second),
+			newResult("unchanged", "pass", 0, time.Second),
+			newResult("skipped", "skip", 0, time.Second),
+		}
+		opts := TestResultsDiffOptions{
+			Base:    FindTestResultsOptions{TaskID: "base"},
+			Current: FindTestResultsOptions{TaskID: "task"},
+		}
+		require.NoError(t, opts.validate())
+		diff := diffTestResults(base, current, opts)
+
+		assert.Equal(t, []string{"newly_failing"}, names(diff.NewlyFailing))
+		assert.Equal(t, []string{"newly_passing"}, names(diff.NewlyPassing))
+		assert.Equal(t, 1, diff.NewlyPassing[0].Current.Trial)
+		assert.Equal(t, []string{"still_failing"}, names(diff.StillFailing))
+		assert.Equal(t, []string{"added"}, names(diff.Added))
+		assert.Nil(t, diff.Added[0].Base)
+		assert.Equal(t, []string{"removed"}, names(diff.Removed))
+		assert.Nil(t, diff.Removed[0].Current)
+		assert.Empty(t, diff.DurationChanges)
+	})
+	t.Run("DurationChanges", func(t *testing.T) {
+		base := []TestResult{
+			newResult("slower", "pass", 0, 10*time.Second),
+			newResult("much_slower", "pass", 0, 10*time.Second),
+			newResult("faster", "pass", 0, 10*time.Second),
+			newResult("noise", "pass", 0, 100*time.Millisecond),
+			newResult("small_change", "pass", 0, 10*time.Second),
+			newResult("unrecorded_base", "pass", 0, 0),
+			newResult("unrecorded_current", "pass", 0, 10*time.Second),
+		}
+		current := []TestResult{
+			newResult("slower", "pass", 0, 16*time.Second),
+			newResult("much_slower", "pass", 0, 30*time.Second),
+			newResult("faster", "pass", 0, 2*time.Second),
+			newResult("noise", "pass", 0, 900*time.Millisecond),
+			newResult("small_change", "pass", 0, 12*time.Second),
+			newResult("unrecorded_base", "pass", 0, 30*time.Second),
+			newResult("unrecorded_current", "pass", 0, 0),
+		}
+		opts := TestResultsDiffOptions{
+			Base:    FindTestResultsOptions{TaskID: "base"},
+			Current: FindTestResultsOptions{TaskID: "task"},
+		}
+		require.NoError(t, opts.validate())
+		diff := diffTestResults(base, current, opts)
+
+		assert.Equal(t, []string{"much_slower", "faster", "slower"}, names(diff.DurationChanges))
+		assert.Equal(t, 20*time.Second, diff.DurationChanges[0].DurationChange())
+		assert.Equal(t, -8*time.Second, diff.DurationChanges[1].DurationChange())
+
+		opts.DurationChangeThreshold = 0.1
+		opts.MinDurationChange = 500 * time.Millisecond
+		diff = diffTestResults(base, current, opts)
+		assert.Equal(t, []string{"much_slower", "faster", "slower", "small_change", "noise"}, names(diff.DurationChanges))
+	})
+}
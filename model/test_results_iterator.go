@@ -2,14 +2,21 @@ package model
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
+	"os"
+	"regexp"
 	"runtime"
 	"sync"
 
 	"github.com/evergreen-ci/pail"
+	"github.com/evergreen-ci/utility"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/mongodb/grip/recovery"
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -22,6 +29,52 @@ type TestResultsIterator interface {
 	Item() TestResult
 }
 
+// TestResultsIteratorFilter specifies which test results a TestResultsIterator
+// returns. Test results that do not match are dropped as soon as they are read,
+// so they are never buffered by the iterator.
+type TestResultsIteratorFilter struct {
+	// TestNameRegex, if not nil, must match the display name of the test.
+	TestNameRegex *regexp.Regexp
+	// Statuses, if not empty, must contain the status of the test.
+	Statuses []string
+	// GroupID, if not empty, must equal the group ID of the test.
+	GroupID string
+	// FailedOnly, if true, only matches tests with a failed status.
+	FailedOnly bool
+	// Attributes, if not empty, must all be attributes of the test with the
+	// same values.
+	Attributes map[string]string
+}
+
+func (f *TestResultsIteratorFilter) isEmpty() bool {
+	return f == nil || (f.TestNameRegex == nil && len(f.Statuses) == 0 && f.GroupID == "" && !f.FailedOnly && len(f.Attributes) == 0)
+}
+
+func (f *TestResultsIteratorFilter) match(displayName, status, groupID string, attributes map[string]string) bool {
+	if f.isEmpty() {
+		return true
+	}
+	if f.TestNameRegex != nil && !f.TestNameRegex.MatchString(displayName) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !utility.StringSliceContains(f.Statuses, status) {
+		return false
+	}
+	if f.GroupID != "" && f.GroupID != groupID {
+		return false
+	}
+	if f.FailedOnly && !isFailedStatus(status) {
+		return false
+	}
+	for key, value := range f.Attributes {
+		if actual, ok := attributes[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
 //////////////////
 // Single Iterator
 //////////////////
@@ -32,6 +85,7 @@ type testResultsIterator struct {
 	currentIdx  int
 	items       chan TestResult
 	currentItem TestResult
+	filter      *TestResultsIteratorFilter
 	exhausted   bool
 	closed      bool
 	catcher     grip.Catcher
@@ -39,9 +93,17 @@ type testResultsIterator struct {
 
 // NewTestResultsIterator returns a TestResultsIterator.
 func NewTestResultsIterator(bucket pail.Bucket) TestResultsIterator {
+	return NewFilteredTestResultsIterator(bucket, nil)
+}
+
+// NewFilteredTestResultsIterator returns a TestResultsIterator that only
+// returns the test results matching the given filter. A nil filter matches all
+// test results.
+func NewFilteredTestResultsIterator(bucket pail.Bucket, filter *TestResultsIteratorFilter) TestResultsIterator {
 	return &testResultsIterator{
 		bucket:  bucket,
 		items:   make(chan TestResult, testResultsIteratorBatchSize),
+		filter:  filter,
 		catcher: grip.NewBasicCatcher(),
 	}
 }
@@ -73,7 +135,8 @@ func (i *testResultsIterator) Next(ctx context.Context) bool {
 		}
 	}
 
-	if len(i.items) == 0 {
+	// A batch may be empty if none of its test results match the filter.
+	for len(i.items) == 0 {
 		if i.currentIdx == len(i.bucketItems) {
 			i.exhausted = true
 			return false
@@ -145,6 +208,9 @@ func (i *testResultsIterator) getNextBatch(ctx context.Context) error {
 					catcher.Wrapf(err, "unmarshalling test result '%s'", i.bucketItems[idx].Name())
 					return
 				}
+				if !i.filter.match(result.GetDisplayName(), result.Status, result.GroupID, result.Attributes) {
+					continue
+				}
 
 				i.items <- result
 			}
@@ -172,6 +238,209 @@ func (i *testResultsIterator) Close() error {
 	return nil
 }
 
+///////////////////
+// Parquet Iterator
+///////////////////
+
+type parquetTestResultsIterator struct {
+	bucket      pail.Bucket
+	key         string
+	source      io.ReadSeekCloser
+	reader      *floor.Reader
+	row         *ParquetTestResults
+	rowIdx      int
+	currentItem TestResult
+	filter      *TestResultsIteratorFilter
+	exhausted   bool
+	closed      bool
+	// err is the error that stopped the iteration. It is not collected
+	// with a catcher so that callers can still inspect its cause, e.g.
+	// whether the Parquet object does not exist.
+	err error
+}
+
+// NewParquetTestResultsIterator returns a TestResultsIterator over the test
+// results stored in the Apache Parquet object with the given key. Rows are
+// decoded one at a time and the test results of the current row are
+// converted and filtered one at a time as the iterator advances, so the
+// iterator holds at most one decoded row. Objects from buckets that cannot
+// be read at arbitrary offsets are spooled to a temporary file rather than
+// into memory. A nil filter matches all test results.
+func NewParquetTestResultsIterator(bucket pail.Bucket, key string, filter *TestResultsIteratorFilter) TestResultsIterator {
+	return &parquetTestResultsIterator{
+		bucket: bucket,
+		key:    key,
+		filter: filter,
+	}
+}
+
+func (i *parquetTestResultsIterator) Next(ctx context.Context) bool {
+	if i.exhausted || i.closed || i.err != nil {
+		return false
+	}
+
+	if i.reader == nil {
+		if i.err = i.open(ctx); i.err != nil {
+			return false
+		}
+	}
+
+	for {
+		if i.err = ctx.Err(); i.err != nil {
+			i.closeOnError()
+			return false
+		}
+
+		if i.row != nil && i.rowIdx < len(i.row.Results) {
+			idx := i.rowIdx
+			i.rowIdx++
+			if !i.row.matches(idx, i.filter) {
+				continue
+			}
+
+			i.currentItem = i.row.convertToTestResult(idx)
+			// Release the converted result so that the row's memory
+			// shrinks as the iterator advances.
+			i.row.Results[idx] = ParquetTestResult{}
+			return true
+		}
+
+		if !i.reader.Next() {
+			if i.err = i.reader.Err(); i.err != nil {
+				i.err = errors.Wrap(i.err, "reading Parquet test results rows")
+				i.closeOnError()
+				return false
+			}
+
+			i.exhausted = true
+			i.err = errors.Wrap(i.closeReader(), "closing Parquet test results reader")
+			return false
+		}
+
+		i.row = &ParquetTestResults{}
+		i.rowIdx = 0
+		if err := i.reader.Scan(i.row); err != nil {
+			i.err = errors.Wrap(err, "reading Parquet test results row")
+			i.closeOnError()
+			return false
+		}
+	}
+}
+
+func (i *parquetTestResultsIterator) closeOnError() {
+	grip.Warning(message.WrapError(i.Close(), message.Fields{
+		"message": "closing Parquet test results iterator",
+		"key":     i.key,
+	}))
+}
+
+func (i *parquetTestResultsIterator) open(ctx context.Context) error {
+	source, err := i.openSource(ctx)
+	if err != nil {
+		return err
+	}
+	i.source = source
+
+	fr, err := goparquet.NewFileReader(source)
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrap(err, "creating Parquet reader")
+		catcher.Add(i.closeReader())
+		return catcher.Resolve()
+	}
+	i.reader = floor.NewReader(fr)
+
+	return nil
+}
+
+// openSource returns a seekable reader over the Parquet object, since the
+// Parquet reader needs to seek to the file's footer. Bucket readers that are
+// already seekable, such as local files, are used as is, otherwise the object
+// is streamed into a temporary file that is removed when the reader is
+// closed.
+func (i *parquetTestResultsIterator) openSource(ctx context.Context) (io.ReadSeekCloser, error) {
+	r, err := i.bucket.Get(ctx, i.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting Parquet test results")
+	}
+	if rs, ok := r.(io.ReadSeekCloser); ok {
+		return rs, nil
+	}
+	defer func() {
+		grip.Warning(message.WrapError(r.Close(), message.Fields{
+			"message": "closing Presto test results bucket reader",
+			"key":     i.key,
+		}))
+	}()
+
+	f, err := ioutil.TempFile("", "cedar-parquet-test-results")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary file for Parquet test results")
+	}
+	tmp := &tempFile{File: f}
+	if _, err = io.Copy(tmp, r); err != nil {
+		grip.Warning(message.WrapError(tmp.Close(), message.Fields{
+			"message": "removing temporary Parquet test results file",
+			"key":     i.key,
+		}))
+		return nil, errors.Wrap(err, "downloading Parquet test results")
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		grip.Warning(message.WrapError(tmp.Close(), message.Fields{
+			"message": "removing temporary Parquet test results file",
+			"key":     i.key,
+		}))
+		return nil, errors.Wrap(err, "rewinding temporary Parquet test results file")
+	}
+
+	return tmp, nil
+}
+
+func (i *parquetTestResultsIterator) closeReader() error {
+	catcher := grip.NewBasicCatcher()
+	if i.reader != nil {
+		catcher.Add(i.reader.Close())
+		i.reader = nil
+	}
+	if i.source != nil {
+		catcher.Add(i.source.Close())
+		i.source = nil
+	}
+	i.row = nil
+
+	return catcher.Resolve()
+}
+
+// tempFile is a temporary file that is removed when closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(f.File.Close())
+	catcher.Add(os.Remove(f.Name()))
+
+	return catcher.Resolve()
+}
+
+func (i *parquetTestResultsIterator) Item() TestResult {
+	return i.currentItem
+}
+
+func (i *parquetTestResultsIterator) Exhausted() bool {
+	return i.exhausted
+}
+
+func (i *parquetTestResultsIterator) Err() error {
+	return i.err
+}
+
+func (i *parquetTestResultsIterator) Close() error {
+	i.closed = true
+	return i.closeReader()
+}
+
 /////////////////
 // Multi Iterator
 /////////////////
@@ -3,12 +3,19 @@ package model
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/pail"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/floor"
+	"github.com/fraugster/parquet-go/parquetschema/autoschema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
@@ -100,3 +107,273 @@ func TestTestResultsIterator(t *testing.T) {
 		})
 	}
 }
+
+func TestFilteredTestResultsIterator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "filtered-test-results-iterator-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir})
+	require.NoError(t, err)
+	failed := map[string]TestResult{}
+	for i := 0; i < 2*testResultsIteratorBatchSize+1; i++ {
+		result := getTestResult()
+		if i%50 == 0 {
+			result.Status = "fail"
+			failed[result.TestName] = result
+		}
+		data, err := bson.Marshal(result)
+		require.NoError(t, err)
+		require.NoError(t, bucket.Put(ctx, result.TestName, bytes.NewReader(data)))
+	}
+
+	t.Run("MatchingResults", func(t *testing.T) {
+		iter := NewFilteredTestResultsIterator(bucket, &TestResultsIteratorFilter{Statuses: []string{"fail"}})
+		var numItems int
+		for iter.Next(ctx) {
+			expected, ok := failed[iter.Item().TestName]
+			require.True(t, ok)
+			assert.Equal(t, expected, iter.Item())
+			numItems++
+		}
+		require.NoError(t, iter.Err())
+		assert.True(t, iter.Exhausted())
+		assert.Equal(t, len(failed), numItems)
+	})
+	t.Run("NoMatchingResults", func(t *testing.T) {
+		iter := NewFilteredTestResultsIterator(bucket, &TestResultsIteratorFilter{GroupID: "DNE"})
+		assert.False(t, iter.Next(ctx))
+		assert.NoError(t, iter.Err())
+		assert.True(t, iter.Exhausted())
+	})
+}
+
+func TestParquetTestResultsIterator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "parquet-test-results-iterator-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir})
+	require.NoError(t, err)
+	tr := getTestResults()
+	var rows []*ParquetTestResults
+	var results []TestResult
+	for i := 0; i < 3; i++ {
+		rowResults := make([]TestResult, 10)
+		for j := range rowResults {
+			rowResults[j] = getTestResult()
+			rowResults[j].TaskID = tr.Info.TaskID
+			rowResults[j].Execution = tr.Info.Execution
+			rowResults[j].TestName = fmt.Sprintf("test%d_%d", i, j)
+			if j%3 == 0 {
+				rowResults[j].Status = "fail"
+				rowResults[j].FailureMessage = "failure"
+				rowResults[j].StackTrace = "stack"
+				rowResults[j].Attributes = map[string]string{"shard": fmt.Sprint(i)}
+			}
+		}
+		results = append(results, rowResults...)
+		rows = append(rows, tr.convertToParquet(rowResults))
+	}
+	w, err := bucket.Writer(ctx, "results.parquet")
+	require.NoError(t, err)
+	pw := floor.NewWriter(goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(parquetTestResultsSchemaDef)))
+	for _, row := range rows {
+		require.NoError(t, pw.Write(row))
+	}
+	require.NoError(t, pw.Close())
+	require.NoError(t, w.Close())
+
+	t.Run("AllResults", func(t *testing.T) {
+		iter := NewParquetTestResultsIterator(bucket, "results.parquet", nil)
+		var actual []TestResult
+		for iter.Next(ctx) {
+			actual = append(actual, iter.Item())
+			assert.False(t, iter.Exhausted())
+		}
+		require.NoError(t, iter.Err())
+		assert.True(t, iter.Exhausted())
+		assert.Equal(t, results, actual)
+
+		assert.False(t, iter.Next(ctx))
+		assert.NoError(t, iter.Close())
+	})
+	t.Run("FilteredResults", func(t *testing.T) {
+		filter := &TestResultsIteratorFilter{
+			TestNameRegex: regexp.MustCompile("^test[02]_"),
+			Statuses:      []string{"fail"},
+		}
+		iter := NewParquetTestResultsIterator(bucket, "results.parquet", filter)
+		var actual []TestResult
+		for iter.Next(ctx) {
+			actual = append(actual, iter.Item())
+		}
+		require.NoError(t, iter.Err())
+
+		var expected []TestResult
+		for _, result := range results {
+			if filter.match(result.GetDisplayName(), result.Status, result.GroupID, result.Attributes) {
+				expected = append(expected, result)
+			}
+		}
+		require.NotEmpty(t, expected)
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("FilteredByAttributes", func(t *testing.T) {
+		iter := NewParquetTestResultsIterator(bucket, "results.parquet", &TestResultsIteratorFilter{Attributes: map[string]string{"shard": "1"}})
+		var actual []TestResult
+		for iter.Next(ctx) {
+			actual = append(actual, iter.Item())
+		}
+		require.NoError(t, iter.Err())
+		require.Len(t, actual, 4)
+		for _, result := range actual {
+			assert.Equal(t, "fail", result.Status)
+			assert.Equal(t, "1", result.Attributes["shard"])
+		}
+	})
+	t.Run("NonSeekableBucketReader", func(t *testing.T) {
+		iter := NewParquetTestResultsIterator(&nonSeekableBucket{Bucket: bucket}, "results.parquet", nil)
+		require.True(t, iter.Next(ctx))
+		source, ok := iter.(*parquetTestResultsIterator).source.(*tempFile)
+		require.True(t, ok)
+		_, err := os.Stat(source.Name())
+		require.NoError(t, err)
+
+		actual := []TestResult{iter.Item()}
+		for iter.Next(ctx) {
+			actual = append(actual, iter.Item())
+		}
+		require.NoError(t, iter.Err())
+		assert.Equal(t, results, actual)
+		_, err = os.Stat(source.Name())
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("ClosePreventsNext", func(t *testing.T) {
+		iter := NewParquetTestResultsIterator(bucket, "results.parquet", nil)
+		require.True(t, iter.Next(ctx))
+		require.NoError(t, iter.Close())
+		assert.False(t, iter.Next(ctx))
+	})
+	t.Run("KeyDNE", func(t *testing.T) {
+		iter := NewParquetTestResultsIterator(bucket, "DNE", nil)
+		assert.False(t, iter.Next(ctx))
+		assert.True(t, pail.IsKeyNotFoundError(iter.Err()))
+		assert.False(t, iter.Exhausted())
+	})
+}
+
+// nonSeekableBucket is a pail.Bucket whose readers cannot seek, like the
+// readers of remote buckets.
+type nonSeekableBucket struct {
+	pail.Bucket
+}
+
+func (b *nonSeekableBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := b.Bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return struct{ io.ReadCloser }{r}, nil
+}
+
+func TestParquetTestResultsIteratorLegacySchema(t *testing.T) {
+	// legacyParquetTestResult is the Parquet test result schema before the
+	// failure message, stack trace, and attributes columns were added.
+	type legacyParquetTestResult struct {
+		TestName        string    `parquet:"name=test_name"`
+		DisplayTestName *string   `parquet:"name=display_test_name"`
+		GroupID         *string   `parquet:"name=group_id"`
+		Trial           int32     `parquet:"name=trial"`
+		Status          string    `parquet:"name=status"`
+		LogTestName     *string   `parquet:"name=log_test_name"`
+		LogURL          *string   `parquet:"name=log_url"`
+		RawLogURL       *string   `parquet:"name=raw_log_url"`
+		LineNum         *int32    `parquet:"name=line_num"`
+		TaskCreateTime  time.Time `parquet:"name=task_create_time, timeunit=MILLIS"`
+		TestStartTime   time.Time `parquet:"name=test_start_time, timeunit=MILLIS"`
+		TestEndTime     time.Time `parquet:"name=test_end_time, timeunit=MILLIS"`
+	}
+	type legacyParquetTestResults struct {
+		Version         string                    `parquet:"name=version"`
+		Variant         string                    `parquet:"name=variant"`
+		TaskName        string                    `parquet:"name=task_name"`
+		DisplayTaskName *string                   `parquet:"name=display_task_name"`
+		TaskID          string                    `parquet:"name=task_id"`
+		DisplayTaskID   *string                   `parquet:"name=display_task_id"`
+		Execution       int32                     `parquet:"name=execution"`
+		RequestType     string                    `parquet:"name=request_type"`
+		CreatedAt       time.Time                 `parquet:"name=created_at, timeunit=MILLIS"`
+		Results         []legacyParquetTestResult `parquet:"name=results"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "parquet-test-results-iterator-legacy-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tmpDir})
+	require.NoError(t, err)
+	tr := getTestResults()
+	row := legacyParquetTestResults{
+		Version:     tr.Info.Version,
+		Variant:     tr.Info.Variant,
+		TaskName:    tr.Info.TaskName,
+		TaskID:      tr.Info.TaskID,
+		Execution:   int32(tr.Info.Execution),
+		RequestType: tr.Info.RequestType,
+		CreatedAt:   tr.CreatedAt,
+	}
+	var expected []TestResult
+	for i := 0; i < 5; i++ {
+		result := getTestResult()
+		result.TaskID = tr.Info.TaskID
+		result.Execution = tr.Info.Execution
+		result.DisplayTestName = ""
+		result.GroupID = ""
+		result.LogTestName = ""
+		result.LogURL = ""
+		result.RawLogURL = ""
+		result.LineNum = 0
+		result.FailureMessage = ""
+		result.StackTrace = ""
+		result.Attributes = nil
+		expected = append(expected, result)
+		row.Results = append(row.Results, legacyParquetTestResult{
+			TestName:       result.TestName,
+			Trial:          int32(result.Trial),
+			Status:         result.Status,
+			TaskCreateTime: result.TaskCreateTime,
+			TestStartTime:  result.TestStartTime,
+			TestEndTime:    result.TestEndTime,
+		})
+	}
+	schemaDef, err := autoschema.GenerateSchema(new(legacyParquetTestResults))
+	require.NoError(t, err)
+	w, err := bucket.Writer(ctx, "legacy.parquet")
+	require.NoError(t, err)
+	pw := floor.NewWriter(goparquet.NewFileWriter(w, goparquet.WithSchemaDefinition(schemaDef)))
+	require.NoError(t, pw.Write(row))
+	require.NoError(t, pw.Close())
+	require.NoError(t, w.Close())
+
+	iter := NewParquetTestResultsIterator(bucket, "legacy.parquet", nil)
+	var actual []TestResult
+	for iter.Next(ctx) {
+		actual = append(actual, iter.Item())
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, expected, actual)
+}
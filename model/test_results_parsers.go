@@ -0,0 +1,383 @@
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// TestResultsFormat represents a format of test results reports that can be
+// parsed into test results.
+type TestResultsFormat string
+
+const (
+	// TestResultsFormatJUnit is the JUnit XML report format.
+	TestResultsFormatJUnit TestResultsFormat = "junit"
+	// TestResultsFormatTAP is the Test Anything Protocol format.
+	TestResultsFormatTAP TestResultsFormat = "tap"
+	// TestResultsFormatGoTest is the JSON format produced by `go test -json`
+	// and `go tool test2json`.
+	TestResultsFormatGoTest TestResultsFormat = "gotest"
+)
+
+// Validate ensures that the TestResultsFormat is supported.
+func (f TestResultsFormat) Validate() error {
+	switch f {
+	case TestResultsFormatJUnit, TestResultsFormatTAP, TestResultsFormatGoTest:
+		return nil
+	default:
+		return errors.Errorf("unsupported test results format '%s'", f)
+	}
+}
+
+// ParseTestResultsOptions represent the options for parsing a test results
+// report.
+type ParseTestResultsOptions struct {
+	Format TestResultsFormat
+	// StartAt is the start time of the first test, used when the report
+	// does not include timestamps. Tests without timestamps are assumed to
+	// run one after another. Defaults to the current time.
+	StartAt time.Time
+}
+
+// ParseTestResults parses the test results report in the given format into
+// test results, returning an error if the report is malformed or any of the
+// test results is invalid. The task ID and execution of the returned results
+// are not set.
+func ParseTestResults(r io.Reader, opts ParseTestResultsOptions) ([]TestResult, error) {
+	if err := opts.Format.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.StartAt.IsZero() {
+		opts.StartAt = time.Now()
+	}
+
+	var (
+		results []TestResult
+		err     error
+	)
+	switch opts.Format {
+	case TestResultsFormatJUnit:
+		results, err = parseJUnit(r, opts.StartAt)
+	case TestResultsFormatTAP:
+		results, err = parseTAP(r, opts.StartAt)
+	case TestResultsFormatGoTest:
+		results, err = parseGoTest(r, opts.StartAt)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s test results", opts.Format)
+	}
+	if err = validateParsedTestResults(results); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s test results", opts.Format)
+	}
+
+	return results, nil
+}
+
+func validateParsedTestResults(results []TestResult) error {
+	catcher := grip.NewBasicCatcher()
+	for i, result := range results {
+		catcher.ErrorfWhen(result.TestName == "", "test result %d is missing a test name", i)
+	}
+
+	return catcher.Resolve()
+}
+
+////////
+// JUnit
+////////
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	TestCases []junitTestCase  `xml:"testcase"`
+	Suites    []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Error     *junitFailure `xml:"error"`
+	Skipped   *struct{}     `xml:"skipped"`
+}
+
+// junitFailure is a failure or error element of a test case, where the
+// message attribute holds the failure message and the text holds the stack
+// trace.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func parseJUnit(r io.Reader, startAt time.Time) ([]TestResult, error) {
+	decoder := xml.NewDecoder(r)
+	var root junitTestSuite
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("missing testsuites or testsuite element")
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading XML")
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "testsuites", "testsuite":
+			// Both elements have the same shape, so the root is
+			// decoded as a suite containing the test cases and
+			// nested suites.
+			if err = decoder.DecodeElement(&root, &start); err != nil {
+				return nil, errors.Wrap(err, "decoding XML")
+			}
+		default:
+			return nil, errors.Errorf("unexpected root element '%s'", start.Name.Local)
+		}
+		break
+	}
+
+	var results []TestResult
+	cursor := startAt
+	var addSuite func(junitTestSuite) error
+	addSuite = func(suite junitTestSuite) error {
+		if suite.Timestamp != "" {
+			ts, err := parseJUnitTimestamp(suite.Timestamp)
+			if err != nil {
+				return errors.Wrapf(err, "parsing timestamp of test suite '%s'", suite.Name)
+			}
+			cursor = ts
+		}
+		for _, tc := range suite.TestCases {
+			duration, err := parseJUnitDuration(tc.Time)
+			if err != nil {
+				return errors.Wrapf(err, "parsing time of test case '%s'", tc.Name)
+			}
+
+			result := TestResult{
+				TestName:      tc.Name,
+				Status:        "pass",
+				TestStartTime: cursor,
+				TestEndTime:   cursor.Add(duration),
+			}
+			if tc.ClassName != "" {
+				result.TestName = fmt.Sprintf("%s.%s", tc.ClassName, tc.Name)
+			}
+			switch {
+			case tc.Failure != nil, tc.Error != nil:
+				result.Status = "fail"
+				failure := tc.Failure
+				if failure == nil {
+					failure = tc.Error
+				}
+				result.FailureMessage = failure.Message
+				result.StackTrace = strings.TrimSpace(failure.Text)
+			case tc.Skipped != nil:
+				result.Status = "skip"
+			}
+			results = append(results, result)
+			cursor = result.TestEndTime
+		}
+		for _, nested := range suite.Suites {
+			if err := addSuite(nested); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if err := addSuite(root); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func parseJUnitTimestamp(timestamp string) (time.Time, error) {
+	if ts, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return ts, nil
+	}
+	// JUnit timestamps are ISO 8601 and often omit the time zone.
+	return time.ParseInLocation("2006-01-02T15:04:05", timestamp, time.UTC)
+}
+
+func parseJUnitDuration(seconds string) (time.Duration, error) {
+	if seconds == "" {
+		return 0, nil
+	}
+	secs, err := strconv.ParseFloat(strings.ReplaceAll(seconds, ",", ""), 64)
+	if err != nil {
+		return 0, err
+	}
+	if secs < 0 {
+		return 0, nil
+	}
+
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+//////
+// TAP
+//////
+
+var tapTestLineRegexp = regexp.MustCompile(`^(not )?ok\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(.*))?$`)
+
+func parseTAP(r io.Reader, startAt time.Time) ([]TestResult, error) {
+	var (
+		results   []TestResult
+		durations []time.Duration
+		inYAML    bool
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if inYAML {
+			if trimmed == "..." {
+				inYAML = false
+				continue
+			}
+			if strings.HasPrefix(trimmed, "duration_ms:") && len(results) > 0 {
+				ms, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(trimmed, "duration_ms:")), 64)
+				if err != nil {
+					return nil, errors.Wrapf(err, "parsing duration of test '%s'", results[len(results)-1].TestName)
+				}
+				durations[len(results)-1] = time.Duration(ms * float64(time.Millisecond))
+			}
+			continue
+		}
+		// The YAML diagnostics of a top-level test are indented by
+		// two spaces.
+		if strings.TrimRight(line, " \t") == "  ---" && len(results) > 0 {
+			inYAML = true
+			continue
+		}
+		if strings.HasPrefix(line, "Bail out!") {
+			break
+		}
+
+		// Only top-level test lines are results, indented lines
+		// belong to subtests.
+		match := tapTestLineRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		result := TestResult{
+			TestName: match[3],
+			Status:   "pass",
+		}
+		if match[1] != "" {
+			result.Status = "fail"
+		}
+		if result.TestName == "" {
+			num := match[2]
+			if num == "" {
+				num = strconv.Itoa(len(results) + 1)
+			}
+			result.TestName = fmt.Sprintf("test %s", num)
+		}
+		directive := strings.ToLower(match[4])
+		switch {
+		case strings.HasPrefix(directive, "skip"):
+			result.Status = "skip"
+		case strings.HasPrefix(directive, "todo") && result.Status == "fail":
+			result.Status = "silentfail"
+		}
+		results = append(results, result)
+		durations = append(durations, 0)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading TAP")
+	}
+
+	cursor := startAt
+	for i := range results {
+		results[i].TestStartTime = cursor
+		results[i].TestEndTime = cursor.Add(durations[i])
+		cursor = results[i].TestEndTime
+	}
+
+	return results, nil
+}
+
+//////////
+// Go test
+//////////
+
+type goTestEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+}
+
+func parseGoTest(r io.Reader, startAt time.Time) ([]TestResult, error) {
+	var results []TestResult
+	cursor := startAt
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 16*bufio.MaxScanTokenSize)
+	for lineNum := 0; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		// Build output and other non-JSON lines may be interleaved
+		// with the events.
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		var event goTestEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, errors.Wrapf(err, "decoding event on line %d", lineNum)
+		}
+		if event.Test == "" {
+			continue
+		}
+
+		var status string
+		switch event.Action {
+		case "pass":
+			status = "pass"
+		case "fail":
+			status = "fail"
+		case "skip":
+			status = "skip"
+		default:
+			continue
+		}
+
+		end := event.Time
+		if end.IsZero() {
+			end = cursor.Add(time.Duration(event.Elapsed * float64(time.Second)))
+		}
+		result := TestResult{
+			TestName:      event.Test,
+			Status:        status,
+			TestStartTime: end.Add(-time.Duration(event.Elapsed * float64(time.Second))),
+			TestEndTime:   end,
+		}
+		if event.Package != "" {
+			result.TestName = fmt.Sprintf("%s.%s", event.Package, event.Test)
+		}
+		results = append(results, result)
+		cursor = end
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading events")
+	}
+
+	return results, nil
+}
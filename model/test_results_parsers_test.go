@@ -0,0 +1,131 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTestResults(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := ParseTestResults(strings.NewReader(""), ParseTestResultsOptions{Format: "DNE"})
+		assert.Error(t, err)
+	})
+	t.Run("JUnit", func(t *testing.T) {
+		report := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+	<testsuite name="suite0" timestamp="2021-06-02T08:00:00">
+		<testcase classname="pkg.Class" name="testPass" time="1.5"/>
+		<testcase classname="pkg.Class" name="testFail" time="2">
+			<failure message="expected true">stack</failure>
+		</testcase>
+		<testcase name="testError" time="0.5">
+			<error message="boom"/>
+		</testcase>
+		<testcase name="testSkip" time="0"><skipped/></testcase>
+	</testsuite>
+	<testsuite name="suite1">
+		<testsuite name="nested">
+			<testcase name="testNested" time="1,000"/>
+		</testsuite>
+	</testsuite>
+</testsuites>`
+		results, err := ParseTestResults(strings.NewReader(report), ParseTestResultsOptions{Format: TestResultsFormatJUnit, StartAt: start})
+		require.NoError(t, err)
+
+		suiteStart := time.Date(2021, 6, 2, 8, 0, 0, 0, time.UTC)
+		assert.Equal(t, []TestResult{
+			{TestName: "pkg.Class.testPass", Status: "pass", TestStartTime: suiteStart, TestEndTime: suiteStart.Add(1500 * time.Millisecond)},
+			{TestName: "pkg.Class.testFail", Status: "fail", TestStartTime: suiteStart.Add(1500 * time.Millisecond), TestEndTime: suiteStart.Add(3500 * time.Millisecond), FailureMessage: "expected true", StackTrace: "stack"},
+			{TestName: "testError", Status: "fail", TestStartTime: suiteStart.Add(3500 * time.Millisecond), TestEndTime: suiteStart.Add(4 * time.Second), FailureMessage: "boom"},
+			{TestName: "testSkip", Status: "skip", TestStartTime: suiteStart.Add(4 * time.Second), TestEndTime: suiteStart.Add(4 * time.Second)},
+			{TestName: "testNested", Status: "pass", TestStartTime: suiteStart.Add(4 * time.Second), TestEndTime: suiteStart.Add(1004 * time.Second)},
+		}, results)
+	})
+	t.Run("JUnitSingleSuite", func(t *testing.T) {
+		report := `<testsuite name="suite"><testcase name="test" time="3"/></testsuite>`
+		results, err := ParseTestResults(strings.NewReader(report), ParseTestResultsOptions{Format: TestResultsFormatJUnit, StartAt: start})
+		require.NoError(t, err)
+		assert.Equal(t, []TestResult{
+			{TestName: "test", Status: "pass", TestStartTime: start, TestEndTime: start.Add(3 * time.Second)},
+		}, results)
+	})
+	t.Run("JUnitInvalid", func(t *testing.T) {
+		for _, report := range []string{
+			"",
+			"<html></html>",
+			`<testsuite><testcase name="test" time="one"/></testsuite>`,
+			`<testsuite><testcase name="test" time="1"/><testcase time="1"/></testsuite>`,
+			"<testsuite>",
+		} {
+			_, err := ParseTestResults(strings.NewReader(report), ParseTestResultsOptions{Format: TestResultsFormatJUnit})
+			assert.Error(t, err, report)
+		}
+	})
+	t.Run("TAP", func(t *testing.T) {
+		report := `TAP version 13
+1..6
+ok 1 - first test
+  ---
+  duration_ms: 250.5
+  ...
+not ok 2 - second test
+    # Subtest: nested
+    ok 1 - nested test
+    1..1
+ok 3 - skipped test # SKIP not supported
+not ok 4 - todo test # TODO not written yet
+ok 5
+# a comment
+ok - unnumbered test
+Bail out! Database down.
+ok 7 - after bail out
+`
+		results, err := ParseTestResults(strings.NewReader(report), ParseTestResultsOptions{Format: TestResultsFormatTAP, StartAt: start})
+		require.NoError(t, err)
+
+		firstEnd := start.Add(250500 * time.Microsecond)
+		assert.Equal(t, []TestResult{
+			{TestName: "first test", Status: "pass", TestStartTime: start, TestEndTime: firstEnd},
+			{TestName: "second test", Status: "fail", TestStartTime: firstEnd, TestEndTime: firstEnd},
+			{TestName: "skipped test", Status: "skip", TestStartTime: firstEnd, TestEndTime: firstEnd},
+			{TestName: "todo test", Status: "silentfail", TestStartTime: firstEnd, TestEndTime: firstEnd},
+			{TestName: "test 5", Status: "pass", TestStartTime: firstEnd, TestEndTime: firstEnd},
+			{TestName: "unnumbered test", Status: "pass", TestStartTime: firstEnd, TestEndTime: firstEnd},
+		}, results)
+	})
+	t.Run("TAPInvalidDuration", func(t *testing.T) {
+		report := "ok 1 - test\n  ---\n  duration_ms: fast\n  ...\n"
+		_, err := ParseTestResults(strings.NewReader(report), ParseTestResultsOptions{Format: TestResultsFormatTAP})
+		assert.Error(t, err)
+	})
+	t.Run("GoTest", func(t *testing.T) {
+		report := `# github.com/evergreen-ci/example
+{"Time":"2021-06-01T12:00:00Z","Action":"run","Package":"example","Test":"TestPass"}
+{"Time":"2021-06-01T12:00:01Z","Action":"output","Package":"example","Test":"TestPass","Output":"--- PASS: TestPass (1.00s)\n"}
+{"Time":"2021-06-01T12:00:01Z","Action":"pass","Package":"example","Test":"TestPass","Elapsed":1}
+{"Time":"2021-06-01T12:00:03.5Z","Action":"fail","Package":"example","Test":"TestFail/Sub","Elapsed":2.5}
+{"Time":"2021-06-01T12:00:04Z","Action":"skip","Package":"example","Test":"TestSkip","Elapsed":0}
+{"Time":"2021-06-01T12:00:04Z","Action":"fail","Package":"example","Elapsed":4}
+{"Action":"pass","Test":"TestNoTime","Elapsed":0.5}
+`
+		results, err := ParseTestResults(strings.NewReader(report), ParseTestResultsOptions{Format: TestResultsFormatGoTest, StartAt: start})
+		require.NoError(t, err)
+
+		assert.Equal(t, []TestResult{
+			{TestName: "example.TestPass", Status: "pass", TestStartTime: start, TestEndTime: start.Add(time.Second)},
+			{TestName: "example.TestFail/Sub", Status: "fail", TestStartTime: start.Add(time.Second), TestEndTime: start.Add(3500 * time.Millisecond)},
+			{TestName: "example.TestSkip", Status: "skip", TestStartTime: start.Add(4 * time.Second), TestEndTime: start.Add(4 * time.Second)},
+			{TestName: "TestNoTime", Status: "pass", TestStartTime: start.Add(4 * time.Second), TestEndTime: start.Add(4500 * time.Millisecond)},
+		}, results)
+	})
+	t.Run("GoTestInvalid", func(t *testing.T) {
+		_, err := ParseTestResults(strings.NewReader(`{"Action":"pass",`), ParseTestResultsOptions{Format: TestResultsFormatGoTest})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	searchTestResultsMaxLimit = 1000
+	// searchTestResultsMaxRecords is the maximum number of task
+	// executions whose test results are downloaded for a single search.
+	searchTestResultsMaxRecords = 500
+)
+
+// ErrTooManyTestResultsToSearch is returned when a test results search
+// matches too many task executions and should be narrowed down.
+var ErrTooManyTestResultsToSearch = errors.Errorf("search matches more than %d task executions", searchTestResultsMaxRecords)
+
+// SearchTestResultsOptions specify the test results to search for across the
+// tasks of a project. Either a version, or a variant and a task name within a
+// date range, are required so that a search never downloads the test results
+// of every task of the project.
+type SearchTestResultsOptions struct {
+	Project  string
+	Version  string
+	Variant  string
+	TaskName string
+	// AfterDate and BeforeDate, if set, bound the creation time of the
+	// tasks' test results.
+	AfterDate  time.Time
+	BeforeDate time.Time
+	// TestName is a regular expression matched against the display name
+	// of the tests.
+	TestName string
+	Statuses []string
+	Limit    int
+
+	testNameRegex *regexp.Regexp
+}
+
+func (opts *SearchTestResultsOptions) validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	catcher.NewWhen(opts.Project == "", "must specify a project")
+	catcher.NewWhen(opts.Version == "" && (opts.Variant == "" || opts.TaskName == ""), "must specify a version, or a variant and a task name")
+	catcher.NewWhen(opts.Version == "" && (opts.AfterDate.IsZero() || opts.BeforeDate.IsZero()), "must specify a date range when not specifying a version")
+	catcher.NewWhen(!opts.AfterDate.IsZero() && !opts.BeforeDate.IsZero() && !opts.BeforeDate.After(opts.AfterDate), "before date must be after after date")
+	catcher.NewWhen(opts.Limit <= 0 || opts.Limit > searchTestResultsMaxLimit, "invalid limit")
+
+	if opts.TestName != "" {
+		var err error
+		opts.testNameRegex, err = regexp.Compile(opts.TestName)
+		catcher.Wrap(err, "compiling test name regex")
+	}
+
+	return catcher.Resolve()
+}
+
+func (opts *SearchTestResultsOptions) createFindQuery() bson.M {
+	query := bson.M{bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoProjectKey): opts.Project}
+	if opts.Version != "" {
+		query[bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVersionKey)] = opts.Version
+	}
+	if opts.Variant != "" {
+		query[bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoVariantKey)] = opts.Variant
+	}
+	if opts.TaskName != "" {
+		query[bsonutil.GetDottedKeyName(testResultsInfoKey, testResultsInfoTaskNameKey)] = opts.TaskName
+	}
+
+	createdAt := bson.M{}
+	if !opts.AfterDate.IsZero() {
+		createdAt["$gte"] = opts.AfterDate
+	}
+	if !opts.BeforeDate.IsZero() {
+		createdAt["$lt"] = opts.BeforeDate
+	}
+	if len(createdAt) > 0 {
+		query[testResultsCreatedAtKey] = createdAt
+	}
+
+	// Task executions without failed tests cannot match a search for
+	// failed statuses only, so there is no need to download them.
+	if len(opts.Statuses) > 0 {
+		onlyFailed := true
+		for _, status := range opts.Statuses {
+			onlyFailed = onlyFailed && isFailedStatus(status)
+		}
+		if onlyFailed {
+			query[bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsFailedCountKey)] = bson.M{"$gt": 0}
+		}
+	}
+
+	return query
+}
+
+// TestResultSearchResult is a test result matching a search along with the
+// information of the task execution it belongs to.
+type TestResultSearchResult struct {
+	Info      TestResultsInfo
+	CreatedAt time.Time
+	Result    TestResult
+}
+
+// SearchTestResults finds the test results matching the given options across
+// the tasks of a project and returns them, the most recently created task
+// executions first, along with the total number of matches. The test results
+// of each task execution are streamed from the offline blob storage and only
+// the matching ones, up to the limit, are kept. The environment should not be
+// nil.
+func SearchTestResults(ctx context.Context, env cedar.Environment, opts SearchTestResultsOptions) ([]TestResultSearchResult, int, error) {
+	if env == nil {
+		return nil, 0, errors.New("cannot search with a nil environment")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, 0, errors.Wrap(err, "invalid search options")
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: testResultsCreatedAtKey, Value: -1}}).
+		SetLimit(searchTestResultsMaxRecords + 1)
+	cur, err := env.GetDB().Collection(testResultsCollection).Find(ctx, opts.createFindQuery(), findOpts)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "finding test results records")
+	}
+	var records []TestResults
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, 0, errors.Wrap(err, "decoding test results records")
+	}
+	if len(records) > searchTestResultsMaxRecords {
+		return nil, 0, ErrTooManyTestResultsToSearch
+	}
+	for i := range records {
+		records[i].populated = true
+		records[i].env = env
+	}
+
+	page := newTestResultsPage(&FilterAndSortTestResultsOptions{Limit: opts.Limit})
+	filter := &TestResultsIteratorFilter{TestNameRegex: opts.testNameRegex, Statuses: opts.Statuses}
+	if err = streamTestResults(ctx, records, filter, page.add); err != nil {
+		return nil, 0, errors.Wrap(err, "streaming test results")
+	}
+
+	matcher, err := GetTestOwnershipMatcher(ctx, env, opts.Project)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "getting test ownerships")
+	}
+
+	items, totalCount := page.sortedItems()
+	results := make([]TestResultSearchResult, len(items))
+	for i, item := range items {
+		matcher.setOwner(&item.result)
+		results[i] = TestResultSearchResult{
+			Info:      records[item.record].Info,
+			CreatedAt: records[item.record].CreatedAt,
+			Result:    item.result,
+		}
+	}
+
+	return results, totalCount, nil
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSearchTestResultsOptions(t *testing.T) {
+	now := time.Now()
+	t.Run("Valid", func(t *testing.T) {
+		for _, opts := range []SearchTestResultsOptions{
+			{Project: "project", Version: "version", Limit: 10},
+			{Project: "project", Variant: "variant", TaskName: "task", AfterDate: now.Add(-time.Hour), BeforeDate: now, Limit: 10},
+			{Project: "project", Version: "version", TestName: "^test", Limit: searchTestResultsMaxLimit},
+		} {
+			assert.NoError(t, opts.validate())
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, opts := range []SearchTestResultsOptions{
+			{Version: "version", Limit: 10},
+			{Project: "project", Limit: 10},
+			{Project: "project", AfterDate: now.Add(-time.Hour), BeforeDate: now, Limit: 10},
+			{Project: "project", Variant: "variant", AfterDate: now.Add(-time.Hour), BeforeDate: now, Limit: 10},
+			{Project: "project", TaskName: "task", AfterDate: now.Add(-time.Hour), BeforeDate: now, Limit: 10},
+			{Project: "project", Variant: "variant", TaskName: "task", Limit: 10},
+			{Project: "project", Variant: "variant", TaskName: "task", AfterDate: now.Add(-time.Hour), Limit: 10},
+			{Project: "project", Variant: "variant", TaskName: "task", AfterDate: now, BeforeDate: now.Add(-time.Hour), Limit: 10},
+			{Project: "project", Version: "version"},
+			{Project: "project", Version: "version", Limit: searchTestResultsMaxLimit + 1},
+			{Project: "project", Version: "version", TestName: "(", Limit: 10},
+		} {
+			assert.Error(t, opts.validate())
+		}
+	})
+	t.Run("FailedStatusesQuery", func(t *testing.T) {
+		failedCountKey := bsonutil.GetDottedKeyName(testResultsStatsKey, testResultsStatsFailedCountKey)
+
+		opts := SearchTestResultsOptions{Project: "project", Version: "version", Statuses: []string{"fail", "silentfail"}}
+		assert.Equal(t, bson.M{"$gt": 0}, opts.createFindQuery()[failedCountKey])
+
+		opts.Statuses = append(opts.Statuses, "pass")
+		assert.NotContains(t, opts.createFindQuery(), failedCountKey)
+	})
+}
+
+func TestSearchTestResults(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tmpDir, err := ioutil.TempDir(".", "search-test-results-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, db.Collection(configurationCollection).Drop(ctx))
+		assert.NoError(t, db.Collection(testResultsCollection).Drop(ctx))
+	}()
+	conf := &CedarConfig{
+		Bucket: BucketConfig{
+			TestResultsBucket:       tmpDir,
+			PrestoBucket:            tmpDir,
+			PrestoTestResultsPrefix: "presto-test-results",
+		},
+		populated: true,
+	}
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	var records []*TestResults
+	for i, variant := range []string{"variant0", "variant1", "variant1"} {
+		tr := getTestResults()
+		tr.Info.Project = "project"
+		tr.Info.Version = "version"
+		tr.Info.Variant = variant
+		tr.CreatedAt = time.Now().Add(time.Duration(i-10) * time.Minute).UTC().Round(time.Millisecond)
+		tr.populated = true
+		_, err = db.Collection(testResultsCollection).InsertOne(ctx, tr)
+		require.NoError(t, err)
+
+		results := make([]TestResult, 5)
+		for j := range results {
+			results[j] = getTestResult()
+			results[j].TaskID = tr.Info.TaskID
+			results[j].Execution = tr.Info.Execution
+			results[j].DisplayTestName = ""
+		}
+		results[0].TestName = "test_common"
+		results[0].Status = "fail"
+		tr.Setup(env)
+		require.NoError(t, tr.Append(ctx, results))
+		records = append(records, tr)
+	}
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		_, _, err := SearchTestResults(ctx, env, SearchTestResultsOptions{Project: "project", Limit: 10})
+		assert.Error(t, err)
+	})
+	t.Run("ByVersionAndTestName", func(t *testing.T) {
+		results, totalCount, err := SearchTestResults(ctx, env, SearchTestResultsOptions{
+			Project:  "project",
+			Version:  "version",
+			TestName: "^test_common$",
+			Statuses: []string{"fail"},
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, totalCount)
+		require.Len(t, results, 3)
+		for i, result := range results {
+			record := records[len(records)-1-i]
+			assert.Equal(t, record.Info, result.Info)
+			assert.Equal(t, record.Info.TaskID, result.Result.TaskID)
+			assert.Equal(t, "test_common", result.Result.TestName)
+		}
+	})
+	t.Run("ByVariantWithLimit", func(t *testing.T) {
+		results, totalCount, err := SearchTestResults(ctx, env, SearchTestResultsOptions{
+			Project: "project",
+			Version: "version",
+			Variant: "variant1",
+			Limit:   2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 10, totalCount)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.Equal(t, records[2].Info, result.Info)
+		}
+	})
+	t.Run("ByDateRange", func(t *testing.T) {
+		results, totalCount, err := SearchTestResults(ctx, env, SearchTestResultsOptions{
+			Project:    "project",
+			Variant:    "variant1",
+			TaskName:   records[1].Info.TaskName,
+			AfterDate:  records[1].CreatedAt,
+			BeforeDate: records[2].CreatedAt,
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, totalCount)
+		require.Len(t, results, 5)
+		for _, result := range results {
+			assert.Equal(t, records[1].Info, result.Info)
+		}
+	})
+	t.Run("NoMatches", func(t *testing.T) {
+		results, totalCount, err := SearchTestResults(ctx, env, SearchTestResultsOptions{
+			Project: "DNE",
+			Version: "version",
+			Limit:   10,
+		})
+		require.NoError(t, err)
+		assert.Zero(t, totalCount)
+		assert.Empty(t, results)
+	})
+}
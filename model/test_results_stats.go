@@ -0,0 +1,231 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testResultsStatsMaxQueryLimit = 10000
+
+// TestResultsStatsGroupBy is a task field by which test results stats are
+// grouped.
+type TestResultsStatsGroupBy string
+
+// Fields by which test results stats can be grouped.
+const (
+	TestResultsStatsGroupByVersion  TestResultsStatsGroupBy = "version"
+	TestResultsStatsGroupByVariant  TestResultsStatsGroupBy = "variant"
+	TestResultsStatsGroupByTaskName TestResultsStatsGroupBy = "task_name"
+)
+
+func (g TestResultsStatsGroupBy) validate() error {
+	switch g {
+	case TestResultsStatsGroupByVersion, TestResultsStatsGroupByVariant, TestResultsStatsGroupByTaskName:
+		return nil
+	default:
+		return errors.Errorf("unrecognized test results stats group by '%s'", g)
+	}
+}
+
+// AggregatedTestResultsStats represents the test results stats of a group of
+// tasks. Only the fields the stats are grouped by are set. The skipped count
+// and total duration are only recorded for test results appended after they
+// were added to the stats, so older tasks count all their non-failed tests as
+// passed.
+type AggregatedTestResultsStats struct {
+	Version         string        `bson:"version,omitempty"`
+	Variant         string        `bson:"variant,omitempty"`
+	TaskName        string        `bson:"task_name,omitempty"`
+	NumTasks        int           `bson:"num_tasks"`
+	TotalCount      int           `bson:"total_count"`
+	PassedCount     int           `bson:"-"`
+	FailedCount     int           `bson:"failed_count"`
+	SkippedCount    int           `bson:"skipped_count"`
+	TotalDuration   time.Duration `bson:"total_duration"`
+	LatestCreatedAt time.Time     `bson:"latest_created_at"`
+}
+
+var (
+	aggregatedTestResultsStatsVersionKey         = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "Version")
+	aggregatedTestResultsStatsVariantKey         = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "Variant")
+	aggregatedTestResultsStatsTaskNameKey        = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "TaskName")
+	aggregatedTestResultsStatsNumTasksKey        = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "NumTasks")
+	aggregatedTestResultsStatsTotalCountKey      = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "TotalCount")
+	aggregatedTestResultsStatsFailedCountKey     = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "FailedCount")
+	aggregatedTestResultsStatsSkippedCountKey    = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "SkippedCount")
+	aggregatedTestResultsStatsTotalDurationKey   = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "TotalDuration")
+	aggregatedTestResultsStatsLatestCreatedAtKey = bsonutil.MustHaveTag(AggregatedTestResultsStats{}, "LatestCreatedAt")
+)
+
+// TestResultsStatsFilter represents search parameters when aggregating the
+// test results stats of a project.
+type TestResultsStatsFilter struct {
+	Project string
+	// Version, Variant, and TaskName, if not empty, must equal the
+	// corresponding field of the tasks.
+	Version  string
+	Variant  string
+	TaskName string
+	// AfterDate and BeforeDate, if set, bound the creation time of the
+	// tasks' test results.
+	AfterDate  time.Time
+	BeforeDate time.Time
+	// MainlineOnly, if true, only aggregates the stats of mainline tasks.
+	MainlineOnly bool
+	// GroupBy are the task fields by which the stats are grouped. The
+	// stats are grouped by version, variant, and task name by default.
+	GroupBy []TestResultsStatsGroupBy
+
+	Limit int
+}
+
+// Validate ensures that the TestResultsStatsFilter is valid.
+func (f *TestResultsStatsFilter) Validate() error {
+	if f == nil {
+		return errors.New("test results stats filter should not be nil")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(f.Project == "", "missing Project value")
+	catcher.NewWhen(f.Version == "" && (f.AfterDate.IsZero() || f.BeforeDate.IsZero()), "must specify a Version or an AfterDate/BeforeDate range")
+	catcher.NewWhen(!f.AfterDate.IsZero() && !f.BeforeDate.IsZero() && !f.BeforeDate.After(f.AfterDate), "invalid AfterDate/BeforeDate values")
+	catcher.NewWhen(f.Limit > testResultsStatsMaxQueryLimit || f.Limit <= 0, "invalid Limit value")
+
+	seen := map[TestResultsStatsGroupBy]bool{}
+	for _, groupBy := range f.GroupBy {
+		catcher.Add(groupBy.validate())
+		catcher.ErrorfWhen(seen[groupBy], "duplicate GroupBy value '%s'", groupBy)
+		seen[groupBy] = true
+	}
+
+	return catcher.Resolve()
+}
+
+// GetAggregatedTestResultsStats returns the test results stats of the latest
+// execution of each task matching the filter, summed by group, with the
+// groups of the most recently created tasks first.
+func GetAggregatedTestResultsStats(ctx context.Context, env cedar.Environment, filter TestResultsStatsFilter) ([]AggregatedTestResultsStats, error) {
+	if env == nil {
+		return nil, errors.New("cannot aggregate with a nil environment")
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, errors.Wrap(err, "the provided TestResultsStatsFilter is invalid")
+	}
+
+	var stats []AggregatedTestResultsStats
+	cursor, err := env.GetDB().Collection(testResultsCollection).Aggregate(ctx, filter.queryPipeline(), options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, errors.Wrap(err, "aggregating test results stats")
+	}
+	if err = cursor.All(ctx, &stats); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling aggregated test results stats")
+	}
+	for i := range stats {
+		stats[i].PassedCount = stats[i].TotalCount - stats[i].FailedCount - stats[i].SkippedCount
+	}
+
+	return stats, nil
+}
+
+// queryPipeline creates an aggregation pipeline to query the test results
+// stats of the latest execution of each task, grouped by the filter's fields.
+func (f TestResultsStatsFilter) queryPipeline() []bson.M {
+	infoKey := func(key string) string {
+		return bsonutil.GetDottedKeyName(testResultsInfoKey, key)
+	}
+	statsKey := func(key string) string {
+		return "$" + bsonutil.GetDottedKeyName(testResultsStatsKey, key)
+	}
+
+	match := bson.M{infoKey(testResultsInfoProjectKey): f.Project}
+	if f.Version != "" {
+		match[infoKey(testResultsInfoVersionKey)] = f.Version
+	}
+	if f.Variant != "" {
+		match[infoKey(testResultsInfoVariantKey)] = f.Variant
+	}
+	if f.TaskName != "" {
+		match[infoKey(testResultsInfoTaskNameKey)] = f.TaskName
+	}
+	if f.MainlineOnly {
+		match[infoKey(testResultsInfoMainlineKey)] = true
+	}
+	createdAt := bson.M{}
+	if !f.AfterDate.IsZero() {
+		createdAt["$gte"] = f.AfterDate
+	}
+	if !f.BeforeDate.IsZero() {
+		createdAt["$lt"] = f.BeforeDate
+	}
+	if len(createdAt) > 0 {
+		match[testResultsCreatedAtKey] = createdAt
+	}
+
+	groupBy := f.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = []TestResultsStatsGroupBy{TestResultsStatsGroupByVersion, TestResultsStatsGroupByVariant, TestResultsStatsGroupByTaskName}
+	}
+	groupID := bson.M{}
+	project := bson.M{
+		"_id":                                        0,
+		aggregatedTestResultsStatsNumTasksKey:        1,
+		aggregatedTestResultsStatsTotalCountKey:      1,
+		aggregatedTestResultsStatsFailedCountKey:     1,
+		aggregatedTestResultsStatsSkippedCountKey:    1,
+		aggregatedTestResultsStatsTotalDurationKey:   1,
+		aggregatedTestResultsStatsLatestCreatedAtKey: 1,
+	}
+	sort := bson.D{{Key: aggregatedTestResultsStatsLatestCreatedAtKey, Value: -1}}
+	for _, field := range groupBy {
+		var key string
+		switch field {
+		case TestResultsStatsGroupByVersion:
+			key = aggregatedTestResultsStatsVersionKey
+		case TestResultsStatsGroupByVariant:
+			key = aggregatedTestResultsStatsVariantKey
+		case TestResultsStatsGroupByTaskName:
+			key = aggregatedTestResultsStatsTaskNameKey
+		}
+		groupID[key] = "$" + key
+		project[key] = "$_id." + key
+		sort = append(sort, bson.E{Key: key, Value: 1})
+	}
+
+	return []bson.M{
+		{"$match": match},
+		{"$sort": bson.D{
+			{Key: infoKey(testResultsInfoTaskIDKey), Value: 1},
+			{Key: infoKey(testResultsInfoExecutionKey), Value: -1},
+		}},
+		{"$group": bson.M{
+			"_id":                                        "$" + infoKey(testResultsInfoTaskIDKey),
+			aggregatedTestResultsStatsVersionKey:         bson.M{"$first": "$" + infoKey(testResultsInfoVersionKey)},
+			aggregatedTestResultsStatsVariantKey:         bson.M{"$first": "$" + infoKey(testResultsInfoVariantKey)},
+			aggregatedTestResultsStatsTaskNameKey:        bson.M{"$first": "$" + infoKey(testResultsInfoTaskNameKey)},
+			aggregatedTestResultsStatsTotalCountKey:      bson.M{"$first": statsKey(testResultsStatsTotalCountKey)},
+			aggregatedTestResultsStatsFailedCountKey:     bson.M{"$first": statsKey(testResultsStatsFailedCountKey)},
+			aggregatedTestResultsStatsSkippedCountKey:    bson.M{"$first": statsKey(testResultsStatsSkippedCountKey)},
+			aggregatedTestResultsStatsTotalDurationKey:   bson.M{"$first": statsKey(testResultsStatsTotalDurationKey)},
+			aggregatedTestResultsStatsLatestCreatedAtKey: bson.M{"$first": "$" + testResultsCreatedAtKey},
+		}},
+		{"$group": bson.M{
+			"_id":                                        groupID,
+			aggregatedTestResultsStatsNumTasksKey:        bson.M{"$sum": 1},
+			aggregatedTestResultsStatsTotalCountKey:      bson.M{"$sum": "$" + aggregatedTestResultsStatsTotalCountKey},
+			aggregatedTestResultsStatsFailedCountKey:     bson.M{"$sum": "$" + aggregatedTestResultsStatsFailedCountKey},
+			aggregatedTestResultsStatsSkippedCountKey:    bson.M{"$sum": "$" + aggregatedTestResultsStatsSkippedCountKey},
+			aggregatedTestResultsStatsTotalDurationKey:   bson.M{"$sum": "$" + aggregatedTestResultsStatsTotalDurationKey},
+			aggregatedTestResultsStatsLatestCreatedAtKey: bson.M{"$max": "$" + aggregatedTestResultsStatsLatestCreatedAtKey},
+		}},
+		{"$project": project},
+		{"$sort": sort},
+		{"$limit": f.Limit},
+	}
+}
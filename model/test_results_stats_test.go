@@ -0,0 +1,177 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestResultsStatsFilterValidate(t *testing.T) {
+	day := utility.GetUTCDay(time.Now())
+	valid := TestResultsStatsFilter{
+		Project:    "project",
+		AfterDate:  day.AddDate(0, 0, -7),
+		BeforeDate: day,
+		GroupBy:    []TestResultsStatsGroupBy{TestResultsStatsGroupByVariant},
+		Limit:      10,
+	}
+	assert.NoError(t, valid.Validate())
+
+	t.Run("VersionWithoutDates", func(t *testing.T) {
+		filter := valid
+		filter.Version = "version"
+		filter.AfterDate = time.Time{}
+		filter.BeforeDate = time.Time{}
+		assert.NoError(t, filter.Validate())
+	})
+	for _, test := range []struct {
+		name   string
+		modify func(*TestResultsStatsFilter)
+	}{
+		{name: "MissingProject", modify: func(f *TestResultsStatsFilter) { f.Project = "" }},
+		{name: "MissingVersionAndAfterDate", modify: func(f *TestResultsStatsFilter) { f.AfterDate = time.Time{} }},
+		{name: "MissingVersionAndBeforeDate", modify: func(f *TestResultsStatsFilter) { f.BeforeDate = time.Time{} }},
+		{name: "BeforeDateNotAfterAfterDate", modify: func(f *TestResultsStatsFilter) { f.BeforeDate = f.AfterDate }},
+		{name: "ZeroLimit", modify: func(f *TestResultsStatsFilter) { f.Limit = 0 }},
+		{name: "LimitTooLarge", modify: func(f *TestResultsStatsFilter) { f.Limit = testResultsStatsMaxQueryLimit + 1 }},
+		{name: "InvalidGroupBy", modify: func(f *TestResultsStatsFilter) { f.GroupBy = []TestResultsStatsGroupBy{"test_name"} }},
+		{name: "DuplicateGroupBy", modify: func(f *TestResultsStatsFilter) {
+			f.GroupBy = []TestResultsStatsGroupBy{TestResultsStatsGroupByVariant, TestResultsStatsGroupByVariant}
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			filter := valid
+			test.modify(&filter)
+			assert.Error(t, filter.Validate())
+		})
+	}
+}
+
+func TestGetAggregatedTestResultsStats(t *testing.T) {
+	env := cedar.GetEnvironment()
+	db := env.GetDB()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		assert.NoError(t, db.Collection(testResultsCollection).Drop(ctx))
+	}()
+
+	now := time.Now().UTC().Round(time.Millisecond)
+	for _, tr := range []struct {
+		version  string
+		variant  string
+		taskID   string
+		taskName string
+		exec     int
+		mainline bool
+		stats    TestResultsStats
+		created  time.Time
+	}{
+		{"v0", "variant0", "t0", "task0", 0, true, TestResultsStats{TotalCount: 10, FailedCount: 5}, now.Add(-time.Hour)},
+		{"v0", "variant0", "t0", "task0", 1, true, TestResultsStats{TotalCount: 10, FailedCount: 1, SkippedCount: 2, TotalDuration: time.Minute}, now.Add(-time.Hour)},
+		{"v0", "variant0", "t1", "task1", 0, true, TestResultsStats{TotalCount: 4, SkippedCount: 1, TotalDuration: time.Second}, now.Add(-2 * time.Hour)},
+		{"v0", "variant1", "t2", "task0", 0, true, TestResultsStats{TotalCount: 6, FailedCount: 6, TotalDuration: time.Second}, now.Add(-3 * time.Hour)},
+		{"v1", "variant0", "t3", "task0", 0, true, TestResultsStats{TotalCount: 3}, now},
+		{"p0", "variant0", "t4", "task0", 0, false, TestResultsStats{TotalCount: 7}, now},
+		{"v2", "variant0", "t5", "task0", 0, true, TestResultsStats{TotalCount: 8}, now.AddDate(0, 0, -30)},
+	} {
+		results := getTestResults()
+		results.Info.Project = "project"
+		results.Info.Version = tr.version
+		results.Info.Variant = tr.variant
+		results.Info.TaskID = tr.taskID
+		results.Info.TaskName = tr.taskName
+		results.Info.Execution = tr.exec
+		results.Info.Mainline = tr.mainline
+		results.ID = results.Info.ID()
+		results.Stats = tr.stats
+		results.CreatedAt = tr.created
+		_, err := db.Collection(testResultsCollection).InsertOne(ctx, results)
+		require.NoError(t, err)
+	}
+	baseFilter := func() TestResultsStatsFilter {
+		return TestResultsStatsFilter{
+			Project:    "project",
+			AfterDate:  now.AddDate(0, 0, -7),
+			BeforeDate: now.Add(time.Hour),
+			Limit:      10,
+		}
+	}
+
+	t.Run("NilEnv", func(t *testing.T) {
+		_, err := GetAggregatedTestResultsStats(ctx, nil, baseFilter())
+		assert.Error(t, err)
+	})
+	t.Run("InvalidFilter", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Limit = 0
+		_, err := GetAggregatedTestResultsStats(ctx, env, filter)
+		assert.Error(t, err)
+	})
+	t.Run("ByVersion", func(t *testing.T) {
+		filter := baseFilter()
+		filter.GroupBy = []TestResultsStatsGroupBy{TestResultsStatsGroupByVersion}
+		stats, err := GetAggregatedTestResultsStats(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, stats, 3)
+
+		assert.Equal(t, "p0", stats[0].Version)
+		assert.Equal(t, "v1", stats[1].Version)
+		assert.Equal(t, AggregatedTestResultsStats{
+			Version:         "v0",
+			NumTasks:        3,
+			TotalCount:      20,
+			PassedCount:     10,
+			FailedCount:     7,
+			SkippedCount:    3,
+			TotalDuration:   time.Minute + 2*time.Second,
+			LatestCreatedAt: now.Add(-time.Hour),
+		}, stats[2])
+	})
+	t.Run("MainlineOnly", func(t *testing.T) {
+		filter := baseFilter()
+		filter.MainlineOnly = true
+		filter.GroupBy = []TestResultsStatsGroupBy{TestResultsStatsGroupByVersion}
+		stats, err := GetAggregatedTestResultsStats(ctx, env, filter)
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "v1", stats[0].Version)
+		assert.Equal(t, "v0", stats[1].Version)
+	})
+	t.Run("VersionByVariantAndTaskName", func(t *testing.T) {
+		stats, err := GetAggregatedTestResultsStats(ctx, env, TestResultsStatsFilter{
+			Project: "project",
+			Version: "v0",
+			Limit:   10,
+		})
+		require.NoError(t, err)
+		require.Len(t, stats, 3)
+		for i, expected := range []struct {
+			variant  string
+			taskName string
+			total    int
+		}{
+			{"variant0", "task0", 10},
+			{"variant0", "task1", 4},
+			{"variant1", "task0", 6},
+		} {
+			assert.Equal(t, "v0", stats[i].Version)
+			assert.Equal(t, expected.variant, stats[i].Variant)
+			assert.Equal(t, expected.taskName, stats[i].TaskName)
+			assert.Equal(t, 1, stats[i].NumTasks)
+			assert.Equal(t, expected.total, stats[i].TotalCount)
+		}
+	})
+	t.Run("Limit", func(t *testing.T) {
+		filter := baseFilter()
+		filter.Limit = 1
+		stats, err := GetAggregatedTestResultsStats(ctx, env, filter)
+		require.NoError(t, err)
+		assert.Len(t, stats, 1)
+	})
+}
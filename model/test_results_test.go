@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
@@ -316,6 +317,9 @@ func TestTestResultsAppend(t *testing.T) {
 				expectedParquet.Results[len(expectedParquet.Results)-1].LogURL = utility.ToStringPtr(result.LogURL)
 				expectedParquet.Results[len(expectedParquet.Results)-1].RawLogURL = utility.ToStringPtr(result.RawLogURL)
 				expectedParquet.Results[len(expectedParquet.Results)-1].LineNum = utility.ToInt32Ptr(int32(result.LineNum))
+				expectedParquet.Results[len(expectedParquet.Results)-1].FailureMessage = utility.ToStringPtr(result.FailureMessage)
+				expectedParquet.Results[len(expectedParquet.Results)-1].StackTrace = utility.ToStringPtr(result.StackTrace)
+				expectedParquet.Results[len(expectedParquet.Results)-1].Attributes = result.Attributes
 			}
 		}
 		assert.Equal(t, expectedParquet, parquetResults[0])
@@ -323,8 +327,14 @@ func TestTestResultsAppend(t *testing.T) {
 		// Check metadata.
 		var saved TestResults
 		require.NoError(t, db.Collection(testResultsCollection).FindOne(ctx, bson.M{"_id": tr.ID}).Decode(&saved))
+		var expectedDuration time.Duration
+		for _, result := range results {
+			expectedDuration += result.getDuration()
+		}
 		assert.Equal(t, len(results), saved.Stats.TotalCount)
 		assert.Zero(t, saved.Stats.FailedCount)
+		assert.Zero(t, saved.Stats.SkippedCount)
+		assert.Equal(t, expectedDuration, saved.Stats.TotalDuration)
 		assert.Empty(t, saved.FailedTestsSample)
 
 		failedResults := make([]TestResult, 2*FailedTestsSampleSize)
@@ -334,6 +344,11 @@ func TestTestResultsAppend(t *testing.T) {
 			failedResults[i].Execution = tr.Info.Execution
 			failedResults[i].Status = "Fail"
 		}
+		skippedResult := getTestResult()
+		skippedResult.TaskID = tr.Info.TaskID
+		skippedResult.Execution = tr.Info.Execution
+		skippedResult.Status = "skip"
+		failedResults = append(failedResults, skippedResult)
 		tr.Setup(env)
 		require.NoError(t, tr.Append(ctx, failedResults[0:3]))
 		require.NoError(t, tr.Append(ctx, failedResults[3:]))
@@ -374,14 +389,22 @@ func TestTestResultsAppend(t *testing.T) {
 				expectedParquet.Results[len(expectedParquet.Results)-1].LogURL = utility.ToStringPtr(result.LogURL)
 				expectedParquet.Results[len(expectedParquet.Results)-1].RawLogURL = utility.ToStringPtr(result.RawLogURL)
 				expectedParquet.Results[len(expectedParquet.Results)-1].LineNum = utility.ToInt32Ptr(int32(result.LineNum))
+				expectedParquet.Results[len(expectedParquet.Results)-1].FailureMessage = utility.ToStringPtr(result.FailureMessage)
+				expectedParquet.Results[len(expectedParquet.Results)-1].StackTrace = utility.ToStringPtr(result.StackTrace)
+				expectedParquet.Results[len(expectedParquet.Results)-1].Attributes = result.Attributes
 			}
 		}
 		assert.Equal(t, expectedParquet, parquetResults[0])
 
 		// Check metadata.
 		require.NoError(t, db.Collection(testResultsCollection).FindOne(ctx, bson.M{"_id": tr.ID}).Decode(&saved))
+		for _, result := range failedResults {
+			expectedDuration += result.getDuration()
+		}
 		assert.Equal(t, len(results)+len(failedResults), saved.Stats.TotalCount)
-		assert.Equal(t, len(failedResults), saved.Stats.FailedCount)
+		assert.Equal(t, len(failedResults)-1, saved.Stats.FailedCount)
+		assert.Equal(t, 1, saved.Stats.SkippedCount)
+		assert.Equal(t, expectedDuration, saved.Stats.TotalDuration)
 		require.Len(t, saved.FailedTestsSample, FailedTestsSampleSize)
 		for i, testName := range saved.FailedTestsSample {
 			assert.Equal(t, failedResults[i].GetDisplayName(), testName)
@@ -478,6 +501,9 @@ func TestTestResultsDownload(t *testing.T) {
 				savedParquet.Results[i].LogURL = utility.ToStringPtr(result.LogURL)
 				savedParquet.Results[i].RawLogURL = utility.ToStringPtr(result.RawLogURL)
 				savedParquet.Results[i].LineNum = utility.ToInt32Ptr(int32(result.LineNum))
+				savedParquet.Results[i].FailureMessage = utility.ToStringPtr(result.FailureMessage)
+				savedParquet.Results[i].StackTrace = utility.ToStringPtr(result.StackTrace)
+				savedParquet.Results[i].Attributes = result.Attributes
 			}
 		}
 		w, err := testBucket.Writer(ctx, fmt.Sprintf("%s/%s", conf.Bucket.PrestoTestResultsPrefix, tr.PrestoPartitionKey()))
@@ -1380,6 +1406,95 @@ func TestFilterAndSortTestResults(t *testing.T) {
 	}
 }
 
+func TestTestResultsPage(t *testing.T) {
+	start := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	statuses := []string{"pass", "fail", "silentfail", "skip"}
+	var items []testResultsPageItem
+	baseStatusMap := map[string]string{}
+	for record := 0; record < 3; record++ {
+		for position := 0; position < 100; position++ {
+			// Use few distinct values so that there are many ties.
+			result := TestResult{
+				TestName:      fmt.Sprintf("test%d", rand.Intn(50)),
+				Status:        statuses[rand.Intn(len(statuses))],
+				TestStartTime: start.Add(time.Duration(rand.Intn(10)) * time.Second),
+			}
+			result.TestEndTime = result.TestStartTime.Add(time.Duration(rand.Intn(10)) * time.Second)
+			baseStatusMap[result.TestName] = statuses[rand.Intn(len(statuses))]
+			items = append(items, testResultsPageItem{result: result, record: record, position: position})
+		}
+	}
+	// The expected results are computed the same way as before streaming:
+	// a stable sort of all of the test results.
+	expectedResults := func(opts *FilterAndSortTestResultsOptions) ([]TestResult, int) {
+		var results []TestResult
+		for _, item := range items {
+			result := item.result
+			if len(opts.baseStatusMap) > 0 {
+				result.BaseStatus = opts.baseStatusMap[result.GetDisplayName()]
+			}
+			results = append(results, result)
+		}
+		page := &testResultsPage{opts: opts}
+		sort.SliceStable(results, func(i, j int) bool {
+			cmp := page.compare(results[i], results[j])
+			if opts.SortOrderDSC {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+		totalCount := len(results)
+		if opts.Limit > 0 {
+			offset := opts.Limit * opts.Page
+			end := offset + opts.Limit
+			if offset > totalCount {
+				offset = totalCount
+			}
+			if end > totalCount {
+				end = totalCount
+			}
+			results = results[offset:end]
+		}
+		if len(results) == 0 {
+			return nil, totalCount
+		}
+		return results, totalCount
+	}
+
+	for _, sortBy := range []TestResultsSortBy{"", TestResultsSortByStart, TestResultsSortByDuration, TestResultsSortByTestName, TestResultsSortByStatus, TestResultsSortByBaseStatus} {
+		for _, dsc := range []bool{false, true} {
+			for _, pagination := range [][2]int{{0, 0}, {1, 0}, {25, 0}, {25, 3}, {25, 11}, {25, 12}, {1000, 0}} {
+				name := fmt.Sprintf("SortBy%s/DSC%t/Limit%d/Page%d", sortBy, dsc, pagination[0], pagination[1])
+				t.Run(name, func(t *testing.T) {
+					opts := &FilterAndSortTestResultsOptions{
+						SortBy:        sortBy,
+						SortOrderDSC:  dsc,
+						Limit:         pagination[0],
+						Page:          pagination[1],
+						baseStatusMap: map[string]string{},
+					}
+					if sortBy == TestResultsSortByBaseStatus {
+						opts.baseStatusMap = baseStatusMap
+					}
+
+					page := newTestResultsPage(opts)
+					for _, i := range rand.Perm(len(items)) {
+						page.add(items[i])
+					}
+					if opts.Limit > 0 {
+						assert.LessOrEqual(t, len(page.items), opts.Limit*(opts.Page+1))
+					}
+
+					results, totalCount := page.results()
+					expected, expectedCount := expectedResults(opts)
+					assert.Equal(t, expectedCount, totalCount)
+					assert.Equal(t, expected, results)
+				})
+			}
+		}
+	}
+}
+
 func TestFilterTestNames(t *testing.T) {
 	for testName, testCase := range map[string]struct {
 		names    []string
@@ -1805,6 +1920,9 @@ func getTestResult() TestResult {
 		result.LogURL = utility.RandomString()
 		result.RawLogURL = utility.RandomString()
 		result.LineNum = rand.Intn(1000)
+		result.FailureMessage = utility.RandomString()
+		result.StackTrace = utility.RandomString()
+		result.Attributes = map[string]string{"seed": utility.RandomString()}
 	}
 
 	return result
@@ -43,6 +43,15 @@ func Admin() cli.Command {
 					uploadCerts(),
 				},
 			},
+			{
+				Name:  "test-owners",
+				Usage: "manage the owners and teams of a project's tests over a REST interface",
+				Subcommands: []cli.Command{
+					listTestOwners(),
+					setTestOwner(),
+					removeTestOwner(),
+				},
+			},
 		},
 	}
 }
@@ -108,6 +117,158 @@ func unsetFeatureFlag() cli.Command {
 	}
 }
 
+const (
+	testOwnersProjectFlag  = "project"
+	testOwnersUsernameFlag = "username"
+	testOwnersAPIKeyFlag   = "api-key"
+)
+
+func testOwnersFlags(flags ...cli.Flag) []cli.Flag {
+	return restServiceFlags(append(flags,
+		cli.StringFlag{
+			Name:  testOwnersProjectFlag,
+			Usage: "specify the project of the tests",
+		},
+		cli.StringFlag{
+			Name:  testOwnersUsernameFlag,
+			Usage: "specify the username used to authenticate with the service",
+		},
+		cli.StringFlag{
+			Name:  testOwnersAPIKeyFlag,
+			Usage: "specify the API key used to authenticate with the service",
+		},
+	)...)
+}
+
+func newTestOwnersClient(c *cli.Context) (*rest.Client, error) {
+	client, err := rest.NewClient(rest.ClientOptions{
+		Host:     c.String(clientHostFlag),
+		Port:     c.Int(clientPortFlag),
+		Prefix:   "/rest",
+		Username: c.String(testOwnersUsernameFlag),
+		ApiKey:   c.String(testOwnersAPIKeyFlag),
+	})
+	return client, errors.Wrap(err, "creating REST client")
+}
+
+func listTestOwners() cli.Command {
+	return cli.Command{
+		Name:   "list",
+		Usage:  "list the test name patterns of a project and their owners",
+		Flags:  testOwnersFlags(),
+		Before: mergeBeforeFuncs(setFlagOrFirstPositional(testOwnersProjectFlag), requireStringFlag(testOwnersProjectFlag)),
+		Action: func(c *cli.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client, err := newTestOwnersClient(c)
+			if err != nil {
+				return err
+			}
+
+			ownerships, err := client.GetTestOwnerships(ctx, c.String(testOwnersProjectFlag))
+			if err != nil {
+				return errors.Wrap(err, "getting test owners")
+			}
+
+			out, err := prettyJSON(ownerships)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Println(out)
+
+			return nil
+		},
+	}
+}
+
+func setTestOwner() cli.Command {
+	const (
+		patternFlag = "pattern"
+		ownerFlag   = "owner"
+		teamFlag    = "team"
+	)
+
+	return cli.Command{
+		Name:  "set",
+		Usage: "set the owner and team of a project's tests matching a test name pattern",
+		Flags: testOwnersFlags(
+			cli.StringFlag{
+				Name:  patternFlag,
+				Usage: "specify the regular expression matching the display names of the tests",
+			},
+			cli.StringFlag{
+				Name:  ownerFlag,
+				Usage: "specify the owner of the tests",
+			},
+			cli.StringFlag{
+				Name:  teamFlag,
+				Usage: "specify the team owning the tests",
+			},
+		),
+		Before: mergeBeforeFuncs(requireStringFlag(testOwnersProjectFlag), requireStringFlag(patternFlag)),
+		Action: func(c *cli.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client, err := newTestOwnersClient(c)
+			if err != nil {
+				return err
+			}
+
+			ownership, err := client.SaveTestOwnership(ctx, c.String(testOwnersProjectFlag), c.String(patternFlag), c.String(ownerFlag), c.String(teamFlag))
+			if err != nil {
+				return errors.Wrap(err, "setting test owner")
+			}
+
+			out, err := prettyJSON(ownership)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Println(out)
+
+			return nil
+		},
+	}
+}
+
+func removeTestOwner() cli.Command {
+	const idFlag = "id"
+
+	return cli.Command{
+		Name:  "remove",
+		Usage: "remove a test name pattern, by ID, from the test owners of a project",
+		Flags: testOwnersFlags(
+			cli.StringFlag{
+				Name:  idFlag,
+				Usage: "specify the ID of the test owner, may also be the first positional argument",
+			},
+		),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(testOwnersProjectFlag),
+			setFlagOrFirstPositional(idFlag),
+			requireStringFlag(idFlag),
+		),
+		Action: func(c *cli.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client, err := newTestOwnersClient(c)
+			if err != nil {
+				return err
+			}
+
+			id := c.String(idFlag)
+			if err = client.RemoveTestOwnership(ctx, c.String(testOwnersProjectFlag), id); err != nil {
+				return errors.Wrapf(err, "removing test owner '%s'", id)
+			}
+			grip.Infof("successfully removed test owner '%s'", id)
+
+			return nil
+		},
+	}
+}
+
 func getUserCert() cli.Command {
 	const (
 		userNameFlag    = "username"
@@ -7,11 +7,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/cedar/rest"
+	restModel "github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
@@ -33,6 +39,9 @@ func Client() cli.Command {
 			getSystemStatusEvents(),
 			systemEvent(),
 			systemInfo(),
+			logs(),
+			testResults(),
+			perf(),
 		},
 	}
 }
@@ -478,3 +487,647 @@ func systemInfoImport() cli.Command {
 		},
 	}
 }
+
+func logs() cli.Command {
+	return cli.Command{
+		Name:  "logs",
+		Usage: "access buildlogger logs",
+		Subcommands: []cli.Command{
+			logsFollow(),
+		},
+	}
+}
+
+func logsFollow() cli.Command {
+	const (
+		idFlag            = "id"
+		startFlag         = "start"
+		printTimeFlag     = "print-time"
+		printPriorityFlag = "print-priority"
+		minPriorityFlag   = "min-priority"
+		maxPriorityFlag   = "max-priority"
+		includeFlag       = "include"
+		excludeFlag       = "exclude"
+		whereFlag         = "where"
+		fieldFlag         = "field"
+	)
+
+	return cli.Command{
+		Name:  "follow",
+		Usage: "prints the lines of a buildlogger log as they are appended until the log is complete",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  idFlag,
+				Usage: "specify the ID of the log, may also be the first positional argument",
+			},
+			cli.StringFlag{
+				Name:  startFlag,
+				Usage: "RFC3339 formatted time, lines before it are skipped",
+			},
+			cli.BoolFlag{
+				Name:  printTimeFlag,
+				Usage: "print the timestamp of each line",
+			},
+			cli.BoolFlag{
+				Name:  printPriorityFlag,
+				Usage: "print the priority of each line",
+			},
+			cli.IntFlag{
+				Name:  minPriorityFlag,
+				Usage: "only print lines with at least this priority",
+			},
+			cli.IntFlag{
+				Name:  maxPriorityFlag,
+				Usage: "only print lines with at most this priority",
+			},
+			cli.StringFlag{
+				Name:  includeFlag,
+				Usage: "only print lines matching this regular expression",
+			},
+			cli.StringFlag{
+				Name:  excludeFlag,
+				Usage: "do not print lines matching this regular expression",
+			},
+			cli.StringSliceFlag{
+				Name:  whereFlag,
+				Usage: "only print structured lines whose field has the value, in the form 'field:value' (may be specified multiple times)",
+			},
+			cli.StringSliceFlag{
+				Name:  fieldFlag,
+				Usage: "only print this field of structured lines (may be specified multiple times)",
+			},
+		},
+		Before: mergeBeforeFuncs(setFlagOrFirstPositional(idFlag), requireStringFlag(idFlag)),
+		Action: func(c *cli.Context) error {
+			ctx := context.Background()
+
+			opts := rest.ClientOptions{
+				Host:   c.Parent().Parent().String(clientHostFlag),
+				Port:   c.Parent().Parent().Int(clientPortFlag),
+				Prefix: "/rest",
+			}
+			client, err := rest.NewClient(opts)
+			if err != nil {
+				return errors.Wrap(err, "creating REST client")
+			}
+
+			followOpts := rest.FollowLogOptions{
+				ID:            c.String(idFlag),
+				PrintTime:     c.Bool(printTimeFlag),
+				PrintPriority: c.Bool(printPriorityFlag),
+				Filter: model.LogFilterOptions{
+					MinPriority: level.Priority(c.Int(minPriorityFlag)),
+					MaxPriority: level.Priority(c.Int(maxPriorityFlag)),
+					Include:     c.String(includeFlag),
+					Exclude:     c.String(excludeFlag),
+					Fields:      c.StringSlice(fieldFlag),
+				},
+			}
+			for _, where := range c.StringSlice(whereFlag) {
+				parts := strings.SplitN(where, ":", 2)
+				if len(parts) != 2 || parts[0] == "" {
+					return errors.Errorf("invalid where condition '%s', must be of the form 'field:value'", where)
+				}
+				if followOpts.Filter.Where == nil {
+					followOpts.Filter.Where = map[string]string{}
+				}
+				followOpts.Filter.Where[parts[0]] = parts[1]
+			}
+			if start := c.String(startFlag); start != "" {
+				followOpts.StartAt, err = time.Parse(time.RFC3339, start)
+				if err != nil {
+					return errors.Wrap(err, "parsing start time")
+				}
+			}
+
+			return errors.Wrap(client.FollowLog(ctx, followOpts, os.Stdout), "following log")
+		},
+	}
+}
+
+func testResults() cli.Command {
+	return cli.Command{
+		Name:  "test-results",
+		Usage: "access test results",
+		Subcommands: []cli.Command{
+			testResultsUpload(),
+		},
+	}
+}
+
+func testResultsUpload() cli.Command {
+	const (
+		fileFlag                   = "file"
+		formatFlag                 = "format"
+		taskIDFlag                 = "task-id"
+		executionFlag              = "execution"
+		projectFlag                = "project"
+		versionFlag                = "version"
+		variantFlag                = "variant"
+		taskNameFlag               = "task-name"
+		displayTaskNameFlag        = "display-task-name"
+		displayTaskIDFlag          = "display-task-id"
+		requestTypeFlag            = "request-type"
+		mainlineFlag               = "mainline"
+		historicalDataDisabledFlag = "historical-data-disabled"
+		usernameFlag               = "username"
+		apiKeyFlag                 = "api-key"
+	)
+
+	return cli.Command{
+		Name:  "upload",
+		Usage: "uploads a JUnit XML, TAP, or go test JSON report as the test results of a task",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  fileFlag,
+				Usage: "specify the path of the report, may also be the first positional argument",
+			},
+			cli.StringFlag{
+				Name:  formatFlag,
+				Usage: "specify the format of the report, one of 'junit', 'tap', or 'gotest'",
+				Value: string(model.TestResultsFormatJUnit),
+			},
+			cli.StringFlag{
+				Name:  taskIDFlag,
+				Usage: "specify the ID of the task",
+			},
+			cli.IntFlag{
+				Name:  executionFlag,
+				Usage: "specify the execution of the task",
+			},
+			cli.StringFlag{
+				Name:  projectFlag,
+				Usage: "specify the project of the task",
+			},
+			cli.StringFlag{
+				Name:  versionFlag,
+				Usage: "specify the version of the task",
+			},
+			cli.StringFlag{
+				Name:  variantFlag,
+				Usage: "specify the build variant of the task",
+			},
+			cli.StringFlag{
+				Name:  taskNameFlag,
+				Usage: "specify the name of the task",
+			},
+			cli.StringFlag{
+				Name:  displayTaskNameFlag,
+				Usage: "specify the name of the task's display task, if any",
+			},
+			cli.StringFlag{
+				Name:  displayTaskIDFlag,
+				Usage: "specify the ID of the task's display task, if any",
+			},
+			cli.StringFlag{
+				Name:  requestTypeFlag,
+				Usage: "specify the request type of the task, e.g. 'gitter_request' or 'patch_request'",
+			},
+			cli.BoolFlag{
+				Name:  mainlineFlag,
+				Usage: "mark the task as a mainline task",
+			},
+			cli.BoolFlag{
+				Name:  historicalDataDisabledFlag,
+				Usage: "do not record the results in the historical test data",
+			},
+			cli.StringFlag{
+				Name:  usernameFlag,
+				Usage: "specify the username used to authenticate with the service",
+			},
+			cli.StringFlag{
+				Name:  apiKeyFlag,
+				Usage: "specify the API key used to authenticate with the service",
+			},
+		},
+		Before: mergeBeforeFuncs(
+			setFlagOrFirstPositional(fileFlag),
+			requireStringFlag(fileFlag),
+			requireFileExists(fileFlag),
+			requireStringFlag(taskIDFlag),
+			requireStringFlag(projectFlag),
+			requireStringFlag(versionFlag),
+			requireStringFlag(variantFlag),
+			requireStringFlag(taskNameFlag),
+			requireStringFlag(requestTypeFlag),
+		),
+		Action: func(c *cli.Context) error {
+			ctx := context.Background()
+
+			format := model.TestResultsFormat(c.String(formatFlag))
+			if err := format.Validate(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			opts := rest.ClientOptions{
+				Host:     c.Parent().Parent().String(clientHostFlag),
+				Port:     c.Parent().Parent().Int(clientPortFlag),
+				Prefix:   "/rest",
+				Username: c.String(usernameFlag),
+				ApiKey:   c.String(apiKeyFlag),
+			}
+			client, err := rest.NewClient(opts)
+			if err != nil {
+				return errors.Wrap(err, "creating REST client")
+			}
+
+			file, err := os.Open(c.String(fileFlag))
+			if err != nil {
+				return errors.Wrap(err, "opening report")
+			}
+			defer file.Close()
+
+			resp, err := client.UploadTestResults(ctx, rest.UploadTestResultsOptions{
+				Info: model.TestResultsInfo{
+					Project:                c.String(projectFlag),
+					Version:                c.String(versionFlag),
+					Variant:                c.String(variantFlag),
+					TaskName:               c.String(taskNameFlag),
+					DisplayTaskName:        c.String(displayTaskNameFlag),
+					TaskID:                 c.String(taskIDFlag),
+					DisplayTaskID:          c.String(displayTaskIDFlag),
+					Execution:              c.Int(executionFlag),
+					RequestType:            c.String(requestTypeFlag),
+					Mainline:               c.Bool(mainlineFlag),
+					HistoricalDataDisabled: c.Bool(historicalDataDisabledFlag),
+				},
+				Format: format,
+				Report: file,
+			})
+			if err != nil {
+				return errors.Wrap(err, "uploading test results")
+			}
+
+			out, err := prettyJSON(resp)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Println(out)
+
+			return nil
+		},
+	}
+}
+
+func perf() cli.Command {
+	return cli.Command{
+		Name:  "perf",
+		Usage: "access performance data",
+		Subcommands: []cli.Command{
+			perfChangePoints(),
+			perfCompare(),
+		},
+	}
+}
+
+func perfCompare() cli.Command {
+	const (
+		baseVersionFlag       = "base-version"
+		versionFlag           = "version"
+		baseTaskIDFlag        = "base-task-id"
+		taskIDFlag            = "task-id"
+		significanceLevelFlag = "significance-level"
+		trialsFlag            = "trials"
+		jsonFlag              = "json"
+	)
+
+	return cli.Command{
+		Name:  "compare",
+		Usage: "compares the performance rollups of two versions or two tasks, such as a patch and its base",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  baseVersionFlag,
+				Usage: "specify the base version to compare against",
+			},
+			cli.StringFlag{
+				Name:  versionFlag,
+				Usage: "specify the version to compare",
+			},
+			cli.StringFlag{
+				Name:  baseTaskIDFlag,
+				Usage: "specify the base task ID to compare against",
+			},
+			cli.StringFlag{
+				Name:  taskIDFlag,
+				Usage: "specify the task ID to compare",
+			},
+			cli.Float64Flag{
+				Name:  significanceLevelFlag,
+				Usage: "specify the p-value below which a difference between trials is significant",
+				Value: 0.05,
+			},
+			cli.BoolFlag{
+				Name:  trialsFlag,
+				Usage: "also print the comparison of each trial",
+			},
+			cli.BoolFlag{
+				Name:  jsonFlag,
+				Usage: "print the comparison as JSON instead of a table",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx := context.Background()
+
+			compareOpts := model.PerformanceComparisonOptions{
+				BaseVersion:       c.String(baseVersionFlag),
+				Version:           c.String(versionFlag),
+				BaseTaskID:        c.String(baseTaskIDFlag),
+				TaskID:            c.String(taskIDFlag),
+				SignificanceLevel: c.Float64(significanceLevelFlag),
+			}
+			if err := compareOpts.Validate(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			opts := rest.ClientOptions{
+				Host:   c.Parent().Parent().String(clientHostFlag),
+				Port:   c.Parent().Parent().Int(clientPortFlag),
+				Prefix: "/rest",
+			}
+			client, err := rest.NewClient(opts)
+			if err != nil {
+				return errors.Wrap(err, "creating REST client")
+			}
+
+			comparison, err := client.ComparePerformanceResults(ctx, compareOpts)
+			if err != nil {
+				return errors.Wrap(err, "comparing performance results")
+			}
+
+			if c.Bool(jsonFlag) {
+				out, err := prettyJSON(comparison)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				fmt.Println(out)
+
+				return nil
+			}
+
+			return errors.Wrap(printPerfComparison(os.Stdout, comparison, c.Bool(trialsFlag)), "printing comparison")
+		},
+	}
+}
+
+// printPerfComparison writes the comparison as a table with a row for each
+// rollup of each test, and of each trial if requested. Significant
+// differences are marked with an asterisk.
+func printPerfComparison(w io.Writer, comparison *restModel.APIPerformanceComparison, trials bool) error {
+	formatValue := func(v *float64) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.6g", *v)
+	}
+	formatPercent := func(v *float64) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%+.2f%%", *v)
+	}
+	formatArgs := func(args map[string]int32) string {
+		parts := make([]string, 0, len(args))
+		for name, value := range args {
+			parts = append(parts, fmt.Sprintf("%s=%d", name, value))
+		}
+		sort.Strings(parts)
+		if len(parts) == 0 {
+			return "-"
+		}
+		return strings.Join(parts, ",")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VARIANT\tTASK\tTEST\tARGS\tTRIAL\tROLLUP\tBASE\tVALUE\tDELTA\tDELTA %\tP-VALUE\t")
+	for _, test := range comparison.Tests {
+		printRollups := func(trial string, rollups []restModel.APIPerformanceRollupComparison) {
+			for _, rollup := range rollups {
+				significant := ""
+				if rollup.Significant {
+					significant = "*"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					utility.FromStringPtr(test.Variant),
+					utility.FromStringPtr(test.TaskName),
+					utility.FromStringPtr(test.TestName),
+					formatArgs(test.Arguments),
+					trial,
+					utility.FromStringPtr(rollup.Name),
+					formatValue(rollup.BaseValue),
+					formatValue(rollup.Value),
+					formatValue(rollup.Delta),
+					formatPercent(rollup.PercentDelta),
+					formatValue(rollup.PValue),
+					significant,
+				)
+			}
+		}
+
+		printRollups("all", test.Rollups)
+		if trials {
+			for _, trial := range test.Trials {
+				printRollups(fmt.Sprint(trial.Trial), trial.Rollups)
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+func perfChangePoints() cli.Command {
+	return cli.Command{
+		Name:  "change-points",
+		Usage: "list and triage performance change points",
+		Subcommands: []cli.Command{
+			perfChangePointsList(),
+			perfChangePointsTriage(),
+		},
+	}
+}
+
+func perfChangePointsList() cli.Command {
+	const (
+		projectFlag     = "project"
+		variantFlag     = "variant"
+		taskFlag        = "task"
+		testFlag        = "test"
+		measurementFlag = "measurement"
+		statusFlag      = "status"
+		limitFlag       = "limit"
+	)
+
+	return cli.Command{
+		Name:  "list",
+		Usage: "lists the change points of a project, most recently detected first",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  projectFlag,
+				Usage: "specify the project of the change points, may also be the first positional argument",
+			},
+			cli.StringFlag{
+				Name:  variantFlag,
+				Usage: "specify the build variant of the change points",
+			},
+			cli.StringFlag{
+				Name:  taskFlag,
+				Usage: "specify the task name of the change points",
+			},
+			cli.StringFlag{
+				Name:  testFlag,
+				Usage: "specify the test name of the change points",
+			},
+			cli.StringFlag{
+				Name:  measurementFlag,
+				Usage: "specify the measurement of the change points",
+			},
+			cli.StringSliceFlag{
+				Name:  statusFlag,
+				Usage: "specify a triage status of the change points, one of 'untriaged', 'acknowledged', 'hidden', or 'linked' (may be repeated)",
+			},
+			cli.IntFlag{
+				Name:  limitFlag,
+				Usage: "specify the maximum number of change points",
+				Value: 100,
+			},
+		},
+		Before: mergeBeforeFuncs(
+			setFlagOrFirstPositional(projectFlag),
+			requireStringFlag(projectFlag),
+		),
+		Action: func(c *cli.Context) error {
+			ctx := context.Background()
+
+			filter := model.PerformanceChangePointsFilter{
+				Project:     c.String(projectFlag),
+				Variant:     c.String(variantFlag),
+				Task:        c.String(taskFlag),
+				Test:        c.String(testFlag),
+				Measurement: c.String(measurementFlag),
+				Limit:       c.Int(limitFlag),
+			}
+			for _, status := range c.StringSlice(statusFlag) {
+				filter.Statuses = append(filter.Statuses, model.PerformanceChangePointTriageStatus(status))
+			}
+			if err := filter.Validate(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			opts := rest.ClientOptions{
+				Host:   c.Parent().Parent().Parent().String(clientHostFlag),
+				Port:   c.Parent().Parent().Parent().Int(clientPortFlag),
+				Prefix: "/rest",
+			}
+			client, err := rest.NewClient(opts)
+			if err != nil {
+				return errors.Wrap(err, "creating REST client")
+			}
+
+			changePoints, err := client.GetPerformanceChangePoints(ctx, filter)
+			if err != nil {
+				return errors.Wrap(err, "getting change points")
+			}
+
+			out, err := prettyJSON(changePoints)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Println(out)
+
+			return nil
+		},
+	}
+}
+
+func perfChangePointsTriage() cli.Command {
+	const (
+		idFlag       = "id"
+		projectFlag  = "project"
+		statusFlag   = "status"
+		notesFlag    = "notes"
+		ticketFlag   = "ticket"
+		usernameFlag = "username"
+		apiKeyFlag   = "api-key"
+	)
+
+	return cli.Command{
+		Name:  "triage",
+		Usage: "acknowledges a change point, hides it as a false positive, or links it to tickets",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  idFlag,
+				Usage: "specify the ID of the change point, may also be the first positional argument",
+			},
+			cli.StringFlag{
+				Name:  projectFlag,
+				Usage: "specify the project of the change point",
+			},
+			cli.StringFlag{
+				Name:  statusFlag,
+				Usage: "specify the triage status, one of 'untriaged', 'acknowledged', 'hidden', or 'linked'",
+			},
+			cli.StringFlag{
+				Name:  notesFlag,
+				Usage: "specify notes about the change point",
+			},
+			cli.StringSliceFlag{
+				Name:  ticketFlag,
+				Usage: "specify a ticket tracking the change point (may be repeated)",
+			},
+			cli.StringFlag{
+				Name:  usernameFlag,
+				Usage: "specify the username used to authenticate with the service",
+			},
+			cli.StringFlag{
+				Name:  apiKeyFlag,
+				Usage: "specify the API key used to authenticate with the service",
+			},
+		},
+		Before: mergeBeforeFuncs(
+			setFlagOrFirstPositional(idFlag),
+			requireStringFlag(idFlag),
+			requireStringFlag(projectFlag),
+			requireStringFlag(statusFlag),
+		),
+		Action: func(c *cli.Context) error {
+			ctx := context.Background()
+
+			triage := model.PerformanceChangePointTriage{
+				Status:  model.PerformanceChangePointTriageStatus(c.String(statusFlag)),
+				Notes:   c.String(notesFlag),
+				Tickets: c.StringSlice(ticketFlag),
+			}
+			if err := triage.Validate(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			opts := rest.ClientOptions{
+				Host:     c.Parent().Parent().Parent().String(clientHostFlag),
+				Port:     c.Parent().Parent().Parent().Int(clientPortFlag),
+				Prefix:   "/rest",
+				Username: c.String(usernameFlag),
+				ApiKey:   c.String(apiKeyFlag),
+			}
+			client, err := rest.NewClient(opts)
+			if err != nil {
+				return errors.Wrap(err, "creating REST client")
+			}
+
+			changePoint, err := client.TriagePerformanceChangePoint(ctx, c.String(projectFlag), c.String(idFlag), restModel.APIPerformanceChangePointTriage{
+				Status:  utility.ToStringPtr(string(triage.Status)),
+				Notes:   utility.ToStringPtr(triage.Notes),
+				Tickets: triage.Tickets,
+			})
+			if err != nil {
+				return errors.Wrap(err, "triaging change point")
+			}
+
+			out, err := prettyJSON(changePoint)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Println(out)
+
+			return nil
+		},
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	cedarperf "github.com/evergreen-ci/cedar/perf"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -71,6 +72,9 @@ func loadCedarConfig() cli.Command {
 			if err != nil {
 				return errors.WithStack(err)
 			}
+			if err = cedarperf.ValidateCustomRollups(conf.Rollups.Custom); err != nil {
+				return errors.Wrap(err, "invalid rollup configuration")
+			}
 
 			sc := newServiceConf(2, true, mongodbURI, "", dbName, dbCredFile)
 			sc.interactive = true
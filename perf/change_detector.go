@@ -0,0 +1,164 @@
+package perf
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// EDivisiveMeansAlgorithm is the name of the algorithm used by the local
+// PerformanceAnalysisService to detect change points.
+const EDivisiveMeansAlgorithm = "e_divisive_means"
+
+type localChangeDetector struct {
+	env  cedar.Environment
+	opts EDivisiveMeansOptions
+}
+
+// NewLocalPerformanceAnalysisService creates a new PerformanceAnalysisService
+// that detects the change points of the updated time series in process,
+// using the E-Divisive means algorithm, and stores them in the DB.
+func NewLocalPerformanceAnalysisService(env cedar.Environment, opts EDivisiveMeansOptions) PerformanceAnalysisService {
+	return &localChangeDetector{env: env, opts: opts}
+}
+
+// ReportUpdatedTimeSeries detects the change points of the mainline time
+// series, ordered by the performance results' order, and replaces the
+// previously detected change points of the series. Only the latest points,
+// up to the maximum series length, are queried and the values of the results
+// with the same order are averaged.
+func (d *localChangeDetector) ReportUpdatedTimeSeries(ctx context.Context, series TimeSeriesModel) error {
+	startAt := time.Now()
+
+	id, err := createPerformanceResultSeriesID(series)
+	if err != nil {
+		return errors.Wrap(err, "converting time series")
+	}
+	opts := d.opts
+	opts.setDefaults()
+	points, err := model.GetPerformanceTimeSeries(ctx, d.env, model.PerformanceTimeSeriesOptions{
+		ID:    id,
+		Limit: opts.MaxSeriesLength,
+	})
+	if err != nil {
+		return errors.Wrap(err, "getting time series")
+	}
+	if len(points) == opts.MaxSeriesLength {
+		points = trimOldestOrder(points)
+	}
+
+	var (
+		orders   []int
+		versions []string
+		values   []float64
+		counts   []int
+	)
+	for _, point := range points {
+		last := len(orders) - 1
+		if last >= 0 && orders[last] == point.Order {
+			values[last] += point.Value
+			counts[last]++
+			continue
+		}
+		orders = append(orders, point.Order)
+		versions = append(versions, point.Version)
+		values = append(values, point.Value)
+		counts = append(counts, 1)
+	}
+	for i := range values {
+		values[i] /= float64(counts[i])
+	}
+
+	var changePoints []model.PerformanceChangePoint
+	for _, cp := range EDivisiveMeans(values, opts) {
+		changePoints = append(changePoints, model.PerformanceChangePoint{
+			Order:      orders[cp.Index],
+			Version:    versions[cp.Index],
+			Algorithm:  EDivisiveMeansAlgorithm,
+			Magnitude:  cp.Magnitude,
+			Confidence: cp.Confidence,
+		})
+	}
+	if err = model.ReplacePerformanceChangePoints(ctx, d.env, id, changePoints); err != nil {
+		return errors.Wrap(err, "saving change points")
+	}
+
+	grip.Debug(message.Fields{
+		"message":       "detected change points of updated time series",
+		"update":        series,
+		"num_points":    len(values),
+		"change_points": len(changePoints),
+		"duration_secs": time.Since(startAt).Seconds(),
+	})
+
+	return nil
+}
+
+// trimOldestOrder removes the points with the oldest order from a series
+// truncated by the query limit, since the limit may have cut off some of that
+// order's values. A series with a single order is returned unchanged.
+func trimOldestOrder(points []model.PerformanceTimeSeriesPoint) []model.PerformanceTimeSeriesPoint {
+	for i := range points {
+		if points[i].Order != points[0].Order {
+			return points[i:]
+		}
+	}
+
+	return points
+}
+
+func createPerformanceResultSeriesID(series TimeSeriesModel) (model.PerformanceResultSeriesID, error) {
+	id := model.PerformanceResultSeriesID{
+		Project:     series.Project,
+		Variant:     series.Variant,
+		Task:        series.Task,
+		Test:        series.Test,
+		Measurement: series.Measurement,
+	}
+	if len(series.Arguments) == 0 {
+		return id, nil
+	}
+
+	id.Arguments = model.PerformanceArguments{}
+	for _, arg := range series.Arguments {
+		value, err := performanceArgumentValue(arg.Value)
+		if err != nil {
+			return id, errors.Wrapf(err, "converting argument '%s'", arg.Name)
+		}
+		id.Arguments[arg.Name] = value
+	}
+
+	return id, nil
+}
+
+// performanceArgumentValue converts a time series argument value to the
+// int32 stored in the performance results' arguments. Values that are not
+// exactly representable as an int32 cannot identify a stored series and are
+// rejected instead of being truncated, which would alias distinct series.
+func performanceArgumentValue(value interface{}) (int32, error) {
+	var v float64
+	switch typed := value.(type) {
+	case int32:
+		return typed, nil
+	case int:
+		v = float64(typed)
+	case int64:
+		v = float64(typed)
+	case float64:
+		v = typed
+	default:
+		return 0, errors.Errorf("unsupported type %T", value)
+	}
+
+	if v != math.Trunc(v) || v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, errors.Errorf("value %v is not a 32-bit integer", value)
+	}
+
+	return int32(v), nil
+}
@@ -0,0 +1,77 @@
+package perf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePerformanceResultSeriesID(t *testing.T) {
+	series := TimeSeriesModel{
+		Project:     "project",
+		Variant:     "variant",
+		Task:        "task",
+		Test:        "test",
+		Measurement: "ops_per_sec",
+	}
+
+	t.Run("NoArguments", func(t *testing.T) {
+		id, err := createPerformanceResultSeriesID(series)
+		require.NoError(t, err)
+		assert.Equal(t, model.PerformanceResultSeriesID{
+			Project:     "project",
+			Variant:     "variant",
+			Task:        "task",
+			Test:        "test",
+			Measurement: "ops_per_sec",
+		}, id)
+		assert.Nil(t, id.Arguments)
+	})
+	t.Run("Arguments", func(t *testing.T) {
+		withArgs := series
+		withArgs.Arguments = []ArgumentsModel{
+			{Name: "thread_level", Value: int32(20)},
+			{Name: "batch_size", Value: float64(8)},
+		}
+		id, err := createPerformanceResultSeriesID(withArgs)
+		require.NoError(t, err)
+		assert.Equal(t, model.PerformanceArguments{"thread_level": 20, "batch_size": 8}, id.Arguments)
+	})
+	t.Run("InvalidArgument", func(t *testing.T) {
+		withArgs := series
+		withArgs.Arguments = []ArgumentsModel{{Name: "thread_level", Value: "20"}}
+		_, err := createPerformanceResultSeriesID(withArgs)
+		assert.Error(t, err)
+	})
+	t.Run("NonIntegerArguments", func(t *testing.T) {
+		for _, value := range []interface{}{8.5, float64(math.MaxInt32) + 1, int64(math.MinInt32) - 1, math.NaN(), math.Inf(1)} {
+			withArgs := series
+			withArgs.Arguments = []ArgumentsModel{{Name: "batch_size", Value: value}}
+			_, err := createPerformanceResultSeriesID(withArgs)
+			assert.Error(t, err, "%v", value)
+		}
+	})
+}
+
+func TestTrimOldestOrder(t *testing.T) {
+	t.Run("MultipleOrders", func(t *testing.T) {
+		points := []model.PerformanceTimeSeriesPoint{
+			{Order: 1, Value: 1},
+			{Order: 1, Value: 2},
+			{Order: 2, Value: 3},
+			{Order: 3, Value: 4},
+			{Order: 3, Value: 5},
+		}
+		assert.Equal(t, points[2:], trimOldestOrder(points))
+	})
+	t.Run("SingleOrder", func(t *testing.T) {
+		points := []model.PerformanceTimeSeriesPoint{{Order: 1, Value: 1}, {Order: 1, Value: 2}}
+		assert.Equal(t, points, trimOldestOrder(points))
+	})
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, trimOldestOrder(nil))
+	})
+}
@@ -0,0 +1,212 @@
+package perf
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// EDivisiveMeansOptions configure the E-Divisive means change point
+// detection algorithm.
+type EDivisiveMeansOptions struct {
+	// PValue is the significance level a change point must reach to be
+	// detected.
+	PValue float64
+	// Permutations is the number of random permutations of a segment of
+	// the series used to estimate the significance of a change point.
+	Permutations int
+	// MinSegmentSize is the minimum number of values on each side of a
+	// change point.
+	MinSegmentSize int
+	// Seed seeds the random permutations, making the detection
+	// deterministic.
+	Seed int64
+	// MaxSeriesLength is the maximum number of the most recent values of
+	// a series that are analyzed. Each split costs O(n^2) per
+	// permutation, so older values are ignored for long series.
+	MaxSeriesLength int
+}
+
+const (
+	defaultEDivisivePValue          = 0.05
+	defaultEDivisivePermutations    = 100
+	defaultEDivisiveMinSegmentSize  = 3
+	defaultEDivisiveMaxSeriesLength = 500
+)
+
+func (opts *EDivisiveMeansOptions) setDefaults() {
+	if opts.PValue <= 0 {
+		opts.PValue = defaultEDivisivePValue
+	}
+	if opts.Permutations <= 0 {
+		opts.Permutations = defaultEDivisivePermutations
+	}
+	if opts.MinSegmentSize < 2 {
+		opts.MinSegmentSize = defaultEDivisiveMinSegmentSize
+	}
+	if opts.MaxSeriesLength <= 0 {
+		opts.MaxSeriesLength = defaultEDivisiveMaxSeriesLength
+	}
+}
+
+// ChangePoint is a change in the distribution of a series of values.
+type ChangePoint struct {
+	// Index is the index of the first value after the change.
+	Index int
+	// Magnitude is the difference between the mean of the values after
+	// and before the change, up to the neighboring change points.
+	Magnitude float64
+	// Confidence is the estimated probability that the change is not due
+	// to noise.
+	Confidence float64
+}
+
+// EDivisiveMeans detects the change points in the series using the
+// hierarchical E-Divisive means algorithm. Segments of the series are split
+// at the point maximizing the energy distance between the values on either
+// side, for as long as the split is significant according to a permutation
+// test. Only the most recent values of long series are analyzed. The change
+// points are returned sorted by index into the full series.
+func EDivisiveMeans(series []float64, opts EDivisiveMeansOptions) []ChangePoint {
+	opts.setDefaults()
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	var offset int
+	if len(series) > opts.MaxSeriesLength {
+		offset = len(series) - opts.MaxSeriesLength
+		series = series[offset:]
+	}
+
+	type segment struct {
+		start, end int
+		split      int
+		q          float64
+	}
+	bestSplit := func(start, end int) segment {
+		split, q := eDivisiveBestSplit(series[start:end], opts.MinSegmentSize)
+		return segment{start: start, end: end, split: start + split, q: q}
+	}
+
+	var changePoints []ChangePoint
+	segments := []segment{bestSplit(0, len(series))}
+	for {
+		best := -1
+		for i, seg := range segments {
+			if seg.split > seg.start && (best < 0 || seg.q > segments[best].q) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		seg := segments[best]
+		pValue := eDivisivePValue(series[seg.start:seg.end], seg.q, opts, rng)
+		if pValue > opts.PValue {
+			break
+		}
+
+		changePoints = append(changePoints, ChangePoint{Index: seg.split, Confidence: 1 - pValue})
+		segments[best] = bestSplit(seg.start, seg.split)
+		segments = append(segments, bestSplit(seg.split, seg.end))
+	}
+
+	sort.Slice(changePoints, func(i, j int) bool { return changePoints[i].Index < changePoints[j].Index })
+	for i := range changePoints {
+		start, end := 0, len(series)
+		if i > 0 {
+			start = changePoints[i-1].Index
+		}
+		if i < len(changePoints)-1 {
+			end = changePoints[i+1].Index
+		}
+		changePoints[i].Magnitude = mean(series[changePoints[i].Index:end]) - mean(series[start:changePoints[i].Index])
+	}
+	for i := range changePoints {
+		changePoints[i].Index += offset
+	}
+
+	return changePoints
+}
+
+// eDivisiveBestSplit returns the index splitting the values with the
+// largest energy statistic, along with the statistic. The index is 0 if the
+// values cannot be split into two segments of the minimum size.
+func eDivisiveBestSplit(values []float64, minSize int) (int, float64) {
+	n := len(values)
+	if n < 2*minSize {
+		return 0, 0
+	}
+
+	// rowLeft[i] and rowRight[i] are the sums of the distances between the
+	// ith value and the values before and after it, respectively.
+	rowLeft := make([]float64, n)
+	rowRight := make([]float64, n)
+	var total float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := math.Abs(values[i] - values[j])
+			rowRight[i] += d
+			rowLeft[j] += d
+			total += d
+		}
+	}
+
+	// Sweep the split from left to right, moving one value at a time from
+	// the right segment to the left segment and updating the sums of the
+	// distances within and between the segments.
+	var (
+		withinLeft  float64
+		withinRight = total
+		between     float64
+		bestSplit   int
+		bestQ       = math.Inf(-1)
+	)
+	for split := 1; split <= n-minSize; split++ {
+		p := split - 1
+		withinLeft += rowLeft[p]
+		withinRight -= rowRight[p]
+		between += rowRight[p] - rowLeft[p]
+		if split < minSize {
+			continue
+		}
+
+		left, right := float64(split), float64(n-split)
+		q := left * right / (left + right) * (2*between/(left*right) -
+			2*withinLeft/(left*(left-1)) -
+			2*withinRight/(right*(right-1)))
+		if q > bestQ {
+			bestSplit, bestQ = split, q
+		}
+	}
+
+	return bestSplit, bestQ
+}
+
+// eDivisivePValue estimates the probability of finding a split at least as
+// significant as q in a random permutation of the values.
+func eDivisivePValue(values []float64, q float64, opts EDivisiveMeansOptions, rng *rand.Rand) float64 {
+	permuted := append([]float64{}, values...)
+	var exceeded int
+	for i := 0; i < opts.Permutations; i++ {
+		rng.Shuffle(len(permuted), func(i, j int) { permuted[i], permuted[j] = permuted[j], permuted[i] })
+		if _, permutedQ := eDivisiveBestSplit(permuted, opts.MinSegmentSize); permutedQ >= q {
+			exceeded++
+		}
+	}
+
+	return float64(exceeded+1) / float64(opts.Permutations+1)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
@@ -0,0 +1,122 @@
+package perf
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEDivisiveMeans(t *testing.T) {
+	noisy := func(rng *rand.Rand, n int, mean float64) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = mean + rng.Float64()
+		}
+		return values
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, EDivisiveMeans(nil, EDivisiveMeansOptions{}))
+	})
+	t.Run("TooShort", func(t *testing.T) {
+		assert.Empty(t, EDivisiveMeans([]float64{1, 1, 10, 10}, EDivisiveMeansOptions{}))
+	})
+	t.Run("Constant", func(t *testing.T) {
+		series := make([]float64, 50)
+		for i := range series {
+			series[i] = 10
+		}
+		assert.Empty(t, EDivisiveMeans(series, EDivisiveMeansOptions{}))
+	})
+	t.Run("Noise", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		assert.Empty(t, EDivisiveMeans(noisy(rng, 50, 100), EDivisiveMeansOptions{}))
+	})
+	t.Run("SingleChange", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		series := append(noisy(rng, 30, 100), noisy(rng, 20, 150)...)
+		changePoints := EDivisiveMeans(series, EDivisiveMeansOptions{})
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, 30, changePoints[0].Index)
+		assert.InDelta(t, 50, changePoints[0].Magnitude, 1)
+		assert.True(t, changePoints[0].Confidence >= 0.95)
+	})
+	t.Run("MultipleChanges", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		series := append(noisy(rng, 20, 100), noisy(rng, 20, 50)...)
+		series = append(series, noisy(rng, 20, 80)...)
+		changePoints := EDivisiveMeans(series, EDivisiveMeansOptions{})
+		require.Len(t, changePoints, 2)
+		assert.Equal(t, 20, changePoints[0].Index)
+		assert.InDelta(t, -50, changePoints[0].Magnitude, 1)
+		assert.Equal(t, 40, changePoints[1].Index)
+		assert.InDelta(t, 30, changePoints[1].Magnitude, 1)
+	})
+	t.Run("Deterministic", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(2))
+		series := append(noisy(rng, 10, 10), noisy(rng, 10, 10.5)...)
+		opts := EDivisiveMeansOptions{Seed: 5}
+		assert.Equal(t, EDivisiveMeans(series, opts), EDivisiveMeans(series, opts))
+	})
+	t.Run("MinSegmentSize", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		series := append(noisy(rng, 30, 100), noisy(rng, 4, 200)...)
+		for _, cp := range EDivisiveMeans(series, EDivisiveMeansOptions{MinSegmentSize: 5}) {
+			assert.True(t, cp.Index <= len(series)-5)
+		}
+		changePoints := EDivisiveMeans(series, EDivisiveMeansOptions{})
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, 30, changePoints[0].Index)
+	})
+	t.Run("MaxSeriesLength", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		series := append(noisy(rng, 30, 200), noisy(rng, 30, 100)...)
+		series = append(series, noisy(rng, 20, 150)...)
+		changePoints := EDivisiveMeans(series, EDivisiveMeansOptions{MaxSeriesLength: 50})
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, 60, changePoints[0].Index)
+		assert.InDelta(t, 50, changePoints[0].Magnitude, 1)
+	})
+}
+
+func TestEDivisiveBestSplit(t *testing.T) {
+	values := []float64{1, 2, 1, 2, 9, 8, 9}
+	split, q := eDivisiveBestSplit(values, 2)
+	assert.Equal(t, 4, split)
+
+	// Compare against the direct definition of the energy statistic.
+	direct := func(values []float64, split int) float64 {
+		left, right := values[:split], values[split:]
+		var between, withinLeft, withinRight float64
+		for _, x := range left {
+			for _, y := range right {
+				between += abs(x - y)
+			}
+		}
+		for i := range left {
+			for j := i + 1; j < len(left); j++ {
+				withinLeft += abs(left[i] - left[j])
+			}
+		}
+		for i := range right {
+			for j := i + 1; j < len(right); j++ {
+				withinRight += abs(right[i] - right[j])
+			}
+		}
+		m, n := float64(len(left)), float64(len(right))
+		return m * n / (m + n) * (2*between/(m*n) - 2*withinLeft/(m*(m-1)) - 2*withinRight/(n*(n-1)))
+	}
+	assert.InDelta(t, direct(values, 4), q, 1e-9)
+	for s := 2; s <= len(values)-2; s++ {
+		assert.True(t, direct(values, s) <= q+1e-9)
+	}
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
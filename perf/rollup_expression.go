@@ -0,0 +1,386 @@
+package perf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aclements/go-moremath/stats"
+	"github.com/pkg/errors"
+)
+
+// rollupExpression is a parsed custom rollup expression that evaluates to a
+// single value given the statistics of a performance result.
+type rollupExpression interface {
+	eval(*PerformanceStatistics) float64
+}
+
+type scalarGetter func(*PerformanceStatistics) float64
+type seriesGetter func(*PerformanceStatistics) []float64
+
+// rollupExpressionScalars are the counters and timers available to custom
+// rollup expressions. Timers are in nanoseconds.
+var rollupExpressionScalars = map[string]scalarGetter{
+	"operations": func(s *PerformanceStatistics) float64 { return float64(s.counters.operationsTotal) },
+	"documents":  func(s *PerformanceStatistics) float64 { return float64(s.counters.documentsTotal) },
+	"size":       func(s *PerformanceStatistics) float64 { return float64(s.counters.sizeTotal) },
+	"errors":     func(s *PerformanceStatistics) float64 { return float64(s.counters.errorsTotal) },
+	"duration":   func(s *PerformanceStatistics) float64 { return float64(s.timers.durationTotal) },
+	"total":      func(s *PerformanceStatistics) float64 { return float64(s.timers.total) },
+	"wall_time":  func(s *PerformanceStatistics) float64 { return float64(s.timers.totalWallTime) },
+}
+
+// rollupExpressionSeries are the per-sample series available to the
+// functions of custom rollup expressions. Latencies are in nanoseconds.
+var rollupExpressionSeries = map[string]seriesGetter{
+	"latency": func(s *PerformanceStatistics) []float64 { return s.timers.extractedDurations },
+	"state":   func(s *PerformanceStatistics) []float64 { return s.gauges.state },
+	"workers": func(s *PerformanceStatistics) []float64 { return s.gauges.workers },
+	"failed":  func(s *PerformanceStatistics) []float64 { return s.gauges.failed },
+}
+
+type rollupExpressionFunction struct {
+	args int
+	fn   func(xs []float64, args []float64) float64
+}
+
+// rollupExpressionFunctions are the functions available to custom rollup
+// expressions. The first argument of every function is a series, followed by
+// the given number of scalar arguments.
+var rollupExpressionFunctions = map[string]rollupExpressionFunction{
+	"count": {fn: func(xs, _ []float64) float64 { return float64(len(xs)) }},
+	"sum": {fn: func(xs, _ []float64) float64 {
+		var sum float64
+		for _, x := range xs {
+			sum += x
+		}
+		return sum
+	}},
+	"mean": {fn: func(xs, _ []float64) float64 {
+		if len(xs) == 0 {
+			return math.NaN()
+		}
+		return stats.Mean(xs)
+	}},
+	"stddev": {fn: func(xs, _ []float64) float64 {
+		if len(xs) < 2 {
+			return math.NaN()
+		}
+		return stats.StdDev(xs)
+	}},
+	"min": {fn: func(xs, _ []float64) float64 {
+		if len(xs) == 0 {
+			return math.NaN()
+		}
+		min, _ := stats.Sample{Xs: xs}.Bounds()
+		return min
+	}},
+	"max": {fn: func(xs, _ []float64) float64 {
+		if len(xs) == 0 {
+			return math.NaN()
+		}
+		_, max := stats.Sample{Xs: xs}.Bounds()
+		return max
+	}},
+	// percentile(series, p) returns the pth percentile of the series,
+	// where p is between 0 and 100.
+	"percentile": {args: 1, fn: func(xs, args []float64) float64 {
+		if len(xs) == 0 || args[0] < 0 || args[0] > 100 {
+			return math.NaN()
+		}
+		sorted := make(sort.Float64Slice, len(xs))
+		copy(sorted, xs)
+		sorted.Sort()
+		return stats.Sample{Xs: sorted, Sorted: true}.Quantile(args[0] / 100)
+	}},
+	// bucket(series, low, high) returns the number of values in the
+	// series in the histogram bucket [low, high).
+	"bucket": {args: 2, fn: func(xs, args []float64) float64 {
+		var count float64
+		for _, x := range xs {
+			if x >= args[0] && x < args[1] {
+				count++
+			}
+		}
+		return count
+	}},
+}
+
+type numberExpression float64
+
+func (e numberExpression) eval(_ *PerformanceStatistics) float64 { return float64(e) }
+
+type scalarExpression struct {
+	get scalarGetter
+}
+
+func (e scalarExpression) eval(s *PerformanceStatistics) float64 { return e.get(s) }
+
+type negateExpression struct {
+	operand rollupExpression
+}
+
+func (e negateExpression) eval(s *PerformanceStatistics) float64 { return -e.operand.eval(s) }
+
+type binaryExpression struct {
+	op          byte
+	left, right rollupExpression
+}
+
+func (e binaryExpression) eval(s *PerformanceStatistics) float64 {
+	left, right := e.left.eval(s), e.right.eval(s)
+	switch e.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	default:
+		return left / right
+	}
+}
+
+type functionExpression struct {
+	function rollupExpressionFunction
+	series   seriesGetter
+	args     []rollupExpression
+}
+
+func (e functionExpression) eval(s *PerformanceStatistics) float64 {
+	args := make([]float64, len(e.args))
+	for i, arg := range e.args {
+		args[i] = arg.eval(s)
+	}
+
+	return e.function.fn(e.series(s), args)
+}
+
+// parseRollupExpression parses an arithmetic expression (+, -, *, / and
+// parentheses) over numbers, the counters and timers of the performance
+// statistics, and functions of the statistics' series, for example
+// "percentile(latency, 99.9) / mean(latency)" or "errors / operations".
+func parseRollupExpression(expr string) (rollupExpression, error) {
+	tokens, err := tokenizeRollupExpression(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "tokenizing expression '%s'", expr)
+	}
+
+	p := &rollupExpressionParser{tokens: tokens}
+	parsed, err := p.parseSum()
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing expression '%s'", expr)
+	}
+	if tok := p.peek(); tok.kind != rollupTokenEOF {
+		return nil, errors.Errorf("parsing expression '%s': unexpected %s at position %d", expr, tok, tok.pos)
+	}
+
+	return parsed, nil
+}
+
+type rollupTokenKind int
+
+const (
+	rollupTokenEOF rollupTokenKind = iota
+	rollupTokenNumber
+	rollupTokenIdent
+	rollupTokenOperator
+)
+
+type rollupToken struct {
+	kind  rollupTokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+func (t rollupToken) String() string {
+	if t.kind == rollupTokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+func tokenizeRollupExpression(expr string) ([]rollupToken, error) {
+	tokens := []rollupToken{}
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/(),", r):
+			tokens = append(tokens, rollupToken{kind: rollupTokenOperator, text: string(r), pos: i})
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '+' || runes[i] == '-') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid number '%s' at position %d", text, start)
+			}
+			tokens = append(tokens, rollupToken{kind: rollupTokenNumber, text: text, value: value, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, rollupToken{kind: rollupTokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, errors.Errorf("unexpected character '%c' at position %d", r, i)
+		}
+	}
+
+	return append(tokens, rollupToken{kind: rollupTokenEOF, pos: len(runes)}), nil
+}
+
+type rollupExpressionParser struct {
+	tokens []rollupToken
+	pos    int
+}
+
+func (p *rollupExpressionParser) peek() rollupToken { return p.tokens[p.pos] }
+
+func (p *rollupExpressionParser) next() rollupToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != rollupTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *rollupExpressionParser) isOperator(ops ...string) bool {
+	tok := p.peek()
+	if tok.kind != rollupTokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *rollupExpressionParser) expect(op string) error {
+	if !p.isOperator(op) {
+		tok := p.peek()
+		return errors.Errorf("expected '%s' but found %s at position %d", op, tok, tok.pos)
+	}
+	p.next()
+	return nil
+}
+
+func (p *rollupExpressionParser) parseSum() (rollupExpression, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text[0]
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpression{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *rollupExpressionParser) parseProduct() (rollupExpression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/") {
+		op := p.next().text[0]
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpression{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *rollupExpressionParser) parseUnary() (rollupExpression, error) {
+	if p.isOperator("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateExpression{operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *rollupExpressionParser) parsePrimary() (rollupExpression, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == rollupTokenNumber:
+		return numberExpression(tok.value), nil
+	case tok.kind == rollupTokenOperator && tok.text == "(":
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	case tok.kind == rollupTokenIdent && p.isOperator("("):
+		return p.parseFunction(tok)
+	case tok.kind == rollupTokenIdent:
+		if get, ok := rollupExpressionScalars[tok.text]; ok {
+			return scalarExpression{get: get}, nil
+		}
+		if _, ok := rollupExpressionSeries[tok.text]; ok {
+			return nil, errors.Errorf("series '%s' at position %d must be aggregated by a function", tok.text, tok.pos)
+		}
+		return nil, errors.Errorf("unknown variable '%s' at position %d", tok.text, tok.pos)
+	default:
+		return nil, errors.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+}
+
+func (p *rollupExpressionParser) parseFunction(name rollupToken) (rollupExpression, error) {
+	function, ok := rollupExpressionFunctions[name.text]
+	if !ok {
+		return nil, errors.Errorf("unknown function '%s' at position %d", name.text, name.pos)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	tok := p.next()
+	series, ok := rollupExpressionSeries[tok.text]
+	if tok.kind != rollupTokenIdent || !ok {
+		return nil, errors.Errorf("function '%s' expects a series as its first argument but found %s at position %d", name.text, tok, tok.pos)
+	}
+
+	args := []rollupExpression{}
+	for p.isOperator(",") {
+		p.next()
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(args) != function.args {
+		return nil, errors.Errorf("function '%s' at position %d expects %d argument(s) after the series but found %d", name.text, name.pos, function.args, len(args))
+	}
+
+	return functionExpression{function: function, series: series, args: args}, nil
+}
@@ -0,0 +1,174 @@
+package perf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollupExpression(t *testing.T) {
+	s := &PerformanceStatistics{}
+	s.counters.operationsTotal = 100
+	s.counters.errorsTotal = 5
+	s.counters.sizeTotal = 2000
+	s.timers.durationTotal = 500 * time.Millisecond
+	s.timers.totalWallTime = 2 * time.Second
+	s.timers.extractedDurations = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	s.gauges.workers = []float64{2, 4, 4, 2}
+
+	for _, test := range []struct {
+		name     string
+		expr     string
+		expected float64
+	}{
+		{name: "Number", expr: "42", expected: 42},
+		{name: "Exponent", expr: "1.5e3", expected: 1500},
+		{name: "Ratio", expr: "errors / operations", expected: 0.05},
+		{name: "Throughput", expr: "operations / wall_time * 1e9", expected: 50},
+		{name: "Precedence", expr: "1 + 2 * 3 - 4 / 2", expected: 5},
+		{name: "Parentheses", expr: "(1 + 2) * 3", expected: 9},
+		{name: "Negation", expr: "-size / -(operations)", expected: 20},
+		{name: "Count", expr: "count(latency)", expected: 10},
+		{name: "Sum", expr: "sum(latency)", expected: 55},
+		{name: "Mean", expr: "mean(workers)", expected: 3},
+		{name: "Min", expr: "min(latency)", expected: 1},
+		{name: "Max", expr: "max(latency)", expected: 10},
+		{name: "Percentile", expr: "percentile(latency, 50)", expected: 5.5},
+		{name: "PercentileExpressionArgument", expr: "percentile(latency, 10 * 10)", expected: 10},
+		{name: "Bucket", expr: "bucket(latency, 3, 6)", expected: 3},
+		{name: "BucketRatio", expr: "bucket(latency, 0, 5) / count(latency)", expected: 0.4},
+		{name: "Nested", expr: "max(latency) / percentile(latency, 50) + duration / 1e9", expected: 10/5.5 + 0.5},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := parseRollupExpression(test.expr)
+			require.NoError(t, err)
+			assert.InDelta(t, test.expected, expr.eval(s), 1e-9)
+		})
+	}
+
+	for _, test := range []struct {
+		name string
+		expr string
+	}{
+		{name: "Empty", expr: ""},
+		{name: "UnknownVariable", expr: "latencies"},
+		{name: "UnknownFunction", expr: "median(latency)"},
+		{name: "UnaggregatedSeries", expr: "latency / 2"},
+		{name: "ScalarFunctionArgument", expr: "mean(operations)"},
+		{name: "MissingArgument", expr: "percentile(latency)"},
+		{name: "ExtraArgument", expr: "mean(latency, 1)"},
+		{name: "UnbalancedParentheses", expr: "(1 + 2"},
+		{name: "TrailingOperator", expr: "errors /"},
+		{name: "TrailingTokens", expr: "errors operations"},
+		{name: "InvalidCharacter", expr: "errors % operations"},
+		{name: "InvalidNumber", expr: "1.2.3"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseRollupExpression(test.expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCustomRollups(t *testing.T) {
+	defer func() {
+		_, err := RegisterCustomRollups(nil)
+		require.NoError(t, err)
+	}()
+
+	s := &PerformanceStatistics{}
+	s.counters.operationsTotal = 10
+	s.counters.errorsTotal = 1
+	s.timers.extractedDurations = []float64{1, 2, 3, 4}
+
+	t.Run("Calc", func(t *testing.T) {
+		rollups := []model.CustomRollup{
+			{Name: "ErrorRatio", Expression: "errors / operations"},
+			{Name: "Latency999thPercentile", Expression: "percentile(latency, 99.9)", MetricType: model.MetricTypeLatency},
+			{Name: "WorkersMean", Expression: "mean(workers)"},
+		}
+		factories, err := RegisterCustomRollups(rollups)
+		require.NoError(t, err)
+		require.Len(t, factories, 3)
+
+		assert.Equal(t, "ErrorRatio", factories[0].Type())
+		assert.Equal(t, []string{"ErrorRatio"}, factories[0].Names())
+		assert.Equal(t, rollups[0].Version(), factories[0].Version())
+		assert.Equal(t, []model.PerfRollupValue{{
+			Name:          "ErrorRatio",
+			Value:         0.1,
+			Version:       rollups[0].Version(),
+			MetricType:    model.MetricTypeMean,
+			UserSubmitted: true,
+		}}, factories[0].Calc(s, true))
+
+		values := factories[1].Calc(s, false)
+		require.Len(t, values, 1)
+		assert.Equal(t, rollups[1].Version(), values[0].Version)
+		assert.Equal(t, model.MetricTypeLatency, values[0].MetricType)
+		assert.InDelta(t, 4, values[0].Value, 0.01)
+
+		values = factories[2].Calc(s, false)
+		require.Len(t, values, 1)
+		assert.Nil(t, values[0].Value)
+	})
+	t.Run("RegistersInRollupsMap", func(t *testing.T) {
+		_, err := RegisterCustomRollups([]model.CustomRollup{{Name: "Custom", Expression: "operations"}})
+		require.NoError(t, err)
+		factory := RollupFactoryFromType("Custom")
+		require.NotNil(t, factory)
+		version := factory.Version()
+		assert.Contains(t, RollupsMap(), "Custom")
+
+		// Changing the definition changes the version.
+		_, err = RegisterCustomRollups([]model.CustomRollup{{Name: "Custom", Expression: "operations * 2"}})
+		require.NoError(t, err)
+		factory = RollupFactoryFromType("Custom")
+		require.NotNil(t, factory)
+		assert.NotEqual(t, version, factory.Version())
+		assert.True(t, IsCustomRollup(factory))
+		assert.True(t, IsRollupOutdated(factory, version))
+		assert.True(t, IsRollupOutdated(factory, factory.Version()+1))
+		assert.False(t, IsRollupOutdated(factory, factory.Version()))
+
+		_, err = RegisterCustomRollups([]model.CustomRollup{{Name: "Other", Expression: "errors"}})
+		require.NoError(t, err)
+		assert.Nil(t, RollupFactoryFromType("Custom"))
+		assert.NotNil(t, RollupFactoryFromType("Other"))
+		for _, factory := range DefaultRollupFactories() {
+			assert.NotNil(t, RollupFactoryFromType(factory.Type()))
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for name, rollups := range map[string][]model.CustomRollup{
+			"MissingName":       {{Expression: "operations"}},
+			"InvalidExpression": {{Name: "Custom", Expression: "operations +"}},
+			"DuplicateName": {
+				{Name: "Custom", Expression: "operations"},
+				{Name: "Custom", Expression: "errors"},
+			},
+			"DefaultType": {{Name: latencyPercentileName, Expression: "operations"}},
+			"DefaultName": {{Name: latencyPercentile50Name, Expression: "operations"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := RegisterCustomRollups([]model.CustomRollup{{Name: "Valid", Expression: "operations"}})
+				require.NoError(t, err)
+
+				assert.Error(t, ValidateCustomRollups(rollups))
+				_, err = RegisterCustomRollups(rollups)
+				assert.Error(t, err)
+				assert.NotNil(t, RollupFactoryFromType("Valid"))
+			})
+		}
+	})
+	t.Run("ValidateDoesNotRegister", func(t *testing.T) {
+		_, err := RegisterCustomRollups(nil)
+		require.NoError(t, err)
+
+		assert.NoError(t, ValidateCustomRollups([]model.CustomRollup{{Name: "Custom", Expression: "operations"}}))
+		assert.Nil(t, RollupFactoryFromType("Custom"))
+	})
+}
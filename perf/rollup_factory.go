@@ -1,10 +1,15 @@
 package perf
 
 import (
+	"math"
 	"sort"
+	"sync"
 
 	"github.com/aclements/go-moremath/stats"
+	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
 )
 
 type RollupFactory interface {
@@ -32,11 +37,30 @@ var rollupsMap = map[string]RollupFactory{
 	overheadSumName:         &overheadSum{},
 }
 
+// rollupsMapMutex guards the rollups map, which is updated whenever the
+// custom rollups are registered.
+var rollupsMapMutex sync.RWMutex
+
+// customRollupTypes are the types in the rollups map registered from the
+// custom rollup configuration.
+var customRollupTypes = map[string]bool{}
+
 func RollupsMap() map[string]RollupFactory {
-	return rollupsMap
+	rollupsMapMutex.RLock()
+	defer rollupsMapMutex.RUnlock()
+
+	rollups := make(map[string]RollupFactory, len(rollupsMap))
+	for t, factory := range rollupsMap {
+		rollups[t] = factory
+	}
+
+	return rollups
 }
 
 func RollupFactoryFromType(t string) RollupFactory {
+	rollupsMapMutex.RLock()
+	defer rollupsMapMutex.RUnlock()
+
 	return rollupsMap[t]
 }
 
@@ -60,6 +84,58 @@ var defaultRollups = []RollupFactory{
 
 func DefaultRollupFactories() []RollupFactory { return defaultRollups }
 
+// RollupFactories returns the default rollup factories followed by the
+// custom rollup factories defined in the application configuration,
+// registering the custom rollups in the rollups map.
+func RollupFactories(env cedar.Environment) ([]RollupFactory, error) {
+	conf := model.NewCedarConfig(env)
+	if err := conf.Find(); err != nil {
+		return nil, errors.Wrap(err, "getting application configuration")
+	}
+
+	custom, err := RegisterCustomRollups(conf.Rollups.Custom)
+	if err != nil {
+		return nil, errors.Wrap(err, "registering custom rollups")
+	}
+
+	factories := make([]RollupFactory, 0, len(defaultRollups)+len(custom))
+	factories = append(factories, defaultRollups...)
+
+	return append(factories, custom...), nil
+}
+
+// ValidateCustomRollups returns an error if any of the given custom rollups
+// is invalid, cannot be parsed, or shares a name with a default rollup.
+func ValidateCustomRollups(rollups []model.CustomRollup) error {
+	_, err := newCustomRollups(rollups)
+	return err
+}
+
+// RegisterCustomRollups creates the rollup factories for the given custom
+// rollups and registers them in the rollups map, replacing any previously
+// registered custom rollups. Custom rollups may not share a name with a
+// default rollup.
+func RegisterCustomRollups(rollups []model.CustomRollup) ([]RollupFactory, error) {
+	factories, err := newCustomRollups(rollups)
+	if err != nil {
+		return nil, err
+	}
+
+	rollupsMapMutex.Lock()
+	defer rollupsMapMutex.Unlock()
+
+	for t := range customRollupTypes {
+		delete(rollupsMap, t)
+	}
+	customRollupTypes = map[string]bool{}
+	for _, factory := range factories {
+		rollupsMap[factory.Type()] = factory
+		customRollupTypes[factory.Type()] = true
+	}
+
+	return factories, nil
+}
+
 //////////////////
 // Default Means
 //////////////////
@@ -493,3 +569,99 @@ func (f *overheadSum) Calc(s *PerformanceStatistics, user bool) []model.PerfRoll
 		},
 	}
 }
+
+///////////////////
+// Custom Rollups
+///////////////////
+type customRollup struct {
+	name       string
+	version    int
+	metricType model.MetricType
+	expression rollupExpression
+}
+
+func newCustomRollups(rollups []model.CustomRollup) ([]RollupFactory, error) {
+	if err := (model.RollupConfig{Custom: rollups}).Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid custom rollups")
+	}
+
+	reserved := map[string]bool{}
+	for _, factory := range defaultRollups {
+		reserved[factory.Type()] = true
+		for _, name := range factory.Names() {
+			reserved[name] = true
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	factories := []RollupFactory{}
+	for _, rollup := range rollups {
+		if reserved[rollup.Name] {
+			catcher.Errorf("custom rollup '%s' conflicts with a default rollup", rollup.Name)
+			continue
+		}
+
+		factory, err := newCustomRollup(rollup)
+		if err != nil {
+			catcher.Wrapf(err, "creating custom rollup '%s'", rollup.Name)
+			continue
+		}
+		factories = append(factories, factory)
+	}
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	return factories, nil
+}
+
+func newCustomRollup(rollup model.CustomRollup) (*customRollup, error) {
+	expression, err := parseRollupExpression(rollup.Expression)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &customRollup{
+		name:       rollup.Name,
+		version:    rollup.Version(),
+		metricType: rollup.GetMetricType(),
+		expression: expression,
+	}, nil
+}
+
+// IsCustomRollup returns whether the factory calculates a custom rollup, whose
+// version is a hash of its definition rather than an increasing number.
+func IsCustomRollup(factory RollupFactory) bool {
+	_, ok := factory.(*customRollup)
+	return ok
+}
+
+// IsRollupOutdated returns whether a rollup value with the given version is
+// outdated with respect to the factory that calculates it. Custom rollup
+// values are outdated whenever their version differs, default rollup values
+// only when their version is lower.
+func IsRollupOutdated(factory RollupFactory, version int) bool {
+	if IsCustomRollup(factory) {
+		return version != factory.Version()
+	}
+
+	return version < factory.Version()
+}
+
+func (f *customRollup) Type() string    { return f.name }
+func (f *customRollup) Names() []string { return []string{f.name} }
+func (f *customRollup) Version() int    { return f.version }
+func (f *customRollup) Calc(s *PerformanceStatistics, user bool) []model.PerfRollupValue {
+	rollup := model.PerfRollupValue{
+		Name:          f.name,
+		Version:       f.version,
+		MetricType:    f.metricType,
+		UserSubmitted: user,
+	}
+
+	if value := f.expression.eval(s); !math.IsNaN(value) && !math.IsInf(value, 0) {
+		rollup.Value = value
+	}
+
+	return []model.PerfRollupValue{rollup}
+}
@@ -3,12 +3,17 @@ package rest
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	dbModel "github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
@@ -25,6 +30,34 @@ const (
 	paginate      = "paginate"
 	trueString    = "true"
 	softSizeLimit = 10 * 1024 * 1024
+
+	logMinPriority = "min_priority"
+	logMaxPriority = "max_priority"
+	logInclude     = "include"
+	logExclude     = "exclude"
+	logMaxLines    = "max_lines"
+	logField       = "field"
+	logWhere       = "where"
+
+	logFollow       = "follow"
+	logFollowCursor = "cursor"
+
+	// LogFollowCursorTrailer is the trailer of a response following a
+	// log that holds the cursor with which to resume following the log.
+	LogFollowCursorTrailer = "Cedar-Log-Follow-Cursor"
+	// LogFollowLinesTrailer is the trailer of a response following a log
+	// that holds the number of lines sent in the response.
+	LogFollowLinesTrailer = "Cedar-Log-Follow-Lines"
+
+	logSearchPattern         = "pattern"
+	logSearchRegex           = "regex"
+	logSearchCaseInsensitive = "case_insensitive"
+	logSearchContext         = "context"
+	logSearchVersion         = "version"
+	logSearchVariant         = "variant"
+	logSearchTaskName        = "task_name"
+	logSearchTaskID          = "task_id"
+	logSearchLogOffset       = "log_offset"
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -32,8 +65,9 @@ const (
 // GET /buildlogger/{id}
 
 type logGetByIDHandler struct {
-	opts data.BuildloggerOptions
-	sc   data.Connector
+	opts   data.BuildloggerOptions
+	follow bool
+	sc     data.Connector
 }
 
 func makeGetLogByID(sc data.Connector) gimlet.RouteHandler {
@@ -58,12 +92,19 @@ func (h *logGetByIDHandler) Parse(_ context.Context, r *http.Request) error {
 	vals := r.URL.Query()
 	h.opts.PrintTime = vals.Get(printTime) == trueString
 	h.opts.PrintPriority = vals.Get(printPriority) == trueString
+	h.opts.Filter, err = parseLogFilter(vals)
+	catcher.Add(err)
 	h.opts.TimeRange, err = parseTimeRange(time.RFC3339Nano, vals.Get(logStartAt), vals.Get(logEndAt))
 	catcher.Add(err)
 	if len(vals[limit]) > 0 {
 		h.opts.Limit, err = strconv.Atoi(vals[limit][0])
 		catcher.Add(err)
 	}
+	h.follow = vals.Get(logFollow) == trueString
+	if h.follow {
+		h.opts.FollowCursor = vals.Get(logFollowCursor)
+		catcher.NewWhen(vals.Get(paginate) == trueString, "cannot paginate when following a log")
+	}
 	if vals.Get(paginate) == trueString && h.opts.Limit <= 0 {
 		h.opts.SoftSizeLimit = softSizeLimit
 	}
@@ -71,8 +112,28 @@ func (h *logGetByIDHandler) Parse(_ context.Context, r *http.Request) error {
 	return catcher.Resolve()
 }
 
-// Run calls FindLogByID and returns the log.
+// Run calls FindLogByID and returns the log. If following the log, Run calls
+// FollowLogByID and returns a response that streams the log lines as they are
+// appended, with the cursor of the stream sent as a trailer.
 func (h *logGetByIDHandler) Run(ctx context.Context) gimlet.Responder {
+	if h.follow {
+		r, err := h.sc.FollowLogByID(ctx, h.opts)
+		if err != nil {
+			err = errors.Wrapf(err, "following log by ID '%s'", h.opts.ID)
+			logFindError(err, message.Fields{
+				"request": gimlet.GetRequestID(ctx),
+				"method":  "GET",
+				"route":   "/buildlogger/{id}",
+				"id":      h.opts.ID,
+				"follow":  true,
+			})
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+
+		setLogFollowStream(ctx, r)
+		return gimlet.NewTextResponse(r)
+	}
+
 	data, next, paginated, err := h.sc.FindLogByID(ctx, h.opts)
 	if err != nil {
 		err = errors.Wrapf(err, "getting log by ID '%s'", h.opts.ID)
@@ -166,6 +227,8 @@ func (h *logGetByTaskIDHandler) Parse(_ context.Context, r *http.Request) error
 	h.opts.Tags = vals[tags]
 	h.opts.PrintTime = vals.Get(printTime) == trueString
 	h.opts.PrintPriority = vals.Get(printPriority) == trueString
+	h.opts.Filter, err = parseLogFilter(vals)
+	catcher.Add(err)
 	h.opts.TimeRange, err = parseTimeRange(time.RFC3339Nano, vals.Get(logStartAt), vals.Get(logEndAt))
 	catcher.Add(err)
 	if len(vals[execution]) > 0 {
@@ -261,6 +324,68 @@ func (h *logMetaGetByTaskIDHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(apiLogs)
 }
 
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /buildlogger/task_id/{task_id}/archive
+
+type logArchiveGetByTaskIDHandler struct {
+	opts data.BuildloggerOptions
+	sc   data.Connector
+}
+
+func makeGetLogArchiveByTaskID(sc data.Connector) gimlet.RouteHandler {
+	return &logArchiveGetByTaskIDHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new logArchiveGetByTaskIDHandler.
+func (h *logArchiveGetByTaskIDHandler) Factory() gimlet.RouteHandler {
+	return &logArchiveGetByTaskIDHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the task ID, execution, and time range from the HTTP request.
+func (h *logArchiveGetByTaskIDHandler) Parse(_ context.Context, r *http.Request) error {
+	var err error
+	catcher := grip.NewBasicCatcher()
+
+	h.opts.TaskID = gimlet.GetVars(r)["task_id"]
+	vals := r.URL.Query()
+	h.opts.ProcessName = vals.Get(procName)
+	h.opts.Tags = vals[tags]
+	h.opts.PrintTime = vals.Get(printTime) == trueString
+	h.opts.PrintPriority = vals.Get(printPriority) == trueString
+	h.opts.TimeRange, err = parseTimeRange(time.RFC3339Nano, vals.Get(logStartAt), vals.Get(logEndAt))
+	catcher.Add(err)
+	if len(vals[execution]) > 0 {
+		h.opts.Execution, err = strconv.Atoi(vals[execution][0])
+		catcher.Add(err)
+	} else {
+		h.opts.EmptyExecution = true
+	}
+
+	return catcher.Resolve()
+}
+
+// Run calls FindLogArchiveByTaskID and streams the archive of the logs.
+func (h *logArchiveGetByTaskIDHandler) Run(ctx context.Context) gimlet.Responder {
+	r, err := h.sc.FindLogArchiveByTaskID(ctx, h.opts)
+	if err != nil {
+		err = errors.Wrapf(err, "getting log archive by task ID '%s'", h.opts.TaskID)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/buildlogger/task_id/{task_id}/archive",
+			"task_id": h.opts.TaskID,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewBinaryResponse(r)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 //
 // GET /buildlogger/task_id/{task_id}/group/{group_id}
@@ -295,6 +420,8 @@ func (h *logGroupByTaskIDHandler) Parse(_ context.Context, r *http.Request) erro
 	h.opts.Tags = vals[tags]
 	h.opts.PrintTime = vals.Get(printTime) == trueString
 	h.opts.PrintPriority = vals.Get(printPriority) == trueString
+	h.opts.Filter, err = parseLogFilter(vals)
+	catcher.Add(err)
 	h.opts.TimeRange, err = parseTimeRange(time.RFC3339Nano, vals.Get(logStartAt), vals.Get(logEndAt))
 	catcher.Add(err)
 	if len(vals[execution]) > 0 {
@@ -366,6 +493,8 @@ func (h *logGetByTestNameHandler) Parse(_ context.Context, r *http.Request) erro
 	h.opts.Tags = vals[tags]
 	h.opts.PrintTime = vals.Get(printTime) == trueString
 	h.opts.PrintPriority = vals.Get(printPriority) == trueString
+	h.opts.Filter, err = parseLogFilter(vals)
+	catcher.Add(err)
 	h.opts.TimeRange, err = parseTimeRange(time.RFC3339Nano, vals.Get(logStartAt), vals.Get(logEndAt))
 	catcher.Add(err)
 	if len(vals[execution]) > 0 {
@@ -508,6 +637,8 @@ func (h *logGroupByTestNameHandler) Parse(_ context.Context, r *http.Request) er
 	h.opts.Tags = vals[tags]
 	h.opts.PrintTime = vals.Get(printTime) == trueString
 	h.opts.PrintPriority = vals.Get(printPriority) == trueString
+	h.opts.Filter, err = parseLogFilter(vals)
+	catcher.Add(err)
 	if len(vals[execution]) > 0 {
 		h.opts.Execution, err = strconv.Atoi(vals[execution][0])
 		catcher.Add(err)
@@ -577,6 +708,313 @@ func (h *logGroupByTestNameHandler) Run(ctx context.Context) gimlet.Responder {
 	return newBuildloggerResponder(h.sc.GetBaseURL(), data, h.opts.TimeRange.StartAt, next, paginated)
 }
 
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /buildlogger/test_result/{task_id}/{test_name}
+
+type logGetByTestResultHandler struct {
+	opts data.BuildloggerOptions
+	sc   data.Connector
+}
+
+func makeGetLogByTestResult(sc data.Connector) gimlet.RouteHandler {
+	return &logGetByTestResultHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new logGetByTestResultHandler.
+func (h *logGetByTestResultHandler) Factory() gimlet.RouteHandler {
+	return &logGetByTestResultHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the task ID, test name, and execution (if present) from the
+// HTTP request.
+func (h *logGetByTestResultHandler) Parse(_ context.Context, r *http.Request) error {
+	var err error
+
+	h.opts.TaskID = gimlet.GetVars(r)["task_id"]
+	h.opts.TestName = gimlet.GetVars(r)["test_name"]
+	vals := r.URL.Query()
+	h.opts.PrintTime = vals.Get(printTime) == trueString
+	h.opts.PrintPriority = vals.Get(printPriority) == trueString
+	if len(vals[execution]) > 0 {
+		h.opts.Execution, err = strconv.Atoi(vals[execution][0])
+	} else {
+		h.opts.EmptyExecution = true
+	}
+
+	return err
+}
+
+// Run calls FindTestResultLog and returns the lines of the logs that belong
+// to the test result.
+func (h *logGetByTestResultHandler) Run(ctx context.Context) gimlet.Responder {
+	data, err := h.sc.FindTestResultLog(ctx, h.opts)
+	if err != nil {
+		err = errors.Wrapf(err, "getting logs by test result '%s'", h.opts.TestName)
+		logFindError(err, message.Fields{
+			"request":   gimlet.GetRequestID(ctx),
+			"method":    "GET",
+			"route":     "/buildlogger/test_result/{task_id}/{test_name}",
+			"task_id":   h.opts.TaskID,
+			"test_name": h.opts.TestName,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewTextResponse(data)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /buildlogger/search/{project_id}
+
+type logSearchHandler struct {
+	opts dbModel.LogSearchOptions
+	sc   data.Connector
+}
+
+func makeSearchLogs(sc data.Connector) gimlet.RouteHandler {
+	return &logSearchHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new logSearchHandler.
+func (h *logSearchHandler) Factory() gimlet.RouteHandler {
+	return &logSearchHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the project ID, search pattern, and log filters from the HTTP
+// request.
+func (h *logSearchHandler) Parse(_ context.Context, r *http.Request) error {
+	var err error
+	catcher := grip.NewBasicCatcher()
+
+	h.opts.Info.Project = gimlet.GetVars(r)["project_id"]
+	vals := r.URL.Query()
+	h.opts.Info.Version = vals.Get(logSearchVersion)
+	h.opts.Info.Variant = vals.Get(logSearchVariant)
+	h.opts.Info.TaskName = vals.Get(logSearchTaskName)
+	h.opts.Info.TaskID = vals.Get(logSearchTaskID)
+	h.opts.Pattern = vals.Get(logSearchPattern)
+	h.opts.Regex = vals.Get(logSearchRegex) == trueString
+	h.opts.CaseInsensitive = vals.Get(logSearchCaseInsensitive) == trueString
+	h.opts.TimeRange, err = parseTimeRange(time.RFC3339Nano, vals.Get(logStartAt), vals.Get(logEndAt))
+	catcher.Add(err)
+	if len(vals[logSearchContext]) > 0 {
+		h.opts.Context, err = strconv.Atoi(vals[logSearchContext][0])
+		catcher.Add(err)
+	}
+	if len(vals[limit]) > 0 {
+		h.opts.Limit, err = strconv.Atoi(vals[limit][0])
+		catcher.Add(err)
+	}
+	if len(vals[logSearchLogOffset]) > 0 {
+		h.opts.LogOffset, err = strconv.Atoi(vals[logSearchLogOffset][0])
+		catcher.Add(err)
+	}
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	if err = h.opts.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid log search options").Error(),
+		}
+	}
+
+	return nil
+}
+
+// Run calls SearchLogs and returns the matching log lines. If the search
+// stopped before scanning every matching log, the response links to the next
+// page of the search.
+func (h *logSearchHandler) Run(ctx context.Context) gimlet.Responder {
+	results, nextLogOffset, err := h.sc.SearchLogs(ctx, h.opts)
+	if err != nil {
+		err = errors.Wrapf(err, "searching logs in project '%s'", h.opts.Info.Project)
+		logFindError(err, message.Fields{
+			"request":    gimlet.GetRequestID(ctx),
+			"method":     "GET",
+			"route":      "/buildlogger/search/{project_id}",
+			"project_id": h.opts.Info.Project,
+			"version":    h.opts.Info.Version,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return newLogSearchResponder(h.sc.GetBaseURL(), results, h.opts.Limit, nextLogOffset)
+}
+
+func newLogSearchResponder(baseURL string, results []model.APILogSearchResult, resultsLimit, nextLogOffset int) gimlet.Responder {
+	resp := gimlet.NewJSONResponse(results)
+	if nextLogOffset == 0 {
+		return resp
+	}
+
+	pages := &gimlet.ResponsePages{
+		Next: &gimlet.Page{
+			BaseURL:         baseURL,
+			KeyQueryParam:   logSearchLogOffset,
+			LimitQueryParam: limit,
+			Key:             strconv.Itoa(nextLogOffset),
+			Limit:           resultsLimit,
+			Relation:        "next",
+		},
+	}
+	if err := resp.SetPages(pages); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "setting response pages"))
+	}
+
+	return resp
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /buildlogger/signatures/{project_id}
+
+type logFailureSignaturesHandler struct {
+	opts dbModel.TopLogFailureSignaturesOptions
+	sc   data.Connector
+}
+
+func makeGetLogFailureSignatures(sc data.Connector) gimlet.RouteHandler {
+	return &logFailureSignaturesHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new logFailureSignaturesHandler.
+func (h *logFailureSignaturesHandler) Factory() gimlet.RouteHandler {
+	return &logFailureSignaturesHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the project ID, variant, time range, and limit from the HTTP
+// request.
+func (h *logFailureSignaturesHandler) Parse(_ context.Context, r *http.Request) error {
+	var err error
+	catcher := grip.NewBasicCatcher()
+
+	h.opts.Project = gimlet.GetVars(r)["project_id"]
+	vals := r.URL.Query()
+	h.opts.Variant = vals.Get(logSearchVariant)
+	h.opts.TimeRange, err = parseTimeRange(time.RFC3339Nano, vals.Get(logStartAt), vals.Get(logEndAt))
+	catcher.Add(err)
+	if len(vals[limit]) > 0 {
+		h.opts.Limit, err = strconv.Atoi(vals[limit][0])
+		catcher.Add(err)
+	}
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	if err = h.opts.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid top failure signatures options").Error(),
+		}
+	}
+
+	return nil
+}
+
+// Run calls FindTopLogFailureSignatures and returns the most common failure
+// signatures.
+func (h *logFailureSignaturesHandler) Run(ctx context.Context) gimlet.Responder {
+	counts, err := h.sc.FindTopLogFailureSignatures(ctx, h.opts)
+	if err != nil {
+		err = errors.Wrapf(err, "finding top failure signatures in project '%s'", h.opts.Project)
+		logFindError(err, message.Fields{
+			"request":    gimlet.GetRequestID(ctx),
+			"method":     "GET",
+			"route":      "/buildlogger/signatures/{project_id}",
+			"project_id": h.opts.Project,
+			"variant":    h.opts.Variant,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(counts)
+}
+
+// parseLogFilter returns the log line filters set in the query parameters.
+// Priorities may be specified either by name or by value and where conditions
+// on the fields of structured lines are of the form "field:value".
+func parseLogFilter(vals url.Values) (dbModel.LogFilterOptions, error) {
+	var err error
+	filter := dbModel.LogFilterOptions{
+		Include: vals.Get(logInclude),
+		Exclude: vals.Get(logExclude),
+		Fields:  vals[logField],
+	}
+	catcher := grip.NewBasicCatcher()
+
+	if vals.Get(logMinPriority) != "" {
+		filter.MinPriority, err = parsePriority(vals.Get(logMinPriority))
+		catcher.Wrap(err, "parsing minimum priority")
+	}
+	if vals.Get(logMaxPriority) != "" {
+		filter.MaxPriority, err = parsePriority(vals.Get(logMaxPriority))
+		catcher.Wrap(err, "parsing maximum priority")
+	}
+	if len(vals[logMaxLines]) > 0 {
+		filter.MaxLines, err = strconv.Atoi(vals[logMaxLines][0])
+		catcher.Wrap(err, "parsing max lines")
+	}
+	for _, where := range vals[logWhere] {
+		field, value, err := parseLogWhere(where)
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+		if _, ok := filter.Where[field]; ok {
+			catcher.Errorf("duplicate where field '%s'", field)
+			continue
+		}
+		if filter.Where == nil {
+			filter.Where = map[string]string{}
+		}
+		filter.Where[field] = value
+	}
+	if catcher.HasErrors() {
+		return filter, catcher.Resolve()
+	}
+
+	return filter, errors.Wrap(filter.Validate(), "invalid log filter")
+}
+
+// parseLogWhere parses a where condition of the form "field:value".
+func parseLogWhere(where string) (string, string, error) {
+	parts := strings.SplitN(where, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", errors.Errorf("invalid where condition '%s', must be of the form 'field:value'", where)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func parsePriority(value string) (level.Priority, error) {
+	if p, err := strconv.Atoi(value); err == nil {
+		return level.Priority(p), nil
+	}
+
+	p := level.FromString(value)
+	if p == level.Invalid {
+		return p, errors.Errorf("invalid priority '%s'", value)
+	}
+
+	return p, nil
+}
+
 func newBuildloggerResponder(baseURL string, data []byte, last, next time.Time, paginated bool) gimlet.Responder {
 	resp := gimlet.NewTextResponse(data)
 
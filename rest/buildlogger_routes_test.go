@@ -1,11 +1,19 @@
 package rest
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +22,7 @@ import (
 	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/pail"
+	"github.com/mongodb/grip/level"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -52,6 +61,9 @@ func (s *LogHandlerSuite) setup(tempDir string) {
 					Type:   dbModel.PailLocal,
 					Prefix: "abc",
 				},
+				FailureSignatures: []dbModel.LogFailureSignature{
+					{Rule: "panic", Signature: "panic: runtime error", Snippet: "panic: runtime error", LineNum: 10},
+				},
 			},
 			"def": {
 				ID: "def",
@@ -83,6 +95,10 @@ func (s *LogHandlerSuite) setup(tempDir string) {
 					Type:   dbModel.PailLocal,
 					Prefix: "ghi",
 				},
+				FailureSignatures: []dbModel.LogFailureSignature{
+					{Rule: "go_test_fail", Signature: "TestFoo", Snippet: "--- FAIL: TestFoo (0.01s)", LineNum: 5},
+					{Rule: "panic", Signature: "panic: runtime error", Snippet: "panic: runtime error: index out of range", LineNum: 20},
+				},
 			},
 			"jkl": {
 				ID: "jkl",
@@ -135,15 +151,26 @@ func (s *LogHandlerSuite) setup(tempDir string) {
 			},
 		},
 	}
+	s.sc.CachedTestResults = map[string][]dbModel.TestResult{
+		"task_id2": {
+			{TaskID: "task_id2", TestName: "test0", LogTestName: "test1", LineNum: 10},
+			{TaskID: "task_id2", TestName: "test1", LogTestName: "test1", LineNum: 40},
+			{TaskID: "task_id2", TestName: "test2", LogTestName: "test1", LineNum: 25},
+		},
+	}
 	s.rh = map[string]gimlet.RouteHandler{
 		"id":              makeGetLogByID(&s.sc),
 		"meta_id":         makeGetLogMetaByID(&s.sc),
 		"task_id":         makeGetLogByTaskID(&s.sc),
 		"meta_task_id":    makeGetLogMetaByTaskID(&s.sc),
+		"archive_task_id": makeGetLogArchiveByTaskID(&s.sc),
 		"group_task_id":   makeGetLogGroupByTaskID(&s.sc),
 		"test_name":       makeGetLogByTestName(&s.sc),
 		"meta_test_name":  makeGetLogMetaByTestName(&s.sc),
 		"group_test_name": makeGetLogGroupByTestName(&s.sc),
+		"search":          makeSearchLogs(&s.sc),
+		"signatures":      makeGetLogFailureSignatures(&s.sc),
+		"test_result":     makeGetLogByTestResult(&s.sc),
 	}
 	s.apiResults = map[string]model.APILog{}
 	s.buckets = map[string]pail.Bucket{}
@@ -246,6 +273,200 @@ func (s *LogHandlerSuite) TestLogGetByIDHandlerFound() {
 	}
 }
 
+func (s *LogHandlerSuite) TestLogGetByIDHandlerFiltered() {
+	rh := s.rh["id"].Factory()
+	rh.(*logGetByIDHandler).opts.ID = "abc"
+	rh.(*logGetByIDHandler).opts.TimeRange = dbModel.TimeRange{
+		StartAt: time.Now().Add(-24 * time.Hour),
+		EndAt:   time.Now().Add(time.Hour),
+	}
+	rh.(*logGetByIDHandler).opts.Filter = dbModel.LogFilterOptions{MinPriority: level.Info}
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Empty(resp.Data())
+
+	rh.(*logGetByIDHandler).opts.Filter = dbModel.LogFilterOptions{MaxPriority: level.Debug, MaxLines: 5}
+	it := dbModel.NewBatchedLogIterator(
+		s.buckets["abc"],
+		s.sc.CachedLogs["abc"].Artifact.Chunks,
+		batchSize,
+		rh.(*logGetByIDHandler).opts.TimeRange,
+	)
+	expected, err := ioutil.ReadAll(dbModel.NewLogIteratorReader(context.TODO(), it, dbModel.LogIteratorReaderOptions{Limit: 5}))
+	s.Require().NoError(err)
+
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal(expected, resp.Data())
+
+	rh.(*logGetByIDHandler).opts.Filter = dbModel.LogFilterOptions{Include: "("}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusBadRequest, resp.Status())
+}
+
+func (s *LogHandlerSuite) TestLogGetByIDHandlerStructured() {
+	ts := time.Now().Add(-time.Hour).Round(time.Millisecond).UTC()
+	records := []string{
+		`{"level":"info","component":"network","msg":{"text":"connected"}}`,
+		`{"level":"error","component":"storage","msg":{"text":"write failed"}}`,
+		`{"level":"error","component":"network","msg":{"text":"connection reset"}}`,
+	}
+	var rawLines string
+	for i, record := range records {
+		rawLines += fmt.Sprintf("%3d%20d%s\n", level.Info, ts.Add(time.Duration(i)*time.Millisecond).UnixNano()/1e6, record)
+	}
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: s.sc.Bucket, Prefix: "structured"})
+	s.Require().NoError(err)
+	chunk := dbModel.LogChunkInfo{
+		Key:      "structured-chunk",
+		NumLines: len(records),
+		Start:    ts,
+		End:      ts.Add(time.Duration(len(records)-1) * time.Millisecond),
+	}
+	s.Require().NoError(bucket.Put(context.TODO(), chunk.Key, strings.NewReader(rawLines)))
+	s.sc.CachedLogs["structured"] = dbModel.Log{
+		ID:   "structured",
+		Info: dbModel.LogInfo{Format: dbModel.LogFormatJSON},
+		Artifact: dbModel.LogArtifactInfo{
+			Type:   dbModel.PailLocal,
+			Prefix: "structured",
+			Chunks: []dbModel.LogChunkInfo{chunk},
+		},
+	}
+	defer delete(s.sc.CachedLogs, "structured")
+
+	rh := s.rh["id"].Factory()
+	rh.(*logGetByIDHandler).opts.ID = "structured"
+	rh.(*logGetByIDHandler).opts.TimeRange = dbModel.TimeRange{EndAt: time.Now()}
+	rh.(*logGetByIDHandler).opts.Filter = dbModel.LogFilterOptions{
+		Where:  map[string]string{"level": "error"},
+		Fields: []string{"component", "msg.text"},
+	}
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal(
+		`{"component":"storage","msg.text":"write failed"}`+"\n"+`{"component":"network","msg.text":"connection reset"}`+"\n",
+		string(resp.Data().([]byte)),
+	)
+
+	rh.(*logGetByIDHandler).opts.Filter = dbModel.LogFilterOptions{Where: map[string]string{"component": "network", "level": "info"}}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal(records[0]+"\n", string(resp.Data().([]byte)))
+}
+
+func (s *LogHandlerSuite) TestLogGetByIDHandlerFollow() {
+	rh := s.rh["id"].Factory()
+	rh.(*logGetByIDHandler).opts.ID = "abc"
+	rh.(*logGetByIDHandler).follow = true
+	chunks := s.sc.CachedLogs["abc"].Artifact.Chunks
+	s.Require().True(len(chunks) > 1)
+	it := dbModel.NewBatchedLogIterator(
+		s.buckets["abc"],
+		chunks,
+		batchSize,
+		dbModel.TimeRange{EndAt: time.Now().AddDate(100, 0, 0)},
+	)
+	expected, err := ioutil.ReadAll(dbModel.NewLogIteratorReader(context.TODO(), it, dbModel.LogIteratorReaderOptions{}))
+	s.Require().NoError(err)
+	s.Require().NotEmpty(expected)
+	expectedLines := strings.SplitAfter(string(expected), "\n")
+	expectedLines = expectedLines[:len(expectedLines)-1]
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal(gimlet.TEXT, resp.Format())
+	r, ok := resp.Data().(io.Reader)
+	s.Require().True(ok)
+	data, err := ioutil.ReadAll(r)
+	s.Require().NoError(err)
+	s.Equal(expected, data)
+
+	// The cursor is the key of the last chunk streamed and resumes the
+	// log after it.
+	follow := func(cursor string) *http.Response {
+		rh = rh.Factory()
+		rh.(*logGetByIDHandler).opts.ID = "abc"
+		rh.(*logGetByIDHandler).opts.FollowCursor = cursor
+		rh.(*logGetByIDHandler).follow = true
+		rec := httptest.NewRecorder()
+		newLogFollowMiddleware().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/buildlogger/abc?follow=true", nil), func(rw http.ResponseWriter, r *http.Request) {
+			gimlet.WriteResponse(rw, rh.Run(r.Context()))
+		})
+		return rec.Result()
+	}
+	result := follow("")
+	s.Equal(http.StatusOK, result.StatusCode)
+	s.Equal(chunks[len(chunks)-1].Key, result.Trailer.Get(LogFollowCursorTrailer))
+	s.Equal(strconv.Itoa(len(expectedLines)), result.Trailer.Get(LogFollowLinesTrailer))
+
+	result = follow(chunks[0].Key)
+	s.Equal(http.StatusOK, result.StatusCode)
+	body, err := ioutil.ReadAll(result.Body)
+	s.Require().NoError(err)
+	s.Equal(strings.Join(expectedLines[chunks[0].NumLines:], ""), string(body))
+	s.Equal(chunks[len(chunks)-1].Key, result.Trailer.Get(LogFollowCursorTrailer))
+	s.Equal(strconv.Itoa(len(expectedLines)-chunks[0].NumLines), result.Trailer.Get(LogFollowLinesTrailer))
+
+	log := s.sc.CachedLogs["abc"]
+	completedAt := log.CompletedAt
+	log.CompletedAt = time.Time{}
+	s.sc.CachedLogs["abc"] = log
+	defer func() {
+		log.CompletedAt = completedAt
+		s.sc.CachedLogs["abc"] = log
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	resp = rh.Run(ctx)
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	r, ok = resp.Data().(io.Reader)
+	s.Require().True(ok)
+	data, err = ioutil.ReadAll(r)
+	s.Error(err)
+	s.Equal(strings.Join(expectedLines[chunks[0].NumLines:], ""), string(data))
+}
+
+func (s *LogHandlerSuite) TestLogGetByIDHandlerFollowNotFound() {
+	rh := s.rh["id"].Factory()
+	rh.(*logGetByIDHandler).opts.ID = "DNE"
+	rh.(*logGetByIDHandler).follow = true
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *LogHandlerSuite) TestLogGetByIDHandlerFollowParse() {
+	ctx := context.Background()
+	rh := s.rh["id"].Factory()
+	req := gimlet.SetURLVars(&http.Request{Method: "GET"}, map[string]string{"id": "abc"})
+	req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/abc?follow=true&cursor=1_2_3&max_lines=20")
+	s.Require().NoError(rh.Parse(ctx, req))
+	s.True(rh.(*logGetByIDHandler).follow)
+	s.Equal("1_2_3", rh.(*logGetByIDHandler).opts.FollowCursor)
+	s.Equal(20, rh.(*logGetByIDHandler).opts.Filter.MaxLines)
+	s.Zero(rh.(*logGetByIDHandler).opts.SoftSizeLimit)
+
+	for _, query := range []string{
+		"?follow=true&paginate=true",
+	} {
+		rh = rh.Factory()
+		req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/abc" + query)
+		s.Error(rh.Parse(ctx, req), query)
+	}
+}
+
 func (s *LogHandlerSuite) TestLogGetByIDHandlerNotFound() {
 	rh := s.rh["id"].Factory()
 	rh.(*logGetByIDHandler).opts.ID = "DNE"
@@ -552,6 +773,73 @@ func (s *LogHandlerSuite) TestLogMetaGetByTaskIDHandlerCtxErr() {
 	s.NotEqual(http.StatusOK, resp.Status())
 }
 
+func (s *LogHandlerSuite) TestLogArchiveGetByTaskIDHandlerFound() {
+	rh := s.rh["archive_task_id"].Factory()
+	rh.(*logArchiveGetByTaskIDHandler).opts.TaskID = "task_id1"
+	rh.(*logArchiveGetByTaskIDHandler).opts.Tags = []string{"tag1"}
+	rh.(*logArchiveGetByTaskIDHandler).opts.TimeRange = dbModel.TimeRange{EndAt: time.Now().Add(24 * time.Hour)}
+	expected := []model.APILog{s.apiResults["jkl"], s.apiResults["mno"]}
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	r, ok := resp.Data().(io.ReadCloser)
+	s.Require().True(ok)
+	defer func() {
+		s.NoError(r.Close())
+	}()
+
+	archive, err := ioutil.ReadAll(r)
+	s.Require().NoError(err)
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	s.Require().NoError(err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		s.Require().NoError(err)
+		files[f.Name], err = ioutil.ReadAll(rc)
+		s.Require().NoError(err)
+		s.Require().NoError(rc.Close())
+	}
+	s.Require().Len(files, len(expected)+1)
+
+	var manifest []model.APILogArchiveEntry
+	s.Require().NoError(json.Unmarshal(files[dbModel.LogArchiveManifestName], &manifest))
+	s.Require().Len(manifest, len(expected))
+	for i, entry := range manifest {
+		s.Equal(*expected[i].ID, *entry.Log.ID)
+		content, ok := files[*entry.FileName]
+		s.Require().True(ok)
+
+		expectedContent, _, _, err := s.sc.FindLogByID(context.TODO(), data.BuildloggerOptions{
+			ID:        *entry.Log.ID,
+			TimeRange: rh.(*logArchiveGetByTaskIDHandler).opts.TimeRange,
+		})
+		s.Require().NoError(err)
+		s.Equal(string(expectedContent), string(content))
+	}
+}
+
+func (s *LogHandlerSuite) TestLogArchiveGetByTaskIDHandlerNotFound() {
+	rh := s.rh["archive_task_id"].Factory()
+	rh.(*logArchiveGetByTaskIDHandler).opts.TaskID = "DNE"
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *LogHandlerSuite) TestLogArchiveGetByTaskIDHandlerCtxErr() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rh := s.rh["archive_task_id"].Factory()
+	rh.(*logArchiveGetByTaskIDHandler).opts.TaskID = "task_id1"
+
+	resp := rh.Run(ctx)
+	s.Require().NotNil(resp)
+	s.NotEqual(http.StatusOK, resp.Status())
+}
+
 func (s *LogHandlerSuite) TestLogGroupByTaskIDHandlerFound() {
 	for _, printTime := range []bool{true, false} {
 		opts := dbModel.LogIteratorReaderOptions{
@@ -1079,6 +1367,364 @@ func (s *LogHandlerSuite) TestLogGroupByTestNameHandlerCtxErr() {
 	s.NotEqual(http.StatusOK, resp.Status())
 }
 
+func (s *LogHandlerSuite) TestLogGetByTestResultHandlerFound() {
+	it := dbModel.NewBatchedLogIterator(
+		s.buckets["ghi"],
+		s.sc.CachedLogs["ghi"].Artifact.Chunks,
+		batchSize,
+		dbModel.TimeRange{EndAt: time.Now().AddDate(100, 0, 0)},
+	)
+	var lines []dbModel.LogLine
+	for it.Next(context.TODO()) {
+		lines = append(lines, it.Item())
+	}
+	s.Require().NoError(it.Err())
+	s.Require().Len(lines, 100)
+	joinLines := func(lines []dbModel.LogLine) string {
+		var data string
+		for _, line := range lines {
+			data += line.Data
+		}
+		return data
+	}
+	readResp := func(testName string) string {
+		rh := s.rh["test_result"].Factory()
+		rh.(*logGetByTestResultHandler).opts = data.BuildloggerOptions{
+			TaskID:         "task_id2",
+			TestName:       testName,
+			EmptyExecution: true,
+		}
+
+		resp := rh.Run(context.TODO())
+		s.Require().NotNil(resp)
+		s.Require().Equal(http.StatusOK, resp.Status())
+		s.Equal(gimlet.TEXT, resp.Format())
+		content, ok := resp.Data().([]byte)
+		s.Require().True(ok)
+		return string(content)
+	}
+
+	s.Run("LineRange", func() {
+		s.Equal(joinLines(lines[10:25]), readResp("test0"))
+		s.Equal(joinLines(lines[25:40]), readResp("test2"))
+	})
+	s.Run("LastInLog", func() {
+		s.Equal(joinLines(lines[40:]), readResp("test1"))
+	})
+	s.Run("Interleaved", func() {
+		results := s.sc.CachedTestResults["task_id2"]
+		defer func() {
+			s.sc.CachedTestResults["task_id2"] = results
+		}()
+		s.sc.CachedTestResults["task_id2"] = []dbModel.TestResult{
+			{TaskID: "task_id2", TestName: "test0", LogTestName: "test1", LineNum: 10, TestStartTime: lines[20].Timestamp, TestEndTime: lines[25].Timestamp},
+			{TaskID: "task_id2", TestName: "test1", LogTestName: "test1", LineNum: 22, TestStartTime: lines[22].Timestamp, TestEndTime: lines[30].Timestamp},
+		}
+
+		s.Equal(joinLines(lines[20:26]), readResp("test0"))
+	})
+}
+
+func (s *LogHandlerSuite) TestLogGetByTestResultHandlerNotFound() {
+	for _, opts := range []data.BuildloggerOptions{
+		{TaskID: "DNE", TestName: "test0", EmptyExecution: true},
+		{TaskID: "task_id2", TestName: "DNE", EmptyExecution: true},
+		{TaskID: "task_id2", TestName: "test0", Execution: 5},
+	} {
+		rh := s.rh["test_result"].Factory()
+		rh.(*logGetByTestResultHandler).opts = opts
+
+		resp := rh.Run(context.TODO())
+		s.Require().NotNil(resp)
+		s.Equal(http.StatusNotFound, resp.Status())
+	}
+}
+
+func (s *LogHandlerSuite) TestLogGetByTestResultHandlerCtxErr() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rh := s.rh["test_result"].Factory()
+	rh.(*logGetByTestResultHandler).opts = data.BuildloggerOptions{
+		TaskID:         "task_id2",
+		TestName:       "test0",
+		EmptyExecution: true,
+	}
+
+	resp := rh.Run(ctx)
+	s.Require().NotNil(resp)
+	s.NotEqual(http.StatusOK, resp.Status())
+}
+
+func (s *LogHandlerSuite) TestLogGetByTestResultHandlerParse() {
+	ctx := context.Background()
+	rh := s.rh["test_result"].Factory()
+	req := gimlet.SetURLVars(&http.Request{Method: "GET"}, map[string]string{"task_id": "task", "test_name": "test"})
+	req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/test_result/task/test?execution=2&print_time=true&print_priority=true")
+	s.Require().NoError(rh.Parse(ctx, req))
+	s.Equal(data.BuildloggerOptions{
+		TaskID:        "task",
+		TestName:      "test",
+		Execution:     2,
+		PrintTime:     true,
+		PrintPriority: true,
+	}, rh.(*logGetByTestResultHandler).opts)
+
+	rh = rh.Factory()
+	req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/test_result/task/test")
+	s.Require().NoError(rh.Parse(ctx, req))
+	s.True(rh.(*logGetByTestResultHandler).opts.EmptyExecution)
+
+	rh = rh.Factory()
+	req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/test_result/task/test?execution=hello")
+	s.Error(rh.Parse(ctx, req))
+}
+
+func (s *LogHandlerSuite) TestLogSearchHandlerFound() {
+	tr := dbModel.TimeRange{
+		StartAt: time.Now().Add(-48 * time.Hour),
+		EndAt:   time.Now().Add(time.Hour),
+	}
+	it := dbModel.NewBatchedLogIterator(s.buckets["ghi"], s.sc.CachedLogs["ghi"].Artifact.Chunks, batchSize, tr)
+	var lines []dbModel.LogLine
+	for it.Next(context.TODO()) {
+		lines = append(lines, it.Item())
+	}
+	s.Require().NoError(it.Err())
+	s.Require().NoError(it.Close())
+	s.Require().True(len(lines) > 10)
+
+	rh := s.rh["search"].Factory()
+	rh.(*logSearchHandler).opts = dbModel.LogSearchOptions{
+		Info: dbModel.LogInfo{
+			Project: "project",
+			TaskID:  "task_id2",
+		},
+		Pattern:   lines[5].Data[:50],
+		Context:   2,
+		TimeRange: tr,
+		Limit:     10,
+	}
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	results, ok := resp.Data().([]model.APILogSearchResult)
+	s.Require().True(ok)
+	s.Require().Len(results, 1)
+	s.Equal("ghi", *results[0].LogID)
+	s.Equal(6, results[0].LineNum)
+	s.Equal(strings.TrimSuffix(lines[5].Data, "\n"), results[0].Line.Data)
+	s.Require().Len(results[0].Before, 2)
+	s.Equal(strings.TrimSuffix(lines[3].Data, "\n"), results[0].Before[0].Data)
+	s.Require().Len(results[0].After, 2)
+	s.Equal(strings.TrimSuffix(lines[7].Data, "\n"), results[0].After[1].Data)
+}
+
+func (s *LogHandlerSuite) TestLogSearchHandlerNoMatches() {
+	rh := s.rh["search"].Factory()
+	rh.(*logSearchHandler).opts = dbModel.LogSearchOptions{
+		Info:      dbModel.LogInfo{Project: "DNE"},
+		Pattern:   "a",
+		TimeRange: dbModel.TimeRange{EndAt: time.Now()},
+	}
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	results, ok := resp.Data().([]model.APILogSearchResult)
+	s.Require().True(ok)
+	s.Empty(results)
+}
+
+func (s *LogHandlerSuite) TestLogSearchHandlerCtxErr() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rh := s.rh["search"].Factory()
+	rh.(*logSearchHandler).opts = dbModel.LogSearchOptions{
+		Info:      dbModel.LogInfo{Project: "project"},
+		Pattern:   "a",
+		TimeRange: dbModel.TimeRange{EndAt: time.Now()},
+	}
+
+	resp := rh.Run(ctx)
+	s.Require().NotNil(resp)
+	s.NotEqual(http.StatusOK, resp.Status())
+}
+
+func (s *LogHandlerSuite) TestLogSearchHandlerParse() {
+	ctx := context.Background()
+	rh := s.rh["search"].Factory()
+	req := gimlet.SetURLVars(&http.Request{Method: "GET"}, map[string]string{"project_id": "project"})
+	req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/search/project?pattern=fo%2B&regex=true&case_insensitive=true&context=3&limit=5&log_offset=2000&version=v1&variant=var&task_name=tn&task_id=tid&start=2012-11-01T22:08:00%2B00:00&end=2013-11-01T22:08:00%2B00:00")
+	s.Require().NoError(rh.Parse(ctx, req))
+	s.Equal(dbModel.LogSearchOptions{
+		Info: dbModel.LogInfo{
+			Project:  "project",
+			Version:  "v1",
+			Variant:  "var",
+			TaskName: "tn",
+			TaskID:   "tid",
+		},
+		Pattern:         "fo+",
+		Regex:           true,
+		CaseInsensitive: true,
+		Context:         3,
+		Limit:           5,
+		LogOffset:       2000,
+		TimeRange: dbModel.TimeRange{
+			StartAt: time.Date(2012, time.November, 1, 22, 8, 0, 0, time.UTC),
+			EndAt:   time.Date(2013, time.November, 1, 22, 8, 0, 0, time.UTC),
+		},
+	}, rh.(*logSearchHandler).opts)
+
+	for _, query := range []string{
+		"",
+		"?pattern=f%28o&regex=true",
+		"?pattern=foo&context=100",
+		"?pattern=foo&limit=-1",
+		"?pattern=foo&log_offset=-1",
+		"?pattern=foo&log_offset=a",
+		"?pattern=foo&start=hello",
+	} {
+		rh = rh.Factory()
+		req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/search/project" + query)
+		s.Error(rh.Parse(ctx, req), query)
+	}
+}
+
+func TestNewLogSearchResponder(t *testing.T) {
+	results := []model.APILogSearchResult{}
+	t.Run("NoNextPage", func(t *testing.T) {
+		resp := newLogSearchResponder("https://cedar.mongodb.com", results, 5, 0)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		assert.Nil(t, resp.Pages())
+	})
+	t.Run("NextPage", func(t *testing.T) {
+		resp := newLogSearchResponder("https://cedar.mongodb.com", results, 5, 1000)
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.NotNil(t, resp.Pages())
+		require.NoError(t, resp.Pages().Validate())
+
+		links := resp.Pages().GetLinks("/buildlogger/search/project?pattern=foo&limit=5")
+		assert.Contains(t, links, "pattern=foo")
+		nextLogOffset, err := getNextLogSearchOffset(links)
+		require.NoError(t, err)
+		assert.Equal(t, 1000, nextLogOffset)
+	})
+	t.Run("NoLinks", func(t *testing.T) {
+		nextLogOffset, err := getNextLogSearchOffset("")
+		require.NoError(t, err)
+		assert.Zero(t, nextLogOffset)
+	})
+}
+
+func (s *LogHandlerSuite) TestLogFailureSignaturesHandlerFound() {
+	rh := s.rh["signatures"].Factory()
+	rh.(*logFailureSignaturesHandler).opts = dbModel.TopLogFailureSignaturesOptions{
+		Project: "project",
+		TimeRange: dbModel.TimeRange{
+			StartAt: time.Now().Add(-48 * time.Hour),
+			EndAt:   time.Now(),
+		},
+		Limit: 10,
+	}
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	counts, ok := resp.Data().([]model.APILogFailureSignatureCount)
+	s.Require().True(ok)
+	s.Require().Len(counts, 2)
+	s.Equal("panic", *counts[0].Rule)
+	s.Equal(2, counts[0].Count)
+	s.Equal("ghi", *counts[0].LatestLogID)
+	s.Equal("task_id2", *counts[0].LatestTaskID)
+	s.Equal("panic: runtime error: index out of range", *counts[0].Snippet)
+	s.Equal("TestFoo", *counts[1].Signature)
+	s.Equal(1, counts[1].Count)
+
+	rh.(*logFailureSignaturesHandler).opts.TimeRange.StartAt = time.Now().Add(-12 * time.Hour)
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	counts, ok = resp.Data().([]model.APILogFailureSignatureCount)
+	s.Require().True(ok)
+	s.Require().Len(counts, 2)
+	s.Equal(1, counts[0].Count)
+	s.Equal(1, counts[1].Count)
+
+	rh.(*logFailureSignaturesHandler).opts.Limit = 1
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	counts, ok = resp.Data().([]model.APILogFailureSignatureCount)
+	s.Require().True(ok)
+	s.Len(counts, 1)
+}
+
+func (s *LogHandlerSuite) TestLogFailureSignaturesHandlerNoSignatures() {
+	rh := s.rh["signatures"].Factory()
+	rh.(*logFailureSignaturesHandler).opts = dbModel.TopLogFailureSignaturesOptions{
+		Project:   "DNE",
+		TimeRange: dbModel.TimeRange{EndAt: time.Now()},
+	}
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	counts, ok := resp.Data().([]model.APILogFailureSignatureCount)
+	s.Require().True(ok)
+	s.Empty(counts)
+}
+
+func (s *LogHandlerSuite) TestLogFailureSignaturesHandlerCtxErr() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rh := s.rh["signatures"].Factory()
+	rh.(*logFailureSignaturesHandler).opts = dbModel.TopLogFailureSignaturesOptions{
+		Project:   "project",
+		TimeRange: dbModel.TimeRange{EndAt: time.Now()},
+	}
+
+	resp := rh.Run(ctx)
+	s.Require().NotNil(resp)
+	s.NotEqual(http.StatusOK, resp.Status())
+}
+
+func (s *LogHandlerSuite) TestLogFailureSignaturesHandlerParse() {
+	ctx := context.Background()
+	rh := s.rh["signatures"].Factory()
+	req := gimlet.SetURLVars(&http.Request{Method: "GET"}, map[string]string{"project_id": "project"})
+	req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/signatures/project?variant=var&limit=5&start=2012-11-01T22:08:00%2B00:00&end=2013-11-01T22:08:00%2B00:00")
+	s.Require().NoError(rh.Parse(ctx, req))
+	s.Equal(dbModel.TopLogFailureSignaturesOptions{
+		Project: "project",
+		Variant: "var",
+		Limit:   5,
+		TimeRange: dbModel.TimeRange{
+			StartAt: time.Date(2012, time.November, 1, 22, 8, 0, 0, time.UTC),
+			EndAt:   time.Date(2013, time.November, 1, 22, 8, 0, 0, time.UTC),
+		},
+	}, rh.(*logFailureSignaturesHandler).opts)
+
+	rh = rh.Factory()
+	req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/signatures/project")
+	s.Require().NoError(rh.Parse(ctx, req))
+	s.Equal(20, rh.(*logFailureSignaturesHandler).opts.Limit)
+
+	for _, query := range []string{
+		"?limit=-1",
+		"?limit=1000",
+		"?limit=foo",
+		"?start=hello",
+		"?start=2013-11-01T22:08:00%2B00:00&end=2012-11-01T22:08:00%2B00:00",
+	} {
+		rh = rh.Factory()
+		req.URL, _ = url.Parse("http://cedar.mongodb.com/buildlogger/signatures/project" + query)
+		s.Error(rh.Parse(ctx, req), query)
+	}
+}
+
 func (s *LogHandlerSuite) TestParse() {
 	for _, test := range []struct {
 		urlString string
@@ -1124,6 +1770,9 @@ func (s *LogHandlerSuite) testParseValid(handler, urlString string, tags bool) {
 	urlString += "&print_time=true"
 	urlString += "&print_priority=true"
 	urlString += "&paginate=true"
+	urlString += "&min_priority=info&max_priority=70"
+	urlString += "&include=foo&exclude=ba%5Br%5D&max_lines=20"
+	urlString += "&field=component&field=msg.text&where=level:error&where=ctx.host:a:b"
 	req := &http.Request{Method: "GET"}
 	req.URL, _ = url.Parse(urlString)
 	expectedTr := dbModel.TimeRange{
@@ -1144,6 +1793,15 @@ func (s *LogHandlerSuite) testParseValid(handler, urlString string, tags bool) {
 	s.True(getLogPrintPriority(rh, handler))
 	s.True(getLogPaginate(rh, handler))
 	s.Zero(getLogLimit(rh, handler))
+	s.Equal(dbModel.LogFilterOptions{
+		MinPriority: level.Info,
+		MaxPriority: level.Error,
+		Include:     "foo",
+		Exclude:     "ba[r]",
+		MaxLines:    20,
+		Where:       map[string]string{"level": "error", "ctx.host": "a:b"},
+		Fields:      []string{"component", "msg.text"},
+	}, getLogFilter(rh, handler))
 
 	rh = rh.Factory() // need to reset this since we are reusing the handlers
 	urlString += "&limit=50"
@@ -1169,6 +1827,25 @@ func (s *LogHandlerSuite) testParseInvalid(handler, urlString string) {
 	req.URL, _ = url.Parse(urlString + invalidEnd)
 	err = rh.Parse(ctx, req)
 	s.Error(err)
+
+	for _, invalidFilter := range []string{
+		"?min_priority=hello",
+		"?max_priority=world",
+		"?min_priority=error&max_priority=info",
+		"?include=(",
+		"?exclude=[",
+		"?max_lines=hello",
+		"?max_lines=-1",
+		"?where=level",
+		"?where=:error",
+		"?where=level:error&where=level:info",
+		"?where=ctx..host:a",
+		"?field=msg.",
+	} {
+		req.URL, _ = url.Parse(urlString + invalidFilter)
+		err = rh.Parse(ctx, req)
+		s.Error(err, invalidFilter)
+	}
 }
 
 func (s *LogHandlerSuite) testParseDefaults(handler, urlString string, tags bool) {
@@ -1188,6 +1865,7 @@ func (s *LogHandlerSuite) testParseDefaults(handler, urlString string, tags bool
 	s.False(getLogPrintTime(rh, handler))
 	s.False(getLogPaginate(rh, handler))
 	s.Zero(getLogLimit(rh, handler))
+	s.Zero(getLogFilter(rh, handler))
 }
 
 func getLogTimeRange(rh gimlet.RouteHandler, handler string) (dbModel.TimeRange, dbModel.TimeRange) {
@@ -1290,6 +1968,23 @@ func getLogLimit(rh gimlet.RouteHandler, handler string) int {
 	}
 }
 
+func getLogFilter(rh gimlet.RouteHandler, handler string) dbModel.LogFilterOptions {
+	switch handler {
+	case "id":
+		return rh.(*logGetByIDHandler).opts.Filter
+	case "task_id":
+		return rh.(*logGetByTaskIDHandler).opts.Filter
+	case "group_task_id":
+		return rh.(*logGroupByTaskIDHandler).opts.Filter
+	case "test_name":
+		return rh.(*logGetByTestNameHandler).opts.Filter
+	case "group_test_name":
+		return rh.(*logGroupByTestNameHandler).opts.Filter
+	default:
+		return dbModel.LogFilterOptions{}
+	}
+}
+
 func TestNewBuildloggerResponder(t *testing.T) {
 	data := []byte("data")
 	last := time.Now().Add(-time.Hour)
@@ -9,10 +9,13 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/evergreen-ci/cedar"
+	dbModel "github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/grip"
@@ -654,3 +657,1273 @@ func (c *Client) RemovePerformanceResultById(ctx context.Context, id string) (st
 
 	return string(out), nil
 }
+
+///////////////////////////////////
+//
+// Performance Time Series
+
+// GetPerformanceTimeSeries returns the points of a performance time series,
+// sorted by order. Only mainline results are included unless specified
+// otherwise and, if a limit is given, only the latest points are returned.
+// The interval bounds are sent as days (YYYY-MM-DD) in UTC.
+func (c *Client) GetPerformanceTimeSeries(ctx context.Context, opts dbModel.PerformanceTimeSeriesOptions) ([]model.APIPerformanceTimeSeriesPoint, error) {
+	vals := url.Values{}
+	vals.Set(perfSeriesVariant, opts.ID.Variant)
+	vals.Set(perfSeriesTask, opts.ID.Task)
+	vals.Set(perfSeriesTest, opts.ID.Test)
+	vals.Set(perfSeriesMeasurement, opts.ID.Measurement)
+	for name, value := range opts.ID.Arguments {
+		vals.Add(perfSeriesArg, fmt.Sprintf("%s:%d", name, value))
+	}
+	if opts.IncludePatches {
+		vals.Set(perfTimeSeriesIncludePatches, trueString)
+	}
+	if opts.MinOrder > 0 {
+		vals.Set(perfTimeSeriesMinOrder, strconv.Itoa(opts.MinOrder))
+	}
+	if opts.MaxOrder > 0 {
+		vals.Set(perfTimeSeriesMaxOrder, strconv.Itoa(opts.MaxOrder))
+	}
+	if !opts.Interval.StartAt.IsZero() {
+		vals.Set(perfStartAt, opts.Interval.StartAt.UTC().Format(timeRangeFormatYearMonthDay))
+	}
+	if !opts.Interval.EndAt.IsZero() {
+		vals.Set(perfEndAt, opts.Interval.EndAt.UTC().Format(timeRangeFormatYearMonthDay))
+	}
+	if opts.Limit > 0 {
+		vals.Set(limit, strconv.Itoa(opts.Limit))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/perf/time_series/%s?%s", url.PathEscape(opts.ID.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	var out []model.APIPerformanceTimeSeriesPoint
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading performance time series")
+	}
+
+	return out, nil
+}
+
+///////////////////////////////////
+//
+// Performance Comparison
+
+// ComparePerformanceResults compares the rollups of the performance results
+// of either two versions or two tasks, pairing them by test name, arguments
+// and trial.
+func (c *Client) ComparePerformanceResults(ctx context.Context, opts dbModel.PerformanceComparisonOptions) (*model.APIPerformanceComparison, error) {
+	vals := url.Values{}
+	if opts.SignificanceLevel > 0 {
+		vals.Set(perfCompareSignificanceLevel, strconv.FormatFloat(opts.SignificanceLevel, 'f', -1, 64))
+	}
+
+	var path string
+	if opts.BaseTaskID != "" || opts.TaskID != "" {
+		path = fmt.Sprintf("/v1/perf/compare/task_id/%s/%s", url.PathEscape(opts.BaseTaskID), url.PathEscape(opts.TaskID))
+	} else {
+		path = fmt.Sprintf("/v1/perf/compare/version/%s/%s", url.PathEscape(opts.BaseVersion), url.PathEscape(opts.Version))
+	}
+
+	url := c.getURL(fmt.Sprintf("%s?%s", path, vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APIPerformanceComparison{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading performance comparison")
+	}
+
+	return out, nil
+}
+
+///////////////////////////////////
+//
+// Performance Change Points
+
+// GetPerformanceChangePoints returns the performance change points of a
+// project matching the given filter, most recently detected first.
+func (c *Client) GetPerformanceChangePoints(ctx context.Context, f dbModel.PerformanceChangePointsFilter) ([]model.APIPerformanceChangePoint, error) {
+	vals := url.Values{}
+	if f.Variant != "" {
+		vals.Set(perfSeriesVariant, f.Variant)
+	}
+	if f.Task != "" {
+		vals.Set(perfSeriesTask, f.Task)
+	}
+	if f.Test != "" {
+		vals.Set(perfSeriesTest, f.Test)
+	}
+	if f.Measurement != "" {
+		vals.Set(perfSeriesMeasurement, f.Measurement)
+	}
+	for _, status := range f.Statuses {
+		vals.Add(perfChangePointsStatus, string(status))
+	}
+	if f.Limit > 0 {
+		vals.Set(limit, strconv.Itoa(f.Limit))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/perf/change_points/%s?%s", url.PathEscape(f.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	var out []model.APIPerformanceChangePoint
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading performance change points")
+	}
+
+	return out, nil
+}
+
+// TriagePerformanceChangePoint sets the triage of the performance change
+// point with the given ID from the project and returns the updated change
+// point. The authenticated user is recorded as the user who triaged it.
+func (c *Client) TriagePerformanceChangePoint(ctx context.Context, project, id string, triage model.APIPerformanceChangePointTriage) (*model.APIPerformanceChangePoint, error) {
+	payload, err := json.Marshal(triage)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling change point triage")
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/perf/change_points/%s/%s/triage", url.PathEscape(project), url.PathEscape(id)))
+	req, err := c.makeRequest(ctx, http.MethodPut, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APIPerformanceChangePoint{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading performance change point")
+	}
+
+	return out, nil
+}
+
+///////////////////////////////////
+//
+// Buildlogger
+
+// GetLogMetadata returns the metadata of the buildlogger log with the given
+// ID.
+func (c *Client) GetLogMetadata(ctx context.Context, id string) (*model.APILog, error) {
+	url := c.getURL(fmt.Sprintf("/v1/buildlogger/%s/meta", url.PathEscape(id)))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APILog{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading log metadata")
+	}
+
+	return out, nil
+}
+
+// FollowLogOptions describes the options for following a buildlogger log.
+type FollowLogOptions struct {
+	ID            string
+	StartAt       time.Time
+	PrintTime     bool
+	PrintPriority bool
+	Filter        dbModel.LogFilterOptions
+}
+
+const (
+	minLogFollowBackoff = time.Second
+	maxLogFollowBackoff = 30 * time.Second
+)
+
+// FollowLog writes the lines of the buildlogger log with the given ID to w as
+// they are appended, blocking until the log is completed, the filter's
+// MaxLines are written, or the context errors. Since the service limits how
+// long a single request may follow a log, FollowLog reconnects as needed,
+// resuming from the cursor sent by the service and backing off while no new
+// lines are appended.
+func (c *Client) FollowLog(ctx context.Context, opts FollowLogOptions, w io.Writer) error {
+	var cursor string
+	backoff := minLogFollowBackoff
+	for {
+		// Check whether the log is complete before following it, so
+		// that the final request returns every remaining line.
+		meta, err := c.GetLogMetadata(ctx, opts.ID)
+		if err != nil {
+			return errors.Wrapf(err, "getting metadata for log '%s'", opts.ID)
+		}
+		completed := !time.Time(meta.CompletedAt).IsZero()
+
+		var lines int
+		cursor, lines, err = c.followLog(ctx, opts, cursor, w)
+		if err != nil {
+			return errors.Wrapf(err, "following log '%s'", opts.ID)
+		}
+		if completed {
+			return nil
+		}
+		if opts.Filter.MaxLines > 0 {
+			opts.Filter.MaxLines -= lines
+			if opts.Filter.MaxLines <= 0 {
+				return nil
+			}
+		}
+
+		if lines > 0 {
+			backoff = minLogFollowBackoff
+			continue
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(ctx.Err(), "following log '%s'", opts.ID)
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxLogFollowBackoff {
+			backoff = maxLogFollowBackoff
+		}
+	}
+}
+
+// followLog writes the lines of the log after the given cursor to w and
+// returns the cursor with which to resume following the log along with the
+// number of lines written.
+func (c *Client) followLog(ctx context.Context, opts FollowLogOptions, cursor string, w io.Writer) (string, int, error) {
+	vals := url.Values{}
+	vals.Set(logFollow, trueString)
+	if cursor != "" {
+		vals.Set(logFollowCursor, cursor)
+	}
+	if !opts.StartAt.IsZero() {
+		vals.Set(logStartAt, opts.StartAt.Format(time.RFC3339Nano))
+	}
+	if opts.PrintTime {
+		vals.Set(printTime, trueString)
+	}
+	if opts.PrintPriority {
+		vals.Set(printPriority, trueString)
+	}
+	if opts.Filter.MinPriority > 0 {
+		vals.Set(logMinPriority, strconv.Itoa(int(opts.Filter.MinPriority)))
+	}
+	if opts.Filter.MaxPriority > 0 {
+		vals.Set(logMaxPriority, strconv.Itoa(int(opts.Filter.MaxPriority)))
+	}
+	if opts.Filter.Include != "" {
+		vals.Set(logInclude, opts.Filter.Include)
+	}
+	if opts.Filter.Exclude != "" {
+		vals.Set(logExclude, opts.Filter.Exclude)
+	}
+	if opts.Filter.MaxLines > 0 {
+		vals.Set(logMaxLines, strconv.Itoa(opts.Filter.MaxLines))
+	}
+	for field, value := range opts.Filter.Where {
+		vals.Add(logWhere, field+":"+value)
+	}
+	for _, field := range opts.Filter.Fields {
+		vals.Add(logField, field)
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/buildlogger/%s?%s", url.PathEscape(opts.ID), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return cursor, 0, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return cursor, 0, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return cursor, 0, errors.Wrap(err, "parsing error message")
+		}
+
+		return cursor, 0, srverr
+	}
+
+	if _, err = io.Copy(w, resp.Body); err != nil {
+		return cursor, 0, errors.Wrap(err, "reading log lines")
+	}
+
+	// The trailers are only available once the body is read.
+	lines, err := strconv.Atoi(resp.Trailer.Get(LogFollowLinesTrailer))
+	if err != nil {
+		return cursor, 0, errors.Wrap(err, "parsing log follow lines")
+	}
+
+	return resp.Trailer.Get(LogFollowCursorTrailer), lines, nil
+}
+
+// DownloadLogArchiveOptions describes the options for downloading the archive
+// of a task's buildlogger logs.
+type DownloadLogArchiveOptions struct {
+	TaskID string
+	// Execution is the execution of the task. If LatestExecution is set,
+	// the latest execution is used instead.
+	Execution       int
+	LatestExecution bool
+	ProcessName     string
+	Tags            []string
+	PrintTime       bool
+	PrintPriority   bool
+}
+
+// DownloadLogArchive writes a zip archive of the buildlogger logs of the given
+// task to w. The archive contains one file per log along with a JSON
+// manifest of their metadata.
+func (c *Client) DownloadLogArchive(ctx context.Context, opts DownloadLogArchiveOptions, w io.Writer) error {
+	vals := url.Values{}
+	if !opts.LatestExecution {
+		vals.Set(execution, strconv.Itoa(opts.Execution))
+	}
+	if opts.ProcessName != "" {
+		vals.Set(procName, opts.ProcessName)
+	}
+	for _, tag := range opts.Tags {
+		vals.Add(tags, tag)
+	}
+	if opts.PrintTime {
+		vals.Set(printTime, trueString)
+	}
+	if opts.PrintPriority {
+		vals.Set(printPriority, trueString)
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/buildlogger/task_id/%s/archive?%s", url.PathEscape(opts.TaskID), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return errors.Wrap(err, "parsing error message")
+		}
+
+		return srverr
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return errors.Wrap(err, "writing log archive")
+}
+
+// GetTestResultLogOptions describes the options for downloading the
+// buildlogger log lines that belong to a single test result.
+type GetTestResultLogOptions struct {
+	TaskID   string
+	TestName string
+	// Execution is the execution of the task. If LatestExecution is set,
+	// the latest execution is used instead.
+	Execution       int
+	LatestExecution bool
+	PrintTime       bool
+	PrintPriority   bool
+}
+
+// GetTestResultLog writes the buildlogger log lines that belong to the given
+// test result to w.
+func (c *Client) GetTestResultLog(ctx context.Context, opts GetTestResultLogOptions, w io.Writer) error {
+	vals := url.Values{}
+	if !opts.LatestExecution {
+		vals.Set(execution, strconv.Itoa(opts.Execution))
+	}
+	if opts.PrintTime {
+		vals.Set(printTime, trueString)
+	}
+	if opts.PrintPriority {
+		vals.Set(printPriority, trueString)
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/buildlogger/test_result/%s/%s?%s", url.PathEscape(opts.TaskID), url.PathEscape(opts.TestName), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return errors.Wrap(err, "parsing error message")
+		}
+
+		return srverr
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return errors.Wrap(err, "writing test result log")
+}
+
+// SearchLogs returns the buildlogger log lines matching the given search
+// options along with their surrounding context lines. If the search stopped
+// before scanning every matching log, the log offset to continue the search
+// from is also returned, otherwise the log offset is 0.
+func (c *Client) SearchLogs(ctx context.Context, opts dbModel.LogSearchOptions) ([]model.APILogSearchResult, int, error) {
+	vals := url.Values{}
+	vals.Set(logSearchPattern, opts.Pattern)
+	if opts.Regex {
+		vals.Set(logSearchRegex, trueString)
+	}
+	if opts.CaseInsensitive {
+		vals.Set(logSearchCaseInsensitive, trueString)
+	}
+	if opts.Context > 0 {
+		vals.Set(logSearchContext, strconv.Itoa(opts.Context))
+	}
+	if opts.Limit > 0 {
+		vals.Set(limit, strconv.Itoa(opts.Limit))
+	}
+	if opts.LogOffset > 0 {
+		vals.Set(logSearchLogOffset, strconv.Itoa(opts.LogOffset))
+	}
+	if opts.Info.Version != "" {
+		vals.Set(logSearchVersion, opts.Info.Version)
+	}
+	if opts.Info.Variant != "" {
+		vals.Set(logSearchVariant, opts.Info.Variant)
+	}
+	if opts.Info.TaskName != "" {
+		vals.Set(logSearchTaskName, opts.Info.TaskName)
+	}
+	if opts.Info.TaskID != "" {
+		vals.Set(logSearchTaskID, opts.Info.TaskID)
+	}
+	if !opts.TimeRange.StartAt.IsZero() {
+		vals.Set(logStartAt, opts.TimeRange.StartAt.Format(time.RFC3339Nano))
+	}
+	if !opts.TimeRange.EndAt.IsZero() {
+		vals.Set(logEndAt, opts.TimeRange.EndAt.Format(time.RFC3339Nano))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/buildlogger/search/%s?%s", url.PathEscape(opts.Info.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, 0, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, 0, srverr
+	}
+
+	out := []model.APILogSearchResult{}
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, 0, errors.Wrap(err, "reading log search results")
+	}
+	nextLogOffset, err := getNextLogSearchOffset(resp.Header.Get("Link"))
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "reading next log search page")
+	}
+
+	return out, nextLogOffset, nil
+}
+
+// getNextLogSearchOffset returns the log offset of the next page link in the
+// given Link header, or 0 if there is no next page.
+func getNextLogSearchOffset(header string) (int, error) {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(link), ";", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) != `rel="next"` {
+			continue
+		}
+
+		next, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+		if err != nil {
+			return 0, errors.Wrap(err, "parsing next page URL")
+		}
+		offset, err := strconv.Atoi(next.Query().Get(logSearchLogOffset))
+		if err != nil {
+			return 0, errors.Wrap(err, "parsing next log offset")
+		}
+
+		return offset, nil
+	}
+
+	return 0, nil
+}
+
+// GetTopLogFailureSignatures returns the failure signatures found in the most
+// buildlogger logs of a project.
+func (c *Client) GetTopLogFailureSignatures(ctx context.Context, opts dbModel.TopLogFailureSignaturesOptions) ([]model.APILogFailureSignatureCount, error) {
+	vals := url.Values{}
+	if opts.Variant != "" {
+		vals.Set(logSearchVariant, opts.Variant)
+	}
+	if opts.Limit > 0 {
+		vals.Set(limit, strconv.Itoa(opts.Limit))
+	}
+	if !opts.TimeRange.StartAt.IsZero() {
+		vals.Set(logStartAt, opts.TimeRange.StartAt.Format(time.RFC3339Nano))
+	}
+	if !opts.TimeRange.EndAt.IsZero() {
+		vals.Set(logEndAt, opts.TimeRange.EndAt.Format(time.RFC3339Nano))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/buildlogger/signatures/%s?%s", url.PathEscape(opts.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := []model.APILogFailureSignatureCount{}
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading failure signature counts")
+	}
+
+	return out, nil
+}
+
+// GetFlakyTestsOptions specify the flaky tests to fetch from the Cedar
+// service. Requesters are API requester values, e.g. "mainline" or "patch",
+// and dates are rounded down to the UTC day. Zero values use the service's
+// defaults.
+type GetFlakyTestsOptions struct {
+	Project    string
+	Requesters []string
+	Variants   []string
+	Tasks      []string
+	AfterDate  time.Time
+	BeforeDate time.Time
+	Limit      int
+}
+
+// GetFlakyTests returns the flaky tests of a project ranked by flake score,
+// along with their daily trend.
+func (c *Client) GetFlakyTests(ctx context.Context, opts GetFlakyTestsOptions) ([]model.APIFlakyTest, error) {
+	vals := url.Values{}
+	if len(opts.Requesters) > 0 {
+		vals.Set("requesters", strings.Join(opts.Requesters, ","))
+	}
+	if len(opts.Variants) > 0 {
+		vals.Set("variants", strings.Join(opts.Variants, ","))
+	}
+	if len(opts.Tasks) > 0 {
+		vals.Set("tasks", strings.Join(opts.Tasks, ","))
+	}
+	if !opts.AfterDate.IsZero() {
+		vals.Set("after_date", opts.AfterDate.UTC().Format(htdAPIDateFormat))
+	}
+	if !opts.BeforeDate.IsZero() {
+		vals.Set("before_date", opts.BeforeDate.UTC().Format(htdAPIDateFormat))
+	}
+	if opts.Limit > 0 {
+		vals.Set(limit, strconv.Itoa(opts.Limit))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/flaky_tests/%s?%s", url.PathEscape(opts.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := []model.APIFlakyTest{}
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading flaky tests")
+	}
+
+	return out, nil
+}
+
+// GetTestDurationSlowdownsOptions specify the test duration slowdowns to fetch
+// from the Cedar service. Dates are rounded down to the UTC day. Zero values
+// use the service's defaults.
+type GetTestDurationSlowdownsOptions struct {
+	Project    string
+	Variants   []string
+	Tasks      []string
+	AfterDate  time.Time
+	BeforeDate time.Time
+	Limit      int
+}
+
+// GetTestDurationSlowdowns returns the test duration slowdowns detected for a
+// project, most recent first.
+func (c *Client) GetTestDurationSlowdowns(ctx context.Context, opts GetTestDurationSlowdownsOptions) ([]model.APITestDurationSlowdown, error) {
+	vals := url.Values{}
+	if len(opts.Variants) > 0 {
+		vals.Set("variants", strings.Join(opts.Variants, ","))
+	}
+	if len(opts.Tasks) > 0 {
+		vals.Set("tasks", strings.Join(opts.Tasks, ","))
+	}
+	if !opts.AfterDate.IsZero() {
+		vals.Set("after_date", opts.AfterDate.UTC().Format(htdAPIDateFormat))
+	}
+	if !opts.BeforeDate.IsZero() {
+		vals.Set("before_date", opts.BeforeDate.UTC().Format(htdAPIDateFormat))
+	}
+	if opts.Limit > 0 {
+		vals.Set(limit, strconv.Itoa(opts.Limit))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/test_duration_slowdowns/%s?%s", url.PathEscape(opts.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := []model.APITestDurationSlowdown{}
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading test duration slowdowns")
+	}
+
+	return out, nil
+}
+
+// UploadTestResultsOptions specify the test results report to upload to the
+// Cedar service and the task execution it belongs to.
+type UploadTestResultsOptions struct {
+	Info   dbModel.TestResultsInfo
+	Format dbModel.TestResultsFormat
+	Report io.Reader
+}
+
+// UploadTestResults uploads a test results report in the given format,
+// creating a new test results record for the task execution.
+func (c *Client) UploadTestResults(ctx context.Context, opts UploadTestResultsOptions) (*model.APITestResultsUpload, error) {
+	vals := url.Values{}
+	vals.Set("format", string(opts.Format))
+	vals.Set("project", opts.Info.Project)
+	vals.Set("version", opts.Info.Version)
+	vals.Set("variant", opts.Info.Variant)
+	vals.Set("task_name", opts.Info.TaskName)
+	vals.Set("request_type", opts.Info.RequestType)
+	vals.Set("execution", strconv.Itoa(opts.Info.Execution))
+	if opts.Info.DisplayTaskName != "" {
+		vals.Set("display_task_name", opts.Info.DisplayTaskName)
+	}
+	if opts.Info.DisplayTaskID != "" {
+		vals.Set("display_task_id", opts.Info.DisplayTaskID)
+	}
+	if opts.Info.Mainline {
+		vals.Set("mainline", "true")
+	}
+	if opts.Info.HistoricalDataDisabled {
+		vals.Set("historical_data_disabled", "true")
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/test_results/task_id/%s/upload?%s", url.PathEscape(opts.Info.TaskID), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodPost, url, opts.Report)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APITestResultsUpload{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading test results upload")
+	}
+
+	return out, nil
+}
+
+// SearchTestResults returns the test results matching the given search options
+// across the tasks of a project, along with their task information. The dates
+// are rounded down to the UTC day.
+func (c *Client) SearchTestResults(ctx context.Context, opts dbModel.SearchTestResultsOptions) (*model.APITestResultsSearch, error) {
+	vals := url.Values{}
+	if opts.Version != "" {
+		vals.Set(testResultsSearchVersion, opts.Version)
+	}
+	if opts.Variant != "" {
+		vals.Set(testResultsSearchVariant, opts.Variant)
+	}
+	if opts.TaskName != "" {
+		vals.Set(testResultsSearchTaskName, opts.TaskName)
+	}
+	if opts.TestName != "" {
+		vals.Set(testResultsTestName, opts.TestName)
+	}
+	for _, status := range opts.Statuses {
+		vals.Add(testResultsStatus, status)
+	}
+	if !opts.AfterDate.IsZero() {
+		vals.Set(testResultsSearchAfterDate, opts.AfterDate.UTC().Format(htdAPIDateFormat))
+	}
+	if !opts.BeforeDate.IsZero() {
+		vals.Set(testResultsSearchBeforeDate, opts.BeforeDate.UTC().Format(htdAPIDateFormat))
+	}
+	if opts.Limit > 0 {
+		vals.Set(testResultsLimit, strconv.Itoa(opts.Limit))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/test_results/search/%s?%s", url.PathEscape(opts.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APITestResultsSearch{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading test results search")
+	}
+
+	return out, nil
+}
+
+// GetFailedTestResultsSampleWithOwners returns the failed tests sample of the
+// given task along with the owner and team of each test.
+func (c *Client) GetFailedTestResultsSampleWithOwners(ctx context.Context, taskID string, displayTask bool) ([]model.APIFailedTestSample, error) {
+	vals := url.Values{}
+	vals.Set(testResultsWithOwners, trueString)
+	if displayTask {
+		vals.Set(isDisplayTask, trueString)
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/test_results/task_id/%s/failed_sample?%s", url.PathEscape(taskID), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	var out []model.APIFailedTestSample
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading failed test results sample")
+	}
+
+	return out, nil
+}
+
+// GetTestFailuresByTeam returns the failed tests of the given project and
+// version aggregated by the team owning them.
+func (c *Client) GetTestFailuresByTeam(ctx context.Context, project, version string) ([]model.APITeamTestFailures, error) {
+	url := c.getURL(fmt.Sprintf("/v1/test_results/failures_by_team/%s/%s", url.PathEscape(project), url.PathEscape(version)))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	var out []model.APITeamTestFailures
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading test failures by team")
+	}
+
+	return out, nil
+}
+
+// GetAggregatedTestResultsStats returns the test results stats of the tasks
+// of a project matching the given filter, summed by the filter's task fields.
+func (c *Client) GetAggregatedTestResultsStats(ctx context.Context, f dbModel.TestResultsStatsFilter) ([]model.APIAggregatedTestResultsStats, error) {
+	vals := url.Values{}
+	if f.Version != "" {
+		vals.Set("version", f.Version)
+	}
+	if f.Variant != "" {
+		vals.Set("variant", f.Variant)
+	}
+	if f.TaskName != "" {
+		vals.Set("task_name", f.TaskName)
+	}
+	if !f.AfterDate.IsZero() {
+		vals.Set("after_date", f.AfterDate.UTC().Format(htdAPIDateFormat))
+	}
+	if !f.BeforeDate.IsZero() {
+		vals.Set("before_date", f.BeforeDate.UTC().Format(htdAPIDateFormat))
+	}
+	if f.MainlineOnly {
+		vals.Set("mainline", trueString)
+	}
+	for _, groupBy := range f.GroupBy {
+		vals.Add("group_by", string(groupBy))
+	}
+	if f.Limit > 0 {
+		vals.Set(limit, strconv.Itoa(f.Limit))
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/test_results/stats/%s?%s", url.PathEscape(f.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	var out []model.APIAggregatedTestResultsStats
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading aggregated test results stats")
+	}
+
+	return out, nil
+}
+
+///////////////////////////////////
+//
+// Test Ownership
+
+// GetTestOwnerships returns the test ownerships of the given project.
+func (c *Client) GetTestOwnerships(ctx context.Context, project string) ([]model.APITestOwnership, error) {
+	url := c.getURL(fmt.Sprintf("/v1/admin/test_owners/%s", url.PathEscape(project)))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	var out []model.APITestOwnership
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading test ownerships")
+	}
+
+	return out, nil
+}
+
+// SaveTestOwnership creates or replaces the owner and team of the tests of the
+// given project matching the test name pattern.
+func (c *Client) SaveTestOwnership(ctx context.Context, project, testNamePattern, owner, team string) (*model.APITestOwnership, error) {
+	payload, err := json.Marshal(model.APITestOwnership{
+		Project:         &project,
+		TestNamePattern: &testNamePattern,
+		Owner:           &owner,
+		Team:            &team,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling test ownership")
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/admin/test_owners/%s", url.PathEscape(project)))
+	req, err := c.makeRequest(ctx, http.MethodPut, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APITestOwnership{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading test ownership")
+	}
+
+	return out, nil
+}
+
+// RemoveTestOwnership removes the test ownership with the given ID from the
+// given project.
+func (c *Client) RemoveTestOwnership(ctx context.Context, project, id string) error {
+	url := c.getURL(fmt.Sprintf("/v1/admin/test_owners/%s/%s", url.PathEscape(project), url.PathEscape(id)))
+	req, err := c.makeRequest(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return errors.Wrap(err, "parsing error message")
+		}
+
+		return srverr
+	}
+
+	return nil
+}
+
+///////////////////////////////////
+//
+// Test Annotations
+
+// GetTestAnnotations returns the test annotations of a project matching the
+// given filter.
+func (c *Client) GetTestAnnotations(ctx context.Context, f dbModel.TestAnnotationsFilter) ([]model.APITestAnnotation, error) {
+	vals := url.Values{}
+	if f.Variant != "" {
+		vals.Set(testAnnotationsVariant, f.Variant)
+	}
+	if f.TaskName != "" {
+		vals.Set(testAnnotationsTaskName, f.TaskName)
+	}
+	if f.TestName != "" {
+		vals.Set(testAnnotationsTestName, f.TestName)
+	}
+	if f.QuarantinedOnly {
+		vals.Set(testAnnotationsQuarantined, trueString)
+	}
+	if f.IncludeExpired {
+		vals.Set(testAnnotationsIncludeExpired, trueString)
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/test_annotations/%s?%s", url.PathEscape(f.Project), vals.Encode()))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	var out []model.APITestAnnotation
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "reading test annotations")
+	}
+
+	return out, nil
+}
+
+// GetTestAnnotation returns the test annotation with the given ID from the
+// given project.
+func (c *Client) GetTestAnnotation(ctx context.Context, project, id string) (*model.APITestAnnotation, error) {
+	url := c.getURL(fmt.Sprintf("/v1/test_annotations/%s/%s", url.PathEscape(project), url.PathEscape(id)))
+	req, err := c.makeRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APITestAnnotation{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading test annotation")
+	}
+
+	return out, nil
+}
+
+// SaveTestAnnotation creates or replaces the annotation of a test. The
+// authenticated user is recorded as the last user to update the annotation.
+func (c *Client) SaveTestAnnotation(ctx context.Context, annotation model.APITestAnnotation) (*model.APITestAnnotation, error) {
+	project := ""
+	if annotation.Project != nil {
+		project = *annotation.Project
+	}
+	payload, err := json.Marshal(annotation)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling test annotation")
+	}
+
+	url := c.getURL(fmt.Sprintf("/v1/test_annotations/%s", url.PathEscape(project)))
+	req, err := c.makeRequest(ctx, http.MethodPut, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return nil, errors.Wrap(err, "parsing error message")
+		}
+
+		return nil, srverr
+	}
+
+	out := &model.APITestAnnotation{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "reading test annotation")
+	}
+
+	return out, nil
+}
+
+// RemoveTestAnnotation removes the test annotation with the given ID from the
+// given project.
+func (c *Client) RemoveTestAnnotation(ctx context.Context, project, id string) error {
+	url := c.getURL(fmt.Sprintf("/v1/test_annotations/%s/%s", url.PathEscape(project), url.PathEscape(id)))
+	req, err := c.makeRequest(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "making request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		srverr := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &srverr); err != nil {
+			return errors.Wrap(err, "parsing error message")
+		}
+
+		return srverr
+	}
+
+	return nil
+}
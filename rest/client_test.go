@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -13,6 +14,8 @@ import (
 	"github.com/evergreen-ci/cedar/model"
 	"github.com/mongodb/amboy/queue"
 	"github.com/mongodb/grip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -281,3 +284,41 @@ func (s *ClientSuite) TestURLGenerationWithEmptyPrefix() {
 // Client/Service Interaction: Public Methods
 //
 ////////////////////////////////////////////////////////////////////////
+
+func TestClientFollowLog(t *testing.T) {
+	var (
+		requests int
+		cursors  []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/v1/buildlogger/abc/meta", func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte(`{"id":"abc"}`))
+	})
+	mux.HandleFunc("/rest/v1/buildlogger/abc", func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		cursors = append(cursors, r.URL.Query().Get(logFollowCursor))
+		rw.Header().Set("Trailer", LogFollowCursorTrailer+", "+LogFollowLinesTrailer)
+		_, _ = rw.Write([]byte("line\nline\n"))
+		rw.Header().Set(LogFollowCursorTrailer, "chunk"+strconv.Itoa(requests))
+		rw.Header().Set(LogFollowLinesTrailer, "2")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	portStart := strings.LastIndex(server.URL, ":")
+	port, err := strconv.Atoi(server.URL[portStart+1:])
+	require.NoError(t, err)
+	client, err := NewClient(ClientOptions{Host: server.URL[:portStart], Port: port, Prefix: "rest"})
+	require.NoError(t, err)
+
+	// The log is never completed, so following stops once the max lines
+	// are written.
+	var buf bytes.Buffer
+	require.NoError(t, client.FollowLog(context.Background(), FollowLogOptions{
+		ID:     "abc",
+		Filter: model.LogFilterOptions{MaxLines: 3},
+	}, &buf))
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, []string{"", "chunk1"}, cursors)
+	assert.Equal(t, "line\nline\nline\nline\n", buf.String())
+}
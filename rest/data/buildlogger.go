@@ -2,7 +2,9 @@ package data
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"sort"
@@ -12,10 +14,18 @@ import (
 	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/pail"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
+// logFollowTimeout is the maximum amount of time a single request follows a
+// log, which must stay under the REST service's write timeout of one minute.
+// Clients are expected to reconnect, resuming from the cursor of the previous
+// stream, until the log is complete.
+const logFollowTimeout = 45 * time.Second
+
 /////////////////////////////
 // DBConnector Implementation
 /////////////////////////////
@@ -50,6 +60,9 @@ func (dbc *DBConnector) FindLogByID(ctx context.Context, opts BuildloggerOptions
 	}
 
 	opts.Tail = 0
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -65,6 +78,37 @@ func (dbc *DBConnector) FindLogByID(ctx context.Context, opts BuildloggerOptions
 	return data, next, paginated, nil
 }
 
+func (dbc *DBConnector) FollowLogByID(ctx context.Context, opts BuildloggerOptions) (*LogFollowStream, error) {
+	log := dbModel.Log{ID: opts.ID}
+	log.Setup(dbc.env)
+	if err := log.Find(ctx); db.ResultsNotFound(err) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("log '%s' not found", opts.ID),
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding log '%s'", opts.ID).Error(),
+		}
+	}
+
+	log.Setup(dbc.env)
+	it, err := log.Follow(ctx, dbModel.LogFollowOptions{
+		StartAt:       opts.TimeRange.StartAt,
+		Timeout:       logFollowTimeout,
+		AfterChunkKey: opts.FollowCursor,
+	})
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "following log '%s'", opts.ID).Error(),
+		}
+	}
+
+	return followData(ctx, it, opts)
+}
+
 func (dbc *DBConnector) FindLogMetadataByID(ctx context.Context, id string) (*model.APILog, error) {
 	log := dbModel.Log{ID: id}
 	log.Setup(dbc.env)
@@ -130,6 +174,9 @@ func (dbc *DBConnector) FindLogsByTaskID(ctx context.Context, opts BuildloggerOp
 		}
 	}
 
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -182,6 +229,38 @@ func (dbc *DBConnector) FindLogMetadataByTaskID(ctx context.Context, opts Buildl
 	return apiLogs, nil
 }
 
+func (dbc *DBConnector) FindLogArchiveByTaskID(ctx context.Context, opts BuildloggerOptions) (io.ReadCloser, error) {
+	dbOpts := dbModel.LogFindOptions{
+		TimeRange: opts.TimeRange,
+		Info: dbModel.LogInfo{
+			TaskID:      opts.TaskID,
+			Execution:   opts.Execution,
+			ProcessName: opts.ProcessName,
+			Tags:        opts.Tags,
+		},
+		LatestExecution: opts.EmptyExecution,
+	}
+	logs := dbModel.Logs{}
+	logs.Setup(dbc.env)
+	if err := logs.Find(ctx, dbOpts); db.ResultsNotFound(err) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("logs with task ID '%s' not found", opts.TaskID),
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding logs with task ID '%s'", opts.TaskID).Error(),
+		}
+	}
+
+	for i := range logs.Logs {
+		logs.Logs[i].Setup(dbc.env)
+	}
+
+	return archiveLogs(ctx, logs.Logs, opts, nil)
+}
+
 func (dbc *DBConnector) FindLogsByTestName(ctx context.Context, opts BuildloggerOptions) ([]byte, time.Time, bool, error) {
 	var (
 		data      []byte
@@ -195,6 +274,9 @@ func (dbc *DBConnector) FindLogsByTestName(ctx context.Context, opts Buildlogger
 	}
 
 	opts.Tail = 0
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -280,6 +362,9 @@ func (dbc *DBConnector) FindGroupedLogs(ctx context.Context, opts BuildloggerOpt
 	it = dbModel.NewMergingIterator(its...)
 
 	opts.Tail = 0
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -295,6 +380,35 @@ func (dbc *DBConnector) FindGroupedLogs(ctx context.Context, opts BuildloggerOpt
 	return data, next, paginated, nil
 }
 
+func (dbc *DBConnector) FindTestResultLog(ctx context.Context, opts BuildloggerOptions) ([]byte, error) {
+	findOpts := dbModel.FindTestResultsOptions{TaskID: opts.TaskID}
+	if !opts.EmptyExecution {
+		findOpts.Execution = utility.ToIntPtr(opts.Execution)
+	}
+	results, _, err := dbModel.FindAndDownloadTestResults(ctx, dbc.env, dbModel.FindAndDownloadTestResultsOptions{Find: findOpts})
+	if db.ResultsNotFound(err) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("test results with task ID '%s' not found", opts.TaskID),
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding test results with task ID '%s'", opts.TaskID).Error(),
+		}
+	}
+
+	if err = setTestResultLogOptions(&opts, results); err != nil {
+		return nil, err
+	}
+	it, err := dbc.findLogsByTestName(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return readTestResultLog(ctx, it, opts)
+}
+
 func (dbc *DBConnector) findLogsByTestName(ctx context.Context, opts *BuildloggerOptions) (dbModel.LogIterator, error) {
 	dbOpts := dbModel.LogFindOptions{
 		TimeRange: opts.TimeRange,
@@ -336,6 +450,42 @@ func (dbc *DBConnector) findLogsByTestName(ctx context.Context, opts *Buildlogge
 	return it, nil
 }
 
+func (dbc *DBConnector) SearchLogs(ctx context.Context, opts dbModel.LogSearchOptions) ([]model.APILogSearchResult, int, error) {
+	results, nextLogOffset, err := dbModel.SearchLogs(ctx, dbc.env, opts)
+	if err != nil {
+		return nil, 0, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "searching logs in project '%s'", opts.Info.Project).Error(),
+		}
+	}
+
+	apiResults, err := importLogSearchResults(results)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return apiResults, nextLogOffset, nil
+}
+
+func (dbc *DBConnector) FindTopLogFailureSignatures(ctx context.Context, opts dbModel.TopLogFailureSignaturesOptions) ([]model.APILogFailureSignatureCount, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid top failure signatures options").Error(),
+		}
+	}
+
+	counts, err := dbModel.FindTopLogFailureSignatures(ctx, dbc.env, opts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding top failure signatures in project '%s'", opts.Project).Error(),
+		}
+	}
+
+	return importLogFailureSignatureCounts(counts)
+}
+
 ///////////////////////////////
 // MockConnector Implementation
 ///////////////////////////////
@@ -365,9 +515,12 @@ func (mc *MockConnector) FindLogByID(ctx context.Context, opts BuildloggerOption
 			Message:    errors.Wrap(err, "creating bucket").Error(),
 		}
 	}
-	it := dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, opts.TimeRange)
+	it := dbModel.NewStructuredLogIterator(dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, opts.TimeRange), log.Info.Format)
 
 	opts.Tail = 0
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -383,6 +536,44 @@ func (mc *MockConnector) FindLogByID(ctx context.Context, opts BuildloggerOption
 	return data, next, paginated, ctx.Err()
 }
 
+func (mc *MockConnector) FollowLogByID(ctx context.Context, opts BuildloggerOptions) (*LogFollowStream, error) {
+	log, ok := mc.CachedLogs[opts.ID]
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("log '%s' not found", opts.ID),
+		}
+	}
+
+	bucketOpts := pail.LocalOptions{
+		Path:   mc.Bucket,
+		Prefix: log.Artifact.Prefix,
+	}
+	bucket, err := pail.NewLocalBucket(bucketOpts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "creating bucket").Error(),
+		}
+	}
+	poll := func(_ context.Context) ([]dbModel.LogChunkInfo, bool, error) {
+		log, ok := mc.CachedLogs[opts.ID]
+		if !ok {
+			return nil, false, errors.Errorf("log '%s' not found", opts.ID)
+		}
+		return log.Artifact.Chunks, !log.CompletedAt.IsZero(), nil
+	}
+	it := dbModel.NewFollowingLogIterator(bucket, poll, dbModel.LogFollowOptions{
+		StartAt:       opts.TimeRange.StartAt,
+		PollInterval:  time.Millisecond,
+		Timeout:       logFollowTimeout,
+		AfterChunkKey: opts.FollowCursor,
+		Format:        log.Info.Format,
+	})
+
+	return followData(ctx, it, opts)
+}
+
 func (mc *MockConnector) FindLogMetadataByID(ctx context.Context, id string) (*model.APILog, error) {
 	log, ok := mc.CachedLogs[id]
 	if !ok {
@@ -450,11 +641,14 @@ func (mc *MockConnector) FindLogsByTaskID(ctx context.Context, opts BuildloggerO
 			}
 		}
 
-		its = append(its, dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, opts.TimeRange))
+		its = append(its, dbModel.NewStructuredLogIterator(dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, opts.TimeRange), log.Info.Format))
 	}
 	it := dbModel.NewMergingIterator(its...)
 
 	var err error
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -510,6 +704,61 @@ func (mc *MockConnector) FindLogMetadataByTaskID(ctx context.Context, opts Build
 	return apiLogs, ctx.Err()
 }
 
+func (mc *MockConnector) FindLogArchiveByTaskID(ctx context.Context, opts BuildloggerOptions) (io.ReadCloser, error) {
+	logs := []dbModel.Log{}
+	for _, log := range mc.CachedLogs {
+		if log.Info.TaskID == opts.TaskID {
+			logs = append(logs, log)
+		}
+	}
+	if len(logs) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("logs with task ID '%s' not found", opts.TaskID),
+		}
+	}
+
+	if opts.EmptyExecution {
+		opts.Execution = getMaxExecution(logs)
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].CreatedAt.After(logs[j].CreatedAt) })
+
+	filtered := []dbModel.Log{}
+	for _, log := range logs {
+		if opts.ProcessName != "" && opts.ProcessName != log.Info.ProcessName {
+			continue
+		}
+		if opts.Execution != log.Info.Execution {
+			continue
+		}
+		if !containsTags(opts.Tags, log.Info.Tags) {
+			continue
+		}
+		filtered = append(filtered, log)
+	}
+	if len(filtered) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("logs with task ID '%s' not found", opts.TaskID),
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return archiveLogs(ctx, filtered, opts, func(_ context.Context, log *dbModel.Log, timeRange dbModel.TimeRange) (dbModel.LogIterator, error) {
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{
+			Path:   mc.Bucket,
+			Prefix: log.Artifact.Prefix,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "creating bucket")
+		}
+
+		return dbModel.NewStructuredLogIterator(dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, timeRange), log.Info.Format), nil
+	})
+}
+
 func (mc *MockConnector) FindLogsByTestName(ctx context.Context, opts BuildloggerOptions) ([]byte, time.Time, bool, error) {
 	var (
 		data      []byte
@@ -523,6 +772,9 @@ func (mc *MockConnector) FindLogsByTestName(ctx context.Context, opts Buildlogge
 	}
 
 	opts.Tail = 0
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -613,6 +865,9 @@ func (mc *MockConnector) FindGroupedLogs(ctx context.Context, opts BuildloggerOp
 	it = dbModel.NewMergingIterator(its...)
 
 	opts.Tail = 0
+	if it, err = filterLogIterator(it, opts); err != nil {
+		return data, time.Time{}, paginated, err
+	}
 	data, paginated, err = paginateData(ctx, it, opts)
 	if err != nil {
 		return data, time.Time{}, paginated, gimlet.ErrorResponse{
@@ -628,6 +883,44 @@ func (mc *MockConnector) FindGroupedLogs(ctx context.Context, opts BuildloggerOp
 	return data, next, paginated, ctx.Err()
 }
 
+func (mc *MockConnector) FindTestResultLog(ctx context.Context, opts BuildloggerOptions) ([]byte, error) {
+	var results []dbModel.TestResult
+	execution := -1
+	for _, result := range mc.CachedTestResults[opts.TaskID] {
+		if !opts.EmptyExecution && result.Execution != opts.Execution {
+			continue
+		}
+		if result.Execution > execution {
+			execution = result.Execution
+			results = nil
+		}
+		if result.Execution == execution {
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("test results with task ID '%s' not found", opts.TaskID),
+		}
+	}
+
+	if err := setTestResultLogOptions(&opts, results); err != nil {
+		return nil, err
+	}
+	it, err := mc.findLogsByTestName(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readTestResultLog(ctx, it, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, ctx.Err()
+}
+
 func (mc *MockConnector) findLogsByTestName(ctx context.Context, opts *BuildloggerOptions) (dbModel.LogIterator, error) {
 	logs := []dbModel.Log{}
 	for _, log := range mc.CachedLogs {
@@ -678,12 +971,200 @@ func (mc *MockConnector) findLogsByTestName(ctx context.Context, opts *Buildlogg
 			}
 		}
 
-		its = append(its, dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, opts.TimeRange))
+		its = append(its, dbModel.NewStructuredLogIterator(dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, opts.TimeRange), log.Info.Format))
 	}
 
 	return dbModel.NewMergingIterator(its...), ctx.Err()
 }
 
+func (mc *MockConnector) SearchLogs(ctx context.Context, opts dbModel.LogSearchOptions) ([]model.APILogSearchResult, int, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, 0, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid log search options").Error(),
+		}
+	}
+
+	logs := []dbModel.Log{}
+	for _, log := range mc.CachedLogs {
+		if log.Info.Project != opts.Info.Project {
+			continue
+		}
+		if opts.Info.Version != "" && log.Info.Version != opts.Info.Version {
+			continue
+		}
+		if opts.Info.Variant != "" && log.Info.Variant != opts.Info.Variant {
+			continue
+		}
+		if opts.Info.TaskName != "" && log.Info.TaskName != opts.Info.TaskName {
+			continue
+		}
+		if opts.Info.TaskID != "" && log.Info.TaskID != opts.Info.TaskID {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].CreatedAt.Before(logs[j].CreatedAt) })
+	if opts.LogOffset >= len(logs) {
+		logs = nil
+	} else {
+		logs = logs[opts.LogOffset:]
+	}
+
+	results := []dbModel.LogSearchResult{}
+	for _, log := range logs {
+		if len(results) >= opts.Limit {
+			break
+		}
+
+		bucket, err := mc.getBucket(ctx, log.Artifact.Prefix)
+		if err != nil {
+			return nil, 0, err
+		}
+		logOpts := opts
+		logOpts.Limit = opts.Limit - len(results)
+		matches, err := dbModel.SearchLogIterator(ctx, dbModel.NewStructuredLogIterator(dbModel.NewBatchedLogIterator(bucket, log.Artifact.Chunks, 2, opts.TimeRange), log.Info.Format), logOpts)
+		if err != nil {
+			return nil, 0, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrapf(err, "searching log '%s'", log.ID).Error(),
+			}
+		}
+		for i := range matches {
+			matches[i].LogID = log.ID
+			matches[i].Info = log.Info
+		}
+		results = append(results, matches...)
+	}
+
+	apiResults, err := importLogSearchResults(results)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return apiResults, 0, ctx.Err()
+}
+
+func (mc *MockConnector) FindTopLogFailureSignatures(ctx context.Context, opts dbModel.TopLogFailureSignaturesOptions) ([]model.APILogFailureSignatureCount, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid top failure signatures options").Error(),
+		}
+	}
+
+	logs := []dbModel.Log{}
+	for _, log := range mc.CachedLogs {
+		if log.Info.Project != opts.Project {
+			continue
+		}
+		if opts.Variant != "" && log.Info.Variant != opts.Variant {
+			continue
+		}
+		if !opts.TimeRange.Check(log.CreatedAt) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].CreatedAt.After(logs[j].CreatedAt) })
+
+	counts := []dbModel.LogFailureSignatureCount{}
+	seen := map[[2]string]int{}
+	for _, log := range logs {
+		for _, sig := range log.FailureSignatures {
+			key := [2]string{sig.Rule, sig.Signature}
+			if i, ok := seen[key]; ok {
+				counts[i].Count++
+				continue
+			}
+			seen[key] = len(counts)
+			counts = append(counts, dbModel.LogFailureSignatureCount{
+				Rule:         sig.Rule,
+				Signature:    sig.Signature,
+				Count:        1,
+				LastSeen:     log.CreatedAt,
+				LatestLogID:  log.ID,
+				LatestTaskID: log.Info.TaskID,
+				Snippet:      sig.Snippet,
+			})
+		}
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].LastSeen.After(counts[j].LastSeen)
+	})
+	if len(counts) > opts.Limit {
+		counts = counts[:opts.Limit]
+	}
+
+	apiCounts, err := importLogFailureSignatureCounts(counts)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiCounts, ctx.Err()
+}
+
+func importLogFailureSignatureCounts(counts []dbModel.LogFailureSignatureCount) ([]model.APILogFailureSignatureCount, error) {
+	apiCounts := make([]model.APILogFailureSignatureCount, len(counts))
+	for i, count := range counts {
+		if err := apiCounts[i].Import(count); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for failure signature count").Error(),
+			}
+		}
+	}
+
+	return apiCounts, nil
+}
+
+func importLogSearchResults(results []dbModel.LogSearchResult) ([]model.APILogSearchResult, error) {
+	apiResults := make([]model.APILogSearchResult, len(results))
+	for i, result := range results {
+		if err := apiResults[i].Import(result); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for log search result").Error(),
+			}
+		}
+	}
+
+	return apiResults, nil
+}
+
+// setTestResultLogOptions sets the options for reading the region of the
+// buildlogger logs that belongs to the test result with the test name set in
+// the given options.
+func setTestResultLogOptions(opts *BuildloggerOptions, results []dbModel.TestResult) error {
+	region, ok := dbModel.GetTestResultLogRegion(opts.TestName, results)
+	if !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("test result with task ID '%s' and test name '%s' not found", opts.TaskID, opts.TestName),
+		}
+	}
+
+	opts.TestName = region.LogTestName
+	opts.EmptyTestName = false
+	opts.Execution = region.Execution
+	opts.EmptyExecution = false
+	opts.TimeRange = region.TimeRange
+	if region.ByTime {
+		opts.Skip = 0
+		opts.Limit = 0
+	} else {
+		opts.Skip = region.StartLine
+		// Skipped lines count towards the limit, so the limit is the
+		// end line. An end line of 0 reads to the end of the logs.
+		opts.Limit = region.EndLine
+	}
+
+	return nil
+}
+
 func getMaxExecution(logs []dbModel.Log) int {
 	max := 0
 	for _, log := range logs {
@@ -695,9 +1176,131 @@ func getMaxExecution(logs []dbModel.Log) int {
 	return max
 }
 
+func filterLogIterator(it dbModel.LogIterator, opts BuildloggerOptions) (dbModel.LogIterator, error) {
+	filtered, err := dbModel.NewFilteredLogIterator(it, opts.Filter)
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(err)
+		catcher.Add(it.Close())
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    catcher.Resolve().Error(),
+		}
+	}
+
+	return filtered, nil
+}
+
+func readTestResultLog(ctx context.Context, it dbModel.LogIterator, opts BuildloggerOptions) ([]byte, error) {
+	it, err := filterLogIterator(it, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := paginateData(ctx, it, opts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "reading test result log").Error(),
+		}
+	}
+
+	return data, nil
+}
+
+// LogFollowStream is a reader that streams the lines of a followed log.
+type LogFollowStream struct {
+	io.Reader
+	following dbModel.FollowingLogIterator
+	counting  *countingLogIterator
+	limit     int
+}
+
+// Cursor returns the key of the last chunk of the followed log whose lines
+// were all consumed by the stream. Once the stream is read to completion, it
+// is the cursor with which a following request resumes the log without
+// downloading the chunks already streamed.
+func (s *LogFollowStream) Cursor() string { return s.following.ChunkKey() }
+
+// Lines returns the number of lines consumed by the stream so far.
+func (s *LogFollowStream) Lines() int {
+	if s.limit > 0 && s.counting.count > s.limit {
+		return s.limit
+	}
+
+	return s.counting.count
+}
+
+// countingLogIterator is a LogIterator that counts the lines it iterates.
+type countingLogIterator struct {
+	dbModel.LogIterator
+	count int
+}
+
+func (it *countingLogIterator) Next(ctx context.Context) bool {
+	if !it.LogIterator.Next(ctx) {
+		return false
+	}
+
+	it.count++
+	return true
+}
+
+func followData(ctx context.Context, it dbModel.FollowingLogIterator, opts BuildloggerOptions) (*LogFollowStream, error) {
+	filtered, err := filterLogIterator(it, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	counting := &countingLogIterator{LogIterator: filtered}
+	return &LogFollowStream{
+		Reader: dbModel.NewLogIteratorReader(ctx, counting, dbModel.LogIteratorReaderOptions{
+			Limit:         opts.Limit,
+			PrintTime:     opts.PrintTime,
+			PrintPriority: opts.PrintPriority,
+			LineBuffered:  true,
+		}),
+		following: it,
+		counting:  counting,
+		limit:     opts.Limit,
+	}, nil
+}
+
+// archiveLogs returns a reader that streams a zip archive of the given logs
+// along with a JSON manifest of their metadata.
+func archiveLogs(ctx context.Context, logs []dbModel.Log, opts BuildloggerOptions, download func(context.Context, *dbModel.Log, dbModel.TimeRange) (dbModel.LogIterator, error)) (io.ReadCloser, error) {
+	names := dbModel.LogArchiveFileNames(logs)
+	manifest := make([]model.APILogArchiveEntry, len(logs))
+	for i, log := range logs {
+		manifest[i].FileName = utility.ToStringPtr(names[i])
+		if err := manifest[i].Log.Import(log); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrapf(err, "corrupt data for logs with task ID '%s'", opts.TaskID).Error(),
+			}
+		}
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "marshalling log archive manifest").Error(),
+		}
+	}
+
+	return dbModel.NewLogArchiveReader(ctx, logs, dbModel.LogArchiveOptions{
+		Manifest:      data,
+		TimeRange:     opts.TimeRange,
+		PrintTime:     opts.PrintTime,
+		PrintPriority: opts.PrintPriority,
+		Download:      download,
+	}), nil
+}
+
 func paginateData(ctx context.Context, it dbModel.LogIterator, opts BuildloggerOptions) ([]byte, bool, error) {
 	readerOpts := dbModel.LogIteratorReaderOptions{
 		Limit:         opts.Limit,
+		Skip:          opts.Skip,
 		TailN:         opts.Tail,
 		PrintTime:     opts.PrintTime,
 		PrintPriority: opts.PrintPriority,
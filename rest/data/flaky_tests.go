@@ -0,0 +1,68 @@
+package data
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// GetFlakyTests queries the service backend to retrieve the ranked flaky
+// tests that match the given filter.
+func (dbc *DBConnector) GetFlakyTests(ctx context.Context, f dbModel.FlakyTestsFilter) ([]model.APIFlakyTest, error) {
+	flaky, err := dbModel.GetFlakyTests(ctx, dbc.env, f)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "fetching flaky tests").Error(),
+		}
+	}
+
+	return importFlakyTests(flaky)
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// GetFlakyTests returns the cached flaky tests, only enforcing the Variants,
+// Tasks, and Limit fields of the filter.
+func (mc *MockConnector) GetFlakyTests(ctx context.Context, f dbModel.FlakyTestsFilter) ([]model.APIFlakyTest, error) {
+	var flaky []dbModel.AggregatedFlakyTest
+	for _, ft := range mc.CachedFlakyTests {
+		if len(f.Variants) > 0 && !utility.StringSliceContains(f.Variants, ft.Variant) {
+			continue
+		}
+		if len(f.Tasks) > 0 && !utility.StringSliceContains(f.Tasks, ft.TaskName) {
+			continue
+		}
+		flaky = append(flaky, ft)
+		if f.Limit > 0 && len(flaky) == f.Limit {
+			break
+		}
+	}
+
+	return importFlakyTests(flaky)
+}
+
+func importFlakyTests(flaky []dbModel.AggregatedFlakyTest) ([]model.APIFlakyTest, error) {
+	apiFlaky := make([]model.APIFlakyTest, len(flaky))
+	for i, ft := range flaky {
+		if err := apiFlaky[i].Import(ft); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for flaky tests").Error(),
+			}
+		}
+	}
+
+	return apiFlaky, nil
+}
@@ -32,13 +32,21 @@ func (dbc *DBConnector) GetBaseURL() string { return dbc.baseURL }
 // MockConnector is a struct that implements the Connector interface backed by
 // a mock Cedar service layer.
 type MockConnector struct {
-	CachedPerformanceResults map[string]model.PerformanceResult
-	ChildMap                 map[string][]string
-	CachedLogs               map[string]model.Log
-	CachedHistoricalTestData []model.AggregatedHistoricalTestData
-	CachedSystemMetrics      map[string]model.SystemMetrics
-	Users                    map[string]bool
-	Bucket                   string
+	CachedPerformanceResults    map[string]model.PerformanceResult
+	CachedPerfChangePoints      map[string]model.PerformanceChangePoint
+	ChildMap                    map[string][]string
+	CachedLogs                  map[string]model.Log
+	CachedTestResults           map[string][]model.TestResult
+	CachedTestResultsStats      []model.AggregatedTestResultsStats
+	CachedHistoricalTestData    []model.AggregatedHistoricalTestData
+	CachedFlakyTests            []model.AggregatedFlakyTest
+	CachedTestDurationSlowdowns []model.TestDurationSlowdown
+	CachedTestOwnerships        map[string]model.TestOwnership
+	CachedTestFailuresByTeam    map[string][]model.TeamTestFailures
+	CachedTestAnnotations       map[string]model.TestAnnotation
+	CachedSystemMetrics         map[string]model.SystemMetrics
+	Users                       map[string]bool
+	Bucket                      string
 
 	env cedar.Environment
 }
@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"io"
 	"time"
 
 	dbModel "github.com/evergreen-ci/cedar/model"
@@ -35,6 +36,23 @@ type Connector interface {
 	// processing recalculation job has been scheduled for each type of
 	// test (project/variant/task/test combo).
 	ScheduleSignalProcessingRecalculateJobs(context.Context) error
+	// GetPerformanceTimeSeries returns the points of the performance
+	// time series described by the given options.
+	GetPerformanceTimeSeries(context.Context, dbModel.PerformanceTimeSeriesOptions) ([]model.APIPerformanceTimeSeriesPoint, error)
+	// ComparePerformanceResults compares the rollups of the performance
+	// results of the two versions or two tasks described by the given
+	// options.
+	ComparePerformanceResults(context.Context, dbModel.PerformanceComparisonOptions) (*model.APIPerformanceComparison, error)
+
+	////////////////////////////
+	// Performance Change Points
+	////////////////////////////
+	// GetPerformanceChangePoints returns the performance change points
+	// matching the given filter.
+	GetPerformanceChangePoints(context.Context, dbModel.PerformanceChangePointsFilter) ([]model.APIPerformanceChangePoint, error)
+	// TriagePerformanceChangePoint sets the triage of the performance
+	// change point with the given ID from the given project.
+	TriagePerformanceChangePoint(context.Context, string, string, dbModel.PerformanceChangePointTriage) (*model.APIPerformanceChangePoint, error)
 
 	//////////////////
 	// Buildlogger Log
@@ -46,6 +64,13 @@ type Connector interface {
 	// ID, PrintTime, PrintPriority, TimeRange, Limit, and SoftSizeLimit
 	// are respected from BuildloggerOptions.
 	FindLogByID(context.Context, BuildloggerOptions) ([]byte, time.Time, bool, error)
+	// FollowLogByID returns a stream of the lines of the buildlogger log
+	// with the given ID as they are appended, until the log is completed
+	// or the follow timeout is reached. Reads block while waiting for new
+	// lines. The stream's cursor is the FollowCursor with which to resume
+	// the log. ID, PrintTime, PrintPriority, TimeRange.StartAt, Limit,
+	// FollowCursor, and Filter are respected from BuildloggerOptions.
+	FollowLogByID(context.Context, BuildloggerOptions) (*LogFollowStream, error)
 	// FindLogMetadataByID returns the metadata for the buildlogger log
 	// with the given ID.
 	FindLogMetadataByID(context.Context, string) (*model.APILog, error)
@@ -60,6 +85,13 @@ type Connector interface {
 	// FindLogsByTaskID returns the metadata for the buildlogger logs with
 	// the given task ID and tags.
 	FindLogMetadataByTaskID(context.Context, BuildloggerOptions) ([]model.APILog, error)
+	// FindLogArchiveByTaskID returns a reader that streams a zip archive
+	// of the buildlogger logs with the given task ID, one file per log,
+	// along with a JSON manifest of their metadata. The caller should
+	// close the reader when done.
+	// TaskID, ProcessName, Execution, Tags, TimeRange, PrintTime, and
+	// PrintPriority are respected from BuildloggerOptions.
+	FindLogArchiveByTaskID(context.Context, BuildloggerOptions) (io.ReadCloser, error)
 	// FindLogsByTestName returns the buildlogger logs with the given task
 	// ID and test name. The time returned is the next timestamp for
 	// pagination and the bool indicates whether the logs are paginated
@@ -72,6 +104,12 @@ type Connector interface {
 	// FindLogsByTestName returns the metadata for the buildlogger logs
 	// with the given task ID, test name, and tags.
 	FindLogMetadataByTestName(context.Context, BuildloggerOptions) ([]model.APILog, error)
+	// FindTestResultLog returns the lines of the buildlogger logs that
+	// belong to the test result with the given task ID and test name, as
+	// resolved by dbModel.GetTestResultLogRegion. TaskID, TestName,
+	// Execution, PrintTime, and PrintPriority are respected from
+	// BuildloggerOptions.
+	FindTestResultLog(context.Context, BuildloggerOptions) ([]byte, error)
 	// FindGroupedLogs finds logs that are grouped via a "group id" held in
 	// the tags field. These groups have a hierarchy of test level and task
 	// level. This function returns logs with the given task ID and group
@@ -82,6 +120,15 @@ type Connector interface {
 	// PrintPriority, Limit, and SoftSizeLimit are respected from
 	// BuildloggerOptions.
 	FindGroupedLogs(context.Context, BuildloggerOptions) ([]byte, time.Time, bool, error)
+	// SearchLogs scans the buildlogger logs matching the given options and
+	// returns the lines matching the search pattern along with their
+	// surrounding context lines. If the search stopped before scanning
+	// every matching log, the log offset to continue from is also
+	// returned, otherwise the log offset is 0.
+	SearchLogs(context.Context, dbModel.LogSearchOptions) ([]model.APILogSearchResult, int, error)
+	// FindTopLogFailureSignatures returns the failure signatures found in
+	// the most buildlogger logs matching the given options.
+	FindTopLogFailureSignatures(context.Context, dbModel.TopLogFailureSignaturesOptions) ([]model.APILogFailureSignatureCount, error)
 
 	///////////////
 	// Test Results
@@ -98,11 +145,59 @@ type Connector interface {
 	// execution is nil, this will return the sample from the most recent
 	// execution. Filtering, sorting, and paginating is not supported.
 	GetFailedTestResultsSample(context.Context, TestResultsOptions) ([]string, error)
+	// GetOwnedFailedTestResultsSample is the same as
+	// GetFailedTestResultsSample, but also returns the owner and team of
+	// each test in the sample and whether the test is quarantined.
+	GetOwnedFailedTestResultsSample(context.Context, TestResultsOptions) ([]model.APIFailedTestSample, error)
 	// GetTestResultsStats queries the DB to aggregate basic stats
 	// of test results for the given options. If the execution is nil, this
 	// will return stats for the most recent execution. Filtering, sorting,
 	// and paginating is not supported.
 	GetTestResultsStats(context.Context, TestResultsOptions) (*model.APITestResultsStats, error)
+	// DiffTestResults queries the DB to find the test results of two tasks
+	// and returns the differences between them. If an execution is nil,
+	// the test results from the most recent execution are used.
+	DiffTestResults(context.Context, TestResultsDiffOptions) (*model.APITestResultsDiff, error)
+	// UploadTestResults parses the given test results report and stores
+	// the results in a new test results record for the task execution.
+	UploadTestResults(context.Context, TestResultsUploadOptions) (*model.APITestResultsUpload, error)
+	// SearchTestResults finds the test results matching the given options
+	// across the tasks of a project, most recent task executions first.
+	SearchTestResults(context.Context, dbModel.SearchTestResultsOptions) (*model.APITestResultsSearch, error)
+	// GetAggregatedTestResultsStats sums the test results stats of the
+	// latest execution of the tasks of a project matching the given
+	// filter, grouped by the filter's task fields.
+	GetAggregatedTestResultsStats(context.Context, dbModel.TestResultsStatsFilter) ([]model.APIAggregatedTestResultsStats, error)
+
+	/////////////////
+	// Test Ownership
+	/////////////////
+	// GetTestOwnerships returns the test ownerships of a project.
+	GetTestOwnerships(context.Context, string) ([]model.APITestOwnership, error)
+	// SaveTestOwnership creates or replaces the owner and team of the
+	// tests of a project matching a test name pattern.
+	SaveTestOwnership(context.Context, dbModel.TestOwnership) (*model.APITestOwnership, error)
+	// RemoveTestOwnership removes the test ownership with the given ID
+	// from the given project.
+	RemoveTestOwnership(context.Context, string, string) error
+	// GetTestFailuresByTeam aggregates the failed tests of the given
+	// project and version by the team owning them.
+	GetTestFailuresByTeam(context.Context, string, string) ([]model.APITeamTestFailures, error)
+
+	///////////////////
+	// Test Annotations
+	///////////////////
+	// GetTestAnnotations returns the test annotations matching the given
+	// filter.
+	GetTestAnnotations(context.Context, dbModel.TestAnnotationsFilter) ([]model.APITestAnnotation, error)
+	// GetTestAnnotation returns the test annotation with the given ID from
+	// the given project.
+	GetTestAnnotation(context.Context, string, string) (*model.APITestAnnotation, error)
+	// SaveTestAnnotation creates or replaces the annotation of a test.
+	SaveTestAnnotation(context.Context, dbModel.TestAnnotation) (*model.APITestAnnotation, error)
+	// RemoveTestAnnotation removes the test annotation with the given ID
+	// from the given project.
+	RemoveTestAnnotation(context.Context, string, string) error
 
 	///////////////////////
 	// Historical Test Data
@@ -111,6 +206,20 @@ type Connector interface {
 	// filter.
 	GetHistoricalTestData(context.Context, dbModel.HistoricalTestDataFilter) ([]model.APIAggregatedHistoricalTestData, error)
 
+	//////////////
+	// Flaky Tests
+	//////////////
+	// GetFlakyTests returns the flaky tests of a project ranked by flake
+	// score, along with their daily trend, using a filter.
+	GetFlakyTests(context.Context, dbModel.FlakyTestsFilter) ([]model.APIFlakyTest, error)
+
+	///////////////////////////
+	// Test Duration Slowdowns
+	///////////////////////////
+	// GetTestDurationSlowdowns returns the test duration slowdowns of a
+	// project, most recent first, using a filter.
+	GetTestDurationSlowdowns(context.Context, dbModel.TestDurationSlowdownsFilter) ([]model.APITestDurationSlowdown, error)
+
 	/////////////////
 	// System Metrics
 	/////////////////
@@ -136,8 +245,11 @@ type BuildloggerOptions struct {
 	PrintTime      bool
 	PrintPriority  bool
 	Limit          int
+	Skip           int
+	FollowCursor   string
 	Tail           int
 	SoftSizeLimit  int
+	Filter         dbModel.LogFilterOptions
 }
 
 // TestResultsOptions holds all values required to find a specific TestResults
@@ -160,6 +272,25 @@ type TestResultsFilterAndSortOptions struct {
 	Limit        int
 	Page         int
 	BaseResults  *TestResultsOptions
+	Attributes   map[string]string
+}
+
+// TestResultsDiffOptions holds all values required to diff the test results
+// of two tasks using connector functions. Zero duration change values use
+// the defaults.
+type TestResultsDiffOptions struct {
+	Base                    TestResultsOptions
+	Current                 TestResultsOptions
+	DurationChangeThreshold float64
+	MinDurationChange       time.Duration
+}
+
+// TestResultsUploadOptions holds all values required to upload a test results
+// report using connector functions.
+type TestResultsUploadOptions struct {
+	Info   dbModel.TestResultsInfo
+	Format dbModel.TestResultsFormat
+	Report io.Reader
 }
 
 // TestSampleOptions specifies the tasks to get the sample for
@@ -0,0 +1,155 @@
+package data
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// GetPerformanceChangePoints returns the performance change points matching
+// the given filter.
+func (dbc *DBConnector) GetPerformanceChangePoints(ctx context.Context, f dbModel.PerformanceChangePointsFilter) ([]model.APIPerformanceChangePoint, error) {
+	changePoints, err := dbModel.SearchPerformanceChangePoints(ctx, dbc.env, f)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "searching performance change points").Error(),
+		}
+	}
+
+	return importPerformanceChangePoints(changePoints)
+}
+
+// TriagePerformanceChangePoint sets the triage of the performance change
+// point with the given ID from the project.
+func (dbc *DBConnector) TriagePerformanceChangePoint(ctx context.Context, project, id string, triage dbModel.PerformanceChangePointTriage) (*model.APIPerformanceChangePoint, error) {
+	if err := triage.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid performance change point triage").Error(),
+		}
+	}
+
+	changePoint, err := dbModel.TriagePerformanceChangePoint(ctx, dbc.env, project, id, triage)
+	if db.ResultsNotFound(err) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    errors.Errorf("performance change point '%s' not found in project '%s'", id, project).Error(),
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "triaging performance change point '%s'", id).Error(),
+		}
+	}
+
+	return importPerformanceChangePoint(*changePoint)
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// GetPerformanceChangePoints returns the cached performance change points
+// matching the given filter, most recently detected first.
+func (mc *MockConnector) GetPerformanceChangePoints(ctx context.Context, f dbModel.PerformanceChangePointsFilter) ([]model.APIPerformanceChangePoint, error) {
+	statuses := map[dbModel.PerformanceChangePointTriageStatus]bool{}
+	for _, status := range f.Statuses {
+		statuses[status] = true
+	}
+
+	var changePoints []dbModel.PerformanceChangePoint
+	for _, cp := range mc.CachedPerfChangePoints {
+		if cp.SeriesID.Project != f.Project {
+			continue
+		}
+		if f.Variant != "" && cp.SeriesID.Variant != f.Variant {
+			continue
+		}
+		if f.Task != "" && cp.SeriesID.Task != f.Task {
+			continue
+		}
+		if f.Test != "" && cp.SeriesID.Test != f.Test {
+			continue
+		}
+		if f.Measurement != "" && cp.SeriesID.Measurement != f.Measurement {
+			continue
+		}
+		if len(statuses) > 0 && !statuses[cp.Triage.Status] {
+			continue
+		}
+		changePoints = append(changePoints, cp)
+	}
+	sort.Slice(changePoints, func(i, j int) bool {
+		if !changePoints[i].DetectedAt.Equal(changePoints[j].DetectedAt) {
+			return changePoints[i].DetectedAt.After(changePoints[j].DetectedAt)
+		}
+		return changePoints[i].ID < changePoints[j].ID
+	})
+	if f.Limit > 0 && len(changePoints) > f.Limit {
+		changePoints = changePoints[:f.Limit]
+	}
+
+	return importPerformanceChangePoints(changePoints)
+}
+
+// TriagePerformanceChangePoint validates the triage and sets it on the cached
+// performance change point with the given ID from the project.
+func (mc *MockConnector) TriagePerformanceChangePoint(ctx context.Context, project, id string, triage dbModel.PerformanceChangePointTriage) (*model.APIPerformanceChangePoint, error) {
+	if err := triage.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid performance change point triage").Error(),
+		}
+	}
+
+	changePoint, ok := mc.CachedPerfChangePoints[id]
+	if !ok || changePoint.SeriesID.Project != project {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    errors.Errorf("performance change point '%s' not found in project '%s'", id, project).Error(),
+		}
+	}
+	triage.TriagedAt = time.Now()
+	changePoint.Triage = triage
+	mc.CachedPerfChangePoints[id] = changePoint
+
+	return importPerformanceChangePoint(changePoint)
+}
+
+func importPerformanceChangePoint(changePoint dbModel.PerformanceChangePoint) (*model.APIPerformanceChangePoint, error) {
+	apiChangePoint := &model.APIPerformanceChangePoint{}
+	if err := apiChangePoint.Import(changePoint); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "corrupt data for performance change point").Error(),
+		}
+	}
+
+	return apiChangePoint, nil
+}
+
+func importPerformanceChangePoints(changePoints []dbModel.PerformanceChangePoint) ([]model.APIPerformanceChangePoint, error) {
+	apiChangePoints := make([]model.APIPerformanceChangePoint, len(changePoints))
+	for i, changePoint := range changePoints {
+		if err := apiChangePoints[i].Import(changePoint); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for performance change points").Error(),
+			}
+		}
+	}
+
+	return apiChangePoints, nil
+}
@@ -0,0 +1,90 @@
+package data
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// ComparePerformanceResults compares the rollups of the performance results
+// of the two versions or two tasks described by the given options.
+func (dbc *DBConnector) ComparePerformanceResults(ctx context.Context, opts dbModel.PerformanceComparisonOptions) (*model.APIPerformanceComparison, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid performance comparison options").Error(),
+		}
+	}
+
+	comparison, err := dbModel.ComparePerformanceResults(ctx, dbc.env, opts)
+	if err != nil {
+		if db.ResultsNotFound(err) {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    err.Error(),
+			}
+		}
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "comparing performance results").Error(),
+		}
+	}
+
+	return importPerformanceComparison(*comparison)
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// ComparePerformanceResults compares the rollups of the cached performance
+// results of the two versions or two tasks described by the given options.
+func (mc *MockConnector) ComparePerformanceResults(_ context.Context, opts dbModel.PerformanceComparisonOptions) (*model.APIPerformanceComparison, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid performance comparison options").Error(),
+		}
+	}
+
+	var base, compare []dbModel.PerformanceResult
+	for _, result := range mc.CachedPerformanceResults {
+		switch {
+		case opts.BaseVersion != "" && result.Info.Version == opts.BaseVersion,
+			opts.BaseTaskID != "" && result.Info.TaskID == opts.BaseTaskID:
+			base = append(base, result)
+		case opts.Version != "" && result.Info.Version == opts.Version,
+			opts.TaskID != "" && result.Info.TaskID == opts.TaskID:
+			compare = append(compare, result)
+		}
+	}
+	if len(base) == 0 || len(compare) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "no performance results found",
+		}
+	}
+
+	return importPerformanceComparison(*dbModel.ComparePerformanceResultSets(base, compare, opts))
+}
+
+func importPerformanceComparison(comparison dbModel.PerformanceComparison) (*model.APIPerformanceComparison, error) {
+	apiComparison := &model.APIPerformanceComparison{}
+	if err := apiComparison.Import(comparison); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "corrupt data for performance comparison").Error(),
+		}
+	}
+
+	return apiComparison, nil
+}
@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// GetPerformanceTimeSeries returns the points of the performance time series
+// described by the given options.
+func (dbc *DBConnector) GetPerformanceTimeSeries(ctx context.Context, opts dbModel.PerformanceTimeSeriesOptions) ([]model.APIPerformanceTimeSeriesPoint, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid performance time series options").Error(),
+		}
+	}
+
+	points, err := dbModel.GetPerformanceTimeSeries(ctx, dbc.env, opts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "getting performance time series").Error(),
+		}
+	}
+
+	return importPerformanceTimeSeries(points)
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// GetPerformanceTimeSeries returns the points of the performance time series
+// described by the given options from the cached performance results, sorted
+// by order and creation time.
+func (mc *MockConnector) GetPerformanceTimeSeries(ctx context.Context, opts dbModel.PerformanceTimeSeriesOptions) ([]model.APIPerformanceTimeSeriesPoint, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid performance time series options").Error(),
+		}
+	}
+
+	var points []dbModel.PerformanceTimeSeriesPoint
+	for _, result := range mc.CachedPerformanceResults {
+		info := result.Info
+		if info.Project != opts.ID.Project || info.Variant != opts.ID.Variant || info.TaskName != opts.ID.Task || info.TestName != opts.ID.Test {
+			continue
+		}
+		if !opts.IncludePatches && !info.Mainline {
+			continue
+		}
+		if (opts.MinOrder > 0 && info.Order < opts.MinOrder) || (opts.MaxOrder > 0 && info.Order > opts.MaxOrder) {
+			continue
+		}
+		if (!opts.Interval.StartAt.IsZero() && result.CreatedAt.Before(opts.Interval.StartAt)) ||
+			(!opts.Interval.EndAt.IsZero() && result.CreatedAt.After(opts.Interval.EndAt)) {
+			continue
+		}
+		if !sameArguments(info.Arguments, opts.ID.Arguments) {
+			continue
+		}
+
+		for _, stat := range result.Rollups.Stats {
+			if stat.Name != opts.ID.Measurement {
+				continue
+			}
+			point := dbModel.PerformanceTimeSeriesPoint{
+				PerformanceResultID: result.ID,
+				Order:               info.Order,
+				Version:             info.Version,
+				TaskID:              info.TaskID,
+				Execution:           info.Execution,
+				CreatedAt:           result.CreatedAt,
+			}
+			switch v := stat.Value.(type) {
+			case float64:
+				point.Value = v
+			case int64:
+				point.Value = float64(v)
+			case int32:
+				point.Value = float64(v)
+			case int:
+				point.Value = float64(v)
+			}
+			points = append(points, point)
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Order != points[j].Order {
+			return points[i].Order < points[j].Order
+		}
+		return points[i].CreatedAt.Before(points[j].CreatedAt)
+	})
+	if opts.Limit > 0 && len(points) > opts.Limit {
+		points = points[len(points)-opts.Limit:]
+	}
+
+	return importPerformanceTimeSeries(points)
+}
+
+func sameArguments(a, b dbModel.PerformanceArguments) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if other, ok := b[k]; !ok || other != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func importPerformanceTimeSeries(points []dbModel.PerformanceTimeSeriesPoint) ([]model.APIPerformanceTimeSeriesPoint, error) {
+	apiPoints := make([]model.APIPerformanceTimeSeriesPoint, len(points))
+	for i, point := range points {
+		if err := apiPoints[i].Import(point); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for performance time series").Error(),
+			}
+		}
+	}
+
+	return apiPoints, nil
+}
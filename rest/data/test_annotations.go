@@ -0,0 +1,224 @@
+package data
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// GetTestAnnotations returns the test annotations matching the given filter.
+func (dbc *DBConnector) GetTestAnnotations(ctx context.Context, f dbModel.TestAnnotationsFilter) ([]model.APITestAnnotation, error) {
+	annotations, err := dbModel.FindTestAnnotations(ctx, dbc.env, f)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "fetching test annotations").Error(),
+		}
+	}
+
+	return importTestAnnotations(annotations)
+}
+
+// GetTestAnnotation returns the test annotation with the given ID from the
+// project.
+func (dbc *DBConnector) GetTestAnnotation(ctx context.Context, project, id string) (*model.APITestAnnotation, error) {
+	annotation, err := dbc.findTestAnnotation(ctx, project, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return importTestAnnotation(*annotation)
+}
+
+// SaveTestAnnotation creates or replaces the annotation of the test.
+func (dbc *DBConnector) SaveTestAnnotation(ctx context.Context, annotation dbModel.TestAnnotation) (*model.APITestAnnotation, error) {
+	if err := annotation.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid test annotation").Error(),
+		}
+	}
+
+	annotation.Setup(dbc.env)
+	if err := annotation.Save(ctx); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "saving test annotation").Error(),
+		}
+	}
+
+	return importTestAnnotation(annotation)
+}
+
+// RemoveTestAnnotation removes the test annotation with the given ID from
+// the project.
+func (dbc *DBConnector) RemoveTestAnnotation(ctx context.Context, project, id string) error {
+	annotation, err := dbc.findTestAnnotation(ctx, project, id)
+	if err != nil {
+		return err
+	}
+
+	if err = annotation.Remove(ctx); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "removing test annotation '%s'", id).Error(),
+		}
+	}
+
+	return nil
+}
+
+func (dbc *DBConnector) findTestAnnotation(ctx context.Context, project, id string) (*dbModel.TestAnnotation, error) {
+	annotation := &dbModel.TestAnnotation{ID: id}
+	annotation.Setup(dbc.env)
+	err := annotation.Find(ctx)
+	if db.ResultsNotFound(err) || (err == nil && annotation.Project != project) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    errors.Errorf("test annotation '%s' not found in project '%s'", id, project).Error(),
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding test annotation '%s'", id).Error(),
+		}
+	}
+
+	return annotation, nil
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// GetTestAnnotations returns the cached test annotations matching the given
+// filter, sorted by test name, variant, and task name.
+func (mc *MockConnector) GetTestAnnotations(ctx context.Context, f dbModel.TestAnnotationsFilter) ([]model.APITestAnnotation, error) {
+	now := time.Now()
+	var annotations []dbModel.TestAnnotation
+	for _, annotation := range mc.CachedTestAnnotations {
+		if annotation.Project != f.Project {
+			continue
+		}
+		if f.Variant != "" && annotation.Variant != f.Variant {
+			continue
+		}
+		if f.TaskName != "" && annotation.TaskName != f.TaskName {
+			continue
+		}
+		if f.TestName != "" && annotation.TestName != f.TestName {
+			continue
+		}
+		if f.QuarantinedOnly && !annotation.Quarantined {
+			continue
+		}
+		if !f.IncludeExpired && annotation.IsExpired(now) {
+			continue
+		}
+		annotations = append(annotations, annotation)
+	}
+	sort.Slice(annotations, func(i, j int) bool {
+		if annotations[i].TestName != annotations[j].TestName {
+			return annotations[i].TestName < annotations[j].TestName
+		}
+		if annotations[i].Variant != annotations[j].Variant {
+			return annotations[i].Variant < annotations[j].Variant
+		}
+		return annotations[i].TaskName < annotations[j].TaskName
+	})
+
+	return importTestAnnotations(annotations)
+}
+
+// GetTestAnnotation returns the cached test annotation with the given ID from
+// the project.
+func (mc *MockConnector) GetTestAnnotation(ctx context.Context, project, id string) (*model.APITestAnnotation, error) {
+	annotation, ok := mc.CachedTestAnnotations[id]
+	if !ok || annotation.Project != project {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    errors.Errorf("test annotation '%s' not found in project '%s'", id, project).Error(),
+		}
+	}
+
+	return importTestAnnotation(annotation)
+}
+
+// SaveTestAnnotation validates and caches the test annotation, preserving the
+// creation fields of an existing annotation.
+func (mc *MockConnector) SaveTestAnnotation(ctx context.Context, annotation dbModel.TestAnnotation) (*model.APITestAnnotation, error) {
+	if err := annotation.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid test annotation").Error(),
+		}
+	}
+
+	annotation.ID = dbModel.TestAnnotationID(annotation.Project, annotation.Variant, annotation.TaskName, annotation.TestName)
+	annotation.LastUpdate = time.Now()
+	if existing, ok := mc.CachedTestAnnotations[annotation.ID]; ok {
+		annotation.CreatedBy = existing.CreatedBy
+		annotation.CreatedAt = existing.CreatedAt
+	} else {
+		annotation.CreatedBy = annotation.LastUpdatedBy
+		annotation.CreatedAt = annotation.LastUpdate
+	}
+	if mc.CachedTestAnnotations == nil {
+		mc.CachedTestAnnotations = map[string]dbModel.TestAnnotation{}
+	}
+	mc.CachedTestAnnotations[annotation.ID] = annotation
+
+	return importTestAnnotation(annotation)
+}
+
+// RemoveTestAnnotation removes the cached test annotation with the given ID
+// from the project.
+func (mc *MockConnector) RemoveTestAnnotation(ctx context.Context, project, id string) error {
+	annotation, ok := mc.CachedTestAnnotations[id]
+	if !ok || annotation.Project != project {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    errors.Errorf("test annotation '%s' not found in project '%s'", id, project).Error(),
+		}
+	}
+	delete(mc.CachedTestAnnotations, id)
+
+	return nil
+}
+
+func importTestAnnotation(annotation dbModel.TestAnnotation) (*model.APITestAnnotation, error) {
+	apiAnnotation := &model.APITestAnnotation{}
+	if err := apiAnnotation.Import(annotation); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "corrupt data for test annotation").Error(),
+		}
+	}
+
+	return apiAnnotation, nil
+}
+
+func importTestAnnotations(annotations []dbModel.TestAnnotation) ([]model.APITestAnnotation, error) {
+	apiAnnotations := make([]model.APITestAnnotation, len(annotations))
+	for i, annotation := range annotations {
+		if err := apiAnnotations[i].Import(annotation); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for test annotations").Error(),
+			}
+		}
+	}
+
+	return apiAnnotations, nil
+}
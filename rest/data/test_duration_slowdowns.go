@@ -0,0 +1,68 @@
+package data
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// GetTestDurationSlowdowns queries the service backend to retrieve the test
+// duration slowdowns that match the given filter.
+func (dbc *DBConnector) GetTestDurationSlowdowns(ctx context.Context, f dbModel.TestDurationSlowdownsFilter) ([]model.APITestDurationSlowdown, error) {
+	slowdowns, err := dbModel.GetTestDurationSlowdowns(ctx, dbc.env, f)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "fetching test duration slowdowns").Error(),
+		}
+	}
+
+	return importTestDurationSlowdowns(slowdowns)
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// GetTestDurationSlowdowns returns the cached test duration slowdowns, only
+// enforcing the Variants, Tasks, and Limit fields of the filter.
+func (mc *MockConnector) GetTestDurationSlowdowns(ctx context.Context, f dbModel.TestDurationSlowdownsFilter) ([]model.APITestDurationSlowdown, error) {
+	var slowdowns []dbModel.TestDurationSlowdown
+	for _, slowdown := range mc.CachedTestDurationSlowdowns {
+		if len(f.Variants) > 0 && !utility.StringSliceContains(f.Variants, slowdown.Info.Variant) {
+			continue
+		}
+		if len(f.Tasks) > 0 && !utility.StringSliceContains(f.Tasks, slowdown.Info.TaskName) {
+			continue
+		}
+		slowdowns = append(slowdowns, slowdown)
+		if f.Limit > 0 && len(slowdowns) == f.Limit {
+			break
+		}
+	}
+
+	return importTestDurationSlowdowns(slowdowns)
+}
+
+func importTestDurationSlowdowns(slowdowns []dbModel.TestDurationSlowdown) ([]model.APITestDurationSlowdown, error) {
+	apiSlowdowns := make([]model.APITestDurationSlowdown, len(slowdowns))
+	for i, slowdown := range slowdowns {
+		if err := apiSlowdowns[i].Import(slowdown); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for test duration slowdowns").Error(),
+			}
+		}
+	}
+
+	return apiSlowdowns, nil
+}
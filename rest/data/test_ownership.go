@@ -0,0 +1,194 @@
+package data
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// GetTestOwnerships returns the test ownerships of the given project.
+func (dbc *DBConnector) GetTestOwnerships(ctx context.Context, project string) ([]model.APITestOwnership, error) {
+	ownerships, err := dbModel.FindTestOwnerships(ctx, dbc.env, project)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "fetching test ownerships").Error(),
+		}
+	}
+
+	return importTestOwnerships(ownerships)
+}
+
+// SaveTestOwnership creates or replaces the test ownership of the project's
+// test name pattern.
+func (dbc *DBConnector) SaveTestOwnership(ctx context.Context, ownership dbModel.TestOwnership) (*model.APITestOwnership, error) {
+	if err := ownership.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid test ownership").Error(),
+		}
+	}
+
+	ownership.Setup(dbc.env)
+	if err := ownership.Save(ctx); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "saving test ownership").Error(),
+		}
+	}
+
+	return importTestOwnership(ownership)
+}
+
+// RemoveTestOwnership removes the test ownership with the given ID from the
+// project.
+func (dbc *DBConnector) RemoveTestOwnership(ctx context.Context, project, id string) error {
+	ownership := &dbModel.TestOwnership{ID: id}
+	ownership.Setup(dbc.env)
+	err := ownership.Find(ctx)
+	if db.ResultsNotFound(err) || (err == nil && ownership.Project != project) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    errors.Errorf("test ownership '%s' not found in project '%s'", id, project).Error(),
+		}
+	} else if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding test ownership '%s'", id).Error(),
+		}
+	}
+
+	if err = ownership.Remove(ctx); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "removing test ownership '%s'", id).Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetTestFailuresByTeam returns the failed tests of the given version
+// aggregated by the team owning them.
+func (dbc *DBConnector) GetTestFailuresByTeam(ctx context.Context, project, version string) ([]model.APITeamTestFailures, error) {
+	byTeam, err := dbModel.GetTestFailuresByTeam(ctx, dbc.env, project, version)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "aggregating test failures by team").Error(),
+		}
+	}
+
+	return importTeamTestFailures(byTeam)
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// GetTestOwnerships returns the cached test ownerships of the given project
+// sorted by test name pattern.
+func (mc *MockConnector) GetTestOwnerships(ctx context.Context, project string) ([]model.APITestOwnership, error) {
+	var ownerships []dbModel.TestOwnership
+	for _, ownership := range mc.CachedTestOwnerships {
+		if ownership.Project == project {
+			ownerships = append(ownerships, ownership)
+		}
+	}
+	sort.Slice(ownerships, func(i, j int) bool {
+		return ownerships[i].TestNamePattern < ownerships[j].TestNamePattern
+	})
+
+	return importTestOwnerships(ownerships)
+}
+
+// SaveTestOwnership validates and caches the test ownership.
+func (mc *MockConnector) SaveTestOwnership(ctx context.Context, ownership dbModel.TestOwnership) (*model.APITestOwnership, error) {
+	if err := ownership.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid test ownership").Error(),
+		}
+	}
+
+	ownership.ID = dbModel.TestOwnershipID(ownership.Project, ownership.TestNamePattern)
+	ownership.LastUpdate = time.Now()
+	if mc.CachedTestOwnerships == nil {
+		mc.CachedTestOwnerships = map[string]dbModel.TestOwnership{}
+	}
+	mc.CachedTestOwnerships[ownership.ID] = ownership
+
+	return importTestOwnership(ownership)
+}
+
+// RemoveTestOwnership removes the cached test ownership with the given ID
+// from the project.
+func (mc *MockConnector) RemoveTestOwnership(ctx context.Context, project, id string) error {
+	ownership, ok := mc.CachedTestOwnerships[id]
+	if !ok || ownership.Project != project {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    errors.Errorf("test ownership '%s' not found in project '%s'", id, project).Error(),
+		}
+	}
+	delete(mc.CachedTestOwnerships, id)
+
+	return nil
+}
+
+// GetTestFailuresByTeam returns the cached test failures by team of the given
+// version, ignoring the project.
+func (mc *MockConnector) GetTestFailuresByTeam(ctx context.Context, project, version string) ([]model.APITeamTestFailures, error) {
+	return importTeamTestFailures(mc.CachedTestFailuresByTeam[version])
+}
+
+func importTestOwnership(ownership dbModel.TestOwnership) (*model.APITestOwnership, error) {
+	apiOwnership := &model.APITestOwnership{}
+	if err := apiOwnership.Import(ownership); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "corrupt data for test ownership").Error(),
+		}
+	}
+
+	return apiOwnership, nil
+}
+
+func importTestOwnerships(ownerships []dbModel.TestOwnership) ([]model.APITestOwnership, error) {
+	apiOwnerships := make([]model.APITestOwnership, len(ownerships))
+	for i, ownership := range ownerships {
+		if err := apiOwnerships[i].Import(ownership); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for test ownerships").Error(),
+			}
+		}
+	}
+
+	return apiOwnerships, nil
+}
+
+func importTeamTestFailures(byTeam []dbModel.TeamTestFailures) ([]model.APITeamTestFailures, error) {
+	apiByTeam := make([]model.APITeamTestFailures, len(byTeam))
+	for i, teamFailures := range byTeam {
+		if err := apiByTeam[i].Import(teamFailures); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for test failures by team").Error(),
+			}
+		}
+	}
+
+	return apiByTeam, nil
+}
@@ -3,12 +3,19 @@ package data
 import (
 	"context"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
 
 	dbModel "github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/cedar/units"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
 
@@ -35,6 +42,12 @@ func (dbc *DBConnector) FindTestResults(ctx context.Context, opts TestResultsOpt
 		}
 	}
 
+	if len(results) > 0 {
+		if err = dbc.setTestResultOwners(ctx, opts, results); err != nil {
+			return nil, err
+		}
+	}
+
 	if err = apiStats.Import(filteredCount); err != nil {
 		return nil, gimlet.ErrorResponse{
 			StatusCode: http.StatusInternalServerError,
@@ -53,6 +66,29 @@ func (dbc *DBConnector) FindTestResults(ctx context.Context, opts TestResultsOpt
 	}, nil
 }
 
+// setTestResultOwners sets the owner and team of the given test results using
+// the test ownerships of their project.
+func (dbc *DBConnector) setTestResultOwners(ctx context.Context, opts TestResultsOptions, results []dbModel.TestResult) error {
+	resultDocs, err := dbModel.FindTestResults(ctx, dbc.env, convertToDBFindTestResultsOptions(opts))
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "retrieving test results").Error(),
+		}
+	}
+
+	matcher, err := dbModel.GetTestOwnershipMatcher(ctx, dbc.env, resultDocs[0].Info.Project)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "retrieving test ownerships").Error(),
+		}
+	}
+	matcher.SetOwners(results)
+
+	return nil
+}
+
 // GetTestResultsFilteredSamples returns test names for the specified test results, filtered by the provided regexes.
 func (dbc *DBConnector) GetTestResultsFilteredSamples(ctx context.Context, opts TestSampleOptions) ([]model.APITestResultsSample, error) {
 	samples, err := dbModel.GetTestResultsFilteredSamples(ctx, dbc.env, convertToDBFindTestSampleOptions(opts))
@@ -83,6 +119,49 @@ func (dbc *DBConnector) GetFailedTestResultsSample(ctx context.Context, opts Tes
 	return extractFailedTestResultsSample(resultDocs...), nil
 }
 
+func (dbc *DBConnector) GetOwnedFailedTestResultsSample(ctx context.Context, opts TestResultsOptions) ([]model.APIFailedTestSample, error) {
+	resultDocs, err := dbModel.FindTestResults(ctx, dbc.env, convertToDBFindTestResultsOptions(opts))
+	if db.ResultsNotFound(err) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "test results not found",
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "retrieving test results").Error(),
+		}
+	}
+
+	matcher, err := dbModel.GetTestOwnershipMatcher(ctx, dbc.env, resultDocs[0].Info.Project)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "retrieving test ownerships").Error(),
+		}
+	}
+
+	quarantines, err := dbModel.GetTestQuarantines(ctx, dbc.env, resultDocs[0].Info.Project)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "retrieving test quarantines").Error(),
+		}
+	}
+
+	var ownedSample []model.APIFailedTestSample
+	for i := 0; i < len(resultDocs) && len(ownedSample) < dbModel.FailedTestsSampleSize; i++ {
+		info := resultDocs[i].Info
+		for _, testName := range resultDocs[i].FailedTestsSample {
+			testSample := model.NewAPIFailedTestSample(testName, matcher.Match(testName))
+			testSample.Quarantined = quarantines.IsQuarantined(info.Variant, info.TaskName, testName)
+			ownedSample = append(ownedSample, testSample)
+		}
+	}
+
+	return ownedSample, nil
+}
+
 func (dbc *DBConnector) GetTestResultsStats(ctx context.Context, opts TestResultsOptions) (*model.APITestResultsStats, error) {
 	stats, err := dbModel.GetTestResultsStats(ctx, dbc.env, convertToDBFindTestResultsOptions(opts))
 	if db.ResultsNotFound(err) {
@@ -108,6 +187,162 @@ func (dbc *DBConnector) GetTestResultsStats(ctx context.Context, opts TestResult
 	return apiStats, nil
 }
 
+func (dbc *DBConnector) DiffTestResults(ctx context.Context, opts TestResultsDiffOptions) (*model.APITestResultsDiff, error) {
+	diff, err := dbModel.DiffTestResults(ctx, dbc.env, dbModel.TestResultsDiffOptions{
+		Base:                    convertToDBFindTestResultsOptions(opts.Base),
+		Current:                 convertToDBFindTestResultsOptions(opts.Current),
+		DurationChangeThreshold: opts.DurationChangeThreshold,
+		MinDurationChange:       opts.MinDurationChange,
+	})
+	if db.ResultsNotFound(err) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "test results not found",
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "diffing test results").Error(),
+		}
+	}
+
+	apiDiff := &model.APITestResultsDiff{}
+	if err = apiDiff.Import(*diff); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "importing diff into APITestResultsDiff struct").Error(),
+		}
+	}
+
+	return apiDiff, nil
+}
+
+func (dbc *DBConnector) UploadTestResults(ctx context.Context, opts TestResultsUploadOptions) (*model.APITestResultsUpload, error) {
+	results, err := dbModel.ParseTestResults(opts.Report, dbModel.ParseTestResultsOptions{Format: opts.Format})
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "parsing test results report").Error(),
+		}
+	}
+
+	conf := dbModel.NewCedarConfig(dbc.env)
+	if err = conf.Find(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "fetching Cedar config").Error(),
+		}
+	}
+	if conf.Bucket.TestResultsBucketType == "" {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "bucket type not specified",
+		}
+	}
+
+	record := dbModel.CreateTestResults(opts.Info, conf.Bucket.TestResultsBucketType)
+	existing := &dbModel.TestResults{ID: record.ID}
+	existing.Setup(dbc.env)
+	if err = existing.Find(ctx); err == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusConflict,
+			Message:    errors.Errorf("test results record '%s' already exists", record.ID).Error(),
+		}
+	} else if !db.ResultsNotFound(err) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "finding test results record '%s'", record.ID).Error(),
+		}
+	}
+
+	record.Setup(dbc.env)
+	if err = record.SaveNew(ctx); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "saving test results record").Error(),
+		}
+	}
+
+	for i := range results {
+		results[i].TaskID = record.Info.TaskID
+		results[i].Execution = record.Info.Execution
+	}
+	if len(results) > 0 {
+		if err = record.Append(ctx, results); err != nil {
+			removeFailedTestResultsUpload(ctx, record)
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrapf(err, "appending test results for '%s'", record.ID).Error(),
+			}
+		}
+	}
+	if err = record.Close(ctx); err != nil {
+		removeFailedTestResultsUpload(ctx, record)
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "closing test results '%s'", record.ID).Error(),
+		}
+	}
+
+	if !record.Info.HistoricalDataDisabled {
+		if !conf.Flags.DisableHistoricalTestData {
+			grip.Error(message.WrapError(record.UpdateHistoricalTestData(ctx, results), message.Fields{
+				"message":           "failed to update historical test data",
+				"test_results_info": record.Info,
+			}))
+		}
+
+		// The record is already saved, so failing to enqueue the job
+		// should not fail the upload.
+		grip.Warning(message.WrapError(amboy.EnqueueUniqueJob(ctx, dbc.env.GetRemoteQueue(), units.NewFlakyTestsJob(record.ID)), message.Fields{
+			"message":           "failed to enqueue flaky tests job",
+			"test_results_id":   record.ID,
+			"test_results_info": record.Info,
+		}))
+	}
+
+	apiUpload := &model.APITestResultsUpload{TestResultsRecordID: utility.ToStringPtr(record.ID)}
+	if err = apiUpload.Stats.Import(record.Stats); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "importing stats into APITestResultsStats struct").Error(),
+		}
+	}
+
+	return apiUpload, nil
+}
+
+// removeFailedTestResultsUpload removes the record and the artifacts of a
+// test results upload that failed after the record was saved so that the
+// upload can be retried.
+func removeFailedTestResultsUpload(ctx context.Context, record *dbModel.TestResults) {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(record.RemoveArtifacts(ctx))
+	catcher.Add(record.Remove(ctx))
+	grip.Error(message.WrapError(catcher.Resolve(), message.Fields{
+		"message":           "failed to clean up failed test results upload",
+		"test_results_id":   record.ID,
+		"test_results_info": record.Info,
+	}))
+}
+
+func (dbc *DBConnector) SearchTestResults(ctx context.Context, opts dbModel.SearchTestResultsOptions) (*model.APITestResultsSearch, error) {
+	results, totalCount, err := dbModel.SearchTestResults(ctx, dbc.env, opts)
+	if errors.Cause(err) == dbModel.ErrTooManyTestResultsToSearch {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "narrow down the search").Error(),
+		}
+	} else if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "searching test results").Error(),
+		}
+	}
+
+	return importTestResultsSearch(results, totalCount)
+}
+
 ///////////////////////////////
 // MockConnector Implementation
 ///////////////////////////////
@@ -124,10 +359,105 @@ func (mc *MockConnector) GetFailedTestResultsSample(ctx context.Context, opts Te
 	return nil, errors.New("not implemented")
 }
 
+func (mc *MockConnector) GetOwnedFailedTestResultsSample(ctx context.Context, opts TestResultsOptions) ([]model.APIFailedTestSample, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (mc *MockConnector) GetTestResultsStats(ctx context.Context, opts TestResultsOptions) (*model.APITestResultsStats, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (mc *MockConnector) DiffTestResults(ctx context.Context, opts TestResultsDiffOptions) (*model.APITestResultsDiff, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (mc *MockConnector) UploadTestResults(ctx context.Context, opts TestResultsUploadOptions) (*model.APITestResultsUpload, error) {
+	results, err := dbModel.ParseTestResults(opts.Report, dbModel.ParseTestResultsOptions{Format: opts.Format})
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "parsing test results report").Error(),
+		}
+	}
+
+	if mc.CachedTestResults == nil {
+		mc.CachedTestResults = map[string][]dbModel.TestResult{}
+	}
+	if _, ok := mc.CachedTestResults[opts.Info.TaskID]; ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusConflict,
+			Message:    errors.Errorf("test results for task '%s' already exist", opts.Info.TaskID).Error(),
+		}
+	}
+
+	var stats dbModel.TestResultsStats
+	for i := range results {
+		results[i].TaskID = opts.Info.TaskID
+		results[i].Execution = opts.Info.Execution
+		stats.TotalCount++
+		if strings.Contains(strings.ToLower(results[i].Status), "fail") {
+			stats.FailedCount++
+		}
+	}
+	mc.CachedTestResults[opts.Info.TaskID] = results
+
+	apiUpload := &model.APITestResultsUpload{TestResultsRecordID: utility.ToStringPtr(opts.Info.ID())}
+	if err = apiUpload.Stats.Import(stats); err != nil {
+		return nil, errors.Wrap(err, "importing stats into APITestResultsStats struct")
+	}
+
+	return apiUpload, nil
+}
+
+// SearchTestResults searches the cached test results, using the task ID as
+// the only task information. Only the TestName, Statuses, and Limit fields of
+// the options are enforced.
+func (mc *MockConnector) SearchTestResults(ctx context.Context, opts dbModel.SearchTestResultsOptions) (*model.APITestResultsSearch, error) {
+	var testNameRegex *regexp.Regexp
+	if opts.TestName != "" {
+		var err error
+		testNameRegex, err = regexp.Compile(opts.TestName)
+		if err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrap(err, "compiling test name regex").Error(),
+			}
+		}
+	}
+
+	taskIDs := make([]string, 0, len(mc.CachedTestResults))
+	for taskID := range mc.CachedTestResults {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+
+	var (
+		results    []dbModel.TestResultSearchResult
+		totalCount int
+	)
+	for _, taskID := range taskIDs {
+		for _, result := range mc.CachedTestResults[taskID] {
+			if testNameRegex != nil && !testNameRegex.MatchString(result.GetDisplayName()) {
+				continue
+			}
+			if len(opts.Statuses) > 0 && !utility.StringSliceContains(opts.Statuses, result.Status) {
+				continue
+			}
+
+			totalCount++
+			if opts.Limit > 0 && len(results) == opts.Limit {
+				continue
+			}
+			results = append(results, dbModel.TestResultSearchResult{
+				Info:   dbModel.TestResultsInfo{Project: opts.Project, TaskID: taskID},
+				Result: result,
+			})
+		}
+	}
+
+	return importTestResultsSearch(results, totalCount)
+}
+
 ///////////////////
 // Helper Functions
 ///////////////////
@@ -212,6 +542,7 @@ func convertToDBFindAndDownloadTestResultsOptions(opts TestResultsOptions) dbMod
 			SortOrderDSC: opts.FilterAndSort.SortOrderDSC,
 			Limit:        opts.FilterAndSort.Limit,
 			Page:         opts.FilterAndSort.Page,
+			Attributes:   opts.FilterAndSort.Attributes,
 		}
 		if opts.FilterAndSort.BaseResults != nil {
 			baseOpts := convertToDBFindTestResultsOptions(*opts.FilterAndSort.BaseResults)
@@ -224,3 +555,20 @@ func convertToDBFindAndDownloadTestResultsOptions(opts TestResultsOptions) dbMod
 		FilterAndSort: filterAndSort,
 	}
 }
+
+func importTestResultsSearch(results []dbModel.TestResultSearchResult, totalCount int) (*model.APITestResultsSearch, error) {
+	search := &model.APITestResultsSearch{
+		TotalCount: totalCount,
+		Results:    make([]model.APITestResultSearchResult, len(results)),
+	}
+	for i, result := range results {
+		if err := search.Results[i].Import(result); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for test results search").Error(),
+			}
+		}
+	}
+
+	return search, nil
+}
@@ -0,0 +1,71 @@
+package data
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+/////////////////////////////
+// DBConnector Implementation
+/////////////////////////////
+
+// GetAggregatedTestResultsStats queries the service backend to sum the test
+// results stats of the tasks matching the given filter.
+func (dbc *DBConnector) GetAggregatedTestResultsStats(ctx context.Context, f dbModel.TestResultsStatsFilter) ([]model.APIAggregatedTestResultsStats, error) {
+	stats, err := dbModel.GetAggregatedTestResultsStats(ctx, dbc.env, f)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrap(err, "aggregating test results stats").Error(),
+		}
+	}
+
+	return importAggregatedTestResultsStats(stats)
+}
+
+///////////////////////////////
+// MockConnector Implementation
+///////////////////////////////
+
+// GetAggregatedTestResultsStats returns the cached aggregated test results
+// stats, only enforcing the Version, Variant, TaskName, and Limit fields of
+// the filter.
+func (mc *MockConnector) GetAggregatedTestResultsStats(ctx context.Context, f dbModel.TestResultsStatsFilter) ([]model.APIAggregatedTestResultsStats, error) {
+	var stats []dbModel.AggregatedTestResultsStats
+	for _, s := range mc.CachedTestResultsStats {
+		if f.Version != "" && s.Version != f.Version {
+			continue
+		}
+		if f.Variant != "" && s.Variant != f.Variant {
+			continue
+		}
+		if f.TaskName != "" && s.TaskName != f.TaskName {
+			continue
+		}
+		stats = append(stats, s)
+		if f.Limit > 0 && len(stats) == f.Limit {
+			break
+		}
+	}
+
+	return importAggregatedTestResultsStats(stats)
+}
+
+func importAggregatedTestResultsStats(stats []dbModel.AggregatedTestResultsStats) ([]model.APIAggregatedTestResultsStats, error) {
+	apiStats := make([]model.APIAggregatedTestResultsStats, len(stats))
+	for i, s := range stats {
+		if err := apiStats[i].Import(s); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    errors.Wrap(err, "corrupt data for aggregated test results stats").Error(),
+			}
+		}
+	}
+
+	return apiStats, nil
+}
@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	flakyTestsAPIMaxLimit     = 1000
+	flakyTestsAPIDefaultLimit = 100
+	flakyTestsAPIDefaultDays  = 28
+	flakyTestsAPIMaxNumTasks  = 50
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /flaky_tests/{project_id}
+
+type flakyTestsHandler struct {
+	filter model.FlakyTestsFilter
+	sc     data.Connector
+}
+
+func makeGetFlakyTests(sc data.Connector) gimlet.RouteHandler {
+	return &flakyTestsHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new flakyTestsHandler.
+func (h *flakyTestsHandler) Factory() gimlet.RouteHandler {
+	return &flakyTestsHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the filter options from the http request.
+func (h *flakyTestsHandler) Parse(_ context.Context, r *http.Request) error {
+	h.filter = model.FlakyTestsFilter{Project: gimlet.GetVars(r)["project_id"]}
+
+	if err := h.parse(r.URL.Query()); err != nil {
+		return errors.Wrap(err, "invalid query parameters")
+	}
+
+	if err := h.filter.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// parse parses the query parameter values and fills the filter. The
+// requesters, variants, and tasks values are parsed the same way as the
+// historical test data's. The date range defaults to the last four weeks,
+// including today.
+func (h *flakyTestsHandler) parse(vals url.Values) error {
+	var (
+		htd htdFilterHandler
+		err error
+	)
+
+	h.filter.Requesters, err = htd.readRequesters(htd.readStringList(vals["requesters"]))
+	if err != nil {
+		return gimlet.ErrorResponse{
+			Message:    "invalid requesters value",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.filter.Variants = htd.readStringList(vals["variants"])
+	h.filter.Tasks = htd.readStringList(vals["tasks"])
+	if len(h.filter.Tasks) > flakyTestsAPIMaxNumTasks {
+		return gimlet.ErrorResponse{
+			Message:    "too many tasks values",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.filter.Limit, err = htd.readInt(vals.Get("limit"), 1, flakyTestsAPIMaxLimit, flakyTestsAPIDefaultLimit)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			Message:    "invalid limit value",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.filter.BeforeDate = utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+	if beforeDate := vals.Get("before_date"); beforeDate != "" {
+		h.filter.BeforeDate, err = time.ParseInLocation(htdAPIDateFormat, beforeDate, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    "invalid before_date value",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	h.filter.AfterDate = h.filter.BeforeDate.AddDate(0, 0, -flakyTestsAPIDefaultDays)
+	if afterDate := vals.Get("after_date"); afterDate != "" {
+		h.filter.AfterDate, err = time.ParseInLocation(htdAPIDateFormat, afterDate, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    "invalid after_date value",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run returns the flaky tests of the project ranked by flake score.
+func (h *flakyTestsHandler) Run(ctx context.Context) gimlet.Responder {
+	flaky, err := h.sc.GetFlakyTests(ctx, h.filter)
+	if err != nil {
+		err = errors.Wrapf(err, "getting flaky tests for project '%s'", h.filter.Project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/flaky_tests/{project_id}",
+			"project": h.filter.Project,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(flaky)
+}
@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlakyTestsHandlerParse(t *testing.T) {
+	t.Run("AllValues", func(t *testing.T) {
+		values := url.Values{
+			"requesters":  []string{htdAPIRequesterPatch},
+			"after_date":  []string{"2018-07-01"},
+			"before_date": []string{"2018-07-15"},
+			"tasks":       []string{"task1,task2"},
+			"variants":    []string{"v1", "v2"},
+			"limit":       []string{"20"},
+		}
+		handler := flakyTestsHandler{}
+		require.NoError(t, handler.parse(values))
+
+		assert.Equal(t, cedar.PatchRequesters, handler.filter.Requesters)
+		assert.Equal(t, time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC), handler.filter.AfterDate)
+		assert.Equal(t, time.Date(2018, 7, 15, 0, 0, 0, 0, time.UTC), handler.filter.BeforeDate)
+		assert.Equal(t, []string{"task1", "task2"}, handler.filter.Tasks)
+		assert.Equal(t, values["variants"], handler.filter.Variants)
+		assert.Equal(t, 20, handler.filter.Limit)
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		handler := flakyTestsHandler{}
+		require.NoError(t, handler.parse(url.Values{}))
+
+		tomorrow := utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+		assert.Equal(t, []string{cedar.RepotrackerVersionRequester}, handler.filter.Requesters)
+		assert.Equal(t, tomorrow, handler.filter.BeforeDate)
+		assert.Equal(t, tomorrow.AddDate(0, 0, -flakyTestsAPIDefaultDays), handler.filter.AfterDate)
+		assert.Equal(t, flakyTestsAPIDefaultLimit, handler.filter.Limit)
+	})
+	t.Run("InvalidValues", func(t *testing.T) {
+		for _, values := range []url.Values{
+			{"requesters": []string{"DNE"}},
+			{"limit": []string{"0"}},
+			{"limit": []string{"1001"}},
+			{"after_date": []string{"07-01-2018"}},
+			{"before_date": []string{"yesterday"}},
+		} {
+			handler := flakyTestsHandler{}
+			assert.Error(t, handler.parse(values))
+		}
+	})
+}
+
+func TestFlakyTestsHandlerRun(t *testing.T) {
+	day := utility.GetUTCDay(time.Now())
+	sc := &data.MockConnector{
+		CachedFlakyTests: []dbModel.AggregatedFlakyTest{
+			{
+				TestName: "test1",
+				TaskName: "task1",
+				Variant:  "v1",
+				NumFlaky: 2,
+				NumRuns:  4,
+				Score:    0.5,
+				Trend:    []dbModel.FlakyTestTrendPoint{{Date: day, NumFlaky: 2, NumRuns: 4, Score: 0.5}},
+			},
+			{
+				TestName: "test2",
+				TaskName: "task2",
+				Variant:  "v1",
+				NumFlaky: 1,
+				NumRuns:  4,
+				Score:    0.25,
+				Trend:    []dbModel.FlakyTestTrendPoint{{Date: day, NumFlaky: 1, NumRuns: 4, Score: 0.25}},
+			},
+			{
+				TestName: "test3",
+				TaskName: "task1",
+				Variant:  "v2",
+				NumFlaky: 1,
+				NumRuns:  10,
+				Score:    0.1,
+				Trend:    []dbModel.FlakyTestTrendPoint{{Date: day, NumFlaky: 1, NumRuns: 10, Score: 0.1}},
+			},
+		},
+	}
+	handler := makeGetFlakyTests(sc).(*flakyTestsHandler)
+
+	t.Run("All", func(t *testing.T) {
+		handler.filter = dbModel.FlakyTestsFilter{Project: "project", Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		flaky, ok := resp.Data().([]model.APIFlakyTest)
+		require.True(t, ok)
+		require.Len(t, flaky, 3)
+		for i, ft := range flaky {
+			expected := model.APIFlakyTest{}
+			require.NoError(t, expected.Import(sc.CachedFlakyTests[i]))
+			assert.Equal(t, expected, ft)
+		}
+	})
+	t.Run("Filtered", func(t *testing.T) {
+		handler.filter = dbModel.FlakyTestsFilter{Project: "project", Variants: []string{"v1"}, Tasks: []string{"task1"}, Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		flaky, ok := resp.Data().([]model.APIFlakyTest)
+		require.True(t, ok)
+		require.Len(t, flaky, 1)
+		assert.Equal(t, "test1", utility.FromStringPtr(flaky[0].TestName))
+	})
+	t.Run("Limit", func(t *testing.T) {
+		handler.filter = dbModel.FlakyTestsFilter{Project: "project", Limit: 2}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		flaky, ok := resp.Data().([]model.APIFlakyTest)
+		require.True(t, ok)
+		assert.Len(t, flaky, 2)
+	})
+	t.Run("NoData", func(t *testing.T) {
+		handler.filter = dbModel.FlakyTestsFilter{Project: "project", Variants: []string{"DNE"}, Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		flaky, ok := resp.Data().([]model.APIFlakyTest)
+		require.True(t, ok)
+		assert.Empty(t, flaky)
+	})
+}
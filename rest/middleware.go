@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"strconv"
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
@@ -107,6 +109,128 @@ func (m *evgAuthReadLogByTaskIDMiddleware) ServeHTTP(rw http.ResponseWriter, r *
 	next(rw, r)
 }
 
+type evgAuthReadLogByProjectMiddleware struct {
+	evgConf *model.EvergreenConfig
+}
+
+// newEvgAuthReadLogByProjectMiddleware returns an implementation of
+// gimlet.Middleware that sends a HTTP request to Evergreen to check if the
+// user is authorized to read the logs and test results of the project given
+// in the route.
+func newEvgAuthReadLogByProjectMiddleware(evgConf *model.EvergreenConfig) *evgAuthReadLogByProjectMiddleware {
+	return &evgAuthReadLogByProjectMiddleware{evgConf: evgConf}
+}
+
+func (m *evgAuthReadLogByProjectMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	ctx := r.Context()
+
+	if resp := evgAuthReadLog(ctx, r, m.evgConf, gimlet.GetVars(r)["project_id"]); resp != nil {
+		gimlet.WriteResponse(rw, resp)
+		return
+	}
+
+	next(rw, r)
+}
+
+type logFollowMiddleware struct{}
+
+// newLogFollowMiddleware returns an implementation of gimlet.Middleware that,
+// when the request follows a log, flushes the response after every write so
+// that streamed log lines reach the client as soon as they are available.
+// Once the log lines are streamed, the cursor with which to resume following
+// the log and the number of lines sent are sent in the LogFollowCursorTrailer
+// and LogFollowLinesTrailer trailers.
+func newLogFollowMiddleware() *logFollowMiddleware { return &logFollowMiddleware{} }
+
+func (m *logFollowMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.URL.Query().Get(logFollow) != trueString {
+		next(rw, r)
+		return
+	}
+
+	state := &logFollowState{}
+	r = r.WithContext(context.WithValue(r.Context(), logFollowStateKey{}, state))
+	rw.Header().Set("Trailer", LogFollowCursorTrailer+", "+LogFollowLinesTrailer)
+	if flusher, ok := rw.(http.Flusher); ok {
+		next(&flushingResponseWriter{ResponseWriter: rw, flusher: flusher}, r)
+	} else {
+		next(rw, r)
+	}
+
+	if state.stream != nil {
+		rw.Header().Set(LogFollowCursorTrailer, state.stream.Cursor())
+		rw.Header().Set(LogFollowLinesTrailer, strconv.Itoa(state.stream.Lines()))
+	}
+}
+
+type logFollowStateKey struct{}
+
+// logFollowState holds the stream of a followed log so that its cursor can be
+// sent once the stream is read.
+type logFollowState struct {
+	stream *data.LogFollowStream
+}
+
+// setLogFollowStream sets the stream whose cursor is sent in the response to
+// the request with the given context, if any.
+func setLogFollowStream(ctx context.Context, stream *data.LogFollowStream) {
+	if state, ok := ctx.Value(logFollowStateKey{}).(*logFollowState); ok {
+		state.stream = stream
+	}
+}
+
+type flushingResponseWriter struct {
+	http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (w *flushingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.flusher.Flush()
+	return n, err
+}
+
+type logArchiveMiddleware struct{}
+
+// newLogArchiveMiddleware returns an implementation of gimlet.Middleware that
+// marks successful log archive responses as attachments named after the
+// task, so that browsers download them.
+func newLogArchiveMiddleware() *logArchiveMiddleware { return &logArchiveMiddleware{} }
+
+func (m *logArchiveMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	next(&attachmentResponseWriter{
+		ResponseWriter: rw,
+		disposition: mime.FormatMediaType("attachment", map[string]string{
+			"filename": gimlet.GetVars(r)["task_id"] + ".zip",
+		}),
+	}, r)
+}
+
+// attachmentResponseWriter sets the Content-Disposition header of successful
+// responses.
+type attachmentResponseWriter struct {
+	http.ResponseWriter
+	disposition string
+	wroteHeader bool
+}
+
+func (w *attachmentResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if statusCode == http.StatusOK && w.disposition != "" {
+			w.Header().Set("Content-Disposition", w.disposition)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *attachmentResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
 func evgAuthReadLog(ctx context.Context, r *http.Request, evgConf *model.EvergreenConfig, resourceID string) gimlet.Responder {
 	req, errResp := createEvgAuthRequest(ctx, r, evgConf, resourceID)
 	if errResp != nil {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/gimlet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -118,6 +119,55 @@ func TestDoEvgAuthRequest(t *testing.T) {
 
 }
 
+func TestLogFollowMiddleware(t *testing.T) {
+	m := newLogFollowMiddleware()
+	next := func(rw http.ResponseWriter, r *http.Request) {
+		_, err := rw.Write([]byte("line\n"))
+		assert.NoError(t, err)
+	}
+
+	t.Run("Follow", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/buildlogger/abc?follow=true", nil)
+		m.ServeHTTP(rw, req, next)
+		assert.True(t, rw.Flushed)
+		assert.Equal(t, "line\n", rw.Body.String())
+		assert.Equal(t, LogFollowCursorTrailer+", "+LogFollowLinesTrailer, rw.Header().Get("Trailer"))
+	})
+	t.Run("NoFollow", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/buildlogger/abc", nil)
+		m.ServeHTTP(rw, req, next)
+		assert.False(t, rw.Flushed)
+		assert.Equal(t, "line\n", rw.Body.String())
+		assert.Empty(t, rw.Header().Get("Trailer"))
+	})
+}
+
+func TestLogArchiveMiddleware(t *testing.T) {
+	m := newLogArchiveMiddleware()
+	req := gimlet.SetURLVars(httptest.NewRequest(http.MethodGet, "/buildlogger/task_id/task%201/archive", nil), map[string]string{"task_id": "task 1"})
+
+	t.Run("Success", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		m.ServeHTTP(rw, req, func(rw http.ResponseWriter, r *http.Request) {
+			_, err := rw.Write([]byte("archive"))
+			assert.NoError(t, err)
+		})
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, `attachment; filename="task 1.zip"`, rw.Header().Get("Content-Disposition"))
+		assert.Equal(t, "archive", rw.Body.String())
+	})
+	t.Run("Error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		m.ServeHTTP(rw, req, func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusNotFound)
+		})
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+		assert.Empty(t, rw.Header().Get("Content-Disposition"))
+	})
+}
+
 type evgAuthMockHandler struct {
 	returnUnauthorized bool
 	returnTrue         bool
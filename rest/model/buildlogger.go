@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	dbmodel "github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/utility"
 	"github.com/pkg/errors"
@@ -14,6 +16,8 @@ type APILog struct {
 	CompletedAt APITime            `json:"completed_at"`
 	Duration    float64            `json:"duration_secs"`
 	Artifact    APILogArtifactInfo `json:"artifact"`
+
+	FailureSignatures []APILogFailureSignature `json:"failure_signatures,omitempty"`
 }
 
 // Import transforms a Log object into an APILog object.
@@ -26,12 +30,23 @@ func (apiResult *APILog) Import(i interface{}) error {
 		apiResult.CompletedAt = NewTime(l.CompletedAt)
 		apiResult.Duration = l.CompletedAt.Sub(l.CreatedAt).Seconds()
 		apiResult.Artifact = getLogArtifactInfo(l.Artifact)
+		apiResult.FailureSignatures = nil
+		for _, sig := range l.FailureSignatures {
+			apiResult.FailureSignatures = append(apiResult.FailureSignatures, getLogFailureSignature(sig))
+		}
 	default:
 		return errors.New("incorrect type when fetching converting Log type")
 	}
 	return nil
 }
 
+// APILogArchiveEntry describes a single buildlogger log in the manifest of a
+// task's log archive.
+type APILogArchiveEntry struct {
+	FileName *string `json:"file_name"`
+	Log      APILog  `json:"log"`
+}
+
 // APILogInfo describes information unique to a single buildlogger log.
 type APILogInfo struct {
 	Project     *string           `json:"project,omitempty"`
@@ -72,10 +87,11 @@ func getLogInfo(l dbmodel.LogInfo) APILogInfo {
 // the cedar-based log metadata storage. The prefix field indicates the name of
 // the "sub-bucket".
 type APILogArtifactInfo struct {
-	Type    *string           `json:"type"`
-	Prefix  *string           `json:"prefix"`
-	Version int               `json:"version"`
-	Chunks  []APILogChunkInfo `json:"chunks,omitempty"`
+	Type        *string           `json:"type"`
+	Prefix      *string           `json:"prefix"`
+	Version     int               `json:"version"`
+	Compression *string           `json:"compression,omitempty"`
+	Chunks      []APILogChunkInfo `json:"chunks,omitempty"`
 }
 
 func getLogArtifactInfo(l dbmodel.LogArtifactInfo) APILogArtifactInfo {
@@ -84,12 +100,17 @@ func getLogArtifactInfo(l dbmodel.LogArtifactInfo) APILogArtifactInfo {
 		chunks[i] = getLogChunkInfo(chunk)
 	}
 
-	return APILogArtifactInfo{
+	info := APILogArtifactInfo{
 		Type:    utility.ToStringPtr(string(l.Type)),
 		Prefix:  utility.ToStringPtr(l.Prefix),
 		Version: l.Version,
 		Chunks:  chunks,
 	}
+	if l.Compression != dbmodel.LogCompressionNone {
+		info.Compression = utility.ToStringPtr(string(l.Compression))
+	}
+
+	return info
 }
 
 // APILogChunkInfo describes a chunk of log lines stored in pail-backed offline
@@ -109,3 +130,107 @@ func getLogChunkInfo(l dbmodel.LogChunkInfo) APILogChunkInfo {
 		End:      NewTime(l.End),
 	}
 }
+
+// APILogLine describes a single buildlogger log line.
+type APILogLine struct {
+	Priority  int                    `json:"priority"`
+	Timestamp APITime                `json:"ts"`
+	Data      string                 `json:"data"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+func getLogLine(l dbmodel.LogLine) APILogLine {
+	return APILogLine{
+		Priority:  int(l.Priority),
+		Timestamp: NewTime(l.Timestamp),
+		Data:      strings.TrimSuffix(l.Data, "\n"),
+		Fields:    l.Fields,
+	}
+}
+
+func getLogLines(lines []dbmodel.LogLine) []APILogLine {
+	apiLines := make([]APILogLine, len(lines))
+	for i, line := range lines {
+		apiLines[i] = getLogLine(line)
+	}
+
+	return apiLines
+}
+
+// APILogSearchResult describes a buildlogger log line that matched a search
+// along with its surrounding context lines.
+type APILogSearchResult struct {
+	LogID   *string      `json:"log_id"`
+	Info    APILogInfo   `json:"info"`
+	LineNum int          `json:"line_num"`
+	Line    APILogLine   `json:"line"`
+	Before  []APILogLine `json:"before,omitempty"`
+	After   []APILogLine `json:"after,omitempty"`
+}
+
+// Import transforms a LogSearchResult object into an APILogSearchResult
+// object.
+func (apiResult *APILogSearchResult) Import(i interface{}) error {
+	switch r := i.(type) {
+	case dbmodel.LogSearchResult:
+		apiResult.LogID = utility.ToStringPtr(r.LogID)
+		apiResult.Info = getLogInfo(r.Info)
+		apiResult.LineNum = r.LineNum
+		apiResult.Line = getLogLine(r.Line)
+		apiResult.Before = getLogLines(r.Before)
+		apiResult.After = getLogLines(r.After)
+	default:
+		return errors.New("incorrect type when converting LogSearchResult type")
+	}
+
+	return nil
+}
+
+// APILogFailureSignature describes a normalized failure signature found in
+// the tail of a buildlogger log.
+type APILogFailureSignature struct {
+	Rule      *string `json:"rule"`
+	Signature *string `json:"signature"`
+	Snippet   *string `json:"snippet"`
+	LineNum   int     `json:"line_num"`
+}
+
+func getLogFailureSignature(s dbmodel.LogFailureSignature) APILogFailureSignature {
+	return APILogFailureSignature{
+		Rule:      utility.ToStringPtr(s.Rule),
+		Signature: utility.ToStringPtr(s.Signature),
+		Snippet:   utility.ToStringPtr(s.Snippet),
+		LineNum:   s.LineNum,
+	}
+}
+
+// APILogFailureSignatureCount describes the number of buildlogger logs in
+// which a failure signature was found.
+type APILogFailureSignatureCount struct {
+	Rule         *string `json:"rule"`
+	Signature    *string `json:"signature"`
+	Count        int     `json:"count"`
+	LastSeen     APITime `json:"last_seen"`
+	LatestLogID  *string `json:"latest_log_id"`
+	LatestTaskID *string `json:"latest_task_id"`
+	Snippet      *string `json:"snippet"`
+}
+
+// Import transforms a LogFailureSignatureCount object into an
+// APILogFailureSignatureCount object.
+func (apiResult *APILogFailureSignatureCount) Import(i interface{}) error {
+	switch c := i.(type) {
+	case dbmodel.LogFailureSignatureCount:
+		apiResult.Rule = utility.ToStringPtr(c.Rule)
+		apiResult.Signature = utility.ToStringPtr(c.Signature)
+		apiResult.Count = c.Count
+		apiResult.LastSeen = NewTime(c.LastSeen)
+		apiResult.LatestLogID = utility.ToStringPtr(c.LatestLogID)
+		apiResult.LatestTaskID = utility.ToStringPtr(c.LatestTaskID)
+		apiResult.Snippet = utility.ToStringPtr(c.Snippet)
+	default:
+		return errors.New("incorrect type when converting LogFailureSignatureCount type")
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	dbmodel "github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/level"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -101,4 +102,102 @@ func TestBuildloggerImport(t *testing.T) {
 		assert.NoError(t, apiLog.Import(log))
 		assert.Equal(t, expected, apiLog)
 	})
+	t.Run("CompressedLog", func(t *testing.T) {
+		log := dbmodel.Log{
+			ID: "id",
+			Artifact: dbmodel.LogArtifactInfo{
+				Type:        dbmodel.PailS3,
+				Prefix:      "id",
+				Version:     2,
+				Compression: dbmodel.LogCompressionZstd,
+			},
+		}
+
+		apiLog := &APILog{}
+		assert.NoError(t, apiLog.Import(log))
+		assert.Equal(t, 2, apiLog.Artifact.Version)
+		assert.Equal(t, string(dbmodel.LogCompressionZstd), utility.FromStringPtr(apiLog.Artifact.Compression))
+	})
+	t.Run("FailureSignatures", func(t *testing.T) {
+		log := dbmodel.Log{
+			ID: "id",
+			FailureSignatures: []dbmodel.LogFailureSignature{
+				{Rule: "panic", Signature: "panic: nil map", Snippet: "panic: nil map", LineNum: 42},
+			},
+		}
+
+		apiLog := &APILog{}
+		assert.NoError(t, apiLog.Import(log))
+		assert.Equal(t, []APILogFailureSignature{
+			{
+				Rule:      utility.ToStringPtr("panic"),
+				Signature: utility.ToStringPtr("panic: nil map"),
+				Snippet:   utility.ToStringPtr("panic: nil map"),
+				LineNum:   42,
+			},
+		}, apiLog.FailureSignatures)
+	})
+}
+
+func TestLogSearchResultImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		apiResult := &APILogSearchResult{}
+		assert.Error(t, apiResult.Import(dbmodel.Log{}))
+	})
+	t.Run("ValidResult", func(t *testing.T) {
+		ts := time.Now().Round(time.Millisecond)
+		result := dbmodel.LogSearchResult{
+			LogID: "log",
+			Info: dbmodel.LogInfo{
+				Project: "project",
+				TaskID:  "task_id",
+			},
+			LineNum: 2,
+			Line:    dbmodel.LogLine{Priority: level.Error, Timestamp: ts, Data: "match\n"},
+			Before:  []dbmodel.LogLine{{Priority: level.Info, Timestamp: ts.Add(-time.Millisecond), Data: "before\n"}},
+		}
+		expected := &APILogSearchResult{
+			LogID:   utility.ToStringPtr("log"),
+			Info:    getLogInfo(result.Info),
+			LineNum: 2,
+			Line:    APILogLine{Priority: int(level.Error), Timestamp: NewTime(ts), Data: "match"},
+			Before:  []APILogLine{{Priority: int(level.Info), Timestamp: NewTime(ts.Add(-time.Millisecond)), Data: "before"}},
+			After:   []APILogLine{},
+		}
+
+		apiResult := &APILogSearchResult{}
+		assert.NoError(t, apiResult.Import(result))
+		assert.Equal(t, expected, apiResult)
+	})
+}
+
+func TestLogFailureSignatureCountImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		apiCount := &APILogFailureSignatureCount{}
+		assert.Error(t, apiCount.Import(dbmodel.Log{}))
+	})
+	t.Run("ValidCount", func(t *testing.T) {
+		count := dbmodel.LogFailureSignatureCount{
+			Rule:         "go_test_fail",
+			Signature:    "TestFoo",
+			Count:        3,
+			LastSeen:     time.Now().Round(time.Millisecond),
+			LatestLogID:  "log",
+			LatestTaskID: "task",
+			Snippet:      "--- FAIL: TestFoo (0.01s)",
+		}
+		expected := &APILogFailureSignatureCount{
+			Rule:         utility.ToStringPtr(count.Rule),
+			Signature:    utility.ToStringPtr(count.Signature),
+			Count:        3,
+			LastSeen:     NewTime(count.LastSeen),
+			LatestLogID:  utility.ToStringPtr(count.LatestLogID),
+			LatestTaskID: utility.ToStringPtr(count.LatestTaskID),
+			Snippet:      utility.ToStringPtr(count.Snippet),
+		}
+
+		apiCount := &APILogFailureSignatureCount{}
+		assert.NoError(t, apiCount.Import(count))
+		assert.Equal(t, expected, apiCount)
+	})
 }
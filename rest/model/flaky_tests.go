@@ -0,0 +1,52 @@
+package model
+
+import (
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIFlakyTest describes the flakiness of a test over a date range, along with
+// the daily trend.
+type APIFlakyTest struct {
+	TestName *string                  `json:"test_name"`
+	TaskName *string                  `json:"task_name"`
+	Variant  *string                  `json:"variant"`
+	NumFlaky int                      `json:"num_flaky"`
+	NumRuns  int                      `json:"num_runs"`
+	Score    float64                  `json:"score"`
+	Trend    []APIFlakyTestTrendPoint `json:"trend"`
+}
+
+// APIFlakyTestTrendPoint describes the flakiness of a test on a single day.
+type APIFlakyTestTrendPoint struct {
+	Date     APITime `json:"date"`
+	NumFlaky int     `json:"num_flaky"`
+	NumRuns  int     `json:"num_runs"`
+	Score    float64 `json:"score"`
+}
+
+// Import transforms an AggregatedFlakyTest object into an APIFlakyTest object.
+func (a *APIFlakyTest) Import(i interface{}) error {
+	switch ft := i.(type) {
+	case dbmodel.AggregatedFlakyTest:
+		a.TestName = utility.ToStringPtr(ft.TestName)
+		a.TaskName = utility.ToStringPtr(ft.TaskName)
+		a.Variant = utility.ToStringPtr(ft.Variant)
+		a.NumFlaky = ft.NumFlaky
+		a.NumRuns = ft.NumRuns
+		a.Score = ft.Score
+		a.Trend = make([]APIFlakyTestTrendPoint, len(ft.Trend))
+		for j, point := range ft.Trend {
+			a.Trend[j] = APIFlakyTestTrendPoint{
+				Date:     NewTime(point.Date),
+				NumFlaky: point.NumFlaky,
+				NumRuns:  point.NumRuns,
+				Score:    point.Score,
+			}
+		}
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIFlakyTest type", i)
+	}
+	return nil
+}
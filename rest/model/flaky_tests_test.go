@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlakyTestImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APIFlakyTest{}
+		assert.Error(t, api.Import(dbmodel.TestResults{}))
+	})
+	t.Run("ValidFlakyTest", func(t *testing.T) {
+		date := utility.GetUTCDay(time.Now())
+		ft := dbmodel.AggregatedFlakyTest{
+			TestName: "test_name",
+			TaskName: "task_name",
+			Variant:  "variant",
+			NumFlaky: 3,
+			NumRuns:  12,
+			Score:    0.25,
+			Trend: []dbmodel.FlakyTestTrendPoint{
+				{Date: date.AddDate(0, 0, -1), NumFlaky: 1, NumRuns: 8, Score: 0.125},
+				{Date: date, NumFlaky: 2, NumRuns: 4, Score: 0.5},
+			},
+		}
+		expected := &APIFlakyTest{
+			TestName: utility.ToStringPtr(ft.TestName),
+			TaskName: utility.ToStringPtr(ft.TaskName),
+			Variant:  utility.ToStringPtr(ft.Variant),
+			NumFlaky: ft.NumFlaky,
+			NumRuns:  ft.NumRuns,
+			Score:    ft.Score,
+			Trend: []APIFlakyTestTrendPoint{
+				{Date: NewTime(ft.Trend[0].Date), NumFlaky: 1, NumRuns: 8, Score: 0.125},
+				{Date: NewTime(ft.Trend[1].Date), NumFlaky: 2, NumRuns: 4, Score: 0.5},
+			},
+		}
+		api := &APIFlakyTest{}
+		assert.NoError(t, api.Import(ft))
+		assert.Equal(t, expected, api)
+	})
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIPerformanceChangePoint describes a change point detected in a
+// performance result series, along with its triage.
+type APIPerformanceChangePoint struct {
+	ID          *string                         `json:"id"`
+	Project     *string                         `json:"project"`
+	Variant     *string                         `json:"variant"`
+	Task        *string                         `json:"task"`
+	Test        *string                         `json:"test"`
+	Measurement *string                         `json:"measurement"`
+	Arguments   map[string]int32                `json:"args"`
+	Order       int                             `json:"order"`
+	Version     *string                         `json:"version"`
+	Algorithm   *string                         `json:"algorithm"`
+	Magnitude   float64                         `json:"magnitude"`
+	Confidence  float64                         `json:"confidence"`
+	DetectedAt  APITime                         `json:"detected_at"`
+	Triage      APIPerformanceChangePointTriage `json:"triage"`
+}
+
+// Import transforms a PerformanceChangePoint object into an
+// APIPerformanceChangePoint object.
+func (cp *APIPerformanceChangePoint) Import(i interface{}) error {
+	switch changePoint := i.(type) {
+	case dbmodel.PerformanceChangePoint:
+		cp.ID = utility.ToStringPtr(changePoint.ID)
+		cp.Project = utility.ToStringPtr(changePoint.SeriesID.Project)
+		cp.Variant = utility.ToStringPtr(changePoint.SeriesID.Variant)
+		cp.Task = utility.ToStringPtr(changePoint.SeriesID.Task)
+		cp.Test = utility.ToStringPtr(changePoint.SeriesID.Test)
+		cp.Measurement = utility.ToStringPtr(changePoint.SeriesID.Measurement)
+		cp.Arguments = changePoint.SeriesID.Arguments
+		cp.Order = changePoint.Order
+		cp.Version = utility.ToStringPtr(changePoint.Version)
+		cp.Algorithm = utility.ToStringPtr(changePoint.Algorithm)
+		cp.Magnitude = changePoint.Magnitude
+		cp.Confidence = changePoint.Confidence
+		cp.DetectedAt = NewTime(changePoint.DetectedAt)
+		if err := cp.Triage.Import(changePoint.Triage); err != nil {
+			return errors.Wrap(err, "converting change point triage")
+		}
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIPerformanceChangePoint type", i)
+	}
+	return nil
+}
+
+// APIPerformanceChangePointTriage describes how a user triaged a change
+// point.
+type APIPerformanceChangePointTriage struct {
+	Status    *string  `json:"status"`
+	TriagedBy *string  `json:"triaged_by"`
+	Notes     *string  `json:"notes"`
+	Tickets   []string `json:"tickets"`
+	TriagedAt APITime  `json:"triaged_at"`
+}
+
+// Import transforms a PerformanceChangePointTriage object into an
+// APIPerformanceChangePointTriage object.
+func (t *APIPerformanceChangePointTriage) Import(i interface{}) error {
+	switch triage := i.(type) {
+	case dbmodel.PerformanceChangePointTriage:
+		t.Status = utility.ToStringPtr(string(triage.Status))
+		t.TriagedBy = utility.ToStringPtr(triage.TriagedBy)
+		t.Notes = utility.ToStringPtr(triage.Notes)
+		t.Tickets = triage.Tickets
+		t.TriagedAt = NewTime(triage.TriagedAt)
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIPerformanceChangePointTriage type", i)
+	}
+	return nil
+}
+
+// Export transforms the APIPerformanceChangePointTriage object into a
+// PerformanceChangePointTriage object. The triage time is set when the
+// change point is triaged.
+func (t *APIPerformanceChangePointTriage) Export() (interface{}, error) {
+	return dbmodel.PerformanceChangePointTriage{
+		Status:    dbmodel.PerformanceChangePointTriageStatus(utility.FromStringPtr(t.Status)),
+		TriagedBy: utility.FromStringPtr(t.TriagedBy),
+		Notes:     utility.FromStringPtr(t.Notes),
+		Tickets:   t.Tickets,
+	}, nil
+}
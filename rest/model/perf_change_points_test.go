@@ -0,0 +1,76 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformanceChangePointImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APIPerformanceChangePoint{}
+		assert.Error(t, api.Import(dbmodel.PerformanceChangePointTriage{}))
+	})
+	t.Run("ValidChangePoint", func(t *testing.T) {
+		now := time.Now().UTC().Round(time.Millisecond)
+		id := dbmodel.PerformanceResultSeriesID{
+			Project:     "project",
+			Variant:     "variant",
+			Task:        "task",
+			Test:        "test",
+			Measurement: "ops_per_sec",
+			Arguments:   dbmodel.PerformanceArguments{"thread_level": 20},
+		}
+		changePoint := dbmodel.PerformanceChangePoint{
+			ID:         dbmodel.PerformanceChangePointID(id, 10),
+			SeriesID:   id,
+			Order:      10,
+			Version:    "version",
+			Algorithm:  "e_divisive_means",
+			Magnitude:  -12.5,
+			Confidence: 0.99,
+			DetectedAt: now.Add(-time.Hour),
+			Triage: dbmodel.PerformanceChangePointTriage{
+				Status:    dbmodel.PerformanceChangePointLinked,
+				TriagedBy: "user",
+				Notes:     "regression",
+				Tickets:   []string{"PERF-1"},
+				TriagedAt: now,
+			},
+		}
+		expected := &APIPerformanceChangePoint{
+			ID:          utility.ToStringPtr(changePoint.ID),
+			Project:     utility.ToStringPtr(id.Project),
+			Variant:     utility.ToStringPtr(id.Variant),
+			Task:        utility.ToStringPtr(id.Task),
+			Test:        utility.ToStringPtr(id.Test),
+			Measurement: utility.ToStringPtr(id.Measurement),
+			Arguments:   id.Arguments,
+			Order:       10,
+			Version:     utility.ToStringPtr(changePoint.Version),
+			Algorithm:   utility.ToStringPtr(changePoint.Algorithm),
+			Magnitude:   changePoint.Magnitude,
+			Confidence:  changePoint.Confidence,
+			DetectedAt:  NewTime(changePoint.DetectedAt),
+			Triage: APIPerformanceChangePointTriage{
+				Status:    utility.ToStringPtr(string(dbmodel.PerformanceChangePointLinked)),
+				TriagedBy: utility.ToStringPtr("user"),
+				Notes:     utility.ToStringPtr("regression"),
+				Tickets:   []string{"PERF-1"},
+				TriagedAt: NewTime(now),
+			},
+		}
+		api := &APIPerformanceChangePoint{}
+		require.NoError(t, api.Import(changePoint))
+		assert.Equal(t, expected, api)
+
+		exported, err := api.Triage.Export()
+		require.NoError(t, err)
+		changePoint.Triage.TriagedAt = time.Time{}
+		assert.Equal(t, changePoint.Triage, exported)
+	})
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIPerformanceComparison describes the comparison of the rollups of the
+// performance results of two versions or two tasks.
+type APIPerformanceComparison struct {
+	BaseVersion *string                        `json:"base_version"`
+	Version     *string                        `json:"version"`
+	BaseTaskID  *string                        `json:"base_task_id"`
+	TaskID      *string                        `json:"task_id"`
+	Tests       []APIPerformanceTestComparison `json:"tests"`
+}
+
+// Import transforms a PerformanceComparison object into an
+// APIPerformanceComparison object.
+func (c *APIPerformanceComparison) Import(i interface{}) error {
+	switch comparison := i.(type) {
+	case dbmodel.PerformanceComparison:
+		c.BaseVersion = utility.ToStringPtr(comparison.BaseVersion)
+		c.Version = utility.ToStringPtr(comparison.Version)
+		c.BaseTaskID = utility.ToStringPtr(comparison.BaseTaskID)
+		c.TaskID = utility.ToStringPtr(comparison.TaskID)
+		c.Tests = make([]APIPerformanceTestComparison, len(comparison.Tests))
+		for j, test := range comparison.Tests {
+			if err := c.Tests[j].Import(test); err != nil {
+				return errors.Wrapf(err, "converting comparison of test '%s'", test.TestName)
+			}
+		}
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIPerformanceComparison type", i)
+	}
+	return nil
+}
+
+// APIPerformanceTestComparison describes the comparison of the rollups of
+// each trial of a single test and of the trials as a whole.
+type APIPerformanceTestComparison struct {
+	Variant   *string                          `json:"variant"`
+	TaskName  *string                          `json:"task_name"`
+	TestName  *string                          `json:"test_name"`
+	Arguments map[string]int32                 `json:"args"`
+	Trials    []APIPerformanceTrialComparison  `json:"trials"`
+	Rollups   []APIPerformanceRollupComparison `json:"rollups"`
+}
+
+// Import transforms a PerformanceTestComparison object into an
+// APIPerformanceTestComparison object.
+func (c *APIPerformanceTestComparison) Import(i interface{}) error {
+	switch comparison := i.(type) {
+	case dbmodel.PerformanceTestComparison:
+		c.Variant = utility.ToStringPtr(comparison.Variant)
+		c.TaskName = utility.ToStringPtr(comparison.TaskName)
+		c.TestName = utility.ToStringPtr(comparison.TestName)
+		c.Arguments = comparison.Arguments
+		c.Trials = make([]APIPerformanceTrialComparison, len(comparison.Trials))
+		for j, trial := range comparison.Trials {
+			if err := c.Trials[j].Import(trial); err != nil {
+				return errors.Wrapf(err, "converting comparison of trial %d", trial.Trial)
+			}
+		}
+		rollups, err := importAPIPerformanceRollupComparisons(comparison.Rollups)
+		if err != nil {
+			return errors.Wrap(err, "converting rollup comparisons")
+		}
+		c.Rollups = rollups
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIPerformanceTestComparison type", i)
+	}
+	return nil
+}
+
+// APIPerformanceTrialComparison describes the comparison of the rollups of a
+// single trial of a test.
+type APIPerformanceTrialComparison struct {
+	Trial   int                              `json:"trial"`
+	BaseID  *string                          `json:"base_id"`
+	ID      *string                          `json:"id"`
+	Rollups []APIPerformanceRollupComparison `json:"rollups"`
+}
+
+// Import transforms a PerformanceTrialComparison object into an
+// APIPerformanceTrialComparison object.
+func (c *APIPerformanceTrialComparison) Import(i interface{}) error {
+	switch comparison := i.(type) {
+	case dbmodel.PerformanceTrialComparison:
+		c.Trial = comparison.Trial
+		c.BaseID = utility.ToStringPtr(comparison.BaseID)
+		c.ID = utility.ToStringPtr(comparison.ID)
+		rollups, err := importAPIPerformanceRollupComparisons(comparison.Rollups)
+		if err != nil {
+			return errors.Wrap(err, "converting rollup comparisons")
+		}
+		c.Rollups = rollups
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIPerformanceTrialComparison type", i)
+	}
+	return nil
+}
+
+// APIPerformanceRollupComparison describes the difference in the value of a
+// rollup between the base and the compared performance results.
+type APIPerformanceRollupComparison struct {
+	Name         *string  `json:"name"`
+	BaseValue    *float64 `json:"base_value"`
+	Value        *float64 `json:"value"`
+	Delta        *float64 `json:"delta"`
+	PercentDelta *float64 `json:"percent_delta"`
+	BaseCount    int      `json:"base_count"`
+	Count        int      `json:"count"`
+	PValue       *float64 `json:"p_value"`
+	Significant  bool     `json:"significant"`
+}
+
+// Import transforms a PerformanceRollupComparison object into an
+// APIPerformanceRollupComparison object.
+func (d *APIPerformanceRollupComparison) Import(i interface{}) error {
+	switch comparison := i.(type) {
+	case dbmodel.PerformanceRollupComparison:
+		d.Name = utility.ToStringPtr(comparison.Name)
+		d.BaseValue = comparison.BaseValue
+		d.Value = comparison.Value
+		d.Delta = comparison.Delta
+		d.PercentDelta = comparison.PercentDelta
+		d.BaseCount = comparison.BaseCount
+		d.Count = comparison.Count
+		d.PValue = comparison.PValue
+		d.Significant = comparison.Significant
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIPerformanceRollupComparison type", i)
+	}
+	return nil
+}
+
+func importAPIPerformanceRollupComparisons(comparisons []dbmodel.PerformanceRollupComparison) ([]APIPerformanceRollupComparison, error) {
+	apiComparisons := make([]APIPerformanceRollupComparison, len(comparisons))
+	for i, comparison := range comparisons {
+		if err := apiComparisons[i].Import(comparison); err != nil {
+			return nil, errors.Wrapf(err, "converting comparison of rollup '%s'", comparison.Name)
+		}
+	}
+
+	return apiComparisons, nil
+}
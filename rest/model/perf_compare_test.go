@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformanceComparisonImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APIPerformanceComparison{}
+		assert.Error(t, api.Import(dbmodel.PerformanceResult{}))
+	})
+	t.Run("ValidComparison", func(t *testing.T) {
+		baseValue, value, delta, percentDelta, pValue := 100.0, 150.0, 50.0, 50.0, 0.001
+		rollup := dbmodel.PerformanceRollupComparison{
+			Name:         "ops_per_sec",
+			BaseValue:    &baseValue,
+			Value:        &value,
+			Delta:        &delta,
+			PercentDelta: &percentDelta,
+			BaseCount:    3,
+			Count:        3,
+			PValue:       &pValue,
+			Significant:  true,
+		}
+		comparison := dbmodel.PerformanceComparison{
+			BaseVersion: "base",
+			Version:     "patch",
+			Tests: []dbmodel.PerformanceTestComparison{
+				{
+					Variant:   "variant",
+					TaskName:  "task",
+					TestName:  "test",
+					Arguments: dbmodel.PerformanceArguments{"thread_level": 8},
+					Trials: []dbmodel.PerformanceTrialComparison{
+						{
+							Trial:  1,
+							BaseID: "base_id",
+							Rollups: []dbmodel.PerformanceRollupComparison{
+								{Name: "ops_per_sec", BaseValue: &baseValue, BaseCount: 1},
+							},
+						},
+					},
+					Rollups: []dbmodel.PerformanceRollupComparison{rollup},
+				},
+			},
+		}
+
+		api := &APIPerformanceComparison{}
+		require.NoError(t, api.Import(comparison))
+		assert.Equal(t, &APIPerformanceComparison{
+			BaseVersion: utility.ToStringPtr("base"),
+			Version:     utility.ToStringPtr("patch"),
+			BaseTaskID:  utility.ToStringPtr(""),
+			TaskID:      utility.ToStringPtr(""),
+			Tests: []APIPerformanceTestComparison{
+				{
+					Variant:   utility.ToStringPtr("variant"),
+					TaskName:  utility.ToStringPtr("task"),
+					TestName:  utility.ToStringPtr("test"),
+					Arguments: map[string]int32{"thread_level": 8},
+					Trials: []APIPerformanceTrialComparison{
+						{
+							Trial:  1,
+							BaseID: utility.ToStringPtr("base_id"),
+							ID:     utility.ToStringPtr(""),
+							Rollups: []APIPerformanceRollupComparison{
+								{Name: utility.ToStringPtr("ops_per_sec"), BaseValue: &baseValue, BaseCount: 1},
+							},
+						},
+					},
+					Rollups: []APIPerformanceRollupComparison{
+						{
+							Name:         utility.ToStringPtr("ops_per_sec"),
+							BaseValue:    &baseValue,
+							Value:        &value,
+							Delta:        &delta,
+							PercentDelta: &percentDelta,
+							BaseCount:    3,
+							Count:        3,
+							PValue:       &pValue,
+							Significant:  true,
+						},
+					},
+				},
+			},
+		}, api)
+	})
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIPerformanceTimeSeriesPoint describes the value of a measurement in a
+// single performance result of a performance result series.
+type APIPerformanceTimeSeriesPoint struct {
+	PerformanceResultID *string `json:"perf_result_id"`
+	Order               int     `json:"order"`
+	Version             *string `json:"version"`
+	TaskID              *string `json:"task_id"`
+	Execution           int     `json:"execution"`
+	Value               float64 `json:"value"`
+	CreatedAt           APITime `json:"created_at"`
+}
+
+// Import transforms a PerformanceTimeSeriesPoint object into an
+// APIPerformanceTimeSeriesPoint object.
+func (p *APIPerformanceTimeSeriesPoint) Import(i interface{}) error {
+	switch point := i.(type) {
+	case dbmodel.PerformanceTimeSeriesPoint:
+		p.PerformanceResultID = utility.ToStringPtr(point.PerformanceResultID)
+		p.Order = point.Order
+		p.Version = utility.ToStringPtr(point.Version)
+		p.TaskID = utility.ToStringPtr(point.TaskID)
+		p.Execution = point.Execution
+		p.Value = point.Value
+		p.CreatedAt = NewTime(point.CreatedAt)
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIPerformanceTimeSeriesPoint type", i)
+	}
+	return nil
+}
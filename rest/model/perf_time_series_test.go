@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformanceTimeSeriesPointImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APIPerformanceTimeSeriesPoint{}
+		assert.Error(t, api.Import(dbmodel.PerformanceResult{}))
+	})
+	t.Run("ValidPoint", func(t *testing.T) {
+		point := dbmodel.PerformanceTimeSeriesPoint{
+			PerformanceResultID: "id",
+			Order:               10,
+			Version:             "version",
+			TaskID:              "task",
+			Execution:           1,
+			Value:               12.5,
+			CreatedAt:           time.Now().UTC().Round(time.Millisecond),
+		}
+		api := &APIPerformanceTimeSeriesPoint{}
+		require.NoError(t, api.Import(point))
+		assert.Equal(t, &APIPerformanceTimeSeriesPoint{
+			PerformanceResultID: utility.ToStringPtr(point.PerformanceResultID),
+			Order:               point.Order,
+			Version:             utility.ToStringPtr(point.Version),
+			TaskID:              utility.ToStringPtr(point.TaskID),
+			Execution:           point.Execution,
+			Value:               point.Value,
+			CreatedAt:           NewTime(point.CreatedAt),
+		}, api)
+	})
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APITestAnnotation describes a note, ticket links, and a quarantine flag
+// attached to a test. An empty variant or task name applies the annotation
+// to the test in all variants or tasks of the project, respectively.
+type APITestAnnotation struct {
+	ID            *string  `json:"id"`
+	Project       *string  `json:"project"`
+	Variant       *string  `json:"variant"`
+	TaskName      *string  `json:"task_name"`
+	TestName      *string  `json:"test_name"`
+	Note          *string  `json:"note"`
+	Tickets       []string `json:"tickets"`
+	Quarantined   bool     `json:"quarantined"`
+	ExpiresAt     APITime  `json:"expires_at"`
+	CreatedBy     *string  `json:"created_by"`
+	CreatedAt     APITime  `json:"created_at"`
+	LastUpdatedBy *string  `json:"last_updated_by"`
+	LastUpdate    APITime  `json:"last_update"`
+}
+
+// Import transforms a TestAnnotation object into an APITestAnnotation object.
+func (a *APITestAnnotation) Import(i interface{}) error {
+	switch annotation := i.(type) {
+	case dbmodel.TestAnnotation:
+		a.ID = utility.ToStringPtr(annotation.ID)
+		a.Project = utility.ToStringPtr(annotation.Project)
+		a.Variant = utility.ToStringPtr(annotation.Variant)
+		a.TaskName = utility.ToStringPtr(annotation.TaskName)
+		a.TestName = utility.ToStringPtr(annotation.TestName)
+		a.Note = utility.ToStringPtr(annotation.Note)
+		a.Tickets = annotation.Tickets
+		a.Quarantined = annotation.Quarantined
+		a.ExpiresAt = NewTime(annotation.ExpiresAt)
+		a.CreatedBy = utility.ToStringPtr(annotation.CreatedBy)
+		a.CreatedAt = NewTime(annotation.CreatedAt)
+		a.LastUpdatedBy = utility.ToStringPtr(annotation.LastUpdatedBy)
+		a.LastUpdate = NewTime(annotation.LastUpdate)
+	default:
+		return errors.Errorf("incorrect type %T when converting to APITestAnnotation type", i)
+	}
+	return nil
+}
+
+// Export transforms the APITestAnnotation object into a TestAnnotation
+// object. The ID, creation, and last update fields are set when the
+// TestAnnotation is saved.
+func (a *APITestAnnotation) Export() (interface{}, error) {
+	return dbmodel.TestAnnotation{
+		Project:       utility.FromStringPtr(a.Project),
+		Variant:       utility.FromStringPtr(a.Variant),
+		TaskName:      utility.FromStringPtr(a.TaskName),
+		TestName:      utility.FromStringPtr(a.TestName),
+		Note:          utility.FromStringPtr(a.Note),
+		Tickets:       a.Tickets,
+		Quarantined:   a.Quarantined,
+		ExpiresAt:     time.Time(a.ExpiresAt),
+		LastUpdatedBy: utility.FromStringPtr(a.LastUpdatedBy),
+	}, nil
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestAnnotationImportExport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APITestAnnotation{}
+		assert.Error(t, api.Import(dbmodel.TestOwnership{}))
+	})
+	t.Run("ValidTestAnnotation", func(t *testing.T) {
+		now := time.Now().UTC().Round(time.Millisecond)
+		annotation := dbmodel.TestAnnotation{
+			ID:            dbmodel.TestAnnotationID("project", "variant", "task", "TestFlaky"),
+			Project:       "project",
+			Variant:       "variant",
+			TaskName:      "task",
+			TestName:      "TestFlaky",
+			Note:          "flaky on this variant",
+			Tickets:       []string{"BF-1", "BF-2"},
+			Quarantined:   true,
+			ExpiresAt:     now.Add(24 * time.Hour),
+			CreatedBy:     "creator",
+			CreatedAt:     now.Add(-time.Hour),
+			LastUpdatedBy: "user",
+			LastUpdate:    now,
+		}
+		expected := &APITestAnnotation{
+			ID:            utility.ToStringPtr(annotation.ID),
+			Project:       utility.ToStringPtr(annotation.Project),
+			Variant:       utility.ToStringPtr(annotation.Variant),
+			TaskName:      utility.ToStringPtr(annotation.TaskName),
+			TestName:      utility.ToStringPtr(annotation.TestName),
+			Note:          utility.ToStringPtr(annotation.Note),
+			Tickets:       annotation.Tickets,
+			Quarantined:   true,
+			ExpiresAt:     NewTime(annotation.ExpiresAt),
+			CreatedBy:     utility.ToStringPtr(annotation.CreatedBy),
+			CreatedAt:     NewTime(annotation.CreatedAt),
+			LastUpdatedBy: utility.ToStringPtr(annotation.LastUpdatedBy),
+			LastUpdate:    NewTime(annotation.LastUpdate),
+		}
+		api := &APITestAnnotation{}
+		require.NoError(t, api.Import(annotation))
+		assert.Equal(t, expected, api)
+
+		exported, err := api.Export()
+		require.NoError(t, err)
+		annotation.ID = ""
+		annotation.CreatedBy = ""
+		annotation.CreatedAt = time.Time{}
+		annotation.LastUpdate = time.Time{}
+		assert.Equal(t, annotation, exported)
+	})
+}
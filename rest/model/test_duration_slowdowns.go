@@ -0,0 +1,53 @@
+package model
+
+import (
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APITestDurationSlowdown describes a statistically significant increase in
+// the duration of a mainline test, detected on a given day.
+type APITestDurationSlowdown struct {
+	TestName *string              `json:"test_name"`
+	TaskName *string              `json:"task_name"`
+	Variant  *string              `json:"variant"`
+	Date     APITime              `json:"date"`
+	Baseline APITestDurationStats `json:"baseline"`
+	Recent   APITestDurationStats `json:"recent"`
+	PValue   float64              `json:"p_value"`
+}
+
+// APITestDurationStats describes the distribution of a test's durations. The
+// durations are in seconds.
+type APITestDurationStats struct {
+	NumRuns int     `json:"num_runs"`
+	Median  float64 `json:"median"`
+	P90     float64 `json:"p90"`
+}
+
+// Import transforms a TestDurationSlowdown object into an
+// APITestDurationSlowdown object.
+func (a *APITestDurationSlowdown) Import(i interface{}) error {
+	switch slowdown := i.(type) {
+	case dbmodel.TestDurationSlowdown:
+		a.TestName = utility.ToStringPtr(slowdown.Info.TestName)
+		a.TaskName = utility.ToStringPtr(slowdown.Info.TaskName)
+		a.Variant = utility.ToStringPtr(slowdown.Info.Variant)
+		a.Date = NewTime(slowdown.Info.Date)
+		a.Baseline = APITestDurationStats{
+			NumRuns: slowdown.Baseline.NumRuns,
+			Median:  slowdown.Baseline.Median.Seconds(),
+			P90:     slowdown.Baseline.P90.Seconds(),
+		}
+		a.Recent = APITestDurationStats{
+			NumRuns: slowdown.Recent.NumRuns,
+			Median:  slowdown.Recent.Median.Seconds(),
+			P90:     slowdown.Recent.P90.Seconds(),
+		}
+		a.PValue = slowdown.PValue
+	default:
+		return errors.Errorf("incorrect type %T when converting to APITestDurationSlowdown type", i)
+	}
+	return nil
+}
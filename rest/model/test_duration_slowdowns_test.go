@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestDurationSlowdownImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APITestDurationSlowdown{}
+		assert.Error(t, api.Import(dbmodel.TestResults{}))
+	})
+	t.Run("ValidTestDurationSlowdown", func(t *testing.T) {
+		date := utility.GetUTCDay(time.Now())
+		slowdown := dbmodel.TestDurationSlowdown{
+			Info: dbmodel.TestDurationSlowdownInfo{
+				Project:  "project",
+				Variant:  "variant",
+				TaskName: "task_name",
+				TestName: "test_name",
+				Date:     date,
+			},
+			Baseline: dbmodel.TestDurationStats{NumRuns: 10, Median: time.Second, P90: 1500 * time.Millisecond},
+			Recent:   dbmodel.TestDurationStats{NumRuns: 5, Median: 2 * time.Second, P90: 2500 * time.Millisecond},
+			PValue:   0.002,
+		}
+		expected := &APITestDurationSlowdown{
+			TestName: utility.ToStringPtr("test_name"),
+			TaskName: utility.ToStringPtr("task_name"),
+			Variant:  utility.ToStringPtr("variant"),
+			Date:     NewTime(date),
+			Baseline: APITestDurationStats{NumRuns: 10, Median: 1, P90: 1.5},
+			Recent:   APITestDurationStats{NumRuns: 5, Median: 2, P90: 2.5},
+			PValue:   0.002,
+		}
+
+		api := &APITestDurationSlowdown{}
+		assert.NoError(t, api.Import(slowdown))
+		assert.Equal(t, expected, api)
+	})
+}
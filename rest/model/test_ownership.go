@@ -0,0 +1,123 @@
+package model
+
+import (
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APITestOwnership describes the owner and team of the tests of a project
+// whose display names match a test name pattern.
+type APITestOwnership struct {
+	ID              *string `json:"id"`
+	Project         *string `json:"project"`
+	TestNamePattern *string `json:"test_name_pattern"`
+	Owner           *string `json:"owner"`
+	Team            *string `json:"team"`
+	LastUpdatedBy   *string `json:"last_updated_by"`
+	LastUpdate      APITime `json:"last_update"`
+}
+
+// Import transforms a TestOwnership object into an APITestOwnership object.
+func (a *APITestOwnership) Import(i interface{}) error {
+	switch ownership := i.(type) {
+	case dbmodel.TestOwnership:
+		a.ID = utility.ToStringPtr(ownership.ID)
+		a.Project = utility.ToStringPtr(ownership.Project)
+		a.TestNamePattern = utility.ToStringPtr(ownership.TestNamePattern)
+		a.Owner = utility.ToStringPtr(ownership.Owner)
+		a.Team = utility.ToStringPtr(ownership.Team)
+		a.LastUpdatedBy = utility.ToStringPtr(ownership.LastUpdatedBy)
+		a.LastUpdate = NewTime(ownership.LastUpdate)
+	default:
+		return errors.Errorf("incorrect type %T when converting to APITestOwnership type", i)
+	}
+	return nil
+}
+
+// Export transforms the APITestOwnership object into a TestOwnership object.
+// The ID and last update fields are set when the TestOwnership is saved.
+func (a *APITestOwnership) Export() (interface{}, error) {
+	return dbmodel.TestOwnership{
+		Project:         utility.FromStringPtr(a.Project),
+		TestNamePattern: utility.FromStringPtr(a.TestNamePattern),
+		Owner:           utility.FromStringPtr(a.Owner),
+		Team:            utility.FromStringPtr(a.Team),
+		LastUpdatedBy:   utility.FromStringPtr(a.LastUpdatedBy),
+	}, nil
+}
+
+// APITestOwner describes the owner and team of a single test.
+type APITestOwner struct {
+	Owner *string `json:"owner,omitempty"`
+	Team  *string `json:"team,omitempty"`
+}
+
+func newAPITestOwner(owner, team string) APITestOwner {
+	var apiOwner APITestOwner
+	if owner != "" {
+		apiOwner.Owner = utility.ToStringPtr(owner)
+	}
+	if team != "" {
+		apiOwner.Team = utility.ToStringPtr(team)
+	}
+	return apiOwner
+}
+
+// APIFailedTestSample describes a test name from the failed tests sample of
+// a task along with its owner and team, if any, and whether the test is
+// quarantined.
+type APIFailedTestSample struct {
+	TestName *string `json:"test_name"`
+	APITestOwner
+	Quarantined bool `json:"quarantined,omitempty"`
+}
+
+// NewAPIFailedTestSample returns an APIFailedTestSample for the given test
+// name and its ownership, which may be nil.
+func NewAPIFailedTestSample(testName string, ownership *dbmodel.TestOwnership) APIFailedTestSample {
+	sample := APIFailedTestSample{TestName: utility.ToStringPtr(testName)}
+	if ownership != nil {
+		sample.APITestOwner = newAPITestOwner(ownership.Owner, ownership.Team)
+	}
+	return sample
+}
+
+// APITeamTestFailures describes the failed tests of a version owned by a
+// team. Failed tests without an owner are reported under an empty team.
+type APITeamTestFailures struct {
+	Team        *string                `json:"team"`
+	NumFailures int                    `json:"num_failures"`
+	FailedTests []APIOwnedTestFailures `json:"failed_tests"`
+}
+
+// APIOwnedTestFailures describes the failures of a single test and its
+// owner.
+type APIOwnedTestFailures struct {
+	TestName    *string `json:"test_name"`
+	Owner       *string `json:"owner,omitempty"`
+	NumFailures int     `json:"num_failures"`
+}
+
+// Import transforms a TeamTestFailures object into an APITeamTestFailures
+// object.
+func (a *APITeamTestFailures) Import(i interface{}) error {
+	switch failures := i.(type) {
+	case dbmodel.TeamTestFailures:
+		a.Team = utility.ToStringPtr(failures.Team)
+		a.NumFailures = failures.NumFailures
+		a.FailedTests = make([]APIOwnedTestFailures, len(failures.FailedTests))
+		for j, test := range failures.FailedTests {
+			a.FailedTests[j] = APIOwnedTestFailures{
+				TestName:    utility.ToStringPtr(test.TestName),
+				NumFailures: test.NumFailures,
+			}
+			if test.Owner != "" {
+				a.FailedTests[j].Owner = utility.ToStringPtr(test.Owner)
+			}
+		}
+	default:
+		return errors.Errorf("incorrect type %T when converting to APITeamTestFailures type", i)
+	}
+	return nil
+}
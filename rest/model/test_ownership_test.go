@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestOwnershipImportExport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APITestOwnership{}
+		assert.Error(t, api.Import(dbmodel.TestResults{}))
+	})
+	t.Run("ValidTestOwnership", func(t *testing.T) {
+		ownership := dbmodel.TestOwnership{
+			ID:              dbmodel.TestOwnershipID("project", "^TestStorage"),
+			Project:         "project",
+			TestNamePattern: "^TestStorage",
+			Owner:           "owner",
+			Team:            "team",
+			LastUpdatedBy:   "user",
+			LastUpdate:      time.Now().Round(time.Millisecond),
+		}
+		expected := &APITestOwnership{
+			ID:              utility.ToStringPtr(ownership.ID),
+			Project:         utility.ToStringPtr(ownership.Project),
+			TestNamePattern: utility.ToStringPtr(ownership.TestNamePattern),
+			Owner:           utility.ToStringPtr(ownership.Owner),
+			Team:            utility.ToStringPtr(ownership.Team),
+			LastUpdatedBy:   utility.ToStringPtr(ownership.LastUpdatedBy),
+			LastUpdate:      NewTime(ownership.LastUpdate),
+		}
+		api := &APITestOwnership{}
+		require.NoError(t, api.Import(ownership))
+		assert.Equal(t, expected, api)
+
+		exported, err := api.Export()
+		require.NoError(t, err)
+		ownership.ID = ""
+		ownership.LastUpdate = time.Time{}
+		assert.Equal(t, ownership, exported)
+	})
+}
+
+func TestNewAPIFailedTestSample(t *testing.T) {
+	assert.Equal(t, APIFailedTestSample{TestName: utility.ToStringPtr("test")}, NewAPIFailedTestSample("test", nil))
+	assert.Equal(t, APIFailedTestSample{
+		TestName:     utility.ToStringPtr("test"),
+		APITestOwner: APITestOwner{Team: utility.ToStringPtr("team")},
+	}, NewAPIFailedTestSample("test", &dbmodel.TestOwnership{Team: "team"}))
+}
+
+func TestTeamTestFailuresImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APITeamTestFailures{}
+		assert.Error(t, api.Import(dbmodel.TestResults{}))
+	})
+	t.Run("ValidTeamTestFailures", func(t *testing.T) {
+		failures := dbmodel.TeamTestFailures{
+			Team:        "team",
+			NumFailures: 3,
+			FailedTests: []dbmodel.OwnedTestFailures{
+				{TestName: "test1", Owner: "owner", NumFailures: 2},
+				{TestName: "test2", NumFailures: 1},
+			},
+		}
+		expected := &APITeamTestFailures{
+			Team:        utility.ToStringPtr("team"),
+			NumFailures: 3,
+			FailedTests: []APIOwnedTestFailures{
+				{TestName: utility.ToStringPtr("test1"), Owner: utility.ToStringPtr("owner"), NumFailures: 2},
+				{TestName: utility.ToStringPtr("test2"), NumFailures: 1},
+			},
+		}
+		api := &APITeamTestFailures{}
+		require.NoError(t, api.Import(failures))
+		assert.Equal(t, expected, api)
+	})
+}
@@ -22,6 +22,8 @@ type APITestResult struct {
 	Trial           int     `json:"trial"`
 	Status          *string `json:"status"`
 	BaseStatus      *string `json:"base_status,omitempty"`
+	Owner           *string `json:"owner,omitempty"`
+	Team            *string `json:"team,omitempty"`
 	LogTestName     *string `json:"log_test_name,omitempty"`
 	LogURL          *string `json:"log_url,omitempty"`
 	RawLogURL       *string `json:"raw_log_url,omitempty"`
@@ -29,6 +31,10 @@ type APITestResult struct {
 	TaskCreateTime  APITime `json:"task_create_time"`
 	TestStartTime   APITime `json:"test_start_time"`
 	TestEndTime     APITime `json:"test_end_time"`
+	FailureMessage  *string `json:"failure_message,omitempty"`
+	StackTrace      *string `json:"stack_trace,omitempty"`
+	// Attributes are arbitrary key-value pairs describing the test run.
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 // Import transforms a TestResult object into an APITestResult object.
@@ -49,6 +55,12 @@ func (a *APITestResult) Import(i interface{}) error {
 		if tr.BaseStatus != "" {
 			a.BaseStatus = utility.ToStringPtr(tr.BaseStatus)
 		}
+		if tr.Owner != "" {
+			a.Owner = utility.ToStringPtr(tr.Owner)
+		}
+		if tr.Team != "" {
+			a.Team = utility.ToStringPtr(tr.Team)
+		}
 		if tr.LogTestName != "" {
 			a.LogTestName = utility.ToStringPtr(tr.LogTestName)
 		}
@@ -62,6 +74,15 @@ func (a *APITestResult) Import(i interface{}) error {
 		a.TaskCreateTime = NewTime(tr.TaskCreateTime)
 		a.TestStartTime = NewTime(tr.TestStartTime)
 		a.TestEndTime = NewTime(tr.TestEndTime)
+		if tr.FailureMessage != "" {
+			a.FailureMessage = utility.ToStringPtr(tr.FailureMessage)
+		}
+		if tr.StackTrace != "" {
+			a.StackTrace = utility.ToStringPtr(tr.StackTrace)
+		}
+		if len(tr.Attributes) > 0 {
+			a.Attributes = tr.Attributes
+		}
 	default:
 		return errors.Errorf("incorrect type %T when converting to APITestResult type", i)
 	}
@@ -71,9 +92,10 @@ func (a *APITestResult) Import(i interface{}) error {
 
 // APITTestResultsStats describes basic stats for a group of test results.
 type APITestResultsStats struct {
-	TotalCount    int  `json:"total_count"`
-	FailedCount   int  `json:"failed_count"`
-	FilteredCount *int `json:"filtered_count,omitempty"`
+	TotalCount             int  `json:"total_count"`
+	FailedCount            int  `json:"failed_count"`
+	QuarantinedFailedCount int  `json:"quarantined_failed_count"`
+	FilteredCount          *int `json:"filtered_count,omitempty"`
 }
 
 // Import transforms a TestResultsStats object into an APITestResultsStats
@@ -83,6 +105,7 @@ func (a *APITestResultsStats) Import(i interface{}) error {
 	case dbModel.TestResultsStats:
 		a.TotalCount = stats.TotalCount
 		a.FailedCount = stats.FailedCount
+		a.QuarantinedFailedCount = stats.QuarantinedFailedCount
 	case int:
 		a.FilteredCount = utility.ToIntPtr(stats)
 	default:
@@ -92,12 +115,21 @@ func (a *APITestResultsStats) Import(i interface{}) error {
 	return nil
 }
 
+// APITestResultsUpload describes the test results record created from an
+// uploaded test results report.
+type APITestResultsUpload struct {
+	TestResultsRecordID *string             `json:"test_results_record_id"`
+	Stats               APITestResultsStats `json:"stats"`
+}
+
 // APITestResultsSample is a sample of test names for a given task and execution.
 type APITestResultsSample struct {
-	TaskID                  *string  `json:"task_id"`
-	Execution               int      `json:"execution"`
-	MatchingFailedTestNames []string `json:"matching_failed_test_names"`
-	TotalFailedNames        int      `json:"total_failed_names"`
+	TaskID                     *string                 `json:"task_id"`
+	Execution                  int                     `json:"execution"`
+	MatchingFailedTestNames    []string                `json:"matching_failed_test_names"`
+	TotalFailedNames           int                     `json:"total_failed_names"`
+	TestOwners                 map[string]APITestOwner `json:"test_owners,omitempty"`
+	QuarantinedFailedTestNames []string                `json:"quarantined_failed_test_names,omitempty"`
 }
 
 // Import transforms a TestResultsSample object into an APITestResultsSample
@@ -109,9 +141,142 @@ func (a *APITestResultsSample) Import(i interface{}) error {
 		a.Execution = sample.Execution
 		a.MatchingFailedTestNames = sample.MatchingFailedTestNames
 		a.TotalFailedNames = sample.TotalFailedTestNames
+		if len(sample.TestOwners) > 0 {
+			a.TestOwners = make(map[string]APITestOwner, len(sample.TestOwners))
+			for name, ownership := range sample.TestOwners {
+				a.TestOwners[name] = newAPITestOwner(ownership.Owner, ownership.Team)
+			}
+		}
+		a.QuarantinedFailedTestNames = sample.QuarantinedFailedTestNames
 	default:
 		return errors.Errorf("incorrect type %T when converting to APITestResultsSample type", i)
 	}
 
 	return nil
 }
+
+// APITestResultsDiff describes the differences between a set of test results
+// and a base set of test results.
+type APITestResultsDiff struct {
+	NewlyFailing    []APITestResultDiff `json:"newly_failing"`
+	NewlyPassing    []APITestResultDiff `json:"newly_passing"`
+	StillFailing    []APITestResultDiff `json:"still_failing"`
+	Added           []APITestResultDiff `json:"added"`
+	Removed         []APITestResultDiff `json:"removed"`
+	DurationChanges []APITestResultDiff `json:"duration_changes"`
+}
+
+// Import transforms a TestResultsDiff object into an APITestResultsDiff
+// object.
+func (a *APITestResultsDiff) Import(i interface{}) error {
+	switch diff := i.(type) {
+	case dbModel.TestResultsDiff:
+		for _, category := range []struct {
+			in  []dbModel.TestResultDiff
+			out *[]APITestResultDiff
+		}{
+			{in: diff.NewlyFailing, out: &a.NewlyFailing},
+			{in: diff.NewlyPassing, out: &a.NewlyPassing},
+			{in: diff.StillFailing, out: &a.StillFailing},
+			{in: diff.Added, out: &a.Added},
+			{in: diff.Removed, out: &a.Removed},
+			{in: diff.DurationChanges, out: &a.DurationChanges},
+		} {
+			*category.out = make([]APITestResultDiff, len(category.in))
+			for j, entry := range category.in {
+				if err := (*category.out)[j].Import(entry); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		return errors.Errorf("incorrect type %T when converting to APITestResultsDiff type", i)
+	}
+
+	return nil
+}
+
+// APITestResultDiff describes a single test in an APITestResultsDiff. Either
+// the base or the current result is nil if the test was added or removed.
+type APITestResultDiff struct {
+	TestName *string `json:"test_name"`
+	// DurationChange is the change in the test's duration in seconds.
+	DurationChange float64        `json:"duration_change"`
+	Base           *APITestResult `json:"base,omitempty"`
+	Current        *APITestResult `json:"current,omitempty"`
+}
+
+// Import transforms a TestResultDiff object into an APITestResultDiff object.
+func (a *APITestResultDiff) Import(i interface{}) error {
+	switch diff := i.(type) {
+	case dbModel.TestResultDiff:
+		a.TestName = utility.ToStringPtr(diff.TestName)
+		a.DurationChange = diff.DurationChange().Seconds()
+		if diff.Base != nil {
+			a.Base = &APITestResult{}
+			if err := a.Base.Import(*diff.Base); err != nil {
+				return errors.Wrap(err, "importing base test result")
+			}
+		}
+		if diff.Current != nil {
+			a.Current = &APITestResult{}
+			if err := a.Current.Import(*diff.Current); err != nil {
+				return errors.Wrap(err, "importing current test result")
+			}
+		}
+	default:
+		return errors.Errorf("incorrect type %T when converting to APITestResultDiff type", i)
+	}
+
+	return nil
+}
+
+// APITestResultsSearch describes the test results matching a search across
+// the tasks of a project.
+type APITestResultsSearch struct {
+	TotalCount int                         `json:"total_count"`
+	Results    []APITestResultSearchResult `json:"results"`
+}
+
+// APITestResultSearchResult describes a test result matching a search along
+// with the task execution it belongs to.
+type APITestResultSearchResult struct {
+	Project         *string       `json:"project"`
+	Version         *string       `json:"version"`
+	Variant         *string       `json:"variant"`
+	TaskName        *string       `json:"task_name"`
+	DisplayTaskName *string       `json:"display_task_name,omitempty"`
+	DisplayTaskID   *string       `json:"display_task_id,omitempty"`
+	RequestType     *string       `json:"request_type"`
+	Mainline        bool          `json:"mainline"`
+	CreatedAt       APITime       `json:"created_at"`
+	Result          APITestResult `json:"result"`
+}
+
+// Import transforms a TestResultSearchResult object into an
+// APITestResultSearchResult object.
+func (a *APITestResultSearchResult) Import(i interface{}) error {
+	switch sr := i.(type) {
+	case dbModel.TestResultSearchResult:
+		a.Project = utility.ToStringPtr(sr.Info.Project)
+		a.Version = utility.ToStringPtr(sr.Info.Version)
+		a.Variant = utility.ToStringPtr(sr.Info.Variant)
+		a.TaskName = utility.ToStringPtr(sr.Info.TaskName)
+		if sr.Info.DisplayTaskName != "" {
+			a.DisplayTaskName = utility.ToStringPtr(sr.Info.DisplayTaskName)
+		}
+		if sr.Info.DisplayTaskID != "" {
+			a.DisplayTaskID = utility.ToStringPtr(sr.Info.DisplayTaskID)
+		}
+		a.RequestType = utility.ToStringPtr(sr.Info.RequestType)
+		a.Mainline = sr.Info.Mainline
+		a.CreatedAt = NewTime(sr.CreatedAt)
+		if err := a.Result.Import(sr.Result); err != nil {
+			return errors.Wrap(err, "importing test result")
+		}
+	default:
+		return errors.Errorf("incorrect type %T when converting to APITestResultSearchResult type", i)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// APIAggregatedTestResultsStats describes the test results stats of a group
+// of tasks. Only the fields the stats are grouped by are set.
+type APIAggregatedTestResultsStats struct {
+	Version         *string `json:"version,omitempty"`
+	Variant         *string `json:"variant,omitempty"`
+	TaskName        *string `json:"task_name,omitempty"`
+	NumTasks        int     `json:"num_tasks"`
+	TotalCount      int     `json:"total_count"`
+	PassedCount     int     `json:"passed_count"`
+	FailedCount     int     `json:"failed_count"`
+	SkippedCount    int     `json:"skipped_count"`
+	TotalDuration   float64 `json:"total_duration_secs"`
+	LatestCreatedAt APITime `json:"latest_created_at"`
+}
+
+// Import transforms an AggregatedTestResultsStats object into an
+// APIAggregatedTestResultsStats object.
+func (a *APIAggregatedTestResultsStats) Import(i interface{}) error {
+	switch stats := i.(type) {
+	case dbmodel.AggregatedTestResultsStats:
+		if stats.Version != "" {
+			a.Version = utility.ToStringPtr(stats.Version)
+		}
+		if stats.Variant != "" {
+			a.Variant = utility.ToStringPtr(stats.Variant)
+		}
+		if stats.TaskName != "" {
+			a.TaskName = utility.ToStringPtr(stats.TaskName)
+		}
+		a.NumTasks = stats.NumTasks
+		a.TotalCount = stats.TotalCount
+		a.PassedCount = stats.PassedCount
+		a.FailedCount = stats.FailedCount
+		a.SkippedCount = stats.SkippedCount
+		a.TotalDuration = stats.TotalDuration.Seconds()
+		a.LatestCreatedAt = NewTime(stats.LatestCreatedAt)
+	default:
+		return errors.Errorf("incorrect type %T when converting to APIAggregatedTestResultsStats type", i)
+	}
+	return nil
+}
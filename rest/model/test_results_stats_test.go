@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregatedTestResultsStatsImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		api := &APIAggregatedTestResultsStats{}
+		assert.Error(t, api.Import(dbmodel.TestResultsStats{}))
+	})
+	t.Run("ValidStats", func(t *testing.T) {
+		stats := dbmodel.AggregatedTestResultsStats{
+			Version:         "version",
+			Variant:         "variant",
+			NumTasks:        2,
+			TotalCount:      20,
+			PassedCount:     15,
+			FailedCount:     3,
+			SkippedCount:    2,
+			TotalDuration:   90 * time.Second,
+			LatestCreatedAt: time.Now(),
+		}
+		expected := &APIAggregatedTestResultsStats{
+			Version:         utility.ToStringPtr(stats.Version),
+			Variant:         utility.ToStringPtr(stats.Variant),
+			NumTasks:        stats.NumTasks,
+			TotalCount:      stats.TotalCount,
+			PassedCount:     stats.PassedCount,
+			FailedCount:     stats.FailedCount,
+			SkippedCount:    stats.SkippedCount,
+			TotalDuration:   90,
+			LatestCreatedAt: NewTime(stats.LatestCreatedAt),
+		}
+
+		api := &APIAggregatedTestResultsStats{}
+		assert.NoError(t, api.Import(stats))
+		assert.Equal(t, expected, api)
+		assert.Nil(t, api.TaskName)
+	})
+}
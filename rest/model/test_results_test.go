@@ -7,6 +7,7 @@ import (
 	dbmodel "github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/utility"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTestResultImport(t *testing.T) {
@@ -24,6 +25,8 @@ func TestTestResultImport(t *testing.T) {
 			Trial:           3,
 			Status:          "pass",
 			BaseStatus:      "pass",
+			Owner:           "owner",
+			Team:            "team",
 			LogTestName:     "log",
 			LogURL:          "url",
 			RawLogURL:       "raw_url",
@@ -31,6 +34,9 @@ func TestTestResultImport(t *testing.T) {
 			TaskCreateTime:  time.Now().Add(-time.Hour),
 			TestStartTime:   time.Now().Add(-30 * time.Minute),
 			TestEndTime:     time.Now(),
+			FailureMessage:  "failure",
+			StackTrace:      "stack",
+			Attributes:      map[string]string{"seed": "42"},
 		}
 		expected := &APITestResult{
 			TaskID:          utility.ToStringPtr(tr.TaskID),
@@ -41,6 +47,8 @@ func TestTestResultImport(t *testing.T) {
 			Trial:           tr.Trial,
 			Status:          utility.ToStringPtr(tr.Status),
 			BaseStatus:      utility.ToStringPtr(tr.BaseStatus),
+			Owner:           utility.ToStringPtr(tr.Owner),
+			Team:            utility.ToStringPtr(tr.Team),
 			LogTestName:     utility.ToStringPtr(tr.LogTestName),
 			LogURL:          utility.ToStringPtr(tr.LogURL),
 			RawLogURL:       utility.ToStringPtr(tr.RawLogURL),
@@ -48,9 +56,98 @@ func TestTestResultImport(t *testing.T) {
 			TaskCreateTime:  NewTime(tr.TaskCreateTime),
 			TestStartTime:   NewTime(tr.TestStartTime),
 			TestEndTime:     NewTime(tr.TestEndTime),
+			FailureMessage:  utility.ToStringPtr(tr.FailureMessage),
+			StackTrace:      utility.ToStringPtr(tr.StackTrace),
+			Attributes:      tr.Attributes,
 		}
 		apiTestResult := &APITestResult{}
 		assert.NoError(t, apiTestResult.Import(tr))
 		assert.Equal(t, expected, apiTestResult)
 	})
 }
+
+func TestTestResultsDiffImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		apiDiff := &APITestResultsDiff{}
+		assert.Error(t, apiDiff.Import(dbmodel.TestResultDiff{}))
+	})
+	t.Run("ValidTestResultsDiff", func(t *testing.T) {
+		start := time.Now().Add(-time.Hour)
+		base := dbmodel.TestResult{TestName: "test0", Status: "pass", TestStartTime: start, TestEndTime: start.Add(time.Second)}
+		current := dbmodel.TestResult{TestName: "test0", Status: "fail", TestStartTime: start, TestEndTime: start.Add(3 * time.Second)}
+		added := dbmodel.TestResult{TestName: "test1", Status: "pass", TestStartTime: start, TestEndTime: start}
+		diff := dbmodel.TestResultsDiff{
+			NewlyFailing:    []dbmodel.TestResultDiff{{TestName: "test0", Base: &base, Current: &current}},
+			Added:           []dbmodel.TestResultDiff{{TestName: "test1", Current: &added}},
+			DurationChanges: []dbmodel.TestResultDiff{{TestName: "test0", Base: &base, Current: &current}},
+		}
+
+		apiDiff := &APITestResultsDiff{}
+		require.NoError(t, apiDiff.Import(diff))
+
+		require.Len(t, apiDiff.NewlyFailing, 1)
+		assert.Equal(t, "test0", *apiDiff.NewlyFailing[0].TestName)
+		assert.Equal(t, 2.0, apiDiff.NewlyFailing[0].DurationChange)
+		require.NotNil(t, apiDiff.NewlyFailing[0].Base)
+		assert.Equal(t, "pass", *apiDiff.NewlyFailing[0].Base.Status)
+		require.NotNil(t, apiDiff.NewlyFailing[0].Current)
+		assert.Equal(t, "fail", *apiDiff.NewlyFailing[0].Current.Status)
+		require.Len(t, apiDiff.Added, 1)
+		assert.Nil(t, apiDiff.Added[0].Base)
+		assert.Zero(t, apiDiff.Added[0].DurationChange)
+		assert.Len(t, apiDiff.DurationChanges, 1)
+		assert.NotNil(t, apiDiff.NewlyPassing)
+		assert.Empty(t, apiDiff.NewlyPassing)
+		assert.Empty(t, apiDiff.StillFailing)
+		assert.Empty(t, apiDiff.Removed)
+	})
+}
+
+func TestTestResultSearchResultImport(t *testing.T) {
+	t.Run("InvalidType", func(t *testing.T) {
+		apiResult := &APITestResultSearchResult{}
+		assert.Error(t, apiResult.Import(dbmodel.TestResult{}))
+	})
+	t.Run("ValidSearchResult", func(t *testing.T) {
+		createdAt := time.Now().Add(-time.Hour)
+		sr := dbmodel.TestResultSearchResult{
+			Info: dbmodel.TestResultsInfo{
+				Project:         "project",
+				Version:         "version",
+				Variant:         "variant",
+				TaskName:        "task_name",
+				DisplayTaskName: "display_task_name",
+				TaskID:          "task_id",
+				DisplayTaskID:   "display_task_id",
+				RequestType:     "request_type",
+				Mainline:        true,
+			},
+			CreatedAt: createdAt,
+			Result: dbmodel.TestResult{
+				TaskID:        "task_id",
+				TestName:      "test_name",
+				Status:        "fail",
+				TestStartTime: createdAt,
+				TestEndTime:   createdAt.Add(time.Minute),
+			},
+		}
+		expectedResult := APITestResult{}
+		require.NoError(t, expectedResult.Import(sr.Result))
+		expected := &APITestResultSearchResult{
+			Project:         utility.ToStringPtr("project"),
+			Version:         utility.ToStringPtr("version"),
+			Variant:         utility.ToStringPtr("variant"),
+			TaskName:        utility.ToStringPtr("task_name"),
+			DisplayTaskName: utility.ToStringPtr("display_task_name"),
+			DisplayTaskID:   utility.ToStringPtr("display_task_id"),
+			RequestType:     utility.ToStringPtr("request_type"),
+			Mainline:        true,
+			CreatedAt:       NewTime(createdAt),
+			Result:          expectedResult,
+		}
+
+		apiResult := &APITestResultSearchResult{}
+		require.NoError(t, apiResult.Import(sr))
+		assert.Equal(t, expected, apiResult)
+	})
+}
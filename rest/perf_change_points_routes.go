@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	perfChangePointsStatus          = "status"
+	perfChangePointsAPIMaxLimit     = 10000
+	perfChangePointsAPIDefaultLimit = 1000
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/change_points/{project_id}
+
+type perfChangePointsGetHandler struct {
+	sc     data.Connector
+	filter dbModel.PerformanceChangePointsFilter
+}
+
+func makeGetPerfChangePoints(sc data.Connector) gimlet.RouteHandler {
+	return &perfChangePointsGetHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new perfChangePointsGetHandler.
+func (h *perfChangePointsGetHandler) Factory() gimlet.RouteHandler {
+	return &perfChangePointsGetHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the filter from the HTTP request. The
+// status values are parsed the same way as the historical test data's string
+// lists.
+func (h *perfChangePointsGetHandler) Parse(_ context.Context, r *http.Request) error {
+	var (
+		htd htdFilterHandler
+		err error
+	)
+
+	vals := r.URL.Query()
+	h.filter = dbModel.PerformanceChangePointsFilter{
+		Project:     gimlet.GetVars(r)["project_id"],
+		Variant:     vals.Get(perfSeriesVariant),
+		Task:        vals.Get(perfSeriesTask),
+		Test:        vals.Get(perfSeriesTest),
+		Measurement: vals.Get(perfSeriesMeasurement),
+	}
+	for _, status := range htd.readStringList(vals[perfChangePointsStatus]) {
+		h.filter.Statuses = append(h.filter.Statuses, dbModel.PerformanceChangePointTriageStatus(status))
+	}
+
+	h.filter.Limit, err = htd.readInt(vals.Get(limit), 1, perfChangePointsAPIMaxLimit, perfChangePointsAPIDefaultLimit)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			Message:    "invalid limit value",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if err = h.filter.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// Run returns the change points of the project matching the filter.
+func (h *perfChangePointsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	changePoints, err := h.sc.GetPerformanceChangePoints(ctx, h.filter)
+	if err != nil {
+		err = errors.Wrapf(err, "getting performance change points for project '%s'", h.filter.Project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/perf/change_points/{project_id}",
+			"project": h.filter.Project,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(changePoints)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// PUT /perf/change_points/{project_id}/{id}/triage
+
+type perfChangePointTriageHandler struct {
+	sc      data.Connector
+	project string
+	id      string
+	triage  dbModel.PerformanceChangePointTriage
+}
+
+func makeTriagePerfChangePoint(sc data.Connector) gimlet.RouteHandler {
+	return &perfChangePointTriageHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new perfChangePointTriageHandler.
+func (h *perfChangePointTriageHandler) Factory() gimlet.RouteHandler {
+	return &perfChangePointTriageHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the change point ID from the HTTP request
+// and reads the triage from the request body. The requesting user is
+// recorded as the user who triaged the change point.
+func (h *perfChangePointTriageHandler) Parse(_ context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.project = vars["project_id"]
+	h.id = vars["id"]
+
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+
+	apiTriage := &model.APIPerformanceChangePointTriage{}
+	if err := utility.ReadJSON(body, apiTriage); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "reading change point triage from request body").Error(),
+		}
+	}
+	apiTriage.TriagedBy = nil
+	if u := gimlet.GetUser(r.Context()); u != nil {
+		apiTriage.TriagedBy = utility.ToStringPtr(u.Username())
+	}
+
+	triage, err := apiTriage.Export()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "exporting change point triage").Error(),
+		}
+	}
+	h.triage = triage.(dbModel.PerformanceChangePointTriage)
+
+	return nil
+}
+
+// Run sets the triage of the change point and returns the change point.
+func (h *perfChangePointTriageHandler) Run(ctx context.Context) gimlet.Responder {
+	changePoint, err := h.sc.TriagePerformanceChangePoint(ctx, h.project, h.id, h.triage)
+	if err != nil {
+		err = errors.Wrapf(err, "triaging performance change point '%s'", h.id)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "PUT",
+			"route":   "/perf/change_points/{project_id}/{id}/triage",
+			"project": h.project,
+			"id":      h.id,
+			"status":  h.triage.Status,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(changePoint)
+}
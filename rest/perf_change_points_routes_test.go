@@ -0,0 +1,200 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfChangePointsGetHandlerParse(t *testing.T) {
+	newRequest := func(query string) *http.Request {
+		req := &http.Request{Method: http.MethodGet}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/perf/change_points/project?" + query)
+		return gimlet.SetURLVars(req, map[string]string{"project_id": "project"})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		handler := makeGetPerfChangePoints(&data.MockConnector{}).(*perfChangePointsGetHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest("variant=variant&task=task&test=test&measurement=ops_per_sec&status=untriaged,linked&status=hidden&limit=20")))
+		assert.Equal(t, dbModel.PerformanceChangePointsFilter{
+			Project:     "project",
+			Variant:     "variant",
+			Task:        "task",
+			Test:        "test",
+			Measurement: "ops_per_sec",
+			Statuses: []dbModel.PerformanceChangePointTriageStatus{
+				dbModel.PerformanceChangePointUntriaged,
+				dbModel.PerformanceChangePointLinked,
+				dbModel.PerformanceChangePointHidden,
+			},
+			Limit: 20,
+		}, handler.filter)
+	})
+	t.Run("DefaultLimit", func(t *testing.T) {
+		handler := makeGetPerfChangePoints(&data.MockConnector{}).(*perfChangePointsGetHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest("")))
+		assert.Equal(t, perfChangePointsAPIDefaultLimit, handler.filter.Limit)
+		assert.Empty(t, handler.filter.Statuses)
+	})
+	t.Run("InvalidStatus", func(t *testing.T) {
+		handler := makeGetPerfChangePoints(&data.MockConnector{}).(*perfChangePointsGetHandler)
+		assert.Error(t, handler.Parse(context.Background(), newRequest("status=DNE")))
+	})
+	t.Run("InvalidLimit", func(t *testing.T) {
+		handler := makeGetPerfChangePoints(&data.MockConnector{}).(*perfChangePointsGetHandler)
+		assert.Error(t, handler.Parse(context.Background(), newRequest("limit=0")))
+	})
+}
+
+func TestPerfChangePointTriageHandlerParse(t *testing.T) {
+	newRequest := func(body string, user gimlet.User) *http.Request {
+		req := &http.Request{Method: http.MethodPut}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/perf/change_points/project/id/triage")
+		req.Body = ioutil.NopCloser(bytes.NewBufferString(body))
+		if user != nil {
+			req = req.WithContext(gimlet.AttachUser(req.Context(), user))
+		}
+		return gimlet.SetURLVars(req, map[string]string{"project_id": "project", "id": "id"})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		handler := makeTriagePerfChangePoint(&data.MockConnector{}).(*perfChangePointTriageHandler)
+		opts, err := gimlet.NewBasicUserOptions("admin")
+		require.NoError(t, err)
+		body := `{"status": "linked", "notes": "regression", "tickets": ["PERF-1"], "triaged_by": "someone"}`
+		require.NoError(t, handler.Parse(context.Background(), newRequest(body, gimlet.NewBasicUser(opts))))
+		assert.Equal(t, "project", handler.project)
+		assert.Equal(t, "id", handler.id)
+		assert.Equal(t, dbModel.PerformanceChangePointTriage{
+			Status:    dbModel.PerformanceChangePointLinked,
+			TriagedBy: "admin",
+			Notes:     "regression",
+			Tickets:   []string{"PERF-1"},
+		}, handler.triage)
+	})
+	t.Run("NoUser", func(t *testing.T) {
+		handler := makeTriagePerfChangePoint(&data.MockConnector{}).(*perfChangePointTriageHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest(`{"status": "hidden", "triaged_by": "someone"}`, nil)))
+		assert.Equal(t, dbModel.PerformanceChangePointHidden, handler.triage.Status)
+		assert.Empty(t, handler.triage.TriagedBy)
+	})
+	t.Run("InvalidBody", func(t *testing.T) {
+		handler := makeTriagePerfChangePoint(&data.MockConnector{}).(*perfChangePointTriageHandler)
+		assert.Error(t, handler.Parse(context.Background(), newRequest("{", nil)))
+	})
+}
+
+func TestPerfChangePointHandlersRun(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	id := dbModel.PerformanceResultSeriesID{
+		Project:     "project",
+		Variant:     "variant",
+		Task:        "task",
+		Test:        "test",
+		Measurement: "ops_per_sec",
+	}
+	otherID := id
+	otherID.Variant = "variant1"
+	sc := &data.MockConnector{CachedPerfChangePoints: map[string]dbModel.PerformanceChangePoint{}}
+	for i, cp := range []dbModel.PerformanceChangePoint{
+		{SeriesID: id, Order: 10, DetectedAt: now.Add(-time.Hour)},
+		{SeriesID: otherID, Order: 10, DetectedAt: now},
+		{SeriesID: dbModel.PerformanceResultSeriesID{Project: "other"}, Order: 10, DetectedAt: now},
+	} {
+		cp.ID = dbModel.PerformanceChangePointID(cp.SeriesID, cp.Order)
+		cp.Triage.Status = dbModel.PerformanceChangePointUntriaged
+		if i == 1 {
+			cp.Triage.Status = dbModel.PerformanceChangePointHidden
+		}
+		sc.CachedPerfChangePoints[cp.ID] = cp
+	}
+	getChangePoints := func(t *testing.T, filter dbModel.PerformanceChangePointsFilter) []model.APIPerformanceChangePoint {
+		getHandler := makeGetPerfChangePoints(sc).(*perfChangePointsGetHandler)
+		getHandler.filter = filter
+		resp := getHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		changePoints, ok := resp.Data().([]model.APIPerformanceChangePoint)
+		require.True(t, ok)
+		return changePoints
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		changePoints := getChangePoints(t, dbModel.PerformanceChangePointsFilter{Project: "project", Limit: 10})
+		require.Len(t, changePoints, 2)
+		assert.Equal(t, "variant1", utility.FromStringPtr(changePoints[0].Variant))
+		assert.Equal(t, "variant", utility.FromStringPtr(changePoints[1].Variant))
+
+		changePoints = getChangePoints(t, dbModel.PerformanceChangePointsFilter{Project: "project", Variant: "variant", Limit: 10})
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, dbModel.PerformanceChangePointID(id, 10), utility.FromStringPtr(changePoints[0].ID))
+
+		changePoints = getChangePoints(t, dbModel.PerformanceChangePointsFilter{Project: "project", Limit: 1})
+		assert.Len(t, changePoints, 1)
+	})
+	t.Run("GetByStatus", func(t *testing.T) {
+		changePoints := getChangePoints(t, dbModel.PerformanceChangePointsFilter{
+			Project:  "project",
+			Statuses: []dbModel.PerformanceChangePointTriageStatus{dbModel.PerformanceChangePointHidden},
+			Limit:    10,
+		})
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, dbModel.PerformanceChangePointID(otherID, 10), utility.FromStringPtr(changePoints[0].ID))
+	})
+	t.Run("Triage", func(t *testing.T) {
+		triageHandler := makeTriagePerfChangePoint(sc).(*perfChangePointTriageHandler)
+		triageHandler.project = "project"
+		triageHandler.id = dbModel.PerformanceChangePointID(id, 10)
+		triageHandler.triage = dbModel.PerformanceChangePointTriage{
+			Status:    dbModel.PerformanceChangePointLinked,
+			TriagedBy: "admin",
+			Tickets:   []string{"PERF-1"},
+		}
+		resp := triageHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		changePoint, ok := resp.Data().(*model.APIPerformanceChangePoint)
+		require.True(t, ok)
+		assert.Equal(t, string(dbModel.PerformanceChangePointLinked), utility.FromStringPtr(changePoint.Triage.Status))
+		assert.Equal(t, "admin", utility.FromStringPtr(changePoint.Triage.TriagedBy))
+		assert.Equal(t, []string{"PERF-1"}, changePoint.Triage.Tickets)
+
+		changePoints := getChangePoints(t, dbModel.PerformanceChangePointsFilter{
+			Project:  "project",
+			Statuses: []dbModel.PerformanceChangePointTriageStatus{dbModel.PerformanceChangePointUntriaged},
+			Limit:    10,
+		})
+		assert.Empty(t, changePoints)
+	})
+	t.Run("TriageInvalid", func(t *testing.T) {
+		triageHandler := makeTriagePerfChangePoint(sc).(*perfChangePointTriageHandler)
+		triageHandler.project = "project"
+		triageHandler.id = dbModel.PerformanceChangePointID(id, 10)
+		triageHandler.triage = dbModel.PerformanceChangePointTriage{Status: dbModel.PerformanceChangePointLinked}
+		resp := triageHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+	t.Run("TriageFromOtherProject", func(t *testing.T) {
+		triageHandler := makeTriagePerfChangePoint(sc).(*perfChangePointTriageHandler)
+		triageHandler.project = "other"
+		triageHandler.id = dbModel.PerformanceChangePointID(id, 10)
+		triageHandler.triage = dbModel.PerformanceChangePointTriage{Status: dbModel.PerformanceChangePointHidden}
+		resp := triageHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
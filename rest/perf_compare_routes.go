@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const perfCompareSignificanceLevel = "significance_level"
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/compare/version/{base_version}/{version}
+// GET /perf/compare/task_id/{base_task_id}/{task_id}
+
+type perfCompareHandler struct {
+	sc     data.Connector
+	byTask bool
+	opts   dbModel.PerformanceComparisonOptions
+}
+
+func makeComparePerfByVersion(sc data.Connector) gimlet.RouteHandler {
+	return &perfCompareHandler{sc: sc}
+}
+
+func makeComparePerfByTaskId(sc data.Connector) gimlet.RouteHandler {
+	return &perfCompareHandler{sc: sc, byTask: true}
+}
+
+// Factory returns a pointer to a new perfCompareHandler.
+func (h *perfCompareHandler) Factory() gimlet.RouteHandler {
+	return &perfCompareHandler{sc: h.sc, byTask: h.byTask}
+}
+
+// Parse fetches the base and compared version or task IDs and the
+// significance level from the HTTP request.
+func (h *perfCompareHandler) Parse(_ context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.opts = dbModel.PerformanceComparisonOptions{}
+	if h.byTask {
+		h.opts.BaseTaskID = vars["base_task_id"]
+		h.opts.TaskID = vars["task_id"]
+	} else {
+		h.opts.BaseVersion = vars["base_version"]
+		h.opts.Version = vars["version"]
+	}
+
+	if err := h.parse(r.URL.Query()); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    errors.Wrap(err, "invalid query parameters").Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if err := h.opts.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// parse parses the query parameter values and fills the comparison options.
+func (h *perfCompareHandler) parse(vals url.Values) error {
+	if level := vals.Get(perfCompareSignificanceLevel); level != "" {
+		var err error
+		if h.opts.SignificanceLevel, err = strconv.ParseFloat(level, 64); err != nil {
+			return errors.Wrap(err, "invalid significance level")
+		}
+	}
+
+	return nil
+}
+
+// Run returns the comparison of the rollups of the performance results.
+func (h *perfCompareHandler) Run(ctx context.Context) gimlet.Responder {
+	comparison, err := h.sc.ComparePerformanceResults(ctx, h.opts)
+	if err != nil {
+		fields := message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+		}
+		if h.byTask {
+			err = errors.Wrapf(err, "comparing performance results of task '%s' to base task '%s'", h.opts.TaskID, h.opts.BaseTaskID)
+			fields["route"] = "/perf/compare/task_id/{base_task_id}/{task_id}"
+			fields["base_task_id"] = h.opts.BaseTaskID
+			fields["task_id"] = h.opts.TaskID
+		} else {
+			err = errors.Wrapf(err, "comparing performance results of version '%s' to base version '%s'", h.opts.Version, h.opts.BaseVersion)
+			fields["route"] = "/perf/compare/version/{base_version}/{version}"
+			fields["base_version"] = h.opts.BaseVersion
+			fields["version"] = h.opts.Version
+		}
+		logFindError(err, fields)
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(comparison)
+}
@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfCompareHandlerParse(t *testing.T) {
+	newRequest := func(vars map[string]string, query string) *http.Request {
+		req := &http.Request{Method: http.MethodGet}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/perf/compare?" + query)
+		return gimlet.SetURLVars(req, vars)
+	}
+
+	t.Run("Versions", func(t *testing.T) {
+		handler := makeComparePerfByVersion(&data.MockConnector{}).(*perfCompareHandler)
+		req := newRequest(map[string]string{"base_version": "base", "version": "patch"}, "significance_level=0.01")
+		require.NoError(t, handler.Parse(context.Background(), req))
+		assert.Equal(t, dbModel.PerformanceComparisonOptions{
+			BaseVersion:       "base",
+			Version:           "patch",
+			SignificanceLevel: 0.01,
+		}, handler.opts)
+	})
+	t.Run("Tasks", func(t *testing.T) {
+		handler := makeComparePerfByTaskId(&data.MockConnector{}).(*perfCompareHandler)
+		req := newRequest(map[string]string{"base_task_id": "base", "task_id": "patch"}, "")
+		require.NoError(t, handler.Parse(context.Background(), req))
+		assert.Equal(t, "base", handler.opts.BaseTaskID)
+		assert.Equal(t, "patch", handler.opts.TaskID)
+		assert.Empty(t, handler.opts.BaseVersion)
+		assert.NotZero(t, handler.opts.SignificanceLevel)
+	})
+	t.Run("FactoryKeepsTasks", func(t *testing.T) {
+		handler := makeComparePerfByTaskId(&data.MockConnector{}).Factory().(*perfCompareHandler)
+		assert.True(t, handler.byTask)
+	})
+	t.Run("InvalidSignificanceLevel", func(t *testing.T) {
+		for _, level := range []string{"low", "1.5"} {
+			handler := makeComparePerfByVersion(&data.MockConnector{}).(*perfCompareHandler)
+			req := newRequest(map[string]string{"base_version": "base", "version": "patch"}, "significance_level="+level)
+			assert.Error(t, handler.Parse(context.Background(), req))
+		}
+	})
+	t.Run("MissingVersion", func(t *testing.T) {
+		handler := makeComparePerfByVersion(&data.MockConnector{}).(*perfCompareHandler)
+		req := newRequest(map[string]string{"base_version": "base"}, "")
+		assert.Error(t, handler.Parse(context.Background(), req))
+	})
+}
+
+func TestPerfCompareHandlerRun(t *testing.T) {
+	sc := &data.MockConnector{CachedPerformanceResults: map[string]dbModel.PerformanceResult{}}
+	for _, test := range []struct {
+		version string
+		trial   int
+		value   float64
+	}{
+		{version: "base", trial: 0, value: 100},
+		{version: "base", trial: 1, value: 101},
+		{version: "base", trial: 2, value: 99},
+		{version: "patch", trial: 0, value: 80},
+		{version: "patch", trial: 1, value: 81},
+		{version: "patch", trial: 2, value: 79},
+	} {
+		result := dbModel.CreatePerformanceResult(dbModel.PerformanceResultInfo{
+			Project:  "project",
+			Version:  test.version,
+			Variant:  "variant",
+			TaskName: "task",
+			TaskID:   test.version + "_task",
+			TestName: "test",
+			Trial:    test.trial,
+		}, nil, []dbModel.PerfRollupValue{
+			{Name: "ops_per_sec", Value: test.value, Version: 1, MetricType: dbModel.MetricTypeThroughput},
+		})
+		sc.CachedPerformanceResults[result.ID] = *result
+	}
+	compare := func(t *testing.T, handler *perfCompareHandler) *model.APIPerformanceComparison {
+		require.NoError(t, handler.opts.Validate())
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		comparison, ok := resp.Data().(*model.APIPerformanceComparison)
+		require.True(t, ok)
+		return comparison
+	}
+
+	t.Run("Versions", func(t *testing.T) {
+		handler := makeComparePerfByVersion(sc).(*perfCompareHandler)
+		handler.opts = dbModel.PerformanceComparisonOptions{BaseVersion: "base", Version: "patch"}
+		comparison := compare(t, handler)
+		assert.Equal(t, "base", utility.FromStringPtr(comparison.BaseVersion))
+		require.Len(t, comparison.Tests, 1)
+		test := comparison.Tests[0]
+		assert.Equal(t, "test", utility.FromStringPtr(test.TestName))
+		assert.Len(t, test.Trials, 3)
+		require.Len(t, test.Rollups, 1)
+		require.NotNil(t, test.Rollups[0].PercentDelta)
+		assert.InDelta(t, -20.0, *test.Rollups[0].PercentDelta, 1e-9)
+		assert.True(t, test.Rollups[0].Significant)
+	})
+	t.Run("Tasks", func(t *testing.T) {
+		handler := makeComparePerfByTaskId(sc).(*perfCompareHandler)
+		handler.opts = dbModel.PerformanceComparisonOptions{BaseTaskID: "base_task", TaskID: "patch_task"}
+		comparison := compare(t, handler)
+		assert.Equal(t, "patch_task", utility.FromStringPtr(comparison.TaskID))
+		require.Len(t, comparison.Tests, 1)
+		require.Len(t, comparison.Tests[0].Rollups, 1)
+		require.NotNil(t, comparison.Tests[0].Rollups[0].Delta)
+		assert.InDelta(t, -20.0, *comparison.Tests[0].Rollups[0].Delta, 1e-9)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		handler := makeComparePerfByVersion(sc).(*perfCompareHandler)
+		handler.opts = dbModel.PerformanceComparisonOptions{BaseVersion: "base", Version: "DNE"}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		handler := makeComparePerfByVersion(sc).(*perfCompareHandler)
+		handler.opts = dbModel.PerformanceComparisonOptions{BaseVersion: "base"}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
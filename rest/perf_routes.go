@@ -19,6 +19,12 @@ const (
 	perfEndAt    = "finished_before"
 	perfSkip     = "skip"
 	perfMaxDepth = "max_depth"
+
+	perfSeriesVariant     = "variant"
+	perfSeriesTask        = "task"
+	perfSeriesTest        = "test"
+	perfSeriesMeasurement = "measurement"
+	perfSeriesArg         = "arg"
 )
 
 // timeRangeFormatYearMonthDay represents the time range format rounded to the
@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	perfTimeSeriesIncludePatches  = "include_patches"
+	perfTimeSeriesMinOrder        = "min_order"
+	perfTimeSeriesMaxOrder        = "max_order"
+	perfTimeSeriesAPIMaxLimit     = 10000
+	perfTimeSeriesAPIDefaultLimit = 1000
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/time_series/{project_id}
+
+type perfTimeSeriesGetHandler struct {
+	sc   data.Connector
+	opts dbModel.PerformanceTimeSeriesOptions
+}
+
+func makeGetPerfTimeSeries(sc data.Connector) gimlet.RouteHandler {
+	return &perfTimeSeriesGetHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new perfTimeSeriesGetHandler.
+func (h *perfTimeSeriesGetHandler) Factory() gimlet.RouteHandler {
+	return &perfTimeSeriesGetHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the series options from the HTTP
+// request.
+func (h *perfTimeSeriesGetHandler) Parse(_ context.Context, r *http.Request) error {
+	h.opts = dbModel.PerformanceTimeSeriesOptions{
+		ID: dbModel.PerformanceResultSeriesID{Project: gimlet.GetVars(r)["project_id"]},
+	}
+
+	if err := h.parse(r.URL.Query()); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    errors.Wrap(err, "invalid query parameters").Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if err := h.opts.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// parse parses the query parameter values and fills the series options. The
+// variant, task, test, and measurement are required and the arguments are
+// given as repeated name:value pairs.
+func (h *perfTimeSeriesGetHandler) parse(vals url.Values) error {
+	var (
+		htd htdFilterHandler
+		err error
+	)
+
+	h.opts.ID.Variant = vals.Get(perfSeriesVariant)
+	h.opts.ID.Task = vals.Get(perfSeriesTask)
+	h.opts.ID.Test = vals.Get(perfSeriesTest)
+	h.opts.ID.Measurement = vals.Get(perfSeriesMeasurement)
+	if h.opts.ID.Variant == "" || h.opts.ID.Task == "" || h.opts.ID.Test == "" {
+		return errors.New("must specify a variant, task, and test")
+	}
+	for _, arg := range vals[perfSeriesArg] {
+		parts := strings.SplitN(arg, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return errors.Errorf("invalid argument '%s', must be of the form 'name:value'", arg)
+		}
+		value, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for argument '%s'", parts[0])
+		}
+		if h.opts.ID.Arguments == nil {
+			h.opts.ID.Arguments = dbModel.PerformanceArguments{}
+		}
+		h.opts.ID.Arguments[parts[0]] = int32(value)
+	}
+
+	h.opts.IncludePatches = vals.Get(perfTimeSeriesIncludePatches) == trueString
+	if minOrder := vals.Get(perfTimeSeriesMinOrder); minOrder != "" {
+		if h.opts.MinOrder, err = strconv.Atoi(minOrder); err != nil {
+			return errors.Wrap(err, "invalid min order")
+		}
+	}
+	if maxOrder := vals.Get(perfTimeSeriesMaxOrder); maxOrder != "" {
+		if h.opts.MaxOrder, err = strconv.Atoi(maxOrder); err != nil {
+			return errors.Wrap(err, "invalid max order")
+		}
+	}
+	if vals.Get(perfStartAt) != "" || vals.Get(perfEndAt) != "" {
+		h.opts.Interval, err = parseTimeRange(timeRangeFormatYearMonthDay, vals.Get(perfStartAt), vals.Get(perfEndAt))
+		if err != nil {
+			return errors.Wrap(err, "invalid time range")
+		}
+	}
+
+	h.opts.Limit, err = htd.readInt(vals.Get(limit), 1, perfTimeSeriesAPIMaxLimit, perfTimeSeriesAPIDefaultLimit)
+	if err != nil {
+		return errors.New("invalid limit value")
+	}
+
+	return nil
+}
+
+// Run returns the points of the performance time series, sorted by order.
+func (h *perfTimeSeriesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	points, err := h.sc.GetPerformanceTimeSeries(ctx, h.opts)
+	if err != nil {
+		err = errors.Wrapf(err, "getting performance time series '%s %s'", h.opts.ID, h.opts.ID.Measurement)
+		logFindError(err, message.Fields{
+			"request":     gimlet.GetRequestID(ctx),
+			"method":      "GET",
+			"route":       "/perf/time_series/{project_id}",
+			"project":     h.opts.ID.Project,
+			"variant":     h.opts.ID.Variant,
+			"task":        h.opts.ID.Task,
+			"test":        h.opts.ID.Test,
+			"measurement": h.opts.ID.Measurement,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(points)
+}
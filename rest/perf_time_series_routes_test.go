@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfTimeSeriesGetHandlerParse(t *testing.T) {
+	newRequest := func(query string) *http.Request {
+		req := &http.Request{Method: http.MethodGet}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/perf/time_series/project?" + query)
+		return gimlet.SetURLVars(req, map[string]string{"project_id": "project"})
+	}
+	const series = "variant=variant&task=task&test=test&measurement=ops_per_sec"
+
+	t.Run("Valid", func(t *testing.T) {
+		handler := makeGetPerfTimeSeries(&data.MockConnector{}).(*perfTimeSeriesGetHandler)
+		query := series + "&arg=thread_level:20&arg=batch_size:8&include_patches=true&min_order=10&max_order=20&started_after=2020-01-01&finished_before=2020-01-08&limit=50"
+		require.NoError(t, handler.Parse(context.Background(), newRequest(query)))
+		assert.Equal(t, dbModel.PerformanceTimeSeriesOptions{
+			ID: dbModel.PerformanceResultSeriesID{
+				Project:     "project",
+				Variant:     "variant",
+				Task:        "task",
+				Test:        "test",
+				Measurement: "ops_per_sec",
+				Arguments:   dbModel.PerformanceArguments{"thread_level": 20, "batch_size": 8},
+			},
+			IncludePatches: true,
+			MinOrder:       10,
+			MaxOrder:       20,
+			Interval: dbModel.TimeRange{
+				StartAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndAt:   time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC),
+			},
+			Limit: 50,
+		}, handler.opts)
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		handler := makeGetPerfTimeSeries(&data.MockConnector{}).(*perfTimeSeriesGetHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest(series)))
+		assert.Nil(t, handler.opts.ID.Arguments)
+		assert.False(t, handler.opts.IncludePatches)
+		assert.True(t, handler.opts.Interval.IsZero())
+		assert.Equal(t, perfTimeSeriesAPIDefaultLimit, handler.opts.Limit)
+	})
+	for name, query := range map[string]string{
+		"MissingTest":        "variant=variant&task=task&measurement=ops_per_sec",
+		"MissingMeasurement": "variant=variant&task=task&test=test",
+		"InvalidArgument":    series + "&arg=thread_level",
+		"InvalidArgValue":    series + "&arg=thread_level:many",
+		"InvalidMinOrder":    series + "&min_order=first",
+		"InvalidOrderRange":  series + "&min_order=20&max_order=10",
+		"InvalidTimeRange":   series + "&started_after=yesterday",
+		"InvalidLimit":       series + "&limit=0",
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := makeGetPerfTimeSeries(&data.MockConnector{}).(*perfTimeSeriesGetHandler)
+			assert.Error(t, handler.Parse(context.Background(), newRequest(query)))
+		})
+	}
+}
+
+func TestPerfTimeSeriesGetHandlerRun(t *testing.T) {
+	id := dbModel.PerformanceResultSeriesID{
+		Project:     "project",
+		Variant:     "variant",
+		Task:        "task",
+		Test:        "test",
+		Measurement: "ops_per_sec",
+		Arguments:   dbModel.PerformanceArguments{"thread_level": 20},
+	}
+	createdAt := time.Now().UTC().Round(time.Millisecond)
+	sc := &data.MockConnector{CachedPerformanceResults: map[string]dbModel.PerformanceResult{}}
+	for _, test := range []struct {
+		order    int
+		mainline bool
+		args     dbModel.PerformanceArguments
+		value    interface{}
+	}{
+		{order: 2, mainline: true, args: id.Arguments, value: 20.5},
+		{order: 1, mainline: true, args: id.Arguments, value: int64(10)},
+		{order: 3, mainline: false, args: id.Arguments, value: 30.0},
+		{order: 4, mainline: true, args: dbModel.PerformanceArguments{"thread_level": 1}, value: 40.0},
+	} {
+		result := dbModel.CreatePerformanceResult(dbModel.PerformanceResultInfo{
+			Project:   id.Project,
+			Version:   fmt.Sprintf("version%d", test.order),
+			Variant:   id.Variant,
+			Order:     test.order,
+			TaskName:  id.Task,
+			TaskID:    fmt.Sprintf("task%d", test.order),
+			TestName:  id.Test,
+			Arguments: test.args,
+			Mainline:  test.mainline,
+		}, nil, []dbModel.PerfRollupValue{
+			{Name: "latency", Value: 1.0, Version: 1, MetricType: dbModel.MetricTypeLatency},
+			{Name: id.Measurement, Value: test.value, Version: 1, MetricType: dbModel.MetricTypeThroughput},
+		})
+		result.CreatedAt = createdAt.Add(time.Duration(test.order) * time.Hour)
+		sc.CachedPerformanceResults[result.ID] = *result
+	}
+	getPoints := func(t *testing.T, opts dbModel.PerformanceTimeSeriesOptions) []model.APIPerformanceTimeSeriesPoint {
+		handler := makeGetPerfTimeSeries(sc).(*perfTimeSeriesGetHandler)
+		handler.opts = opts
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		points, ok := resp.Data().([]model.APIPerformanceTimeSeriesPoint)
+		require.True(t, ok)
+		return points
+	}
+
+	t.Run("Mainline", func(t *testing.T) {
+		points := getPoints(t, dbModel.PerformanceTimeSeriesOptions{ID: id})
+		require.Len(t, points, 2)
+		assert.Equal(t, 1, points[0].Order)
+		assert.Equal(t, "version1", utility.FromStringPtr(points[0].Version))
+		assert.Equal(t, "task1", utility.FromStringPtr(points[0].TaskID))
+		assert.Equal(t, 10.0, points[0].Value)
+		assert.Equal(t, model.NewTime(createdAt.Add(time.Hour)), points[0].CreatedAt)
+		assert.Equal(t, 2, points[1].Order)
+		assert.Equal(t, 20.5, points[1].Value)
+	})
+	t.Run("IncludePatchesWithLimit", func(t *testing.T) {
+		points := getPoints(t, dbModel.PerformanceTimeSeriesOptions{ID: id, IncludePatches: true, Limit: 2})
+		require.Len(t, points, 2)
+		assert.Equal(t, 2, points[0].Order)
+		assert.Equal(t, 3, points[1].Order)
+	})
+	t.Run("OrderRange", func(t *testing.T) {
+		points := getPoints(t, dbModel.PerformanceTimeSeriesOptions{ID: id, MinOrder: 2, MaxOrder: 3})
+		require.Len(t, points, 1)
+		assert.Equal(t, 2, points[0].Order)
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		handler := makeGetPerfTimeSeries(sc).(*perfTimeSeriesGetHandler)
+		handler.opts = dbModel.PerformanceTimeSeriesOptions{ID: id, MinOrder: 3, MaxOrder: 2}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
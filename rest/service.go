@@ -283,6 +283,9 @@ func (s *Service) addRoutes() {
 	checkDepot := newCertCheckDepotMiddleware(s.Depot == nil)
 	evgAuthReadLogByID := newEvgAuthReadLogByIDMiddleware(s.sc, &s.Conf.Evergreen)
 	evgAuthReadLogByTaskID := newEvgAuthReadLogByTaskIDMiddleware(s.sc, &s.Conf.Evergreen)
+	evgAuthReadLogByProject := newEvgAuthReadLogByProjectMiddleware(&s.Conf.Evergreen)
+	logFollow := newLogFollowMiddleware()
+	logArchive := newLogArchiveMiddleware()
 
 	s.app.AddRoute("/admin/status").Version(1).Get().Handler(s.statusHandler)
 	s.app.AddRoute("/admin/status/event/{id}").Version(1).Get().Wrap(checkUser).Handler(s.getSystemEvent)
@@ -294,6 +297,9 @@ func (s *Service) addRoutes() {
 	s.app.AddRoute("/admin/users/certificate").Version(1).Post().Get().Wrap(checkDepot).Handler(s.fetchUserCert)
 	s.app.AddRoute("/admin/users/certificate/key").Version(1).Post().Get().Wrap(checkDepot).Handler(s.fetchUserCertKey)
 	s.app.AddRoute("/admin/perf/change_points").Version(1).Post().Wrap(checkUser).RouteHandler(makePerfSignalProcessingRecalculate(s.sc))
+	s.app.AddRoute("/admin/test_owners/{project_id}").Version(1).Get().Wrap(checkUser).RouteHandler(makeGetTestOwnerships(s.sc))
+	s.app.AddRoute("/admin/test_owners/{project_id}").Version(1).Put().Wrap(checkUser).RouteHandler(makeSaveTestOwnership(s.sc))
+	s.app.AddRoute("/admin/test_owners/{project_id}/{id}").Version(1).Delete().Wrap(checkUser).RouteHandler(makeRemoveTestOwnership(s.sc))
 
 	s.app.AddRoute("/simple_log/{id}").Version(1).Post().Wrap(checkUser).Handler(s.simpleLogIngestion)
 	s.app.AddRoute("/simple_log/{id}").Version(1).Get().Handler(s.simpleLogRetrieval)
@@ -304,30 +310,50 @@ func (s *Service) addRoutes() {
 	s.app.AddRoute("/perf/{id}").Version(1).Get().RouteHandler(makeGetPerfById(s.sc))
 	s.app.AddRoute("/perf/{id}").Version(1).Delete().Wrap(checkUser).RouteHandler(makeRemovePerfById(s.sc))
 	s.app.AddRoute("/perf/children/{id}").Version(1).Get().RouteHandler(makeGetPerfChildren(s.sc))
+	s.app.AddRoute("/perf/change_points/{project_id}").Version(1).Get().RouteHandler(makeGetPerfChangePoints(s.sc))
+	s.app.AddRoute("/perf/change_points/{project_id}/{id}/triage").Version(1).Put().Wrap(checkUser).RouteHandler(makeTriagePerfChangePoint(s.sc))
+	s.app.AddRoute("/perf/time_series/{project_id}").Version(1).Get().RouteHandler(makeGetPerfTimeSeries(s.sc))
+	s.app.AddRoute("/perf/compare/version/{base_version}/{version}").Version(1).Get().RouteHandler(makeComparePerfByVersion(s.sc))
+	s.app.AddRoute("/perf/compare/task_id/{base_task_id}/{task_id}").Version(1).Get().RouteHandler(makeComparePerfByTaskId(s.sc))
 	s.app.AddRoute("/perf/task_id/{task_id}").Version(1).Get().RouteHandler(makeGetPerfByTaskId(s.sc))
 	s.app.AddRoute("/perf/task_id/{task_id}/count").Version(1).Get().RouteHandler(makeCountPerfByTaskId(s.sc))
 	s.app.AddRoute("/perf/task_name/{task_name}").Version(1).Get().RouteHandler(makeGetPerfByTaskName(s.sc))
 	s.app.AddRoute("/perf/version/{version}").Version(1).Get().RouteHandler(makeGetPerfByVersion(s.sc))
 
-	s.app.AddRoute("/buildlogger/{id}").Version(1).Get().Wrap(evgAuthReadLogByID).RouteHandler(makeGetLogByID(s.sc))
+	s.app.AddRoute("/buildlogger/search/{project_id}").Version(1).Get().Wrap(evgAuthReadLogByProject).RouteHandler(makeSearchLogs(s.sc))
+	s.app.AddRoute("/buildlogger/signatures/{project_id}").Version(1).Get().Wrap(evgAuthReadLogByProject).RouteHandler(makeGetLogFailureSignatures(s.sc))
+	s.app.AddRoute("/buildlogger/{id}").Version(1).Get().Wrap(evgAuthReadLogByID, logFollow).RouteHandler(makeGetLogByID(s.sc))
 	s.app.AddRoute("/buildlogger/{id}/meta").Version(1).Get().Wrap(evgAuthReadLogByID).RouteHandler(makeGetLogMetaByID(s.sc))
 	s.app.AddRoute("/buildlogger/task_id/{task_id}").Version(1).Get().Wrap(evgAuthReadLogByTaskID).RouteHandler(makeGetLogByTaskID(s.sc))
 	s.app.AddRoute("/buildlogger/task_id/{task_id}/meta").Version(1).Get().Wrap(evgAuthReadLogByTaskID).RouteHandler(makeGetLogMetaByTaskID(s.sc))
+	s.app.AddRoute("/buildlogger/task_id/{task_id}/archive").Version(1).Get().Wrap(evgAuthReadLogByTaskID, logArchive).RouteHandler(makeGetLogArchiveByTaskID(s.sc))
 	s.app.AddRoute("/buildlogger/task_id/{task_id}/group/{group_id}").Version(1).Get().Wrap(evgAuthReadLogByTaskID).RouteHandler(makeGetLogGroupByTaskID(s.sc))
 	s.app.AddRoute("/buildlogger/test_name/{task_id}/{test_name}").Version(1).Get().Wrap(evgAuthReadLogByTaskID).RouteHandler(makeGetLogByTestName(s.sc))
 	s.app.AddRoute("/buildlogger/test_name/{task_id}/{test_name}/meta").Version(1).Get().Wrap(evgAuthReadLogByTaskID).RouteHandler(makeGetLogMetaByTestName(s.sc))
 	s.app.AddRoute("/buildlogger/test_name/{task_id}/{test_name}/group/{group_id}").Version(1).Get().Wrap(evgAuthReadLogByTaskID).RouteHandler(makeGetLogGroupByTestName(s.sc))
+	s.app.AddRoute("/buildlogger/test_result/{task_id}/{test_name}").Version(1).Get().Wrap(evgAuthReadLogByTaskID).RouteHandler(makeGetLogByTestResult(s.sc))
 
 	s.app.AddRoute("/test_results/filtered_samples").Version(1).Get().RouteHandler(makeGetTestResultsFilteredSamples(s.sc))
 	s.app.AddRoute("/test_results/task_id/{task_id}").Version(1).Get().RouteHandler(makeGetTestResultsByTaskID(s.sc))
 	s.app.AddRoute("/test_results/task_id/{task_id}/failed_sample").Version(1).Get().RouteHandler(makeGetTestResultsFailedSample(s.sc))
 	s.app.AddRoute("/test_results/task_id/{task_id}/stats").Version(1).Get().RouteHandler(makeGetTestResultsStats(s.sc))
+	s.app.AddRoute("/test_results/task_id/{task_id}/diff").Version(1).Get().RouteHandler(makeGetTestResultsDiff(s.sc))
+	s.app.AddRoute("/test_results/task_id/{task_id}/upload").Version(1).Post().Wrap(checkUser).RouteHandler(makeUploadTestResults(s.sc))
+	s.app.AddRoute("/test_results/search/{project_id}").Version(1).Get().Wrap(evgAuthReadLogByProject).RouteHandler(makeSearchTestResults(s.sc))
+	s.app.AddRoute("/test_results/stats/{project_id}").Version(1).Get().RouteHandler(makeGetAggregatedTestResultsStats(s.sc))
+	s.app.AddRoute("/test_results/failures_by_team/{project_id}/{version}").Version(1).Get().RouteHandler(makeGetTestFailuresByTeam(s.sc))
 	// TODO: (EVG-15299) Remove these two routes once we are sure no one is
 	// using them. Keeping temporarily for backwards compatibility.
 	s.app.AddRoute("/test_results/display_task_id/{display_task_id}").Version(1).Get().RouteHandler(makeGetTestResultsByDisplayTaskID(s.sc))
 	s.app.AddRoute("/test_results/test_name/{task_id}/{test_name}").Version(1).Get().RouteHandler(makeGetTestResultByTestName(s.sc))
 
 	s.app.AddRoute("/historical_test_data/{project_id}").Version(1).Get().RouteHandler(makeGetHistoricalTestData(s.sc))
+	s.app.AddRoute("/flaky_tests/{project_id}").Version(1).Get().RouteHandler(makeGetFlakyTests(s.sc))
+	s.app.AddRoute("/test_duration_slowdowns/{project_id}").Version(1).Get().RouteHandler(makeGetTestDurationSlowdowns(s.sc))
+	s.app.AddRoute("/test_annotations/{project_id}").Version(1).Get().RouteHandler(makeGetTestAnnotations(s.sc))
+	s.app.AddRoute("/test_annotations/{project_id}").Version(1).Put().Wrap(checkUser).RouteHandler(makeSaveTestAnnotation(s.sc))
+	s.app.AddRoute("/test_annotations/{project_id}/{id}").Version(1).Get().RouteHandler(makeGetTestAnnotationByID(s.sc))
+	s.app.AddRoute("/test_annotations/{project_id}/{id}").Version(1).Delete().Wrap(checkUser).RouteHandler(makeRemoveTestAnnotation(s.sc))
 
 	s.app.AddRoute("/system_metrics/type/{task_id}/{type}").Version(1).Get().RouteHandler(makeGetSystemMetricsByType(s.sc))
 }
@@ -0,0 +1,241 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	testAnnotationsVariant        = "variant"
+	testAnnotationsTaskName       = "task_name"
+	testAnnotationsTestName       = "test_name"
+	testAnnotationsQuarantined    = "quarantined"
+	testAnnotationsIncludeExpired = "include_expired"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /test_annotations/{project_id}
+
+type testAnnotationsGetHandler struct {
+	sc     data.Connector
+	filter dbModel.TestAnnotationsFilter
+}
+
+func makeGetTestAnnotations(sc data.Connector) gimlet.RouteHandler {
+	return &testAnnotationsGetHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testAnnotationsGetHandler.
+func (h *testAnnotationsGetHandler) Factory() gimlet.RouteHandler {
+	return &testAnnotationsGetHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the filter from the HTTP request.
+func (h *testAnnotationsGetHandler) Parse(_ context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.filter = dbModel.TestAnnotationsFilter{
+		Project:         gimlet.GetVars(r)["project_id"],
+		Variant:         vals.Get(testAnnotationsVariant),
+		TaskName:        vals.Get(testAnnotationsTaskName),
+		TestName:        vals.Get(testAnnotationsTestName),
+		QuarantinedOnly: vals.Get(testAnnotationsQuarantined) == trueString,
+		IncludeExpired:  vals.Get(testAnnotationsIncludeExpired) == trueString,
+	}
+
+	return nil
+}
+
+// Run returns the test annotations of the project matching the filter.
+func (h *testAnnotationsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	annotations, err := h.sc.GetTestAnnotations(ctx, h.filter)
+	if err != nil {
+		err = errors.Wrapf(err, "getting test annotations for project '%s'", h.filter.Project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/test_annotations/{project_id}",
+			"project": h.filter.Project,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(annotations)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /test_annotations/{project_id}/{id}
+
+type testAnnotationGetByIDHandler struct {
+	sc      data.Connector
+	project string
+	id      string
+}
+
+func makeGetTestAnnotationByID(sc data.Connector) gimlet.RouteHandler {
+	return &testAnnotationGetByIDHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testAnnotationGetByIDHandler.
+func (h *testAnnotationGetByIDHandler) Factory() gimlet.RouteHandler {
+	return &testAnnotationGetByIDHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the test annotation ID from the HTTP
+// request.
+func (h *testAnnotationGetByIDHandler) Parse(_ context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.project = vars["project_id"]
+	h.id = vars["id"]
+
+	return nil
+}
+
+// Run returns the test annotation.
+func (h *testAnnotationGetByIDHandler) Run(ctx context.Context) gimlet.Responder {
+	annotation, err := h.sc.GetTestAnnotation(ctx, h.project, h.id)
+	if err != nil {
+		err = errors.Wrapf(err, "getting test annotation '%s'", h.id)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/test_annotations/{project_id}/{id}",
+			"project": h.project,
+			"id":      h.id,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(annotation)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// PUT /test_annotations/{project_id}
+
+type testAnnotationSaveHandler struct {
+	sc         data.Connector
+	annotation dbModel.TestAnnotation
+}
+
+func makeSaveTestAnnotation(sc data.Connector) gimlet.RouteHandler {
+	return &testAnnotationSaveHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testAnnotationSaveHandler.
+func (h *testAnnotationSaveHandler) Factory() gimlet.RouteHandler {
+	return &testAnnotationSaveHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID from the HTTP request and reads the test
+// annotation from the request body. The requesting user is recorded as the
+// last user to update the test annotation.
+func (h *testAnnotationSaveHandler) Parse(_ context.Context, r *http.Request) error {
+	project := gimlet.GetVars(r)["project_id"]
+
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+
+	apiAnnotation := &model.APITestAnnotation{}
+	if err := utility.ReadJSON(body, apiAnnotation); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "reading test annotation from request body").Error(),
+		}
+	}
+	if apiAnnotation.Project != nil && *apiAnnotation.Project != project {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Errorf("test annotation project '%s' does not match project '%s'", *apiAnnotation.Project, project).Error(),
+		}
+	}
+	apiAnnotation.Project = utility.ToStringPtr(project)
+	apiAnnotation.LastUpdatedBy = nil
+	if u := gimlet.GetUser(r.Context()); u != nil {
+		apiAnnotation.LastUpdatedBy = utility.ToStringPtr(u.Username())
+	}
+
+	annotation, err := apiAnnotation.Export()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "exporting test annotation").Error(),
+		}
+	}
+	h.annotation = annotation.(dbModel.TestAnnotation)
+
+	return nil
+}
+
+// Run saves the test annotation and returns it.
+func (h *testAnnotationSaveHandler) Run(ctx context.Context) gimlet.Responder {
+	annotation, err := h.sc.SaveTestAnnotation(ctx, h.annotation)
+	if err != nil {
+		err = errors.Wrapf(err, "saving test annotation for project '%s'", h.annotation.Project)
+		logFindError(err, message.Fields{
+			"request":   gimlet.GetRequestID(ctx),
+			"method":    "PUT",
+			"route":     "/test_annotations/{project_id}",
+			"project":   h.annotation.Project,
+			"test_name": h.annotation.TestName,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(annotation)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// DELETE /test_annotations/{project_id}/{id}
+
+type testAnnotationRemoveHandler struct {
+	sc      data.Connector
+	project string
+	id      string
+}
+
+func makeRemoveTestAnnotation(sc data.Connector) gimlet.RouteHandler {
+	return &testAnnotationRemoveHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testAnnotationRemoveHandler.
+func (h *testAnnotationRemoveHandler) Factory() gimlet.RouteHandler {
+	return &testAnnotationRemoveHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the test annotation ID from the HTTP
+// request.
+func (h *testAnnotationRemoveHandler) Parse(_ context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.project = vars["project_id"]
+	h.id = vars["id"]
+
+	return nil
+}
+
+// Run removes the test annotation from the project.
+func (h *testAnnotationRemoveHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.RemoveTestAnnotation(ctx, h.project, h.id); err != nil {
+		err = errors.Wrapf(err, "removing test annotation '%s'", h.id)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "DELETE",
+			"route":   "/test_annotations/{project_id}/{id}",
+			"project": h.project,
+			"id":      h.id,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestAnnotationsGetHandlerParse(t *testing.T) {
+	req := &http.Request{Method: http.MethodGet}
+	req.URL, _ = url.Parse("https://cedar.mongodb.com/test_annotations/project?variant=variant&task_name=task&test_name=test&quarantined=true&include_expired=true")
+	req = gimlet.SetURLVars(req, map[string]string{"project_id": "project"})
+
+	handler := makeGetTestAnnotations(&data.MockConnector{}).(*testAnnotationsGetHandler)
+	require.NoError(t, handler.Parse(context.Background(), req))
+	assert.Equal(t, dbModel.TestAnnotationsFilter{
+		Project:         "project",
+		Variant:         "variant",
+		TaskName:        "task",
+		TestName:        "test",
+		QuarantinedOnly: true,
+		IncludeExpired:  true,
+	}, handler.filter)
+}
+
+func TestTestAnnotationSaveHandlerParse(t *testing.T) {
+	newRequest := func(body string, user gimlet.User) *http.Request {
+		req := &http.Request{Method: http.MethodPut}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/test_annotations/project")
+		req.Body = http.NoBody
+		if body != "" {
+			req.Body = ioutil.NopCloser(bytes.NewBufferString(body))
+		}
+		if user != nil {
+			req = req.WithContext(gimlet.AttachUser(req.Context(), user))
+		}
+		return gimlet.SetURLVars(req, map[string]string{"project_id": "project"})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		handler := makeSaveTestAnnotation(&data.MockConnector{}).(*testAnnotationSaveHandler)
+		opts, err := gimlet.NewBasicUserOptions("admin")
+		require.NoError(t, err)
+		user := gimlet.NewBasicUser(opts)
+		body := `{"variant": "variant", "test_name": "TestFlaky", "note": "flaky", "tickets": ["BF-1"], "quarantined": true, "expires_at": "2030-01-02T00:00:00.000Z", "last_updated_by": "someone"}`
+		require.NoError(t, handler.Parse(context.Background(), newRequest(body, user)))
+		assert.Equal(t, dbModel.TestAnnotation{
+			Project:       "project",
+			Variant:       "variant",
+			TestName:      "TestFlaky",
+			Note:          "flaky",
+			Tickets:       []string{"BF-1"},
+			Quarantined:   true,
+			ExpiresAt:     time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+			LastUpdatedBy: "admin",
+		}, handler.annotation)
+	})
+	t.Run("MatchingProject", func(t *testing.T) {
+		handler := makeSaveTestAnnotation(&data.MockConnector{}).(*testAnnotationSaveHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest(`{"project": "project", "test_name": "TestFlaky", "quarantined": true}`, nil)))
+		assert.Equal(t, "project", handler.annotation.Project)
+		assert.Empty(t, handler.annotation.LastUpdatedBy)
+	})
+	t.Run("MismatchedProject", func(t *testing.T) {
+		handler := makeSaveTestAnnotation(&data.MockConnector{}).(*testAnnotationSaveHandler)
+		assert.Error(t, handler.Parse(context.Background(), newRequest(`{"project": "other", "test_name": "TestFlaky", "quarantined": true}`, nil)))
+	})
+	t.Run("InvalidBody", func(t *testing.T) {
+		handler := makeSaveTestAnnotation(&data.MockConnector{}).(*testAnnotationSaveHandler)
+		assert.Error(t, handler.Parse(context.Background(), newRequest("{", nil)))
+	})
+}
+
+func TestTestAnnotationHandlersRun(t *testing.T) {
+	ctx := context.Background()
+	sc := &data.MockConnector{}
+
+	saveHandler := makeSaveTestAnnotation(sc).(*testAnnotationSaveHandler)
+	for _, annotation := range []dbModel.TestAnnotation{
+		{Project: "project", Variant: "variant", TestName: "TestFlaky", Quarantined: true, LastUpdatedBy: "admin"},
+		{Project: "project", TestName: "TestBroken", Tickets: []string{"BF-1"}, LastUpdatedBy: "admin"},
+		{Project: "other", TestName: "TestFlaky", Note: "other project"},
+	} {
+		saveHandler.annotation = annotation
+		resp := saveHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		saved, ok := resp.Data().(*model.APITestAnnotation)
+		require.True(t, ok)
+		assert.Equal(t, dbModel.TestAnnotationID(annotation.Project, annotation.Variant, annotation.TaskName, annotation.TestName), utility.FromStringPtr(saved.ID))
+		assert.Equal(t, annotation.LastUpdatedBy, utility.FromStringPtr(saved.CreatedBy))
+		assert.Equal(t, annotation.LastUpdatedBy, utility.FromStringPtr(saved.LastUpdatedBy))
+	}
+
+	t.Run("SaveKeepsCreator", func(t *testing.T) {
+		saveHandler.annotation = dbModel.TestAnnotation{Project: "project", TestName: "TestBroken", Note: "still broken", LastUpdatedBy: "someone"}
+		resp := saveHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		saved, ok := resp.Data().(*model.APITestAnnotation)
+		require.True(t, ok)
+		assert.Equal(t, "admin", utility.FromStringPtr(saved.CreatedBy))
+		assert.Equal(t, "someone", utility.FromStringPtr(saved.LastUpdatedBy))
+	})
+	t.Run("SaveInvalid", func(t *testing.T) {
+		saveHandler.annotation = dbModel.TestAnnotation{Project: "project", TestName: "TestFlaky"}
+		resp := saveHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+	t.Run("Get", func(t *testing.T) {
+		getHandler := makeGetTestAnnotations(sc).(*testAnnotationsGetHandler)
+		getHandler.filter = dbModel.TestAnnotationsFilter{Project: "project"}
+		resp := getHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		annotations, ok := resp.Data().([]model.APITestAnnotation)
+		require.True(t, ok)
+		require.Len(t, annotations, 2)
+		assert.Equal(t, "TestBroken", utility.FromStringPtr(annotations[0].TestName))
+		assert.Equal(t, "TestFlaky", utility.FromStringPtr(annotations[1].TestName))
+
+		getHandler.filter.QuarantinedOnly = true
+		resp = getHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		annotations, ok = resp.Data().([]model.APITestAnnotation)
+		require.True(t, ok)
+		require.Len(t, annotations, 1)
+		assert.Equal(t, "TestFlaky", utility.FromStringPtr(annotations[0].TestName))
+	})
+	t.Run("GetByID", func(t *testing.T) {
+		getHandler := makeGetTestAnnotationByID(sc).(*testAnnotationGetByIDHandler)
+		getHandler.project = "project"
+		getHandler.id = dbModel.TestAnnotationID("project", "variant", "", "TestFlaky")
+		resp := getHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		annotation, ok := resp.Data().(*model.APITestAnnotation)
+		require.True(t, ok)
+		assert.True(t, annotation.Quarantined)
+
+		getHandler.project = "other"
+		resp = getHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+	t.Run("RemoveFromOtherProject", func(t *testing.T) {
+		removeHandler := makeRemoveTestAnnotation(sc).(*testAnnotationRemoveHandler)
+		removeHandler.project = "other"
+		removeHandler.id = dbModel.TestAnnotationID("project", "", "", "TestBroken")
+		resp := removeHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+	t.Run("Remove", func(t *testing.T) {
+		removeHandler := makeRemoveTestAnnotation(sc).(*testAnnotationRemoveHandler)
+		removeHandler.project = "project"
+		removeHandler.id = dbModel.TestAnnotationID("project", "", "", "TestBroken")
+		resp := removeHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		assert.Len(t, sc.CachedTestAnnotations, 2)
+
+		resp = removeHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
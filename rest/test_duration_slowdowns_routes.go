@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	testDurationSlowdownsAPIMaxLimit     = 1000
+	testDurationSlowdownsAPIDefaultLimit = 100
+	testDurationSlowdownsAPIDefaultDays  = 7
+	testDurationSlowdownsAPIMaxNumTasks  = 50
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /test_duration_slowdowns/{project_id}
+
+type testDurationSlowdownsHandler struct {
+	filter model.TestDurationSlowdownsFilter
+	sc     data.Connector
+}
+
+func makeGetTestDurationSlowdowns(sc data.Connector) gimlet.RouteHandler {
+	return &testDurationSlowdownsHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testDurationSlowdownsHandler.
+func (h *testDurationSlowdownsHandler) Factory() gimlet.RouteHandler {
+	return &testDurationSlowdownsHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the filter options from the http request.
+func (h *testDurationSlowdownsHandler) Parse(_ context.Context, r *http.Request) error {
+	h.filter = model.TestDurationSlowdownsFilter{Project: gimlet.GetVars(r)["project_id"]}
+
+	if err := h.parse(r.URL.Query()); err != nil {
+		return errors.Wrap(err, "invalid query parameters")
+	}
+
+	if err := h.filter.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// parse parses the query parameter values and fills the filter. The variants
+// and tasks values are parsed the same way as the flaky tests'. The date range
+// defaults to the last week, including today.
+func (h *testDurationSlowdownsHandler) parse(vals url.Values) error {
+	var (
+		htd htdFilterHandler
+		err error
+	)
+
+	h.filter.Variants = htd.readStringList(vals["variants"])
+	h.filter.Tasks = htd.readStringList(vals["tasks"])
+	if len(h.filter.Tasks) > testDurationSlowdownsAPIMaxNumTasks {
+		return gimlet.ErrorResponse{
+			Message:    "too many tasks values",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.filter.Limit, err = htd.readInt(vals.Get("limit"), 1, testDurationSlowdownsAPIMaxLimit, testDurationSlowdownsAPIDefaultLimit)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			Message:    "invalid limit value",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.filter.BeforeDate = utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+	if beforeDate := vals.Get("before_date"); beforeDate != "" {
+		h.filter.BeforeDate, err = time.ParseInLocation(htdAPIDateFormat, beforeDate, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    "invalid before_date value",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	h.filter.AfterDate = h.filter.BeforeDate.AddDate(0, 0, -testDurationSlowdownsAPIDefaultDays)
+	if afterDate := vals.Get("after_date"); afterDate != "" {
+		h.filter.AfterDate, err = time.ParseInLocation(htdAPIDateFormat, afterDate, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    "invalid after_date value",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run returns the test duration slowdowns of the project, most recent first.
+func (h *testDurationSlowdownsHandler) Run(ctx context.Context) gimlet.Responder {
+	slowdowns, err := h.sc.GetTestDurationSlowdowns(ctx, h.filter)
+	if err != nil {
+		err = errors.Wrapf(err, "getting test duration slowdowns for project '%s'", h.filter.Project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/test_duration_slowdowns/{project_id}",
+			"project": h.filter.Project,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(slowdowns)
+}
@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestDurationSlowdownsHandlerParse(t *testing.T) {
+	t.Run("AllValues", func(t *testing.T) {
+		values := url.Values{
+			"after_date":  []string{"2018-07-01"},
+			"before_date": []string{"2018-07-15"},
+			"tasks":       []string{"task1,task2"},
+			"variants":    []string{"v1", "v2"},
+			"limit":       []string{"20"},
+		}
+		handler := testDurationSlowdownsHandler{}
+		require.NoError(t, handler.parse(values))
+
+		assert.Equal(t, time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC), handler.filter.AfterDate)
+		assert.Equal(t, time.Date(2018, 7, 15, 0, 0, 0, 0, time.UTC), handler.filter.BeforeDate)
+		assert.Equal(t, []string{"task1", "task2"}, handler.filter.Tasks)
+		assert.Equal(t, values["variants"], handler.filter.Variants)
+		assert.Equal(t, 20, handler.filter.Limit)
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		handler := testDurationSlowdownsHandler{}
+		require.NoError(t, handler.parse(url.Values{}))
+
+		tomorrow := utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+		assert.Equal(t, tomorrow, handler.filter.BeforeDate)
+		assert.Equal(t, tomorrow.AddDate(0, 0, -testDurationSlowdownsAPIDefaultDays), handler.filter.AfterDate)
+		assert.Equal(t, testDurationSlowdownsAPIDefaultLimit, handler.filter.Limit)
+		assert.Empty(t, handler.filter.Tasks)
+		assert.Empty(t, handler.filter.Variants)
+	})
+	t.Run("InvalidValues", func(t *testing.T) {
+		tasks := make([]string, testDurationSlowdownsAPIMaxNumTasks+1)
+		for i := range tasks {
+			tasks[i] = "task"
+		}
+		for _, values := range []url.Values{
+			{"limit": []string{"0"}},
+			{"limit": []string{"1001"}},
+			{"tasks": tasks},
+			{"after_date": []string{"07-01-2018"}},
+			{"before_date": []string{"yesterday"}},
+		} {
+			handler := testDurationSlowdownsHandler{}
+			assert.Error(t, handler.parse(values))
+		}
+	})
+}
+
+func TestTestDurationSlowdownsHandlerRun(t *testing.T) {
+	day := utility.GetUTCDay(time.Now())
+	newSlowdown := func(variant, taskName, testName string) dbModel.TestDurationSlowdown {
+		return dbModel.TestDurationSlowdown{
+			Info: dbModel.TestDurationSlowdownInfo{
+				Project:  "project",
+				Variant:  variant,
+				TaskName: taskName,
+				TestName: testName,
+				Date:     day,
+			},
+			Baseline: dbModel.TestDurationStats{NumRuns: 10, Median: time.Second, P90: time.Second},
+			Recent:   dbModel.TestDurationStats{NumRuns: 5, Median: 2 * time.Second, P90: 2 * time.Second},
+			PValue:   0.001,
+		}
+	}
+	sc := &data.MockConnector{
+		CachedTestDurationSlowdowns: []dbModel.TestDurationSlowdown{
+			newSlowdown("v1", "task1", "test1"),
+			newSlowdown("v1", "task2", "test2"),
+			newSlowdown("v2", "task1", "test3"),
+		},
+	}
+	handler := makeGetTestDurationSlowdowns(sc).(*testDurationSlowdownsHandler)
+
+	t.Run("All", func(t *testing.T) {
+		handler.filter = dbModel.TestDurationSlowdownsFilter{Project: "project", Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		slowdowns, ok := resp.Data().([]model.APITestDurationSlowdown)
+		require.True(t, ok)
+		require.Len(t, slowdowns, 3)
+		for i, slowdown := range slowdowns {
+			expected := model.APITestDurationSlowdown{}
+			require.NoError(t, expected.Import(sc.CachedTestDurationSlowdowns[i]))
+			assert.Equal(t, expected, slowdown)
+		}
+	})
+	t.Run("Filtered", func(t *testing.T) {
+		handler.filter = dbModel.TestDurationSlowdownsFilter{Project: "project", Variants: []string{"v1"}, Tasks: []string{"task1"}, Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		slowdowns, ok := resp.Data().([]model.APITestDurationSlowdown)
+		require.True(t, ok)
+		require.Len(t, slowdowns, 1)
+		assert.Equal(t, "test1", utility.FromStringPtr(slowdowns[0].TestName))
+	})
+	t.Run("Limit", func(t *testing.T) {
+		handler.filter = dbModel.TestDurationSlowdownsFilter{Project: "project", Limit: 2}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		slowdowns, ok := resp.Data().([]model.APITestDurationSlowdown)
+		require.True(t, ok)
+		assert.Len(t, slowdowns, 2)
+	})
+}
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /admin/test_owners/{project_id}
+
+type testOwnershipsGetHandler struct {
+	sc      data.Connector
+	project string
+}
+
+func makeGetTestOwnerships(sc data.Connector) gimlet.RouteHandler {
+	return &testOwnershipsGetHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testOwnershipsGetHandler.
+func (h *testOwnershipsGetHandler) Factory() gimlet.RouteHandler {
+	return &testOwnershipsGetHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID from the HTTP request.
+func (h *testOwnershipsGetHandler) Parse(_ context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+	return nil
+}
+
+// Run returns the test ownerships of the project.
+func (h *testOwnershipsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	ownerships, err := h.sc.GetTestOwnerships(ctx, h.project)
+	if err != nil {
+		err = errors.Wrapf(err, "getting test ownerships for project '%s'", h.project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/admin/test_owners/{project_id}",
+			"project": h.project,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(ownerships)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// PUT /admin/test_owners/{project_id}
+
+type testOwnershipSaveHandler struct {
+	sc        data.Connector
+	ownership dbModel.TestOwnership
+}
+
+func makeSaveTestOwnership(sc data.Connector) gimlet.RouteHandler {
+	return &testOwnershipSaveHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testOwnershipSaveHandler.
+func (h *testOwnershipSaveHandler) Factory() gimlet.RouteHandler {
+	return &testOwnershipSaveHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID from the HTTP request and reads the test
+// ownership from the request body. The requesting user is recorded as the
+// last user to update the test ownership.
+func (h *testOwnershipSaveHandler) Parse(_ context.Context, r *http.Request) error {
+	project := gimlet.GetVars(r)["project_id"]
+
+	body := utility.NewRequestReader(r)
+	defer body.Close()
+
+	apiOwnership := &model.APITestOwnership{}
+	if err := utility.ReadJSON(body, apiOwnership); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "reading test ownership from request body").Error(),
+		}
+	}
+	if apiOwnership.Project != nil && *apiOwnership.Project != project {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Errorf("test ownership project '%s' does not match project '%s'", *apiOwnership.Project, project).Error(),
+		}
+	}
+	apiOwnership.Project = utility.ToStringPtr(project)
+	apiOwnership.LastUpdatedBy = nil
+	if u := gimlet.GetUser(r.Context()); u != nil {
+		apiOwnership.LastUpdatedBy = utility.ToStringPtr(u.Username())
+	}
+
+	ownership, err := apiOwnership.Export()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "exporting test ownership").Error(),
+		}
+	}
+	h.ownership = ownership.(dbModel.TestOwnership)
+
+	return nil
+}
+
+// Run saves the test ownership and returns it.
+func (h *testOwnershipSaveHandler) Run(ctx context.Context) gimlet.Responder {
+	ownership, err := h.sc.SaveTestOwnership(ctx, h.ownership)
+	if err != nil {
+		err = errors.Wrapf(err, "saving test ownership for project '%s'", h.ownership.Project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "PUT",
+			"route":   "/admin/test_owners/{project_id}",
+			"project": h.ownership.Project,
+			"pattern": h.ownership.TestNamePattern,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(ownership)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// DELETE /admin/test_owners/{project_id}/{id}
+
+type testOwnershipRemoveHandler struct {
+	sc      data.Connector
+	project string
+	id      string
+}
+
+func makeRemoveTestOwnership(sc data.Connector) gimlet.RouteHandler {
+	return &testOwnershipRemoveHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testOwnershipRemoveHandler.
+func (h *testOwnershipRemoveHandler) Factory() gimlet.RouteHandler {
+	return &testOwnershipRemoveHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the test ownership ID from the HTTP
+// request.
+func (h *testOwnershipRemoveHandler) Parse(_ context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.project = vars["project_id"]
+	h.id = vars["id"]
+
+	return nil
+}
+
+// Run removes the test ownership from the project.
+func (h *testOwnershipRemoveHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.RemoveTestOwnership(ctx, h.project, h.id); err != nil {
+		err = errors.Wrapf(err, "removing test ownership '%s'", h.id)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "DELETE",
+			"route":   "/admin/test_owners/{project_id}/{id}",
+			"project": h.project,
+			"id":      h.id,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
@@ -0,0 +1,160 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestOwnershipSaveHandlerParse(t *testing.T) {
+	newRequest := func(body string, user gimlet.User) *http.Request {
+		req := &http.Request{Method: http.MethodPut}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/admin/test_owners/project")
+		req.Body = http.NoBody
+		if body != "" {
+			req.Body = ioutil.NopCloser(bytes.NewBufferString(body))
+		}
+		if user != nil {
+			req = req.WithContext(gimlet.AttachUser(req.Context(), user))
+		}
+		return gimlet.SetURLVars(req, map[string]string{"project_id": "project"})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		handler := makeSaveTestOwnership(&data.MockConnector{}).(*testOwnershipSaveHandler)
+		opts, err := gimlet.NewBasicUserOptions("admin")
+		require.NoError(t, err)
+		user := gimlet.NewBasicUser(opts)
+		body := `{"test_name_pattern": "^TestStorage", "owner": "owner", "team": "team", "last_updated_by": "someone"}`
+		require.NoError(t, handler.Parse(context.Background(), newRequest(body, user)))
+		assert.Equal(t, dbModel.TestOwnership{
+			Project:         "project",
+			TestNamePattern: "^TestStorage",
+			Owner:           "owner",
+			Team:            "team",
+			LastUpdatedBy:   "admin",
+		}, handler.ownership)
+	})
+	t.Run("MatchingProject", func(t *testing.T) {
+		handler := makeSaveTestOwnership(&data.MockConnector{}).(*testOwnershipSaveHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest(`{"project": "project", "test_name_pattern": "^Test", "team": "team"}`, nil)))
+		assert.Equal(t, "project", handler.ownership.Project)
+		assert.Empty(t, handler.ownership.LastUpdatedBy)
+	})
+	t.Run("MismatchedProject", func(t *testing.T) {
+		handler := makeSaveTestOwnership(&data.MockConnector{}).(*testOwnershipSaveHandler)
+		assert.Error(t, handler.Parse(context.Background(), newRequest(`{"project": "other", "test_name_pattern": "^Test", "team": "team"}`, nil)))
+	})
+	t.Run("InvalidBody", func(t *testing.T) {
+		handler := makeSaveTestOwnership(&data.MockConnector{}).(*testOwnershipSaveHandler)
+		assert.Error(t, handler.Parse(context.Background(), newRequest("{", nil)))
+	})
+}
+
+func TestTestOwnershipHandlersRun(t *testing.T) {
+	ctx := context.Background()
+	sc := &data.MockConnector{}
+
+	saveHandler := makeSaveTestOwnership(sc).(*testOwnershipSaveHandler)
+	for _, ownership := range []dbModel.TestOwnership{
+		{Project: "project", TestNamePattern: "^TestStorage", Owner: "owner", Team: "storage", LastUpdatedBy: "admin"},
+		{Project: "project", TestNamePattern: "^TestQuery", Team: "query"},
+		{Project: "other", TestNamePattern: "^Test", Team: "other"},
+	} {
+		saveHandler.ownership = ownership
+		resp := saveHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		saved, ok := resp.Data().(*model.APITestOwnership)
+		require.True(t, ok)
+		assert.Equal(t, dbModel.TestOwnershipID(ownership.Project, ownership.TestNamePattern), utility.FromStringPtr(saved.ID))
+		assert.Equal(t, ownership.LastUpdatedBy, utility.FromStringPtr(saved.LastUpdatedBy))
+	}
+
+	t.Run("SaveInvalid", func(t *testing.T) {
+		saveHandler.ownership = dbModel.TestOwnership{Project: "project", TestNamePattern: "^TestStorage"}
+		resp := saveHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+	t.Run("Get", func(t *testing.T) {
+		getHandler := makeGetTestOwnerships(sc).(*testOwnershipsGetHandler)
+		getHandler.project = "project"
+		resp := getHandler.Run(ctx)
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		ownerships, ok := resp.Data().([]model.APITestOwnership)
+		require.True(t, ok)
+		require.Len(t, ownerships, 2)
+		assert.Equal(t, "^TestQuery", utility.FromStringPtr(ownerships[0].TestNamePattern))
+		assert.Equal(t, "^TestStorage", utility.FromStringPtr(ownerships[1].TestNamePattern))
+	})
+	t.Run("RemoveFromOtherProject", func(t *testing.T) {
+		removeHandler := makeRemoveTestOwnership(sc).(*testOwnershipRemoveHandler)
+		removeHandler.project = "other"
+		removeHandler.id = dbModel.TestOwnershipID("project", "^TestQuery")
+		resp := removeHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+	t.Run("Remove", func(t *testing.T) {
+		removeHandler := makeRemoveTestOwnership(sc).(*testOwnershipRemoveHandler)
+		removeHandler.project = "project"
+		removeHandler.id = dbModel.TestOwnershipID("project", "^TestQuery")
+		resp := removeHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		assert.Len(t, sc.CachedTestOwnerships, 2)
+
+		resp = removeHandler.Run(ctx)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
+
+func TestTestResultsFailuresByTeamHandlerRun(t *testing.T) {
+	sc := &data.MockConnector{
+		CachedTestFailuresByTeam: map[string][]dbModel.TeamTestFailures{
+			"version": {
+				{
+					Team:        "storage",
+					NumFailures: 2,
+					FailedTests: []dbModel.OwnedTestFailures{{TestName: "TestStorage", Owner: "owner", NumFailures: 2}},
+				},
+			},
+		},
+	}
+	handler := makeGetTestFailuresByTeam(sc).(*testResultsFailuresByTeamHandler)
+	handler.project = "project"
+	handler.version = "version"
+
+	resp := handler.Run(context.Background())
+	require.NotNil(t, resp)
+	require.Equal(t, http.StatusOK, resp.Status())
+	byTeam, ok := resp.Data().([]model.APITeamTestFailures)
+	require.True(t, ok)
+	require.Len(t, byTeam, 1)
+	assert.Equal(t, "storage", utility.FromStringPtr(byTeam[0].Team))
+	assert.Equal(t, 2, byTeam[0].NumFailures)
+	require.Len(t, byTeam[0].FailedTests, 1)
+	assert.Equal(t, "owner", utility.FromStringPtr(byTeam[0].FailedTests[0].Owner))
+
+	handler.version = "other"
+	resp = handler.Run(context.Background())
+	require.NotNil(t, resp)
+	require.Equal(t, http.StatusOK, resp.Status())
+	byTeam, ok = resp.Data().([]model.APITeamTestFailures)
+	require.True(t, ok)
+	assert.Empty(t, byTeam)
+}
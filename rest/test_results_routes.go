@@ -1,11 +1,17 @@
 package rest
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/cedar/rest/data"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
@@ -24,6 +30,34 @@ const (
 	testResultsLimit      = "limit"
 	testResultsPage       = "page"
 	testResultsBaseTaskID = "base_task_id"
+	testResultsAttribute  = "attribute"
+
+	testResultsDiffBaseExecution           = "base_execution"
+	testResultsDiffDurationChangeThreshold = "duration_change_threshold"
+	testResultsDiffMinDurationChange       = "min_duration_change"
+
+	testResultsUploadFormat                 = "format"
+	testResultsUploadProject                = "project"
+	testResultsUploadVersion                = "version"
+	testResultsUploadVariant                = "variant"
+	testResultsUploadTaskName               = "task_name"
+	testResultsUploadDisplayTaskName        = "display_task_name"
+	testResultsUploadDisplayTaskID          = "display_task_id"
+	testResultsUploadRequestType            = "request_type"
+	testResultsUploadMainline               = "mainline"
+	testResultsUploadHistoricalDataDisabled = "historical_data_disabled"
+	testResultsUploadMaxReportSize          = 16 * 1024 * 1024
+
+	testResultsSearchVersion      = "version"
+	testResultsSearchVariant      = "variant"
+	testResultsSearchTaskName     = "task_name"
+	testResultsSearchAfterDate    = "after_date"
+	testResultsSearchBeforeDate   = "before_date"
+	testResultsSearchDefaultLimit = 100
+	testResultsSearchMaxLimit     = 1000
+	testResultsSearchDefaultDays  = 7
+
+	testResultsWithOwners = "with_owners"
 )
 
 type testResultsBaseHandler struct {
@@ -76,6 +110,8 @@ func (h *testResultsGetByTaskIDHandler) Parse(ctx context.Context, r *http.Reque
 	groupID := vals.Get(testResultsGroupID)
 	sortBy := vals.Get(testResultsSortBy)
 	baseTaskID := vals.Get(testResultsBaseTaskID)
+	attributes, err := parseTestResultsAttributes(vals[testResultsAttribute])
+	catcher.Add(err)
 	var limit, page int
 	if len(vals[testResultsLimit]) > 0 {
 		var err error
@@ -88,17 +124,18 @@ func (h *testResultsGetByTaskIDHandler) Parse(ctx context.Context, r *http.Reque
 		catcher.Add(err)
 	}
 
-	if testName == "" && len(statuses) == 0 && groupID == "" && sortBy == "" && baseTaskID == "" && limit <= 0 && page <= 0 {
+	if testName == "" && len(statuses) == 0 && groupID == "" && len(attributes) == 0 && sortBy == "" && baseTaskID == "" && limit <= 0 && page <= 0 {
 		return catcher.Resolve()
 	}
 
 	h.opts.FilterAndSort = &data.TestResultsFilterAndSortOptions{
-		TestName: testName,
-		Statuses: statuses,
-		GroupID:  groupID,
-		SortBy:   sortBy,
-		Limit:    limit,
-		Page:     page,
+		TestName:   testName,
+		Statuses:   statuses,
+		GroupID:    groupID,
+		Attributes: attributes,
+		SortBy:     sortBy,
+		Limit:      limit,
+		Page:       page,
 	}
 	if vals.Get(testResultsSortDSC) == trueString {
 		h.opts.FilterAndSort.SortOrderDSC = true
@@ -113,6 +150,25 @@ func (h *testResultsGetByTaskIDHandler) Parse(ctx context.Context, r *http.Reque
 	return catcher.Resolve()
 }
 
+// parseTestResultsAttributes parses the attribute filters, each of the form
+// "key:value", into a map of attribute keys to values.
+func parseTestResultsAttributes(attributes []string) (map[string]string, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		parts := strings.SplitN(attribute, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid attribute filter '%s', must be of the form 'key:value'", attribute)
+		}
+		parsed[parts[0]] = parts[1]
+	}
+
+	return parsed, nil
+}
+
 // Factory returns a pointer to a new testResultsGetByTaskIDHandler.
 func (h *testResultsGetByTaskIDHandler) Factory() gimlet.RouteHandler {
 	return &testResultsGetByTaskIDHandler{
@@ -221,7 +277,8 @@ func (h *testResultsGetFilteredSamplesHandler) Run(ctx context.Context) gimlet.R
 // GET /test_results/task_id/{task_id}/failed_sample
 
 type testResultsGetFailedSampleHandler struct {
-	sc data.Connector
+	sc         data.Connector
+	withOwners bool
 	testResultsBaseHandler
 }
 
@@ -238,9 +295,30 @@ func (h *testResultsGetFailedSampleHandler) Factory() gimlet.RouteHandler {
 	}
 }
 
-// Run finds and returns the desired failed test results sample.
+// Parse fetches the task ID from the HTTP request and whether to return the
+// owner and team of each test in the sample.
+func (h *testResultsGetFailedSampleHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := h.testResultsBaseHandler.Parse(ctx, r); err != nil {
+		return err
+	}
+	h.withOwners = r.URL.Query().Get(testResultsWithOwners) == trueString
+
+	return nil
+}
+
+// Run finds and returns the desired failed test results sample. If owners
+// are requested, each test name in the sample is returned along with its
+// owner and team and whether it is quarantined.
 func (h *testResultsGetFailedSampleHandler) Run(ctx context.Context) gimlet.Responder {
-	sample, err := h.sc.GetFailedTestResultsSample(ctx, h.opts)
+	var (
+		sample interface{}
+		err    error
+	)
+	if h.withOwners {
+		sample, err = h.sc.GetOwnedFailedTestResultsSample(ctx, h.opts)
+	} else {
+		sample, err = h.sc.GetFailedTestResultsSample(ctx, h.opts)
+	}
 	if err != nil {
 		err = errors.Wrapf(err, "getting failed test results sample by task ID '%s'", h.opts.TaskID)
 		logFindError(err, message.Fields{
@@ -296,6 +374,330 @@ func (h *testResultsGetStatsHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(stats)
 }
 
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /test_results/task_id/{task_id}/diff
+
+type testResultsGetDiffHandler struct {
+	sc   data.Connector
+	opts data.TestResultsDiffOptions
+	testResultsBaseHandler
+}
+
+func makeGetTestResultsDiff(sc data.Connector) gimlet.RouteHandler {
+	return &testResultsGetDiffHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new testResultsGetDiffHandler.
+func (h *testResultsGetDiffHandler) Factory() gimlet.RouteHandler {
+	return &testResultsGetDiffHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the task ID and the base task ID from the HTTP request, along
+// with their executions and the duration change thresholds.
+func (h *testResultsGetDiffHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := h.testResultsBaseHandler.Parse(ctx, r); err != nil {
+		return err
+	}
+	h.opts = data.TestResultsDiffOptions{Current: h.testResultsBaseHandler.opts}
+
+	vals := r.URL.Query()
+	catcher := grip.NewBasicCatcher()
+	h.opts.Base = data.TestResultsOptions{
+		TaskID:      vals.Get(testResultsBaseTaskID),
+		DisplayTask: h.opts.Current.DisplayTask,
+	}
+	catcher.NewWhen(h.opts.Base.TaskID == "", "must specify a base task ID")
+	if len(vals[testResultsDiffBaseExecution]) > 0 {
+		exec, err := strconv.Atoi(vals[testResultsDiffBaseExecution][0])
+		catcher.Wrap(err, "invalid base execution value")
+		h.opts.Base.Execution = utility.ToIntPtr(exec)
+	}
+	if len(vals[testResultsDiffDurationChangeThreshold]) > 0 {
+		threshold, err := strconv.ParseFloat(vals[testResultsDiffDurationChangeThreshold][0], 64)
+		catcher.Wrap(err, "invalid duration change threshold value")
+		catcher.NewWhen(threshold < 0, "duration change threshold cannot be negative")
+		h.opts.DurationChangeThreshold = threshold
+	}
+	if len(vals[testResultsDiffMinDurationChange]) > 0 {
+		secs, err := strconv.ParseFloat(vals[testResultsDiffMinDurationChange][0], 64)
+		catcher.Wrap(err, "invalid minimum duration change value")
+		catcher.NewWhen(secs < 0, "minimum duration change cannot be negative")
+		h.opts.MinDurationChange = time.Duration(secs * float64(time.Second))
+	}
+	if catcher.HasErrors() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    catcher.Resolve().Error(),
+		}
+	}
+
+	return nil
+}
+
+// Run finds and returns the diff of the test results of the task against the
+// test results of the base task.
+func (h *testResultsGetDiffHandler) Run(ctx context.Context) gimlet.Responder {
+	diff, err := h.sc.DiffTestResults(ctx, h.opts)
+	if err != nil {
+		err = errors.Wrapf(err, "getting test results diff for task ID '%s' against base task ID '%s'", h.opts.Current.TaskID, h.opts.Base.TaskID)
+		logFindError(err, message.Fields{
+			"request":         gimlet.GetRequestID(ctx),
+			"method":          "GET",
+			"route":           "/test_results/task_id/{task_id}/diff",
+			"task_id":         h.opts.Current.TaskID,
+			"base_task_id":    h.opts.Base.TaskID,
+			"is_display_task": h.opts.Current.DisplayTask,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(diff)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// POST /test_results/task_id/{task_id}/upload
+
+type testResultsUploadHandler struct {
+	sc     data.Connector
+	info   model.TestResultsInfo
+	format model.TestResultsFormat
+	report []byte
+}
+
+func makeUploadTestResults(sc data.Connector) gimlet.RouteHandler {
+	return &testResultsUploadHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new testResultsUploadHandler.
+func (h *testResultsUploadHandler) Factory() gimlet.RouteHandler {
+	return &testResultsUploadHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the task ID and test results info from the HTTP request and
+// reads the test results report from the request body.
+func (h *testResultsUploadHandler) Parse(_ context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.info = model.TestResultsInfo{
+		Project:                vals.Get(testResultsUploadProject),
+		Version:                vals.Get(testResultsUploadVersion),
+		Variant:                vals.Get(testResultsUploadVariant),
+		TaskName:               vals.Get(testResultsUploadTaskName),
+		DisplayTaskName:        vals.Get(testResultsUploadDisplayTaskName),
+		TaskID:                 gimlet.GetVars(r)["task_id"],
+		DisplayTaskID:          vals.Get(testResultsUploadDisplayTaskID),
+		RequestType:            vals.Get(testResultsUploadRequestType),
+		Mainline:               vals.Get(testResultsUploadMainline) == trueString,
+		HistoricalDataDisabled: vals.Get(testResultsUploadHistoricalDataDisabled) == trueString,
+	}
+	h.format = model.TestResultsFormat(vals.Get(testResultsUploadFormat))
+
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(h.format.Validate())
+	if len(vals[execution]) > 0 {
+		exec, err := strconv.Atoi(vals[execution][0])
+		catcher.Wrap(err, "invalid execution value")
+		h.info.Execution = exec
+	}
+	catcher.NewWhen(h.info.Project == "", "must specify a project")
+	catcher.NewWhen(h.info.Version == "", "must specify a version")
+	catcher.NewWhen(h.info.Variant == "", "must specify a variant")
+	catcher.NewWhen(h.info.TaskName == "", "must specify a task name")
+	catcher.NewWhen(h.info.RequestType == "", "must specify a request type")
+	if catcher.HasErrors() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    catcher.Resolve().Error(),
+		}
+	}
+
+	// Read one byte past the maximum size so that larger reports are
+	// rejected rather than silently truncated.
+	body := utility.NewRequestReaderWithSize(r, testResultsUploadMaxReportSize+1)
+	defer body.Close()
+
+	var err error
+	h.report, err = ioutil.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(err, "reading test results report")
+	}
+	if len(h.report) > testResultsUploadMaxReportSize {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    fmt.Sprintf("test results report must not exceed %d bytes", testResultsUploadMaxReportSize),
+		}
+	}
+
+	return nil
+}
+
+// Run parses the test results report and stores the test results.
+func (h *testResultsUploadHandler) Run(ctx context.Context) gimlet.Responder {
+	upload, err := h.sc.UploadTestResults(ctx, data.TestResultsUploadOptions{
+		Info:   h.info,
+		Format: h.format,
+		Report: bytes.NewReader(h.report),
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "uploading test results for task ID '%s'", h.info.TaskID)
+		logFindError(err, message.Fields{
+			"request":   gimlet.GetRequestID(ctx),
+			"method":    "POST",
+			"route":     "/test_results/task_id/{task_id}/upload",
+			"task_id":   h.info.TaskID,
+			"execution": h.info.Execution,
+			"format":    h.format,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(upload)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /test_results/search/{project_id}
+
+type testResultsSearchHandler struct {
+	sc   data.Connector
+	opts model.SearchTestResultsOptions
+}
+
+func makeSearchTestResults(sc data.Connector) gimlet.RouteHandler {
+	return &testResultsSearchHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testResultsSearchHandler.
+func (h *testResultsSearchHandler) Factory() gimlet.RouteHandler {
+	return &testResultsSearchHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the search options from the HTTP request.
+// Searches without a version must specify a variant and a task name and
+// default to the last week, including today.
+func (h *testResultsSearchHandler) Parse(_ context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.opts = model.SearchTestResultsOptions{
+		Project:  gimlet.GetVars(r)["project_id"],
+		Version:  vals.Get(testResultsSearchVersion),
+		Variant:  vals.Get(testResultsSearchVariant),
+		TaskName: vals.Get(testResultsSearchTaskName),
+		TestName: vals.Get(testResultsTestName),
+		Statuses: vals[testResultsStatus],
+	}
+
+	var (
+		htd htdFilterHandler
+		err error
+	)
+	catcher := grip.NewBasicCatcher()
+	if h.opts.TestName != "" {
+		_, err = regexp.Compile(h.opts.TestName)
+		catcher.Wrap(err, "invalid test name regex")
+	}
+	h.opts.Limit, err = htd.readInt(vals.Get(testResultsLimit), 1, testResultsSearchMaxLimit, testResultsSearchDefaultLimit)
+	catcher.Wrap(err, "invalid limit value")
+	if beforeDate := vals.Get(testResultsSearchBeforeDate); beforeDate != "" {
+		h.opts.BeforeDate, err = time.ParseInLocation(htdAPIDateFormat, beforeDate, time.UTC)
+		catcher.Wrap(err, "invalid before_date value")
+	}
+	if afterDate := vals.Get(testResultsSearchAfterDate); afterDate != "" {
+		h.opts.AfterDate, err = time.ParseInLocation(htdAPIDateFormat, afterDate, time.UTC)
+		catcher.Wrap(err, "invalid after_date value")
+	}
+	if h.opts.Version == "" {
+		catcher.NewWhen(h.opts.Variant == "" || h.opts.TaskName == "", "must specify a version, or a variant and a task name")
+		if h.opts.BeforeDate.IsZero() {
+			h.opts.BeforeDate = utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+		}
+		if h.opts.AfterDate.IsZero() {
+			h.opts.AfterDate = h.opts.BeforeDate.AddDate(0, 0, -testResultsSearchDefaultDays)
+		}
+	}
+	catcher.NewWhen(!h.opts.AfterDate.IsZero() && !h.opts.BeforeDate.IsZero() && !h.opts.BeforeDate.After(h.opts.AfterDate), "before_date must be after after_date")
+	if catcher.HasErrors() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    catcher.Resolve().Error(),
+		}
+	}
+
+	return nil
+}
+
+// Run returns the test results of the project matching the search.
+func (h *testResultsSearchHandler) Run(ctx context.Context) gimlet.Responder {
+	search, err := h.sc.SearchTestResults(ctx, h.opts)
+	if err != nil {
+		err = errors.Wrapf(err, "searching test results for project '%s'", h.opts.Project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/test_results/search/{project_id}",
+			"project": h.opts.Project,
+			"version": h.opts.Version,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(search)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /test_results/failures_by_team/{project_id}/{version}
+
+type testResultsFailuresByTeamHandler struct {
+	sc      data.Connector
+	project string
+	version string
+}
+
+func makeGetTestFailuresByTeam(sc data.Connector) gimlet.RouteHandler {
+	return &testResultsFailuresByTeamHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testResultsFailuresByTeamHandler.
+func (h *testResultsFailuresByTeamHandler) Factory() gimlet.RouteHandler {
+	return &testResultsFailuresByTeamHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the version from the HTTP request.
+func (h *testResultsFailuresByTeamHandler) Parse(_ context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.project = vars["project_id"]
+	h.version = vars["version"]
+
+	return nil
+}
+
+// Run returns the failed tests of the version aggregated by owning team.
+func (h *testResultsFailuresByTeamHandler) Run(ctx context.Context) gimlet.Responder {
+	byTeam, err := h.sc.GetTestFailuresByTeam(ctx, h.project, h.version)
+	if err != nil {
+		err = errors.Wrapf(err, "getting test failures by team for version '%s'", h.version)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/test_results/failures_by_team/{project_id}/{version}",
+			"project": h.project,
+			"version": h.version,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(byTeam)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 //
 // GET /test_results/display_task_id/{display_task_id}
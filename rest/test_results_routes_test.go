@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,6 +20,8 @@ import (
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/amboy/queue"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -148,6 +151,7 @@ func (s *TestResultsHandlerSuite) setup(tempDir string) {
 		"task_id":             makeGetTestResultsByTaskID(s.sc),
 		"failed_tests_sample": makeGetTestResultsFailedSample(s.sc),
 		"stats":               makeGetTestResultsStats(s.sc),
+		"diff":                makeGetTestResultsDiff(s.sc),
 		"display_task_id":     makeGetTestResultsByDisplayTaskID(s.sc),
 		"test_name":           makeGetTestResultByTestName(s.sc),
 	}
@@ -487,6 +491,61 @@ func (s *TestResultsHandlerSuite) TestTestResultsGetStats() {
 	}
 }
 
+func (s *TestResultsHandlerSuite) TestTestResultsGetDiff() {
+	rh := s.rh["diff"].(*testResultsGetDiffHandler)
+
+	for _, test := range []struct {
+		name         string
+		opts         data.TestResultsDiffOptions
+		stillFailing int
+		errorStatus  int
+	}{
+		{
+			name: "FailsWhenTaskIDDNE",
+			opts: data.TestResultsDiffOptions{
+				Base:    data.TestResultsOptions{TaskID: "task1"},
+				Current: data.TestResultsOptions{TaskID: "DNE"},
+			},
+			errorStatus: http.StatusNotFound,
+		},
+		{
+			name: "FailsWhenBaseTaskIDDNE",
+			opts: data.TestResultsDiffOptions{
+				Base:    data.TestResultsOptions{TaskID: "DNE"},
+				Current: data.TestResultsOptions{TaskID: "task1"},
+			},
+			errorStatus: http.StatusNotFound,
+		},
+		{
+			name: "SucceedsWithTaskIDs",
+			opts: data.TestResultsDiffOptions{
+				Base:    data.TestResultsOptions{TaskID: "task2"},
+				Current: data.TestResultsOptions{TaskID: "task1", Execution: utility.ToIntPtr(0)},
+			},
+			stillFailing: 3,
+		},
+	} {
+		s.T().Run(test.name, func(t *testing.T) {
+			rh.opts = test.opts
+			resp := rh.Run(context.TODO())
+
+			s.Require().NotNil(resp)
+			if test.errorStatus > 0 {
+				s.Equal(test.errorStatus, resp.Status())
+			} else {
+				s.Equal(http.StatusOK, resp.Status())
+				diff, ok := resp.Data().(*model.APITestResultsDiff)
+				s.Require().True(ok)
+				s.Len(diff.StillFailing, test.stillFailing)
+				s.Empty(diff.NewlyFailing)
+				s.Empty(diff.NewlyPassing)
+				s.Empty(diff.Added)
+				s.Empty(diff.Removed)
+			}
+		})
+	}
+}
+
 func (s *TestResultsHandlerSuite) TestTestResultsGetByDisplayTaskIDHandler() {
 	rh := s.rh["display_task_id"].(*testResultsGetByDisplayTaskIDHandler)
 	optsList := []data.TestResultsOptions{
@@ -688,12 +747,13 @@ func (s *TestResultsHandlerSuite) TestTaskIDParse() {
 
 	// Test valid query parameters.
 	rh = rh.Factory().(*testResultsGetByTaskIDHandler)
-	urlString += "?test_name=test&status=fail&status=silentfail&group_id=group&sort_by=sort&sort_order_dsc=true&limit=5&page=2"
+	urlString += "?test_name=test&status=fail&status=silentfail&group_id=group&attribute=seed:42&attribute=shard:a:b&sort_by=sort&sort_order_dsc=true&limit=5&page=2"
 	expected = data.TestResultsOptions{
 		FilterAndSort: &data.TestResultsFilterAndSortOptions{
 			TestName:     "test",
 			Statuses:     []string{"fail", "silentfail"},
 			GroupID:      "group",
+			Attributes:   map[string]string{"seed": "42", "shard": "a:b"},
 			SortBy:       "sort",
 			SortOrderDSC: true,
 			Limit:        5,
@@ -706,4 +766,275 @@ func (s *TestResultsHandlerSuite) TestTaskIDParse() {
 	err = rh.Parse(context.TODO(), req)
 	s.Require().NoError(err)
 	s.Equal(expected, rh.opts)
+
+	// Test invalid attribute filter.
+	for _, attribute := range []string{"seed", ":42"} {
+		rh = rh.Factory().(*testResultsGetByTaskIDHandler)
+		req = &http.Request{Method: http.MethodGet}
+		req.URL, _ = url.Parse("http://cedar.mongodb.com/rest/v1/test_results/task_id/task_id1?attribute=" + attribute)
+		s.Error(rh.Parse(context.TODO(), req), attribute)
+	}
+}
+
+func TestTestResultsGetDiffHandlerParse(t *testing.T) {
+	newRequest := func(query string) *http.Request {
+		req := &http.Request{Method: http.MethodGet}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/test_results/task_id/task/diff?" + query)
+		return gimlet.SetURLVars(req, map[string]string{"task_id": "task"})
+	}
+
+	t.Run("AllValues", func(t *testing.T) {
+		handler := makeGetTestResultsDiff(&data.MockConnector{}).(*testResultsGetDiffHandler)
+		query := "base_task_id=base&base_execution=1&execution=2&display_task=true&duration_change_threshold=0.25&min_duration_change=0.5"
+		require.NoError(t, handler.Parse(context.Background(), newRequest(query)))
+
+		assert.Equal(t, data.TestResultsDiffOptions{
+			Base:                    data.TestResultsOptions{TaskID: "base", Execution: utility.ToIntPtr(1), DisplayTask: true},
+			Current:                 data.TestResultsOptions{TaskID: "task", Execution: utility.ToIntPtr(2), DisplayTask: true},
+			DurationChangeThreshold: 0.25,
+			MinDurationChange:       500 * time.Millisecond,
+		}, handler.opts)
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		handler := makeGetTestResultsDiff(&data.MockConnector{}).(*testResultsGetDiffHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest("base_task_id=base")))
+
+		assert.Equal(t, data.TestResultsDiffOptions{
+			Base:    data.TestResultsOptions{TaskID: "base"},
+			Current: data.TestResultsOptions{TaskID: "task"},
+		}, handler.opts)
+	})
+	t.Run("InvalidValues", func(t *testing.T) {
+		for _, query := range []string{
+			"",
+			"base_task_id=base&execution=one",
+			"base_task_id=base&base_execution=one",
+			"base_task_id=base&duration_change_threshold=high",
+			"base_task_id=base&duration_change_threshold=-1",
+			"base_task_id=base&min_duration_change=long",
+			"base_task_id=base&min_duration_change=-1",
+		} {
+			handler := makeGetTestResultsDiff(&data.MockConnector{}).(*testResultsGetDiffHandler)
+			assert.Error(t, handler.Parse(context.Background(), newRequest(query)), query)
+		}
+	})
+}
+
+func TestTestResultsUploadHandlerParse(t *testing.T) {
+	newRequest := func(query, body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://cedar.mongodb.com/test_results/task_id/task/upload?"+query, strings.NewReader(body))
+		require.NoError(t, err)
+		return gimlet.SetURLVars(req, map[string]string{"task_id": "task"})
+	}
+	required := "project=project&version=version&variant=variant&task_name=task_name&request_type=patch_request"
+
+	t.Run("AllValues", func(t *testing.T) {
+		handler := makeUploadTestResults(&data.MockConnector{}).(*testResultsUploadHandler)
+		query := required + "&format=tap&execution=2&display_task_name=display&display_task_id=display_id&mainline=true&historical_data_disabled=true"
+		require.NoError(t, handler.Parse(context.Background(), newRequest(query, "ok 1")))
+
+		assert.Equal(t, dbModel.TestResultsInfo{
+			Project:                "project",
+			Version:                "version",
+			Variant:                "variant",
+			TaskName:               "task_name",
+			DisplayTaskName:        "display",
+			TaskID:                 "task",
+			DisplayTaskID:          "display_id",
+			Execution:              2,
+			RequestType:            "patch_request",
+			Mainline:               true,
+			HistoricalDataDisabled: true,
+		}, handler.info)
+		assert.Equal(t, dbModel.TestResultsFormatTAP, handler.format)
+		assert.Equal(t, "ok 1", string(handler.report))
+	})
+	t.Run("ReportTooLarge", func(t *testing.T) {
+		handler := makeUploadTestResults(&data.MockConnector{}).(*testResultsUploadHandler)
+		err := handler.Parse(context.Background(), newRequest(required+"&format=tap", strings.Repeat("a", testResultsUploadMaxReportSize+1)))
+		require.Error(t, err)
+		errResp, ok := err.(gimlet.ErrorResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, errResp.StatusCode)
+	})
+	t.Run("InvalidValues", func(t *testing.T) {
+		for _, query := range []string{
+			required,
+			required + "&format=DNE",
+			required + "&format=junit&execution=one",
+			"format=junit&version=version&variant=variant&task_name=task_name&request_type=patch_request",
+			"format=junit&project=project&variant=variant&task_name=task_name&request_type=patch_request",
+			"format=junit&project=project&version=version&task_name=task_name&request_type=patch_request",
+			"format=junit&project=project&version=version&variant=variant&request_type=patch_request",
+			"format=junit&project=project&version=version&variant=variant&task_name=task_name",
+		} {
+			handler := makeUploadTestResults(&data.MockConnector{}).(*testResultsUploadHandler)
+			err := handler.Parse(context.Background(), newRequest(query, ""))
+			require.Error(t, err, query)
+			errResp, ok := err.(gimlet.ErrorResponse)
+			require.True(t, ok, query)
+			assert.Equal(t, http.StatusBadRequest, errResp.StatusCode, query)
+		}
+	})
+}
+
+func TestTestResultsUploadHandlerRun(t *testing.T) {
+	sc := &data.MockConnector{}
+	newHandler := func(format dbModel.TestResultsFormat, report string) *testResultsUploadHandler {
+		handler := makeUploadTestResults(sc).(*testResultsUploadHandler)
+		handler.info = dbModel.TestResultsInfo{
+			Project:     "project",
+			Version:     "version",
+			Variant:     "variant",
+			TaskName:    "task_name",
+			TaskID:      "task",
+			Execution:   1,
+			RequestType: "patch_request",
+		}
+		handler.format = format
+		handler.report = []byte(report)
+		return handler
+	}
+	report := `<testsuite name="suite">
+	<testcase classname="pkg" name="testPass" time="1"/>
+	<testcase classname="pkg" name="testFail" time="2"><failure/></testcase>
+</testsuite>`
+
+	t.Run("InvalidReport", func(t *testing.T) {
+		resp := newHandler(dbModel.TestResultsFormatJUnit, "<html>").Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+	t.Run("Succeeds", func(t *testing.T) {
+		handler := newHandler(dbModel.TestResultsFormatJUnit, report)
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		upload, ok := resp.Data().(*model.APITestResultsUpload)
+		require.True(t, ok)
+		assert.Equal(t, handler.info.ID(), *upload.TestResultsRecordID)
+		assert.Equal(t, 2, upload.Stats.TotalCount)
+		assert.Equal(t, 1, upload.Stats.FailedCount)
+
+		results := sc.CachedTestResults["task"]
+		require.Len(t, results, 2)
+		for i, name := range []string{"pkg.testPass", "pkg.testFail"} {
+			assert.Equal(t, name, results[i].TestName)
+			assert.Equal(t, "task", results[i].TaskID)
+			assert.Equal(t, 1, results[i].Execution)
+		}
+	})
+	t.Run("AlreadyExists", func(t *testing.T) {
+		resp := newHandler(dbModel.TestResultsFormatJUnit, report).Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusConflict, resp.Status())
+	})
+}
+
+func TestTestResultsSearchHandlerParse(t *testing.T) {
+	newRequest := func(query string) *http.Request {
+		req := &http.Request{Method: http.MethodGet}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/test_results/search/project?" + query)
+		return gimlet.SetURLVars(req, map[string]string{"project_id": "project"})
+	}
+
+	t.Run("AllValues", func(t *testing.T) {
+		handler := makeSearchTestResults(&data.MockConnector{}).(*testResultsSearchHandler)
+		query := "version=version&variant=variant&task_name=task_name&test_name=^test&status=fail&status=silentfail&after_date=2021-06-01&before_date=2021-06-08&limit=20"
+		require.NoError(t, handler.Parse(context.Background(), newRequest(query)))
+
+		assert.Equal(t, dbModel.SearchTestResultsOptions{
+			Project:    "project",
+			Version:    "version",
+			Variant:    "variant",
+			TaskName:   "task_name",
+			TestName:   "^test",
+			Statuses:   []string{"fail", "silentfail"},
+			AfterDate:  time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+			BeforeDate: time.Date(2021, 6, 8, 0, 0, 0, 0, time.UTC),
+			Limit:      20,
+		}, handler.opts)
+	})
+	t.Run("VersionDefaults", func(t *testing.T) {
+		handler := makeSearchTestResults(&data.MockConnector{}).(*testResultsSearchHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest("version=version")))
+
+		assert.Equal(t, dbModel.SearchTestResultsOptions{
+			Project: "project",
+			Version: "version",
+			Limit:   testResultsSearchDefaultLimit,
+		}, handler.opts)
+	})
+	t.Run("VariantAndTaskDefaults", func(t *testing.T) {
+		handler := makeSearchTestResults(&data.MockConnector{}).(*testResultsSearchHandler)
+		require.NoError(t, handler.Parse(context.Background(), newRequest("variant=variant&task_name=task_name")))
+
+		tomorrow := utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+		assert.Equal(t, tomorrow, handler.opts.BeforeDate)
+		assert.Equal(t, tomorrow.AddDate(0, 0, -testResultsSearchDefaultDays), handler.opts.AfterDate)
+		assert.Equal(t, testResultsSearchDefaultLimit, handler.opts.Limit)
+	})
+	t.Run("InvalidValues", func(t *testing.T) {
+		for _, query := range []string{
+			"",
+			"variant=variant",
+			"task_name=task_name&after_date=2021-06-01&before_date=2021-06-08",
+			"version=version&test_name=(",
+			"version=version&limit=0",
+			"version=version&limit=1001",
+			"version=version&after_date=06-01-2021",
+			"version=version&before_date=yesterday",
+			"version=version&after_date=2021-06-08&before_date=2021-06-01",
+		} {
+			handler := makeSearchTestResults(&data.MockConnector{}).(*testResultsSearchHandler)
+			err := handler.Parse(context.Background(), newRequest(query))
+			require.Error(t, err, query)
+			errResp, ok := err.(gimlet.ErrorResponse)
+			require.True(t, ok, query)
+			assert.Equal(t, http.StatusBadRequest, errResp.StatusCode, query)
+		}
+	})
+}
+
+func TestTestResultsSearchHandlerRun(t *testing.T) {
+	sc := &data.MockConnector{
+		CachedTestResults: map[string][]dbModel.TestResult{
+			"task0": {
+				{TaskID: "task0", TestName: "test0", Status: "pass"},
+				{TaskID: "task0", TestName: "test1", Status: "fail"},
+			},
+			"task1": {
+				{TaskID: "task1", TestName: "test1", Status: "fail"},
+				{TaskID: "task1", TestName: "other", Status: "fail"},
+			},
+		},
+	}
+	handler := makeSearchTestResults(sc).(*testResultsSearchHandler)
+
+	t.Run("Matches", func(t *testing.T) {
+		handler.opts = dbModel.SearchTestResultsOptions{Project: "project", Version: "version", TestName: "^test", Statuses: []string{"fail"}, Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		search, ok := resp.Data().(*model.APITestResultsSearch)
+		require.True(t, ok)
+		assert.Equal(t, 2, search.TotalCount)
+		require.Len(t, search.Results, 2)
+		for i, taskID := range []string{"task0", "task1"} {
+			assert.Equal(t, "project", utility.FromStringPtr(search.Results[i].Project))
+			assert.Equal(t, taskID, utility.FromStringPtr(search.Results[i].Result.TaskID))
+			assert.Equal(t, "test1", utility.FromStringPtr(search.Results[i].Result.TestName))
+		}
+	})
+	t.Run("Limit", func(t *testing.T) {
+		handler.opts = dbModel.SearchTestResultsOptions{Project: "project", Version: "version", Limit: 1}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		require.Equal(t, http.StatusOK, resp.Status())
+		search, ok := resp.Data().(*model.APITestResultsSearch)
+		require.True(t, ok)
+		assert.Equal(t, 4, search.TotalCount)
+		assert.Len(t, search.Results, 1)
+	})
 }
@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	testResultsStatsAPIMaxLimit     = 10000
+	testResultsStatsAPIDefaultLimit = 1000
+	testResultsStatsAPIDefaultDays  = 7
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /test_results/stats/{project_id}
+
+type testResultsStatsHandler struct {
+	filter model.TestResultsStatsFilter
+	sc     data.Connector
+}
+
+func makeGetAggregatedTestResultsStats(sc data.Connector) gimlet.RouteHandler {
+	return &testResultsStatsHandler{sc: sc}
+}
+
+// Factory returns a pointer to a new testResultsStatsHandler.
+func (h *testResultsStatsHandler) Factory() gimlet.RouteHandler {
+	return &testResultsStatsHandler{sc: h.sc}
+}
+
+// Parse fetches the project ID and the filter options from the http request.
+func (h *testResultsStatsHandler) Parse(_ context.Context, r *http.Request) error {
+	h.filter = model.TestResultsStatsFilter{Project: gimlet.GetVars(r)["project_id"]}
+
+	if err := h.parse(r.URL.Query()); err != nil {
+		return errors.Wrap(err, "invalid query parameters")
+	}
+
+	if err := h.filter.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// parse parses the query parameter values and fills the filter. The group_by
+// values are parsed the same way as the historical test data's string lists.
+// Without a version, the date range defaults to the last week, including
+// today.
+func (h *testResultsStatsHandler) parse(vals url.Values) error {
+	var (
+		htd htdFilterHandler
+		err error
+	)
+
+	h.filter.Version = vals.Get("version")
+	h.filter.Variant = vals.Get("variant")
+	h.filter.TaskName = vals.Get("task_name")
+	h.filter.MainlineOnly = vals.Get("mainline") == trueString
+	for _, groupBy := range htd.readStringList(vals["group_by"]) {
+		h.filter.GroupBy = append(h.filter.GroupBy, model.TestResultsStatsGroupBy(groupBy))
+	}
+
+	h.filter.Limit, err = htd.readInt(vals.Get("limit"), 1, testResultsStatsAPIMaxLimit, testResultsStatsAPIDefaultLimit)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			Message:    "invalid limit value",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if h.filter.Version == "" {
+		h.filter.BeforeDate = utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+	}
+	if beforeDate := vals.Get("before_date"); beforeDate != "" {
+		h.filter.BeforeDate, err = time.ParseInLocation(htdAPIDateFormat, beforeDate, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    "invalid before_date value",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	if h.filter.Version == "" {
+		h.filter.AfterDate = h.filter.BeforeDate.AddDate(0, 0, -testResultsStatsAPIDefaultDays)
+	}
+	if afterDate := vals.Get("after_date"); afterDate != "" {
+		h.filter.AfterDate, err = time.ParseInLocation(htdAPIDateFormat, afterDate, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    "invalid after_date value",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run returns the test results stats of the project's tasks, summed by
+// group.
+func (h *testResultsStatsHandler) Run(ctx context.Context) gimlet.Responder {
+	stats, err := h.sc.GetAggregatedTestResultsStats(ctx, h.filter)
+	if err != nil {
+		err = errors.Wrapf(err, "getting aggregated test results stats for project '%s'", h.filter.Project)
+		logFindError(err, message.Fields{
+			"request": gimlet.GetRequestID(ctx),
+			"method":  "GET",
+			"route":   "/test_results/stats/{project_id}",
+			"project": h.filter.Project,
+			"version": h.filter.Version,
+		})
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(stats)
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	dbModel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestResultsStatsHandlerParse(t *testing.T) {
+	t.Run("AllValues", func(t *testing.T) {
+		values := url.Values{
+			"version":     []string{"version"},
+			"variant":     []string{"variant"},
+			"task_name":   []string{"task"},
+			"mainline":    []string{"true"},
+			"after_date":  []string{"2018-07-01"},
+			"before_date": []string{"2018-07-15"},
+			"group_by":    []string{"variant,task_name"},
+			"limit":       []string{"20"},
+		}
+		handler := testResultsStatsHandler{}
+		require.NoError(t, handler.parse(values))
+
+		assert.Equal(t, "version", handler.filter.Version)
+		assert.Equal(t, "variant", handler.filter.Variant)
+		assert.Equal(t, "task", handler.filter.TaskName)
+		assert.True(t, handler.filter.MainlineOnly)
+		assert.Equal(t, time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC), handler.filter.AfterDate)
+		assert.Equal(t, time.Date(2018, 7, 15, 0, 0, 0, 0, time.UTC), handler.filter.BeforeDate)
+		assert.Equal(t, []dbModel.TestResultsStatsGroupBy{dbModel.TestResultsStatsGroupByVariant, dbModel.TestResultsStatsGroupByTaskName}, handler.filter.GroupBy)
+		assert.Equal(t, 20, handler.filter.Limit)
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		handler := testResultsStatsHandler{}
+		require.NoError(t, handler.parse(url.Values{}))
+
+		tomorrow := utility.GetUTCDay(time.Now()).AddDate(0, 0, 1)
+		assert.False(t, handler.filter.MainlineOnly)
+		assert.Empty(t, handler.filter.GroupBy)
+		assert.Equal(t, tomorrow, handler.filter.BeforeDate)
+		assert.Equal(t, tomorrow.AddDate(0, 0, -testResultsStatsAPIDefaultDays), handler.filter.AfterDate)
+		assert.Equal(t, testResultsStatsAPIDefaultLimit, handler.filter.Limit)
+	})
+	t.Run("VersionWithoutDates", func(t *testing.T) {
+		handler := testResultsStatsHandler{}
+		require.NoError(t, handler.parse(url.Values{"version": []string{"version"}}))
+
+		assert.Zero(t, handler.filter.AfterDate)
+		assert.Zero(t, handler.filter.BeforeDate)
+	})
+	t.Run("InvalidValues", func(t *testing.T) {
+		for _, values := range []url.Values{
+			{"limit": []string{"0"}},
+			{"limit": []string{"10001"}},
+			{"after_date": []string{"07-01-2018"}},
+			{"before_date": []string{"yesterday"}},
+		} {
+			handler := testResultsStatsHandler{}
+			assert.Error(t, handler.parse(values))
+		}
+	})
+	t.Run("InvalidGroupBy", func(t *testing.T) {
+		req := &http.Request{Method: http.MethodGet}
+		req.URL, _ = url.Parse("https://cedar.mongodb.com/test_results/stats/project?group_by=test_name")
+		req = gimlet.SetURLVars(req, map[string]string{"project_id": "project"})
+
+		handler := makeGetAggregatedTestResultsStats(&data.MockConnector{}).(*testResultsStatsHandler)
+		assert.Error(t, handler.Parse(context.Background(), req))
+	})
+}
+
+func TestTestResultsStatsHandlerRun(t *testing.T) {
+	now := time.Now()
+	sc := &data.MockConnector{
+		CachedTestResultsStats: []dbModel.AggregatedTestResultsStats{
+			{Version: "v1", Variant: "variant0", TaskName: "task0", NumTasks: 1, TotalCount: 10, PassedCount: 8, FailedCount: 1, SkippedCount: 1, TotalDuration: time.Minute, LatestCreatedAt: now},
+			{Version: "v1", Variant: "variant1", TaskName: "task0", NumTasks: 1, TotalCount: 5, PassedCount: 5, LatestCreatedAt: now},
+			{Version: "v0", Variant: "variant0", TaskName: "task0", NumTasks: 1, TotalCount: 10, FailedCount: 10, LatestCreatedAt: now.Add(-time.Hour)},
+		},
+	}
+	handler := makeGetAggregatedTestResultsStats(sc).(*testResultsStatsHandler)
+
+	t.Run("All", func(t *testing.T) {
+		handler.filter = dbModel.TestResultsStatsFilter{Project: "project", Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		stats, ok := resp.Data().([]model.APIAggregatedTestResultsStats)
+		require.True(t, ok)
+		require.Len(t, stats, 3)
+		for i, s := range stats {
+			expected := model.APIAggregatedTestResultsStats{}
+			require.NoError(t, expected.Import(sc.CachedTestResultsStats[i]))
+			assert.Equal(t, expected, s)
+		}
+	})
+	t.Run("Filtered", func(t *testing.T) {
+		handler.filter = dbModel.TestResultsStatsFilter{Project: "project", Version: "v1", Variant: "variant0", Limit: 10}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		stats, ok := resp.Data().([]model.APIAggregatedTestResultsStats)
+		require.True(t, ok)
+		require.Len(t, stats, 1)
+		assert.Equal(t, 60.0, stats[0].TotalDuration)
+	})
+	t.Run("Limit", func(t *testing.T) {
+		handler.filter = dbModel.TestResultsStatsFilter{Project: "project", Limit: 2}
+		resp := handler.Run(context.Background())
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.Status())
+		stats, ok := resp.Data().([]model.APIAggregatedTestResultsStats)
+		require.True(t, ok)
+		assert.Len(t, stats, 2)
+	})
+}
@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// checkProjectLogsAccess ensures that the caller may read the logs of the
+// given Evergreen project, applying the same project-level check as the REST
+// read routes. The caller's Evergreen credentials are read from the
+// Evergreen-Api-User and Evergreen-Api-Key request metadata.
+func checkProjectLogsAccess(ctx context.Context, env cedar.Environment, projectID string) error {
+	conf := model.NewCedarConfig(env)
+	if err := conf.Find(); err != nil {
+		return newRPCError(codes.Internal, errors.Wrap(err, "fetching cedar config"))
+	}
+
+	req, err := createEvgAuthRequest(ctx, &conf.Evergreen, projectID)
+	if err != nil {
+		return err
+	}
+
+	return doEvgAuthRequest(req, projectID)
+}
+
+func createEvgAuthRequest(ctx context.Context, evgConf *model.EvergreenConfig, projectID string) (*http.Request, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	apiUser := md.Get(cedar.EvergreenAPIUserHeader)
+	apiKey := md.Get(cedar.EvergreenAPIKeyHeader)
+	if len(apiUser) == 0 || apiUser[0] == "" || len(apiKey) == 0 || apiKey[0] == "" {
+		return nil, newRPCError(codes.Unauthenticated, errors.New("unauthorized user"))
+	}
+
+	urlString := fmt.Sprintf("%s/rest/v2/auth?resource=%s&resource_type=project&permission=project_logs&required_level=10", evgConf.URL, url.QueryEscape(projectID))
+	req, err := http.NewRequest(http.MethodGet, urlString, nil)
+	if err != nil {
+		return nil, newRPCError(codes.Internal, errors.Wrap(err, "creating HTTP request"))
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(evgConf.HeaderKeyName, apiKey[0])
+	req.Header.Set(evgConf.HeaderUserName, apiUser[0])
+
+	return req, nil
+}
+
+func doEvgAuthRequest(req *http.Request, projectID string) error {
+	client := utility.GetDefaultHTTPRetryableClient()
+	defer utility.PutHTTPClient(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return newRPCError(codes.Internal, errors.Wrap(err, "authenticating user"))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return newRPCError(codes.Unauthenticated, errors.New("unauthorized user"))
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return newRPCError(codes.Internal, errors.Wrap(err, "reading response body"))
+	}
+	if string(body) != "true" {
+		return newRPCError(codes.PermissionDenied, errors.Errorf("unauthorized to read logs from project '%s'", projectID))
+	}
+
+	return nil
+}
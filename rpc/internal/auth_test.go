@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateEvgAuthRequest(t *testing.T) {
+	evgConf := &model.EvergreenConfig{
+		URL:            "https://evergreen.mongodb.com",
+		HeaderKeyName:  "Api-Key",
+		HeaderUserName: "Username",
+	}
+
+	t.Run("NoCredentials", func(t *testing.T) {
+		req, err := createEvgAuthRequest(context.Background(), evgConf, "project")
+		assert.Nil(t, req)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	t.Run("MissingKey", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(cedar.EvergreenAPIUserHeader, "user"))
+		req, err := createEvgAuthRequest(ctx, evgConf, "project")
+		assert.Nil(t, req)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	t.Run("Credentials", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+			cedar.EvergreenAPIUserHeader, "user",
+			cedar.EvergreenAPIKeyHeader, "key",
+		))
+		req, err := createEvgAuthRequest(ctx, evgConf, "my project")
+		require.NoError(t, err)
+		require.NotNil(t, req)
+		assert.Equal(t, "https://evergreen.mongodb.com/rest/v2/auth?resource=my+project&resource_type=project&permission=project_logs&required_level=10", req.URL.String())
+		assert.Equal(t, ctx, req.Context())
+		assert.Equal(t, "key", req.Header.Get(evgConf.HeaderKeyName))
+		assert.Equal(t, "user", req.Header.Get(evgConf.HeaderUserName))
+	})
+}
+
+func TestDoEvgAuthRequest(t *testing.T) {
+	var response string
+	var statusCode int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(statusCode)
+		_, _ = rw.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		name       string
+		statusCode int
+		response   string
+		code       codes.Code
+	}{
+		{name: "Unauthorized", statusCode: http.StatusUnauthorized, code: codes.Unauthenticated},
+		{name: "False", statusCode: http.StatusOK, response: "false", code: codes.PermissionDenied},
+		{name: "True", statusCode: http.StatusOK, response: "true", code: codes.OK},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			statusCode = test.statusCode
+			response = test.response
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.code, status.Code(doEvgAuthRequest(req, "project")))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"github.com/evergreen-ci/cedar/model"
 	"github.com/mongodb/grip/level"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Export exports LogFormat to the corresponding LogFormat type in the model
@@ -66,3 +67,78 @@ func (l *LogInfo) Export() model.LogInfo {
 		Mainline:    l.Mainline,
 	}
 }
+
+// importLogFormat imports the LogFormat type from the model package to the
+// corresponding LogFormat type.
+func importLogFormat(f model.LogFormat) LogFormat {
+	switch f {
+	case model.LogFormatText:
+		return LogFormat_LOG_FORMAT_TEXT
+	case model.LogFormatJSON:
+		return LogFormat_LOG_FORMAT_JSON
+	case model.LogFormatBSON:
+		return LogFormat_LOG_FORMAT_BSON
+	default:
+		return LogFormat_LOG_FORMAT_UNKNOWN
+	}
+}
+
+// importLogStorage imports the PailType from the model package to the
+// corresponding LogStorage type.
+func importLogStorage(t model.PailType) LogStorage {
+	switch t {
+	case model.PailS3:
+		return LogStorage_LOG_STORAGE_S3
+	case model.PailGridFS, model.PailLegacyGridFS:
+		return LogStorage_LOG_STORAGE_GRIDFS
+	default:
+		return LogStorage_LOG_STORAGE_LOCAL
+	}
+}
+
+// importLogLine imports the LogLine type from the model package to the
+// corresponding LogLine type.
+func importLogLine(l model.LogLine) *LogLine {
+	return &LogLine{
+		Priority:  int32(l.Priority),
+		Timestamp: timestamppb.New(l.Timestamp),
+		Data:      []byte(l.Data),
+	}
+}
+
+// importLogInfo imports the LogInfo type from the model package to the
+// corresponding LogInfo type.
+func importLogInfo(info model.LogInfo) *LogInfo {
+	return &LogInfo{
+		Project:   info.Project,
+		Version:   info.Version,
+		Variant:   info.Variant,
+		TaskName:  info.TaskName,
+		TaskId:    info.TaskID,
+		Execution: int32(info.Execution),
+		TestName:  info.TestName,
+		Trial:     int32(info.Trial),
+		ProcName:  info.ProcessName,
+		Format:    importLogFormat(info.Format),
+		Tags:      info.Tags,
+		Arguments: info.Arguments,
+		Mainline:  info.Mainline,
+	}
+}
+
+// importLogMetadata imports the metadata of a Log from the model package to
+// the corresponding LogMetadata type.
+func importLogMetadata(l model.Log) *LogMetadata {
+	metadata := &LogMetadata{
+		LogId:     l.ID,
+		Info:      importLogInfo(l.Info),
+		Storage:   importLogStorage(l.Artifact.Type),
+		CreatedAt: timestamppb.New(l.CreatedAt),
+		ExitCode:  int32(l.Info.ExitCode),
+	}
+	if !l.CompletedAt.IsZero() {
+		metadata.CompletedAt = timestamppb.New(l.CompletedAt)
+	}
+
+	return metadata
+}
@@ -124,3 +124,60 @@ func TestLogInfoExport(t *testing.T) {
 	assert.Equal(t, logInfo.Mainline, modelLogInfo.Mainline)
 
 }
+
+func TestImportLogLine(t *testing.T) {
+	modelLogLine := model.LogLine{
+		Priority:  level.Alert,
+		Timestamp: time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC),
+		Data:      "Goodbye year 9999\n",
+	}
+	logLine := importLogLine(modelLogLine)
+	assert.Equal(t, int32(90), logLine.Priority)
+	assert.Equal(t, int64(253402300799), logLine.Timestamp.Seconds)
+	assert.EqualValues(t, modelLogLine.Data, logLine.Data)
+	assert.Equal(t, modelLogLine, logLine.Export())
+}
+
+func TestImportLogMetadata(t *testing.T) {
+	modelLogInfo := model.LogInfo{
+		Project:     "project",
+		Version:     "version",
+		Variant:     "variant",
+		TaskName:    "task_name",
+		TaskID:      "task_id",
+		Execution:   3,
+		TestName:    "test_name",
+		Trial:       2,
+		ProcessName: "process_name",
+		Format:      model.LogFormatJSON,
+		Tags:        []string{"tag1", "tag2", "tag3"},
+		Arguments:   map[string]string{"hello": "world", "goodbye": "world"},
+		Mainline:    true,
+		ExitCode:    1,
+	}
+	t.Run("Completed", func(t *testing.T) {
+		log := model.CreateLog(modelLogInfo, model.PailS3)
+		log.CompletedAt = log.CreatedAt.Add(time.Minute)
+
+		metadata := importLogMetadata(*log)
+		assert.Equal(t, log.ID, metadata.LogId)
+		assert.Equal(t, LogStorage_LOG_STORAGE_S3, metadata.Storage)
+		assert.True(t, log.CreatedAt.Equal(metadata.CreatedAt.AsTime()))
+		assert.True(t, log.CompletedAt.Equal(metadata.CompletedAt.AsTime()))
+		assert.Equal(t, int32(modelLogInfo.ExitCode), metadata.ExitCode)
+
+		expectedInfo := modelLogInfo
+		expectedInfo.ExitCode = 0
+		info := metadata.Info.Export()
+		assert.Equal(t, expectedInfo, info)
+		assert.Equal(t, log.ID, info.ID())
+	})
+	t.Run("InProgress", func(t *testing.T) {
+		log := model.CreateLog(modelLogInfo, model.PailLocal)
+
+		metadata := importLogMetadata(*log)
+		assert.Equal(t, log.ID, metadata.LogId)
+		assert.Equal(t, LogStorage_LOG_STORAGE_LOCAL, metadata.Storage)
+		assert.Nil(t, metadata.CompletedAt)
+	})
+}
@@ -540,6 +540,353 @@ func (x *BuildloggerResponse) GetLogId() string {
 	return ""
 }
 
+type LogReadOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Tail    int32                  `protobuf:"varint,3,opt,name=tail,proto3" json:"tail,omitempty"`
+	Reverse bool                   `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+}
+
+func (x *LogReadOptions) Reset() {
+	*x = LogReadOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_buildlogger_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogReadOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogReadOptions) ProtoMessage() {}
+
+func (x *LogReadOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_buildlogger_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogReadOptions.ProtoReflect.Descriptor instead.
+func (*LogReadOptions) Descriptor() ([]byte, []int) {
+	return file_buildlogger_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LogReadOptions) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *LogReadOptions) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *LogReadOptions) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *LogReadOptions) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+type GetLogRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogId   string          `protobuf:"bytes,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	Options *LogReadOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *GetLogRequest) Reset() {
+	*x = GetLogRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_buildlogger_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogRequest) ProtoMessage() {}
+
+func (x *GetLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_buildlogger_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogRequest.ProtoReflect.Descriptor instead.
+func (*GetLogRequest) Descriptor() ([]byte, []int) {
+	return file_buildlogger_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLogRequest) GetLogId() string {
+	if x != nil {
+		return x.LogId
+	}
+	return ""
+}
+
+func (x *GetLogRequest) GetOptions() *LogReadOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GetLogsByTaskIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskId          string          `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Execution       int32           `protobuf:"varint,2,opt,name=execution,proto3" json:"execution,omitempty"`
+	LatestExecution bool            `protobuf:"varint,3,opt,name=latest_execution,json=latestExecution,proto3" json:"latest_execution,omitempty"`
+	ProcName        string          `protobuf:"bytes,4,opt,name=proc_name,json=procName,proto3" json:"proc_name,omitempty"`
+	Tags            []string        `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Options         *LogReadOptions `protobuf:"bytes,6,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *GetLogsByTaskIDRequest) Reset() {
+	*x = GetLogsByTaskIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_buildlogger_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLogsByTaskIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogsByTaskIDRequest) ProtoMessage() {}
+
+func (x *GetLogsByTaskIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_buildlogger_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogsByTaskIDRequest.ProtoReflect.Descriptor instead.
+func (*GetLogsByTaskIDRequest) Descriptor() ([]byte, []int) {
+	return file_buildlogger_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetLogsByTaskIDRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *GetLogsByTaskIDRequest) GetExecution() int32 {
+	if x != nil {
+		return x.Execution
+	}
+	return 0
+}
+
+func (x *GetLogsByTaskIDRequest) GetLatestExecution() bool {
+	if x != nil {
+		return x.LatestExecution
+	}
+	return false
+}
+
+func (x *GetLogsByTaskIDRequest) GetProcName() string {
+	if x != nil {
+		return x.ProcName
+	}
+	return ""
+}
+
+func (x *GetLogsByTaskIDRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetLogsByTaskIDRequest) GetOptions() *LogReadOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GetLogMetadataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogId string `protobuf:"bytes,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+}
+
+func (x *GetLogMetadataRequest) Reset() {
+	*x = GetLogMetadataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_buildlogger_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLogMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLogMetadataRequest) ProtoMessage() {}
+
+func (x *GetLogMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_buildlogger_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLogMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetLogMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_buildlogger_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetLogMetadataRequest) GetLogId() string {
+	if x != nil {
+		return x.LogId
+	}
+	return ""
+}
+
+type LogMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogId       string                 `protobuf:"bytes,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	Info        *LogInfo               `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	Storage     LogStorage             `protobuf:"varint,3,opt,name=storage,proto3,enum=cedar.LogStorage" json:"storage,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	ExitCode    int32                  `protobuf:"varint,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+}
+
+func (x *LogMetadata) Reset() {
+	*x = LogMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_buildlogger_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogMetadata) ProtoMessage() {}
+
+func (x *LogMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_buildlogger_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogMetadata.ProtoReflect.Descriptor instead.
+func (*LogMetadata) Descriptor() ([]byte, []int) {
+	return file_buildlogger_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LogMetadata) GetLogId() string {
+	if x != nil {
+		return x.LogId
+	}
+	return ""
+}
+
+func (x *LogMetadata) GetInfo() *LogInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *LogMetadata) GetStorage() LogStorage {
+	if x != nil {
+		return x.Storage
+	}
+	return LogStorage_LOG_STORAGE_S3
+}
+
+func (x *LogMetadata) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *LogMetadata) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *LogMetadata) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
 var File_buildlogger_proto protoreflect.FileDescriptor
 
 var file_buildlogger_proto_rawDesc = []byte{
@@ -600,36 +947,97 @@ var file_buildlogger_proto_rawDesc = []byte{
 	0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x2c, 0x0a, 0x13, 0x42, 0x75,
 	0x69, 0x6c, 0x64, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
 	0x65, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x49, 0x64, 0x2a, 0x4f, 0x0a, 0x0a, 0x4c, 0x6f, 0x67, 0x53,
-	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x4f, 0x47, 0x5f, 0x53, 0x54,
-	0x4f, 0x52, 0x41, 0x47, 0x45, 0x5f, 0x53, 0x33, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x4c, 0x4f,
-	0x47, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x41, 0x47, 0x45, 0x5f, 0x47, 0x52, 0x49, 0x44, 0x46, 0x53,
-	0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x4f, 0x47, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x41, 0x47,
-	0x45, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x10, 0x02, 0x2a, 0x62, 0x0a, 0x09, 0x4c, 0x6f, 0x67,
-	0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x16, 0x0a, 0x12, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f,
-	0x52, 0x4d, 0x41, 0x54, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x13,
-	0x0a, 0x0f, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x54, 0x45, 0x58,
-	0x54, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41,
-	0x54, 0x5f, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x4f, 0x47, 0x5f,
-	0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x42, 0x53, 0x4f, 0x4e, 0x10, 0x03, 0x32, 0x81, 0x02,
-	0x0a, 0x0b, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x12, 0x37, 0x0a,
-	0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x6f, 0x67, 0x12, 0x0e, 0x2e, 0x63, 0x65, 0x64,
-	0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x1a, 0x2e, 0x63, 0x65, 0x64,
-	0x61, 0x72, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64,
-	0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72,
-	0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x1a, 0x1a, 0x2e, 0x63, 0x65, 0x64, 0x61,
-	0x72, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4c,
-	0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e,
-	0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x1a, 0x1a, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72,
-	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x39, 0x0a, 0x08, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x4c,
-	0x6f, 0x67, 0x12, 0x11, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x45, 0x6e,
-	0x64, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x1a, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0x0e, 0x5a, 0x0c, 0x72, 0x70, 0x63, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x49, 0x64, 0x22, 0x9e, 0x01, 0x0a, 0x0e, 0x4c, 0x6f, 0x67,
+	0x52, 0x65, 0x61, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a,
+	0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x61, 0x69, 0x6c, 0x12,
+	0x18, 0x0a, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x22, 0x57, 0x0a, 0x0d, 0x47, 0x65, 0x74,
+	0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x6f,
+	0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x49,
+	0x64, 0x12, 0x2f, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x52, 0x65,
+	0x61, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0xdc, 0x01, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x73, 0x42, 0x79,
+	0x54, 0x61, 0x73, 0x6b, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x74, 0x61, 0x73, 0x6b, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f,
+	0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x12, 0x2f, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x61,
+	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x2e, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x6f,
+	0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x49,
+	0x64, 0x22, 0x8c, 0x02, 0x0a, 0x0b, 0x4c, 0x6f, 0x67, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c,
+	0x6f, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a, 0x07,
+	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x52, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65,
+	0x2a, 0x4f, 0x0a, 0x0a, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x12,
+	0x0a, 0x0e, 0x4c, 0x4f, 0x47, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x41, 0x47, 0x45, 0x5f, 0x53, 0x33,
+	0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x4c, 0x4f, 0x47, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x41, 0x47,
+	0x45, 0x5f, 0x47, 0x52, 0x49, 0x44, 0x46, 0x53, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x4f,
+	0x47, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x41, 0x47, 0x45, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x10,
+	0x02, 0x2a, 0x62, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x16,
+	0x0a, 0x12, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x55, 0x4e, 0x4b,
+	0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f,
+	0x52, 0x4d, 0x41, 0x54, 0x5f, 0x54, 0x45, 0x58, 0x54, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x4c,
+	0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x02,
+	0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x42,
+	0x53, 0x4f, 0x4e, 0x10, 0x03, 0x32, 0xbb, 0x03, 0x0a, 0x0b, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c,
+	0x6f, 0x67, 0x67, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c,
+	0x6f, 0x67, 0x12, 0x0e, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x44, 0x61,
+	0x74, 0x61, 0x1a, 0x1a, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x6c, 0x6f, 0x67, 0x67, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d,
+	0x0a, 0x0e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73,
+	0x12, 0x0f, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65,
+	0x73, 0x1a, 0x1a, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c,
+	0x6f, 0x67, 0x67, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a,
+	0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12,
+	0x0f, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73,
+	0x1a, 0x1a, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c, 0x6f,
+	0x67, 0x67, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x39,
+	0x0a, 0x08, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x4c, 0x6f, 0x67, 0x12, 0x11, 0x2e, 0x63, 0x65, 0x64,
+	0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x1a, 0x2e,
+	0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x6c, 0x6f, 0x67, 0x67, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x47, 0x65, 0x74,
+	0x4c, 0x6f, 0x67, 0x12, 0x14, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x4c,
+	0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x63, 0x65, 0x64, 0x61,
+	0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x4c, 0x6f, 0x67, 0x73, 0x42, 0x79, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x44, 0x12, 0x1d,
+	0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x73, 0x42, 0x79,
+	0x54, 0x61, 0x73, 0x6b, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x30, 0x01, 0x12,
+	0x42, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x1c, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x12, 0x2e, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x42, 0x0e, 0x5a, 0x0c, 0x72, 0x70, 0x63, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -645,39 +1053,58 @@ func file_buildlogger_proto_rawDescGZIP() []byte {
 }
 
 var file_buildlogger_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_buildlogger_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_buildlogger_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_buildlogger_proto_goTypes = []interface{}{
-	(LogStorage)(0),               // 0: cedar.LogStorage
-	(LogFormat)(0),                // 1: cedar.LogFormat
-	(*LogData)(nil),               // 2: cedar.LogData
-	(*LogInfo)(nil),               // 3: cedar.LogInfo
-	(*LogLines)(nil),              // 4: cedar.LogLines
-	(*LogLine)(nil),               // 5: cedar.LogLine
-	(*LogEndInfo)(nil),            // 6: cedar.LogEndInfo
-	(*BuildloggerResponse)(nil),   // 7: cedar.BuildloggerResponse
-	nil,                           // 8: cedar.LogInfo.ArgumentsEntry
-	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(LogStorage)(0),                // 0: cedar.LogStorage
+	(LogFormat)(0),                 // 1: cedar.LogFormat
+	(*LogData)(nil),                // 2: cedar.LogData
+	(*LogInfo)(nil),                // 3: cedar.LogInfo
+	(*LogLines)(nil),               // 4: cedar.LogLines
+	(*LogLine)(nil),                // 5: cedar.LogLine
+	(*LogEndInfo)(nil),             // 6: cedar.LogEndInfo
+	(*BuildloggerResponse)(nil),    // 7: cedar.BuildloggerResponse
+	(*LogReadOptions)(nil),         // 8: cedar.LogReadOptions
+	(*GetLogRequest)(nil),          // 9: cedar.GetLogRequest
+	(*GetLogsByTaskIDRequest)(nil), // 10: cedar.GetLogsByTaskIDRequest
+	(*GetLogMetadataRequest)(nil),  // 11: cedar.GetLogMetadataRequest
+	(*LogMetadata)(nil),            // 12: cedar.LogMetadata
+	nil,                            // 13: cedar.LogInfo.ArgumentsEntry
+	(*timestamppb.Timestamp)(nil),  // 14: google.protobuf.Timestamp
 }
 var file_buildlogger_proto_depIdxs = []int32{
 	3,  // 0: cedar.LogData.info:type_name -> cedar.LogInfo
 	0,  // 1: cedar.LogData.storage:type_name -> cedar.LogStorage
 	1,  // 2: cedar.LogInfo.format:type_name -> cedar.LogFormat
-	8,  // 3: cedar.LogInfo.arguments:type_name -> cedar.LogInfo.ArgumentsEntry
+	13, // 3: cedar.LogInfo.arguments:type_name -> cedar.LogInfo.ArgumentsEntry
 	5,  // 4: cedar.LogLines.lines:type_name -> cedar.LogLine
-	9,  // 5: cedar.LogLine.timestamp:type_name -> google.protobuf.Timestamp
-	2,  // 6: cedar.Buildlogger.CreateLog:input_type -> cedar.LogData
-	4,  // 7: cedar.Buildlogger.AppendLogLines:input_type -> cedar.LogLines
-	4,  // 8: cedar.Buildlogger.StreamLogLines:input_type -> cedar.LogLines
-	6,  // 9: cedar.Buildlogger.CloseLog:input_type -> cedar.LogEndInfo
-	7,  // 10: cedar.Buildlogger.CreateLog:output_type -> cedar.BuildloggerResponse
-	7,  // 11: cedar.Buildlogger.AppendLogLines:output_type -> cedar.BuildloggerResponse
-	7,  // 12: cedar.Buildlogger.StreamLogLines:output_type -> cedar.BuildloggerResponse
-	7,  // 13: cedar.Buildlogger.CloseLog:output_type -> cedar.BuildloggerResponse
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	14, // 5: cedar.LogLine.timestamp:type_name -> google.protobuf.Timestamp
+	14, // 6: cedar.LogReadOptions.start:type_name -> google.protobuf.Timestamp
+	14, // 7: cedar.LogReadOptions.end:type_name -> google.protobuf.Timestamp
+	8,  // 8: cedar.GetLogRequest.options:type_name -> cedar.LogReadOptions
+	8,  // 9: cedar.GetLogsByTaskIDRequest.options:type_name -> cedar.LogReadOptions
+	3,  // 10: cedar.LogMetadata.info:type_name -> cedar.LogInfo
+	0,  // 11: cedar.LogMetadata.storage:type_name -> cedar.LogStorage
+	14, // 12: cedar.LogMetadata.created_at:type_name -> google.protobuf.Timestamp
+	14, // 13: cedar.LogMetadata.completed_at:type_name -> google.protobuf.Timestamp
+	2,  // 14: cedar.Buildlogger.CreateLog:input_type -> cedar.LogData
+	4,  // 15: cedar.Buildlogger.AppendLogLines:input_type -> cedar.LogLines
+	4,  // 16: cedar.Buildlogger.StreamLogLines:input_type -> cedar.LogLines
+	6,  // 17: cedar.Buildlogger.CloseLog:input_type -> cedar.LogEndInfo
+	9,  // 18: cedar.Buildlogger.GetLog:input_type -> cedar.GetLogRequest
+	10, // 19: cedar.Buildlogger.GetLogsByTaskID:input_type -> cedar.GetLogsByTaskIDRequest
+	11, // 20: cedar.Buildlogger.GetLogMetadata:input_type -> cedar.GetLogMetadataRequest
+	7,  // 21: cedar.Buildlogger.CreateLog:output_type -> cedar.BuildloggerResponse
+	7,  // 22: cedar.Buildlogger.AppendLogLines:output_type -> cedar.BuildloggerResponse
+	7,  // 23: cedar.Buildlogger.StreamLogLines:output_type -> cedar.BuildloggerResponse
+	7,  // 24: cedar.Buildlogger.CloseLog:output_type -> cedar.BuildloggerResponse
+	5,  // 25: cedar.Buildlogger.GetLog:output_type -> cedar.LogLine
+	5,  // 26: cedar.Buildlogger.GetLogsByTaskID:output_type -> cedar.LogLine
+	12, // 27: cedar.Buildlogger.GetLogMetadata:output_type -> cedar.LogMetadata
+	21, // [21:28] is the sub-list for method output_type
+	14, // [14:21] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_buildlogger_proto_init() }
@@ -758,6 +1185,66 @@ func file_buildlogger_proto_init() {
 				return nil
 			}
 		}
+		file_buildlogger_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogReadOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_buildlogger_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_buildlogger_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLogsByTaskIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_buildlogger_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLogMetadataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_buildlogger_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -765,7 +1252,7 @@ func file_buildlogger_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_buildlogger_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   7,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
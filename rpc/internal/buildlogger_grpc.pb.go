@@ -26,6 +26,9 @@ type BuildloggerClient interface {
 	AppendLogLines(ctx context.Context, in *LogLines, opts ...grpc.CallOption) (*BuildloggerResponse, error)
 	StreamLogLines(ctx context.Context, opts ...grpc.CallOption) (Buildlogger_StreamLogLinesClient, error)
 	CloseLog(ctx context.Context, in *LogEndInfo, opts ...grpc.CallOption) (*BuildloggerResponse, error)
+	GetLog(ctx context.Context, in *GetLogRequest, opts ...grpc.CallOption) (Buildlogger_GetLogClient, error)
+	GetLogsByTaskID(ctx context.Context, in *GetLogsByTaskIDRequest, opts ...grpc.CallOption) (Buildlogger_GetLogsByTaskIDClient, error)
+	GetLogMetadata(ctx context.Context, in *GetLogMetadataRequest, opts ...grpc.CallOption) (*LogMetadata, error)
 }
 
 type buildloggerClient struct {
@@ -97,6 +100,79 @@ func (c *buildloggerClient) CloseLog(ctx context.Context, in *LogEndInfo, opts .
 	return out, nil
 }
 
+func (c *buildloggerClient) GetLog(ctx context.Context, in *GetLogRequest, opts ...grpc.CallOption) (Buildlogger_GetLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Buildlogger_ServiceDesc.Streams[1], "/cedar.Buildlogger/GetLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &buildloggerGetLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Buildlogger_GetLogClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type buildloggerGetLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *buildloggerGetLogClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *buildloggerClient) GetLogsByTaskID(ctx context.Context, in *GetLogsByTaskIDRequest, opts ...grpc.CallOption) (Buildlogger_GetLogsByTaskIDClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Buildlogger_ServiceDesc.Streams[2], "/cedar.Buildlogger/GetLogsByTaskID", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &buildloggerGetLogsByTaskIDClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Buildlogger_GetLogsByTaskIDClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type buildloggerGetLogsByTaskIDClient struct {
+	grpc.ClientStream
+}
+
+func (x *buildloggerGetLogsByTaskIDClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *buildloggerClient) GetLogMetadata(ctx context.Context, in *GetLogMetadataRequest, opts ...grpc.CallOption) (*LogMetadata, error) {
+	out := new(LogMetadata)
+	err := c.cc.Invoke(ctx, "/cedar.Buildlogger/GetLogMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BuildloggerServer is the server API for Buildlogger service.
 // All implementations must embed UnimplementedBuildloggerServer
 // for forward compatibility
@@ -105,6 +181,9 @@ type BuildloggerServer interface {
 	AppendLogLines(context.Context, *LogLines) (*BuildloggerResponse, error)
 	StreamLogLines(Buildlogger_StreamLogLinesServer) error
 	CloseLog(context.Context, *LogEndInfo) (*BuildloggerResponse, error)
+	GetLog(*GetLogRequest, Buildlogger_GetLogServer) error
+	GetLogsByTaskID(*GetLogsByTaskIDRequest, Buildlogger_GetLogsByTaskIDServer) error
+	GetLogMetadata(context.Context, *GetLogMetadataRequest) (*LogMetadata, error)
 	mustEmbedUnimplementedBuildloggerServer()
 }
 
@@ -124,6 +203,15 @@ func (UnimplementedBuildloggerServer) StreamLogLines(Buildlogger_StreamLogLinesS
 func (UnimplementedBuildloggerServer) CloseLog(context.Context, *LogEndInfo) (*BuildloggerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CloseLog not implemented")
 }
+func (UnimplementedBuildloggerServer) GetLog(*GetLogRequest, Buildlogger_GetLogServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetLog not implemented")
+}
+func (UnimplementedBuildloggerServer) GetLogsByTaskID(*GetLogsByTaskIDRequest, Buildlogger_GetLogsByTaskIDServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetLogsByTaskID not implemented")
+}
+func (UnimplementedBuildloggerServer) GetLogMetadata(context.Context, *GetLogMetadataRequest) (*LogMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogMetadata not implemented")
+}
 func (UnimplementedBuildloggerServer) mustEmbedUnimplementedBuildloggerServer() {}
 
 // UnsafeBuildloggerServer may be embedded to opt out of forward compatibility for this service.
@@ -217,6 +305,66 @@ func _Buildlogger_CloseLog_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Buildlogger_GetLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildloggerServer).GetLog(m, &buildloggerGetLogServer{stream})
+}
+
+type Buildlogger_GetLogServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
+}
+
+type buildloggerGetLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *buildloggerGetLogServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Buildlogger_GetLogsByTaskID_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLogsByTaskIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildloggerServer).GetLogsByTaskID(m, &buildloggerGetLogsByTaskIDServer{stream})
+}
+
+type Buildlogger_GetLogsByTaskIDServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
+}
+
+type buildloggerGetLogsByTaskIDServer struct {
+	grpc.ServerStream
+}
+
+func (x *buildloggerGetLogsByTaskIDServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Buildlogger_GetLogMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildloggerServer).GetLogMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cedar.Buildlogger/GetLogMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildloggerServer).GetLogMetadata(ctx, req.(*GetLogMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Buildlogger_ServiceDesc is the grpc.ServiceDesc for Buildlogger service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -236,6 +384,10 @@ var Buildlogger_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CloseLog",
 			Handler:    _Buildlogger_CloseLog_Handler,
 		},
+		{
+			MethodName: "GetLogMetadata",
+			Handler:    _Buildlogger_GetLogMetadata_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -243,6 +395,16 @@ var Buildlogger_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Buildlogger_StreamLogLines_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "GetLog",
+			Handler:       _Buildlogger_GetLog_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetLogsByTaskID",
+			Handler:       _Buildlogger_GetLogsByTaskID_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "buildlogger.proto",
 }
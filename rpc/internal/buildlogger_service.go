@@ -3,10 +3,15 @@ package internal
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/units"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -36,7 +41,16 @@ func BuildloggerServiceName() string {
 
 // CreateLog creates a new buildlogger log record.
 func (s *buildloggerService) CreateLog(ctx context.Context, data *LogData) (*BuildloggerResponse, error) {
+	conf := model.NewCedarConfig(s.env)
+	if err := conf.Find(); err != nil && !db.ResultsNotFound(err) {
+		return nil, newRPCError(codes.Internal, errors.Wrap(err, "fetching Cedar config"))
+	}
+
 	log := model.CreateLog(data.Info.Export(), data.Storage.Export())
+	if conf.Bucket.BuildLogsCompression != model.LogCompressionNone {
+		log.Artifact.Version = 2
+		log.Artifact.Compression = conf.Bucket.BuildLogsCompression
+	}
 	log.Setup(s.env)
 	return &BuildloggerResponse{LogId: log.ID}, newRPCError(codes.Internal, errors.Wrap(log.SaveNew(ctx), "saving log record"))
 }
@@ -108,6 +122,182 @@ func (s *buildloggerService) CloseLog(ctx context.Context, info *LogEndInfo) (*B
 		return nil, newRPCError(codes.Internal, errors.Wrapf(err, "finding log record '%s'", info.LogId))
 	}
 
-	return &BuildloggerResponse{LogId: log.ID},
-		newRPCError(codes.Internal, errors.Wrapf(log.Close(ctx, int(info.ExitCode)), "closing log '%s'", log.ID))
+	if err := log.Close(ctx, int(info.ExitCode)); err != nil {
+		return nil, newRPCError(codes.Internal, errors.Wrapf(err, "closing log '%s'", log.ID))
+	}
+
+	if info.ExitCode != 0 {
+		// The log is already closed, so failing to enqueue the job should
+		// not fail the close.
+		grip.Warning(message.WrapError(amboy.EnqueueUniqueJob(ctx, s.env.GetRemoteQueue(), units.NewBuildloggerFailureSignaturesJob(log.ID)), message.Fields{
+			"message":  "failed to enqueue failure signatures job",
+			"log_id":   log.ID,
+			"log_info": log.Info,
+		}))
+	}
+
+	return &BuildloggerResponse{LogId: log.ID}, nil
+}
+
+// GetLog streams the lines of an existing buildlogger log via server-side
+// streaming. The caller must be allowed to read the logs of the log's
+// project.
+func (s *buildloggerService) GetLog(req *GetLogRequest, stream Buildlogger_GetLogServer) error {
+	ctx := stream.Context()
+	opts, err := req.Options.export()
+	if err != nil {
+		return newRPCError(codes.InvalidArgument, err)
+	}
+
+	log := &model.Log{ID: req.LogId}
+	log.Setup(s.env)
+	if err := log.Find(ctx); err != nil {
+		if db.ResultsNotFound(err) {
+			return newRPCError(codes.NotFound, err)
+		}
+		return newRPCError(codes.Internal, errors.Wrapf(err, "finding log record '%s'", req.LogId))
+	}
+	if err := checkProjectLogsAccess(ctx, s.env, log.Info.Project); err != nil {
+		return err
+	}
+
+	log.Setup(s.env)
+	it, err := log.Download(ctx, opts.timeRange)
+	if err != nil {
+		return newRPCError(codes.Internal, errors.Wrapf(err, "downloading log '%s'", req.LogId))
+	}
+
+	return sendLogLines(ctx, it, opts, stream.Send)
+}
+
+// GetLogsByTaskID streams the merged lines of the buildlogger logs with the
+// given task ID via server-side streaming. The caller must be allowed to read
+// the logs of the task's project.
+func (s *buildloggerService) GetLogsByTaskID(req *GetLogsByTaskIDRequest, stream Buildlogger_GetLogsByTaskIDServer) error {
+	ctx := stream.Context()
+	if req.TaskId == "" {
+		return newRPCError(codes.InvalidArgument, errors.New("must specify a task ID"))
+	}
+	opts, err := req.Options.export()
+	if err != nil {
+		return newRPCError(codes.InvalidArgument, err)
+	}
+
+	logs := &model.Logs{}
+	logs.Setup(s.env)
+	err = logs.Find(ctx, model.LogFindOptions{
+		TimeRange: opts.timeRange,
+		Info: model.LogInfo{
+			TaskID:      req.TaskId,
+			Execution:   int(req.Execution),
+			ProcessName: req.ProcName,
+			Tags:        req.Tags,
+		},
+		LatestExecution: req.LatestExecution,
+	})
+	if err != nil {
+		if db.ResultsNotFound(err) {
+			return newRPCError(codes.NotFound, err)
+		}
+		return newRPCError(codes.Internal, errors.Wrapf(err, "finding logs with task ID '%s'", req.TaskId))
+	}
+	if err = checkProjectLogsAccess(ctx, s.env, logs.Logs[0].Info.Project); err != nil {
+		return err
+	}
+
+	logs.Setup(s.env)
+	it, err := logs.Merge(ctx)
+	if err != nil {
+		return newRPCError(codes.Internal, errors.Wrapf(err, "downloading logs with task ID '%s'", req.TaskId))
+	}
+
+	return sendLogLines(ctx, it, opts, stream.Send)
+}
+
+// GetLogMetadata returns the metadata of an existing buildlogger log. The
+// caller must be allowed to read the logs of the log's project.
+func (s *buildloggerService) GetLogMetadata(ctx context.Context, req *GetLogMetadataRequest) (*LogMetadata, error) {
+	log := &model.Log{ID: req.LogId}
+	log.Setup(s.env)
+	if err := log.Find(ctx); err != nil {
+		if db.ResultsNotFound(err) {
+			return nil, newRPCError(codes.NotFound, err)
+		}
+		return nil, newRPCError(codes.Internal, errors.Wrapf(err, "finding log record '%s'", req.LogId))
+	}
+	if err := checkProjectLogsAccess(ctx, s.env, log.Info.Project); err != nil {
+		return nil, err
+	}
+
+	return importLogMetadata(*log), nil
+}
+
+type logReadOptions struct {
+	timeRange model.TimeRange
+	tail      int
+	reverse   bool
+}
+
+// export converts the read options of a log request into the options used
+// for iterating over the log lines. The end of the time range defaults to the
+// current time.
+func (o *LogReadOptions) export() (logReadOptions, error) {
+	opts := logReadOptions{timeRange: model.TimeRange{EndAt: time.Now()}}
+	if o == nil {
+		return opts, nil
+	}
+
+	if o.Start != nil {
+		opts.timeRange.StartAt = o.Start.AsTime()
+	}
+	if o.End != nil {
+		opts.timeRange.EndAt = o.End.AsTime()
+	}
+	if !opts.timeRange.IsValid() {
+		return opts, errors.New("start time cannot be after end time")
+	}
+	if o.Tail < 0 {
+		return opts, errors.New("tail cannot be negative")
+	}
+	opts.tail = int(o.Tail)
+	opts.reverse = o.Reverse
+
+	return opts, nil
+}
+
+// sendLogLines sends each line from the log iterator, respecting the given
+// read options, and closes the iterator. Lines are sent as they are read from
+// the iterator, except when tailing a log in chronological order, in which
+// case at most the requested number of lines are buffered.
+func sendLogLines(ctx context.Context, it model.LogIterator, opts logReadOptions, send func(*LogLine) error) error {
+	if opts.reverse || opts.tail > 0 {
+		it = it.Reverse()
+	}
+
+	catcher := grip.NewBasicCatcher()
+	if opts.tail > 0 && !opts.reverse {
+		var lines []*LogLine
+		for len(lines) < opts.tail && it.Next(ctx) {
+			lines = append(lines, importLogLine(it.Item()))
+		}
+		catcher.Add(it.Err())
+
+		for i := len(lines) - 1; i >= 0 && !catcher.HasErrors(); i-- {
+			catcher.Add(send(lines[i]))
+		}
+	} else {
+		for count := 0; (opts.tail <= 0 || count < opts.tail) && it.Next(ctx); count++ {
+			if err := send(importLogLine(it.Item())); err != nil {
+				catcher.Add(err)
+				break
+			}
+		}
+		catcher.Add(it.Err())
+	}
+	catcher.Add(it.Close())
+
+	if err := ctx.Err(); err != nil {
+		return newRPCError(codes.Aborted, err)
+	}
+	return newRPCError(codes.Internal, errors.Wrap(catcher.Resolve(), "streaming log lines"))
 }
@@ -3,10 +3,15 @@ package internal
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,10 +19,12 @@ import (
 	"github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/pail"
 	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -556,6 +563,520 @@ func TestCloseLog(t *testing.T) {
 	}
 }
 
+func TestGetLog(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env, err := createBuildloggerEnv()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, teardownBuildloggerEnv(ctx, env))
+	}()
+	tempDir, err := ioutil.TempDir(".", "buildlogger-test")
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tempDir))
+	}()
+	require.NoError(t, err)
+
+	conf, err := model.LoadCedarConfig(filepath.Join("testdata", "cedarconf.yaml"))
+	require.NoError(t, err)
+	conf.Bucket.BuildLogsBucket = tempDir
+	evgServer := startEvergreenAuthServer()
+	defer evgServer.Close()
+	conf.Evergreen = newTestEvergreenAuthConfig(evgServer.URL)
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	log := model.CreateLog(model.LogInfo{Project: "test"}, model.PailLocal)
+	log.Setup(env)
+	require.NoError(t, log.SaveNew(ctx))
+	deniedLog := model.CreateLog(model.LogInfo{Project: "denied"}, model.PailLocal)
+	deniedLog.Setup(env)
+	require.NoError(t, deniedLog.SaveNew(ctx))
+	lines := createTestLogLines(time.Now().Add(-time.Hour), 10)
+	log.Setup(env)
+	require.NoError(t, appendTestLogLines(ctx, log, lines))
+
+	for _, test := range []struct {
+		name            string
+		req             *GetLogRequest
+		env             cedar.Environment
+		unauthenticated bool
+		expectedLines   []model.LogLine
+		hasErr          bool
+	}{
+		{
+			name:          "DefaultOptions",
+			req:           &GetLogRequest{LogId: log.ID},
+			env:           env,
+			expectedLines: lines,
+		},
+		{
+			name: "TimeRange",
+			req: &GetLogRequest{
+				LogId: log.ID,
+				Options: &LogReadOptions{
+					Start: timestamppb.New(lines[2].Timestamp),
+					End:   timestamppb.New(lines[5].Timestamp),
+				},
+			},
+			env:           env,
+			expectedLines: lines[2:6],
+		},
+		{
+			name: "Tail",
+			req: &GetLogRequest{
+				LogId:   log.ID,
+				Options: &LogReadOptions{Tail: 3},
+			},
+			env:           env,
+			expectedLines: lines[7:],
+		},
+		{
+			name: "Reverse",
+			req: &GetLogRequest{
+				LogId:   log.ID,
+				Options: &LogReadOptions{Reverse: true},
+			},
+			env:           env,
+			expectedLines: reverseTestLogLines(lines),
+		},
+		{
+			name: "ReverseTail",
+			req: &GetLogRequest{
+				LogId:   log.ID,
+				Options: &LogReadOptions{Tail: 3, Reverse: true},
+			},
+			env:           env,
+			expectedLines: reverseTestLogLines(lines[7:]),
+		},
+		{
+			name: "InvalidTimeRange",
+			req: &GetLogRequest{
+				LogId: log.ID,
+				Options: &LogReadOptions{
+					Start: timestamppb.New(lines[5].Timestamp),
+					End:   timestamppb.New(lines[2].Timestamp),
+				},
+			},
+			env:    env,
+			hasErr: true,
+		},
+		{
+			name: "NegativeTail",
+			req: &GetLogRequest{
+				LogId:   log.ID,
+				Options: &LogReadOptions{Tail: -1},
+			},
+			env:    env,
+			hasErr: true,
+		},
+		{
+			name:   "LogDNE",
+			req:    &GetLogRequest{LogId: "DNE"},
+			env:    env,
+			hasErr: true,
+		},
+		{
+			name:   "InvalidEnv",
+			req:    &GetLogRequest{LogId: log.ID},
+			env:    nil,
+			hasErr: true,
+		},
+		{
+			name:            "Unauthenticated",
+			req:             &GetLogRequest{LogId: log.ID},
+			env:             env,
+			unauthenticated: true,
+			hasErr:          true,
+		},
+		{
+			name:   "PermissionDenied",
+			req:    &GetLogRequest{LogId: deniedLog.ID},
+			env:    env,
+			hasErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			port := getPort()
+			require.NoError(t, startBuildloggerService(ctx, test.env, port))
+			client, err := getBuildloggerGRPCClient(ctx, fmt.Sprintf("localhost:%d", port), []grpc.DialOption{grpc.WithInsecure()})
+			require.NoError(t, err)
+
+			stream, err := client.GetLog(testEvergreenAuthContext(ctx, test.unauthenticated), test.req)
+			require.NoError(t, err)
+			received, err := receiveLogLines(stream)
+			if test.hasErr {
+				assert.Error(t, err)
+				assert.Empty(t, received)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedLines, received)
+			}
+		})
+	}
+}
+
+func TestGetLogsByTaskID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env, err := createBuildloggerEnv()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, teardownBuildloggerEnv(ctx, env))
+	}()
+	tempDir, err := ioutil.TempDir(".", "buildlogger-test")
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tempDir))
+	}()
+	require.NoError(t, err)
+
+	conf, err := model.LoadCedarConfig(filepath.Join("testdata", "cedarconf.yaml"))
+	require.NoError(t, err)
+	conf.Bucket.BuildLogsBucket = tempDir
+	evgServer := startEvergreenAuthServer()
+	defer evgServer.Close()
+	conf.Evergreen = newTestEvergreenAuthConfig(evgServer.URL)
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	start := time.Now().Add(-time.Hour)
+	log1 := model.CreateLog(model.LogInfo{TaskID: "task", ProcessName: "proc1", Tags: []string{"tag"}}, model.PailLocal)
+	log1.Setup(env)
+	require.NoError(t, log1.SaveNew(ctx))
+	lines1 := createTestLogLines(start, 5)
+	log1.Setup(env)
+	require.NoError(t, appendTestLogLines(ctx, log1, lines1))
+	log2 := model.CreateLog(model.LogInfo{TaskID: "task", ProcessName: "proc2"}, model.PailLocal)
+	log2.Setup(env)
+	require.NoError(t, log2.SaveNew(ctx))
+	lines2 := createTestLogLines(start.Add(500*time.Millisecond), 5)
+	log2.Setup(env)
+	require.NoError(t, appendTestLogLines(ctx, log2, lines2))
+	log3 := model.CreateLog(model.LogInfo{TaskID: "task", Execution: 1}, model.PailLocal)
+	log3.Setup(env)
+	require.NoError(t, log3.SaveNew(ctx))
+	lines3 := createTestLogLines(start, 5)
+	log3.Setup(env)
+	require.NoError(t, appendTestLogLines(ctx, log3, lines3))
+
+	var merged []model.LogLine
+	for i := range lines1 {
+		merged = append(merged, lines1[i], lines2[i])
+	}
+
+	for _, test := range []struct {
+		name            string
+		req             *GetLogsByTaskIDRequest
+		env             cedar.Environment
+		unauthenticated bool
+		expectedLines   []model.LogLine
+		hasErr          bool
+	}{
+		{
+			name:          "Execution",
+			req:           &GetLogsByTaskIDRequest{TaskId: "task"},
+			env:           env,
+			expectedLines: merged,
+		},
+		{
+			name:          "LatestExecution",
+			req:           &GetLogsByTaskIDRequest{TaskId: "task", LatestExecution: true},
+			env:           env,
+			expectedLines: lines3,
+		},
+		{
+			name:          "ProcessName",
+			req:           &GetLogsByTaskIDRequest{TaskId: "task", ProcName: "proc2"},
+			env:           env,
+			expectedLines: lines2,
+		},
+		{
+			name:          "Tags",
+			req:           &GetLogsByTaskIDRequest{TaskId: "task", Tags: []string{"tag"}},
+			env:           env,
+			expectedLines: lines1,
+		},
+		{
+			name: "Tail",
+			req: &GetLogsByTaskIDRequest{
+				TaskId:  "task",
+				Options: &LogReadOptions{Tail: 4},
+			},
+			env:           env,
+			expectedLines: merged[6:],
+		},
+		{
+			name: "Reverse",
+			req: &GetLogsByTaskIDRequest{
+				TaskId:  "task",
+				Options: &LogReadOptions{Reverse: true},
+			},
+			env:           env,
+			expectedLines: reverseTestLogLines(merged),
+		},
+		{
+			name:   "NoTaskID",
+			req:    &GetLogsByTaskIDRequest{},
+			env:    env,
+			hasErr: true,
+		},
+		{
+			name:   "LogsDNE",
+			req:    &GetLogsByTaskIDRequest{TaskId: "DNE"},
+			env:    env,
+			hasErr: true,
+		},
+		{
+			name:   "InvalidEnv",
+			req:    &GetLogsByTaskIDRequest{TaskId: "task"},
+			env:    nil,
+			hasErr: true,
+		},
+		{
+			name:            "Unauthenticated",
+			req:             &GetLogsByTaskIDRequest{TaskId: "task"},
+			env:             env,
+			unauthenticated: true,
+			hasErr:          true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			port := getPort()
+			require.NoError(t, startBuildloggerService(ctx, test.env, port))
+			client, err := getBuildloggerGRPCClient(ctx, fmt.Sprintf("localhost:%d", port), []grpc.DialOption{grpc.WithInsecure()})
+			require.NoError(t, err)
+
+			stream, err := client.GetLogsByTaskID(testEvergreenAuthContext(ctx, test.unauthenticated), test.req)
+			require.NoError(t, err)
+			received, err := receiveLogLines(stream)
+			if test.hasErr {
+				assert.Error(t, err)
+				assert.Empty(t, received)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedLines, received)
+			}
+		})
+	}
+}
+
+func TestGetLogMetadata(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env, err := createBuildloggerEnv()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, teardownBuildloggerEnv(ctx, env))
+	}()
+
+	conf, err := model.LoadCedarConfig(filepath.Join("testdata", "cedarconf.yaml"))
+	require.NoError(t, err)
+	evgServer := startEvergreenAuthServer()
+	defer evgServer.Close()
+	conf.Evergreen = newTestEvergreenAuthConfig(evgServer.URL)
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+
+	log := model.CreateLog(model.LogInfo{Project: "test", TaskID: "task"}, model.PailLocal)
+	log.Setup(env)
+	require.NoError(t, log.SaveNew(ctx))
+	deniedLog := model.CreateLog(model.LogInfo{Project: "denied", TaskID: "task"}, model.PailLocal)
+	deniedLog.Setup(env)
+	require.NoError(t, deniedLog.SaveNew(ctx))
+
+	for _, test := range []struct {
+		name            string
+		req             *GetLogMetadataRequest
+		env             cedar.Environment
+		unauthenticated bool
+		hasErr          bool
+	}{
+		{
+			name: "ValidData",
+			req:  &GetLogMetadataRequest{LogId: log.ID},
+			env:  env,
+		},
+		{
+			name:   "LogDNE",
+			req:    &GetLogMetadataRequest{LogId: "DNE"},
+			env:    env,
+			hasErr: true,
+		},
+		{
+			name:   "InvalidEnv",
+			req:    &GetLogMetadataRequest{LogId: log.ID},
+			env:    nil,
+			hasErr: true,
+		},
+		{
+			name:            "Unauthenticated",
+			req:             &GetLogMetadataRequest{LogId: log.ID},
+			env:             env,
+			unauthenticated: true,
+			hasErr:          true,
+		},
+		{
+			name:   "PermissionDenied",
+			req:    &GetLogMetadataRequest{LogId: deniedLog.ID},
+			env:    env,
+			hasErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			port := getPort()
+			require.NoError(t, startBuildloggerService(ctx, test.env, port))
+			client, err := getBuildloggerGRPCClient(ctx, fmt.Sprintf("localhost:%d", port), []grpc.DialOption{grpc.WithInsecure()})
+			require.NoError(t, err)
+
+			resp, err := client.GetLogMetadata(testEvergreenAuthContext(ctx, test.unauthenticated), test.req)
+			if test.hasErr {
+				assert.Error(t, err)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				assert.Equal(t, log.ID, resp.LogId)
+				assert.Equal(t, log.Info, resp.Info.Export())
+				assert.Equal(t, LogStorage_LOG_STORAGE_LOCAL, resp.Storage)
+				assert.WithinDuration(t, log.CreatedAt, resp.CreatedAt.AsTime(), time.Millisecond)
+				assert.Nil(t, resp.CompletedAt)
+			}
+		})
+	}
+}
+
+func TestSendLogLines(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tempDir, err := ioutil.TempDir(".", "buildlogger-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tempDir))
+	}()
+	bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: tempDir})
+	require.NoError(t, err)
+	chunks, lines, err := model.GenerateTestLog(ctx, bucket, 20, 5)
+	require.NoError(t, err)
+	timeRange := model.TimeRange{
+		StartAt: chunks[0].Start,
+		EndAt:   chunks[len(chunks)-1].End,
+	}
+
+	for _, test := range []struct {
+		name          string
+		opts          logReadOptions
+		expectedLines []model.LogLine
+	}{
+		{
+			name:          "All",
+			expectedLines: lines,
+		},
+		{
+			name:          "Tail",
+			opts:          logReadOptions{tail: 7},
+			expectedLines: lines[13:],
+		},
+		{
+			name:          "TailLongerThanLog",
+			opts:          logReadOptions{tail: 100},
+			expectedLines: lines,
+		},
+		{
+			name:          "Reverse",
+			opts:          logReadOptions{reverse: true},
+			expectedLines: reverseTestLogLines(lines),
+		},
+		{
+			name:          "ReverseTail",
+			opts:          logReadOptions{tail: 7, reverse: true},
+			expectedLines: reverseTestLogLines(lines[13:]),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			it := model.NewSerializedLogIterator(bucket, chunks, timeRange)
+
+			var received []model.LogLine
+			require.NoError(t, sendLogLines(ctx, it, test.opts, func(line *LogLine) error {
+				received = append(received, line.Export())
+				return nil
+			}))
+			assert.Equal(t, test.expectedLines, received)
+		})
+	}
+	t.Run("SendError", func(t *testing.T) {
+		it := model.NewSerializedLogIterator(bucket, chunks, timeRange)
+
+		var count int
+		assert.Error(t, sendLogLines(ctx, it, logReadOptions{}, func(_ *LogLine) error {
+			count++
+			return errors.New("send error")
+		}))
+		assert.Equal(t, 1, count)
+	})
+	t.Run("ContextError", func(t *testing.T) {
+		it := model.NewSerializedLogIterator(bucket, chunks, timeRange)
+		tctx, tcancel := context.WithCancel(ctx)
+		tcancel()
+
+		assert.Error(t, sendLogLines(tctx, it, logReadOptions{}, func(_ *LogLine) error {
+			return nil
+		}))
+	})
+}
+
+func createTestLogLines(start time.Time, n int) []model.LogLine {
+	start = start.Round(time.Millisecond).UTC()
+	lines := make([]model.LogLine, n)
+	for i := range lines {
+		lines[i] = model.LogLine{
+			Priority:  level.Info,
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Data:      fmt.Sprintf("This is log line %d.\n", i),
+		}
+	}
+
+	return lines
+}
+
+// appendTestLogLines appends the given lines to the log, stripping the
+// trailing newline that is added back when the lines are read.
+func appendTestLogLines(ctx context.Context, log *model.Log, lines []model.LogLine) error {
+	toAppend := make([]model.LogLine, len(lines))
+	for i, line := range lines {
+		line.Data = strings.TrimSuffix(line.Data, "\n")
+		toAppend[i] = line
+	}
+
+	return log.Append(ctx, toAppend)
+}
+
+func reverseTestLogLines(lines []model.LogLine) []model.LogLine {
+	reversed := make([]model.LogLine, len(lines))
+	for i, line := range lines {
+		reversed[len(lines)-1-i] = line
+	}
+
+	return reversed
+}
+
+type logLineReceiver interface {
+	Recv() (*LogLine, error)
+}
+
+func receiveLogLines(stream logLineReceiver) ([]model.LogLine, error) {
+	var lines []model.LogLine
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+		lines = append(lines, line.Export())
+	}
+}
+
 func createBuildloggerEnv() (cedar.Environment, error) {
 	env, err := cedar.NewEnvironment(context.Background(), testDBName, &cedar.Configuration{
 		MongoDBURI:    "mongodb://localhost:27017",
@@ -572,6 +1093,34 @@ func teardownBuildloggerEnv(ctx context.Context, env cedar.Environment) error {
 	return errors.WithStack(env.GetDB().Drop(ctx))
 }
 
+// startEvergreenAuthServer starts a server that mocks the Evergreen auth
+// route, allowing the test user to read the logs of every project except the
+// "denied" project.
+func startEvergreenAuthServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		allowed := r.Header.Get("Api-User") == "user" && r.Header.Get("Api-Key") == "key" && r.URL.Query().Get("resource") != "denied"
+		_, _ = rw.Write([]byte(strconv.FormatBool(allowed)))
+	}))
+}
+
+func newTestEvergreenAuthConfig(url string) model.EvergreenConfig {
+	return model.EvergreenConfig{
+		URL:            url,
+		HeaderUserName: "Api-User",
+		HeaderKeyName:  "Api-Key",
+	}
+}
+
+// testEvergreenAuthContext returns a context with the test user's Evergreen
+// credentials, unless unauthenticated is true.
+func testEvergreenAuthContext(ctx context.Context, unauthenticated bool) context.Context {
+	if unauthenticated {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, cedar.EvergreenAPIUserHeader, "user", cedar.EvergreenAPIKeyHeader, "key")
+}
+
 func startBuildloggerService(ctx context.Context, env cedar.Environment, port int) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
 	if err != nil {
@@ -255,7 +255,16 @@ func (srv *perfService) addFTDCRollupsJob(ctx context.Context, id string, artifa
 		}
 		hasEventData = true
 
-		job, err := units.NewFTDCRollupsJob(id, &artifact, perf.DefaultRollupFactories(), false)
+		factories, err := perf.RollupFactories(srv.env)
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "could not get custom rollups, calculating default rollups only",
+				"perf_id": id,
+			}))
+			factories = perf.DefaultRollupFactories()
+		}
+
+		job, err := units.NewFTDCRollupsJob(id, &artifact, factories, false)
 		if err != nil {
 			return newRPCError(codes.InvalidArgument, errors.WithStack(err))
 		}
@@ -76,5 +76,8 @@ func (t *TestResult) Export() model.TestResult {
 		TaskCreateTime:  t.TaskCreateTime.AsTime(),
 		TestStartTime:   t.TestStartTime.AsTime(),
 		TestEndTime:     t.TestEndTime.AsTime(),
+		FailureMessage:  t.FailureMessage,
+		StackTrace:      t.StackTrace,
+		Attributes:      t.Attributes,
 	}
 }
@@ -110,6 +110,9 @@ func TestTestResultExport(t *testing.T) {
 		TaskCreateTime: &timestamppb.Timestamp{Seconds: 1588278536},
 		TestStartTime:  &timestamppb.Timestamp{Seconds: 1588278500},
 		TestEndTime:    &timestamppb.Timestamp{Seconds: 1588278490},
+		FailureMessage: "failure_message",
+		StackTrace:     "stack_trace",
+		Attributes:     map[string]string{"seed": "42"},
 	}
 
 	modelResult := result.Export()
@@ -125,4 +128,7 @@ func TestTestResultExport(t *testing.T) {
 	assert.Equal(t, result.TaskCreateTime.AsTime(), modelResult.TaskCreateTime)
 	assert.Equal(t, result.TestStartTime.AsTime(), modelResult.TestStartTime)
 	assert.Equal(t, result.TestEndTime.AsTime(), modelResult.TestEndTime)
+	assert.Equal(t, result.FailureMessage, modelResult.FailureMessage)
+	assert.Equal(t, result.StackTrace, modelResult.StackTrace)
+	assert.Equal(t, result.Attributes, modelResult.Attributes)
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/units"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/anser/db"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -152,6 +154,17 @@ func (s *testResultsService) CloseTestResultsRecord(ctx context.Context, info *T
 	if err := record.Close(ctx); err != nil {
 		return nil, newRPCError(codes.Internal, errors.Wrapf(err, "closing test results '%s'", record.ID))
 	}
+
+	if !record.Info.HistoricalDataDisabled {
+		// The record is already closed, so failing to enqueue the job
+		// should not fail the close.
+		grip.Warning(message.WrapError(amboy.EnqueueUniqueJob(ctx, s.env.GetRemoteQueue(), units.NewFlakyTestsJob(record.ID)), message.Fields{
+			"message":           "failed to enqueue flaky tests job",
+			"test_results_id":   record.ID,
+			"test_results_info": record.Info,
+		}))
+	}
+
 	return &TestResultsResponse{TestResultsRecordId: record.ID}, nil
 }
 
@@ -165,38 +178,10 @@ func (s *testResultsService) updateHistoricalData(record *model.TestResults, res
 		}
 	}()
 
-	taskName := record.Info.DisplayTaskName
-	if taskName == "" {
-		taskName = record.Info.TaskName
-	}
-	for _, res := range results {
-		info := model.HistoricalTestDataInfo{
-			Project:     record.Info.Project,
-			Variant:     record.Info.Variant,
-			TaskName:    taskName,
-			TestName:    res.GetDisplayName(),
-			RequestType: record.Info.RequestType,
-			Date:        res.TestEndTime,
-		}
-		htd, err := model.CreateHistoricalTestData(info)
-		if err != nil {
-			grip.Error(message.WrapError(errors.Wrap(err, "creating historical test data"), message.Fields{
-				"message":                   "failed to update historical test data",
-				"test_results_info":         record.Info,
-				"historical_test_data_info": info,
-				"test_result":               res,
-			}))
-			continue
-		}
-		htd.Setup(s.env)
-
-		ctx, cancel := s.env.Context()
-		defer cancel()
-		grip.Error(message.WrapError(htd.Update(ctx, res), message.Fields{
-			"message":                   "failed to update historical test data",
-			"test_results_info":         record.Info,
-			"historical_test_data_info": info,
-			"test_result":               res,
-		}))
-	}
+	ctx, cancel := s.env.Context()
+	defer cancel()
+	grip.Error(message.WrapError(record.UpdateHistoricalTestData(ctx, results), message.Fields{
+		"message":           "failed to update historical test data",
+		"test_results_info": record.Info,
+	}))
 }
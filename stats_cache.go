@@ -21,6 +21,7 @@ func newStatsCacheRegistry(ctx context.Context) map[string]*statsCache {
 		StatsCacheBuildlogger: newStatsCache(StatsCacheBuildlogger),
 		StatsCacheTestResults: newStatsCache(StatsCacheTestResults),
 		StatsCachePerf:        newStatsCache(StatsCachePerf),
+		StatsCacheRetention:   newStatsCache(StatsCacheRetention),
 	}
 	for _, r := range registry {
 		go r.consumerLoop(ctx)
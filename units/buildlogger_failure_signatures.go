@@ -0,0 +1,100 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const buildloggerFailureSignaturesJobName = "buildlogger-failure-signatures"
+
+type buildloggerFailureSignaturesJob struct {
+	LogID    string `bson:"log_id" json:"log_id" yaml:"log_id"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env cedar.Environment
+}
+
+func init() {
+	registry.AddJobType(buildloggerFailureSignaturesJobName, func() amboy.Job { return makeBuildloggerFailureSignaturesJob() })
+}
+
+func makeBuildloggerFailureSignaturesJob() *buildloggerFailureSignaturesJob {
+	j := &buildloggerFailureSignaturesJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    buildloggerFailureSignaturesJobName,
+				Version: 1,
+			},
+		},
+	}
+	return j
+}
+
+// NewBuildloggerFailureSignaturesJob creates a new amboy job that finds the
+// failure signatures in the tail of the buildlogger log with the given ID,
+// using the rules set in the application configuration, and stores them on
+// the log. The job is a no-op if the log was closed with a zero exit code or
+// signature extraction is disabled.
+func NewBuildloggerFailureSignaturesJob(logID string) amboy.Job {
+	j := makeBuildloggerFailureSignaturesJob()
+	j.SetID(fmt.Sprintf("%s.%s", buildloggerFailureSignaturesJobName, logID))
+	j.LogID = logID
+	return j
+}
+
+func (j *buildloggerFailureSignaturesJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = cedar.GetEnvironment()
+	}
+
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err != nil {
+		j.AddError(errors.Wrap(err, "getting application configuration"))
+		return
+	}
+	if conf.LogSignatures.Disabled {
+		return
+	}
+
+	log := &model.Log{ID: j.LogID}
+	log.Setup(j.env)
+	if err := log.Find(ctx); err != nil {
+		j.AddError(errors.Wrapf(err, "finding log '%s'", j.LogID))
+		return
+	}
+	if log.Info.ExitCode == 0 {
+		return
+	}
+
+	signatures, err := log.FindFailureSignatures(ctx, model.LogSignatureOptions{
+		Rules:     conf.LogSignatures.Rules,
+		TailLines: conf.LogSignatures.TailLines,
+	})
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "finding failure signatures for log '%s'", j.LogID))
+		return
+	}
+	if err = log.SetFailureSignatures(ctx, signatures); err != nil {
+		j.AddError(err)
+		return
+	}
+
+	grip.Debug(message.Fields{
+		"job":        j.ID(),
+		"message":    "found buildlogger log failure signatures",
+		"log_id":     j.LogID,
+		"exit_code":  log.Info.ExitCode,
+		"signatures": len(signatures),
+	})
+}
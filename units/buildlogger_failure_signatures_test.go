@@ -0,0 +1,106 @@
+package units
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildloggerFailureSignaturesJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := cedar.GetEnvironment()
+	tmpDir, err := ioutil.TempDir(".", "failure-signatures-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, tearDownEnv(env))
+	}()
+
+	conf := model.NewCedarConfig(env)
+	conf.Bucket.BuildLogsBucket = tmpDir
+	require.NoError(t, conf.Save())
+
+	createLog := func(t *testing.T, procName string, exitCode int) *model.Log {
+		log := model.CreateLog(model.LogInfo{Project: "project", TaskID: "task", ProcessName: procName}, model.PailLocal)
+		log.Setup(env)
+		require.NoError(t, log.SaveNew(ctx))
+		ts := time.Now().Round(time.Millisecond).UTC()
+		require.NoError(t, log.Append(ctx, []model.LogLine{
+			{Priority: level.Info, Timestamp: ts, Data: "=== RUN   TestFoo"},
+			{Priority: level.Info, Timestamp: ts, Data: "--- FAIL: TestFoo (0.01s)"},
+		}))
+		require.NoError(t, log.Close(ctx, exitCode))
+		return log
+	}
+	findLog := func(t *testing.T, id string) *model.Log {
+		log := &model.Log{ID: id}
+		log.Setup(env)
+		require.NoError(t, log.Find(ctx))
+		return log
+	}
+
+	t.Run("LogDNE", func(t *testing.T) {
+		j := NewBuildloggerFailureSignaturesJob("DNE")
+		j.Run(ctx)
+		assert.Error(t, j.Error())
+	})
+	t.Run("SuccessfulLog", func(t *testing.T) {
+		log := createLog(t, "success", 0)
+		j := NewBuildloggerFailureSignaturesJob(log.ID)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		assert.Empty(t, findLog(t, log.ID).FailureSignatures)
+	})
+	t.Run("FailedLog", func(t *testing.T) {
+		log := createLog(t, "failure", 1)
+		j := NewBuildloggerFailureSignaturesJob(log.ID)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		assert.Equal(t, []model.LogFailureSignature{
+			{Rule: "go_test_fail", Signature: "TestFoo", Snippet: "--- FAIL: TestFoo (0.01s)", LineNum: 1},
+		}, findLog(t, log.ID).FailureSignatures)
+	})
+	t.Run("ConfiguredRules", func(t *testing.T) {
+		conf.LogSignatures.Rules = []model.LogSignatureRule{{Name: "run", Pattern: `=== RUN\s+(\S+)`}}
+		require.NoError(t, conf.Save())
+		defer func() {
+			conf.LogSignatures.Rules = nil
+			require.NoError(t, conf.Save())
+		}()
+
+		log := createLog(t, "configured", 1)
+		j := NewBuildloggerFailureSignaturesJob(log.ID)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		assert.Equal(t, []model.LogFailureSignature{
+			{Rule: "run", Signature: "TestFoo", Snippet: "=== RUN   TestFoo", LineNum: 0},
+		}, findLog(t, log.ID).FailureSignatures)
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		conf.LogSignatures.Disabled = true
+		require.NoError(t, conf.Save())
+		defer func() {
+			conf.LogSignatures.Disabled = false
+			require.NoError(t, conf.Save())
+		}()
+
+		log := createLog(t, "disabled", 1)
+		j := NewBuildloggerFailureSignaturesJob(log.ID)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		assert.Empty(t, findLog(t, log.ID).FailureSignatures)
+	})
+}
@@ -0,0 +1,139 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BuildloggerRecompressionJobName is the name of the buildlogger
+	// recompression job as well as the ID of the BatchJobController that
+	// controls it.
+	BuildloggerRecompressionJobName = "buildlogger-recompression"
+	defaultRecompressionBatchSize   = 100
+	// maxRecompressionAttempts is the number of times recompressing a log
+	// may fail before the log is no longer selected for recompression.
+	maxRecompressionAttempts = 3
+)
+
+type buildloggerRecompressionJob struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env      cedar.Environment
+}
+
+func init() {
+	registry.AddJobType(BuildloggerRecompressionJobName, func() amboy.Job { return makeBuildloggerRecompressionJob() })
+}
+
+func makeBuildloggerRecompressionJob() *buildloggerRecompressionJob {
+	j := &buildloggerRecompressionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    BuildloggerRecompressionJobName,
+				Version: 1,
+			},
+		},
+	}
+	return j
+}
+
+// NewBuildloggerRecompressionJob creates a new amboy job that compresses
+// batches of existing uncompressed buildlogger logs with the codec set in the
+// application configuration. The job is a no-op unless a compression codec is
+// configured and a BatchJobController with the ID
+// BuildloggerRecompressionJobName exists. Logs that fail to be recompressed
+// are skipped for the rest of the run and, after maxRecompressionAttempts
+// failures, by later runs.
+func NewBuildloggerRecompressionJob(id string) amboy.Job {
+	j := makeBuildloggerRecompressionJob()
+	j.SetID(fmt.Sprintf("%s.%s", BuildloggerRecompressionJobName, id))
+	return j
+}
+
+func (j *buildloggerRecompressionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = cedar.GetEnvironment()
+	}
+
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err != nil {
+		j.AddError(errors.Wrap(err, "getting application configuration"))
+		return
+	}
+	compression := conf.Bucket.BuildLogsCompression
+	if compression == model.LogCompressionNone {
+		return
+	}
+
+	controller, err := model.FindBatchJobController(ctx, j.env, BuildloggerRecompressionJobName)
+	if db.ResultsNotFound(err) {
+		return
+	}
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	if controller.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, controller.Timeout)
+		defer cancel()
+	}
+	batchSize := controller.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRecompressionBatchSize
+	}
+	iterations := controller.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	var count int
+	findOpts := model.FindLogsToRecompressOptions{
+		FailureLimit: maxRecompressionAttempts,
+		Limit:        batchSize,
+	}
+	for i := 0; i < iterations; i++ {
+		logs, err := model.FindLogsToRecompress(ctx, j.env, findOpts)
+		if err != nil {
+			j.AddError(err)
+			break
+		}
+		if len(logs) == 0 {
+			break
+		}
+
+		for _, log := range logs {
+			if err = log.Recompress(ctx, compression); err != nil {
+				j.AddError(errors.Wrapf(err, "recompressing log '%s'", log.ID))
+				j.AddError(log.IncFailedRecompressionAttempts(ctx))
+				// Skip the logs that could not be recompressed so that
+				// they do not block the following batches.
+				findOpts.ExcludeIDs = append(findOpts.ExcludeIDs, log.ID)
+				continue
+			}
+			count++
+		}
+	}
+
+	grip.Info(message.Fields{
+		"job":         j.ID(),
+		"message":     "recompressed buildlogger logs",
+		"compression": compression,
+		"count":       count,
+		"failures":    findOpts.ExcludeIDs,
+		"version":     controller.Version,
+	})
+}
@@ -0,0 +1,158 @@
+package units
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildloggerRecompressionJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := cedar.GetEnvironment()
+	tmpDir, err := ioutil.TempDir(".", "recompression-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, tearDownEnv(env))
+	}()
+
+	conf := model.NewCedarConfig(env)
+	conf.Bucket.BuildLogsBucket = tmpDir
+	require.NoError(t, conf.Save())
+
+	var logs []*model.Log
+	for _, procName := range []string{"proc0", "proc1", "proc2"} {
+		log := model.CreateLog(model.LogInfo{Project: "project", TaskID: "task", ProcessName: procName}, model.PailLocal)
+		log.Setup(env)
+		require.NoError(t, log.SaveNew(ctx))
+		require.NoError(t, log.Append(ctx, []model.LogLine{
+			{Priority: level.Info, Timestamp: time.Now().Round(time.Millisecond).UTC(), Data: "line"},
+		}))
+		require.NoError(t, log.Close(ctx, 0))
+		logs = append(logs, log)
+	}
+
+	t.Run("NoCompressionConfigured", func(t *testing.T) {
+		j := NewBuildloggerRecompressionJob("no-compression")
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		toRecompress, err := model.FindLogsToRecompress(ctx, env, model.FindLogsToRecompressOptions{})
+		require.NoError(t, err)
+		assert.Len(t, toRecompress, len(logs))
+	})
+	conf.Bucket.BuildLogsCompression = model.LogCompressionZstd
+	require.NoError(t, conf.Save())
+	t.Run("NoController", func(t *testing.T) {
+		j := NewBuildloggerRecompressionJob("no-controller")
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		toRecompress, err := model.FindLogsToRecompress(ctx, env, model.FindLogsToRecompressOptions{})
+		require.NoError(t, err)
+		assert.Len(t, toRecompress, len(logs))
+	})
+	_, err = env.GetDB().Collection(model.BatchJobControllerCollection).InsertOne(ctx, model.BatchJobController{
+		ID:         BuildloggerRecompressionJobName,
+		BatchSize:  2,
+		Iterations: 1,
+	})
+	require.NoError(t, err)
+	t.Run("SingleBatch", func(t *testing.T) {
+		j := NewBuildloggerRecompressionJob("single-batch")
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		toRecompress, err := model.FindLogsToRecompress(ctx, env, model.FindLogsToRecompressOptions{})
+		require.NoError(t, err)
+		assert.Len(t, toRecompress, 1)
+	})
+	t.Run("RemainingBatch", func(t *testing.T) {
+		j := NewBuildloggerRecompressionJob("remaining-batch")
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		toRecompress, err := model.FindLogsToRecompress(ctx, env, model.FindLogsToRecompressOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, toRecompress)
+		for _, log := range logs {
+			require.NoError(t, log.Find(ctx))
+			assert.Equal(t, 2, log.Artifact.Version)
+			assert.Equal(t, model.LogCompressionZstd, log.Artifact.Compression)
+		}
+	})
+}
+
+func TestBuildloggerRecompressionJobFailures(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := cedar.GetEnvironment()
+	tmpDir, err := ioutil.TempDir(".", "recompression-failures-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, tearDownEnv(env))
+	}()
+
+	conf := model.NewCedarConfig(env)
+	conf.Bucket.BuildLogsBucket = tmpDir
+	conf.Bucket.BuildLogsCompression = model.LogCompressionZstd
+	require.NoError(t, conf.Save())
+	_, err = env.GetDB().Collection(model.BatchJobControllerCollection).InsertOne(ctx, model.BatchJobController{
+		ID:         BuildloggerRecompressionJobName,
+		BatchSize:  1,
+		Iterations: 2,
+	})
+	require.NoError(t, err)
+
+	// The failing log is the oldest, so it is selected first, and its
+	// artifact type cannot be created, so it always fails.
+	failing := model.CreateLog(model.LogInfo{Project: "project", TaskID: "task", ProcessName: "failing"}, "DNE")
+	failing.Setup(env)
+	require.NoError(t, failing.SaveNew(ctx))
+	require.NoError(t, failing.Close(ctx, 0))
+	log := model.CreateLog(model.LogInfo{Project: "project", TaskID: "task", ProcessName: "proc"}, model.PailLocal)
+	log.Setup(env)
+	require.NoError(t, log.SaveNew(ctx))
+	require.NoError(t, log.Append(ctx, []model.LogLine{
+		{Priority: level.Info, Timestamp: time.Now().Round(time.Millisecond).UTC(), Data: "line"},
+	}))
+	require.NoError(t, log.Close(ctx, 0))
+
+	t.Run("SkipsFailedLogsForTheRestOfTheRun", func(t *testing.T) {
+		j := NewBuildloggerRecompressionJob("skips-failed")
+		j.Run(ctx)
+		assert.Error(t, j.Error())
+
+		require.NoError(t, log.Find(ctx))
+		assert.Equal(t, 2, log.Artifact.Version)
+		require.NoError(t, failing.Find(ctx))
+		assert.Equal(t, 1, failing.Artifact.Version)
+		assert.Equal(t, 1, failing.FailedRecompressionAttempts)
+	})
+	t.Run("SkipsLogsAfterMaxAttempts", func(t *testing.T) {
+		for i := 1; i < maxRecompressionAttempts; i++ {
+			j := NewBuildloggerRecompressionJob("retry-" + strconv.Itoa(i))
+			j.Run(ctx)
+			assert.Error(t, j.Error())
+		}
+		require.NoError(t, failing.Find(ctx))
+		assert.Equal(t, maxRecompressionAttempts, failing.FailedRecompressionAttempts)
+
+		j := NewBuildloggerRecompressionJob("max-attempts")
+		j.Run(ctx)
+		assert.NoError(t, j.Error())
+		require.NoError(t, failing.Find(ctx))
+		assert.Equal(t, maxRecompressionAttempts, failing.FailedRecompressionAttempts)
+	})
+}
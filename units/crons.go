@@ -71,7 +71,15 @@ func StartCrons(ctx context.Context, env cedar.Environment, rpcTLS bool) error {
 		return queue.Put(ctx, NewRemoteAmboyStatsCollector(env, utility.RoundPartOfMinute(0).Format(tsFormat)))
 	})
 	amboy.IntervalQueueOperation(ctx, remote, time.Hour, time.Now(), opts, func(ctx context.Context, queue amboy.Queue) error {
-		job, err := NewFindOutdatedRollupsJob(perf.DefaultRollupFactories())
+		factories, err := perf.RollupFactories(env)
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "could not get custom rollups, finding outdated default rollups only",
+			}))
+			factories = perf.DefaultRollupFactories()
+		}
+
+		job, err := NewFindOutdatedRollupsJob(factories)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -89,6 +97,41 @@ func StartCrons(ctx context.Context, env cedar.Environment, rpcTLS bool) error {
 		job := NewPeriodicTimeSeriesUpdateJob(utility.RoundPartOfHour(10).Format(tsFormat))
 		return queue.Put(ctx, job)
 	})
+	amboy.IntervalQueueOperation(ctx, remote, time.Hour, time.Now(), opts, func(ctx context.Context, queue amboy.Queue) error {
+		conf := model.NewCedarConfig(env)
+		if err := conf.Find(); err != nil {
+			return errors.WithStack(err)
+		}
+		if conf.Bucket.BuildLogsCompression == model.LogCompressionNone {
+			return nil
+		}
+
+		return queue.Put(ctx, NewBuildloggerRecompressionJob(utility.RoundPartOfHour(0).Format(tsFormat)))
+	})
+	amboy.IntervalQueueOperation(ctx, remote, time.Hour, time.Now(), opts, func(ctx context.Context, queue amboy.Queue) error {
+		conf := model.NewCedarConfig(env)
+		if err := conf.Find(); err != nil {
+			return errors.WithStack(err)
+		}
+		if conf.Retention.IsZero() {
+			return nil
+		}
+
+		return queue.Put(ctx, NewRetentionJob(utility.RoundPartOfHour(0).Format(tsFormat)))
+	})
+	amboy.IntervalQueueOperation(ctx, remote, 24*time.Hour, time.Now(), opts, func(ctx context.Context, queue amboy.Queue) error {
+		conf := model.NewCedarConfig(env)
+		if err := conf.Find(); err != nil {
+			return errors.WithStack(err)
+		}
+		if conf.Flags.DisableTestDurationSlowdowns {
+			return nil
+		}
+
+		// Slowdowns are detected once a day, so the job may already
+		// exist if the service restarted during the day.
+		return amboy.EnqueueUniqueJob(ctx, queue, NewTestDurationSlowdownsJob(utility.GetUTCDay(time.Now()).Format(tsFormat)))
+	})
 	amboy.IntervalQueueOperation(ctx, remote, 24*time.Hour, time.Now(), opts, func(ctx context.Context, queue amboy.Queue) error {
 		conf := model.NewCedarConfig(env)
 		if err := conf.Find(); err != nil {
@@ -82,15 +82,8 @@ func (j *findOutdatedRollupsJob) Run(ctx context.Context) {
 		j.seenIDs = map[string]bool{}
 	}
 
-	factories := []perf.RollupFactory{}
-	for _, t := range j.RollupTypes {
-		factory := perf.RollupFactoryFromType(t)
-		if factory == nil {
-			j.AddError(errors.Errorf("resolving rollup factory type '%s'", t))
-			continue
-		}
-		factories = append(factories, factory)
-	}
+	factories, err := resolveRollupFactories(j.env, j.RollupTypes)
+	j.AddError(err)
 
 	count := 0
 	results := model.PerformanceResults{}
@@ -98,7 +91,11 @@ func (j *findOutdatedRollupsJob) Run(ctx context.Context) {
 	for i, factory := range factories {
 		for _, name := range factory.Names() {
 			after := time.Now().Add(-90 * 24 * time.Hour)
-			if err := results.FindOutdatedRollups(ctx, name, factory.Version(), after, 3); err != nil {
+			find := results.FindOutdatedRollups
+			if perf.IsCustomRollup(factory) {
+				find = results.FindOutdatedCustomRollups
+			}
+			if err := find(ctx, name, factory.Version(), after, 3); err != nil {
 				j.AddError(errors.Wrapf(err, "checking for outdated rollups for '%s'", name))
 				continue
 			}
@@ -160,7 +157,7 @@ func findOutdatedFromResult(factories []perf.RollupFactory, result model.Perform
 			version, ok := rollups[name]
 			if !ok {
 				outdated = append(outdated, factory)
-			} else if version < factory.Version() {
+			} else if perf.IsRollupOutdated(factory, version) {
 				outdated = append(outdated, factory)
 			}
 		}
@@ -0,0 +1,126 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const flakyTestsJobName = "flaky-tests"
+
+type flakyTestsJob struct {
+	TestResultsID string `bson:"test_results_id" json:"test_results_id" yaml:"test_results_id"`
+	job.Base      `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env cedar.Environment
+}
+
+func init() {
+	registry.AddJobType(flakyTestsJobName, func() amboy.Job { return makeFlakyTestsJob() })
+}
+
+func makeFlakyTestsJob() *flakyTestsJob {
+	j := &flakyTestsJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    flakyTestsJobName,
+				Version: 1,
+			},
+		},
+	}
+	return j
+}
+
+// NewFlakyTestsJob creates a new amboy job that finds the tests that flaked
+// in the test results record with the given ID, either across trials or
+// relative to the previous execution of the task, and adds the flakes to the
+// flaky test scores. The job is a no-op if historical test data is disabled
+// for the record or globally, since the scores depend on it.
+func NewFlakyTestsJob(testResultsID string) amboy.Job {
+	j := makeFlakyTestsJob()
+	j.SetID(fmt.Sprintf("%s.%s", flakyTestsJobName, testResultsID))
+	j.TestResultsID = testResultsID
+	return j
+}
+
+func (j *flakyTestsJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = cedar.GetEnvironment()
+	}
+
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err != nil {
+		j.AddError(errors.Wrap(err, "getting application configuration"))
+		return
+	}
+	if conf.Flags.DisableHistoricalTestData {
+		return
+	}
+
+	record := &model.TestResults{ID: j.TestResultsID}
+	record.Setup(j.env)
+	if err := record.Find(ctx); err != nil {
+		j.AddError(errors.Wrapf(err, "finding test results record '%s'", j.TestResultsID))
+		return
+	}
+	if record.Info.HistoricalDataDisabled {
+		return
+	}
+
+	flaky, err := record.FindFlakyTests(ctx)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "finding flaky tests for '%s'", j.TestResultsID))
+		return
+	}
+
+	taskName := record.Info.DisplayTaskName
+	if taskName == "" {
+		taskName = record.Info.TaskName
+	}
+	catcher := grip.NewBasicCatcher()
+	for _, result := range flaky {
+		info := model.HistoricalTestDataInfo{
+			Project:     record.Info.Project,
+			Variant:     record.Info.Variant,
+			TaskName:    taskName,
+			TestName:    result.GetDisplayName(),
+			RequestType: record.Info.RequestType,
+			Date:        result.TestEndTime,
+		}
+		data, err := model.CreateFlakyTestData(info)
+		if err != nil {
+			catcher.Wrapf(err, "creating flaky test data for test '%s'", info.TestName)
+			continue
+		}
+		data.Setup(j.env)
+
+		htd := &model.HistoricalTestData{Info: data.Info}
+		htd.Setup(j.env)
+		if err = htd.Find(ctx); err != nil && !db.ResultsNotFound(err) {
+			catcher.Wrapf(err, "finding historical test data for test '%s'", info.TestName)
+			continue
+		}
+
+		catcher.Wrapf(data.AddFlake(ctx, htd.NumPass+htd.NumFail), "adding flake for test '%s'", info.TestName)
+	}
+	j.AddError(catcher.Resolve())
+
+	grip.Debug(message.Fields{
+		"job":             j.ID(),
+		"message":         "found flaky tests",
+		"test_results_id": j.TestResultsID,
+		"execution":       record.Info.Execution,
+		"flaky":           len(flaky),
+	})
+}
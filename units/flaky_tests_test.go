@@ -0,0 +1,119 @@
+package units
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlakyTestsJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := cedar.GetEnvironment()
+	tmpDir, err := ioutil.TempDir(".", "flaky-tests-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, tearDownEnv(env))
+	}()
+
+	conf := model.NewCedarConfig(env)
+	conf.Bucket.TestResultsBucket = tmpDir
+	conf.Bucket.PrestoBucket = tmpDir
+	conf.Bucket.PrestoTestResultsPrefix = "presto-test-results"
+	require.NoError(t, conf.Save())
+
+	now := time.Now().UTC()
+	createTestResults := func(t *testing.T, taskID string, execution int, historicalDataDisabled bool, results []model.TestResult) *model.TestResults {
+		record := model.CreateTestResults(model.TestResultsInfo{
+			Project:                "project",
+			Version:                "version",
+			Variant:                "variant",
+			TaskName:               "task",
+			TaskID:                 taskID,
+			Execution:              execution,
+			RequestType:            "mainline",
+			HistoricalDataDisabled: historicalDataDisabled,
+		}, model.PailLocal)
+		record.Setup(env)
+		require.NoError(t, record.SaveNew(ctx))
+		for i := range results {
+			results[i].TaskID = taskID
+			results[i].Execution = execution
+			results[i].TestStartTime = now
+			results[i].TestEndTime = now.Add(time.Second)
+		}
+		require.NoError(t, record.Append(ctx, results))
+		return record
+	}
+	getFlakyTests := func(t *testing.T) []model.AggregatedFlakyTest {
+		flaky, err := model.GetFlakyTests(ctx, env, model.FlakyTestsFilter{
+			Project:    "project",
+			Requesters: []string{"mainline"},
+			AfterDate:  utility.GetUTCDay(now),
+			BeforeDate: utility.GetUTCDay(now).AddDate(0, 0, 1),
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		return flaky
+	}
+
+	htd, err := model.CreateHistoricalTestData(model.HistoricalTestDataInfo{
+		Project:     "project",
+		Variant:     "variant",
+		TaskName:    "task",
+		TestName:    "test0",
+		RequestType: "mainline",
+		Date:        now,
+	})
+	require.NoError(t, err)
+	htd.Setup(env)
+	require.NoError(t, htd.Update(ctx, model.TestResult{Status: "fail"}))
+	require.NoError(t, htd.Update(ctx, model.TestResult{Status: "pass"}))
+
+	t.Run("RecordDNE", func(t *testing.T) {
+		j := NewFlakyTestsJob("DNE")
+		j.Run(ctx)
+		assert.Error(t, j.Error())
+	})
+	t.Run("HistoricalDataDisabled", func(t *testing.T) {
+		record := createTestResults(t, "disabled", 0, true, []model.TestResult{
+			{TestName: "test1", Trial: 0, Status: "fail"},
+			{TestName: "test1", Trial: 1, Status: "pass"},
+		})
+		j := NewFlakyTestsJob(record.ID)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		assert.Empty(t, getFlakyTests(t))
+	})
+	t.Run("FlakyAcrossExecutions", func(t *testing.T) {
+		createTestResults(t, "task_id", 0, false, []model.TestResult{
+			{TestName: "test0", Status: "fail"},
+			{TestName: "test2", Status: "pass"},
+		})
+		record := createTestResults(t, "task_id", 1, false, []model.TestResult{
+			{TestName: "test0", Status: "pass"},
+			{TestName: "test2", Status: "pass"},
+		})
+		j := NewFlakyTestsJob(record.ID)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		flaky := getFlakyTests(t)
+		require.Len(t, flaky, 1)
+		assert.Equal(t, "test0", flaky[0].TestName)
+		assert.Equal(t, 1, flaky[0].NumFlaky)
+		assert.Equal(t, 2, flaky[0].NumRuns)
+		assert.Equal(t, 0.5, flaky[0].Score)
+		require.Len(t, flaky[0].Trend, 1)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/mongodb/amboy/job"
 	"github.com/mongodb/amboy/registry"
 	"github.com/mongodb/ftdc"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -120,13 +121,11 @@ func (j *ftdcRollupsJob) Run(ctx context.Context) {
 		return
 	}
 
+	factories, err := resolveRollupFactories(j.env, j.RollupTypes)
+	j.AddError(err)
+
 	rollups := []model.PerfRollupValue{}
-	for _, t := range j.RollupTypes {
-		factory := perf.RollupFactoryFromType(t)
-		if factory == nil {
-			j.AddError(errors.Errorf("resolving rollup factory type '%s'", t))
-			continue
-		}
+	for _, factory := range factories {
 		rollups = append(rollups, factory.Calc(perfStats, j.UserSubmitted)...)
 	}
 
@@ -162,3 +161,35 @@ func (j *ftdcRollupsJob) createSignalProcessingJob(ctx context.Context, result *
 		j.AddError(err)
 	}
 }
+
+// resolveRollupFactories returns the rollup factories for the given types.
+// If any type is not a default rollup, the custom rollups are first
+// registered from the application configuration so that jobs always
+// calculate the current definition of a custom rollup.
+func resolveRollupFactories(env cedar.Environment, types []string) ([]perf.RollupFactory, error) {
+	defaults := map[string]bool{}
+	for _, factory := range perf.DefaultRollupFactories() {
+		defaults[factory.Type()] = true
+	}
+	for _, t := range types {
+		if !defaults[t] {
+			if _, err := perf.RollupFactories(env); err != nil {
+				return nil, errors.Wrap(err, "registering custom rollups")
+			}
+			break
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	factories := []perf.RollupFactory{}
+	for _, t := range types {
+		factory := perf.RollupFactoryFromType(t)
+		if factory == nil {
+			catcher.Errorf("resolving rollup factory type '%s'", t)
+			continue
+		}
+		factories = append(factories, factory)
+	}
+
+	return factories, catcher.Resolve()
+}
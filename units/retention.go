@@ -0,0 +1,309 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RetentionJobName is the name of the data retention job as well as the
+	// ID of the BatchJobController that controls it.
+	RetentionJobName          = "data-retention"
+	defaultRetentionBatchSize = 100
+)
+
+type retentionJob struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env      cedar.Environment
+}
+
+func init() {
+	registry.AddJobType(RetentionJobName, func() amboy.Job { return makeRetentionJob() })
+}
+
+func makeRetentionJob() *retentionJob {
+	j := &retentionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    RetentionJobName,
+				Version: 1,
+			},
+		},
+	}
+	return j
+}
+
+// NewRetentionJob creates a new amboy job that removes buildlogger logs, test
+// results, system metrics and performance results, along with their artifacts
+// in Cedar's buckets, that have expired according to the retention policies
+// set in the application configuration. For each data type and policy, the
+// job removes up to the configured number of iterations of batches. The job
+// is a no-op unless a retention policy is configured and a BatchJobController
+// with the ID RetentionJobName exists.
+func NewRetentionJob(id string) amboy.Job {
+	j := makeRetentionJob()
+	j.SetID(fmt.Sprintf("%s.%s", RetentionJobName, id))
+	return j
+}
+
+// retentionRemoveFunc removes a batch of expired data matching the given find
+// options. It returns the number of documents found and the IDs of those that
+// could not be removed. Errors removing individual documents are added to the
+// job, the returned error is only non-nil if the batch could not be found.
+type retentionRemoveFunc func(context.Context, model.RetentionFindOptions) (int, []string, error)
+
+func (j *retentionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = cedar.GetEnvironment()
+	}
+
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err != nil {
+		j.AddError(errors.Wrap(err, "getting application configuration"))
+		return
+	}
+	if conf.Retention.IsZero() {
+		return
+	}
+	if err := conf.Retention.Validate(); err != nil {
+		j.AddError(errors.Wrap(err, "invalid retention configuration"))
+		return
+	}
+
+	controller, err := model.FindBatchJobController(ctx, j.env, RetentionJobName)
+	if db.ResultsNotFound(err) {
+		return
+	}
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	if controller.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, controller.Timeout)
+		defer cancel()
+	}
+	batchSize := controller.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRetentionBatchSize
+	}
+	iterations := controller.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	removers := []struct {
+		name   string
+		remove retentionRemoveFunc
+	}{
+		{name: "logs", remove: j.removeLogs},
+		{name: "test_results", remove: j.removeTestResults},
+		{name: "system_metrics", remove: j.removeSystemMetrics},
+		{name: "perf_results", remove: j.removePerformanceResults},
+	}
+	counts := message.Fields{}
+	failures := message.Fields{}
+	for _, remover := range removers {
+		var count int
+		var failed []string
+		for _, opts := range j.findOptions(conf.Retention, time.Now(), batchSize) {
+			for i := 0; i < iterations; i++ {
+				n, failedIDs, err := remover.remove(ctx, opts)
+				if err != nil {
+					j.AddError(errors.Wrapf(err, "finding expired %s", remover.name))
+					break
+				}
+				count += n - len(failedIDs)
+				// Skip the documents that could not be removed so that
+				// they do not block the following batches.
+				failed = append(failed, failedIDs...)
+				opts.ExcludeIDs = append(opts.ExcludeIDs, failedIDs...)
+				if n < batchSize {
+					break
+				}
+			}
+		}
+		counts[remover.name] = count
+		if len(failed) > 0 {
+			failures[remover.name] = failed
+		}
+	}
+
+	grip.Info(message.Fields{
+		"job":      j.ID(),
+		"message":  "removed expired data",
+		"counts":   counts,
+		"failures": failures,
+		"version":  controller.Version,
+	})
+}
+
+// findOptions returns the find options for each project and the default
+// retention policy, for both mainline and patch data.
+func (j *retentionJob) findOptions(conf model.RetentionConfig, now time.Time, batchSize int) []model.RetentionFindOptions {
+	var opts []model.RetentionFindOptions
+	addPolicy := func(policy model.RetentionPolicy, project string, excludeProjects []string) {
+		for _, mainline := range []bool{true, false} {
+			days := policy.Days(mainline)
+			if days <= 0 {
+				continue
+			}
+			opts = append(opts, model.RetentionFindOptions{
+				Project:         project,
+				ExcludeProjects: excludeProjects,
+				Mainline:        mainline,
+				CreatedBefore:   now.Add(-time.Duration(days) * 24 * time.Hour),
+				Limit:           batchSize,
+			})
+		}
+	}
+
+	var projects []string
+	for _, p := range conf.Projects {
+		addPolicy(p.Policy, p.Project, nil)
+		projects = append(projects, p.Project)
+	}
+	addPolicy(conf.Default, "", projects)
+
+	return opts
+}
+
+func (j *retentionJob) removeLogs(ctx context.Context, opts model.RetentionFindOptions) (int, []string, error) {
+	logs, err := model.FindExpiredLogs(ctx, j.env, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var failed []string
+	for _, log := range logs {
+		if err = log.RemoveArtifacts(ctx); err != nil {
+			j.addRemoveError(err, "log", log.ID)
+			failed = append(failed, log.ID)
+			continue
+		}
+		if err = log.Remove(ctx); err != nil {
+			j.addRemoveError(err, "log", log.ID)
+			failed = append(failed, log.ID)
+			continue
+		}
+		j.addStat(1, log.Info.Project, log.Info.Version, log.Info.TaskID)
+	}
+
+	return len(logs), failed, nil
+}
+
+func (j *retentionJob) removeTestResults(ctx context.Context, opts model.RetentionFindOptions) (int, []string, error) {
+	results, err := model.FindExpiredTestResults(ctx, j.env, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var failed []string
+	for _, record := range results {
+		if err = record.RemoveArtifacts(ctx); err != nil {
+			j.addRemoveError(err, "test results record", record.ID)
+			failed = append(failed, record.ID)
+			continue
+		}
+		if err = record.Remove(ctx); err != nil {
+			j.addRemoveError(err, "test results record", record.ID)
+			failed = append(failed, record.ID)
+			continue
+		}
+		j.addStat(1, record.Info.Project, record.Info.Version, record.Info.TaskID)
+	}
+
+	return len(results), failed, nil
+}
+
+func (j *retentionJob) removeSystemMetrics(ctx context.Context, opts model.RetentionFindOptions) (int, []string, error) {
+	metrics, err := model.FindExpiredSystemMetrics(ctx, j.env, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var failed []string
+	for _, sm := range metrics {
+		if err = sm.RemoveArtifacts(ctx); err != nil {
+			j.addRemoveError(err, "system metrics record", sm.ID)
+			failed = append(failed, sm.ID)
+			continue
+		}
+		if err = sm.Remove(ctx); err != nil {
+			j.addRemoveError(err, "system metrics record", sm.ID)
+			failed = append(failed, sm.ID)
+			continue
+		}
+		j.addStat(1, sm.Info.Project, sm.Info.Version, sm.Info.TaskID)
+	}
+
+	return len(metrics), failed, nil
+}
+
+func (j *retentionJob) removePerformanceResults(ctx context.Context, opts model.RetentionFindOptions) (int, []string, error) {
+	results, err := model.FindExpiredPerformanceResults(ctx, j.env, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var failed []string
+	for _, result := range results {
+		// Children are removed along with their parent, so they may
+		// have already been removed earlier in the batch.
+		if err = result.Find(ctx); db.ResultsNotFound(err) {
+			continue
+		} else if err != nil {
+			j.addRemoveError(err, "performance result", result.ID)
+			failed = append(failed, result.ID)
+			continue
+		}
+
+		if err = result.RemoveArtifacts(ctx); err != nil {
+			j.addRemoveError(err, "performance result", result.ID)
+			failed = append(failed, result.ID)
+			continue
+		}
+		n, err := result.Remove(ctx)
+		if err != nil {
+			j.addRemoveError(err, "performance result", result.ID)
+			failed = append(failed, result.ID)
+			continue
+		}
+		j.addStat(n, result.Info.Project, result.Info.Version, result.Info.TaskID)
+	}
+
+	return len(results), failed, nil
+}
+
+func (j *retentionJob) addRemoveError(err error, kind, id string) {
+	j.AddError(errors.Wrapf(err, "removing expired %s '%s'", kind, id))
+}
+
+func (j *retentionJob) addStat(count int, project, version, taskID string) {
+	if err := j.env.GetStatsCache(cedar.StatsCacheRetention).AddStat(cedar.Stat{
+		Count:   count,
+		Project: project,
+		Version: version,
+		TaskID:  taskID,
+	}); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "stats were dropped",
+			"job":     j.ID(),
+			"cache":   cedar.StatsCacheRetention,
+		}))
+	}
+}
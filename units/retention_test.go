@@ -0,0 +1,139 @@
+package units
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRetentionJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := cedar.GetEnvironment()
+	tmpDir, err := ioutil.TempDir(".", "retention-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, tearDownEnv(env))
+	}()
+
+	conf := model.NewCedarConfig(env)
+	conf.Bucket.BuildLogsBucket = tmpDir
+	require.NoError(t, conf.Save())
+
+	var logs []*model.Log
+	for _, info := range []model.LogInfo{
+		{Project: "p0", TaskID: "t0", Mainline: true},
+		{Project: "p0", TaskID: "t1"},
+		{Project: "p1", TaskID: "t2"},
+		{Project: "p1", TaskID: "t3"},
+	} {
+		log := model.CreateLog(info, model.PailLocal)
+		log.Setup(env)
+		require.NoError(t, log.SaveNew(ctx))
+		require.NoError(t, log.Append(ctx, []model.LogLine{
+			{Priority: level.Info, Timestamp: time.Now().Round(time.Millisecond).UTC(), Data: "line"},
+		}))
+		require.NoError(t, log.Close(ctx, 0))
+		logs = append(logs, log)
+	}
+	// Age all but the last log.
+	for _, log := range logs[:3] {
+		_, err = env.GetDB().Collection("buildlogs").UpdateOne(ctx, bson.M{"_id": log.ID}, bson.M{
+			"$set": bson.M{"created_at": time.Now().Add(-10 * 24 * time.Hour)},
+		})
+		require.NoError(t, err)
+	}
+	countLogs := func(t *testing.T) int64 {
+		count, err := env.GetDB().Collection("buildlogs").CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("NoRetentionConfigured", func(t *testing.T) {
+		j := NewRetentionJob("no-retention")
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		assert.EqualValues(t, len(logs), countLogs(t))
+	})
+	conf.Retention = model.RetentionConfig{
+		Default: model.RetentionPolicy{PatchDays: 5},
+		Projects: []model.ProjectRetentionPolicy{
+			{Project: "p1", Policy: model.RetentionPolicy{PatchDays: 30}},
+		},
+	}
+	require.NoError(t, conf.Save())
+	t.Run("NoController", func(t *testing.T) {
+		j := NewRetentionJob("no-controller")
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		assert.EqualValues(t, len(logs), countLogs(t))
+	})
+	_, err = env.GetDB().Collection(model.BatchJobControllerCollection).InsertOne(ctx, model.BatchJobController{
+		ID:         RetentionJobName,
+		BatchSize:  10,
+		Iterations: 1,
+	})
+	require.NoError(t, err)
+	t.Run("RemovesExpired", func(t *testing.T) {
+		j := NewRetentionJob("removes-expired")
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		assert.EqualValues(t, 3, countLogs(t))
+
+		// Only the expired patch log from the project using the
+		// default policy should be removed.
+		for i, log := range logs {
+			err := log.Find(ctx)
+			if i == 1 {
+				assert.Error(t, err)
+				files, err := ioutil.ReadDir(tmpDir + "/" + log.Artifact.Prefix)
+				if err == nil {
+					assert.Empty(t, files)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		}
+	})
+	t.Run("SkipsFailedRemovals", func(t *testing.T) {
+		_, err := env.GetDB().Collection(model.BatchJobControllerCollection).UpdateOne(ctx, bson.M{"_id": RetentionJobName}, bson.M{
+			"$set": bson.M{"batch_size": 1, "iterations": 3},
+		})
+		require.NoError(t, err)
+
+		// Logs without an artifact prefix cannot be removed, the
+		// expired log created after them should still be removed.
+		var failing []*model.Log
+		for i, taskID := range []string{"t4", "t5", "t6"} {
+			log := model.CreateLog(model.LogInfo{Project: "p0", TaskID: taskID}, model.PailLocal)
+			log.Setup(env)
+			if i < 2 {
+				log.Artifact.Prefix = ""
+				failing = append(failing, log)
+			}
+			require.NoError(t, log.SaveNew(ctx))
+			_, err = env.GetDB().Collection("buildlogs").UpdateOne(ctx, bson.M{"_id": log.ID}, bson.M{
+				"$set": bson.M{"created_at": time.Now().Add(-time.Duration(10-i) * 24 * time.Hour)},
+			})
+			require.NoError(t, err)
+		}
+
+		j := NewRetentionJob("skips-failed-removals")
+		j.Run(ctx)
+		assert.Error(t, j.Error())
+		assert.EqualValues(t, 5, countLogs(t))
+		for _, log := range failing {
+			assert.NoError(t, log.Find(ctx))
+		}
+	})
+}
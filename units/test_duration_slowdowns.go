@@ -0,0 +1,182 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	testDurationSlowdownsJobName     = "test-duration-slowdowns"
+	testDurationSlowdownsTaskJobName = "test-duration-slowdowns-task"
+)
+
+type testDurationSlowdownsJob struct {
+	Options  model.DetectTestDurationSlowdownsOptions `bson:"options" json:"options" yaml:"options"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env   cedar.Environment
+	queue amboy.Queue
+}
+
+func init() {
+	registry.AddJobType(testDurationSlowdownsJobName, func() amboy.Job { return makeTestDurationSlowdownsJob() })
+}
+
+func makeTestDurationSlowdownsJob() *testDurationSlowdownsJob {
+	j := &testDurationSlowdownsJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    testDurationSlowdownsJobName,
+				Version: 1,
+			},
+		},
+	}
+	return j
+}
+
+// NewTestDurationSlowdownsJob creates a new amboy job that finds the tasks with
+// passing mainline tests over a rolling window and enqueues a job per task
+// that saves the tests whose recent durations are significantly longer than
+// their earlier durations. The job is a no-op if test duration slowdowns are
+// disabled.
+func NewTestDurationSlowdownsJob(id string) amboy.Job {
+	j := makeTestDurationSlowdownsJob()
+	j.SetID(fmt.Sprintf("%s.%s", testDurationSlowdownsJobName, id))
+	return j
+}
+
+func (j *testDurationSlowdownsJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = cedar.GetEnvironment()
+	}
+	if j.queue == nil {
+		j.queue = j.env.GetRemoteQueue()
+	}
+
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err != nil {
+		j.AddError(errors.Wrap(err, "getting application configuration"))
+		return
+	}
+	if conf.Flags.DisableTestDurationSlowdowns {
+		return
+	}
+
+	// Every task is analyzed over the same window.
+	if j.Options.EndAt.IsZero() {
+		j.Options.EndAt = time.Now()
+	}
+	tasks, err := model.FindTestDurationTasks(ctx, j.env, j.Options)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding test duration tasks"))
+		return
+	}
+
+	for _, task := range tasks {
+		if err = amboy.EnqueueUniqueJob(ctx, j.queue, NewTestDurationSlowdownsTaskJob(task, j.Options)); err != nil {
+			j.AddError(errors.Wrapf(err, "enqueueing test duration slowdowns job for task '%s'", task.TaskName))
+			return
+		}
+	}
+
+	grip.Info(message.Fields{
+		"job_id":    j.ID(),
+		"message":   "enqueued test duration slowdowns jobs",
+		"num_tasks": len(tasks),
+	})
+}
+
+type testDurationSlowdownsTaskJob struct {
+	Task     model.TestDurationTask                   `bson:"task" json:"task" yaml:"task"`
+	Options  model.DetectTestDurationSlowdownsOptions `bson:"options" json:"options" yaml:"options"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env cedar.Environment
+}
+
+func init() {
+	registry.AddJobType(testDurationSlowdownsTaskJobName, func() amboy.Job { return makeTestDurationSlowdownsTaskJob() })
+}
+
+func makeTestDurationSlowdownsTaskJob() *testDurationSlowdownsTaskJob {
+	j := &testDurationSlowdownsTaskJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    testDurationSlowdownsTaskJobName,
+				Version: 1,
+			},
+		},
+	}
+	return j
+}
+
+// NewTestDurationSlowdownsTaskJob creates a new amboy job that saves the
+// passing mainline tests of the given task whose recent durations are
+// significantly longer than their earlier durations. The job is a no-op if
+// test duration slowdowns are disabled.
+func NewTestDurationSlowdownsTaskJob(task model.TestDurationTask, opts model.DetectTestDurationSlowdownsOptions) amboy.Job {
+	j := makeTestDurationSlowdownsTaskJob()
+	j.Task = task
+	j.Options = opts
+	j.SetID(fmt.Sprintf(
+		"%s.%s.%s.%s.%s",
+		testDurationSlowdownsTaskJobName,
+		task.Project,
+		task.Variant,
+		task.TaskName,
+		utility.GetUTCDay(opts.EndAt).Format(tsFormat),
+	))
+	return j
+}
+
+func (j *testDurationSlowdownsTaskJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = cedar.GetEnvironment()
+	}
+
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err != nil {
+		j.AddError(errors.Wrap(err, "getting application configuration"))
+		return
+	}
+	if conf.Flags.DisableTestDurationSlowdowns {
+		return
+	}
+
+	slowdowns, err := model.DetectTestDurationSlowdowns(ctx, j.env, j.Task, j.Options)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "detecting test duration slowdowns"))
+		return
+	}
+
+	for i := range slowdowns {
+		slowdowns[i].Setup(j.env)
+		if err := slowdowns[i].Save(ctx); err != nil {
+			j.AddError(errors.Wrapf(err, "saving test duration slowdown for test '%s'", slowdowns[i].Info.TestName))
+		}
+	}
+
+	grip.Info(message.Fields{
+		"job_id":        j.ID(),
+		"message":       "detected test duration slowdowns",
+		"project":       j.Task.Project,
+		"variant":       j.Task.Variant,
+		"task_name":     j.Task.TaskName,
+		"num_slowdowns": len(slowdowns),
+	})
+}
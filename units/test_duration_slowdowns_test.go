@@ -0,0 +1,136 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestDurationSlowdownsJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := cedar.GetEnvironment()
+	tmpDir, err := ioutil.TempDir(".", "test-duration-slowdowns-test")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(tmpDir))
+		assert.NoError(t, tearDownEnv(env))
+	}()
+
+	conf := model.NewCedarConfig(env)
+	conf.Bucket.TestResultsBucket = tmpDir
+	conf.Bucket.PrestoBucket = tmpDir
+	conf.Bucket.PrestoTestResultsPrefix = "presto-test-results"
+	require.NoError(t, conf.Save())
+
+	now := time.Now()
+	createTestResults := func(t *testing.T, taskID string, createdAt time.Time, mainline bool, duration time.Duration) {
+		record := model.CreateTestResults(model.TestResultsInfo{
+			Project:     "project",
+			Version:     taskID,
+			Variant:     "variant",
+			TaskName:    "task",
+			TaskID:      taskID,
+			RequestType: "gitter_request",
+			Mainline:    mainline,
+		}, model.PailLocal)
+		record.CreatedAt = createdAt
+		record.Setup(env)
+		require.NoError(t, record.SaveNew(ctx))
+		require.NoError(t, record.Append(ctx, []model.TestResult{
+			{
+				TaskID:        taskID,
+				TestName:      "test0",
+				Status:        "pass",
+				TestStartTime: createdAt,
+				TestEndTime:   createdAt.Add(duration),
+			},
+			{
+				TaskID:        taskID,
+				TestName:      "test1",
+				Status:        "pass",
+				TestStartTime: createdAt,
+				TestEndTime:   createdAt.Add(time.Second),
+			},
+		}))
+	}
+	for i := 0; i < 10; i++ {
+		jitter := time.Duration(i) * 10 * time.Millisecond
+		createTestResults(t, fmt.Sprintf("baseline%d", i), now.Add(-time.Duration(i+3)*24*time.Hour), true, time.Second+jitter)
+		createTestResults(t, fmt.Sprintf("patch%d", i), now.Add(-time.Duration(i)*time.Hour), false, 10*time.Second+jitter)
+	}
+	for i := 0; i < 6; i++ {
+		jitter := time.Duration(i) * 10 * time.Millisecond
+		createTestResults(t, fmt.Sprintf("recent%d", i), now.Add(-time.Duration(i+1)*time.Hour), true, 2*time.Second+jitter)
+	}
+
+	findSlowdowns := func(t *testing.T) []model.TestDurationSlowdown {
+		slowdowns, err := model.GetTestDurationSlowdowns(ctx, env, model.TestDurationSlowdownsFilter{
+			Project:    "project",
+			AfterDate:  utility.GetUTCDay(now),
+			BeforeDate: utility.GetUTCDay(now).AddDate(0, 0, 1),
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		return slowdowns
+	}
+
+	t.Run("EnqueuesTaskJobs", func(t *testing.T) {
+		j := NewTestDurationSlowdownsJob("id").(*testDurationSlowdownsJob)
+		j.queue = queue.NewLocalLimitedSize(1, 100)
+		require.NoError(t, j.queue.Start(ctx))
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		assert.False(t, j.Options.EndAt.IsZero())
+		amboy.Wait(ctx, j.queue)
+
+		assert.Equal(t, 1, j.queue.Stats(ctx).Total)
+		expected := NewTestDurationSlowdownsTaskJob(model.TestDurationTask{Project: "project", Variant: "variant", TaskName: "task"}, j.Options)
+		enqueued, ok := j.queue.Get(ctx, expected.ID())
+		require.True(t, ok)
+		taskJob, ok := enqueued.(*testDurationSlowdownsTaskJob)
+		require.True(t, ok)
+		assert.Equal(t, "task", taskJob.Task.TaskName)
+		assert.Equal(t, j.Options.EndAt, taskJob.Options.EndAt)
+	})
+	t.Run("DetectsSlowdowns", func(t *testing.T) {
+		j := NewTestDurationSlowdownsTaskJob(
+			model.TestDurationTask{Project: "project", Variant: "variant", TaskName: "task"},
+			model.DetectTestDurationSlowdownsOptions{EndAt: now.Add(time.Minute)},
+		)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		slowdowns := findSlowdowns(t)
+		require.Len(t, slowdowns, 1)
+		assert.Equal(t, "test0", slowdowns[0].Info.TestName)
+		assert.Equal(t, "variant", slowdowns[0].Info.Variant)
+		assert.Equal(t, "task", slowdowns[0].Info.TaskName)
+		assert.Equal(t, 10, slowdowns[0].Baseline.NumRuns)
+		assert.Equal(t, 6, slowdowns[0].Recent.NumRuns)
+	})
+	t.Run("LimitsRuns", func(t *testing.T) {
+		j := NewTestDurationSlowdownsTaskJob(
+			model.TestDurationTask{Project: "project", Variant: "variant", TaskName: "task"},
+			model.DetectTestDurationSlowdownsOptions{EndAt: now.Add(time.Minute), MaxRuns: 5},
+		)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		slowdowns := findSlowdowns(t)
+		require.Len(t, slowdowns, 1)
+		assert.Equal(t, 5, slowdowns[0].Baseline.NumRuns)
+		assert.Equal(t, 5, slowdowns[0].Recent.NumRuns)
+	})
+}
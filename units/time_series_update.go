@@ -99,7 +99,15 @@ func (j *timeSeriesUpdateJob) Run(ctx context.Context) {
 	}
 
 	if j.service == nil {
-		j.service = perf.NewPerformanceAnalysisService(j.conf.ChangeDetector.URI, j.conf.ChangeDetector.User, j.conf.ChangeDetector.Token)
+		if err := j.conf.ChangeDetector.Validate(); err != nil {
+			j.AddError(errors.Wrap(err, "invalid change detector configuration"))
+			return
+		}
+		if j.conf.ChangeDetector.IsLocal() {
+			j.service = perf.NewLocalPerformanceAnalysisService(j.env, perf.EDivisiveMeansOptions{})
+		} else {
+			j.service = perf.NewPerformanceAnalysisService(j.conf.ChangeDetector.URI, j.conf.ChangeDetector.User, j.conf.ChangeDetector.Token)
+		}
 	}
 
 	for _, id := range j.Series.CreateSeriesIDs() {
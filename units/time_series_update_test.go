@@ -2,11 +2,13 @@ package units
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/cedar/perf"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,6 +55,68 @@ func TestUpdateTimeSeriesJob(t *testing.T) {
 			require.Equal(t, series.Measurements[i], call.Measurement)
 		}
 	})
+	t.Run("DetectsChangePointsLocally", func(t *testing.T) {
+		require.NoError(t, env.GetDB().Drop(ctx))
+		series := model.UnanalyzedPerformanceSeries{
+			Project:      "project",
+			Variant:      "variant",
+			Task:         "task",
+			Test:         "test",
+			Arguments:    map[string]int32{"thread_level": 20},
+			Measurements: []string{"ops_per_sec"},
+		}
+		for i := 0; i < 20; i++ {
+			value := 100.0 + float64(i%3)
+			if i >= 12 {
+				value += 50
+			}
+			result := model.CreatePerformanceResult(model.PerformanceResultInfo{
+				Project:   series.Project,
+				Version:   fmt.Sprintf("version%d", i),
+				Variant:   series.Variant,
+				Order:     i + 1,
+				TaskName:  series.Task,
+				TaskID:    fmt.Sprintf("task%d", i),
+				TestName:  series.Test,
+				Arguments: series.Arguments,
+				Mainline:  true,
+			}, nil, []model.PerfRollupValue{{Name: "ops_per_sec", Value: value, Version: 1, MetricType: model.MetricTypeMean}})
+			result.Setup(env)
+			require.NoError(t, result.SaveNew(ctx))
+		}
+
+		j := NewUpdateTimeSeriesJob(series)
+		job := j.(*timeSeriesUpdateJob)
+		job.conf = model.NewCedarConfig(env)
+		job.conf.ChangeDetector.Implementation = model.ChangeDetectorImplementationLocal
+		j.Run(ctx)
+		require.True(t, j.Status().Completed)
+		require.NoError(t, j.Error())
+
+		id := series.CreateSeriesIDs()[0]
+		changePoints, err := model.FindPerformanceChangePoints(ctx, env, id)
+		require.NoError(t, err)
+		require.Len(t, changePoints, 1)
+		assert.Equal(t, 13, changePoints[0].Order)
+		assert.Equal(t, "version12", changePoints[0].Version)
+		assert.Equal(t, perf.EDivisiveMeansAlgorithm, changePoints[0].Algorithm)
+		assert.InDelta(t, 50, changePoints[0].Magnitude, 1)
+		assert.True(t, changePoints[0].Confidence >= 0.95)
+	})
+	t.Run("InvalidImplementation", func(t *testing.T) {
+		j := NewUpdateTimeSeriesJob(model.UnanalyzedPerformanceSeries{
+			Project: "project",
+			Variant: "variant",
+			Task:    "task",
+			Test:    "test",
+		})
+		job := j.(*timeSeriesUpdateJob)
+		job.conf = model.NewCedarConfig(env)
+		job.conf.ChangeDetector.Implementation = "DNE"
+		j.Run(ctx)
+		require.True(t, j.Status().Completed)
+		assert.Error(t, j.Error())
+	})
 	t.Run("DoesNothingWhenDisabled", func(t *testing.T) {
 		j := NewUpdateTimeSeriesJob(model.UnanalyzedPerformanceSeries{
 			Project: "projecta",